@@ -0,0 +1,344 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fd
+
+import (
+	"math"
+	"sync"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+)
+
+// GradientBatch estimates the gradient of the multivariate function f at the
+// location x as Gradient does, but evaluates f at many locations in each call.
+// Each row of the matrix x passed to f is a location at which f is evaluated
+// and f must store the value at that location into the corresponding row of
+// the single column matrix y. The locations are passed in batches of at most
+// settings.BatchSize rows, and if settings.Concurrent is true batches are
+// evaluated concurrently. settings.Adaptive is not used.
+//
+// GradientBatch panics under the same conditions as Gradient.
+func GradientBatch(dst []float64, f func(y, x *mat.Dense), x []float64, settings *Settings) []float64 {
+	n := len(x)
+	if dst == nil {
+		dst = make([]float64, n)
+	}
+	if len(dst) != n {
+		panic("fd: slice length mismatch")
+	}
+
+	// Default settings.
+	formula := Forward
+	step := formula.Step
+	var originValue float64
+	var originKnown, concurrent, relative bool
+	var workers int
+	var steps []float64
+	var bounds []Bound
+	var batch int
+
+	// Use user settings if provided.
+	if settings != nil {
+		if !settings.Formula.isZero() {
+			formula = settings.Formula
+			step = formula.Step
+			checkFormula(formula)
+			if formula.Derivative != 1 {
+				panic(badDerivOrder)
+			}
+		}
+		if settings.Step != 0 {
+			step = settings.Step
+		}
+		steps = settings.Steps
+		relative = settings.Relative
+		bounds = settings.Bounds
+		if bounds != nil && len(bounds) != n {
+			panic("fd: bounds length mismatch")
+		}
+		originKnown = settings.OriginKnown
+		originValue = settings.OriginValue
+		concurrent = settings.Concurrent
+		workers = settings.Workers
+		batch = settings.BatchSize
+	}
+	stencils, stepOf := variableStencils(formula, step, steps, relative, bounds, x)
+
+	// Assemble the evaluation locations, one per row,
+	// with the origin first if it is needed.
+	var locs [][]float64
+	var hasOrigin bool
+	for _, stencil := range stencils {
+		hasOrigin = hasOrigin || usesOrigin(stencil)
+	}
+	evalOrigin := hasOrigin && !originKnown
+	if evalOrigin {
+		locs = append(locs, x)
+	}
+	for i, stencil := range stencils {
+		for _, pt := range stencil {
+			if pt.Loc == 0 {
+				continue
+			}
+			loc := make([]float64, n)
+			copy(loc, x)
+			loc[i] += pt.Loc * stepOf[i]
+			locs = append(locs, loc)
+		}
+	}
+	y := evaluateBatches(f, locs, 1, batch, concurrent, workers)
+
+	r := 0
+	if evalOrigin {
+		originValue = y.At(0, 0)
+		r++
+	}
+	for i, stencil := range stencils {
+		var deriv float64
+		for _, pt := range stencil {
+			if pt.Loc == 0 {
+				deriv += pt.Coeff * originValue
+				continue
+			}
+			deriv += pt.Coeff * y.At(r, 0)
+			r++
+		}
+		dst[i] = deriv / stepOf[i]
+	}
+	return dst
+}
+
+// JacobianBatch approximates the Jacobian matrix of a vector-valued function f
+// at the location x as Jacobian does, but evaluates f at many locations in each
+// call. Each row of the matrix x passed to f is a location at which f is
+// evaluated and f must store the value at that location into the corresponding
+// row of y, which has as many columns as dst has rows. The locations are passed
+// in batches of at most settings.BatchSize rows, and if settings.Concurrent is
+// true batches are evaluated concurrently. settings.Pattern and
+// settings.Adaptive are not used.
+//
+// JacobianBatch panics under the same conditions as Jacobian.
+func JacobianBatch(dst *mat.Dense, f func(y, x *mat.Dense), x []float64, settings *JacobianSettings) {
+	n := len(x)
+	if n == 0 {
+		panic("jacobian: x has zero length")
+	}
+	m, c := dst.Dims()
+	if c != n {
+		panic("jacobian: mismatched matrix size")
+	}
+
+	// Default settings.
+	formula := Forward
+	step := formula.Step
+	var originValue []float64
+	var concurrent, relative bool
+	var workers int
+	var steps []float64
+	var bounds []Bound
+	var batch int
+
+	// Use user settings if provided.
+	if settings != nil {
+		if !settings.Formula.isZero() {
+			formula = settings.Formula
+			step = formula.Step
+			checkFormula(formula)
+			if formula.Derivative != 1 {
+				panic(badDerivOrder)
+			}
+		}
+		if settings.Step != 0 {
+			step = settings.Step
+		}
+		steps = settings.Steps
+		relative = settings.Relative
+		bounds = settings.Bounds
+		if bounds != nil && len(bounds) != n {
+			panic("jacobian: mismatched bounds length")
+		}
+		originValue = settings.OriginValue
+		if originValue != nil && len(originValue) != m {
+			panic("jacobian: mismatched OriginValue slice length")
+		}
+		concurrent = settings.Concurrent
+		workers = settings.Workers
+		batch = settings.BatchSize
+	}
+	stencils, stepOf := variableStencils(formula, step, steps, relative, bounds, x)
+
+	// Assemble the evaluation locations, one per row,
+	// with the origin first if it is needed.
+	var locs [][]float64
+	var hasOrigin bool
+	for _, stencil := range stencils {
+		hasOrigin = hasOrigin || usesOrigin(stencil)
+	}
+	evalOrigin := hasOrigin && originValue == nil
+	if evalOrigin {
+		locs = append(locs, x)
+	}
+	for j, stencil := range stencils {
+		for _, pt := range stencil {
+			if pt.Loc == 0 {
+				continue
+			}
+			loc := make([]float64, n)
+			copy(loc, x)
+			loc[j] += pt.Loc * stepOf[j]
+			locs = append(locs, loc)
+		}
+	}
+	y := evaluateBatches(f, locs, m, batch, concurrent, workers)
+
+	r := 0
+	if evalOrigin {
+		originValue = y.RawRowView(0)
+		r++
+	}
+	col := make([]float64, m)
+	for j, stencil := range stencils {
+		for i := range col {
+			col[i] = 0
+		}
+		for _, pt := range stencil {
+			if pt.Loc == 0 {
+				floats.AddScaled(col, pt.Coeff, originValue)
+				continue
+			}
+			floats.AddScaled(col, pt.Coeff, y.RawRowView(r))
+			r++
+		}
+		floats.Scale(1/stepOf[j], col)
+		dst.SetCol(j, col)
+	}
+}
+
+// HessianBatch approximates the Hessian matrix of the multivariate function f
+// at the location x as Hessian does, but evaluates f at many locations in each
+// call. Each row of the matrix x passed to f is a location at which f is
+// evaluated and f must store the value at that location into the corresponding
+// row of the single column matrix y. The locations are passed in batches of at
+// most settings.BatchSize rows, and if settings.Concurrent is true batches are
+// evaluated concurrently.
+//
+// HessianBatch panics under the same conditions as Hessian.
+func HessianBatch(dst *mat.SymDense, f func(y, x *mat.Dense), x []float64, settings *Settings) *mat.SymDense {
+	n := len(x)
+	if dst == nil {
+		dst = mat.NewSymDense(n, nil)
+	} else {
+		if n2 := dst.Symmetric(); n2 != n {
+			panic("hessian: dst size mismatch")
+		}
+	}
+
+	// Default settings.
+	formula := Forward
+	step := math.Sqrt(formula.Step) // Use the sqrt because taking derivatives of derivatives.
+	var originValue float64
+	var originKnown, concurrent bool
+	var workers int
+	var batch int
+
+	// Use user settings if provided.
+	if settings != nil {
+		if !settings.Formula.isZero() {
+			formula = settings.Formula
+			step = math.Sqrt(formula.Step)
+			checkFormula(formula)
+			if formula.Derivative != 1 {
+				panic(badDerivOrder)
+			}
+		}
+		if settings.Step != 0 {
+			if settings.Step < 0 {
+				panic(negativeStep)
+			}
+			step = settings.Step
+		}
+		originKnown = settings.OriginKnown
+		originValue = settings.OriginValue
+		concurrent = settings.Concurrent
+		workers = settings.Workers
+		batch = settings.BatchSize
+	}
+	stencil := formula.Stencil
+
+	pts, index := hessianLocs(n, stencil, originKnown)
+	locs := make([][]float64, len(pts))
+	for k, p := range pts {
+		locs[k] = make([]float64, n)
+		p.displace(locs[k], x, step)
+	}
+	y := evaluateBatches(f, locs, 1, batch, concurrent, workers)
+	vals := make([]float64, len(pts))
+	for k := range vals {
+		vals[k] = y.At(k, 0)
+	}
+	hessianAssemble(dst, stencil, index, vals, originKnown, originValue, step)
+	return dst
+}
+
+// evaluateBatches evaluates the batched function f at each of the locations
+// in locs, passing at most size locations in each call, and returns a matrix
+// with the m values for each location in the corresponding row. If size is
+// zero, all locations are passed in a single call. If concurrent is true,
+// batches are evaluated concurrently by at most workers goroutines, or
+// GOMAXPROCS goroutines if workers is zero.
+func evaluateBatches(f func(y, x *mat.Dense), locs [][]float64, m, size int, concurrent bool, workers int) *mat.Dense {
+	k := len(locs)
+	if k == 0 {
+		return nil
+	}
+	if size < 0 {
+		panic("fd: negative batch size")
+	}
+	n := len(locs[0])
+	x := mat.NewDense(k, n, nil)
+	for i, loc := range locs {
+		x.SetRow(i, loc)
+	}
+	y := mat.NewDense(k, m, nil)
+	if size == 0 || size > k {
+		size = k
+	}
+	batches := (k + size - 1) / size
+	eval := func(b int) {
+		lo := b * size
+		hi := lo + size
+		if hi > k {
+			hi = k
+		}
+		f(y.Slice(lo, hi, 0, m).(*mat.Dense), x.Slice(lo, hi, 0, n).(*mat.Dense))
+	}
+
+	nWorkers := computeWorkers(concurrent, workers, batches)
+	if nWorkers == 1 {
+		for b := 0; b < batches; b++ {
+			eval(b)
+		}
+		return y
+	}
+	var wg sync.WaitGroup
+	jobs := make(chan int, nWorkers)
+	for i := 0; i < nWorkers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for b := range jobs {
+				// Each batch writes to distinct rows of y.
+				eval(b)
+			}
+		}()
+	}
+	for b := 0; b < batches; b++ {
+		jobs <- b
+	}
+	close(jobs)
+	wg.Wait()
+	return y
+}
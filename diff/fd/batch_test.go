@@ -0,0 +1,137 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fd
+
+import (
+	"sync"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+)
+
+// batchScalar returns a batched version of f that records the
+// number of rows passed in each call.
+func batchScalar(f func([]float64) float64, sizes *[]int, mu *sync.Mutex) func(y, x *mat.Dense) {
+	return func(y, x *mat.Dense) {
+		r, _ := x.Dims()
+		mu.Lock()
+		*sizes = append(*sizes, r)
+		mu.Unlock()
+		for i := 0; i < r; i++ {
+			y.Set(i, 0, f(x.RawRowView(i)))
+		}
+	}
+}
+
+func checkBatchSizes(t *testing.T, name string, sizes []int, size, want int) {
+	var total int
+	for _, s := range sizes {
+		if size != 0 && s > size {
+			t.Errorf("%s: batch larger than BatchSize: got:%d want:<=%d", name, s, size)
+		}
+		total += s
+	}
+	if total != want {
+		t.Errorf("%s: unexpected number of evaluations: got:%d want:%d", name, total, want)
+	}
+}
+
+func TestGradientBatch(t *testing.T) {
+	rand.Seed(1)
+	r := Rosenbrock{6}
+	x := randomSlice(6, 10)
+	for _, formula := range []Formula{Forward, Backward, Central} {
+		for _, size := range []int{0, 1, 4, 100} {
+			for _, concurrent := range []bool{false, true} {
+				settings := &Settings{Formula: formula, BatchSize: size, Concurrent: concurrent}
+				want := Gradient(nil, r.F, x, settings)
+
+				var mu sync.Mutex
+				var sizes []int
+				got := GradientBatch(nil, batchScalar(r.F, &sizes, &mu), x, settings)
+				if !floats.Equal(got, want) {
+					t.Errorf("unexpected gradient for formula=%v size=%d concurrent=%t: got:%v want:%v",
+						formula.Stencil, size, concurrent, got, want)
+				}
+				n := len(formula.Stencil) * len(x)
+				if usesOrigin(formula.Stencil) {
+					n += 1 - len(x)
+				}
+				checkBatchSizes(t, "GradientBatch", sizes, size, n)
+			}
+		}
+	}
+}
+
+func TestJacobianBatch(t *testing.T) {
+	rand.Seed(1)
+	x := randomSlice(3, 10)
+	for _, formula := range []Formula{Forward, Backward, Central} {
+		for _, size := range []int{0, 1, 4, 100} {
+			for _, concurrent := range []bool{false, true} {
+				settings := &JacobianSettings{Formula: formula, BatchSize: size, Concurrent: concurrent}
+				want := mat.NewDense(4, 3, nil)
+				Jacobian(want, vecFunc43, x, settings)
+
+				var mu sync.Mutex
+				var sizes []int
+				f := func(y, x *mat.Dense) {
+					r, _ := x.Dims()
+					mu.Lock()
+					sizes = append(sizes, r)
+					mu.Unlock()
+					for i := 0; i < r; i++ {
+						vecFunc43(y.RawRowView(i), x.RawRowView(i))
+					}
+				}
+				got := mat.NewDense(4, 3, nil)
+				JacobianBatch(got, f, x, settings)
+				if !mat.Equal(got, want) {
+					t.Errorf("unexpected Jacobian for formula=%v size=%d concurrent=%t: got:%v want:%v",
+						formula.Stencil, size, concurrent, mat.Formatted(got), mat.Formatted(want))
+				}
+				n := len(formula.Stencil) * len(x)
+				if usesOrigin(formula.Stencil) {
+					n += 1 - len(x)
+				}
+				checkBatchSizes(t, "JacobianBatch", sizes, size, n)
+			}
+		}
+	}
+}
+
+func TestHessianBatch(t *testing.T) {
+	for i, test := range hessianTestCases {
+		for _, size := range []int{0, 1, 5} {
+			for _, concurrent := range []bool{false, true} {
+				var settings Settings
+				if test.settings != nil {
+					settings = *test.settings
+				}
+				settings.BatchSize = size
+				settings.Concurrent = concurrent
+
+				var mu sync.Mutex
+				var evals int
+				want := Hessian(nil, func(x []float64) float64 {
+					mu.Lock()
+					evals++
+					mu.Unlock()
+					return test.h.Func(x)
+				}, test.x, &settings)
+
+				var sizes []int
+				got := HessianBatch(nil, batchScalar(test.h.Func, &sizes, &mu), test.x, &settings)
+				if !mat.Equal(got, want) {
+					t.Errorf("Case %d: unexpected Hessian for size=%d concurrent=%t", i, size, concurrent)
+				}
+				checkBatchSizes(t, "HessianBatch", sizes, size, evals)
+			}
+		}
+	}
+}
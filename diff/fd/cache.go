@@ -0,0 +1,165 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fd
+
+import (
+	"encoding/binary"
+	"math"
+	"sync"
+)
+
+// Cache holds the values of a function at the locations it has been evaluated,
+// so that locations shared between calls are evaluated only once. A Cache is
+// passed to the functions in this package through the Cache field of Settings
+// or JacobianSettings, for example to share the evaluation at the origin
+// between calls to Gradient and Hessian at the same location.
+//
+// A Cache must only be used with a single function, and values are not
+// invalidated if that function changes. A Cache is safe for concurrent use.
+// The zero value of Cache is an empty Cache that matches locations exactly.
+type Cache struct {
+	tol float64
+
+	mu      sync.Mutex
+	exact   map[string][]float64
+	entries []cacheEntry
+}
+
+type cacheEntry struct {
+	x, y []float64
+}
+
+// NewCache returns a new empty Cache. Two locations are considered equal if
+// none of their elements differ by more than tol. tol must be much smaller
+// than the step sizes used with the Cache, otherwise perturbed locations are
+// matched to the cached origin and derivative estimates are silently zero.
+// A tol of zero matches locations exactly, and lookups are faster than for
+// a positive tol. NewCache panics if tol is negative.
+func NewCache(tol float64) *Cache {
+	if tol < 0 {
+		panic("fd: negative cache tolerance")
+	}
+	return &Cache{tol: tol}
+}
+
+// Len returns the number of locations held in the cache.
+func (c *Cache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.exact) + len(c.entries)
+}
+
+// Reset removes all the locations held in the cache.
+func (c *Cache) Reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.exact = nil
+	c.entries = c.entries[:0]
+}
+
+// get returns the value stored for x, and whether x was found.
+func (c *Cache) get(x []float64) ([]float64, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.tol == 0 {
+		y, ok := c.exact[cacheKey(x)]
+		return y, ok
+	}
+	for _, e := range c.entries {
+		if c.near(e.x, x) {
+			return e.y, true
+		}
+	}
+	return nil, false
+}
+
+// set stores a copy of the value y for a copy of x.
+func (c *Cache) set(x, y []float64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	y = append([]float64(nil), y...)
+	if c.tol == 0 {
+		if c.exact == nil {
+			c.exact = make(map[string][]float64)
+		}
+		c.exact[cacheKey(x)] = y
+		return
+	}
+	for _, e := range c.entries {
+		// The location was stored concurrently.
+		if c.near(e.x, x) {
+			return
+		}
+	}
+	c.entries = append(c.entries, cacheEntry{x: append([]float64(nil), x...), y: y})
+}
+
+// near returns whether a and b are equal to within the tolerance of c.
+func (c *Cache) near(a, b []float64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i, v := range a {
+		if math.Abs(v-b[i]) > c.tol {
+			return false
+		}
+	}
+	return true
+}
+
+// cacheKey returns a key for the exact value of x.
+func cacheKey(x []float64) string {
+	b := make([]byte, 8*len(x))
+	for i, v := range x {
+		if v == 0 {
+			v = 0 // Map negative zero to zero.
+		}
+		binary.LittleEndian.PutUint64(b[8*i:], math.Float64bits(v))
+	}
+	return string(b)
+}
+
+// scalar returns a function that returns the value of f at x held in c,
+// evaluating f and storing the value if it is not held. If c is nil, f is
+// returned.
+func (c *Cache) scalar(f func([]float64) float64) func([]float64) float64 {
+	if c == nil {
+		return f
+	}
+	return func(x []float64) float64 {
+		if y, ok := c.get(x); ok {
+			if len(y) != 1 {
+				panic("fd: cache value length mismatch")
+			}
+			return y[0]
+		}
+		v := f(x)
+		c.set(x, []float64{v})
+		return v
+	}
+}
+
+// vector returns a function that stores the value of f at x held in c into y,
+// evaluating f and storing the value if it is not held. Values are only stored
+// if f returns a nil error. If c is nil, f is returned.
+func (c *Cache) vector(f func(y, x []float64) error) func(y, x []float64) error {
+	if c == nil {
+		return f
+	}
+	return func(y, x []float64) error {
+		if v, ok := c.get(x); ok {
+			if len(v) != len(y) {
+				panic("fd: cache value length mismatch")
+			}
+			copy(y, v)
+			return nil
+		}
+		if err := f(y, x); err != nil {
+			return err
+		}
+		c.set(x, y)
+		return nil
+	}
+}
@@ -0,0 +1,142 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fd
+
+import (
+	"sync"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestCache(t *testing.T) {
+	rand.Seed(1)
+	const n = 4
+	r := Rosenbrock{n}
+	x := randomSlice(n, 10)
+
+	for _, concurrent := range []bool{false, true} {
+		var mu sync.Mutex
+		var evals int
+		f := func(x []float64) float64 {
+			mu.Lock()
+			evals++
+			mu.Unlock()
+			return r.F(x)
+		}
+
+		cache := NewCache(0)
+		settings := &Settings{Concurrent: concurrent, Cache: cache}
+		want := Gradient(nil, r.F, x, &Settings{Concurrent: concurrent})
+		got := Gradient(nil, f, x, settings)
+		if !floats.Equal(got, want) {
+			t.Errorf("unexpected gradient for concurrent=%t: got:%v want:%v", concurrent, got, want)
+		}
+		if evals != n+1 {
+			t.Errorf("unexpected number of evaluations for concurrent=%t: got:%d want:%d", concurrent, evals, n+1)
+		}
+		if cache.Len() != n+1 {
+			t.Errorf("unexpected cache length for concurrent=%t: got:%d want:%d", concurrent, cache.Len(), n+1)
+		}
+
+		// All locations are held by the cache.
+		evals = 0
+		got = Gradient(nil, f, x, settings)
+		if !floats.Equal(got, want) {
+			t.Errorf("unexpected cached gradient for concurrent=%t: got:%v want:%v", concurrent, got, want)
+		}
+		if evals != 0 {
+			t.Errorf("unexpected number of cached evaluations for concurrent=%t: got:%d want:0", concurrent, evals)
+		}
+
+		// The Hessian shares the origin with the gradient.
+		evals = 0
+		hess := Hessian(nil, f, x, settings)
+		wantHess := Hessian(nil, r.F, x, &Settings{Concurrent: concurrent})
+		if !mat.Equal(hess, wantHess) {
+			t.Errorf("unexpected cached Hessian for concurrent=%t", concurrent)
+		}
+		if want := 2*n + n*(n-1)/2; evals != want {
+			t.Errorf("unexpected number of Hessian evaluations for concurrent=%t: got:%d want:%d", concurrent, evals, want)
+		}
+
+		cache.Reset()
+		if cache.Len() != 0 {
+			t.Errorf("unexpected cache length after reset: got:%d", cache.Len())
+		}
+	}
+}
+
+func TestCacheTolerance(t *testing.T) {
+	var evals int
+	f := func(x []float64) float64 {
+		evals++
+		return x[0] + 2*x[1]
+	}
+	cache := NewCache(1e-10)
+	settings := &Settings{Cache: cache}
+	Gradient(nil, f, []float64{1, 2}, settings)
+	evals = 0
+	got := Gradient(nil, f, []float64{1 + 1e-12, 2}, settings)
+	if evals != 0 {
+		t.Errorf("unexpected number of evaluations within tolerance: got:%d want:0", evals)
+	}
+	if !floats.EqualApprox(got, []float64{1, 2}, 1e-6) {
+		t.Errorf("unexpected gradient: got:%v want:%v", got, []float64{1, 2})
+	}
+	Gradient(nil, f, []float64{1 + 1e-6, 2}, settings)
+	if evals != 3 {
+		t.Errorf("unexpected number of evaluations outside tolerance: got:%d want:3", evals)
+	}
+
+	// The zero value is usable.
+	var zero Cache
+	evals = 0
+	Gradient(nil, f, []float64{1, 2}, &Settings{Cache: &zero})
+	Gradient(nil, f, []float64{1, 2}, &Settings{Cache: &zero})
+	if evals != 3 {
+		t.Errorf("unexpected number of evaluations with zero value cache: got:%d want:3", evals)
+	}
+
+	if !Panics(func() { NewCache(-1) }) {
+		t.Errorf("NewCache did not panic with negative tolerance")
+	}
+}
+
+func TestCacheJacobian(t *testing.T) {
+	rand.Seed(1)
+	x := randomSlice(3, 10)
+	for _, formula := range []Formula{Forward, Central} {
+		for _, concurrent := range []bool{false, true} {
+			var mu sync.Mutex
+			var evals int
+			f := func(y, x []float64) {
+				mu.Lock()
+				evals++
+				mu.Unlock()
+				vecFunc43(y, x)
+			}
+
+			want := mat.NewDense(4, 3, nil)
+			Jacobian(want, vecFunc43, x, &JacobianSettings{Formula: formula, Concurrent: concurrent})
+
+			settings := &JacobianSettings{Formula: formula, Concurrent: concurrent, Cache: NewCache(0)}
+			for i := 0; i < 2; i++ {
+				evals = 0
+				got := mat.NewDense(4, 3, nil)
+				Jacobian(got, f, x, settings)
+				if !mat.Equal(got, want) {
+					t.Errorf("unexpected Jacobian for concurrent=%t call %d", concurrent, i)
+				}
+				if i > 0 && evals != 0 {
+					t.Errorf("unexpected number of cached evaluations for concurrent=%t: got:%d want:0", concurrent, evals)
+				}
+			}
+		}
+	}
+}
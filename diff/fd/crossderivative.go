@@ -0,0 +1,98 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fd
+
+import "math"
+
+// CrossDerivative approximates the mixed second partial derivative of the
+// multivariate function f at the location x with respect to x_i and x_j. That is
+//  ∂^2 f(x)/∂x_i ∂x_j
+// which is the element H_{i,j} of the Hessian matrix computed by Hessian, but
+// requires only the function evaluations needed for that element. If i equals
+// j, the second derivative with respect to x_i is returned.
+//
+// Finite difference formula and other options are specified by settings as for
+// Hessian. If settings is nil, the derivative will be estimated using the Forward
+// formula and a default step size.
+//
+// CrossDerivative panics if i or j is out of range for x, or if the derivative
+// order of the formula is not 1.
+func CrossDerivative(f func(x []float64) float64, x []float64, i, j int, settings *Settings) float64 {
+	n := len(x)
+	if i < 0 || n <= i || j < 0 || n <= j {
+		panic("fd: index out of range")
+	}
+	if j < i {
+		i, j = j, i
+	}
+
+	// Default settings.
+	formula := Forward
+	step := math.Sqrt(formula.Step) // Use the sqrt because taking derivatives of derivatives.
+	var originValue float64
+	var originKnown, concurrent bool
+	var workers int
+
+	// Use user settings if provided.
+	if settings != nil {
+		if !settings.Formula.isZero() {
+			formula = settings.Formula
+			step = math.Sqrt(formula.Step)
+			checkFormula(formula)
+			if formula.Derivative != 1 {
+				panic(badDerivOrder)
+			}
+		}
+		if settings.Step != 0 {
+			if settings.Step < 0 {
+				panic(negativeStep)
+			}
+			step = settings.Step
+		}
+		originKnown = settings.OriginKnown
+		originValue = settings.OriginValue
+		concurrent = settings.Concurrent
+		workers = settings.Workers
+		f = settings.Cache.scalar(f)
+	}
+	stencil := formula.Stencil
+
+	// Collect the distinct evaluation locations.
+	index := make(map[hessPoint]int)
+	var locs []hessPoint
+	for _, pti := range stencil {
+		for _, ptj := range stencil {
+			p := newHessPoint(i, j, pti.Loc, ptj.Loc)
+			if originKnown && p.isOrigin() {
+				continue
+			}
+			if _, ok := index[p]; !ok {
+				index[p] = len(locs)
+				locs = append(locs, p)
+			}
+		}
+	}
+
+	vals := make([]float64, len(locs))
+	nWorkers := computeWorkers(concurrent, workers, len(locs))
+	if nWorkers == 1 {
+		hessianSerial(vals, f, x, locs, step)
+	} else {
+		hessianConcurrent(vals, nWorkers, f, x, locs, step)
+	}
+
+	var deriv float64
+	for _, pti := range stencil {
+		for _, ptj := range stencil {
+			p := newHessPoint(i, j, pti.Loc, ptj.Loc)
+			v := originValue
+			if !originKnown || !p.isOrigin() {
+				v = vals[index[p]]
+			}
+			deriv += v * pti.Coeff * ptj.Coeff
+		}
+	}
+	return deriv / (step * step)
+}
@@ -0,0 +1,77 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fd
+
+import (
+	"math"
+	"sync"
+	"testing"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestCrossDerivative(t *testing.T) {
+	for cas, test := range hessianTestCases {
+		n := len(test.x)
+		want := mat.NewSymDense(n, nil)
+		test.h.Hess(want, test.x)
+		for _, concurrent := range []bool{false, true} {
+			var settings Settings
+			if test.settings != nil {
+				settings = *test.settings
+			}
+			settings.Concurrent = concurrent
+			hess := Hessian(nil, test.h.Func, test.x, &settings)
+			for i := 0; i < n; i++ {
+				for j := 0; j < n; j++ {
+					got := CrossDerivative(test.h.Func, test.x, i, j, &settings)
+					if !floats.EqualWithinAbsOrRel(got, want.At(i, j), test.tol, test.tol) {
+						t.Errorf("Case %d: unexpected cross derivative (%d,%d): got:%v want:%v", cas, i, j, got, want.At(i, j))
+					}
+					if math.Abs(got-hess.At(i, j)) > 1e-12*math.Max(1, math.Abs(got)) {
+						t.Errorf("Case %d: cross derivative (%d,%d) does not match Hessian: got:%v want:%v", cas, i, j, got, hess.At(i, j))
+					}
+				}
+			}
+		}
+	}
+
+	var mu sync.Mutex
+	var evals int
+	f := func(x []float64) float64 {
+		mu.Lock()
+		evals++
+		mu.Unlock()
+		return x[0] * x[1] * x[2]
+	}
+	for _, test := range []struct {
+		i, j    int
+		formula Formula
+		want    int
+	}{
+		{i: 0, j: 2, formula: Forward, want: 4},
+		{i: 1, j: 1, formula: Forward, want: 3},
+		{i: 0, j: 2, formula: Central, want: 4},
+		{i: 1, j: 1, formula: Central, want: 3},
+	} {
+		evals = 0
+		got := CrossDerivative(f, []float64{1, 2, 3}, test.i, test.j, &Settings{Formula: test.formula})
+		if evals != test.want {
+			t.Errorf("unexpected number of evaluations for (%d,%d): got:%d want:%d", test.i, test.j, evals, test.want)
+		}
+		want := 0.0
+		if test.i != test.j {
+			want = 2
+		}
+		if math.Abs(got-want) > 1e-6 {
+			t.Errorf("unexpected cross derivative for (%d,%d): got:%v want:%v", test.i, test.j, got, want)
+		}
+	}
+
+	if !Panics(func() { CrossDerivative(f, []float64{1, 2}, 0, 2, nil) }) {
+		t.Errorf("CrossDerivative did not panic with index out of range")
+	}
+}
@@ -35,6 +35,7 @@ func CrossLaplacian(f func(x, y []float64) float64, x, y []float64, settings *Se
 	step := math.Sqrt(formula.Step) // Use the sqrt because taking derivatives of derivatives.
 	var originValue float64
 	var originKnown, concurrent bool
+	var workers int
 
 	// Use user settings if provided.
 	if settings != nil {
@@ -55,6 +56,7 @@ func CrossLaplacian(f func(x, y []float64) float64, x, y []float64, settings *Se
 		originKnown = settings.OriginKnown
 		originValue = settings.OriginValue
 		concurrent = settings.Concurrent
+		workers = settings.Workers
 	}
 
 	evals := n * len(formula.Stencil) * len(formula.Stencil)
@@ -62,7 +64,7 @@ func CrossLaplacian(f func(x, y []float64) float64, x, y []float64, settings *Se
 		evals -= n
 	}
 
-	nWorkers := computeWorkers(concurrent, evals)
+	nWorkers := computeWorkers(concurrent, workers, evals)
 	if nWorkers == 1 {
 		return crossLaplacianSerial(f, x, y, formula.Stencil, step, originKnown, originValue)
 	}
@@ -77,7 +79,7 @@ func crossLaplacianSerial(f func(x, y []float64) float64, x, y []float64, stenci
 		// Copy x and y in case they are modified during the call.
 		copy(xCopy, x)
 		copy(yCopy, y)
-		return f(x, y)
+		return f(xCopy, yCopy)
 	}
 	origin := getOrigin(originKnown, originValue, fo, stencil)
 
@@ -120,8 +122,7 @@ func crossLaplacianConcurrent(nWorkers, evals int, f func(x, y []float64) float6
 	ans := make(chan run, evals)
 
 	var originWG sync.WaitGroup
-	hasOrigin := usesOrigin(stencil)
-	if hasOrigin {
+	if usesOrigin(stencil) && !originKnown {
 		originWG.Add(1)
 		// Launch worker to compute the origin.
 		go func() {
@@ -5,6 +5,7 @@
 package fd
 
 import (
+	"sync"
 	"testing"
 
 	"gonum.org/v1/gonum/floats"
@@ -109,3 +110,32 @@ func TestCrossLaplacian(t *testing.T) {
 		}
 	}
 }
+
+func TestCrossLaplacianOriginKnown(t *testing.T) {
+	x := []float64{1, 2}
+	y := []float64{3, 4}
+	for _, concurrent := range []bool{false, true} {
+		var mu sync.Mutex
+		var originEvals int
+		f := func(u, v []float64) float64 {
+			if floats.Equal(u, x) && floats.Equal(v, y) {
+				mu.Lock()
+				originEvals++
+				mu.Unlock()
+			}
+			return u[0]*v[0] + u[1]*v[1]
+		}
+		settings := &Settings{
+			OriginKnown: true,
+			OriginValue: 11,
+			Concurrent:  concurrent,
+		}
+		got := CrossLaplacian(f, x, y, settings)
+		if originEvals != 0 {
+			t.Errorf("unexpected evaluation of known origin for concurrent=%t", concurrent)
+		}
+		if !floats.EqualWithinAbsOrRel(got, 2, 1e-6, 1e-6) {
+			t.Errorf("unexpected cross Laplacian for concurrent=%t: got:%v want:2", concurrent, got)
+		}
+	}
+}
@@ -6,20 +6,26 @@ package fd
 
 import (
 	"math"
-	"runtime"
 	"sync"
 )
 
 // Derivative estimates the derivative of the function f at the given location.
 // The finite difference formula, the step size, and other options are
 // specified by settings. If settings is nil, the first derivative will be
-// estimated using the Forward formula and a default step size.
+// estimated using the Forward formula and a default step size. If
+// settings.Bounds is not nil, it must have length one.
 func Derivative(f func(float64) float64, x float64, settings *Settings) float64 {
+	if settings != nil && settings.Adaptive {
+		deriv, _ := DerivativeError(f, x, settings)
+		return deriv
+	}
+
 	// Default settings.
 	formula := Forward
 	step := formula.Step
 	var originValue float64
 	var originKnown, concurrent bool
+	var workers int
 
 	// Use user settings if provided.
 	if settings != nil {
@@ -31,14 +37,26 @@ func Derivative(f func(float64) float64, x float64, settings *Settings) float64
 		if settings.Step != 0 {
 			step = settings.Step
 		}
+		if settings.Relative {
+			step *= math.Max(math.Abs(x), 1)
+		}
 		originKnown = settings.OriginKnown
 		originValue = settings.OriginValue
 		concurrent = settings.Concurrent
+		workers = settings.Workers
+	}
+	stencil := formula.Stencil
+	if settings != nil && settings.Bounds != nil {
+		if len(settings.Bounds) != 1 {
+			panic("fd: bounds length mismatch")
+		}
+		stencil, step = boundedStencil(formula, x, step, settings.Bounds[0])
 	}
 
 	var deriv float64
-	if !concurrent || runtime.GOMAXPROCS(0) == 1 {
-		for _, pt := range formula.Stencil {
+	nWorkers := computeWorkers(concurrent, workers, len(stencil))
+	if nWorkers == 1 {
+		for _, pt := range stencil {
 			if originKnown && pt.Loc == 0 {
 				deriv += pt.Coeff * originValue
 				continue
@@ -50,7 +68,8 @@ func Derivative(f func(float64) float64, x float64, settings *Settings) float64
 
 	wg := &sync.WaitGroup{}
 	mux := &sync.Mutex{}
-	for _, pt := range formula.Stencil {
+	sem := make(chan struct{}, nWorkers) // Limit the number of concurrent calls.
+	for _, pt := range stencil {
 		if originKnown && pt.Loc == 0 {
 			mux.Lock()
 			deriv += pt.Coeff * originValue
@@ -60,7 +79,9 @@ func Derivative(f func(float64) float64, x float64, settings *Settings) float64
 		wg.Add(1)
 		go func(pt Point) {
 			defer wg.Done()
+			sem <- struct{}{}
 			fofx := f(x + step*pt.Loc)
+			<-sem
 			mux.Lock()
 			defer mux.Unlock()
 			deriv += pt.Coeff * fofx
@@ -143,3 +143,94 @@ func TestDerivativeDefault(t *testing.T) {
 		}
 	}
 }
+
+func TestDerivativeBounds(t *testing.T) {
+	inf := math.Inf(1)
+	for i, test := range []struct {
+		f       func(float64) float64
+		loc     float64
+		formula Formula
+		bound   Bound
+		ans     float64
+		tol     float64
+	}{
+		{
+			// The Central stencil would evaluate log at a negative value.
+			f:       math.Log,
+			loc:     1e-6,
+			formula: Central,
+			bound:   Bound{Min: 0, Max: inf},
+			ans:     1e6,
+			tol:     1e-3,
+		},
+		{
+			f:       math.Log,
+			loc:     1e-3,
+			formula: Central,
+			bound:   Bound{Min: 1e-3, Max: inf},
+			ans:     1e3,
+			tol:     1e-3,
+		},
+		{
+			f:       func(x float64) float64 { return math.Sqrt(1 - x) },
+			loc:     0.5,
+			formula: Forward,
+			bound:   Bound{Min: -inf, Max: 0.5},
+			ans:     -1 / math.Sqrt(2),
+			tol:     1e-6,
+		},
+		{
+			f:       func(x float64) float64 { return x * x * x },
+			loc:     1,
+			formula: Central2nd,
+			bound:   Bound{Min: 0, Max: 1},
+			ans:     6,
+			tol:     1e-4,
+		},
+		{
+			f:       func(x float64) float64 { return x * x * x },
+			loc:     0,
+			formula: Forward2nd,
+			bound:   Bound{Min: -inf, Max: 0},
+			ans:     0,
+			tol:     1e-3,
+		},
+		{
+			// The bounds are narrower than the step.
+			f:       math.Sin,
+			loc:     0,
+			formula: Central,
+			bound:   Bound{Min: 0, Max: 1e-12},
+			ans:     1,
+			tol:     1e-6,
+		},
+	} {
+		settings := &Settings{Formula: test.formula, Bounds: []Bound{test.bound}}
+		if test.formula.Derivative == 1 {
+			settings.Step = 1e-9
+		}
+		for _, concurrent := range []bool{false, true} {
+			settings.Concurrent = concurrent
+			f := func(x float64) float64 {
+				if x < test.bound.Min || test.bound.Max < x {
+					t.Errorf("Case %v: evaluation outside bounds: %v", i, x)
+				}
+				return test.f(x)
+			}
+			ans := Derivative(f, test.loc, settings)
+			if math.Abs(test.ans-ans) > test.tol*math.Max(1, math.Abs(test.ans)) {
+				t.Errorf("Case %v: ans mismatch with bounds: expected %v, found %v", i, test.ans, ans)
+			}
+		}
+	}
+
+	for _, settings := range []*Settings{
+		{Bounds: []Bound{{Min: 1, Max: 2}}},
+		{Bounds: []Bound{{Min: 0, Max: 0}}},
+		{Bounds: []Bound{{Min: 0, Max: 1}, {Min: 0, Max: 1}}},
+	} {
+		if !Panics(func() { Derivative(math.Log, 0, settings) }) {
+			t.Errorf("Derivative did not panic with bounds %v", settings.Bounds)
+		}
+	}
+}
@@ -0,0 +1,110 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fd
+
+import (
+	"math"
+	"sync"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// DerivativeMat estimates the elementwise derivative of the matrix-valued
+// function f of a scalar at the location t and stores the result in-place into
+// dst. That is
+//  D_{i,j} = d^k F_{i,j}(t)/dt^k
+// where F(t) is the matrix stored into dst by f(dst, t) and k is the
+// derivative order of the formula. f is passed a matrix with the same
+// dimensions as dst into which it must store the value of the function.
+//
+// The finite difference formula, the step size, and other options are
+// specified by settings, and are interpreted as for Derivative. The value of
+// f at t is always evaluated, so settings.OriginKnown and settings.OriginValue
+// are not used, and settings.Adaptive is not used. If settings is nil, the
+// first derivative will be estimated using the Forward formula and a default
+// step size.
+//
+// The matrix passed to f must not be retained. If settings.Concurrent is
+// true, f may be called concurrently.
+//
+// DerivativeMat panics if dst is empty or if settings.Bounds is not nil and
+// does not have length one.
+func DerivativeMat(dst *mat.Dense, f func(dst *mat.Dense, t float64), t float64, settings *Settings) {
+	if dst.IsZero() {
+		panic("fd: empty destination matrix")
+	}
+	r, c := dst.Dims()
+
+	// Default settings.
+	formula := Forward
+	step := formula.Step
+	var concurrent bool
+	var workers int
+
+	// Use user settings if provided.
+	if settings != nil {
+		if !settings.Formula.isZero() {
+			formula = settings.Formula
+			step = formula.Step
+			checkFormula(formula)
+		}
+		if settings.Step != 0 {
+			step = settings.Step
+		}
+		if settings.Relative {
+			step *= math.Max(math.Abs(t), 1)
+		}
+		concurrent = settings.Concurrent
+		workers = settings.Workers
+	}
+	stencil := formula.Stencil
+	if settings != nil && settings.Bounds != nil {
+		if len(settings.Bounds) != 1 {
+			panic("fd: bounds length mismatch")
+		}
+		stencil, step = boundedStencil(formula, t, step, settings.Bounds[0])
+	}
+
+	for i := 0; i < r; i++ {
+		for j := 0; j < c; j++ {
+			dst.Set(i, j, 0)
+		}
+	}
+	nWorkers := computeWorkers(concurrent, workers, len(stencil))
+	if nWorkers == 1 {
+		y := mat.NewDense(r, c, nil)
+		for _, pt := range stencil {
+			f(y, t+step*pt.Loc)
+			y.Scale(pt.Coeff, y)
+			dst.Add(dst, y)
+		}
+	} else {
+		var (
+			wg sync.WaitGroup
+			mu sync.Mutex // Guard access to dst.
+		)
+		jobs := make(chan Point, nWorkers)
+		for i := 0; i < nWorkers; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				y := mat.NewDense(r, c, nil)
+				for pt := range jobs {
+					f(y, t+step*pt.Loc)
+					y.Scale(pt.Coeff, y)
+					mu.Lock()
+					dst.Add(dst, y)
+					mu.Unlock()
+				}
+			}()
+		}
+		for _, pt := range stencil {
+			jobs <- pt
+		}
+		close(jobs)
+		wg.Wait()
+	}
+	dst.Scale(1/math.Pow(step, float64(formula.Derivative)), dst)
+}
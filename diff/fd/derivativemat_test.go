@@ -0,0 +1,77 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fd
+
+import (
+	"math"
+	"testing"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestDerivativeMat(t *testing.T) {
+	// rot is the rotation by angle t scaled by t^2.
+	rot := func(dst *mat.Dense, t float64) {
+		s, c := math.Sincos(t)
+		dst.Set(0, 0, t*t*c)
+		dst.Set(0, 1, -t*t*s)
+		dst.Set(1, 0, t*t*s)
+		dst.Set(1, 1, t*t*c)
+		dst.Set(2, 0, t)
+		dst.Set(2, 1, 1)
+	}
+	rotDeriv := func(t float64) *mat.Dense {
+		s, c := math.Sincos(t)
+		return mat.NewDense(3, 2, []float64{
+			2*t*c - t*t*s, -2*t*s - t*t*c,
+			2*t*s + t*t*c, 2*t*c - t*t*s,
+			1, 0,
+		})
+	}
+	rotDeriv2 := func(t float64) *mat.Dense {
+		s, c := math.Sincos(t)
+		return mat.NewDense(3, 2, []float64{
+			2*c - 4*t*s - t*t*c, -2*s - 4*t*c + t*t*s,
+			2*s + 4*t*c - t*t*s, 2*c - 4*t*s - t*t*c,
+			0, 0,
+		})
+	}
+
+	for _, test := range []struct {
+		settings *Settings
+		want     func(float64) *mat.Dense
+		tol      float64
+	}{
+		{want: rotDeriv, tol: 1e-6},
+		{settings: &Settings{Formula: Central}, want: rotDeriv, tol: 1e-8},
+		{settings: &Settings{Formula: Central, Relative: true}, want: rotDeriv, tol: 1e-8},
+		{settings: &Settings{Formula: Central2nd}, want: rotDeriv2, tol: 1e-5},
+		{settings: &Settings{Formula: Central, Bounds: []Bound{{Min: 0.3, Max: 2}}}, want: rotDeriv, tol: 1e-6},
+	} {
+		for _, t0 := range []float64{0.3, 1.5, -4} {
+			if test.settings != nil && test.settings.Bounds != nil && t0 < 0 {
+				continue
+			}
+			for _, concurrent := range []bool{false, true} {
+				var settings Settings
+				if test.settings != nil {
+					settings = *test.settings
+				}
+				settings.Concurrent = concurrent
+				got := mat.NewDense(3, 2, nil)
+				DerivativeMat(got, rot, t0, &settings)
+				want := test.want(t0)
+				if !mat.EqualApprox(got, want, test.tol) {
+					t.Errorf("unexpected derivative at t=%v concurrent=%t:\ngot: %v\nwant:%v",
+						t0, concurrent, mat.Formatted(got), mat.Formatted(want))
+				}
+			}
+		}
+	}
+
+	if !Panics(func() { DerivativeMat(&mat.Dense{}, rot, 0, nil) }) {
+		t.Errorf("DerivativeMat did not panic with empty dst")
+	}
+}
@@ -5,8 +5,12 @@
 package fd
 
 import (
+	"errors"
 	"math"
 	"runtime"
+	"sync"
+
+	"gonum.org/v1/gonum/floats"
 )
 
 // A Point is a stencil location in a finite difference formula.
@@ -40,11 +44,174 @@ type Settings struct {
 	// Step is the distance between points of the stencil.
 	// If equal to 0, formula's default step will be used.
 	Step float64
+	// Steps holds the step size for each variable,
+	// overriding Step for each variable with a non-zero
+	// element. If Steps is not nil, it must have an
+	// element for each variable. Steps is used by
+	// Gradient.
+	Steps []float64
+	// Relative specifies that the step size for each
+	// variable is scaled by max(|x_i|, 1), so that badly
+	// scaled variables are perturbed by a comparable
+	// relative amount. Relative is used by Derivative
+	// and Gradient.
+	Relative bool
 
 	OriginKnown bool    // Flag that the value at the origin x is known.
 	OriginValue float64 // Value at the origin (only used if OriginKnown is true).
 
 	Concurrent bool // Should the function calls be executed concurrently.
+	// Workers is the maximum number of concurrent
+	// function calls when Concurrent is true. If
+	// Workers is zero, GOMAXPROCS calls are made
+	// concurrently.
+	Workers int
+
+	// Bounds holds the interval within which each
+	// variable may be evaluated. If Bounds is not nil,
+	// it must have an element for each variable.
+	// Near a bound the formula is replaced by a
+	// one-sided formula, and the step is reduced if
+	// necessary, so that no stencil point lies
+	// outside its interval. Bounds is used by
+	// Derivative and Gradient, and JacobianSettings
+	// has a corresponding field.
+	Bounds []Bound
+
+	// Adaptive specifies that the step size is chosen
+	// automatically for each variable, ignoring Step,
+	// Steps and Relative. The derivative is estimated
+	// with a sequence of decreasing step sizes and the
+	// estimate with the smallest estimated error is
+	// used. Adaptive is used by Derivative and Gradient.
+	Adaptive bool
+	// Noise is the absolute error in the values of the
+	// function, used to estimate the floating point
+	// cancellation error of a derivative estimate. If
+	// Noise is zero, the error is taken to be machine
+	// epsilon relative to the function values.
+	Noise float64
+
+	// BatchSize is the maximum number of locations
+	// passed in a single call to a batched function.
+	// If BatchSize is zero, all locations are passed
+	// in a single call. BatchSize is used by
+	// GradientBatch and HessianBatch.
+	BatchSize int
+
+	// Cache holds the function values at previously
+	// evaluated locations. If Cache is not nil, a
+	// location held in Cache is not evaluated again
+	// and the values at new locations are stored in
+	// Cache. Cache is used by Gradient, Hessian,
+	// Laplacian and CrossDerivative, and
+	// JacobianSettings has a corresponding field.
+	Cache *Cache
+}
+
+// Bound is the closed interval [Min, Max] within which a variable may be
+// evaluated. An unbounded side is represented by an infinite value.
+type Bound struct {
+	Min, Max float64
+}
+
+// boundedStencil returns a stencil approximating the same derivative as
+// formula and a step no larger than step such that the variable at x is only
+// evaluated within b. The stencil of formula is returned if it is within b,
+// followed in order of preference by the stencil of formula reflected about x,
+// and second-order accurate forward and backward one-sided stencils. If none
+// of these is within b, the step is reduced to the largest step for which
+// one of them is within b.
+// boundedStencil panics if x is outside b or b has zero width.
+func boundedStencil(formula Formula, x, step float64, b Bound) ([]Point, float64) {
+	if x < b.Min || b.Max < x {
+		panic("fd: location outside bounds")
+	}
+	if stencil, ok := fitStencil(formula, x, step, b); ok {
+		return stencil, step
+	}
+	var (
+		best     []Point
+		bestStep float64
+	)
+	for _, stencil := range boundedCandidates(formula) {
+		if s := maxStep(stencil, x, b); s > bestStep {
+			best, bestStep = stencil, s
+		}
+	}
+	if bestStep == 0 {
+		panic("fd: bounds too narrow for step")
+	}
+	return best, bestStep
+}
+
+// fitStencil returns the most preferred of the stencils considered by
+// boundedStencil that is within b when scaled by step, and whether any
+// such stencil exists.
+func fitStencil(formula Formula, x, step float64, b Bound) ([]Point, bool) {
+	for _, stencil := range boundedCandidates(formula) {
+		if within(stencil, x, step, b) {
+			return stencil, true
+		}
+	}
+	return nil, false
+}
+
+// boundedCandidates returns the stencils considered by boundedStencil
+// in order of preference.
+func boundedCandidates(formula Formula) [][]Point {
+	fwd := NewFormula(formula.Derivative, 2, ForwardStencil).Stencil
+	return [][]Point{
+		formula.Stencil,
+		reflect(formula.Stencil, formula.Derivative),
+		fwd,
+		reflect(fwd, formula.Derivative),
+	}
+}
+
+// within returns whether all locations of stencil scaled by step
+// and relative to x are within b.
+func within(stencil []Point, x, step float64, b Bound) bool {
+	for _, pt := range stencil {
+		if v := x + pt.Loc*step; v < b.Min || b.Max < v {
+			return false
+		}
+	}
+	return true
+}
+
+// maxStep returns the largest step for which stencil is within b
+// relative to x.
+func maxStep(stencil []Point, x float64, b Bound) float64 {
+	s := math.Inf(1)
+	for _, pt := range stencil {
+		switch {
+		case pt.Loc > 0:
+			s = math.Min(s, (b.Max-x)/pt.Loc)
+		case pt.Loc < 0:
+			s = math.Min(s, (x-b.Min)/-pt.Loc)
+		}
+	}
+	// Guard against rounding placing a
+	// location just outside the bounds.
+	for s > 0 && !within(stencil, x, s, b) {
+		s = math.Nextafter(s, 0)
+	}
+	return s
+}
+
+// reflect returns the stencil reflected about the origin
+// for a formula approximating the derivative of order k.
+func reflect(stencil []Point, k int) []Point {
+	sign := 1.0
+	if k%2 == 1 {
+		sign = -1
+	}
+	r := make([]Point, len(stencil))
+	for i, pt := range stencil {
+		r[i] = Point{Loc: -pt.Loc, Coeff: sign * pt.Coeff}
+	}
+	return r
 }
 
 // Forward represents a first-order accurate forward approximation
@@ -105,18 +272,138 @@ func checkFormula(formula Formula) {
 }
 
 // computeWorkers returns the desired number of workers given the concurrency
-// level and number of evaluations.
-func computeWorkers(concurrent bool, evals int) int {
+// level, the requested number of workers and the number of evaluations.
+func computeWorkers(concurrent bool, workers, evals int) int {
 	if !concurrent {
 		return 1
 	}
-	nWorkers := runtime.GOMAXPROCS(0)
+	if workers < 0 {
+		panic("fd: negative number of workers")
+	}
+	nWorkers := workers
+	if nWorkers == 0 {
+		nWorkers = runtime.GOMAXPROCS(0)
+	}
 	if nWorkers > evals {
 		nWorkers = evals
 	}
 	return nWorkers
 }
 
+// ErrNaN is returned by JacobianChecked when the function returns a NaN value.
+var ErrNaN = errors.New("fd: NaN function value")
+
+// failOnNaN returns a function that calls f and returns ErrNaN if f
+// succeeds but stores a NaN value into y.
+func failOnNaN(f func(y, x []float64) error) func(y, x []float64) error {
+	return func(y, x []float64) error {
+		if err := f(y, x); err != nil {
+			return err
+		}
+		if floats.HasNaN(y) {
+			return ErrNaN
+		}
+		return nil
+	}
+}
+
+// firstError records the first error reported by a set of workers. The
+// done channel is closed when an error is recorded.
+type firstError struct {
+	mu   sync.Mutex
+	err  error
+	done chan struct{}
+}
+
+func newFirstError() *firstError {
+	return &firstError{done: make(chan struct{})}
+}
+
+// set records err if it is non-nil and no error has been recorded.
+func (e *firstError) set(err error) {
+	if err == nil {
+		return
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.err != nil {
+		return
+	}
+	e.err = err
+	if e.done != nil {
+		close(e.done)
+	}
+}
+
+// failed returns whether an error has been recorded.
+func (e *firstError) failed() bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.err != nil
+}
+
+// floatPool holds buffers that are reused by evaluation workers
+// across calls.
+var floatPool sync.Pool
+
+// getFloats returns a buffer of length n from floatPool. The buffer
+// is not zeroed and should be returned to the pool with putFloats.
+func getFloats(n int) *[]float64 {
+	p, _ := floatPool.Get().(*[]float64)
+	if p == nil || cap(*p) < n {
+		s := make([]float64, n)
+		return &s
+	}
+	*p = (*p)[:n]
+	return p
+}
+
+// putFloats returns a buffer obtained from getFloats to floatPool.
+func putFloats(p *[]float64) {
+	floatPool.Put(p)
+}
+
+// stepSizes returns the step size for each element of x given the default
+// step, the per-variable overrides in steps and whether the step sizes are
+// relative to the magnitude of x. It panics if steps is not nil and its length
+// is not equal to the length of x, or if any step size is negative.
+func stepSizes(step float64, steps []float64, relative bool, x []float64) []float64 {
+	if steps != nil && len(steps) != len(x) {
+		panic("fd: steps length mismatch")
+	}
+	s := make([]float64, len(x))
+	for i := range s {
+		s[i] = step
+		if steps != nil && steps[i] != 0 {
+			s[i] = steps[i]
+		}
+		if s[i] < 0 {
+			panic(negativeStep)
+		}
+		if relative {
+			s[i] *= math.Max(math.Abs(x[i]), 1)
+		}
+	}
+	return s
+}
+
+// variableStencils returns the stencil and step size used for each element
+// of x given the formula, the default step, the per-variable overrides in
+// steps, whether the step sizes are relative to the magnitude of x, and the
+// bounds, which may be nil. Near a bound the stencil may differ from the
+// stencil of the formula and the step may be reduced.
+func variableStencils(formula Formula, step float64, steps []float64, relative bool, bounds []Bound, x []float64) ([][]Point, []float64) {
+	stepOf := stepSizes(step, steps, relative, x)
+	stencils := make([][]Point, len(x))
+	for i := range stencils {
+		stencils[i] = formula.Stencil
+	}
+	for i, b := range bounds {
+		stencils[i], stepOf[i] = boundedStencil(formula, x[i], stepOf[i], b)
+	}
+	return stencils, stepOf
+}
+
 // usesOrigin returns whether the stencil uses the origin, which is true iff
 // one of the locations in the stencil equals 0.
 func usesOrigin(stencil []Point) bool {
@@ -0,0 +1,454 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fd
+
+import (
+	"math"
+	"sync"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+)
+
+// The adaptive step sizes for a variable at x are
+//  max(|x|, 1) * adaptiveStep / adaptiveRatio^k
+// for k = 0, ..., adaptiveSteps-1.
+const (
+	adaptiveSteps = 14
+	adaptiveStep  = 0.1
+	adaptiveRatio = 4
+)
+
+// DerivativeError estimates the derivative of the function f at the given
+// location as Derivative does, and also returns an estimate of the absolute
+// error of the derivative.
+//
+// The truncation error of the derivative is estimated by Richardson
+// extrapolation, comparing the estimate with one made with half the step
+// size, and the floating point cancellation error is estimated from
+// settings.Noise. If settings.Adaptive is true, the estimate with the smallest
+// estimated error among a sequence of step sizes is returned. Estimating the
+// error requires additional function evaluations. settings.Concurrent is not
+// used.
+func DerivativeError(f func(float64) float64, x float64, settings *Settings) (deriv, err float64) {
+	// Default settings.
+	formula := Forward
+	step := formula.Step
+	var originValue, noise float64
+	var originKnown, adaptive bool
+	var bound *Bound
+
+	// Use user settings if provided.
+	if settings != nil {
+		if !settings.Formula.isZero() {
+			formula = settings.Formula
+			step = formula.Step
+			checkFormula(formula)
+		}
+		if settings.Step != 0 {
+			step = settings.Step
+		}
+		if settings.Relative {
+			step *= math.Max(math.Abs(x), 1)
+		}
+		if settings.Bounds != nil {
+			if len(settings.Bounds) != 1 {
+				panic("fd: bounds length mismatch")
+			}
+			bound = &settings.Bounds[0]
+		}
+		originKnown = settings.OriginKnown
+		originValue = settings.OriginValue
+		adaptive = settings.Adaptive
+		noise = settings.Noise
+	}
+	if bound != nil {
+		if adaptive {
+			if x < bound.Min || bound.Max < x {
+				panic("fd: location outside bounds")
+			}
+		} else {
+			_, step = boundedStencil(formula, x, step, *bound)
+		}
+	}
+
+	var origin []float64
+	if originKnown {
+		origin = []float64{originValue}
+	}
+	g := func(y []float64, t float64) {
+		y[0] = f(x + t)
+	}
+	var d, e [1]float64
+	estimate(d[:], e[:], g, origin, x, bound, formula, candidateSteps(step, adaptive, x), noise)
+	return d[0], e[0]
+}
+
+// GradientError estimates the gradient of the multivariate function f at the
+// location x as Gradient does, and stores the estimated absolute error of each
+// element of the gradient into errs if errs is not nil. The gradient is stored
+// in-place into dst if it is not nil, and is returned. The errors are estimated
+// as described for DerivativeError, and if settings.Adaptive is true the step
+// size is chosen independently for each element.
+//
+// GradientError panics under the same conditions as Gradient, or if errs is not
+// nil and its length is not equal to the length of x.
+func GradientError(dst, errs []float64, f func([]float64) float64, x []float64, settings *Settings) []float64 {
+	n := len(x)
+	if dst == nil {
+		dst = make([]float64, n)
+	}
+	if len(dst) != n {
+		panic("fd: slice length mismatch")
+	}
+	if errs == nil {
+		errs = make([]float64, n)
+	}
+	if len(errs) != n {
+		panic("fd: slice length mismatch")
+	}
+
+	// Default settings.
+	formula := Forward
+	step := formula.Step
+	var originValue, noise float64
+	var originKnown, concurrent, relative, adaptive bool
+	var workers int
+	var steps []float64
+	var bounds []Bound
+
+	// Use user settings if provided.
+	if settings != nil {
+		if !settings.Formula.isZero() {
+			formula = settings.Formula
+			step = formula.Step
+			checkFormula(formula)
+			if formula.Derivative != 1 {
+				panic(badDerivOrder)
+			}
+		}
+		if settings.Step != 0 {
+			step = settings.Step
+		}
+		steps = settings.Steps
+		relative = settings.Relative
+		bounds = settings.Bounds
+		if bounds != nil && len(bounds) != n {
+			panic("fd: bounds length mismatch")
+		}
+		originKnown = settings.OriginKnown
+		originValue = settings.OriginValue
+		concurrent = settings.Concurrent
+		workers = settings.Workers
+		f = settings.Cache.scalar(f)
+		adaptive = settings.Adaptive
+		noise = settings.Noise
+	}
+	stepOf := stepSizes(step, steps, relative, x)
+	for i, b := range bounds {
+		if adaptive {
+			if x[i] < b.Min || b.Max < x[i] {
+				panic("fd: location outside bounds")
+			}
+			continue
+		}
+		_, stepOf[i] = boundedStencil(formula, x[i], stepOf[i], b)
+	}
+
+	// The origin is shared by all elements. One-sided
+	// stencils used near bounds include the origin.
+	var origin []float64
+	if originKnown {
+		origin = []float64{originValue}
+	} else if usesOrigin(formula.Stencil) || bounds != nil {
+		xcopy := make([]float64, n)
+		copy(xcopy, x)
+		origin = []float64{f(xcopy)}
+	}
+
+	element := func(i int, xcopy []float64) {
+		g := func(y []float64, t float64) {
+			// See Gradient for comment on the copy.
+			copy(xcopy, x)
+			xcopy[i] += t
+			y[0] = f(xcopy)
+		}
+		var b *Bound
+		if bounds != nil {
+			b = &bounds[i]
+		}
+		estimate(dst[i:i+1], errs[i:i+1], g, origin, x[i], b, formula, candidateSteps(stepOf[i], adaptive, x[i]), noise)
+	}
+	forEachVariable(n, computeWorkers(concurrent, workers, n), element)
+	return dst
+}
+
+// JacobianError approximates the Jacobian matrix of a vector-valued function
+// f at the location x as Jacobian does, and stores the estimated absolute error
+// of each element of the Jacobian into errs if errs is not nil. The errors are
+// estimated as described for DerivativeError, and if settings.Adaptive is true
+// the step size is chosen independently for each element. settings.Pattern is
+// not used.
+//
+// JacobianError panics under the same conditions as Jacobian, or if errs is not
+// nil and its dimensions do not match those of dst.
+func JacobianError(dst, errs *mat.Dense, f func(y, x []float64), x []float64, settings *JacobianSettings) {
+	n := len(x)
+	if n == 0 {
+		panic("jacobian: x has zero length")
+	}
+	m, c := dst.Dims()
+	if c != n {
+		panic("jacobian: mismatched matrix size")
+	}
+	if errs != nil {
+		if r, c := errs.Dims(); r != m || c != n {
+			panic("jacobian: mismatched matrix size")
+		}
+	}
+
+	// Default settings.
+	formula := Forward
+	step := formula.Step
+	var originValue []float64
+	var concurrent, relative, adaptive bool
+	var workers int
+	var steps []float64
+	var bounds []Bound
+	var noise float64
+
+	// Use user settings if provided.
+	if settings != nil {
+		if !settings.Formula.isZero() {
+			formula = settings.Formula
+			step = formula.Step
+			checkFormula(formula)
+			if formula.Derivative != 1 {
+				panic(badDerivOrder)
+			}
+		}
+		if settings.Step != 0 {
+			step = settings.Step
+		}
+		steps = settings.Steps
+		relative = settings.Relative
+		bounds = settings.Bounds
+		if bounds != nil && len(bounds) != n {
+			panic("jacobian: mismatched bounds length")
+		}
+		originValue = settings.OriginValue
+		if originValue != nil && len(originValue) != m {
+			panic("jacobian: mismatched OriginValue slice length")
+		}
+		concurrent = settings.Concurrent
+		workers = settings.Workers
+		if settings.Cache != nil {
+			cached := settings.Cache.vector(func(y, x []float64) error {
+				f(y, x)
+				return nil
+			})
+			f = func(y, x []float64) { cached(y, x) }
+		}
+		adaptive = settings.Adaptive
+		noise = settings.Noise
+	}
+	stepOf := stepSizes(step, steps, relative, x)
+	for j, b := range bounds {
+		if adaptive {
+			if x[j] < b.Min || b.Max < x[j] {
+				panic("fd: location outside bounds")
+			}
+			continue
+		}
+		_, stepOf[j] = boundedStencil(formula, x[j], stepOf[j], b)
+	}
+
+	// One-sided stencils used near bounds include the origin.
+	if originValue == nil && (usesOrigin(formula.Stencil) || bounds != nil) {
+		originValue = make([]float64, m)
+		xcopy := make([]float64, n)
+		copy(xcopy, x)
+		f(originValue, xcopy)
+	}
+
+	column := func(j int, xcopy []float64) {
+		g := func(y []float64, t float64) {
+			copy(xcopy, x)
+			xcopy[j] += t
+			f(y, xcopy)
+		}
+		var b *Bound
+		if bounds != nil {
+			b = &bounds[j]
+		}
+		col := make([]float64, m)
+		ecol := make([]float64, m)
+		estimate(col, ecol, g, originValue, x[j], b, formula, candidateSteps(stepOf[j], adaptive, x[j]), noise)
+		// Each column is written by a single call.
+		dst.SetCol(j, col)
+		if errs != nil {
+			errs.SetCol(j, ecol)
+		}
+	}
+	forEachVariable(n, computeWorkers(concurrent, workers, n), column)
+}
+
+// forEachVariable calls fn for each variable index in [0, n) using nWorkers
+// goroutines. Each goroutine passes its own scratch slice of length n to fn.
+func forEachVariable(n, nWorkers int, fn func(i int, xcopy []float64)) {
+	if nWorkers <= 1 {
+		xcopy := make([]float64, n)
+		for i := 0; i < n; i++ {
+			fn(i, xcopy)
+		}
+		return
+	}
+	var wg sync.WaitGroup
+	jobs := make(chan int, nWorkers)
+	for w := 0; w < nWorkers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			xcopy := make([]float64, n)
+			for i := range jobs {
+				fn(i, xcopy)
+			}
+		}()
+	}
+	for i := 0; i < n; i++ {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+}
+
+// candidateSteps returns the decreasing step sizes used to estimate a
+// derivative with respect to a variable at x. If adaptive is false, step
+// and half of step are returned, otherwise the adaptive step sizes are.
+func candidateSteps(step float64, adaptive bool, x float64) []float64 {
+	if step < 0 {
+		panic(negativeStep)
+	}
+	if !adaptive {
+		return []float64{step, step / 2}
+	}
+	steps := make([]float64, adaptiveSteps)
+	h := math.Max(math.Abs(x), 1) * adaptiveStep
+	for k := range steps {
+		steps[k] = h
+		h /= adaptiveRatio
+	}
+	return steps
+}
+
+// estimate estimates the derivative of a vector-valued function with respect
+// to a single variable at x, storing the estimate and its estimated absolute
+// error into dst and errs. The function g evaluates the function into y with
+// the variable displaced by t, and origin, if not nil, holds the value of the
+// function at x. If b is not nil, stencils are chosen to remain within b.
+//
+// The derivative is estimated with each of the decreasing step sizes in steps.
+// The truncation error of the estimate for each step is obtained by Richardson
+// extrapolation against the estimate for the following step, and is added to
+// the cancellation error implied by noise. For each element, the estimate with
+// the smallest error is used; the estimate for the last step is never used.
+func estimate(dst, errs []float64, g func(y []float64, t float64), origin []float64, x float64, b *Bound, formula Formula, steps []float64, noise float64) {
+	m := len(dst)
+	type stepEstimate struct {
+		step  float64
+		order int
+		est   []float64
+		round []float64 // Floating point cancellation error of est.
+	}
+	ests := make([]*stepEstimate, len(steps))
+	y := make([]float64, m)
+	for k, h := range steps {
+		stencil := formula.Stencil
+		if b != nil {
+			var ok bool
+			stencil, ok = fitStencil(formula, x, h, *b)
+			if !ok {
+				continue
+			}
+		}
+		est := make([]float64, m)
+		fmax := make([]float64, m)
+		var sum float64
+		for _, pt := range stencil {
+			v := y
+			if pt.Loc == 0 {
+				if origin == nil {
+					origin = make([]float64, m)
+					g(origin, 0)
+				}
+				v = origin
+			} else {
+				g(y, pt.Loc*h)
+			}
+			floats.AddScaled(est, pt.Coeff, v)
+			for i, vi := range v {
+				fmax[i] = math.Max(fmax[i], math.Abs(vi))
+			}
+			sum += math.Abs(pt.Coeff)
+		}
+		scale := math.Pow(h, float64(formula.Derivative))
+		round := make([]float64, m)
+		for i := range est {
+			est[i] /= scale
+			eps := noise
+			if eps == 0 {
+				eps = machEps * fmax[i]
+			}
+			round[i] = eps * sum / scale
+		}
+		ests[k] = &stepEstimate{
+			step:  h,
+			order: accuracyOrder(stencil, formula.Derivative),
+			est:   est,
+			round: round,
+		}
+	}
+
+	var pairs int
+	for k := 0; k+1 < len(ests); k++ {
+		a, c := ests[k], ests[k+1]
+		if a == nil || c == nil {
+			continue
+		}
+		pairs++
+		rp := math.Pow(a.step/c.step, float64(a.order))
+		for i := range dst {
+			e := math.Abs(a.est[i]-c.est[i])*rp/(rp-1) + a.round[i]
+			if pairs == 1 || e < errs[i] || math.IsNaN(errs[i]) {
+				dst[i] = a.est[i]
+				errs[i] = e
+			}
+		}
+	}
+	if pairs == 0 {
+		panic("fd: bounds too narrow for step")
+	}
+}
+
+// accuracyOrder returns the order of accuracy of a stencil approximating the
+// derivative of order deriv, which is the power of the step size in the leading
+// term of the truncation error.
+func accuracyOrder(stencil []Point, deriv int) int {
+	for k := deriv + 1; k <= deriv+len(stencil); k++ {
+		var moment, size float64
+		for _, pt := range stencil {
+			t := pt.Coeff * math.Pow(pt.Loc, float64(k))
+			moment += t
+			size += math.Abs(t)
+		}
+		if math.Abs(moment) > 1e-8*size {
+			return k - deriv
+		}
+	}
+	return len(stencil)
+}
+
+// machEps is the machine epsilon.
+const machEps = 1.0 / (1 << 53)
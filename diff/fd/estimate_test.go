@@ -0,0 +1,138 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fd
+
+import (
+	"math"
+	"testing"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestDerivativeError(t *testing.T) {
+	for _, test := range []struct {
+		f, df   func(float64) float64
+		x       float64
+		formula Formula
+	}{
+		{f: math.Sin, df: math.Cos, x: 1, formula: Forward},
+		{f: math.Sin, df: math.Cos, x: 1, formula: Backward},
+		{f: math.Sin, df: math.Cos, x: 1, formula: Central},
+		{f: math.Exp, df: math.Exp, x: 3, formula: Central},
+		{f: math.Sin, df: func(x float64) float64 { return -math.Sin(x) }, x: 2, formula: Central2nd},
+		{f: math.Sin, df: func(x float64) float64 { return -math.Sin(x) }, x: 2, formula: Forward2nd},
+	} {
+		want := test.df(test.x)
+		for _, adaptive := range []bool{false, true} {
+			settings := &Settings{Formula: test.formula, Adaptive: adaptive}
+			got, err := DerivativeError(test.f, test.x, settings)
+			if got != Derivative(test.f, test.x, settings) {
+				t.Errorf("DerivativeError does not match Derivative for adaptive=%t formula %v", adaptive, test.formula)
+			}
+			if math.Abs(got-want) > 2*err {
+				t.Errorf("error underestimated for adaptive=%t formula %v: estimated:%v actual:%v", adaptive, test.formula, err, math.Abs(got-want))
+			}
+			if math.Abs(got-want) < 1e-3*err && err > 1e-12 {
+				t.Errorf("error overestimated for adaptive=%t formula %v: estimated:%v actual:%v", adaptive, test.formula, err, math.Abs(got-want))
+			}
+		}
+	}
+
+	// The default step is far too small for this function.
+	f := func(x float64) float64 { return math.Sin(1e-4 * x) }
+	want := 1e-4 * math.Cos(1)
+	got, err := DerivativeError(f, 1e4, &Settings{Formula: Central, Adaptive: true})
+	if math.Abs(got-want) > 1e-8*want {
+		t.Errorf("unexpected adaptive derivative: got:%v want:%v", got, want)
+	}
+	if math.Abs(got-want) > 2*err {
+		t.Errorf("adaptive error underestimated: estimated:%v actual:%v", err, math.Abs(got-want))
+	}
+
+	// Noisy function values increase the chosen step.
+	noisy := func(x float64) float64 { return math.Exp(x) + 1e-6*math.Sin(1e9*x) }
+	got, err = DerivativeError(noisy, 0, &Settings{Formula: Central, Adaptive: true, Noise: 1e-6})
+	if math.Abs(got-1) > 1e-3 {
+		t.Errorf("unexpected adaptive derivative of noisy function: got:%v want:1", got)
+	}
+	if err < 1e-6 {
+		t.Errorf("noise not included in error estimate: got:%v", err)
+	}
+}
+
+func TestGradientError(t *testing.T) {
+	x := []float64{1e-4, 1e4}
+	want := badlyScaledGrad(x)
+	for _, adaptive := range []bool{false, true} {
+		var first []float64
+		for _, concurrent := range []bool{false, true} {
+			settings := &Settings{Formula: Central, Adaptive: adaptive, Concurrent: concurrent, Steps: []float64{1e-9, 1e-1}}
+			errs := make([]float64, len(x))
+			got := GradientError(nil, errs, badlyScaled, x, settings)
+			for i := range got {
+				if math.Abs(got[i]-want[i]) > 1e-6*math.Abs(want[i]) {
+					t.Errorf("gradient mismatch for adaptive=%t element %d: want:%v got:%v", adaptive, i, want[i], got[i])
+				}
+				if math.Abs(got[i]-want[i]) > 2*errs[i] {
+					t.Errorf("error underestimated for adaptive=%t element %d: estimated:%v actual:%v", adaptive, i, errs[i], math.Abs(got[i]-want[i]))
+				}
+			}
+			if first == nil {
+				first = got
+			} else if !floats.Equal(got, first) {
+				t.Errorf("concurrent gradient mismatch for adaptive=%t: got:%v want:%v", adaptive, got, first)
+			}
+			if !adaptive && !floats.Equal(got, Gradient(nil, badlyScaled, x, settings)) {
+				t.Errorf("GradientError does not match Gradient")
+			}
+		}
+	}
+
+	// Adaptive steps respect bounds.
+	f := func(x []float64) float64 {
+		var sum float64
+		for _, v := range x {
+			if v < 0 {
+				t.Errorf("evaluation outside bounds: %v", x)
+			}
+			sum += math.Log(v)
+		}
+		return sum
+	}
+	x = []float64{1e-3, 10}
+	bounds := []Bound{{Min: 0, Max: math.Inf(1)}, {Min: 0, Max: math.Inf(1)}}
+	errs := make([]float64, len(x))
+	got := GradientError(nil, errs, f, x, &Settings{Formula: Central, Bounds: bounds, Adaptive: true})
+	for i, v := range x {
+		if math.Abs(got[i]-1/v) > 1e-6/v {
+			t.Errorf("gradient mismatch with bounds for element %d: want:%v got:%v", i, 1/v, got[i])
+		}
+	}
+}
+
+func TestJacobianError(t *testing.T) {
+	x := []float64{0.3, -1.2, 2}
+	want := mat.NewDense(4, 3, nil)
+	vecFunc43Jac(want, x)
+	for _, adaptive := range []bool{false, true} {
+		for _, concurrent := range []bool{false, true} {
+			got := mat.NewDense(4, 3, nil)
+			errs := mat.NewDense(4, 3, nil)
+			JacobianError(got, errs, vecFunc43, x, &JacobianSettings{Formula: Central, Adaptive: adaptive, Concurrent: concurrent})
+			for i := 0; i < 4; i++ {
+				for j := 0; j < 3; j++ {
+					diff := math.Abs(got.At(i, j) - want.At(i, j))
+					if diff > 1e-8 {
+						t.Errorf("Jacobian mismatch for adaptive=%t element (%d,%d): want:%v got:%v", adaptive, i, j, want.At(i, j), got.At(i, j))
+					}
+					if diff > 2*errs.At(i, j) {
+						t.Errorf("error underestimated for adaptive=%t element (%d,%d): estimated:%v actual:%v", adaptive, i, j, errs.At(i, j), diff)
+					}
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,149 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fd
+
+import "math"
+
+// StencilKind specifies the placement of the stencil locations of a
+// finite difference formula relative to the origin.
+type StencilKind int
+
+const (
+	// CentralStencil locations are placed
+	// symmetrically about the origin.
+	CentralStencil StencilKind = iota
+	// ForwardStencil locations are at and
+	// after the origin.
+	ForwardStencil
+	// BackwardStencil locations are at and
+	// before the origin.
+	BackwardStencil
+)
+
+// NewFormula returns a finite difference formula approximating the derivative
+// of the given order with the given order of accuracy on a regularly spaced
+// stencil of the given kind. The coefficients are computed with Fornberg's
+// algorithm described in doi:10.1090/S0025-5718-1988-0935077-0.
+//
+// Forward and backward stencils use derivative+accuracy locations and may be
+// used near the bounds of a domain. Central stencils are symmetric, so accuracy
+// must be even for CentralStencil. Locations with a zero coefficient are not
+// included in the stencil. The default step of the returned formula balances
+// truncation and floating point cancellation error for a well scaled function.
+//
+// NewFormula panics if derivative or accuracy is less than one, if accuracy is
+// odd for a central stencil or if kind is not a valid StencilKind.
+func NewFormula(derivative, accuracy int, kind StencilKind) Formula {
+	if derivative < 1 {
+		panic(badDerivOrder)
+	}
+	if accuracy < 1 {
+		panic("fd: invalid accuracy")
+	}
+	var lo, hi int
+	switch kind {
+	case CentralStencil:
+		if accuracy%2 != 0 {
+			panic("fd: odd accuracy for central stencil")
+		}
+		hi = (derivative-1)/2 + accuracy/2
+		lo = -hi
+	case ForwardStencil:
+		hi = derivative + accuracy - 1
+	case BackwardStencil:
+		lo = -(derivative + accuracy - 1)
+	default:
+		panic("fd: invalid stencil kind")
+	}
+	locs := make([]float64, 0, hi-lo+1)
+	for l := lo; l <= hi; l++ {
+		locs = append(locs, float64(l))
+	}
+	return Formula{
+		Stencil:    NewStencil(derivative, locs),
+		Derivative: derivative,
+		Step:       math.Pow(machEps, 1/float64(derivative+accuracy)),
+	}
+}
+
+// NewStencil returns the stencil approximating the derivative of the given
+// order at the origin with the maximum order of accuracy possible using the
+// given locations, which need not be regularly spaced. Locations with a zero
+// coefficient are not included in the stencil.
+//
+// NewStencil panics if derivative is less than one, if there are not more
+// locations than the derivative order or if the locations are not distinct.
+func NewStencil(derivative int, locs []float64) []Point {
+	if derivative < 1 {
+		panic(badDerivOrder)
+	}
+	if len(locs) <= derivative {
+		panic("fd: too few stencil locations")
+	}
+	coeffs := fornberg(derivative, locs)
+
+	// Coefficients that are zero in exact arithmetic
+	// are subject to rounding error, so compare them
+	// against the largest coefficient.
+	var max float64
+	for _, c := range coeffs {
+		max = math.Max(max, math.Abs(c))
+	}
+	stencil := make([]Point, 0, len(locs))
+	for i, c := range coeffs {
+		if math.Abs(c) <= 1e-12*max {
+			continue
+		}
+		stencil = append(stencil, Point{Loc: locs[i], Coeff: c})
+	}
+	return stencil
+}
+
+// fornberg returns the finite difference weights for the derivative of order
+// m at the origin using function values at the locations in x.
+func fornberg(m int, x []float64) []float64 {
+	n := len(x)
+	// c[j][k] holds the weight of x[j] for the
+	// derivative of order k.
+	c := make([][]float64, n)
+	for j := range c {
+		c[j] = make([]float64, m+1)
+	}
+	c[0][0] = 1
+	c1 := 1.0
+	c4 := x[0]
+	for i := 1; i < n; i++ {
+		mn := i
+		if mn > m {
+			mn = m
+		}
+		c2 := 1.0
+		c5 := c4
+		c4 = x[i]
+		for j := 0; j < i; j++ {
+			c3 := x[i] - x[j]
+			if c3 == 0 {
+				panic("fd: repeated stencil location")
+			}
+			c2 *= c3
+			if j == i-1 {
+				for k := mn; k >= 1; k-- {
+					c[i][k] = c1 * (float64(k)*c[i-1][k-1] - c5*c[i-1][k]) / c2
+				}
+				c[i][0] = -c1 * c5 * c[i-1][0] / c2
+			}
+			for k := mn; k >= 1; k-- {
+				c[j][k] = (c4*c[j][k] - float64(k)*c[j][k-1]) / c3
+			}
+			c[j][0] = c4 * c[j][0] / c3
+		}
+		c1 = c2
+	}
+	w := make([]float64, n)
+	for j := range w {
+		w[j] = c[j][m]
+	}
+	return w
+}
@@ -0,0 +1,123 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fd
+
+import (
+	"math"
+	"sort"
+	"testing"
+)
+
+type byLoc []Point
+
+func (p byLoc) Len() int           { return len(p) }
+func (p byLoc) Less(i, j int) bool { return p[i].Loc < p[j].Loc }
+func (p byLoc) Swap(i, j int)      { p[i], p[j] = p[j], p[i] }
+
+func sameStencil(a, b []Point, tol float64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	a = append([]Point(nil), a...)
+	b = append([]Point(nil), b...)
+	sort.Sort(byLoc(a))
+	sort.Sort(byLoc(b))
+	for i := range a {
+		if a[i].Loc != b[i].Loc || math.Abs(a[i].Coeff-b[i].Coeff) > tol {
+			return false
+		}
+	}
+	return true
+}
+
+func TestNewFormula(t *testing.T) {
+	for _, test := range []struct {
+		derivative, accuracy int
+		kind                 StencilKind
+		want                 []Point
+	}{
+		{derivative: 1, accuracy: 1, kind: ForwardStencil, want: Forward.Stencil},
+		{derivative: 1, accuracy: 1, kind: BackwardStencil, want: Backward.Stencil},
+		{derivative: 1, accuracy: 2, kind: CentralStencil, want: Central.Stencil},
+		{derivative: 2, accuracy: 1, kind: ForwardStencil, want: Forward2nd.Stencil},
+		{derivative: 2, accuracy: 1, kind: BackwardStencil, want: Backward2nd.Stencil},
+		{derivative: 2, accuracy: 2, kind: CentralStencil, want: Central2nd.Stencil},
+		{
+			derivative: 1, accuracy: 2, kind: ForwardStencil,
+			want: []Point{{Loc: 0, Coeff: -1.5}, {Loc: 1, Coeff: 2}, {Loc: 2, Coeff: -0.5}},
+		},
+		{
+			derivative: 1, accuracy: 4, kind: CentralStencil,
+			want: []Point{{Loc: -2, Coeff: 1.0 / 12}, {Loc: -1, Coeff: -2.0 / 3}, {Loc: 1, Coeff: 2.0 / 3}, {Loc: 2, Coeff: -1.0 / 12}},
+		},
+		{
+			derivative: 3, accuracy: 2, kind: CentralStencil,
+			want: []Point{{Loc: -2, Coeff: -0.5}, {Loc: -1, Coeff: 1}, {Loc: 1, Coeff: -1}, {Loc: 2, Coeff: 0.5}},
+		},
+		{
+			derivative: 4, accuracy: 2, kind: CentralStencil,
+			want: []Point{{Loc: -2, Coeff: 1}, {Loc: -1, Coeff: -4}, {Loc: 0, Coeff: 6}, {Loc: 1, Coeff: -4}, {Loc: 2, Coeff: 1}},
+		},
+	} {
+		f := NewFormula(test.derivative, test.accuracy, test.kind)
+		if f.Derivative != test.derivative {
+			t.Errorf("unexpected derivative order: got:%d want:%d", f.Derivative, test.derivative)
+		}
+		if !sameStencil(f.Stencil, test.want, 1e-14) {
+			t.Errorf("unexpected stencil for derivative %d accuracy %d kind %d: got:%v want:%v",
+				test.derivative, test.accuracy, test.kind, f.Stencil, test.want)
+		}
+		if got := accuracyOrder(f.Stencil, test.derivative); got != test.accuracy {
+			t.Errorf("unexpected accuracy for derivative %d accuracy %d kind %d: got:%d", test.derivative, test.accuracy, test.kind, got)
+		}
+	}
+
+	// Derivatives of exp at 0 are all 1.
+	for d := 1; d <= 4; d++ {
+		for _, kind := range []StencilKind{CentralStencil, ForwardStencil, BackwardStencil} {
+			for _, accuracy := range []int{2, 4, 6} {
+				got := Derivative(math.Exp, 0, &Settings{Formula: NewFormula(d, accuracy, kind)})
+				tol := math.Pow(10, float64(d-7))
+				if math.Abs(got-1) > tol {
+					t.Errorf("unexpected derivative %d with accuracy %d kind %d: got:%v want:1", d, accuracy, kind, got)
+				}
+			}
+		}
+	}
+
+	for _, test := range []struct {
+		derivative, accuracy int
+		kind                 StencilKind
+	}{
+		{derivative: 0, accuracy: 2, kind: CentralStencil},
+		{derivative: 1, accuracy: 0, kind: ForwardStencil},
+		{derivative: 1, accuracy: 3, kind: CentralStencil},
+		{derivative: 1, accuracy: 2, kind: StencilKind(-1)},
+	} {
+		if !Panics(func() { NewFormula(test.derivative, test.accuracy, test.kind) }) {
+			t.Errorf("NewFormula did not panic for derivative %d accuracy %d kind %d", test.derivative, test.accuracy, test.kind)
+		}
+	}
+}
+
+func TestNewStencil(t *testing.T) {
+	// Irregularly spaced locations.
+	locs := []float64{-0.5, 0, 1, 3}
+	stencil := NewStencil(1, locs)
+	f := func(x float64) float64 { return x*x*x - 2*x*x + 5*x + 1 }
+	var got float64
+	for _, pt := range stencil {
+		got += pt.Coeff * f(pt.Loc)
+	}
+	if math.Abs(got-5) > 1e-12 {
+		t.Errorf("unexpected derivative with irregular stencil: got:%v want:5", got)
+	}
+	if !Panics(func() { NewStencil(1, []float64{0, 1, 1}) }) {
+		t.Errorf("NewStencil did not panic with repeated locations")
+	}
+	if !Panics(func() { NewStencil(2, []float64{0, 1}) }) {
+		t.Errorf("NewStencil did not panic with too few locations")
+	}
+}
@@ -4,8 +4,6 @@
 
 package fd
 
-import "gonum.org/v1/gonum/floats"
-
 // Gradient estimates the gradient of the multivariate function f at the
 // location x. If dst is not nil, the result will be stored in-place into dst
 // and returned, otherwise a new slice will be allocated first. Finite
@@ -13,8 +11,9 @@ import "gonum.org/v1/gonum/floats"
 // nil, the gradient will be estimated using the Forward formula and a default
 // step size.
 //
-// Gradient panics if the length of dst and x is not equal, or if the derivative
-// order of the formula is not 1.
+// Gradient panics if the length of dst and x is not equal, if settings.Bounds
+// or settings.Steps is not nil and its length is not equal to the length of x,
+// or if the derivative order of the formula is not 1.
 func Gradient(dst []float64, f func([]float64) float64, x []float64, settings *Settings) []float64 {
 	if dst == nil {
 		dst = make([]float64, len(x))
@@ -23,11 +22,18 @@ func Gradient(dst []float64, f func([]float64) float64, x []float64, settings *S
 		panic("fd: slice length mismatch")
 	}
 
+	if settings != nil && settings.Adaptive {
+		return GradientError(dst, nil, f, x, settings)
+	}
+
 	// Default settings.
 	formula := Forward
 	step := formula.Step
 	var originValue float64
-	var originKnown, concurrent bool
+	var originKnown, concurrent, relative bool
+	var workers int
+	var steps []float64
+	var bounds []Bound
 
 	// Use user settings if provided.
 	if settings != nil {
@@ -42,15 +48,33 @@ func Gradient(dst []float64, f func([]float64) float64, x []float64, settings *S
 		if settings.Step != 0 {
 			step = settings.Step
 		}
+		steps = settings.Steps
+		relative = settings.Relative
+		bounds = settings.Bounds
+		if bounds != nil && len(bounds) != len(x) {
+			panic("fd: bounds length mismatch")
+		}
 		originKnown = settings.OriginKnown
 		originValue = settings.OriginValue
 		concurrent = settings.Concurrent
+		workers = settings.Workers
+		f = settings.Cache.scalar(f)
 	}
 
-	evals := len(formula.Stencil) * len(x)
-	nWorkers := computeWorkers(concurrent, evals)
+	// Each variable may have its own step size and,
+	// near a bound, its own stencil.
+	stencils, stepOf := variableStencils(formula, step, steps, relative, bounds, x)
+
+	var (
+		evals     int
+		hasOrigin bool
+	)
+	for _, stencil := range stencils {
+		evals += len(stencil)
+		hasOrigin = hasOrigin || usesOrigin(stencil)
+	}
+	nWorkers := computeWorkers(concurrent, workers, evals)
 
-	hasOrigin := usesOrigin(formula.Stencil)
 	// Copy x in case it is modified during the call.
 	xcopy := make([]float64, len(x))
 	if hasOrigin && !originKnown {
@@ -61,7 +85,7 @@ func Gradient(dst []float64, f func([]float64) float64, x []float64, settings *S
 	if nWorkers == 1 {
 		for i := range xcopy {
 			var deriv float64
-			for _, pt := range formula.Stencil {
+			for _, pt := range stencils[i] {
 				if pt.Loc == 0 {
 					deriv += pt.Coeff * originValue
 					continue
@@ -72,10 +96,10 @@ func Gradient(dst []float64, f func([]float64) float64, x []float64, settings *S
 				// location. Secondly, it protects against the function
 				// modifying the input data.
 				copy(xcopy, x)
-				xcopy[i] += pt.Loc * step
+				xcopy[i] += pt.Loc * stepOf[i]
 				deriv += pt.Coeff * f(xcopy)
 			}
-			dst[i] = deriv / step
+			dst[i] = deriv / stepOf[i]
 		}
 		return dst
 	}
@@ -96,7 +120,7 @@ func Gradient(dst []float64, f func([]float64) float64, x []float64, settings *S
 				case run := <-sendChan:
 					// See above comment on the copy.
 					copy(xcopy, x)
-					xcopy[run.idx] += run.pt.Loc * step
+					xcopy[run.idx] += run.pt.Loc * stepOf[run.idx]
 					run.result = f(xcopy)
 					ansChan <- run
 				}
@@ -107,7 +131,7 @@ func Gradient(dst []float64, f func([]float64) float64, x []float64, settings *S
 	// Launch the distributor. Distributor sends the cases to be computed.
 	go func(sendChan chan<- fdrun, ansChan chan<- fdrun) {
 		for i := range x {
-			for _, pt := range formula.Stencil {
+			for _, pt := range stencils[i] {
 				if pt.Loc == 0 {
 					// Answer already known. Send the answer on the answer channel.
 					ansChan <- fdrun{
@@ -134,7 +158,9 @@ func Gradient(dst []float64, f func([]float64) float64, x []float64, settings *S
 		run := <-ansChan
 		dst[run.idx] += run.pt.Coeff * run.result
 	}
-	floats.Scale(1/step, dst)
+	for i, s := range stepOf {
+		dst[i] /= s
+	}
 	return dst
 }
 
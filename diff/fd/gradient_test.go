@@ -186,3 +186,113 @@ func TestGradientPanics(t *testing.T) {
 		t.Errorf("Gradient did not panic with length mismatch")
 	}
 }
+
+func TestGradientBounds(t *testing.T) {
+	// f(x) = \sum_i log(x_i) is only defined for positive x.
+	f := func(x []float64) float64 {
+		var sum float64
+		for _, v := range x {
+			if v < 0 {
+				t.Errorf("evaluation outside bounds: %v", x)
+			}
+			sum += math.Log(v)
+		}
+		return sum
+	}
+	x := []float64{1e-6, 1e-3, 1, 10}
+	bounds := make([]Bound, len(x))
+	want := make([]float64, len(x))
+	for i, v := range x {
+		bounds[i] = Bound{Min: 0, Max: math.Inf(1)}
+		want[i] = 1 / v
+	}
+	for _, concurrent := range []bool{false, true} {
+		settings := &Settings{
+			Formula:    Central,
+			Step:       1e-9,
+			Bounds:     bounds,
+			Concurrent: concurrent,
+		}
+		got := Gradient(nil, f, x, settings)
+		for i := range got {
+			if math.Abs(got[i]-want[i]) > 1e-3*want[i] {
+				t.Errorf("gradient mismatch with bounds for element %d: want:%v got:%v", i, want[i], got[i])
+			}
+		}
+	}
+
+	if !Panics(func() {
+		Gradient(nil, f, x, &Settings{Bounds: bounds[:1]})
+	}) {
+		t.Errorf("Gradient did not panic with bounds length mismatch")
+	}
+}
+
+// badlyScaled is a function of two variables whose natural
+// scales differ by eight orders of magnitude.
+func badlyScaled(x []float64) float64 {
+	return math.Sin(1e4*x[0]) + math.Sin(1e-4*x[1])
+}
+
+func badlyScaledGrad(x []float64) []float64 {
+	return []float64{1e4 * math.Cos(1e4*x[0]), 1e-4 * math.Cos(1e-4*x[1])}
+}
+
+func TestGradientSteps(t *testing.T) {
+	x := []float64{1e-4, 1e4}
+	want := badlyScaledGrad(x)
+	for _, test := range []struct {
+		name     string
+		settings Settings
+	}{
+		{
+			name:     "steps",
+			settings: Settings{Formula: Central, Steps: []float64{1e-9, 1e-1}},
+		},
+		{
+			name:     "steps with default",
+			settings: Settings{Formula: Central, Step: 1e-9, Steps: []float64{0, 1e-1}},
+		},
+		{
+			name:     "relative steps",
+			settings: Settings{Formula: Central, Steps: []float64{1e-9, 1e-5}, Relative: true},
+		},
+	} {
+		for _, concurrent := range []bool{false, true} {
+			settings := test.settings
+			settings.Concurrent = concurrent
+			got := Gradient(nil, badlyScaled, x, &settings)
+			for i := range got {
+				if math.Abs(got[i]-want[i]) > 1e-6*math.Abs(want[i]) {
+					t.Errorf("gradient mismatch for %s element %d: want:%v got:%v", test.name, i, want[i], got[i])
+				}
+			}
+		}
+	}
+
+	// The second variable is too large for the default
+	// absolute step.
+	f := func(x []float64) float64 { return x[0]*x[0] + math.Sin(1e-4*x[1]) }
+	x = []float64{0.5, 1e4}
+	want = []float64{1, 1e-4 * math.Cos(1)}
+	got := Gradient(nil, f, x, &Settings{Relative: true})
+	for i := range got {
+		if math.Abs(got[i]-want[i]) > 1e-6*math.Abs(want[i]) {
+			t.Errorf("gradient mismatch for relative default step element %d: want:%v got:%v", i, want[i], got[i])
+		}
+	}
+	if got := Derivative(func(x float64) float64 { return math.Sin(1e-4 * x) }, 1e4, &Settings{Relative: true}); math.Abs(got-want[1]) > 1e-6*want[1] {
+		t.Errorf("derivative mismatch for relative default step: want:%v got:%v", want[1], got)
+	}
+
+	if !Panics(func() {
+		Gradient(nil, f, x, &Settings{Steps: []float64{1e-6}})
+	}) {
+		t.Errorf("Gradient did not panic with steps length mismatch")
+	}
+	if !Panics(func() {
+		Gradient(nil, f, x, &Settings{Steps: []float64{1e-6, -1e-6}})
+	}) {
+		t.Errorf("Gradient did not panic with negative step")
+	}
+}
@@ -19,6 +19,10 @@ import (
 // formula and other options are specified by settings. If settings is nil,
 // the Hessian will be estimated using the Forward formula and a default step size.
 //
+// Each element of H is estimated by applying the formula along x_i and x_j.
+// Evaluation locations that are shared between elements, such as the origin
+// and locations displaced along a single coordinate, are evaluated only once.
+//
 // Hessian panics if the size of dst and x is not equal, or if the derivative
 // order of the formula is not 1.
 func Hessian(dst *mat.SymDense, f func(x []float64) float64, x []float64, settings *Settings) *mat.SymDense {
@@ -29,11 +33,6 @@ func Hessian(dst *mat.SymDense, f func(x []float64) float64, x []float64, settin
 		if n2 := dst.Symmetric(); n2 != n {
 			panic("hessian: dst size mismatch")
 		}
-		for i := 0; i < n; i++ {
-			for j := i; j < n; j++ {
-				dst.SetSym(i, j, 0)
-			}
-		}
 	}
 
 	// Default settings.
@@ -41,6 +40,7 @@ func Hessian(dst *mat.SymDense, f func(x []float64) float64, x []float64, settin
 	step := math.Sqrt(formula.Step) // Use the sqrt because taking derivatives of derivatives.
 	var originValue float64
 	var originKnown, concurrent bool
+	var workers int
 
 	// Use user settings if provided.
 	if settings != nil {
@@ -61,133 +61,145 @@ func Hessian(dst *mat.SymDense, f func(x []float64) float64, x []float64, settin
 		originKnown = settings.OriginKnown
 		originValue = settings.OriginValue
 		concurrent = settings.Concurrent
+		workers = settings.Workers
+		f = settings.Cache.scalar(f)
 	}
+	stencil := formula.Stencil
 
-	evals := n * (n + 1) / 2 * len(formula.Stencil) * len(formula.Stencil)
-	for _, pt := range formula.Stencil {
-		if pt.Loc == 0 {
-			evals -= n * (n + 1) / 2
-			break
-		}
-	}
-
-	nWorkers := computeWorkers(concurrent, evals)
+	locs, index := hessianLocs(n, stencil, originKnown)
+	vals := make([]float64, len(locs))
+	nWorkers := computeWorkers(concurrent, workers, len(locs))
 	if nWorkers == 1 {
-		hessianSerial(dst, f, x, formula.Stencil, step, originKnown, originValue)
-		return dst
+		hessianSerial(vals, f, x, locs, step)
+	} else {
+		hessianConcurrent(vals, nWorkers, f, x, locs, step)
 	}
-	hessianConcurrent(dst, nWorkers, evals, f, x, formula.Stencil, step, originKnown, originValue)
+	hessianAssemble(dst, stencil, index, vals, originKnown, originValue, step)
 	return dst
 }
 
-func hessianSerial(dst *mat.SymDense, f func(x []float64) float64, x []float64, stencil []Point, step float64, originKnown bool, originValue float64) {
-	n := len(x)
-	xCopy := make([]float64, n)
-	fo := func() float64 {
-		// Copy x in case it is modified during the call.
-		copy(xCopy, x)
-		return f(x)
+// hessianLocs returns the distinct evaluation locations needed to estimate
+// the Hessian of a function of n variables using stencil along each pair of
+// variables, and the index of each location in the returned slice. The origin
+// is not included if originKnown is true.
+func hessianLocs(n int, stencil []Point, originKnown bool) ([]hessPoint, map[hessPoint]int) {
+	index := make(map[hessPoint]int)
+	var locs []hessPoint
+	for i := 0; i < n; i++ {
+		for j := i; j < n; j++ {
+			for _, pti := range stencil {
+				for _, ptj := range stencil {
+					p := newHessPoint(i, j, pti.Loc, ptj.Loc)
+					if originKnown && p.isOrigin() {
+						continue
+					}
+					if _, ok := index[p]; !ok {
+						index[p] = len(locs)
+						locs = append(locs, p)
+					}
+				}
+			}
+		}
 	}
+	return locs, index
+}
+
+// hessianAssemble stores the Hessian estimated from the function values
+// at the locations returned by hessianLocs into dst.
+func hessianAssemble(dst *mat.SymDense, stencil []Point, index map[hessPoint]int, vals []float64, originKnown bool, originValue, step float64) {
+	n := dst.Symmetric()
 	is2 := 1 / (step * step)
-	origin := getOrigin(originKnown, originValue, fo, stencil)
 	for i := 0; i < n; i++ {
 		for j := i; j < n; j++ {
 			var hess float64
 			for _, pti := range stencil {
 				for _, ptj := range stencil {
-					var v float64
-					if pti.Loc == 0 && ptj.Loc == 0 {
-						v = origin
-					} else {
-						// Copying the data anew has two benefits. First, it
-						// avoids floating point issues where adding and then
-						// subtracting the step don't return to the exact same
-						// location. Secondly, it protects against the function
-						// modifying the input data.
-						copy(xCopy, x)
-						xCopy[i] += pti.Loc * step
-						xCopy[j] += ptj.Loc * step
-						v = f(xCopy)
+					p := newHessPoint(i, j, pti.Loc, ptj.Loc)
+					v := originValue
+					if !originKnown || !p.isOrigin() {
+						v = vals[index[p]]
 					}
-					hess += v * pti.Coeff * ptj.Coeff * is2
+					hess += v * pti.Coeff * ptj.Coeff
 				}
 			}
-			dst.SetSym(i, j, hess)
+			dst.SetSym(i, j, hess*is2)
 		}
 	}
 }
 
-func hessianConcurrent(dst *mat.SymDense, nWorkers, evals int, f func(x []float64) float64, x []float64, stencil []Point, step float64, originKnown bool, originValue float64) {
-	n := dst.Symmetric()
-	type run struct {
-		i, j       int
-		iIdx, jIdx int
-		result     float64
+// hessPoint is an evaluation location displaced from the origin by li steps
+// along x_i and lj steps along x_j. A negative index indicates no displacement.
+type hessPoint struct {
+	i, j   int
+	li, lj float64
+}
+
+// newHessPoint returns the canonical representation of the location displaced
+// by li steps along x_i and lj steps along x_j, where i ≤ j.
+func newHessPoint(i, j int, li, lj float64) hessPoint {
+	if i == j {
+		li += lj
+		lj = 0
 	}
+	switch {
+	case li == 0 && lj == 0:
+		return hessPoint{i: -1, j: -1}
+	case li == 0:
+		return hessPoint{i: j, j: -1, li: lj}
+	case lj == 0:
+		return hessPoint{i: i, j: -1, li: li}
+	}
+	return hessPoint{i: i, j: j, li: li, lj: lj}
+}
 
-	send := make(chan run, evals)
-	ans := make(chan run, evals)
+func (p hessPoint) isOrigin() bool { return p.i < 0 }
 
-	var originWG sync.WaitGroup
-	hasOrigin := usesOrigin(stencil)
-	if hasOrigin {
-		originWG.Add(1)
-		// Launch worker to compute the origin.
-		go func() {
-			defer originWG.Done()
-			xCopy := make([]float64, len(x))
-			copy(xCopy, x)
-			originValue = f(xCopy)
-		}()
+// evaluate returns f evaluated at p, using xCopy as scratch space.
+func (p hessPoint) evaluate(f func(x []float64) float64, x, xCopy []float64, step float64) float64 {
+	// Copying the data anew has two benefits. First, it
+	// avoids floating point issues where adding and then
+	// subtracting the step don't return to the exact same
+	// location. Secondly, it protects against the function
+	// modifying the input data.
+	p.displace(xCopy, x, step)
+	return f(xCopy)
+}
+
+// displace stores x displaced to p into dst.
+func (p hessPoint) displace(dst, x []float64, step float64) {
+	copy(dst, x)
+	if p.i >= 0 {
+		dst[p.i] += p.li * step
+	}
+	if p.j >= 0 {
+		dst[p.j] += p.lj * step
+	}
+}
+
+func hessianSerial(dst []float64, f func(x []float64) float64, x []float64, locs []hessPoint, step float64) {
+	xCopy := make([]float64, len(x))
+	for k, p := range locs {
+		dst[k] = p.evaluate(f, x, xCopy, step)
 	}
+}
 
-	var workerWG sync.WaitGroup
-	// Launch workers.
+func hessianConcurrent(dst []float64, nWorkers int, f func(x []float64) float64, x []float64, locs []hessPoint, step float64) {
+	var wg sync.WaitGroup
+	jobs := make(chan int, nWorkers)
 	for i := 0; i < nWorkers; i++ {
-		workerWG.Add(1)
-		go func(send <-chan run, ans chan<- run) {
-			defer workerWG.Done()
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
 			xCopy := make([]float64, len(x))
-			for r := range send {
-				if stencil[r.iIdx].Loc == 0 && stencil[r.jIdx].Loc == 0 {
-					originWG.Wait()
-					r.result = originValue
-				} else {
-					// See hessianSerial for comment on the copy.
-					copy(xCopy, x)
-					xCopy[r.i] += stencil[r.iIdx].Loc * step
-					xCopy[r.j] += stencil[r.jIdx].Loc * step
-					r.result = f(xCopy)
-				}
-				ans <- r
+			for k := range jobs {
+				// Each job writes to a distinct element of dst.
+				dst[k] = locs[k].evaluate(f, x, xCopy, step)
 			}
-		}(send, ans)
+		}()
 	}
-
-	// Launch the distributor, which sends all of runs.
-	go func(send chan<- run) {
-		for i := 0; i < n; i++ {
-			for j := i; j < n; j++ {
-				for iIdx := range stencil {
-					for jIdx := range stencil {
-						send <- run{
-							i: i, j: j, iIdx: iIdx, jIdx: jIdx,
-						}
-					}
-				}
-			}
-		}
-		close(send)
-		// Wait for all the workers to quit, then close the ans channel.
-		workerWG.Wait()
-		close(ans)
-	}(send)
-
-	is2 := 1 / (step * step)
-	// Read in the results.
-	for r := range ans {
-		v := r.result * stencil[r.iIdx].Coeff * stencil[r.jIdx].Coeff * is2
-		v += dst.At(r.i, r.j)
-		dst.SetSym(r.i, r.j, v)
+	for k := range locs {
+		jobs <- k
 	}
+	close(jobs)
+	wg.Wait()
 }
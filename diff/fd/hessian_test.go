@@ -5,6 +5,7 @@
 package fd
 
 import (
+	"sync"
 	"testing"
 
 	"gonum.org/v1/gonum/mat"
@@ -92,3 +93,43 @@ func TestHessian(t *testing.T) {
 		}
 	}
 }
+
+func TestHessianEvaluations(t *testing.T) {
+	h := Watson{}
+	for _, test := range []struct {
+		n        int
+		settings *Settings
+		want     int
+	}{
+		// Origin, two single coordinate displacements for
+		// each variable and one pair displacement for each
+		// off-diagonal element.
+		{n: 4, want: 1 + 2*4 + 4*3/2},
+		{n: 4, settings: &Settings{OriginKnown: true, OriginValue: h.Func(make([]float64, 4))}, want: 2*4 + 4*3/2},
+		// Origin, displacements of ±2 along each variable
+		// and four pair displacements for each off-diagonal
+		// element.
+		{n: 4, settings: &Settings{Formula: Central}, want: 1 + 2*4 + 4*4*3/2},
+	} {
+		for _, concurrent := range []bool{false, true} {
+			var settings Settings
+			if test.settings != nil {
+				settings = *test.settings
+			}
+			settings.Concurrent = concurrent
+
+			var mu sync.Mutex
+			var evals int
+			f := func(x []float64) float64 {
+				mu.Lock()
+				evals++
+				mu.Unlock()
+				return h.Func(x)
+			}
+			Hessian(nil, f, make([]float64, test.n), &settings)
+			if evals != test.want {
+				t.Errorf("unexpected number of evaluations for concurrent=%t: got:%d want:%d", concurrent, evals, test.want)
+			}
+		}
+	}
+}
@@ -16,6 +16,33 @@ type JacobianSettings struct {
 	OriginValue []float64
 	Step        float64
 	Concurrent  bool
+	Workers     int // Maximum number of concurrent calls, as described for Settings.
+
+	// Steps holds the step size for each element of x,
+	// overriding Step for each element with a non-zero
+	// value. If Steps is not nil, its length must equal
+	// the length of x.
+	Steps []float64
+	// Relative specifies that the step size for each
+	// element of x is scaled by max(|x_j|, 1).
+	Relative bool
+
+	// Pattern is the sparsity pattern of the Jacobian.
+	// If Pattern is not nil, it must have the same
+	// dimensions as the Jacobian, and its zero elements
+	// mark elements of the Jacobian that are known to
+	// be zero. Columns that do not share a non-zero row
+	// are then perturbed together, reducing the number
+	// of function evaluations.
+	Pattern mat.Matrix
+
+	// Bounds, Adaptive, Noise, BatchSize and Cache
+	// are used as described for Settings.
+	Bounds    []Bound
+	Adaptive  bool
+	Noise     float64
+	BatchSize int
+	Cache     *Cache
 }
 
 // Jacobian approximates the Jacobian matrix of a vector-valued function f at
@@ -36,9 +63,32 @@ type JacobianSettings struct {
 //      [     .          .  .     ]
 //      [ ∂f_m/∂x_1 ... ∂f_m/∂x_n ]
 //
-// dst must be non-nil, the number of its columns must equal the length of x, and
-// the derivative order of the formula must be 1, otherwise Jacobian will panic.
+// dst must be non-nil, the number of its columns must equal the length of x,
+// settings.Steps and settings.Bounds must be nil or have the same length as x,
+// settings.Pattern must be nil or have the same dimensions as dst, and the
+// derivative order of the formula must be 1, otherwise Jacobian will panic.
 func Jacobian(dst *mat.Dense, f func(y, x []float64), x []float64, settings *JacobianSettings) {
+	jacobian(dst, func(y, x []float64) error {
+		f(y, x)
+		return nil
+	}, x, settings, false)
+}
+
+// JacobianChecked approximates the Jacobian matrix of a vector-valued function
+// f at the location x as Jacobian does, but allows f to report a failed
+// evaluation. If f returns a non-nil error, or returns without error but with
+// a NaN element in y, no further evaluations are started and JacobianChecked
+// returns the error, or ErrNaN for a NaN value. The contents of dst are
+// undefined when a non-nil error is returned.
+//
+// JacobianChecked panics under the same conditions as Jacobian.
+func JacobianChecked(dst *mat.Dense, f func(y, x []float64) error, x []float64, settings *JacobianSettings) error {
+	return jacobian(dst, f, x, settings, true)
+}
+
+// jacobian implements Jacobian and JacobianChecked. If checkNaN is true, an
+// evaluation of f that returns a NaN value is treated as having returned ErrNaN.
+func jacobian(dst *mat.Dense, f func(y, x []float64) error, x []float64, settings *JacobianSettings, checkNaN bool) error {
 	n := len(x)
 	if n == 0 {
 		panic("jacobian: x has zero length")
@@ -48,11 +98,39 @@ func Jacobian(dst *mat.Dense, f func(y, x []float64), x []float64, settings *Jac
 		panic("jacobian: mismatched matrix size")
 	}
 
+	if settings != nil {
+		f = settings.Cache.vector(f)
+	}
+	if checkNaN {
+		f = failOnNaN(f)
+	}
+
+	if settings != nil && settings.Adaptive {
+		// The cache is already applied to f.
+		adaptive := *settings
+		adaptive.Cache = nil
+
+		// JacobianError cannot be stopped part way, so
+		// evaluations after a failure are skipped instead.
+		var fail firstError
+		JacobianError(dst, nil, func(y, x []float64) {
+			if fail.failed() {
+				return
+			}
+			fail.set(f(y, x))
+		}, x, &adaptive)
+		return fail.err
+	}
+
 	// Default settings.
 	formula := Forward
 	step := formula.Step
 	var originValue []float64
-	var concurrent bool
+	var concurrent, relative bool
+	var workers int
+	var steps []float64
+	var bounds []Bound
+	var pattern mat.Matrix
 
 	// Use user settings if provided.
 	if settings != nil {
@@ -67,59 +145,93 @@ func Jacobian(dst *mat.Dense, f func(y, x []float64), x []float64, settings *Jac
 		if settings.Step != 0 {
 			step = settings.Step
 		}
+		steps = settings.Steps
+		relative = settings.Relative
+		bounds = settings.Bounds
+		pattern = settings.Pattern
 		originValue = settings.OriginValue
 		if originValue != nil && len(originValue) != m {
 			panic("jacobian: mismatched OriginValue slice length")
 		}
 		concurrent = settings.Concurrent
+		workers = settings.Workers
 	}
 
-	evals := n * len(formula.Stencil)
-	for _, pt := range formula.Stencil {
-		if pt.Loc == 0 {
-			evals -= n - 1
-			break
+	if bounds != nil && len(bounds) != n {
+		panic("jacobian: mismatched bounds length")
+	}
+	// Each column may have its own step size and,
+	// near a bound, its own stencil.
+	stencils, stepOf := variableStencils(formula, step, steps, relative, bounds, x)
+
+	if pattern != nil {
+		if r, c := pattern.Dims(); r != m || c != n {
+			panic("jacobian: mismatched pattern size")
 		}
+		return jacobianSparse(dst, f, x, originValue, stencils, stepOf, pattern, concurrent, workers)
 	}
 
-	nWorkers := computeWorkers(concurrent, evals)
+	var (
+		evals     int
+		hasOrigin bool
+	)
+	for _, stencil := range stencils {
+		for _, pt := range stencil {
+			if pt.Loc == 0 {
+				hasOrigin = true
+				continue
+			}
+			evals++
+		}
+	}
+	if hasOrigin {
+		evals++
+	}
+
+	nWorkers := computeWorkers(concurrent, workers, evals)
 	if nWorkers == 1 {
-		jacobianSerial(dst, f, x, originValue, formula, step)
-		return
+		return jacobianSerial(dst, f, x, originValue, stencils, stepOf)
 	}
-	jacobianConcurrent(dst, f, x, originValue, formula, step, nWorkers)
+	return jacobianConcurrent(dst, f, x, originValue, stencils, stepOf, nWorkers)
 }
 
-func jacobianSerial(dst *mat.Dense, f func([]float64, []float64), x, origin []float64, formula Formula, step float64) {
+func jacobianSerial(dst *mat.Dense, f func([]float64, []float64) error, x, origin []float64, stencils [][]Point, step []float64) error {
 	m, n := dst.Dims()
-	xcopy := make([]float64, n)
-	y := make([]float64, m)
-	col := make([]float64, m)
+	xbuf, ybuf, colbuf := getFloats(n), getFloats(m), getFloats(m)
+	defer putFloats(xbuf)
+	defer putFloats(ybuf)
+	defer putFloats(colbuf)
+	xcopy, y, col := *xbuf, *ybuf, *colbuf
 	for j := 0; j < n; j++ {
 		for i := range col {
 			col[i] = 0
 		}
-		for _, pt := range formula.Stencil {
+		for _, pt := range stencils[j] {
 			if pt.Loc == 0 {
 				if origin == nil {
 					origin = make([]float64, m)
 					copy(xcopy, x)
-					f(origin, xcopy)
+					if err := f(origin, xcopy); err != nil {
+						return err
+					}
 				}
 				floats.AddScaled(col, pt.Coeff, origin)
 			} else {
 				copy(xcopy, x)
-				xcopy[j] += pt.Loc * step
-				f(y, xcopy)
+				xcopy[j] += pt.Loc * step[j]
+				if err := f(y, xcopy); err != nil {
+					return err
+				}
 				floats.AddScaled(col, pt.Coeff, y)
 			}
 		}
+		floats.Scale(1/step[j], col)
 		dst.SetCol(j, col)
 	}
-	dst.Scale(1/step, dst)
+	return nil
 }
 
-func jacobianConcurrent(dst *mat.Dense, f func([]float64, []float64), x, origin []float64, formula Formula, step float64, nWorkers int) {
+func jacobianConcurrent(dst *mat.Dense, f func([]float64, []float64) error, x, origin []float64, stencils [][]Point, step []float64, nWorkers int) error {
 	m, n := dst.Dims()
 	for i := 0; i < m; i++ {
 		for j := 0; j < n; j++ {
@@ -128,19 +240,28 @@ func jacobianConcurrent(dst *mat.Dense, f func([]float64, []float64), x, origin
 	}
 
 	var (
-		wg sync.WaitGroup
-		mu = make([]sync.Mutex, n) // Guard access to individual columns.
+		wg   sync.WaitGroup
+		mu   = make([]sync.Mutex, n) // Guard access to individual columns.
+		fail = newFirstError()
 	)
 	worker := func(jobs <-chan jacJob) {
 		defer wg.Done()
-		xcopy := make([]float64, n)
-		y := make([]float64, m)
+		xbuf, ybuf := getFloats(n), getFloats(m)
+		defer putFloats(xbuf)
+		defer putFloats(ybuf)
+		xcopy, y := *xbuf, *ybuf
 		yVec := mat.NewVecDense(m, y)
 		var col mat.VecDense
 		for job := range jobs {
+			if fail.failed() {
+				continue
+			}
 			copy(xcopy, x)
-			xcopy[job.j] += job.pt.Loc * step
-			f(y, xcopy)
+			xcopy[job.j] += job.pt.Loc * step[job.j]
+			if err := f(y, xcopy); err != nil {
+				fail.set(err)
+				continue
+			}
 			col.ColViewOf(dst, job.j)
 			mu[job.j].Lock()
 			col.AddScaledVec(&col, job.pt.Coeff, yVec)
@@ -153,16 +274,9 @@ func jacobianConcurrent(dst *mat.Dense, f func([]float64, []float64), x, origin
 		go worker(jobs)
 	}
 	var hasOrigin bool
-	for _, pt := range formula.Stencil {
-		if pt.Loc == 0 {
-			hasOrigin = true
-			continue
-		}
-		for j := 0; j < n; j++ {
-			jobs <- jacJob{j, pt}
-		}
+	for _, stencil := range stencils {
+		hasOrigin = hasOrigin || usesOrigin(stencil)
 	}
-	close(jobs)
 	if hasOrigin && origin == nil {
 		wg.Add(1)
 		go func() {
@@ -170,30 +284,49 @@ func jacobianConcurrent(dst *mat.Dense, f func([]float64, []float64), x, origin
 			origin = make([]float64, m)
 			xcopy := make([]float64, n)
 			copy(xcopy, x)
-			f(origin, xcopy)
+			fail.set(f(origin, xcopy))
 		}()
 	}
+send:
+	for j, stencil := range stencils {
+		for _, pt := range stencil {
+			if pt.Loc == 0 {
+				continue
+			}
+			select {
+			case jobs <- jacJob{j, pt}:
+			case <-fail.done:
+				break send
+			}
+		}
+	}
+	close(jobs)
 	wg.Wait()
+	if fail.err != nil {
+		return fail.err
+	}
 
+	var col mat.VecDense
 	if hasOrigin {
 		// The formula evaluated at x, we need to add scaled origin to
-		// all columns of dst. Iterate again over all Formula points
+		// the columns of dst. Iterate again over all stencil points
 		// because we don't forbid repeated locations.
-
 		originVec := mat.NewVecDense(m, origin)
-		for _, pt := range formula.Stencil {
-			if pt.Loc != 0 {
-				continue
-			}
-			var col mat.VecDense
-			for j := 0; j < n; j++ {
-				col.ColViewOf(dst, j)
-				col.AddScaledVec(&col, pt.Coeff, originVec)
+		for j, stencil := range stencils {
+			col.ColViewOf(dst, j)
+			for _, pt := range stencil {
+				if pt.Loc == 0 {
+					col.AddScaledVec(&col, pt.Coeff, originVec)
+				}
 			}
 		}
 	}
 
-	dst.Scale(1/step, dst)
+	for j := 0; j < n; j++ {
+		col.ColViewOf(dst, j)
+		col.ScaleVec(1/step[j], &col)
+	}
+	return nil
 }
 
 type jacJob struct {
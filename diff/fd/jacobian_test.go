@@ -5,7 +5,9 @@
 package fd
 
 import (
+	"errors"
 	"math"
+	"sync"
 	"testing"
 
 	"golang.org/x/exp/rand"
@@ -267,3 +269,348 @@ func fillNaNDense(m *mat.Dense) {
 		}
 	}
 }
+
+func TestJacobianSteps(t *testing.T) {
+	f := func(y, x []float64) {
+		y[0] = badlyScaled(x)
+		y[1] = x[0] * x[1]
+	}
+	x := []float64{1e-4, 1e4}
+	grad := badlyScaledGrad(x)
+	want := mat.NewDense(2, 2, []float64{
+		grad[0], grad[1],
+		x[1], x[0],
+	})
+	for _, test := range []struct {
+		name     string
+		settings JacobianSettings
+	}{
+		{
+			name:     "steps",
+			settings: JacobianSettings{Formula: Central, Steps: []float64{1e-9, 1e-1}},
+		},
+		{
+			name:     "relative steps",
+			settings: JacobianSettings{Formula: Central, Steps: []float64{1e-9, 1e-5}, Relative: true},
+		},
+		{
+			name:     "forward relative steps",
+			settings: JacobianSettings{Formula: Forward, Steps: []float64{1e-11, 1e-8}, Relative: true},
+		},
+	} {
+		for _, concurrent := range []bool{false, true} {
+			settings := test.settings
+			settings.Concurrent = concurrent
+			got := mat.NewDense(2, 2, nil)
+			Jacobian(got, f, x, &settings)
+			for i := 0; i < 2; i++ {
+				for j := 0; j < 2; j++ {
+					if math.Abs(got.At(i, j)-want.At(i, j)) > 1e-6*math.Abs(want.At(i, j)) {
+						t.Errorf("Jacobian mismatch for %s concurrent=%t element (%d,%d): want:%v got:%v",
+							test.name, concurrent, i, j, want.At(i, j), got.At(i, j))
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestJacobianPattern(t *testing.T) {
+	// f has a tridiagonal Jacobian.
+	const n = 10
+	var evals int
+	var mu sync.Mutex
+	f := func(y, x []float64) {
+		mu.Lock()
+		evals++
+		mu.Unlock()
+		for i := range y {
+			y[i] = 3 * x[i] * x[i]
+			if i > 0 {
+				y[i] += math.Sin(x[i-1])
+			}
+			if i < n-1 {
+				y[i] -= x[i] * x[i+1]
+			}
+		}
+	}
+	x := make([]float64, n)
+	for i := range x {
+		x[i] = float64(i) / 3
+	}
+	want := mat.NewDense(n, n, nil)
+	pattern := mat.NewDense(n, n, nil)
+	for i := 0; i < n; i++ {
+		d := 6 * x[i]
+		if i < n-1 {
+			d -= x[i+1]
+			want.Set(i, i+1, -x[i])
+			pattern.Set(i, i+1, 1)
+		}
+		if i > 0 {
+			want.Set(i, i-1, math.Cos(x[i-1]))
+			pattern.Set(i, i-1, 1)
+		}
+		want.Set(i, i, d)
+		pattern.Set(i, i, 1)
+	}
+
+	for _, test := range []struct {
+		formula Formula
+		evals   int
+		tol     float64
+	}{
+		{formula: Forward, evals: 3 + 1, tol: 1e-6},
+		{formula: Central, evals: 3 * 2, tol: 1e-8},
+	} {
+		for _, concurrent := range []bool{false, true} {
+			evals = 0
+			got := mat.NewDense(n, n, nil)
+			got.Set(0, n-1, 1) // Elements outside the pattern must be zeroed.
+			Jacobian(got, f, x, &JacobianSettings{
+				Formula:    test.formula,
+				Pattern:    pattern,
+				Concurrent: concurrent,
+			})
+			if evals != test.evals {
+				t.Errorf("unexpected number of evaluations for concurrent=%t: got:%d want:%d", concurrent, evals, test.evals)
+			}
+			if !mat.EqualApprox(got, want, test.tol) {
+				t.Errorf("Jacobian mismatch with pattern for concurrent=%t\ngot:\n%v\nwant:\n%v",
+					concurrent, mat.Formatted(got), mat.Formatted(want))
+			}
+		}
+	}
+
+	if !Panics(func() {
+		Jacobian(mat.NewDense(n, n, nil), f, x, &JacobianSettings{Pattern: mat.NewDense(n, n-1, nil)})
+	}) {
+		t.Errorf("Jacobian did not panic with pattern size mismatch")
+	}
+}
+
+func TestJacobianVec(t *testing.T) {
+	rand.Seed(1)
+	for cas, test := range []struct {
+		m, n    int
+		fn      func(y, x []float64)
+		jac     func(jac *mat.Dense, x []float64)
+		formula Formula
+		tol     float64
+	}{
+		{m: 1, n: 3, fn: vecFunc13, jac: vecFunc13Jac, formula: Forward, tol: 1e-6},
+		{m: 2, n: 2, fn: vecFunc22, jac: vecFunc22Jac, formula: Central, tol: 1e-8},
+		{m: 4, n: 3, fn: vecFunc43, jac: vecFunc43Jac, formula: Central, tol: 1e-8},
+		{m: 4, n: 3, fn: vecFunc43, jac: vecFunc43Jac, formula: Backward, tol: 1e-6},
+	} {
+		x := randomSlice(test.n, 1)
+		v := randomSlice(test.n, 1)
+		u := randomSlice(test.m, 1)
+		jac := mat.NewDense(test.m, test.n, nil)
+		test.jac(jac, x)
+		var want, wantT mat.VecDense
+		want.MulVec(jac, mat.NewVecDense(test.n, v))
+		wantT.MulVec(jac.T(), mat.NewVecDense(test.m, u))
+
+		for _, concurrent := range []bool{false, true} {
+			settings := &JacobianSettings{Formula: test.formula, Concurrent: concurrent}
+			got := make([]float64, test.m)
+			JacobianVec(got, test.fn, x, v, settings)
+			if !floats.EqualApprox(got, want.RawVector().Data, test.tol) {
+				t.Errorf("Case %d: unexpected J·v for concurrent=%t: got:%v want:%v", cas, concurrent, got, want.RawVector().Data)
+			}
+			gotT := make([]float64, test.n)
+			JacobianTransVec(gotT, test.fn, x, u, settings)
+			if !floats.EqualApprox(gotT, wantT.RawVector().Data, test.tol) {
+				t.Errorf("Case %d: unexpected Jᵀ·u for concurrent=%t: got:%v want:%v", cas, concurrent, gotT, wantT.RawVector().Data)
+			}
+		}
+	}
+
+	// A zero direction gives a zero product without evaluating f.
+	got := []float64{1}
+	JacobianVec(got, func(y, x []float64) { t.Error("unexpected evaluation") }, []float64{1, 2}, []float64{0, 0}, nil)
+	if got[0] != 0 {
+		t.Errorf("unexpected J·v for zero v: got:%v want:0", got[0])
+	}
+}
+
+func TestJacobianBounds(t *testing.T) {
+	// f is only defined for positive x.
+	const n = 4
+	f := func(y, x []float64) {
+		for _, v := range x {
+			if v < 0 {
+				t.Errorf("evaluation outside bounds: %v", x)
+			}
+		}
+		for i := range y {
+			y[i] = math.Sqrt(x[i])
+			if i > 0 {
+				y[i] += math.Log1p(x[i-1])
+			}
+		}
+	}
+	x := []float64{0, 1e-10, 0.5, 3}
+	bounds := make([]Bound, n)
+	want := mat.NewDense(n, n, nil)
+	pattern := mat.NewDense(n, n, nil)
+	for i := range bounds {
+		bounds[i] = Bound{Min: 0, Max: math.Inf(1)}
+	}
+	for i := 0; i < n; i++ {
+		want.Set(i, i, 0.5/math.Sqrt(x[i]))
+		pattern.Set(i, i, 1)
+		if i > 0 {
+			want.Set(i, i-1, 1/(1+x[i-1]))
+			pattern.Set(i, i-1, 1)
+		}
+	}
+
+	for _, test := range []struct {
+		name     string
+		settings JacobianSettings
+	}{
+		{name: "dense", settings: JacobianSettings{Formula: Central, Bounds: bounds}},
+		{name: "sparse", settings: JacobianSettings{Formula: Central, Bounds: bounds, Pattern: pattern}},
+		{name: "adaptive", settings: JacobianSettings{Formula: Central, Bounds: bounds, Adaptive: true}},
+	} {
+		for _, concurrent := range []bool{false, true} {
+			settings := test.settings
+			settings.Concurrent = concurrent
+			got := mat.NewDense(n, n, nil)
+			Jacobian(got, f, x, &settings)
+			// The derivative of the square root is infinite at zero,
+			// so only check the columns away from zero.
+			for i := 0; i < n; i++ {
+				for j := 2; j < n; j++ {
+					if math.Abs(got.At(i, j)-want.At(i, j)) > 1e-6*math.Max(1, math.Abs(want.At(i, j))) {
+						t.Errorf("Jacobian mismatch for %s concurrent=%t element (%d,%d): want:%v got:%v",
+							test.name, concurrent, i, j, want.At(i, j), got.At(i, j))
+					}
+				}
+			}
+		}
+	}
+
+	if !Panics(func() {
+		Jacobian(mat.NewDense(n, n, nil), f, x, &JacobianSettings{Bounds: bounds[:1]})
+	}) {
+		t.Errorf("Jacobian did not panic with bounds length mismatch")
+	}
+}
+
+func TestJacobianWorkers(t *testing.T) {
+	const n = 20
+	for _, workers := range []int{1, 2, 3} {
+		for _, pattern := range []mat.Matrix{nil, eye(n)} {
+			var mu sync.Mutex
+			var active, maxActive int
+			f := func(y, x []float64) {
+				mu.Lock()
+				active++
+				if active > maxActive {
+					maxActive = active
+				}
+				mu.Unlock()
+				for i := range y {
+					y[i] = x[i] * x[i]
+				}
+				mu.Lock()
+				active--
+				mu.Unlock()
+			}
+			x := randomSlice(n, 10)
+			settings := &JacobianSettings{Formula: Central, Concurrent: true, Workers: workers, Pattern: pattern}
+			Jacobian(mat.NewDense(n, n, nil), f, x, settings)
+			if maxActive > workers {
+				t.Errorf("too many concurrent evaluations for workers=%d pattern=%t: got:%d", workers, pattern != nil, maxActive)
+			}
+		}
+	}
+
+	if !Panics(func() {
+		Jacobian(mat.NewDense(2, 2, nil), vecFunc22, []float64{1, 2}, &JacobianSettings{Concurrent: true, Workers: -1})
+	}) {
+		t.Errorf("Jacobian did not panic with negative workers")
+	}
+}
+
+func eye(n int) *mat.Dense {
+	m := mat.NewDense(n, n, nil)
+	for i := 0; i < n; i++ {
+		m.Set(i, i, 1)
+	}
+	return m
+}
+
+func TestJacobianChecked(t *testing.T) {
+	const n = 20
+	errFail := errors.New("evaluation failed")
+	x := randomSlice(n, 10)
+	for _, test := range []struct {
+		name     string
+		settings JacobianSettings
+	}{
+		{name: "dense", settings: JacobianSettings{Formula: Central}},
+		{name: "origin", settings: JacobianSettings{Formula: Forward}},
+		{name: "sparse", settings: JacobianSettings{Formula: Central, Pattern: eye(n)}},
+		{name: "adaptive", settings: JacobianSettings{Formula: Central, Adaptive: true}},
+	} {
+		for _, concurrent := range []bool{false, true} {
+			settings := test.settings
+			settings.Concurrent = concurrent
+			settings.Workers = 2
+
+			want := mat.NewDense(n, n, nil)
+			Jacobian(want, func(y, x []float64) {
+				for i := range y {
+					y[i] = x[i] * x[i]
+				}
+			}, x, &settings)
+
+			for _, fail := range []error{nil, errFail, ErrNaN} {
+				var mu sync.Mutex
+				var evals, after int
+				f := func(y, x []float64) error {
+					mu.Lock()
+					evals++
+					failed := evals > 1
+					if failed {
+						after++
+					}
+					mu.Unlock()
+					for i := range y {
+						y[i] = x[i] * x[i]
+					}
+					if fail == nil || !failed {
+						return nil
+					}
+					if fail == ErrNaN {
+						y[0] = math.NaN()
+						return nil
+					}
+					return fail
+				}
+				got := mat.NewDense(n, n, nil)
+				err := JacobianChecked(got, f, x, &settings)
+				if err != fail {
+					t.Errorf("unexpected error for %s concurrent=%t: got:%v want:%v", test.name, concurrent, err, fail)
+				}
+				if fail == nil {
+					if !mat.Equal(got, want) {
+						t.Errorf("unexpected Jacobian for %s concurrent=%t", test.name, concurrent)
+					}
+					continue
+				}
+				// Evaluations already started when the first failure
+				// is recorded may complete, one per worker and one
+				// for the origin.
+				if after > settings.Workers+1 {
+					t.Errorf("evaluation not aborted for %s concurrent=%t: %d evaluations after failure",
+						test.name, concurrent, after)
+				}
+			}
+		}
+	}
+}
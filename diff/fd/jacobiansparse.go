@@ -0,0 +1,224 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fd
+
+import (
+	"sync"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// jacobianSparse approximates the Jacobian of f with the sparsity pattern
+// given by pattern, using stencils[j] and step[j] for column j. Columns of
+// the Jacobian that have no non-zero row in common and the same stencil are
+// grouped using the Curtis-Powell-Reid method and the variables of each group
+// are perturbed simultaneously, so the number of evaluations depends on the
+// number of groups rather than the number of variables.
+func jacobianSparse(dst *mat.Dense, f func([]float64, []float64) error, x, origin []float64, stencils [][]Point, step []float64, pattern mat.Matrix, concurrent bool, workers int) error {
+	m, n := dst.Dims()
+	groups := columnGroups(pattern, stencils)
+
+	// rowCol[g][i] holds the column in group g
+	// with a non-zero element in row i, or -1
+	// if there is no such column.
+	rowCol := make([][]int, len(groups))
+	for g, cols := range groups {
+		rc := make([]int, m)
+		for i := range rc {
+			rc[i] = -1
+		}
+		for _, j := range cols {
+			for i := 0; i < m; i++ {
+				if pattern.At(i, j) != 0 {
+					rc[i] = j
+				}
+			}
+		}
+		rowCol[g] = rc
+	}
+
+	for i := 0; i < m; i++ {
+		for j := 0; j < n; j++ {
+			dst.Set(i, j, 0)
+		}
+	}
+	accumulate := func(g int, coeff float64, y []float64) {
+		for i, j := range rowCol[g] {
+			if j >= 0 {
+				dst.Set(i, j, dst.At(i, j)+coeff*y[i])
+			}
+		}
+	}
+	// perturb evaluates f with the variables in group g
+	// displaced by loc steps.
+	perturb := func(y, xcopy []float64, g int, loc float64) error {
+		copy(xcopy, x)
+		for _, j := range groups[g] {
+			xcopy[j] += loc * step[j]
+		}
+		return f(y, xcopy)
+	}
+
+	// All columns in a group share a stencil.
+	stencilOf := func(g int) []Point { return stencils[groups[g][0]] }
+
+	var hasOrigin bool
+	var evals int
+	for g := range groups {
+		for _, pt := range stencilOf(g) {
+			if pt.Loc == 0 {
+				hasOrigin = true
+				continue
+			}
+			evals++
+		}
+	}
+	if hasOrigin {
+		if origin == nil {
+			origin = make([]float64, m)
+			xcopy := make([]float64, n)
+			copy(xcopy, x)
+			if err := f(origin, xcopy); err != nil {
+				return err
+			}
+		}
+		for g := range groups {
+			for _, pt := range stencilOf(g) {
+				if pt.Loc == 0 {
+					accumulate(g, pt.Coeff, origin)
+				}
+			}
+		}
+	}
+
+	nWorkers := computeWorkers(concurrent, workers, evals)
+	if nWorkers <= 1 {
+		xbuf, ybuf := getFloats(n), getFloats(m)
+		defer putFloats(xbuf)
+		defer putFloats(ybuf)
+		xcopy, y := *xbuf, *ybuf
+		for g := range groups {
+			for _, pt := range stencilOf(g) {
+				if pt.Loc == 0 {
+					continue
+				}
+				if err := perturb(y, xcopy, g, pt.Loc); err != nil {
+					return err
+				}
+				accumulate(g, pt.Coeff, y)
+			}
+		}
+	} else {
+		var (
+			wg   sync.WaitGroup
+			mu   = make([]sync.Mutex, len(groups)) // Guard access to the columns of each group.
+			fail = newFirstError()
+		)
+		type groupJob struct {
+			g  int
+			pt Point
+		}
+		worker := func(jobs <-chan groupJob) {
+			defer wg.Done()
+			xbuf, ybuf := getFloats(n), getFloats(m)
+			defer putFloats(xbuf)
+			defer putFloats(ybuf)
+			xcopy, y := *xbuf, *ybuf
+			for job := range jobs {
+				if fail.failed() {
+					continue
+				}
+				if err := perturb(y, xcopy, job.g, job.pt.Loc); err != nil {
+					fail.set(err)
+					continue
+				}
+				mu[job.g].Lock()
+				accumulate(job.g, job.pt.Coeff, y)
+				mu[job.g].Unlock()
+			}
+		}
+		jobs := make(chan groupJob, nWorkers)
+		for i := 0; i < nWorkers; i++ {
+			wg.Add(1)
+			go worker(jobs)
+		}
+	send:
+		for g := range groups {
+			for _, pt := range stencilOf(g) {
+				if pt.Loc == 0 {
+					continue
+				}
+				select {
+				case jobs <- groupJob{g, pt}:
+				case <-fail.done:
+					break send
+				}
+			}
+		}
+		close(jobs)
+		wg.Wait()
+		if fail.err != nil {
+			return fail.err
+		}
+	}
+
+	var col mat.VecDense
+	for j := 0; j < n; j++ {
+		col.ColViewOf(dst, j)
+		col.ScaleVec(1/step[j], &col)
+	}
+	return nil
+}
+
+// columnGroups returns a partition of the columns of pattern such that no
+// two columns in a group have a non-zero element in the same row, and all
+// columns in a group have equal stencils. Columns are assigned in order to
+// the first group they fit.
+func columnGroups(pattern mat.Matrix, stencils [][]Point) [][]int {
+	m, n := pattern.Dims()
+	var (
+		groups [][]int
+		used   [][]bool // used[g][i] is true if a column in group g is non-zero in row i.
+	)
+	for j := 0; j < n; j++ {
+		g := 0
+	search:
+		for ; g < len(groups); g++ {
+			if !equalStencils(stencils[groups[g][0]], stencils[j]) {
+				continue
+			}
+			for i := 0; i < m; i++ {
+				if used[g][i] && pattern.At(i, j) != 0 {
+					continue search
+				}
+			}
+			break
+		}
+		if g == len(groups) {
+			groups = append(groups, nil)
+			used = append(used, make([]bool, m))
+		}
+		groups[g] = append(groups[g], j)
+		for i := 0; i < m; i++ {
+			if pattern.At(i, j) != 0 {
+				used[g][i] = true
+			}
+		}
+	}
+	return groups
+}
+
+// equalStencils returns whether a and b hold the same points in the same order.
+func equalStencils(a, b []Point) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i, pt := range a {
+		if pt != b[i] {
+			return false
+		}
+	}
+	return true
+}
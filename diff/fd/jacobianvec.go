@@ -0,0 +1,179 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fd
+
+import (
+	"math"
+	"sync"
+
+	"gonum.org/v1/gonum/floats"
+)
+
+// JacobianVec approximates the product of the Jacobian matrix of a
+// vector-valued function f at the location x with the vector v
+//  J·v = \sum_j ∂f/∂x_j v_j,
+// and stores the result in-place into dst, without forming J. The product
+// is estimated as the directional derivative of f along v, so each point
+// of the formula stencil requires a single evaluation of f.
+//
+// Finite difference formula and other options are specified by settings. If
+// settings is nil, the product will be estimated using the Forward formula
+// and a default step size. The step is taken along the unit vector in the
+// direction of v and, if settings.Relative is true, is scaled by max(‖x‖, 1).
+// settings.Steps, settings.Bounds and settings.Pattern are not used.
+//
+// JacobianVec panics if the lengths of x and v are not equal, if
+// settings.OriginValue is not nil and its length is not equal to the length
+// of dst, or if the derivative order of the formula is not 1.
+func JacobianVec(dst []float64, f func(y, x []float64), x, v []float64, settings *JacobianSettings) {
+	n := len(x)
+	if len(v) != n {
+		panic("jacobian: mismatched vector length")
+	}
+	m := len(dst)
+
+	// Default settings.
+	formula := Forward
+	step := formula.Step
+	var originValue []float64
+	var concurrent, relative bool
+	var workers int
+
+	// Use user settings if provided.
+	if settings != nil {
+		if !settings.Formula.isZero() {
+			formula = settings.Formula
+			step = formula.Step
+			checkFormula(formula)
+			if formula.Derivative != 1 {
+				panic(badDerivOrder)
+			}
+		}
+		if settings.Step != 0 {
+			if settings.Step < 0 {
+				panic(negativeStep)
+			}
+			step = settings.Step
+		}
+		relative = settings.Relative
+		originValue = settings.OriginValue
+		if originValue != nil && len(originValue) != m {
+			panic("jacobian: mismatched OriginValue slice length")
+		}
+		concurrent = settings.Concurrent
+		workers = settings.Workers
+	}
+
+	for i := range dst {
+		dst[i] = 0
+	}
+	norm := floats.Norm(v, 2)
+	if norm == 0 {
+		return
+	}
+	step /= norm
+	if relative {
+		step *= math.Max(floats.Norm(x, 2), 1)
+	}
+
+	// eval evaluates f into y at the location displaced
+	// from x by loc steps along v.
+	eval := func(y []float64, loc float64) {
+		xcopy := make([]float64, n)
+		for j := range xcopy {
+			xcopy[j] = x[j] + loc*step*v[j]
+		}
+		f(y, xcopy)
+	}
+
+	if usesOrigin(formula.Stencil) && originValue == nil {
+		originValue = make([]float64, m)
+		eval(originValue, 0)
+	}
+
+	if computeWorkers(concurrent, workers, len(formula.Stencil)) == 1 {
+		y := make([]float64, m)
+		for _, pt := range formula.Stencil {
+			if pt.Loc == 0 {
+				floats.AddScaled(dst, pt.Coeff, originValue)
+				continue
+			}
+			eval(y, pt.Loc)
+			floats.AddScaled(dst, pt.Coeff, y)
+		}
+		floats.Scale(1/step, dst)
+		return
+	}
+
+	var (
+		wg sync.WaitGroup
+		mu sync.Mutex
+	)
+	for _, pt := range formula.Stencil {
+		if pt.Loc == 0 {
+			mu.Lock()
+			floats.AddScaled(dst, pt.Coeff, originValue)
+			mu.Unlock()
+			continue
+		}
+		wg.Add(1)
+		go func(pt Point) {
+			defer wg.Done()
+			y := make([]float64, m)
+			eval(y, pt.Loc)
+			mu.Lock()
+			defer mu.Unlock()
+			floats.AddScaled(dst, pt.Coeff, y)
+		}(pt)
+	}
+	wg.Wait()
+	floats.Scale(1/step, dst)
+}
+
+// JacobianTransVec approximates the product of the transpose of the Jacobian
+// matrix of a vector-valued function f at the location x with the vector u
+//  Jᵀ·u = ∇ (u · f(x)),
+// and stores the result in-place into dst, without forming J. The product is
+// estimated as the gradient of the scalar function u · f(x), so it requires as
+// many evaluations of f as Jacobian, but only O(m + n) storage.
+//
+// Finite difference formula and other options are specified by settings as
+// for Jacobian. If settings is nil, the product will be estimated using the
+// Forward formula and a default step size. settings.Pattern is not used.
+//
+// JacobianTransVec panics if the lengths of dst and x are not equal, if
+// settings.OriginValue is not nil and its length is not equal to the length
+// of u, or if the derivative order of the formula is not 1.
+func JacobianTransVec(dst []float64, f func(y, x []float64), x, u []float64, settings *JacobianSettings) {
+	if len(dst) != len(x) {
+		panic("jacobian: mismatched vector length")
+	}
+	m := len(u)
+
+	var s Settings
+	if settings != nil {
+		s = Settings{
+			Formula:    settings.Formula,
+			Step:       settings.Step,
+			Steps:      settings.Steps,
+			Relative:   settings.Relative,
+			Bounds:     settings.Bounds,
+			Concurrent: settings.Concurrent,
+		}
+		if settings.OriginValue != nil {
+			if len(settings.OriginValue) != m {
+				panic("jacobian: mismatched OriginValue slice length")
+			}
+			s.OriginKnown = true
+			s.OriginValue = floats.Dot(u, settings.OriginValue)
+		}
+	}
+	g := func(x []float64) float64 {
+		y := make([]float64, m)
+		f(y, x)
+		return floats.Dot(u, y)
+	}
+	Gradient(dst, g, x, &s)
+}
@@ -5,6 +5,7 @@
 package fd
 
 import (
+	"sync"
 	"testing"
 
 	"gonum.org/v1/gonum/floats"
@@ -42,3 +43,39 @@ func TestLaplacian(t *testing.T) {
 		}
 	}
 }
+
+func TestLaplacianEvaluations(t *testing.T) {
+	h := Watson{}
+	x := []float64{0.2, 0.3, 0.1, 0.4}
+	var want float64
+	for _, test := range []struct {
+		settings Settings
+		evals    int
+	}{
+		{settings: Settings{}, evals: 1 + 2*len(x)},
+		{settings: Settings{Concurrent: true}, evals: 1 + 2*len(x)},
+		{settings: Settings{OriginKnown: true, OriginValue: h.Func(x)}, evals: 2 * len(x)},
+		{settings: Settings{OriginKnown: true, OriginValue: h.Func(x), Concurrent: true}, evals: 2 * len(x)},
+		{settings: Settings{Formula: Forward2nd}, evals: 1 + 2*len(x)},
+	} {
+		var mu sync.Mutex
+		var evals int
+		f := func(x []float64) float64 {
+			mu.Lock()
+			evals++
+			mu.Unlock()
+			return h.Func(x)
+		}
+		got := Laplacian(f, x, &test.settings)
+		if evals != test.evals {
+			t.Errorf("unexpected number of evaluations for %+v: got:%d want:%d", test.settings, evals, test.evals)
+		}
+		if test.settings.Formula.isZero() {
+			if want == 0 {
+				want = got
+			} else if got != want {
+				t.Errorf("unexpected Laplacian for %+v: got:%v want:%v", test.settings, got, want)
+			}
+		}
+	}
+}
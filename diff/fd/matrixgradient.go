@@ -0,0 +1,105 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fd
+
+import "gonum.org/v1/gonum/mat"
+
+// MatrixGradient estimates the gradient of the function f of a matrix
+// argument at the location a. That is
+//  G_{i,j} = ∂f(A)/∂A_{i,j}
+// If dst is not nil, the result will be stored in-place into dst and returned,
+// otherwise a new matrix will be allocated first. Finite difference formula
+// and other options are specified by settings, and are interpreted as for
+// Gradient with each element of a treated as an independent variable. If
+// settings is nil, the gradient will be estimated using the Forward formula
+// and a default step size.
+//
+// The matrix passed to f must not be retained. If settings.Concurrent is
+// true, f may be called concurrently.
+//
+// MatrixGradient panics if the dimensions of dst and a are not equal, or if
+// the derivative order of the formula is not 1.
+func MatrixGradient(dst *mat.Dense, f func(*mat.Dense) float64, a mat.Matrix, settings *Settings) *mat.Dense {
+	r, c := a.Dims()
+	if dst == nil {
+		dst = mat.NewDense(r, c, nil)
+	} else if rd, cd := dst.Dims(); rd != r || cd != c {
+		panic("fd: matrix size mismatch")
+	}
+
+	x := make([]float64, r*c)
+	for i := 0; i < r; i++ {
+		for j := 0; j < c; j++ {
+			x[i*c+j] = a.At(i, j)
+		}
+	}
+	grad := Gradient(nil, func(x []float64) float64 {
+		return f(mat.NewDense(r, c, x))
+	}, x, settings)
+	for i := 0; i < r; i++ {
+		for j := 0; j < c; j++ {
+			dst.Set(i, j, grad[i*c+j])
+		}
+	}
+	return dst
+}
+
+// SymGradient estimates the gradient of the function f of a symmetric matrix
+// argument at the location a. Only the upper triangle of a is perturbed, and
+// each perturbation of an off-diagonal element is reflected to the lower
+// triangle so that f is only evaluated at symmetric matrices. The result is
+// the gradient with respect to the independent elements of a,
+//  G_{i,j} = ∂f(A)/∂A_{i,j}                  if i == j,
+//  G_{i,j} = ∂f(A)/∂A_{i,j} + ∂f(A)/∂A_{j,i} otherwise,
+// where the partial derivatives on the right are those of f extended to
+// general matrices.
+//
+// If dst is not nil, the result will be stored in-place into dst and returned,
+// otherwise a new matrix will be allocated first. Finite difference formula
+// and other options are specified by settings, and are interpreted as for
+// Gradient with each element of the upper triangle of a treated as an
+// independent variable. If settings is nil, the gradient will be estimated
+// using the Forward formula and a default step size.
+//
+// The matrix passed to f must not be retained. If settings.Concurrent is
+// true, f may be called concurrently.
+//
+// SymGradient panics if the sizes of dst and a are not equal, or if the
+// derivative order of the formula is not 1.
+func SymGradient(dst *mat.SymDense, f func(*mat.SymDense) float64, a mat.Symmetric, settings *Settings) *mat.SymDense {
+	n := a.Symmetric()
+	if dst == nil {
+		dst = mat.NewSymDense(n, nil)
+	} else if dst.Symmetric() != n {
+		panic("fd: matrix size mismatch")
+	}
+
+	// x holds the upper triangle of a in row-major order.
+	x := make([]float64, 0, n*(n+1)/2)
+	for i := 0; i < n; i++ {
+		for j := i; j < n; j++ {
+			x = append(x, a.At(i, j))
+		}
+	}
+	grad := Gradient(nil, func(x []float64) float64 {
+		s := mat.NewSymDense(n, nil)
+		var k int
+		for i := 0; i < n; i++ {
+			for j := i; j < n; j++ {
+				s.SetSym(i, j, x[k])
+				k++
+			}
+		}
+		return f(s)
+	}, x, settings)
+	var k int
+	for i := 0; i < n; i++ {
+		for j := i; j < n; j++ {
+			dst.SetSym(i, j, grad[k])
+			k++
+		}
+	}
+	return dst
+}
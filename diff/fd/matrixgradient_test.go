@@ -0,0 +1,132 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fd
+
+import (
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestMatrixGradient(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for cas, test := range []struct {
+		r, c     int
+		settings *Settings
+		tol      float64
+	}{
+		{r: 1, c: 1, tol: 1e-6},
+		{r: 3, c: 2, tol: 1e-6},
+		{r: 4, c: 5, settings: &Settings{Formula: Central}, tol: 1e-8},
+		{r: 4, c: 5, settings: &Settings{Formula: Central, Concurrent: true}, tol: 1e-8},
+	} {
+		b := mat.NewDense(test.r, test.c, nil)
+		a := mat.NewDense(test.r, test.c, nil)
+		for i := 0; i < test.r; i++ {
+			for j := 0; j < test.c; j++ {
+				b.Set(i, j, rnd.NormFloat64())
+				a.Set(i, j, rnd.NormFloat64())
+			}
+		}
+
+		// f(A) = tr(Bᵀ A) + ‖A‖_F² / 2 has gradient B + A.
+		f := func(x *mat.Dense) float64 {
+			var v float64
+			for i := 0; i < test.r; i++ {
+				for j := 0; j < test.c; j++ {
+					v += b.At(i, j)*x.At(i, j) + x.At(i, j)*x.At(i, j)/2
+				}
+			}
+			return v
+		}
+		var want mat.Dense
+		want.Add(b, a)
+
+		got := MatrixGradient(nil, f, a, test.settings)
+		if !mat.EqualApprox(got, &want, test.tol) {
+			t.Errorf("Case %d: unexpected gradient:\ngot: %v\nwant:%v", cas, mat.Formatted(got), mat.Formatted(&want))
+		}
+
+		dst := mat.NewDense(test.r, test.c, nil)
+		MatrixGradient(dst, f, a, test.settings)
+		if !mat.Equal(dst, got) {
+			t.Errorf("Case %d: unexpected in-place gradient", cas)
+		}
+	}
+}
+
+func TestSymGradient(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for cas, test := range []struct {
+		n        int
+		settings *Settings
+		tol      float64
+	}{
+		{n: 1, tol: 1e-6},
+		{n: 3, tol: 1e-5},
+		{n: 5, settings: &Settings{Formula: Central}, tol: 1e-7},
+		{n: 5, settings: &Settings{Formula: Central, Concurrent: true}, tol: 1e-7},
+	} {
+		n := test.n
+
+		// Construct a well conditioned symmetric positive definite matrix.
+		a := mat.NewSymDense(n, nil)
+		for i := 0; i < n; i++ {
+			a.SetSym(i, i, float64(n)+rnd.Float64())
+			for j := i + 1; j < n; j++ {
+				a.SetSym(i, j, rnd.Float64()-0.5)
+			}
+		}
+
+		// f(X) = log(det(X)) has unconstrained gradient X⁻¹, so the
+		// gradient with respect to the upper triangle is 2X⁻¹ - diag(X⁻¹).
+		f := func(x *mat.SymDense) float64 {
+			if !mat.Equal(x, x.T()) {
+				t.Errorf("Case %d: f called with asymmetric matrix", cas)
+			}
+			var chol mat.Cholesky
+			if !chol.Factorize(x) {
+				panic("unexpected non-positive definite matrix")
+			}
+			return chol.LogDet()
+		}
+		var chol mat.Cholesky
+		chol.Factorize(a)
+		want := mat.NewSymDense(n, nil)
+		err := chol.InverseTo(want)
+		if err != nil {
+			t.Fatalf("Case %d: unexpected error: %v", cas, err)
+		}
+		for i := 0; i < n; i++ {
+			for j := i + 1; j < n; j++ {
+				want.SetSym(i, j, 2*want.At(i, j))
+			}
+		}
+
+		got := SymGradient(nil, f, a, test.settings)
+		if !mat.EqualApprox(got, want, test.tol) {
+			t.Errorf("Case %d: unexpected gradient:\ngot: %v\nwant:%v", cas, mat.Formatted(got), mat.Formatted(want))
+		}
+
+		dst := mat.NewSymDense(n, nil)
+		SymGradient(dst, f, a, test.settings)
+		if !mat.Equal(dst, got) {
+			t.Errorf("Case %d: unexpected in-place gradient", cas)
+		}
+	}
+}
+
+func TestMatrixGradientPanics(t *testing.T) {
+	f := func(*mat.Dense) float64 { return 0 }
+	if !Panics(func() { MatrixGradient(mat.NewDense(2, 3, nil), f, mat.NewDense(3, 2, nil), nil) }) {
+		t.Error("expected panic for dimension mismatch")
+	}
+	s := func(*mat.SymDense) float64 { return 0 }
+	if !Panics(func() { SymGradient(mat.NewSymDense(2, nil), s, mat.NewSymDense(3, nil), nil) }) {
+		t.Error("expected panic for dimension mismatch")
+	}
+}
@@ -0,0 +1,99 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fd
+
+import "math"
+
+// SweepResult is the accuracy and cost of a derivative estimate made
+// with a single finite difference formula and step size.
+type SweepResult struct {
+	Formula Formula
+	Step    float64
+
+	// Evals is the number of function
+	// evaluations used by the estimate.
+	Evals int
+
+	Estimate float64
+	Error    float64 // Absolute error of Estimate.
+}
+
+// Settings returns settings that reproduce the estimate of r.
+func (r SweepResult) Settings() *Settings {
+	return &Settings{Formula: r.Formula, Step: r.Step}
+}
+
+// Sweep estimates the derivative of the function f at the location x with each
+// of the given formulas and step sizes and reports the accuracy and cost of
+// each estimate, as an aid to choosing Settings for Derivative, Gradient and
+// other functions in this package. The error of each estimate is measured
+// against the reference value want, which may be obtained analytically or
+// from an estimate believed to be more accurate.
+//
+// If formulas is nil, Forward, Backward and Central are used, and if steps
+// is nil, steps of 1e-1 to 1e-12 by factors of ten are used. The returned
+// results are ordered by formula and then by step. All formulas must
+// approximate a derivative of the same order, otherwise Sweep will panic.
+//
+// Plotting Error against Step for each formula shows the trade off between
+// truncation error, which dominates at large steps, and floating point
+// cancellation error, which dominates at small steps.
+func Sweep(f func(float64) float64, x, want float64, formulas []Formula, steps []float64) []SweepResult {
+	if formulas == nil {
+		formulas = []Formula{Forward, Backward, Central}
+	}
+	if steps == nil {
+		for i := 1; i <= 12; i++ {
+			steps = append(steps, math.Pow(10, -float64(i)))
+		}
+	}
+	for _, formula := range formulas {
+		checkFormula(formula)
+		if formula.Derivative != formulas[0].Derivative {
+			panic(badDerivOrder)
+		}
+	}
+
+	results := make([]SweepResult, 0, len(formulas)*len(steps))
+	for _, formula := range formulas {
+		for _, step := range steps {
+			if step <= 0 {
+				panic(negativeStep)
+			}
+			est := Derivative(f, x, &Settings{Formula: formula, Step: step})
+			results = append(results, SweepResult{
+				Formula:  formula,
+				Step:     step,
+				Evals:    len(formula.Stencil),
+				Estimate: est,
+				Error:    math.Abs(est - want),
+			})
+		}
+	}
+	return results
+}
+
+// MostAccurate returns the result with the smallest error among the results
+// that use no more than maxEvals function evaluations. If maxEvals is less
+// than one, the number of evaluations is not limited. The returned bool is
+// false if no result satisfies the limit or all errors are NaN.
+func MostAccurate(results []SweepResult, maxEvals int) (SweepResult, bool) {
+	best := -1
+	for i, r := range results {
+		if maxEvals > 0 && r.Evals > maxEvals {
+			continue
+		}
+		if math.IsNaN(r.Error) {
+			continue
+		}
+		if best == -1 || r.Error < results[best].Error {
+			best = i
+		}
+	}
+	if best == -1 {
+		return SweepResult{}, false
+	}
+	return results[best], true
+}
@@ -0,0 +1,74 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fd
+
+import (
+	"math"
+	"testing"
+)
+
+func TestSweep(t *testing.T) {
+	const x = 1.0
+	want := math.Cos(x)
+	results := Sweep(math.Sin, x, want, nil, nil)
+	if len(results) != 3*12 {
+		t.Fatalf("unexpected number of results: got:%d want:%d", len(results), 3*12)
+	}
+	for i, r := range results {
+		if r.Evals != len(r.Formula.Stencil) {
+			t.Errorf("unexpected number of evaluations for result %d: got:%d want:%d", i, r.Evals, len(r.Formula.Stencil))
+		}
+		if got := Derivative(math.Sin, x, r.Settings()); got != r.Estimate {
+			t.Errorf("settings do not reproduce estimate for result %d: got:%v want:%v", i, got, r.Estimate)
+		}
+		if r.Error != math.Abs(r.Estimate-want) {
+			t.Errorf("unexpected error for result %d: got:%v want:%v", i, r.Error, math.Abs(r.Estimate-want))
+		}
+	}
+
+	// The most accurate estimate should be from the central
+	// formula at a step near the cube root of machine epsilon.
+	best, ok := MostAccurate(results, 0)
+	if !ok {
+		t.Fatal("no most accurate result")
+	}
+	if !sameFormula(best.Formula, Central) {
+		t.Errorf("unexpected most accurate formula: got:%v want:%v", best.Formula, Central)
+	}
+	if best.Step < 1e-7 || 1e-4 < best.Step {
+		t.Errorf("unexpected most accurate step: %v", best.Step)
+	}
+	if best.Error > 1e-9 {
+		t.Errorf("unexpected error of most accurate result: %v", best.Error)
+	}
+	for _, r := range results {
+		if r.Error < best.Error {
+			t.Errorf("result more accurate than most accurate: %v < %v", r.Error, best.Error)
+		}
+	}
+
+	if _, ok := MostAccurate(results, 1); ok {
+		t.Error("unexpected result with evaluation limit below formula costs")
+	}
+
+	if !Panics(func() { Sweep(math.Sin, x, want, []Formula{Central, Central2nd}, nil) }) {
+		t.Error("expected panic for mixed derivative orders")
+	}
+	if !Panics(func() { Sweep(math.Sin, x, want, nil, []float64{-1}) }) {
+		t.Error("expected panic for negative step")
+	}
+}
+
+func sameFormula(a, b Formula) bool {
+	if a.Derivative != b.Derivative || a.Step != b.Step || len(a.Stencil) != len(b.Stencil) {
+		return false
+	}
+	for i := range a.Stencil {
+		if a.Stencil[i] != b.Stencil[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,11 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package fourier provides discrete Fourier transforms and streaming
+// spectral processing of real-valued signals.
+//
+// The streaming types, OverlapAdd, OverlapSave, STFT and ISTFT, process
+// a signal in fixed-size frames and hold all of their working storage
+// internally, so steady-state processing does not allocate.
+package fourier // import "gonum.org/v1/gonum/fourier"
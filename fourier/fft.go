@@ -0,0 +1,123 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fourier
+
+import "math"
+
+// FFT implements the fast Fourier transform of complex sequences whose
+// length is a power of two.
+type FFT struct {
+	n        int
+	perm     []int
+	twiddles []complex128
+}
+
+// NewFFT returns an FFT for sequences of length n. NewFFT will panic
+// if n is not a positive power of two.
+func NewFFT(n int) *FFT {
+	if n < 1 || n&(n-1) != 0 {
+		panic("fourier: length not a power of two")
+	}
+	var bits uint
+	for 1<<bits < n {
+		bits++
+	}
+	perm := make([]int, n)
+	for i := range perm {
+		var r int
+		for b := uint(0); b < bits; b++ {
+			if i&(1<<b) != 0 {
+				r |= 1 << (bits - 1 - b)
+			}
+		}
+		perm[i] = r
+	}
+	twiddles := make([]complex128, n/2)
+	for k := range twiddles {
+		s, c := math.Sincos(-2 * math.Pi * float64(k) / float64(n))
+		twiddles[k] = complex(c, s)
+	}
+	return &FFT{n: n, perm: perm, twiddles: twiddles}
+}
+
+// Len returns the length of the sequences transformed by the FFT.
+func (t *FFT) Len() int {
+	return t.n
+}
+
+// Transform computes the discrete Fourier transform of src
+//  dst[k] = \sum_j src[j] * exp(-2πi jk/n)
+// and stores the result in dst, which is returned. If dst is nil a new
+// slice is allocated. dst and src may be the same slice. Transform will
+// panic if src or a non-nil dst do not have length Len.
+func (t *FFT) Transform(dst, src []complex128) []complex128 {
+	return t.transform(dst, src, false)
+}
+
+// Inverse computes the inverse discrete Fourier transform of src
+//  dst[j] = 1/n \sum_k src[k] * exp(2πi jk/n)
+// and stores the result in dst, which is returned. If dst is nil a new
+// slice is allocated. dst and src may be the same slice. Inverse will
+// panic if src or a non-nil dst do not have length Len.
+func (t *FFT) Inverse(dst, src []complex128) []complex128 {
+	dst = t.transform(dst, src, true)
+	f := complex(1/float64(t.n), 0)
+	for i := range dst {
+		dst[i] *= f
+	}
+	return dst
+}
+
+func (t *FFT) transform(dst, src []complex128, inverse bool) []complex128 {
+	if len(src) != t.n {
+		panic("fourier: sequence length mismatch")
+	}
+	if dst == nil {
+		dst = make([]complex128, t.n)
+	}
+	if len(dst) != t.n {
+		panic("fourier: destination length mismatch")
+	}
+
+	if &dst[0] == &src[0] {
+		for i, j := range t.perm {
+			if i < j {
+				dst[i], dst[j] = dst[j], dst[i]
+			}
+		}
+	} else {
+		for i, j := range t.perm {
+			dst[i] = src[j]
+		}
+	}
+
+	for size := 2; size <= t.n; size <<= 1 {
+		half := size / 2
+		step := t.n / size
+		for start := 0; start < t.n; start += size {
+			for k := 0; k < half; k++ {
+				w := t.twiddles[k*step]
+				if inverse {
+					w = complex(real(w), -imag(w))
+				}
+				a := dst[start+k]
+				b := dst[start+k+half] * w
+				dst[start+k] = a + b
+				dst[start+k+half] = a - b
+			}
+		}
+	}
+	return dst
+}
+
+// nextPowerOfTwo returns the smallest power of two
+// that is not less than n.
+func nextPowerOfTwo(n int) int {
+	p := 1
+	for p < n {
+		p <<= 1
+	}
+	return p
+}
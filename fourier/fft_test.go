@@ -0,0 +1,85 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fourier
+
+import (
+	"math"
+	"math/cmplx"
+	"testing"
+
+	"golang.org/x/exp/rand"
+)
+
+// naiveDFT returns the discrete Fourier transform of x computed directly.
+func naiveDFT(x []complex128) []complex128 {
+	n := len(x)
+	dst := make([]complex128, n)
+	for k := range dst {
+		for j, v := range x {
+			s, c := math.Sincos(-2 * math.Pi * float64(j*k) / float64(n))
+			dst[k] += v * complex(c, s)
+		}
+	}
+	return dst
+}
+
+func TestFFT(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for _, n := range []int{1, 2, 4, 8, 64, 256} {
+		x := make([]complex128, n)
+		for i := range x {
+			x[i] = complex(rnd.NormFloat64(), rnd.NormFloat64())
+		}
+		fft := NewFFT(n)
+		if fft.Len() != n {
+			t.Errorf("unexpected length: got:%d want:%d", fft.Len(), n)
+		}
+		got := fft.Transform(nil, x)
+		want := naiveDFT(x)
+		for k := range want {
+			if cmplx.Abs(got[k]-want[k]) > 1e-10*float64(n) {
+				t.Errorf("unexpected coefficient %d for n=%d: got:%v want:%v", k, n, got[k], want[k])
+			}
+		}
+
+		inv := fft.Inverse(nil, got)
+		for i := range x {
+			if cmplx.Abs(inv[i]-x[i]) > 1e-12*float64(n) {
+				t.Errorf("unexpected round trip value %d for n=%d: got:%v want:%v", i, n, inv[i], x[i])
+			}
+		}
+
+		// Transforms in place match transforms into a new slice.
+		inPlace := make([]complex128, n)
+		copy(inPlace, x)
+		fft.Transform(inPlace, inPlace)
+		for k := range got {
+			if inPlace[k] != got[k] {
+				t.Errorf("unexpected in place coefficient %d for n=%d: got:%v want:%v", k, n, inPlace[k], got[k])
+			}
+		}
+	}
+
+	for _, n := range []int{0, 3, 12, -4} {
+		if !panics(func() { NewFFT(n) }) {
+			t.Errorf("expected panic for length %d", n)
+		}
+	}
+	fft := NewFFT(8)
+	if !panics(func() { fft.Transform(nil, make([]complex128, 4)) }) {
+		t.Error("expected panic for sequence length mismatch")
+	}
+	if !panics(func() { fft.Transform(make([]complex128, 4), make([]complex128, 8)) }) {
+		t.Error("expected panic for destination length mismatch")
+	}
+}
+
+func panics(fn func()) (ok bool) {
+	defer func() {
+		ok = recover() != nil
+	}()
+	fn()
+	return
+}
@@ -0,0 +1,321 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fourier
+
+// OverlapAdd convolves a stream of fixed-size frames with a finite impulse
+// response filter using the overlap-add method. Each input frame is
+// transformed after zero padding, and the part of the result extending
+// beyond the frame is added to the output of the following frame.
+type OverlapAdd struct {
+	fft   *FFT
+	frame int
+	h     []complex128
+	work  []complex128
+	tail  []float64
+}
+
+// NewOverlapAdd returns an OverlapAdd that convolves frames of the given
+// size with filter. NewOverlapAdd will panic if filter is empty or frame
+// is not positive.
+func NewOverlapAdd(filter []float64, frame int) *OverlapAdd {
+	fft, h := filterSpectrum(filter, frame)
+	return &OverlapAdd{
+		fft:   fft,
+		frame: frame,
+		h:     h,
+		work:  make([]complex128, fft.Len()),
+		tail:  make([]float64, len(filter)-1),
+	}
+}
+
+// Process convolves the next frame of the stream with the filter, storing
+// the corresponding output samples in dst, which is returned. The output
+// is the linear convolution of the stream with the filter, truncated to
+// the samples received so far. If dst is nil a new slice is allocated.
+// dst and frame may be the same slice. Process will panic if frame or a
+// non-nil dst do not have the frame size of the receiver.
+func (c *OverlapAdd) Process(dst, frame []float64) []float64 {
+	dst = checkFrame(dst, frame, c.frame)
+	for i := range c.work {
+		if i < len(frame) {
+			c.work[i] = complex(frame[i], 0)
+		} else {
+			c.work[i] = 0
+		}
+	}
+	c.fft.Transform(c.work, c.work)
+	for i, v := range c.h {
+		c.work[i] *= v
+	}
+	c.fft.Inverse(c.work, c.work)
+
+	for i := range dst {
+		dst[i] = real(c.work[i])
+		if i < len(c.tail) {
+			dst[i] += c.tail[i]
+		}
+	}
+	for j := range c.tail {
+		v := real(c.work[c.frame+j])
+		if c.frame+j < len(c.tail) {
+			v += c.tail[c.frame+j]
+		}
+		c.tail[j] = v
+	}
+	return dst
+}
+
+// Reset clears the stream history of the receiver.
+func (c *OverlapAdd) Reset() {
+	for i := range c.tail {
+		c.tail[i] = 0
+	}
+}
+
+// OverlapSave convolves a stream of fixed-size frames with a finite impulse
+// response filter using the overlap-save method. Each input frame is
+// transformed together with the preceding input samples needed by the
+// filter, and the samples corrupted by circular wrap-around are discarded.
+type OverlapSave struct {
+	fft   *FFT
+	frame int
+	h     []complex128
+	work  []complex128
+	hist  []float64
+}
+
+// NewOverlapSave returns an OverlapSave that convolves frames of the given
+// size with filter. NewOverlapSave will panic if filter is empty or frame
+// is not positive.
+func NewOverlapSave(filter []float64, frame int) *OverlapSave {
+	fft, h := filterSpectrum(filter, frame)
+	return &OverlapSave{
+		fft:   fft,
+		frame: frame,
+		h:     h,
+		work:  make([]complex128, fft.Len()),
+		hist:  make([]float64, len(filter)-1),
+	}
+}
+
+// Process convolves the next frame of the stream with the filter, storing
+// the corresponding output samples in dst, which is returned. The output
+// is the linear convolution of the stream with the filter, truncated to
+// the samples received so far. If dst is nil a new slice is allocated.
+// dst and frame may be the same slice. Process will panic if frame or a
+// non-nil dst do not have the frame size of the receiver.
+func (c *OverlapSave) Process(dst, frame []float64) []float64 {
+	dst = checkFrame(dst, frame, c.frame)
+	m := len(c.hist)
+	for i := range c.work {
+		switch {
+		case i < m:
+			c.work[i] = complex(c.hist[i], 0)
+		case i < m+len(frame):
+			c.work[i] = complex(frame[i-m], 0)
+		default:
+			c.work[i] = 0
+		}
+	}
+	if len(frame) >= m {
+		copy(c.hist, frame[len(frame)-m:])
+	} else {
+		copy(c.hist, c.hist[len(frame):])
+		copy(c.hist[m-len(frame):], frame)
+	}
+
+	c.fft.Transform(c.work, c.work)
+	for i, v := range c.h {
+		c.work[i] *= v
+	}
+	c.fft.Inverse(c.work, c.work)
+	for i := range dst {
+		dst[i] = real(c.work[m+i])
+	}
+	return dst
+}
+
+// Reset clears the stream history of the receiver.
+func (c *OverlapSave) Reset() {
+	for i := range c.hist {
+		c.hist[i] = 0
+	}
+}
+
+// filterSpectrum returns an FFT long enough for the linear convolution
+// of a frame with filter, and the transform of the zero padded filter.
+func filterSpectrum(filter []float64, frame int) (*FFT, []complex128) {
+	if len(filter) == 0 {
+		panic("fourier: empty filter")
+	}
+	if frame <= 0 {
+		panic("fourier: non-positive frame size")
+	}
+	fft := NewFFT(nextPowerOfTwo(frame + len(filter) - 1))
+	h := make([]complex128, fft.Len())
+	for i, v := range filter {
+		h[i] = complex(v, 0)
+	}
+	return fft, fft.Transform(h, h)
+}
+
+// checkFrame panics if frame does not have length n or dst is not nil and
+// does not have length n. It returns dst, allocating it if it is nil.
+func checkFrame(dst, frame []float64, n int) []float64 {
+	if len(frame) != n {
+		panic("fourier: frame length mismatch")
+	}
+	if dst == nil {
+		return make([]float64, n)
+	}
+	if len(dst) != n {
+		panic("fourier: destination length mismatch")
+	}
+	return dst
+}
+
+// STFT computes the short-time Fourier transform of a stream. The stream
+// is received in hops of fixed size and after each hop the most recent
+// window length of samples is windowed and transformed.
+type STFT struct {
+	fft    *FFT
+	window []float64
+	hop    int
+	buf    []float64
+}
+
+// NewSTFT returns an STFT using the given analysis window and hop size.
+// The window is not copied. NewSTFT will panic if the length of window is
+// not a power of two or hop is not in [1, len(window)].
+func NewSTFT(window []float64, hop int) *STFT {
+	checkHop(window, hop)
+	return &STFT{
+		fft:    NewFFT(len(window)),
+		window: window,
+		hop:    hop,
+		buf:    make([]float64, len(window)),
+	}
+}
+
+// Next shifts the samples of the next hop into the analysis buffer and
+// stores the transform of the windowed buffer in dst, which is returned.
+// Before the first window length of samples has been received the buffer
+// is padded with leading zeros. If dst is nil a new slice is allocated.
+// Next will panic if samples does not have length equal to the hop size
+// or a non-nil dst does not have length equal to the window length.
+func (s *STFT) Next(dst []complex128, samples []float64) []complex128 {
+	if len(samples) != s.hop {
+		panic("fourier: hop length mismatch")
+	}
+	n := len(s.buf)
+	if dst == nil {
+		dst = make([]complex128, n)
+	}
+	if len(dst) != n {
+		panic("fourier: destination length mismatch")
+	}
+	copy(s.buf, s.buf[s.hop:])
+	copy(s.buf[n-s.hop:], samples)
+	for i, v := range s.buf {
+		dst[i] = complex(v*s.window[i], 0)
+	}
+	return s.fft.Transform(dst, dst)
+}
+
+// Reset clears the stream history of the receiver.
+func (s *STFT) Reset() {
+	for i := range s.buf {
+		s.buf[i] = 0
+	}
+}
+
+// ISTFT reconstructs a stream from its short-time Fourier transform by
+// weighted overlap-add. The inverse transform of each spectrum is
+// multiplied by the synthesis window and accumulated, and the completed
+// samples are normalized by the sum of the squared overlapping windows.
+//
+// When used with an STFT with the same window and hop, the reconstructed
+// stream is the original stream delayed by the window length minus the
+// hop size, provided that every sample is covered by a non-zero window
+// value.
+type ISTFT struct {
+	fft    *FFT
+	window []float64
+	hop    int
+	work   []complex128
+	acc    []float64
+	norm   []float64
+}
+
+// NewISTFT returns an ISTFT using the given synthesis window and hop size.
+// The window is not copied. NewISTFT will panic if the length of window is
+// not a power of two or hop is not in [1, len(window)].
+func NewISTFT(window []float64, hop int) *ISTFT {
+	checkHop(window, hop)
+	norm := make([]float64, hop)
+	for i := range norm {
+		for j := i; j < len(window); j += hop {
+			norm[i] += window[j] * window[j]
+		}
+	}
+	return &ISTFT{
+		fft:    NewFFT(len(window)),
+		window: window,
+		hop:    hop,
+		work:   make([]complex128, len(window)),
+		acc:    make([]float64, len(window)),
+		norm:   norm,
+	}
+}
+
+// Next overlap-adds the inverse transform of spectrum and stores the hop
+// of samples that are complete in dst, which is returned. If dst is nil a
+// new slice is allocated. Next will panic if spectrum does not have length
+// equal to the window length or a non-nil dst does not have length equal
+// to the hop size.
+func (s *ISTFT) Next(dst []float64, spectrum []complex128) []float64 {
+	n := len(s.acc)
+	if len(spectrum) != n {
+		panic("fourier: spectrum length mismatch")
+	}
+	if dst == nil {
+		dst = make([]float64, s.hop)
+	}
+	if len(dst) != s.hop {
+		panic("fourier: destination length mismatch")
+	}
+	s.fft.Inverse(s.work, spectrum)
+	for i, v := range s.work {
+		s.acc[i] += real(v) * s.window[i]
+	}
+	for i := range dst {
+		if s.norm[i] == 0 {
+			dst[i] = 0
+			continue
+		}
+		dst[i] = s.acc[i] / s.norm[i]
+	}
+	copy(s.acc, s.acc[s.hop:])
+	for i := n - s.hop; i < n; i++ {
+		s.acc[i] = 0
+	}
+	return dst
+}
+
+// Reset clears the stream history of the receiver.
+func (s *ISTFT) Reset() {
+	for i := range s.acc {
+		s.acc[i] = 0
+	}
+}
+
+func checkHop(window []float64, hop int) {
+	if len(window) == 0 || len(window)&(len(window)-1) != 0 {
+		panic("fourier: window length not a power of two")
+	}
+	if hop < 1 || len(window) < hop {
+		panic("fourier: hop size out of range")
+	}
+}
@@ -0,0 +1,174 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fourier
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+)
+
+// convolve returns the linear convolution of x and h truncated to len(x).
+func convolve(x, h []float64) []float64 {
+	y := make([]float64, len(x))
+	for i := range y {
+		for j, v := range h {
+			if i-j < 0 {
+				break
+			}
+			y[i] += v * x[i-j]
+		}
+	}
+	return y
+}
+
+type convolver interface {
+	Process(dst, frame []float64) []float64
+	Reset()
+}
+
+func TestConvolvers(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for _, test := range []struct {
+		filter, frame int
+	}{
+		{filter: 1, frame: 8},
+		{filter: 5, frame: 16},
+		{filter: 17, frame: 16},
+		{filter: 40, frame: 7},
+	} {
+		const frames = 12
+		h := make([]float64, test.filter)
+		for i := range h {
+			h[i] = rnd.NormFloat64()
+		}
+		x := make([]float64, frames*test.frame)
+		for i := range x {
+			x[i] = rnd.NormFloat64()
+		}
+		want := convolve(x, h)
+
+		for _, c := range []struct {
+			name string
+			conv convolver
+		}{
+			{name: "overlap-add", conv: NewOverlapAdd(h, test.frame)},
+			{name: "overlap-save", conv: NewOverlapSave(h, test.frame)},
+		} {
+			for pass := 0; pass < 2; pass++ {
+				got := make([]float64, 0, len(x))
+				dst := make([]float64, test.frame)
+				for f := 0; f < frames; f++ {
+					frame := x[f*test.frame : (f+1)*test.frame]
+					got = append(got, c.conv.Process(dst, frame)...)
+				}
+				for i := range want {
+					if math.Abs(got[i]-want[i]) > 1e-10 {
+						t.Errorf("%s filter=%d frame=%d pass=%d: unexpected output at %d: got:%v want:%v",
+							c.name, test.filter, test.frame, pass, i, got[i], want[i])
+						break
+					}
+				}
+				c.conv.Reset()
+			}
+
+			frame := make([]float64, test.frame)
+			if allocs := testing.AllocsPerRun(10, func() { c.conv.Process(frame, frame) }); allocs != 0 {
+				t.Errorf("%s: unexpected allocations: got:%v want:0", c.name, allocs)
+			}
+			if !panics(func() { c.conv.Process(nil, make([]float64, test.frame+1)) }) {
+				t.Errorf("%s: expected panic for frame length mismatch", c.name)
+			}
+		}
+	}
+	if !panics(func() { NewOverlapAdd(nil, 4) }) {
+		t.Error("expected panic for empty filter")
+	}
+	if !panics(func() { NewOverlapSave([]float64{1}, 0) }) {
+		t.Error("expected panic for non-positive frame size")
+	}
+}
+
+func TestSTFTReconstruction(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for _, test := range []struct {
+		n, hop int
+		window func([]float64) []float64
+	}{
+		{n: 16, hop: 8, window: Hann},
+		{n: 32, hop: 8, window: Hann},
+		{n: 16, hop: 5, window: Hamming},
+		{n: 8, hop: 8, window: Hamming},
+	} {
+		w := test.window(make([]float64, test.n))
+		stft := NewSTFT(w, test.hop)
+		istft := NewISTFT(w, test.hop)
+
+		const hops = 40
+		x := make([]float64, hops*test.hop)
+		for i := range x {
+			x[i] = rnd.NormFloat64()
+		}
+		var y []float64
+		spec := make([]complex128, test.n)
+		out := make([]float64, test.hop)
+		for k := 0; k < hops; k++ {
+			stft.Next(spec, x[k*test.hop:(k+1)*test.hop])
+			y = append(y, istft.Next(out, spec)...)
+		}
+
+		delay := test.n - test.hop
+		for i := 0; i+delay < len(y); i++ {
+			if math.Abs(y[i+delay]-x[i]) > 1e-12 {
+				t.Errorf("n=%d hop=%d: unexpected reconstruction at %d: got:%v want:%v", test.n, test.hop, i, y[i+delay], x[i])
+				break
+			}
+		}
+		for i := 0; i < delay; i++ {
+			if math.Abs(y[i]) > 1e-12 {
+				t.Errorf("n=%d hop=%d: unexpected non-zero leading output at %d: %v", test.n, test.hop, i, y[i])
+				break
+			}
+		}
+
+		samples := make([]float64, test.hop)
+		if allocs := testing.AllocsPerRun(10, func() {
+			stft.Next(spec, samples)
+			istft.Next(out, spec)
+		}); allocs != 0 {
+			t.Errorf("n=%d hop=%d: unexpected allocations: got:%v want:0", test.n, test.hop, allocs)
+		}
+	}
+
+	// The spectrum of a windowed sinusoid is concentrated at its frequency.
+	const n = 64
+	stft := NewSTFT(Hann(make([]float64, n)), n)
+	x := make([]float64, n)
+	for i := range x {
+		x[i] = math.Cos(2 * math.Pi * 5 * float64(i) / n)
+	}
+	spec := stft.Next(nil, x)
+	for k := 0; k < n/2; k++ {
+		mag := math.Hypot(real(spec[k]), imag(spec[k]))
+		want := 0.0
+		switch k {
+		case 5:
+			want = n / 4
+		case 4, 6:
+			want = n / 8
+		}
+		if math.Abs(mag-want) > 1e-10 {
+			t.Errorf("unexpected magnitude at bin %d: got:%v want:%v", k, mag, want)
+		}
+	}
+
+	if !panics(func() { NewSTFT(make([]float64, 12), 4) }) {
+		t.Error("expected panic for window length not a power of two")
+	}
+	if !panics(func() { NewISTFT(make([]float64, 8), 9) }) {
+		t.Error("expected panic for hop size out of range")
+	}
+}
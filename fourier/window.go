@@ -0,0 +1,30 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fourier
+
+import "math"
+
+// Hann fills dst with the periodic Hann window
+//  w[i] = 0.5 - 0.5 cos(2πi/n)
+// where n is the length of dst, and returns dst. Overlapping periodic
+// Hann windows at a hop of n/2 sum to one.
+func Hann(dst []float64) []float64 {
+	n := float64(len(dst))
+	for i := range dst {
+		dst[i] = 0.5 - 0.5*math.Cos(2*math.Pi*float64(i)/n)
+	}
+	return dst
+}
+
+// Hamming fills dst with the periodic Hamming window
+//  w[i] = 0.54 - 0.46 cos(2πi/n)
+// where n is the length of dst, and returns dst.
+func Hamming(dst []float64) []float64 {
+	n := float64(len(dst))
+	for i := range dst {
+		dst[i] = 0.54 - 0.46*math.Cos(2*math.Pi*float64(i)/n)
+	}
+	return dst
+}
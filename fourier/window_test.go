@@ -0,0 +1,32 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fourier
+
+import (
+	"math"
+	"testing"
+)
+
+func TestWindows(t *testing.T) {
+	const n = 16
+	hann := Hann(make([]float64, n))
+	if hann[0] != 0 || math.Abs(hann[n/2]-1) > 1e-15 {
+		t.Errorf("unexpected Hann window: %v", hann)
+	}
+	for i := 0; i < n/2; i++ {
+		if sum := hann[i] + hann[i+n/2]; math.Abs(sum-1) > 1e-15 {
+			t.Errorf("unexpected sum of overlapping Hann windows at %d: got:%v want:1", i, sum)
+		}
+	}
+	hamming := Hamming(make([]float64, n))
+	if math.Abs(hamming[0]-0.08) > 1e-15 || math.Abs(hamming[n/2]-1) > 1e-15 {
+		t.Errorf("unexpected Hamming window: %v", hamming)
+	}
+	for i := 1; i < n; i++ {
+		if math.Abs(hann[i]-hann[n-i]) > 1e-15 || math.Abs(hamming[i]-hamming[n-i]) > 1e-15 {
+			t.Errorf("window not symmetric at %d", i)
+		}
+	}
+}
@@ -0,0 +1,245 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package coarsen
+
+import (
+	"sort"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/internal/ordered"
+	"gonum.org/v1/gonum/graph/simple"
+)
+
+// Level is a single level of a coarsening hierarchy, relating the nodes of a
+// fine graph to the nodes of the coarse graph obtained by contracting it.
+type Level struct {
+	// Coarse is the coarse graph. Coarse nodes have IDs from
+	// zero in the order of the lowest ID of their fine nodes.
+	// The weight of each coarse edge is the total weight of
+	// the fine edges joining the contracted nodes.
+	Coarse *simple.WeightedUndirectedGraph
+
+	// parent holds the coarse node of
+	// each fine node, and children holds
+	// the fine nodes of each coarse node.
+	parent   map[int64]graph.Node
+	children map[int64][]graph.Node
+
+	// nodeWeight holds the total weight
+	// of the fine nodes contracted into
+	// each coarse node, and internal holds
+	// the total weight of the fine edges
+	// contracted within each coarse node.
+	nodeWeight map[int64]float64
+	internal   map[int64]float64
+}
+
+// Parent returns the coarse node containing the fine node n,
+// or nil if n is not a node of the fine graph.
+func (l *Level) Parent(n graph.Node) graph.Node {
+	return l.parent[n.ID()]
+}
+
+// Children returns the fine nodes contracted into the coarse node n,
+// sorted by ID.
+func (l *Level) Children(n graph.Node) []graph.Node {
+	return l.children[n.ID()]
+}
+
+// NodeWeight returns the weight of the coarse node n, which is the total
+// weight of the fine nodes contracted into n. Nodes of the original graph
+// have unit weight.
+func (l *Level) NodeWeight(n graph.Node) float64 {
+	return l.nodeWeight[n.ID()]
+}
+
+// InternalWeight returns the total weight of the edges of the original graph
+// that have been contracted within the coarse node n.
+func (l *Level) InternalWeight(n graph.Node) float64 {
+	return l.internal[n.ID()]
+}
+
+// Prolong sets the value of each fine node to the value held in x for its
+// coarse node, placing the result in dst and returning it. If dst is nil, a
+// new map is created. Coarse nodes without a value in x are ignored.
+func (l *Level) Prolong(dst, x map[int64]float64) map[int64]float64 {
+	if dst == nil {
+		dst = make(map[int64]float64, len(l.parent))
+	}
+	for id, p := range l.parent {
+		if v, ok := x[p.ID()]; ok {
+			dst[id] = v
+		}
+	}
+	return dst
+}
+
+// ProlongLabels sets the label of each fine node to the label held in labels
+// for its coarse node, placing the result in dst and returning it. If dst is
+// nil, a new map is created. Coarse nodes without a label are ignored.
+func (l *Level) ProlongLabels(dst, labels map[int64]int) map[int64]int {
+	if dst == nil {
+		dst = make(map[int64]int, len(l.parent))
+	}
+	for id, p := range l.parent {
+		if c, ok := labels[p.ID()]; ok {
+			dst[id] = c
+		}
+	}
+	return dst
+}
+
+// HeavyEdgeMatching returns a single level of coarsening of g obtained by
+// contracting the edges of a heavy edge matching. Nodes are visited in a
+// random order and each unmatched node is matched with the unmatched neighbor
+// joined to it by the heaviest edge, with ties broken by lowest ID. If g
+// implements graph.Weighted, edge weights are used, otherwise each edge has
+// unit weight. Self edges are ignored. If src is not nil it is used as the
+// random source, otherwise rand.Perm is used.
+func HeavyEdgeMatching(g graph.Undirected, src *rand.Rand) *Level {
+	return heavyEdgeMatching(g, nil, src)
+}
+
+// Hierarchy returns the levels of a coarsening hierarchy for g, obtained by
+// repeated heavy edge matching until the coarse graph has no more than
+// minNodes nodes or a level fails to reduce the number of nodes by at least
+// ten percent. The first level coarsens g and each subsequent level coarsens
+// the Coarse graph of the previous level. If src is not nil it is used as
+// the random source, otherwise rand.Perm is used.
+func Hierarchy(g graph.Undirected, minNodes int, src *rand.Rand) []*Level {
+	var (
+		levels []*Level
+		prev   *Level
+	)
+	for n := len(g.Nodes()); n > minNodes; {
+		l := heavyEdgeMatching(g, prev, src)
+		levels = append(levels, l)
+		m := len(l.Coarse.Nodes())
+		if float64(m) > 0.9*float64(n) {
+			break
+		}
+		g, prev, n = l.Coarse, l, m
+	}
+	return levels
+}
+
+// heavyEdgeMatching returns a heavy edge matching coarsening of g. If prev is
+// not nil, g is the coarse graph of prev and the node and internal weights of
+// the nodes of g are taken from prev, otherwise nodes have unit weight and no
+// internal weight.
+func heavyEdgeMatching(g graph.Undirected, prev *Level, src *rand.Rand) *Level {
+	nodes := g.Nodes()
+	sort.Sort(ordered.ByID(nodes))
+	indexOf := make(map[int64]int, len(nodes))
+	for i, n := range nodes {
+		indexOf[n.ID()] = i
+	}
+
+	perm := rand.Perm
+	if src != nil {
+		perm = src.Perm
+	}
+	weight := weightFunc(g)
+	mate := make([]int, len(nodes))
+	for i := range mate {
+		mate[i] = -1
+	}
+	for _, i := range perm(len(nodes)) {
+		if mate[i] != -1 {
+			continue
+		}
+		u := nodes[i]
+		to := g.From(u)
+		sort.Sort(ordered.ByID(to))
+		best := -1
+		var max float64
+		for _, v := range to {
+			j := indexOf[v.ID()]
+			if j == i || mate[j] != -1 {
+				continue
+			}
+			if w := weight(u, v); best == -1 || w > max {
+				best = j
+				max = w
+			}
+		}
+		if best == -1 {
+			// Leave u as a singleton.
+			mate[i] = i
+			continue
+		}
+		mate[i] = best
+		mate[best] = i
+	}
+
+	l := &Level{
+		Coarse:     simple.NewWeightedUndirectedGraph(0, 0),
+		parent:     make(map[int64]graph.Node, len(nodes)),
+		children:   make(map[int64][]graph.Node),
+		nodeWeight: make(map[int64]float64),
+		internal:   make(map[int64]float64),
+	}
+	for i, u := range nodes {
+		j := mate[i]
+		if j < i {
+			continue
+		}
+		p := simple.Node(len(l.children))
+		l.Coarse.AddNode(p)
+		l.parent[u.ID()] = p
+		l.children[p.ID()] = append(l.children[p.ID()], u)
+		if j != i {
+			l.parent[nodes[j].ID()] = p
+			l.children[p.ID()] = append(l.children[p.ID()], nodes[j])
+		}
+		for _, n := range l.children[p.ID()] {
+			if prev == nil {
+				l.nodeWeight[p.ID()]++
+				continue
+			}
+			l.nodeWeight[p.ID()] += prev.nodeWeight[n.ID()]
+			l.internal[p.ID()] += prev.internal[n.ID()]
+		}
+	}
+
+	for i, u := range nodes {
+		pu := l.parent[u.ID()]
+		for _, v := range g.From(u) {
+			j := indexOf[v.ID()]
+			if j <= i {
+				continue
+			}
+			w := weight(u, v)
+			pv := l.parent[v.ID()]
+			if pu.ID() == pv.ID() {
+				l.internal[pu.ID()] += w
+				continue
+			}
+			if e := l.Coarse.WeightedEdgeBetween(pu, pv); e != nil {
+				w += e.Weight()
+			}
+			l.Coarse.SetWeightedEdge(simple.WeightedEdge{F: pu, T: pv, W: w})
+		}
+	}
+	return l
+}
+
+// weightFunc returns a function returning the weight of the edge
+// between u and v in g, or unit weight if g is not weighted.
+func weightFunc(g graph.Graph) func(u, v graph.Node) float64 {
+	wg, ok := g.(graph.Weighted)
+	if !ok {
+		return func(_, _ graph.Node) float64 { return 1 }
+	}
+	return func(u, v graph.Node) float64 {
+		w, ok := wg.Weight(u, v)
+		if !ok {
+			panic("coarsen: unexpected invalid weight")
+		}
+		return w
+	}
+}
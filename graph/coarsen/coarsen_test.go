@@ -0,0 +1,183 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package coarsen
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/graphs/gen"
+	"gonum.org/v1/gonum/graph/simple"
+)
+
+func TestHeavyEdgeMatching(t *testing.T) {
+	// A path with alternating heavy and
+	// light edges is contracted along the
+	// heavy edges whatever the visit order.
+	g := simple.NewWeightedUndirectedGraph(0, 0)
+	for i := 0; i < 7; i++ {
+		w := 1.0
+		if i%2 == 0 {
+			w = 10
+		}
+		g.SetWeightedEdge(simple.WeightedEdge{F: simple.Node(i), T: simple.Node(i + 1), W: w})
+	}
+	for seed := uint64(0); seed < 10; seed++ {
+		l := HeavyEdgeMatching(g, rand.New(rand.NewSource(seed)))
+		if n := len(l.Coarse.Nodes()); n != 4 {
+			t.Errorf("unexpected number of coarse nodes for seed %d: got:%d want:4", seed, n)
+		}
+		for i := 0; i < 4; i++ {
+			p := simple.Node(i)
+			children := l.Children(p)
+			want := fmt.Sprint([]graph.Node{simple.Node(2 * i), simple.Node(2*i + 1)})
+			if fmt.Sprint(children) != want {
+				t.Errorf("unexpected children of %d for seed %d: got:%v want:%v", i, seed, children, want)
+			}
+			if w := l.InternalWeight(p); w != 10 {
+				t.Errorf("unexpected internal weight of %d for seed %d: got:%v want:10", i, seed, w)
+			}
+			if w := l.NodeWeight(p); w != 2 {
+				t.Errorf("unexpected node weight of %d for seed %d: got:%v want:2", i, seed, w)
+			}
+		}
+		checkLevel(t, fmt.Sprintf("path with seed %d", seed), g, l)
+	}
+}
+
+func TestHierarchy(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	u := simple.NewUndirectedGraph()
+	gen.Gnp(u, 500, 0.01, rnd)
+	g := simple.NewWeightedUndirectedGraph(0, 0)
+	for _, n := range u.Nodes() {
+		g.AddNode(n)
+	}
+	var total float64
+	for _, e := range u.Edges() {
+		w := 1 + rnd.Float64()
+		total += w
+		g.SetWeightedEdge(simple.WeightedEdge{F: e.From(), T: e.To(), W: w})
+	}
+
+	const minNodes = 20
+	levels := Hierarchy(g, minNodes, rnd)
+	if len(levels) == 0 {
+		t.Fatal("no coarsening levels")
+	}
+	var fine graph.Undirected = g
+	for i, l := range levels {
+		name := fmt.Sprintf("level %d", i)
+		checkLevel(t, name, fine, l)
+
+		// The total node weight is the number of
+		// original nodes and the internal and external
+		// edge weights sum to the original total.
+		var nodeWeight, weight float64
+		for _, n := range l.Coarse.Nodes() {
+			nodeWeight += l.NodeWeight(n)
+			weight += l.InternalWeight(n)
+		}
+		for _, e := range l.Coarse.Edges() {
+			weight += e.(simple.WeightedEdge).W
+		}
+		if nodeWeight != float64(len(g.Nodes())) {
+			t.Errorf("unexpected total node weight for %s: got:%v want:%d", name, nodeWeight, len(g.Nodes()))
+		}
+		if math.Abs(weight-total) > 1e-9 {
+			t.Errorf("unexpected total edge weight for %s: got:%v want:%v", name, weight, total)
+		}
+		if i < len(levels)-1 {
+			fine = l.Coarse
+		}
+	}
+	last := levels[len(levels)-1]
+	if n := len(last.Coarse.Nodes()); n > minNodes && float64(n) <= 0.9*float64(len(fine.Nodes())) {
+		t.Errorf("hierarchy terminated early with %d nodes", n)
+	}
+
+	// Prolong labels from the coarsest graph
+	// to the original graph.
+	labels := make(map[int64]int)
+	for _, n := range last.Coarse.Nodes() {
+		labels[n.ID()] = int(n.ID())
+	}
+	for i := len(levels) - 1; i >= 0; i-- {
+		labels = levels[i].ProlongLabels(nil, labels)
+	}
+	if len(labels) != len(g.Nodes()) {
+		t.Errorf("unexpected number of prolonged labels: got:%d want:%d", len(labels), len(g.Nodes()))
+	}
+	for _, n := range g.Nodes() {
+		p := graph.Node(n)
+		for _, l := range levels {
+			p = l.Parent(p)
+		}
+		if labels[n.ID()] != int(p.ID()) {
+			t.Errorf("unexpected prolonged label for node %d: got:%d want:%d", n.ID(), labels[n.ID()], p.ID())
+		}
+	}
+}
+
+// checkLevel checks that l is a valid contraction of the fine graph g.
+func checkLevel(t *testing.T, name string, g graph.Undirected, l *Level) {
+	for _, n := range g.Nodes() {
+		p := l.Parent(n)
+		if p == nil {
+			t.Errorf("no parent for node %d in %s", n.ID(), name)
+			continue
+		}
+		found := false
+		for _, c := range l.Children(p) {
+			if c.ID() == n.ID() {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("node %d not a child of its parent %d in %s", n.ID(), p.ID(), name)
+		}
+	}
+	for _, p := range l.Coarse.Nodes() {
+		children := l.Children(p)
+		if len(children) < 1 || 2 < len(children) {
+			t.Errorf("unexpected number of children for coarse node %d in %s: %d", p.ID(), name, len(children))
+		}
+		if len(children) == 2 && !g.HasEdgeBetween(children[0], children[1]) {
+			t.Errorf("contracted nodes %d and %d are not adjacent in %s", children[0].ID(), children[1].ID(), name)
+		}
+	}
+
+	wg, _ := g.(graph.Weighted)
+	want := make(map[[2]int64]float64)
+	for _, u := range g.Nodes() {
+		for _, v := range g.From(u) {
+			if u.ID() > v.ID() {
+				continue
+			}
+			pu, pv := l.Parent(u).ID(), l.Parent(v).ID()
+			if pu == pv {
+				continue
+			}
+			if pu > pv {
+				pu, pv = pv, pu
+			}
+			w, _ := wg.Weight(u, v)
+			want[[2]int64{pu, pv}] += w
+		}
+	}
+	if len(l.Coarse.Edges()) != len(want) {
+		t.Errorf("unexpected number of coarse edges in %s: got:%d want:%d", name, len(l.Coarse.Edges()), len(want))
+	}
+	for e, w := range want {
+		got, _ := l.Coarse.Weight(simple.Node(e[0]), simple.Node(e[1]))
+		if math.Abs(got-w) > 1e-9 {
+			t.Errorf("unexpected weight for coarse edge %d--%d in %s: got:%v want:%v", e[0], e[1], name, got, w)
+		}
+	}
+}
@@ -0,0 +1,11 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package coarsen provides graph coarsening for multilevel graph algorithms.
+//
+// Multilevel algorithms for layout, partitioning and community detection
+// repeatedly contract a graph into smaller graphs, solve the problem on the
+// smallest graph and then prolong the solution back through the levels of
+// the hierarchy, refining it at each level.
+package coarsen // import "gonum.org/v1/gonum/graph/coarsen"
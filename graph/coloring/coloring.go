@@ -0,0 +1,306 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package coloring
+
+import (
+	"container/heap"
+	"sort"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/internal/ordered"
+)
+
+// DSatur returns a coloring of g found with the DSATUR heuristic of Brélaz
+// and the number of colors used. Colors are numbered from zero. At each step
+// DSATUR colors the uncolored node with the greatest saturation, the number
+// of distinct colors among its neighbors, breaking ties by greatest degree
+// and then lowest ID, with the lowest color not used by its neighbors.
+// Self edges are ignored.
+//
+// See Brélaz, D. "New methods to color the vertices of a graph."
+// Commun. ACM 22(4):251–256 (1979).
+func DSatur(g graph.Undirected) (colors map[int64]int, k int) {
+	return dsatur(g, false)
+}
+
+// DSaturInterchange returns a coloring of g found with the DSATUR heuristic
+// and the number of colors used, as for DSatur, but before introducing a new
+// color for a node it attempts to free an existing color by swapping a pair
+// of colors on Kempe chains, the connected components of the subgraph
+// induced by two colors, that contain neighbors of the node. The resulting
+// colorings often use fewer colors than those found by DSatur, though this is
+// not guaranteed since an interchange changes the order in which later nodes
+// are colored. Self edges are ignored.
+func DSaturInterchange(g graph.Undirected) (colors map[int64]int, k int) {
+	return dsatur(g, true)
+}
+
+func dsatur(g graph.Undirected, interchange bool) (colors map[int64]int, k int) {
+	nodes := g.Nodes()
+	sort.Sort(ordered.ByID(nodes))
+	s := &saturation{
+		g:      g,
+		colors: make(map[int64]int, len(nodes)),
+		adj:    make(map[int64]map[int]int, len(nodes)),
+		degree: make(map[int64]int, len(nodes)),
+	}
+	for _, n := range nodes {
+		id := n.ID()
+		s.adj[id] = make(map[int]int)
+		for _, v := range g.From(n) {
+			if v.ID() != id {
+				s.degree[id]++
+			}
+		}
+		heap.Push(&s.queue, satItem{node: n, degree: s.degree[id]})
+	}
+
+	for s.queue.Len() != 0 {
+		it := heap.Pop(&s.queue).(satItem)
+		id := it.node.ID()
+		if _, done := s.colors[id]; done || it.sat != len(s.adj[id]) {
+			// Stale entry; a current entry
+			// has been pushed for the node.
+			continue
+		}
+		c := lowestFree(s.adj[id])
+		if c == k && interchange && s.interchange(it.node, k) {
+			c = lowestFree(s.adj[id])
+		}
+		if c == k {
+			k++
+		}
+		s.setColor(it.node, c)
+	}
+	return s.colors, k
+}
+
+// saturation holds the state of a DSATUR coloring.
+type saturation struct {
+	g      graph.Undirected
+	colors map[int64]int
+
+	// adj holds the number of colored
+	// neighbors of each node with each
+	// color. The saturation of a node is
+	// the number of colors in its entry.
+	adj    map[int64]map[int]int
+	degree map[int64]int
+	queue  satQueue
+}
+
+// setColor colors n with c, updating the saturation of the neighbors of n.
+func (s *saturation) setColor(n graph.Node, c int) {
+	id := n.ID()
+	old, recolor := s.colors[id]
+	s.colors[id] = c
+	for _, v := range s.g.From(n) {
+		vid := v.ID()
+		if vid == id {
+			continue
+		}
+		a := s.adj[vid]
+		if recolor {
+			a[old]--
+			if a[old] == 0 {
+				delete(a, old)
+			}
+		}
+		a[c]++
+		if _, done := s.colors[vid]; !done {
+			heap.Push(&s.queue, satItem{node: v, sat: len(a), degree: s.degree[vid]})
+		}
+	}
+}
+
+// interchange attempts to free a color in 0 to k-1 for the uncolored node n
+// whose neighbors use all k colors by swapping a pair of colors i and j on
+// the Kempe chains that contain the neighbors of n colored i. The swap is
+// made only if none of those chains contains a neighbor of n colored j.
+// It returns whether a color was freed.
+func (s *saturation) interchange(n graph.Node, k int) bool {
+	var neighbors []graph.Node
+	for _, v := range s.g.From(n) {
+		if _, ok := s.colors[v.ID()]; ok && v.ID() != n.ID() {
+			neighbors = append(neighbors, v)
+		}
+	}
+	for i := 0; i < k; i++ {
+	pair:
+		for j := 0; j < k; j++ {
+			if i == j {
+				continue
+			}
+			chain := make(map[int64]graph.Node)
+			for _, v := range neighbors {
+				if s.colors[v.ID()] == i {
+					s.kempeChain(v, i, j, chain)
+				}
+			}
+			for _, v := range neighbors {
+				if _, ok := chain[v.ID()]; ok && s.colors[v.ID()] == j {
+					continue pair
+				}
+			}
+			for _, v := range chain {
+				if s.colors[v.ID()] == i {
+					s.setColor(v, j)
+				} else {
+					s.setColor(v, i)
+				}
+			}
+			return true
+		}
+	}
+	return false
+}
+
+// kempeChain adds the nodes of the connected component of the subgraph
+// induced by the nodes colored i or j that contains n to chain.
+func (s *saturation) kempeChain(n graph.Node, i, j int, chain map[int64]graph.Node) {
+	if _, ok := chain[n.ID()]; ok {
+		return
+	}
+	chain[n.ID()] = n
+	stack := []graph.Node{n}
+	for len(stack) != 0 {
+		u := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		for _, v := range s.g.From(u) {
+			if _, ok := chain[v.ID()]; ok {
+				continue
+			}
+			c, ok := s.colors[v.ID()]
+			if !ok || (c != i && c != j) {
+				continue
+			}
+			chain[v.ID()] = v
+			stack = append(stack, v)
+		}
+	}
+}
+
+// satItem is a DSATUR queue entry.
+type satItem struct {
+	node   graph.Node
+	sat    int
+	degree int
+}
+
+// satQueue is a max-priority queue of nodes ordered by saturation,
+// then degree and then lowest ID.
+type satQueue []satItem
+
+func (q satQueue) Len() int { return len(q) }
+func (q satQueue) Less(i, j int) bool {
+	a, b := q[i], q[j]
+	if a.sat != b.sat {
+		return a.sat > b.sat
+	}
+	if a.degree != b.degree {
+		return a.degree > b.degree
+	}
+	return a.node.ID() < b.node.ID()
+}
+func (q satQueue) Swap(i, j int)       { q[i], q[j] = q[j], q[i] }
+func (q *satQueue) Push(x interface{}) { *q = append(*q, x.(satItem)) }
+func (q *satQueue) Pop() interface{} {
+	old := *q
+	n := len(old) - 1
+	x := old[n]
+	*q = old[:n]
+	return x
+}
+
+// WelshPowell returns a coloring of g found with the Welsh–Powell heuristic
+// and the number of colors used. Colors are numbered from zero. Nodes are
+// colored greedily in order of decreasing degree, breaking ties by lowest ID,
+// each with the lowest color not used by its neighbors. Self edges are
+// ignored.
+//
+// See Welsh, D. J. A. and Powell, M. B. "An upper bound for the chromatic
+// number of a graph and its application to timetabling problems."
+// The Computer Journal 10(1):85–86 (1967).
+func WelshPowell(g graph.Undirected) (colors map[int64]int, k int) {
+	nodes := g.Nodes()
+	degree := make(map[int64]int, len(nodes))
+	for _, n := range nodes {
+		for _, v := range g.From(n) {
+			if v.ID() != n.ID() {
+				degree[n.ID()]++
+			}
+		}
+	}
+	sort.Slice(nodes, func(i, j int) bool {
+		di, dj := degree[nodes[i].ID()], degree[nodes[j].ID()]
+		if di != dj {
+			return di > dj
+		}
+		return nodes[i].ID() < nodes[j].ID()
+	})
+	return greedy(g, nodes)
+}
+
+// RandomizedGreedy returns the coloring of g with the fewest colors found by
+// greedily coloring the nodes in each of the given number of random orders,
+// and the number of colors used. Colors are numbered from zero. If
+// iterations is less than one, a single order is used. If src is not nil it
+// is used as the random source, otherwise rand.Intn is used. Self edges are
+// ignored.
+func RandomizedGreedy(g graph.Undirected, iterations int, src *rand.Rand) (colors map[int64]int, k int) {
+	if iterations < 1 {
+		iterations = 1
+	}
+	shuffle := rand.Shuffle
+	if src != nil {
+		shuffle = src.Shuffle
+	}
+	nodes := g.Nodes()
+	sort.Sort(ordered.ByID(nodes))
+	for i := 0; i < iterations; i++ {
+		shuffle(len(nodes), func(i, j int) { nodes[i], nodes[j] = nodes[j], nodes[i] })
+		c, n := greedy(g, nodes)
+		if colors == nil || n < k {
+			colors, k = c, n
+		}
+	}
+	return colors, k
+}
+
+// greedy colors the nodes of g in the given order, each with the lowest
+// color not used by its colored neighbors.
+func greedy(g graph.Undirected, order []graph.Node) (colors map[int64]int, k int) {
+	colors = make(map[int64]int, len(order))
+	used := make(map[int]int)
+	for _, n := range order {
+		for c := range used {
+			delete(used, c)
+		}
+		for _, v := range g.From(n) {
+			if c, ok := colors[v.ID()]; ok && v.ID() != n.ID() {
+				used[c]++
+			}
+		}
+		c := lowestFree(used)
+		colors[n.ID()] = c
+		if c == k {
+			k++
+		}
+	}
+	return colors, k
+}
+
+// lowestFree returns the lowest non-negative color not in used.
+func lowestFree(used map[int]int) int {
+	c := 0
+	for {
+		if _, ok := used[c]; !ok {
+			return c
+		}
+		c++
+	}
+}
@@ -0,0 +1,189 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package coloring
+
+import (
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/graphs/gen"
+	"gonum.org/v1/gonum/graph/simple"
+)
+
+var coloringTests = []struct {
+	name  string
+	nodes []int64
+	edges [][2]int64
+
+	// chromatic is the chromatic number of the graph.
+	chromatic int
+}{
+	{
+		name:      "empty",
+		chromatic: 0,
+	},
+	{
+		name:      "isolated",
+		nodes:     []int64{0, 1, 2},
+		chromatic: 1,
+	},
+	{
+		name:      "edge",
+		edges:     [][2]int64{{0, 1}},
+		chromatic: 2,
+	},
+	{
+		name:      "path",
+		edges:     [][2]int64{{0, 1}, {1, 2}, {2, 3}},
+		chromatic: 2,
+	},
+	{
+		name:      "even cycle",
+		edges:     [][2]int64{{0, 1}, {1, 2}, {2, 3}, {3, 4}, {4, 5}, {5, 0}},
+		chromatic: 2,
+	},
+	{
+		name:      "odd cycle",
+		edges:     [][2]int64{{0, 1}, {1, 2}, {2, 3}, {3, 4}, {4, 0}},
+		chromatic: 3,
+	},
+	{
+		name: "K4",
+		edges: [][2]int64{
+			{0, 1}, {0, 2}, {0, 3},
+			{1, 2}, {1, 3},
+			{2, 3},
+		},
+		chromatic: 4,
+	},
+	{
+		name: "wheel",
+		edges: [][2]int64{
+			{1, 2}, {2, 3}, {3, 4}, {4, 5}, {5, 1},
+			{0, 1}, {0, 2}, {0, 3}, {0, 4}, {0, 5},
+		},
+		chromatic: 4,
+	},
+	{
+		// The Petersen graph.
+		name: "petersen",
+		edges: [][2]int64{
+			{0, 1}, {1, 2}, {2, 3}, {3, 4}, {4, 0},
+			{0, 5}, {1, 6}, {2, 7}, {3, 8}, {4, 9},
+			{5, 7}, {7, 9}, {9, 6}, {6, 8}, {8, 5},
+		},
+		chromatic: 3,
+	},
+	{
+		// A crown graph is bipartite, but is
+		// colored with n/2 colors by greedy
+		// coloring in the order 0, 1, 2, ...
+		name: "crown",
+		edges: [][2]int64{
+			{0, 3}, {0, 5}, {0, 7},
+			{2, 1}, {2, 5}, {2, 7},
+			{4, 1}, {4, 3}, {4, 7},
+			{6, 1}, {6, 3}, {6, 5},
+		},
+		chromatic: 2,
+	},
+}
+
+var coloringFuncs = []struct {
+	name string
+	fn   func(graph.Undirected) (map[int64]int, int)
+
+	// exactBipartite is true if the function
+	// is guaranteed to color bipartite graphs
+	// with two colors.
+	exactBipartite bool
+}{
+	{name: "DSatur", fn: DSatur, exactBipartite: true},
+	{name: "DSaturInterchange", fn: DSaturInterchange, exactBipartite: true},
+	{name: "WelshPowell", fn: WelshPowell},
+	{name: "RandomizedGreedy", fn: func(g graph.Undirected) (map[int64]int, int) {
+		return RandomizedGreedy(g, 10, rand.New(rand.NewSource(1)))
+	}},
+}
+
+func TestColoring(t *testing.T) {
+	for _, test := range coloringTests {
+		g := simple.NewUndirectedGraph()
+		for _, id := range test.nodes {
+			g.AddNode(simple.Node(id))
+		}
+		for _, e := range test.edges {
+			g.SetEdge(simple.Edge{F: simple.Node(e[0]), T: simple.Node(e[1])})
+		}
+		for _, f := range coloringFuncs {
+			colors, k := f.fn(g)
+			checkColoring(t, test.name+" "+f.name, g, colors, k)
+			if k < test.chromatic {
+				t.Errorf("%s %s: too few colors: got:%d chromatic:%d", test.name, f.name, k, test.chromatic)
+			}
+			if f.exactBipartite && test.chromatic <= 2 && k != test.chromatic {
+				t.Errorf("%s %s: unexpected number of colors for bipartite graph: got:%d want:%d", test.name, f.name, k, test.chromatic)
+			}
+		}
+	}
+}
+
+func TestColoringRandom(t *testing.T) {
+	var dsTotal, dsiTotal int
+	for seed := uint64(1); seed <= 20; seed++ {
+		g := simple.NewUndirectedGraph()
+		err := gen.Gnp(g, 100, 0.1, rand.New(rand.NewSource(seed)))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		ds, dsK := DSatur(g)
+		checkColoring(t, "DSatur", g, ds, dsK)
+		dsi, dsiK := DSaturInterchange(g)
+		checkColoring(t, "DSaturInterchange", g, dsi, dsiK)
+		dsTotal += dsK
+		dsiTotal += dsiK
+		wp, wpK := WelshPowell(g)
+		checkColoring(t, "WelshPowell", g, wp, wpK)
+		rg, rgK := RandomizedGreedy(g, 5, rand.New(rand.NewSource(seed)))
+		checkColoring(t, "RandomizedGreedy", g, rg, rgK)
+	}
+	if dsiTotal >= dsTotal {
+		t.Errorf("interchange did not reduce the total number of colors: got:%d DSatur:%d", dsiTotal, dsTotal)
+	}
+}
+
+// checkColoring checks that colors is a proper coloring of g using
+// exactly the colors 0 to k-1.
+func checkColoring(t *testing.T, name string, g graph.Undirected, colors map[int64]int, k int) {
+	nodes := g.Nodes()
+	if len(colors) != len(nodes) {
+		t.Errorf("%s: unexpected number of colored nodes: got:%d want:%d", name, len(colors), len(nodes))
+	}
+	used := make([]bool, k)
+	for _, n := range nodes {
+		c, ok := colors[n.ID()]
+		if !ok {
+			t.Errorf("%s: node %d not colored", name, n.ID())
+			continue
+		}
+		if c < 0 || k <= c {
+			t.Errorf("%s: color of node %d out of range: got:%d k:%d", name, n.ID(), c, k)
+			continue
+		}
+		used[c] = true
+		for _, v := range g.From(n) {
+			if v.ID() != n.ID() && colors[v.ID()] == c {
+				t.Errorf("%s: adjacent nodes %d and %d share color %d", name, n.ID(), v.ID(), c)
+			}
+		}
+	}
+	for c, u := range used {
+		if !u {
+			t.Errorf("%s: color %d not used", name, c)
+		}
+	}
+}
@@ -0,0 +1,14 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package coloring provides graph coloring functions.
+//
+// The functions in this package find proper vertex colorings, assignments of
+// colors to the nodes of an undirected graph such that no two adjacent nodes
+// share a color. Finding a coloring with the fewest colors is NP-hard, so the
+// functions are heuristics that aim to use few colors quickly. Colorings are
+// useful for problems such as register allocation and scheduling, where
+// colors correspond to resources or time slots that conflicting items must
+// not share.
+package coloring // import "gonum.org/v1/gonum/graph/coloring"
@@ -0,0 +1,149 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package community
+
+import (
+	"sort"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/internal/ordered"
+)
+
+// LabelPropagation returns the communities of the undirected graph g found
+// by asynchronous label propagation as described in Raghavan, Albert and
+// Kumara doi:10.1103/PhysRevE.76.036106.
+//
+// Each node is initially given a unique label. In each sweep the nodes are
+// visited in a random order and each node adopts the label held by the
+// greatest number of its neighbors, with ties broken at random in favor of
+// the current label if it is one of the most frequent labels. If g
+// implements graph.Weighted, labels are weighted by the weight of the edge
+// to each neighbor. Self edges are ignored. Sweeps stop when no label is
+// changed or after maxIter sweeps; if maxIter is less than one, sweeps
+// continue until no label changes. Each sweep takes time linear in the
+// number of edges of g.
+//
+// If src is not nil it is used as the random source, otherwise the
+// global source is used. Given the same graph and a source with the same
+// seed, LabelPropagation returns the same communities. The returned
+// communities are ordered by their lowest node ID and the nodes of each
+// community are sorted by ID.
+func LabelPropagation(g graph.Undirected, maxIter int, src *rand.Rand) [][]graph.Node {
+	var (
+		perm = rand.Perm
+		intn = rand.Intn
+	)
+	if src != nil {
+		perm = src.Perm
+		intn = src.Intn
+	}
+
+	nodes := g.Nodes()
+	sort.Sort(ordered.ByID(nodes))
+	indexOf := make(map[int64]int, len(nodes))
+	for i, n := range nodes {
+		indexOf[n.ID()] = i
+	}
+	wg, isWeighted := g.(graph.Weighted)
+	adj := make([][]int, len(nodes))
+	weights := make([][]float64, len(nodes))
+	for i, u := range nodes {
+		to := g.From(u)
+		sort.Sort(ordered.ByID(to))
+		for _, v := range to {
+			j := indexOf[v.ID()]
+			if j == i {
+				continue
+			}
+			w := 1.0
+			if isWeighted {
+				var ok bool
+				w, ok = wg.Weight(u, v)
+				if !ok {
+					panic("community: unexpected invalid weight")
+				}
+			}
+			adj[i] = append(adj[i], j)
+			weights[i] = append(weights[i], w)
+		}
+	}
+
+	label := make([]int, len(nodes))
+	for i := range label {
+		label[i] = i
+	}
+	var (
+		// count holds the total weight of each label
+		// among the neighbors of the current node, and
+		// seen holds the labels that have been counted.
+		count   = make([]float64, len(nodes))
+		counted = make([]bool, len(nodes))
+		seen    []int
+		best    []int
+	)
+	for iter := 0; maxIter < 1 || iter < maxIter; iter++ {
+		changed := false
+		for _, i := range perm(len(nodes)) {
+			if len(adj[i]) == 0 {
+				continue
+			}
+			seen = seen[:0]
+			for k, j := range adj[i] {
+				l := label[j]
+				if !counted[l] {
+					counted[l] = true
+					seen = append(seen, l)
+				}
+				count[l] += weights[i][k]
+			}
+
+			best = best[:0]
+			var max float64
+			for _, l := range seen {
+				switch c := count[l]; {
+				case len(best) == 0 || c > max:
+					best = append(best[:0], l)
+					max = c
+				case c == max:
+					best = append(best, l)
+				}
+			}
+			keep := false
+			for _, l := range best {
+				if l == label[i] {
+					keep = true
+					break
+				}
+			}
+			for _, l := range seen {
+				count[l] = 0
+				counted[l] = false
+			}
+			if keep {
+				continue
+			}
+			label[i] = best[intn(len(best))]
+			changed = true
+		}
+		if !changed {
+			break
+		}
+	}
+
+	var communities [][]graph.Node
+	index := make(map[int]int)
+	for i, n := range nodes {
+		k, ok := index[label[i]]
+		if !ok {
+			k = len(communities)
+			index[label[i]] = k
+			communities = append(communities, nil)
+		}
+		communities[k] = append(communities[k], n)
+	}
+	return communities
+}
@@ -0,0 +1,155 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package community
+
+import (
+	"reflect"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/simple"
+	"gonum.org/v1/gonum/graph/topo"
+)
+
+var labelPropagationTests = []struct {
+	name string
+	g    []intset
+	want [][]int64
+}{
+	{
+		name: "unconnected",
+		g:    unconnected,
+		want: [][]int64{{0}, {1}, {2}, {3}, {4}, {5}},
+	},
+	{
+		name: "two cliques",
+		g: []intset{
+			0: linksTo(1, 2, 3),
+			1: linksTo(2, 3),
+			2: linksTo(3),
+			3: linksTo(4),
+			4: linksTo(5, 6, 7),
+			5: linksTo(6, 7),
+			6: linksTo(7),
+			7: nil,
+		},
+		want: [][]int64{{0, 1, 2, 3}, {4, 5, 6, 7}},
+	},
+	{
+		name: "two components",
+		g: []intset{
+			0: linksTo(1, 2),
+			1: linksTo(2),
+			2: nil,
+			3: linksTo(4, 5),
+			4: linksTo(5),
+			5: nil,
+		},
+		want: [][]int64{{0, 1, 2}, {3, 4, 5}},
+	},
+}
+
+func TestLabelPropagation(t *testing.T) {
+	for _, test := range labelPropagationTests {
+		g := simple.NewUndirectedGraph()
+		for u, e := range test.g {
+			// Add nodes that are not defined by an edge.
+			if !g.Has(simple.Node(u)) {
+				g.AddNode(simple.Node(u))
+			}
+			for v := range e {
+				g.SetEdge(simple.Edge{F: simple.Node(u), T: simple.Node(v)})
+			}
+		}
+		for seed := uint64(1); seed <= 10; seed++ {
+			got := communityIDs(LabelPropagation(g, 0, rand.New(rand.NewSource(seed))))
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("unexpected communities for %q with seed %d:\ngot: %v\nwant:%v", test.name, seed, got, test.want)
+			}
+		}
+	}
+}
+
+func TestLabelPropagationWeighted(t *testing.T) {
+	// Node 2 is joined to the triangle 3-4-5 more
+	// heavily than to the pair 0-1, so it must take
+	// the label of the triangle.
+	g := simple.NewWeightedUndirectedGraph(0, 0)
+	for _, e := range []simple.WeightedEdge{
+		{F: simple.Node(0), T: simple.Node(1), W: 1},
+		{F: simple.Node(0), T: simple.Node(2), W: 1},
+		{F: simple.Node(1), T: simple.Node(2), W: 1},
+		{F: simple.Node(2), T: simple.Node(3), W: 4},
+		{F: simple.Node(3), T: simple.Node(4), W: 4},
+		{F: simple.Node(3), T: simple.Node(5), W: 4},
+		{F: simple.Node(4), T: simple.Node(5), W: 4},
+	} {
+		g.SetWeightedEdge(e)
+	}
+	for seed := uint64(1); seed <= 10; seed++ {
+		for _, c := range LabelPropagation(g, 0, rand.New(rand.NewSource(seed))) {
+			var has2, has3 bool
+			for _, n := range c {
+				has2 = has2 || n.ID() == 2
+				has3 = has3 || n.ID() == 3
+			}
+			if has2 != has3 {
+				t.Errorf("unexpected community for seed %d: %v", seed, communityIDs([][]graph.Node{c}))
+			}
+		}
+	}
+}
+
+func TestLabelPropagationDeterministic(t *testing.T) {
+	for _, g := range []*simple.UndirectedGraph{dupGraph} {
+		cc := make(map[int64]int)
+		for i, c := range topo.ConnectedComponents(g) {
+			for _, n := range c {
+				cc[n.ID()] = i
+			}
+		}
+		for _, maxIter := range []int{0, 1, 5} {
+			want := LabelPropagation(g, maxIter, rand.New(rand.NewSource(1)))
+			got := LabelPropagation(g, maxIter, rand.New(rand.NewSource(1)))
+			if !reflect.DeepEqual(communityIDs(got), communityIDs(want)) {
+				t.Errorf("unexpected nondeterministic result with maxIter=%d", maxIter)
+			}
+
+			var n int
+			for _, c := range got {
+				n += len(c)
+				for i := 1; i < len(c); i++ {
+					if c[i].ID() <= c[i-1].ID() {
+						t.Errorf("community nodes not sorted by ID with maxIter=%d", maxIter)
+						break
+					}
+				}
+				// Labels cannot cross component boundaries.
+				for _, u := range c {
+					if cc[u.ID()] != cc[c[0].ID()] {
+						t.Errorf("community spans connected components with maxIter=%d", maxIter)
+						break
+					}
+				}
+			}
+			if n != len(g.Nodes()) {
+				t.Errorf("unexpected number of nodes in communities with maxIter=%d: got:%d want:%d", maxIter, n, len(g.Nodes()))
+			}
+		}
+	}
+}
+
+func communityIDs(communities [][]graph.Node) [][]int64 {
+	ids := make([][]int64, len(communities))
+	for i, c := range communities {
+		ids[i] = make([]int64, len(c))
+		for j, n := range c {
+			ids[i][j] = n.ID()
+		}
+	}
+	return ids
+}
@@ -93,6 +93,50 @@ func Modularize(g graph.Graph, resolution float64, src *rand.Rand) ReducedGraph
 	}
 }
 
+// Partition is a partition of the nodes of a graph into communities.
+type Partition struct {
+	// Communities holds the nodes of each
+	// community of the partition.
+	Communities [][]graph.Node
+
+	// Q is the modularity of the partition.
+	Q float64
+}
+
+// Partitions returns the partitions of the original graph described by each
+// level of the hierarchical modularization r, with the modularity of each
+// partition calculated at the given resolution. Partitions are ordered from
+// the lowest level of the hierarchy, holding the smallest communities, to
+// the level of r, so the last partition is r.Communities().
+func Partitions(r ReducedGraph, resolution float64) []Partition {
+	var p []Partition
+	for ; r != nil; r = expanded(r) {
+		p = append(p, Partition{Communities: r.Communities(), Q: Q(r, r.Structure(), resolution)})
+	}
+	for i, j := 0, len(p)-1; i < j; i, j = i+1, j-1 {
+		p[i], p[j] = p[j], p[i]
+	}
+	return p
+}
+
+// expanded returns the next lower level of r, or nil if r is at the
+// lowest level.
+func expanded(r ReducedGraph) ReducedGraph {
+	// The concrete reduced graph types return typed
+	// nil values from Expanded at the lowest level.
+	switch r := r.(type) {
+	case *ReducedUndirected:
+		if r.parent == nil {
+			return nil
+		}
+	case *ReducedDirected:
+		if r.parent == nil {
+			return nil
+		}
+	}
+	return r.Expanded()
+}
+
 // Multiplex is a multiplex graph.
 type Multiplex interface {
 	// Nodes returns the slice of nodes
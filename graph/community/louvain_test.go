@@ -6,6 +6,8 @@ package community
 
 import (
 	"fmt"
+	"math"
+	"testing"
 
 	"golang.org/x/exp/rand"
 
@@ -276,3 +278,43 @@ func init() {
 		}
 	}
 }
+
+func TestPartitions(t *testing.T) {
+	for _, test := range []struct {
+		name string
+		g    graph.Graph
+	}{
+		{name: "undirected", g: dupGraph},
+		{name: "directed", g: dupGraphDirected},
+	} {
+		r := Modularize(test.g, 1, rand.New(rand.NewSource(1)))
+
+		var levels int
+		for p := r; p != nil; p = expanded(p) {
+			levels++
+		}
+		got := Partitions(r, 1)
+		if len(got) != levels {
+			t.Fatalf("unexpected number of partitions for %s graph: got:%d want:%d", test.name, len(got), levels)
+		}
+		for i, p := range got {
+			var n int
+			for _, c := range p.Communities {
+				n += len(c)
+			}
+			if n != len(test.g.Nodes()) {
+				t.Errorf("unexpected number of nodes in level %d of %s graph: got:%d want:%d", i, test.name, n, len(test.g.Nodes()))
+			}
+			want := Q(test.g, p.Communities, 1)
+			if math.Abs(p.Q-want) > 1e-12 {
+				t.Errorf("unexpected modularity for level %d of %s graph: got:%v want:%v", i, test.name, p.Q, want)
+			}
+			if i != 0 && p.Q < got[i-1].Q-1e-12 {
+				t.Errorf("unexpected decrease in modularity at level %d of %s graph: %v < %v", i, test.name, p.Q, got[i-1].Q)
+			}
+		}
+		if want := Q(test.g, r.Communities(), 1); math.Abs(got[len(got)-1].Q-want) > 1e-12 {
+			t.Errorf("unexpected modularity for highest level of %s graph: got:%v want:%v", test.name, got[len(got)-1].Q, want)
+		}
+	}
+}
@@ -0,0 +1,135 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package concurrent
+
+import (
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/simple"
+)
+
+// DirectedGraph implements a generalized directed graph that is
+// safe for concurrent use by multiple goroutines.
+type DirectedGraph struct {
+	sharded
+}
+
+// NewDirectedGraph returns a DirectedGraph.
+func NewDirectedGraph() *DirectedGraph {
+	g := &DirectedGraph{}
+	g.init(true)
+	return g
+}
+
+// NewEdge returns a new Edge from the source to the destination node.
+func (g *DirectedGraph) NewEdge(from, to graph.Node) graph.Edge {
+	return &simple.Edge{F: from, T: to}
+}
+
+// SetEdge adds e, an edge from one node to another. If the nodes do not exist, they are added.
+// It will panic if the IDs of the e.From and e.To are equal.
+func (g *DirectedGraph) SetEdge(e graph.Edge) {
+	var (
+		from = e.From()
+		fid  = from.ID()
+		to   = e.To()
+		tid  = to.ID()
+	)
+
+	if fid == tid {
+		panic("concurrent: adding self edge")
+	}
+
+	sf, st, unlock := g.lockPair(fid, tid)
+	defer unlock()
+	if _, ok := sf.nodes[fid]; !ok {
+		g.addNode(sf, from)
+	}
+	if _, ok := st.nodes[tid]; !ok {
+		g.addNode(st, to)
+	}
+
+	sf.from[fid][tid] = e
+	st.to[tid][fid] = e
+}
+
+// RemoveEdge removes e from the graph, leaving the terminal nodes. If the edge does not exist
+// it is a no-op.
+func (g *DirectedGraph) RemoveEdge(e graph.Edge) {
+	fid, tid := e.From().ID(), e.To().ID()
+	sf, st, unlock := g.lockPair(fid, tid)
+	defer unlock()
+	if _, ok := sf.nodes[fid]; !ok {
+		return
+	}
+	if _, ok := st.nodes[tid]; !ok {
+		return
+	}
+
+	delete(sf.from[fid], tid)
+	delete(st.to[tid], fid)
+}
+
+// Edges returns all the edges in the graph.
+func (g *DirectedGraph) Edges() []graph.Edge {
+	var edges []graph.Edge
+	for i := range g.shards {
+		sh := &g.shards[i]
+		sh.RLock()
+		for _, from := range sh.from {
+			for _, e := range from {
+				edges = append(edges, e)
+			}
+		}
+		sh.RUnlock()
+	}
+	return edges
+}
+
+// From returns all nodes in g that can be reached directly from n.
+func (g *DirectedGraph) From(n graph.Node) []graph.Node {
+	return g.adjacent(n, fromLists)
+}
+
+// To returns all nodes in g that can reach directly to n.
+func (g *DirectedGraph) To(n graph.Node) []graph.Node {
+	return g.adjacent(n, toLists)
+}
+
+// HasEdgeBetween returns whether an edge exists between nodes x and y without
+// considering direction.
+func (g *DirectedGraph) HasEdgeBetween(x, y graph.Node) bool {
+	if _, ok := g.edge(x.ID(), y.ID()); ok {
+		return true
+	}
+	_, ok := g.edge(y.ID(), x.ID())
+	return ok
+}
+
+// Edge returns the edge from u to v if such an edge exists and nil otherwise.
+// The node v must be directly reachable from u as defined by the From method.
+func (g *DirectedGraph) Edge(u, v graph.Node) graph.Edge {
+	edge, ok := g.edge(u.ID(), v.ID())
+	if !ok {
+		return nil
+	}
+	return edge
+}
+
+// HasEdgeFromTo returns whether an edge exists in the graph from u to v.
+func (g *DirectedGraph) HasEdgeFromTo(u, v graph.Node) bool {
+	_, ok := g.edge(u.ID(), v.ID())
+	return ok
+}
+
+// Degree returns the in+out degree of n in g.
+func (g *DirectedGraph) Degree(n graph.Node) int {
+	sh := g.shard(n.ID())
+	sh.RLock()
+	defer sh.RUnlock()
+	if _, ok := sh.nodes[n.ID()]; !ok {
+		return 0
+	}
+	return len(sh.from[n.ID()]) + len(sh.to[n.ID()])
+}
@@ -0,0 +1,285 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package concurrent
+
+import (
+	"sort"
+	"sync"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/internal/ordered"
+	"gonum.org/v1/gonum/graph/simple"
+)
+
+var (
+	directedGraph = (*DirectedGraph)(nil)
+
+	_ graph.Graph           = directedGraph
+	_ graph.Directed        = directedGraph
+	_ graph.DirectedBuilder = directedGraph
+	_ graph.NodeRemover     = directedGraph
+	_ graph.EdgeRemover     = directedGraph
+)
+
+// randomEdges returns n random edges between nodes with IDs in [0, nodes).
+func randomEdges(n, nodes int, seed uint64) []simple.Edge {
+	rnd := rand.New(rand.NewSource(seed))
+	edges := make([]simple.Edge, 0, n)
+	for len(edges) < n {
+		u, v := rnd.Intn(nodes), rnd.Intn(nodes)
+		if u == v {
+			continue
+		}
+		edges = append(edges, simple.Edge{F: simple.Node(u), T: simple.Node(v)})
+	}
+	return edges
+}
+
+// ingest adds the edges to g from the given number of goroutines.
+func ingest(g graph.EdgeAdder, edges []simple.Edge, workers int) {
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := w; i < len(edges); i += workers {
+				g.SetEdge(edges[i])
+			}
+		}(w)
+	}
+	wg.Wait()
+}
+
+func TestDirectedConcurrentIngest(t *testing.T) {
+	edges := randomEdges(5000, 500, 1)
+	g := NewDirectedGraph()
+	ingest(g, edges, 8)
+
+	want := simple.NewDirectedGraph()
+	for _, e := range edges {
+		want.SetEdge(e)
+	}
+	if !graph.Equal(g, want) {
+		t.Errorf("concurrently built graph differs from sequentially built graph: %+v", graph.Diff(g, want))
+	}
+	if got := len(g.Edges()); got != len(want.Edges()) {
+		t.Errorf("unexpected number of edges: got:%d want:%d", got, len(want.Edges()))
+	}
+	for _, n := range want.Nodes() {
+		if g.Degree(n) != want.Degree(n) {
+			t.Errorf("unexpected degree of node %d: got:%d want:%d", n.ID(), g.Degree(n), want.Degree(n))
+		}
+		to := g.To(n)
+		sort.Sort(ordered.ByID(to))
+		wantTo := want.To(n)
+		sort.Sort(ordered.ByID(wantTo))
+		if len(to) != len(wantTo) {
+			t.Errorf("unexpected number of nodes to %d: got:%d want:%d", n.ID(), len(to), len(wantTo))
+			continue
+		}
+		for i := range to {
+			if to[i].ID() != wantTo[i].ID() {
+				t.Errorf("unexpected nodes to %d: got:%v want:%v", n.ID(), to, wantTo)
+				break
+			}
+		}
+	}
+}
+
+func TestDirectedConcurrentNewNode(t *testing.T) {
+	g := NewDirectedGraph()
+	g.AddNode(simple.Node(10))
+
+	const workers, perWorker = 8, 100
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < perWorker; i++ {
+				g.AddNode(g.NewNode())
+			}
+		}()
+	}
+	wg.Wait()
+	if got := len(g.Nodes()); got != workers*perWorker+1 {
+		t.Errorf("unexpected number of nodes: got:%d want:%d", got, workers*perWorker+1)
+	}
+}
+
+func TestDirectedNewNodeReuse(t *testing.T) {
+	g := NewDirectedGraph()
+	for i := 0; i < 2*numShards; i++ {
+		g.AddNode(simple.Node(i))
+	}
+	// Remove a node from every shard so that
+	// NewNode finds a free ID in any shard.
+	for i := 0; i < numShards; i++ {
+		g.RemoveNode(simple.Node(i))
+	}
+	n := g.NewNode()
+	if n.ID() >= numShards {
+		t.Errorf("removed ID not reused: got:%d", n.ID())
+	}
+	if g.Has(n) {
+		t.Errorf("node %d added by NewNode", n.ID())
+	}
+
+	const workers, perWorker = 8, 100
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < perWorker; i++ {
+				g.AddNode(g.NewNode())
+			}
+		}()
+	}
+	wg.Wait()
+	if got, want := len(g.Nodes()), numShards+workers*perWorker; got != want {
+		t.Errorf("unexpected number of nodes: got:%d want:%d", got, want)
+	}
+}
+
+func TestDirectedConcurrentNewNodeRelease(t *testing.T) {
+	g := NewDirectedGraph()
+	for i := 0; i < numShards; i++ {
+		g.AddNode(simple.Node(i))
+	}
+	for i := 0; i < numShards; i++ {
+		g.RemoveNode(simple.Node(i))
+	}
+
+	// Each worker holds at most one ID at a time, so
+	// the released IDs are always enough to go round.
+	const workers, perWorker = 8, 200
+	var wg sync.WaitGroup
+	errs := make(chan int64, workers)
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < perWorker; i++ {
+				n := g.NewNode()
+				if n.ID() >= numShards {
+					errs <- n.ID()
+					return
+				}
+				g.AddNode(n)
+				g.RemoveNode(n)
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for id := range errs {
+		t.Errorf("released ID not reused: got new ID %d", id)
+	}
+
+	// No released ID has been lost.
+	seen := make(map[int64]bool)
+	for i := 0; i < numShards; i++ {
+		n := g.NewNode()
+		if n.ID() >= numShards || seen[n.ID()] {
+			t.Errorf("unexpected new node ID: %d", n.ID())
+		}
+		seen[n.ID()] = true
+	}
+}
+
+func TestDirectedConcurrentReadWrite(t *testing.T) {
+	edges := randomEdges(2000, 200, 2)
+	g := NewDirectedGraph()
+
+	var wg sync.WaitGroup
+	done := make(chan struct{})
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for {
+			select {
+			case <-done:
+				return
+			default:
+			}
+			// The graph is being modified, so only
+			// check that queries do not race.
+			for _, n := range g.Nodes() {
+				for _, v := range g.From(n) {
+					g.HasEdgeBetween(n, v)
+					g.Edge(n, v)
+				}
+				g.To(n)
+				g.Degree(n)
+			}
+		}
+	}()
+	ingest(g, edges, 4)
+	for _, e := range edges[:100] {
+		g.RemoveEdge(e)
+	}
+	for i := 0; i < 10; i++ {
+		g.RemoveNode(simple.Node(i))
+	}
+	close(done)
+	wg.Wait()
+
+	want := simple.NewDirectedGraph()
+	for _, e := range edges {
+		want.SetEdge(e)
+	}
+	for _, e := range edges[:100] {
+		want.RemoveEdge(e)
+	}
+	for i := 0; i < 10; i++ {
+		want.RemoveNode(simple.Node(i))
+	}
+	if !graph.Equal(g, want) {
+		t.Errorf("graph differs from sequentially built graph: %+v", graph.Diff(g, want))
+	}
+}
+
+func TestDirectedGraph(t *testing.T) {
+	g := NewDirectedGraph()
+	g.SetEdge(simple.Edge{F: simple.Node(0), T: simple.Node(1)})
+	g.SetEdge(simple.Edge{F: simple.Node(1), T: simple.Node(2)})
+	if !g.HasEdgeFromTo(simple.Node(0), simple.Node(1)) || g.HasEdgeFromTo(simple.Node(1), simple.Node(0)) {
+		t.Error("unexpected edge direction")
+	}
+	if !g.HasEdgeBetween(simple.Node(1), simple.Node(0)) {
+		t.Error("expected edge between 1 and 0")
+	}
+	if e := g.Edge(simple.Node(1), simple.Node(0)); e != nil {
+		t.Errorf("unexpected edge from 1 to 0: %v", e)
+	}
+	if n := g.NewNode(); n.ID() != 3 {
+		t.Errorf("unexpected new node ID: got:%d want:3", n.ID())
+	}
+	g.RemoveNode(simple.Node(1))
+	if g.Has(simple.Node(1)) || len(g.Edges()) != 0 || g.Degree(simple.Node(0)) != 0 {
+		t.Errorf("unexpected graph after node removal: nodes:%v edges:%v", g.Nodes(), g.Edges())
+	}
+	if g.From(simple.Node(1)) != nil {
+		t.Error("unexpected neighbors of removed node")
+	}
+
+	panics := func(fn func()) (ok bool) {
+		defer func() {
+			ok = recover() != nil
+		}()
+		fn()
+		return
+	}
+	if !panics(func() { g.AddNode(simple.Node(0)) }) {
+		t.Error("expected panic for node ID collision")
+	}
+	if !panics(func() { g.SetEdge(simple.Edge{F: simple.Node(0), T: simple.Node(0)}) }) {
+		t.Error("expected panic for self edge")
+	}
+}
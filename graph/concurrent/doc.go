@@ -0,0 +1,15 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package concurrent provides graph implementations that are safe for
+// concurrent use by multiple goroutines.
+//
+// The graphs partition their nodes and adjacency lists into shards that are
+// locked independently, so goroutines adding nodes and edges to different
+// parts of a graph do not contend for a single lock. Single node and edge
+// queries are consistent with all completed mutations. Queries returning
+// collections of nodes or edges visit the shards in turn and so reflect a
+// consistent state of the graph only if it is not being concurrently
+// modified.
+package concurrent // import "gonum.org/v1/gonum/graph/concurrent"
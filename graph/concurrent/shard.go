@@ -0,0 +1,278 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package concurrent
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/internal/set"
+	"gonum.org/v1/gonum/graph/simple"
+)
+
+// numShards is the number of independently
+// locked partitions of a graph.
+const numShards = 64
+
+// shard holds the nodes and adjacency lists of the
+// graph nodes whose IDs map to the shard.
+type shard struct {
+	sync.RWMutex
+
+	nodes map[int64]graph.Node
+	from  map[int64]map[int64]graph.Edge
+	to    map[int64]map[int64]graph.Edge
+
+	// free holds the IDs of nodes removed from
+	// the shard that may be handed out by NewNode.
+	free set.Int64s
+}
+
+// sharded is the sharded storage shared by the graph implementations.
+// Undirected graphs store all edges in from and leave to nil.
+type sharded struct {
+	// next is greater than the ID of every node
+	// ever added to the graph and nfree is the
+	// number of IDs held in the shard free sets.
+	// They are accessed atomically and are placed
+	// first to ensure 64-bit alignment.
+	next  int64
+	nfree int64
+
+	// pick selects the shard NewNode looks
+	// in for a free ID.
+	pick uint32
+
+	directed bool
+	shards   [numShards]shard
+}
+
+func (s *sharded) init(directed bool) {
+	s.directed = directed
+	for i := range s.shards {
+		s.shards[i].nodes = make(map[int64]graph.Node)
+		s.shards[i].from = make(map[int64]map[int64]graph.Edge)
+		s.shards[i].free = make(set.Int64s)
+		if directed {
+			s.shards[i].to = make(map[int64]map[int64]graph.Edge)
+		}
+	}
+}
+
+// shardIndex returns the index of the shard holding the node with the given ID.
+func shardIndex(id int64) int {
+	return int(uint64(id) % numShards)
+}
+
+// shard returns the shard holding the node with the given ID.
+func (s *sharded) shard(id int64) *shard {
+	return &s.shards[shardIndex(id)]
+}
+
+// lockPair write locks the shards holding the nodes with IDs
+// u and v, returning the shards and a function to unlock them.
+// Shards are locked in index order to avoid deadlock.
+func (s *sharded) lockPair(u, v int64) (su, sv *shard, unlock func()) {
+	i, j := shardIndex(u), shardIndex(v)
+	su, sv = &s.shards[i], &s.shards[j]
+	switch {
+	case i == j:
+		su.Lock()
+		return su, sv, su.Unlock
+	case i < j:
+		su.Lock()
+		sv.Lock()
+	default:
+		sv.Lock()
+		su.Lock()
+	}
+	return su, sv, func() {
+		su.Unlock()
+		sv.Unlock()
+	}
+}
+
+// lockAll write locks all the shards.
+func (s *sharded) lockAll() {
+	for i := range s.shards {
+		s.shards[i].Lock()
+	}
+}
+
+// unlockAll unlocks all the shards.
+func (s *sharded) unlockAll() {
+	for i := range s.shards {
+		s.shards[i].Unlock()
+	}
+}
+
+// use records that the given ID is in use.
+func (s *sharded) use(id int64) {
+	for {
+		next := atomic.LoadInt64(&s.next)
+		if id < next || atomic.CompareAndSwapInt64(&s.next, next, id+1) {
+			return
+		}
+	}
+}
+
+// NewNode returns a new unique Node to be added to g. The Node's ID does
+// not become valid in g until the Node is added to g. Nodes returned by
+// concurrent calls to NewNode have distinct IDs. The non-negative IDs of
+// removed nodes are returned by NewNode before new IDs are allocated. An
+// ID returned by NewNode is not returned again unless it is added to g
+// and then removed.
+func (s *sharded) NewNode() graph.Node {
+	// Search the shards for a free ID starting from the
+	// shard selected by pick to spread contention, holding
+	// at most one shard lock at a time.
+	start := atomic.AddUint32(&s.pick, 1)
+	for k := uint32(0); k < numShards && atomic.LoadInt64(&s.nfree) != 0; k++ {
+		sh := &s.shards[(start+k)%numShards]
+		sh.Lock()
+		for id := range sh.free {
+			delete(sh.free, id)
+			atomic.AddInt64(&s.nfree, -1)
+			sh.Unlock()
+			return simple.Node(id)
+		}
+		sh.Unlock()
+	}
+	id := atomic.AddInt64(&s.next, 1) - 1
+	if id < 0 {
+		panic("concurrent: cannot allocate node: no slot")
+	}
+	return simple.Node(id)
+}
+
+// AddNode adds n to the graph. It panics if the added node ID matches an existing node ID.
+func (s *sharded) AddNode(n graph.Node) {
+	sh := s.shard(n.ID())
+	sh.Lock()
+	defer sh.Unlock()
+	if _, exists := sh.nodes[n.ID()]; exists {
+		panic(fmt.Sprintf("concurrent: node ID collision: %d", n.ID()))
+	}
+	s.addNode(sh, n)
+}
+
+// addNode adds n to the locked shard sh.
+func (s *sharded) addNode(sh *shard, n graph.Node) {
+	sh.nodes[n.ID()] = n
+	sh.from[n.ID()] = make(map[int64]graph.Edge)
+	if s.directed {
+		sh.to[n.ID()] = make(map[int64]graph.Edge)
+	}
+	if sh.free.Has(n.ID()) {
+		sh.free.Remove(n.ID())
+		atomic.AddInt64(&s.nfree, -1)
+	}
+	s.use(n.ID())
+}
+
+// RemoveNode removes n from the graph, as well as any edges attached to it. If the node
+// is not in the graph it is a no-op. RemoveNode locks the entire graph.
+func (s *sharded) RemoveNode(n graph.Node) {
+	id := n.ID()
+	s.lockAll()
+	defer s.unlockAll()
+	sh := s.shard(id)
+	if _, ok := sh.nodes[id]; !ok {
+		return
+	}
+	delete(sh.nodes, id)
+
+	if s.directed {
+		for to := range sh.from[id] {
+			delete(s.shard(to).to[to], id)
+		}
+		for from := range sh.to[id] {
+			delete(s.shard(from).from[from], id)
+		}
+		delete(sh.to, id)
+	} else {
+		for v := range sh.from[id] {
+			delete(s.shard(v).from[v], id)
+		}
+	}
+	delete(sh.from, id)
+
+	if id >= 0 {
+		sh.free.Add(id)
+		atomic.AddInt64(&s.nfree, 1)
+	}
+}
+
+// Node returns the node in the graph with the given ID.
+func (s *sharded) Node(id int64) graph.Node {
+	sh := s.shard(id)
+	sh.RLock()
+	defer sh.RUnlock()
+	return sh.nodes[id]
+}
+
+// Has returns whether the node exists within the graph.
+func (s *sharded) Has(n graph.Node) bool {
+	sh := s.shard(n.ID())
+	sh.RLock()
+	defer sh.RUnlock()
+	_, ok := sh.nodes[n.ID()]
+	return ok
+}
+
+// Nodes returns all the nodes in the graph.
+func (s *sharded) Nodes() []graph.Node {
+	var nodes []graph.Node
+	for i := range s.shards {
+		sh := &s.shards[i]
+		sh.RLock()
+		for _, n := range sh.nodes {
+			nodes = append(nodes, n)
+		}
+		sh.RUnlock()
+	}
+	return nodes
+}
+
+// adjacent returns the nodes of the graph with IDs
+// in the keys of the adjacency list of n held in adj.
+func (s *sharded) adjacent(n graph.Node, adj func(*shard) map[int64]map[int64]graph.Edge) []graph.Node {
+	sh := s.shard(n.ID())
+	sh.RLock()
+	edges, ok := adj(sh)[n.ID()]
+	if !ok {
+		sh.RUnlock()
+		return nil
+	}
+	ids := make([]int64, 0, len(edges))
+	for id := range edges {
+		ids = append(ids, id)
+	}
+	sh.RUnlock()
+
+	nodes := make([]graph.Node, 0, len(ids))
+	for _, id := range ids {
+		// The node may have been removed
+		// since the shard was unlocked.
+		if v := s.Node(id); v != nil {
+			nodes = append(nodes, v)
+		}
+	}
+	return nodes
+}
+
+// edge returns the edge from u to v in the from adjacency lists.
+func (s *sharded) edge(u, v int64) (graph.Edge, bool) {
+	sh := s.shard(u)
+	sh.RLock()
+	defer sh.RUnlock()
+	e, ok := sh.from[u][v]
+	return e, ok
+}
+
+func fromLists(sh *shard) map[int64]map[int64]graph.Edge { return sh.from }
+func toLists(sh *shard) map[int64]map[int64]graph.Edge   { return sh.to }
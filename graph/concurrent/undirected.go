@@ -0,0 +1,130 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package concurrent
+
+import (
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/simple"
+)
+
+// UndirectedGraph implements a generalized undirected graph that is
+// safe for concurrent use by multiple goroutines.
+type UndirectedGraph struct {
+	sharded
+}
+
+// NewUndirectedGraph returns an UndirectedGraph.
+func NewUndirectedGraph() *UndirectedGraph {
+	g := &UndirectedGraph{}
+	g.init(false)
+	return g
+}
+
+// NewEdge returns a new Edge from the source to the destination node.
+func (g *UndirectedGraph) NewEdge(from, to graph.Node) graph.Edge {
+	return &simple.Edge{F: from, T: to}
+}
+
+// SetEdge adds e, an edge from one node to another. If the nodes do not exist, they are added.
+// It will panic if the IDs of the e.From and e.To are equal.
+func (g *UndirectedGraph) SetEdge(e graph.Edge) {
+	var (
+		from = e.From()
+		fid  = from.ID()
+		to   = e.To()
+		tid  = to.ID()
+	)
+
+	if fid == tid {
+		panic("concurrent: adding self edge")
+	}
+
+	sf, st, unlock := g.lockPair(fid, tid)
+	defer unlock()
+	if _, ok := sf.nodes[fid]; !ok {
+		g.addNode(sf, from)
+	}
+	if _, ok := st.nodes[tid]; !ok {
+		g.addNode(st, to)
+	}
+
+	sf.from[fid][tid] = e
+	st.from[tid][fid] = e
+}
+
+// RemoveEdge removes e from the graph, leaving the terminal nodes. If the edge does not exist
+// it is a no-op.
+func (g *UndirectedGraph) RemoveEdge(e graph.Edge) {
+	fid, tid := e.From().ID(), e.To().ID()
+	sf, st, unlock := g.lockPair(fid, tid)
+	defer unlock()
+	if _, ok := sf.nodes[fid]; !ok {
+		return
+	}
+	if _, ok := st.nodes[tid]; !ok {
+		return
+	}
+
+	delete(sf.from[fid], tid)
+	delete(st.from[tid], fid)
+}
+
+// Edges returns all the edges in the graph.
+func (g *UndirectedGraph) Edges() []graph.Edge {
+	var edges []graph.Edge
+	for i := range g.shards {
+		sh := &g.shards[i]
+		sh.RLock()
+		for uid, u := range sh.from {
+			for vid, e := range u {
+				// Each edge is held in the adjacency
+				// lists of both its nodes, so only
+				// report it from one of them.
+				if uid < vid {
+					edges = append(edges, e)
+				}
+			}
+		}
+		sh.RUnlock()
+	}
+	return edges
+}
+
+// From returns all nodes in g that can be reached directly from n.
+func (g *UndirectedGraph) From(n graph.Node) []graph.Node {
+	return g.adjacent(n, fromLists)
+}
+
+// HasEdgeBetween returns whether an edge exists between nodes x and y.
+func (g *UndirectedGraph) HasEdgeBetween(x, y graph.Node) bool {
+	_, ok := g.edge(x.ID(), y.ID())
+	return ok
+}
+
+// Edge returns the edge from u to v if such an edge exists and nil otherwise.
+// The node v must be directly reachable from u as defined by the From method.
+func (g *UndirectedGraph) Edge(u, v graph.Node) graph.Edge {
+	return g.EdgeBetween(u, v)
+}
+
+// EdgeBetween returns the edge between nodes x and y.
+func (g *UndirectedGraph) EdgeBetween(x, y graph.Node) graph.Edge {
+	edge, ok := g.edge(x.ID(), y.ID())
+	if !ok {
+		return nil
+	}
+	return edge
+}
+
+// Degree returns the degree of n in g.
+func (g *UndirectedGraph) Degree(n graph.Node) int {
+	sh := g.shard(n.ID())
+	sh.RLock()
+	defer sh.RUnlock()
+	if _, ok := sh.nodes[n.ID()]; !ok {
+		return 0
+	}
+	return len(sh.from[n.ID()])
+}
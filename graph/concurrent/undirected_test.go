@@ -0,0 +1,74 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package concurrent
+
+import (
+	"testing"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/simple"
+)
+
+var (
+	undirectedGraph = (*UndirectedGraph)(nil)
+
+	_ graph.Graph             = undirectedGraph
+	_ graph.Undirected        = undirectedGraph
+	_ graph.UndirectedBuilder = undirectedGraph
+	_ graph.NodeRemover       = undirectedGraph
+	_ graph.EdgeRemover       = undirectedGraph
+)
+
+func TestUndirectedConcurrentIngest(t *testing.T) {
+	edges := randomEdges(5000, 500, 3)
+	g := NewUndirectedGraph()
+	ingest(g, edges, 8)
+
+	want := simple.NewUndirectedGraph()
+	for _, e := range edges {
+		want.SetEdge(e)
+	}
+	if !graph.Equal(g, want) {
+		t.Errorf("concurrently built graph differs from sequentially built graph: %+v", graph.Diff(g, want))
+	}
+	if got := len(g.Edges()); got != len(want.Edges()) {
+		t.Errorf("unexpected number of edges: got:%d want:%d", got, len(want.Edges()))
+	}
+	for _, n := range want.Nodes() {
+		if g.Degree(n) != want.Degree(n) {
+			t.Errorf("unexpected degree of node %d: got:%d want:%d", n.ID(), g.Degree(n), want.Degree(n))
+		}
+	}
+
+	for _, e := range edges[:100] {
+		g.RemoveEdge(e)
+		want.RemoveEdge(e)
+	}
+	for i := 0; i < 10; i++ {
+		g.RemoveNode(simple.Node(i))
+		want.RemoveNode(simple.Node(i))
+	}
+	if !graph.Equal(g, want) {
+		t.Errorf("graph differs after removals: %+v", graph.Diff(g, want))
+	}
+}
+
+func TestUndirectedGraph(t *testing.T) {
+	g := NewUndirectedGraph()
+	g.SetEdge(simple.Edge{F: simple.Node(0), T: simple.Node(1)})
+	if !g.HasEdgeBetween(simple.Node(1), simple.Node(0)) {
+		t.Error("expected edge between 1 and 0")
+	}
+	if e := g.EdgeBetween(simple.Node(1), simple.Node(0)); e == nil || e.From().ID() != 0 {
+		t.Errorf("unexpected edge between 1 and 0: %v", e)
+	}
+	if len(g.Edges()) != 1 {
+		t.Errorf("unexpected edges: %v", g.Edges())
+	}
+	g.RemoveEdge(simple.Edge{F: simple.Node(1), T: simple.Node(0)})
+	if g.HasEdgeBetween(simple.Node(0), simple.Node(1)) || !g.Has(simple.Node(0)) {
+		t.Error("unexpected graph after edge removal")
+	}
+}
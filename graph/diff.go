@@ -0,0 +1,125 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package graph
+
+import "sort"
+
+// Difference holds the structural differences between two graphs, a and b,
+// as returned by Diff.
+type Difference struct {
+	// NodesOnlyInA and NodesOnlyInB hold
+	// the nodes that are present in only
+	// one of the graphs, sorted by ID.
+	NodesOnlyInA, NodesOnlyInB []Node
+
+	// EdgesOnlyInA and EdgesOnlyInB hold
+	// the edges that are present in only
+	// one of the graphs, sorted by the IDs
+	// of their end points.
+	EdgesOnlyInA, EdgesOnlyInB []Edge
+
+	// WeightMismatches holds the pairs of
+	// edges from a and b that are present
+	// in both graphs with different weights,
+	// sorted by the IDs of their end points.
+	WeightMismatches [][2]WeightedEdge
+}
+
+// Empty returns whether d holds no differences.
+func (d Difference) Empty() bool {
+	return len(d.NodesOnlyInA) == 0 && len(d.NodesOnlyInB) == 0 &&
+		len(d.EdgesOnlyInA) == 0 && len(d.EdgesOnlyInB) == 0 &&
+		len(d.WeightMismatches) == 0
+}
+
+// Equal returns whether the graphs a and b have the same nodes and edges,
+// with the same weights if both graphs are Weighted. Nodes and edges are
+// compared by node ID.
+func Equal(a, b Graph) bool {
+	return Diff(a, b).Empty()
+}
+
+// Diff returns the nodes and edges that are present in one of the graphs
+// a and b but not the other and, if both graphs are Weighted, the edges
+// present in both with different weights. Nodes and edges are compared by
+// node ID, and edges are identified by the end points returned by From, so
+// an edge u->v is considered to be present in a graph if Edge(u, v) is not
+// nil. If both graphs are Undirected each edge is reported once.
+func Diff(a, b Graph) Difference {
+	var d Difference
+	d.NodesOnlyInA = nodesNotIn(a, b)
+	d.NodesOnlyInB = nodesNotIn(b, a)
+	d.EdgesOnlyInA = edgesNotIn(a, b)
+	d.EdgesOnlyInB = edgesNotIn(b, a)
+
+	aw, aok := a.(Weighted)
+	bw, bok := b.(Weighted)
+	if !aok || !bok {
+		return d
+	}
+	_, undirected := a.(Undirected)
+	if _, ok := b.(Undirected); !ok {
+		undirected = false
+	}
+	for _, u := range sortedNodes(a.Nodes()) {
+		if !b.Has(u) {
+			continue
+		}
+		for _, v := range sortedNodes(a.From(u)) {
+			if undirected && v.ID() < u.ID() {
+				continue
+			}
+			if !b.Has(v) || b.Edge(u, v) == nil {
+				continue
+			}
+			ae := aw.WeightedEdge(u, v)
+			be := bw.WeightedEdge(u, v)
+			if ae.Weight() != be.Weight() {
+				d.WeightMismatches = append(d.WeightMismatches, [2]WeightedEdge{ae, be})
+			}
+		}
+	}
+	return d
+}
+
+// nodesNotIn returns the nodes of a that are not in b, sorted by ID.
+func nodesNotIn(a, b Graph) []Node {
+	var nodes []Node
+	for _, n := range sortedNodes(a.Nodes()) {
+		if !b.Has(n) {
+			nodes = append(nodes, n)
+		}
+	}
+	return nodes
+}
+
+// edgesNotIn returns the edges of a that are not in b, sorted by the IDs
+// of their end points. If a and b are both undirected, each edge is
+// returned once.
+func edgesNotIn(a, b Graph) []Edge {
+	_, undirected := a.(Undirected)
+	if _, ok := b.(Undirected); !ok {
+		undirected = false
+	}
+	var edges []Edge
+	for _, u := range sortedNodes(a.Nodes()) {
+		for _, v := range sortedNodes(a.From(u)) {
+			if undirected && v.ID() < u.ID() {
+				continue
+			}
+			if b.Has(u) && b.Has(v) && b.Edge(u, v) != nil {
+				continue
+			}
+			edges = append(edges, a.Edge(u, v))
+		}
+	}
+	return edges
+}
+
+// sortedNodes sorts nodes by ID and returns it.
+func sortedNodes(nodes []Node) []Node {
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].ID() < nodes[j].ID() })
+	return nodes
+}
@@ -0,0 +1,147 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package graph_test
+
+import (
+	"reflect"
+	"testing"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/simple"
+)
+
+// edgeIDs returns the end point IDs of the edges.
+func edgeIDs(edges []graph.Edge) [][2]int64 {
+	var ids [][2]int64
+	for _, e := range edges {
+		ids = append(ids, [2]int64{e.From().ID(), e.To().ID()})
+	}
+	return ids
+}
+
+// undirectedEdgeIDs returns the end point IDs of the edges
+// with the lower ID first.
+func undirectedEdgeIDs(edges []graph.Edge) [][2]int64 {
+	ids := edgeIDs(edges)
+	for i, e := range ids {
+		if e[0] > e[1] {
+			ids[i] = [2]int64{e[1], e[0]}
+		}
+	}
+	return ids
+}
+
+// nodeIDs returns the IDs of the nodes.
+func nodeIDs(nodes []graph.Node) []int64 {
+	var ids []int64
+	for _, n := range nodes {
+		ids = append(ids, n.ID())
+	}
+	return ids
+}
+
+func TestDiff(t *testing.T) {
+	a := simple.NewUndirectedGraph()
+	b := simple.NewUndirectedGraph()
+	for _, e := range []simple.Edge{
+		{F: simple.Node(0), T: simple.Node(1)},
+		{F: simple.Node(1), T: simple.Node(2)},
+		{F: simple.Node(2), T: simple.Node(3)},
+	} {
+		a.SetEdge(e)
+		b.SetEdge(e)
+	}
+	a.AddNode(simple.Node(10))
+	if !graph.Equal(a, a) {
+		t.Error("graph not equal to itself")
+	}
+	if graph.Equal(a, b) {
+		t.Error("unexpected equality of graphs with different nodes")
+	}
+	b.AddNode(simple.Node(10))
+	if !graph.Equal(a, b) {
+		t.Error("unexpected inequality of equal graphs")
+	}
+
+	a.SetEdge(simple.Edge{F: simple.Node(3), T: simple.Node(0)})
+	b.RemoveEdge(simple.Edge{F: simple.Node(1), T: simple.Node(2)})
+	b.SetEdge(simple.Edge{F: simple.Node(4), T: simple.Node(3)})
+	d := graph.Diff(a, b)
+	if got := nodeIDs(d.NodesOnlyInA); got != nil {
+		t.Errorf("unexpected nodes only in a: got:%v", got)
+	}
+	if got, want := nodeIDs(d.NodesOnlyInB), []int64{4}; !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected nodes only in b: got:%v want:%v", got, want)
+	}
+	if got, want := undirectedEdgeIDs(d.EdgesOnlyInA), [][2]int64{{0, 3}, {1, 2}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected edges only in a: got:%v want:%v", got, want)
+	}
+	if got, want := undirectedEdgeIDs(d.EdgesOnlyInB), [][2]int64{{3, 4}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected edges only in b: got:%v want:%v", got, want)
+	}
+	if d.Empty() {
+		t.Error("unexpected empty difference")
+	}
+
+	// Directed graphs distinguish edge direction.
+	da := simple.NewDirectedGraph()
+	db := simple.NewDirectedGraph()
+	da.SetEdge(simple.Edge{F: simple.Node(0), T: simple.Node(1)})
+	db.SetEdge(simple.Edge{F: simple.Node(1), T: simple.Node(0)})
+	d = graph.Diff(da, db)
+	if got, want := edgeIDs(d.EdgesOnlyInA), [][2]int64{{0, 1}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected directed edges only in a: got:%v want:%v", got, want)
+	}
+	if got, want := edgeIDs(d.EdgesOnlyInB), [][2]int64{{1, 0}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected directed edges only in b: got:%v want:%v", got, want)
+	}
+
+	// A directed graph with edges in both directions
+	// is equal to the corresponding undirected graph.
+	db.SetEdge(simple.Edge{F: simple.Node(0), T: simple.Node(1)})
+	u := simple.NewUndirectedGraph()
+	u.SetEdge(simple.Edge{F: simple.Node(0), T: simple.Node(1)})
+	if !graph.Equal(db, u) {
+		t.Error("unexpected inequality of directed and undirected graphs")
+	}
+}
+
+func TestDiffWeighted(t *testing.T) {
+	a := simple.NewWeightedUndirectedGraph(0, 0)
+	b := simple.NewWeightedUndirectedGraph(0, 0)
+	for _, e := range []simple.WeightedEdge{
+		{F: simple.Node(0), T: simple.Node(1), W: 1},
+		{F: simple.Node(1), T: simple.Node(2), W: 2},
+		{F: simple.Node(2), T: simple.Node(3), W: 3},
+	} {
+		a.SetWeightedEdge(e)
+		b.SetWeightedEdge(e)
+	}
+	if !graph.Equal(a, b) {
+		t.Error("unexpected inequality of equal weighted graphs")
+	}
+	b.SetWeightedEdge(simple.WeightedEdge{F: simple.Node(2), T: simple.Node(1), W: 5})
+	d := graph.Diff(a, b)
+	if len(d.WeightMismatches) != 1 {
+		t.Fatalf("unexpected number of weight mismatches: got:%d want:1", len(d.WeightMismatches))
+	}
+	m := d.WeightMismatches[0]
+	if m[0].Weight() != 2 || m[1].Weight() != 5 {
+		t.Errorf("unexpected mismatched weights: got:%v and %v want:2 and 5", m[0].Weight(), m[1].Weight())
+	}
+	if from, to := m[0].From().ID(), m[0].To().ID(); from+to != 3 || from*to != 2 {
+		t.Errorf("unexpected mismatched edge: got:%d--%d want:1--2", from, to)
+	}
+	if len(d.EdgesOnlyInA) != 0 || len(d.EdgesOnlyInB) != 0 {
+		t.Errorf("unexpected edge differences: %v %v", edgeIDs(d.EdgesOnlyInA), edgeIDs(d.EdgesOnlyInB))
+	}
+
+	// Weights are not compared when one graph is unweighted.
+	u := simple.NewUndirectedGraph()
+	graph.Copy(u, b)
+	if !graph.Equal(a, u) {
+		t.Error("unexpected inequality of weighted and unweighted graphs")
+	}
+}
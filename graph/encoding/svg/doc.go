@@ -0,0 +1,10 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package svg implements rendering of laid out graphs as SVG images.
+//
+// The renderer is intentionally minimal and is intended to allow small
+// graphs to be visualized without depending on an external GraphViz
+// installation.
+package svg // import "gonum.org/v1/gonum/graph/encoding/svg"
@@ -0,0 +1,293 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package svg
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/internal/ordered"
+)
+
+// Layout is a two-dimensional graph node layout.
+type Layout interface {
+	// Coord returns the coordinates of the
+	// node with the given ID.
+	Coord(id int64) (x, y float64)
+}
+
+// LayoutFunc is a function type that implements Layout.
+type LayoutFunc func(id int64) (x, y float64)
+
+// Coord returns f(id).
+func (f LayoutFunc) Coord(id int64) (x, y float64) { return f(id) }
+
+// NodeStyle holds the rendering style of a node.
+type NodeStyle struct {
+	// Radius is the radius of the
+	// node's circle.
+	Radius float64
+
+	// Fill and Stroke are the SVG fill
+	// and stroke colors of the node.
+	Fill, Stroke string
+
+	// StrokeWidth is the width of
+	// the node's circle stroke.
+	StrokeWidth float64
+
+	// Label is rendered centered
+	// on the node if not empty.
+	Label string
+}
+
+// EdgeStyle holds the rendering style of an edge.
+type EdgeStyle struct {
+	// Stroke is the SVG stroke
+	// color of the edge.
+	Stroke string
+
+	// StrokeWidth is the width
+	// of the edge's line.
+	StrokeWidth float64
+}
+
+// Style specifies how a graph is rendered. Zero values for fields
+// are replaced with their documented defaults.
+type Style struct {
+	// Width and Height are the dimensions
+	// of the rendered image. The default
+	// dimensions are 400×400.
+	Width, Height float64
+
+	// Margin is the distance between
+	// the node layout bounding box and
+	// the image edge. The default margin
+	// is 20.
+	Margin float64
+
+	// Node and Edge return the style
+	// for each node and edge. If nil,
+	// DefaultNodeStyle is used for
+	// all nodes and the DefaultEdgeStyle
+	// is used for all edges.
+	Node func(graph.Node) NodeStyle
+	Edge func(graph.Edge) EdgeStyle
+}
+
+var (
+	// DefaultNodeStyle is the style used for nodes
+	// when no Node style function is provided. Zero
+	// fields of a NodeStyle returned by a style
+	// function are filled from DefaultNodeStyle.
+	DefaultNodeStyle = NodeStyle{Radius: 5, Fill: "white", Stroke: "black", StrokeWidth: 1}
+
+	// DefaultEdgeStyle is the style used for edges
+	// when no Edge style function is provided. Zero
+	// fields of an EdgeStyle returned by a style
+	// function are filled from DefaultEdgeStyle.
+	DefaultEdgeStyle = EdgeStyle{Stroke: "black", StrokeWidth: 1}
+)
+
+// ErrBadCoord is returned when a layout returns a non-finite coordinate.
+var ErrBadCoord = errors.New("svg: non-finite node coordinate")
+
+// Marshal returns the SVG rendering of the graph g with node positions
+// given by l and rendering style s. If s is nil, a default style is used.
+//
+// Node coordinates are scaled uniformly to fit in the image and the y-axis
+// is inverted so that increasing y is rendered upwards. If g is a
+// graph.Directed, edges are rendered with arrow heads.
+func Marshal(g graph.Graph, l Layout, s *Style) ([]byte, error) {
+	var buf bytes.Buffer
+	err := Encode(&buf, g, l, s)
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Encode writes the SVG rendering of the graph g to w. See Marshal for
+// details of the rendering.
+func Encode(w io.Writer, g graph.Graph, l Layout, s *Style) error {
+	var style Style
+	if s != nil {
+		style = *s
+	}
+	if style.Width == 0 {
+		style.Width = 400
+	}
+	if style.Height == 0 {
+		style.Height = 400
+	}
+	if style.Margin == 0 {
+		style.Margin = 20
+	}
+
+	nodes := g.Nodes()
+	sort.Sort(ordered.ByID(nodes))
+
+	type point struct{ x, y float64 }
+	pos := make(map[int64]point, len(nodes))
+	minX, minY := math.Inf(1), math.Inf(1)
+	maxX, maxY := math.Inf(-1), math.Inf(-1)
+	for _, n := range nodes {
+		x, y := l.Coord(n.ID())
+		if math.IsNaN(x) || math.IsInf(x, 0) || math.IsNaN(y) || math.IsInf(y, 0) {
+			return ErrBadCoord
+		}
+		pos[n.ID()] = point{x, y}
+		minX, maxX = math.Min(minX, x), math.Max(maxX, x)
+		minY, maxY = math.Min(minY, y), math.Max(maxY, y)
+	}
+
+	// Scale uniformly so that the layout fits within
+	// the margins, centering it in the image.
+	innerW := style.Width - 2*style.Margin
+	innerH := style.Height - 2*style.Margin
+	scale := 1.0
+	if dx, dy := maxX-minX, maxY-minY; dx > 0 || dy > 0 {
+		scale = math.Inf(1)
+		if dx > 0 {
+			scale = innerW / dx
+		}
+		if dy > 0 {
+			scale = math.Min(scale, innerH/dy)
+		}
+	}
+	cx, cy := (minX+maxX)/2, (minY+maxY)/2
+	transform := func(p point) point {
+		return point{
+			x: style.Width/2 + (p.x-cx)*scale,
+			y: style.Height/2 - (p.y-cy)*scale,
+		}
+	}
+
+	p := printer{w: w}
+	p.printf(`<svg xmlns="http://www.w3.org/2000/svg" width="%s" height="%s" viewBox="0 0 %[1]s %[2]s">`+"\n",
+		ftoa(style.Width), ftoa(style.Height))
+
+	_, directed := g.(graph.Directed)
+	if directed {
+		p.printf(`<defs><marker id="arrow" viewBox="0 0 10 10" refX="10" refY="5" markerWidth="6" markerHeight="6" orient="auto"><path d="M0,0 L10,5 L0,10 z"/></marker></defs>` + "\n")
+	}
+
+	nodeStyle := func(n graph.Node) NodeStyle {
+		if style.Node == nil {
+			return DefaultNodeStyle
+		}
+		ns := style.Node(n)
+		if ns.Radius == 0 {
+			ns.Radius = DefaultNodeStyle.Radius
+		}
+		if ns.Fill == "" {
+			ns.Fill = DefaultNodeStyle.Fill
+		}
+		if ns.Stroke == "" {
+			ns.Stroke = DefaultNodeStyle.Stroke
+		}
+		if ns.StrokeWidth == 0 {
+			ns.StrokeWidth = DefaultNodeStyle.StrokeWidth
+		}
+		return ns
+	}
+	edgeStyle := func(e graph.Edge) EdgeStyle {
+		if style.Edge == nil {
+			return DefaultEdgeStyle
+		}
+		es := style.Edge(e)
+		if es.Stroke == "" {
+			es.Stroke = DefaultEdgeStyle.Stroke
+		}
+		if es.StrokeWidth == 0 {
+			es.StrokeWidth = DefaultEdgeStyle.StrokeWidth
+		}
+		return es
+	}
+
+	radius := make(map[int64]float64, len(nodes))
+	for _, n := range nodes {
+		radius[n.ID()] = nodeStyle(n).Radius
+	}
+
+	p.printf("<g>\n")
+	for _, u := range nodes {
+		to := g.From(u)
+		sort.Sort(ordered.ByID(to))
+		for _, v := range to {
+			if !directed && v.ID() < u.ID() {
+				// Only render undirected edges once.
+				continue
+			}
+			e := g.Edge(u, v)
+			es := edgeStyle(e)
+			a := transform(pos[u.ID()])
+			b := transform(pos[v.ID()])
+			if directed {
+				// Shorten the line so that the
+				// arrow head meets the node circle.
+				dx, dy := b.x-a.x, b.y-a.y
+				if d := math.Hypot(dx, dy); d > 0 {
+					r := radius[v.ID()]
+					b.x -= dx / d * r
+					b.y -= dy / d * r
+				}
+			}
+			p.printf(`<line x1="%s" y1="%s" x2="%s" y2="%s" stroke="%s" stroke-width="%s"`,
+				ftoa(a.x), ftoa(a.y), ftoa(b.x), ftoa(b.y), escape(es.Stroke), ftoa(es.StrokeWidth))
+			if directed {
+				p.printf(` marker-end="url(#arrow)"`)
+			}
+			p.printf("/>\n")
+		}
+	}
+	p.printf("</g>\n<g>\n")
+	for _, n := range nodes {
+		ns := nodeStyle(n)
+		c := transform(pos[n.ID()])
+		p.printf(`<circle cx="%s" cy="%s" r="%s" fill="%s" stroke="%s" stroke-width="%s"/>`+"\n",
+			ftoa(c.x), ftoa(c.y), ftoa(ns.Radius), escape(ns.Fill), escape(ns.Stroke), ftoa(ns.StrokeWidth))
+		if ns.Label != "" {
+			p.printf(`<text x="%s" y="%s" text-anchor="middle" dominant-baseline="central">%s</text>`+"\n",
+				ftoa(c.x), ftoa(c.y), escape(ns.Label))
+		}
+	}
+	p.printf("</g>\n</svg>\n")
+
+	return p.err
+}
+
+// printer is an error-capturing formatted writer.
+type printer struct {
+	w   io.Writer
+	err error
+}
+
+func (p *printer) printf(format string, args ...interface{}) {
+	if p.err != nil {
+		return
+	}
+	_, p.err = fmt.Fprintf(p.w, format, args...)
+}
+
+// ftoa returns the shortest decimal representation of f rounded
+// to four decimal places.
+func ftoa(f float64) string {
+	return strconv.FormatFloat(math.Floor(f*1e4+0.5)/1e4, 'f', -1, 64)
+}
+
+// escape returns s escaped for use as XML attribute or text content.
+func escape(s string) string {
+	var buf bytes.Buffer
+	xml.EscapeText(&buf, []byte(s))
+	return buf.String()
+}
@@ -0,0 +1,121 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package svg
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/simple"
+)
+
+var square = map[int64][2]float64{
+	0: {0, 0},
+	1: {1, 0},
+	2: {1, 1},
+	3: {0, 1},
+}
+
+func squareLayout(id int64) (x, y float64) {
+	p := square[id]
+	return p[0], p[1]
+}
+
+var encodeTests = []struct {
+	name   string
+	g      func() graph.Graph
+	layout Layout
+	style  *Style
+
+	want string
+}{
+	{
+		name: "undirected",
+		g: func() graph.Graph {
+			g := simple.NewUndirectedGraph()
+			g.SetEdge(simple.Edge{F: simple.Node(0), T: simple.Node(1)})
+			g.SetEdge(simple.Edge{F: simple.Node(1), T: simple.Node(2)})
+			g.AddNode(simple.Node(3))
+			return g
+		},
+		layout: LayoutFunc(squareLayout),
+		style:  &Style{Width: 100, Height: 100, Margin: 10},
+
+		want: `<svg xmlns="http://www.w3.org/2000/svg" width="100" height="100" viewBox="0 0 100 100">
+<g>
+<line x1="10" y1="90" x2="90" y2="90" stroke="black" stroke-width="1"/>
+<line x1="90" y1="90" x2="90" y2="10" stroke="black" stroke-width="1"/>
+</g>
+<g>
+<circle cx="10" cy="90" r="5" fill="white" stroke="black" stroke-width="1"/>
+<circle cx="90" cy="90" r="5" fill="white" stroke="black" stroke-width="1"/>
+<circle cx="90" cy="10" r="5" fill="white" stroke="black" stroke-width="1"/>
+<circle cx="10" cy="10" r="5" fill="white" stroke="black" stroke-width="1"/>
+</g>
+</svg>
+`,
+	},
+	{
+		name: "directed styled",
+		g: func() graph.Graph {
+			g := simple.NewDirectedGraph()
+			g.SetEdge(simple.Edge{F: simple.Node(0), T: simple.Node(1)})
+			g.SetEdge(simple.Edge{F: simple.Node(1), T: simple.Node(0)})
+			return g
+		},
+		layout: LayoutFunc(squareLayout),
+		style: &Style{
+			Width: 100, Height: 60, Margin: 10,
+			Node: func(n graph.Node) NodeStyle {
+				return NodeStyle{Radius: 4, Fill: "red", Label: fmt.Sprintf("<%d>", n.ID())}
+			},
+			Edge: func(e graph.Edge) EdgeStyle {
+				if e.From().ID() == 0 {
+					return EdgeStyle{Stroke: "blue", StrokeWidth: 2}
+				}
+				return EdgeStyle{}
+			},
+		},
+
+		want: `<svg xmlns="http://www.w3.org/2000/svg" width="100" height="60" viewBox="0 0 100 60">
+<defs><marker id="arrow" viewBox="0 0 10 10" refX="10" refY="5" markerWidth="6" markerHeight="6" orient="auto"><path d="M0,0 L10,5 L0,10 z"/></marker></defs>
+<g>
+<line x1="10" y1="30" x2="86" y2="30" stroke="blue" stroke-width="2" marker-end="url(#arrow)"/>
+<line x1="90" y1="30" x2="14" y2="30" stroke="black" stroke-width="1" marker-end="url(#arrow)"/>
+</g>
+<g>
+<circle cx="10" cy="30" r="4" fill="red" stroke="black" stroke-width="1"/>
+<text x="10" y="30" text-anchor="middle" dominant-baseline="central">&lt;0&gt;</text>
+<circle cx="90" cy="30" r="4" fill="red" stroke="black" stroke-width="1"/>
+<text x="90" y="30" text-anchor="middle" dominant-baseline="central">&lt;1&gt;</text>
+</g>
+</svg>
+`,
+	},
+}
+
+func TestMarshal(t *testing.T) {
+	for _, test := range encodeTests {
+		got, err := Marshal(test.g(), test.layout, test.style)
+		if err != nil {
+			t.Errorf("unexpected error for %q: %v", test.name, err)
+			continue
+		}
+		if string(got) != test.want {
+			t.Errorf("unexpected SVG for %q:\ngot:\n%s\nwant:\n%s", test.name, got, test.want)
+		}
+	}
+}
+
+func TestMarshalBadCoord(t *testing.T) {
+	g := simple.NewUndirectedGraph()
+	g.AddNode(simple.Node(0))
+	_, err := Marshal(g, LayoutFunc(func(int64) (x, y float64) { return math.NaN(), 0 }), nil)
+	if err != ErrBadCoord {
+		t.Errorf("unexpected error for NaN coordinate: got:%v want:%v", err, ErrBadCoord)
+	}
+}
@@ -0,0 +1,353 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package flow
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/internal/ordered"
+	"gonum.org/v1/gonum/graph/simple"
+)
+
+// Flow is a flow in a capacitated directed graph.
+type Flow struct {
+	// Value is the total flow from
+	// the source to the sink.
+	Value float64
+
+	nodes   []graph.Node
+	indexOf map[int64]int
+
+	// flow holds the positive flow along
+	// each edge indexed by node indices.
+	flow []map[int]float64
+
+	// sourceSide holds whether each node
+	// is on the source side of the cut.
+	sourceSide []bool
+}
+
+// FlowOn returns the flow along the edge from u to v.
+func (f Flow) FlowOn(u, v graph.Node) float64 {
+	i, ok := f.indexOf[u.ID()]
+	if !ok {
+		return 0
+	}
+	j, ok := f.indexOf[v.ID()]
+	if !ok {
+		return 0
+	}
+	return f.flow[i][j]
+}
+
+// Edges returns the edges carrying a positive flow as weighted edges with
+// weights equal to the flow along the edge. Edges are sorted by from and
+// then to node ID.
+func (f Flow) Edges() []graph.WeightedEdge {
+	var edges []graph.WeightedEdge
+	for i, u := range f.nodes {
+		var to []int
+		for j := range f.flow[i] {
+			to = append(to, j)
+		}
+		sort.Ints(to)
+		for _, j := range to {
+			edges = append(edges, simple.WeightedEdge{F: u, T: f.nodes[j], W: f.flow[i][j]})
+		}
+	}
+	return edges
+}
+
+// MinCut returns the partition of the nodes of the graph into the source
+// side and the sink side of a minimum cut. The total capacity of the edges
+// from the source side to the sink side is equal to the flow value. Nodes
+// are sorted by ID.
+func (f Flow) MinCut() (source, sink []graph.Node) {
+	for i, n := range f.nodes {
+		if f.sourceSide[i] {
+			source = append(source, n)
+		} else {
+			sink = append(sink, n)
+		}
+	}
+	return source, sink
+}
+
+// EdmondsKarp returns a maximum flow from s to t in g using the Edmonds-Karp
+// algorithm, repeatedly augmenting the flow along shortest residual paths.
+// The capacity of each edge is given by its weight. EdmondsKarp will panic
+// if s and t are the same node or g has a negative edge weight.
+//
+// The time complexity of EdmondsKarp is O(|V|.|E|^2).
+func EdmondsKarp(g graph.WeightedDirected, s, t graph.Node) Flow {
+	r, si, ti := newResidual(g, s, t)
+	if si < 0 || ti < 0 {
+		return r.flowResult(si)
+	}
+
+	n := len(r.nodes)
+	parent := make([]int, n)
+	for {
+		// Find a shortest augmenting path.
+		for i := range parent {
+			parent[i] = -1
+		}
+		parent[si] = si
+		queue := []int{si}
+		for len(queue) != 0 && parent[ti] == -1 {
+			u := queue[0]
+			queue = queue[1:]
+			for _, v := range r.adj[u] {
+				if parent[v] == -1 && r.residual(u, v) > r.tol(u, v) {
+					parent[v] = u
+					queue = append(queue, v)
+				}
+			}
+		}
+		if parent[ti] == -1 {
+			break
+		}
+
+		bottleneck := math.Inf(1)
+		for v := ti; v != si; v = parent[v] {
+			bottleneck = math.Min(bottleneck, r.residual(parent[v], v))
+		}
+		for v := ti; v != si; v = parent[v] {
+			r.push(parent[v], v, bottleneck)
+		}
+	}
+
+	return r.flowResult(si)
+}
+
+// PushRelabel returns a maximum flow from s to t in g using the FIFO
+// push-relabel algorithm of Goldberg and Tarjan doi:10.1145/48014.61051.
+// The capacity of each edge is given by its weight. PushRelabel will panic
+// if s and t are the same node or g has a negative edge weight.
+//
+// The time complexity of PushRelabel is O(|V|^3).
+func PushRelabel(g graph.WeightedDirected, s, t graph.Node) Flow {
+	r, si, ti := newResidual(g, s, t)
+	if si < 0 || ti < 0 {
+		return r.flowResult(si)
+	}
+
+	n := len(r.nodes)
+	height := make([]int, n)
+	excess := make([]float64, n)
+	current := make([]int, n)
+	height[si] = n
+
+	var queue []int
+	active := make([]bool, n)
+	activate := func(v int) {
+		if !active[v] && v != si && v != ti && excess[v] > 0 {
+			active[v] = true
+			queue = append(queue, v)
+		}
+	}
+
+	// Saturate all edges leaving the source.
+	for _, v := range r.adj[si] {
+		c := r.residual(si, v)
+		if c > r.tol(si, v) {
+			r.push(si, v, c)
+			excess[v] += c
+			excess[si] -= c
+			activate(v)
+		}
+	}
+
+	for len(queue) != 0 {
+		u := queue[0]
+		queue = queue[1:]
+		active[u] = false
+
+		// Discharge u.
+		for excess[u] > 0 {
+			if current[u] == len(r.adj[u]) {
+				// Relabel u to allow a push.
+				minHeight := math.MaxInt32
+				for _, v := range r.adj[u] {
+					if r.residual(u, v) > r.tol(u, v) && height[v] < minHeight {
+						minHeight = height[v]
+					}
+				}
+				if minHeight == math.MaxInt32 {
+					// The excess is due to rounding error
+					// and cannot be returned to the source.
+					excess[u] = 0
+					break
+				}
+				height[u] = minHeight + 1
+				current[u] = 0
+				continue
+			}
+			v := r.adj[u][current[u]]
+			if c := r.residual(u, v); c > r.tol(u, v) && height[u] == height[v]+1 {
+				d := math.Min(excess[u], c)
+				r.push(u, v, d)
+				excess[u] -= d
+				excess[v] += d
+				activate(v)
+				continue
+			}
+			current[u]++
+		}
+	}
+
+	return r.flowResult(si)
+}
+
+// residual is a residual network.
+type residual struct {
+	nodes   []graph.Node
+	indexOf map[int64]int
+
+	// adj holds the neighbors of each node
+	// ignoring edge direction, and cap and
+	// flow hold the capacities and skew
+	// symmetric flows between nodes.
+	adj  [][]int
+	cap  []map[int]float64
+	flow []map[int]float64
+}
+
+// newResidual returns the residual network for g with zero flow and the
+// indices of s and t. If either s or t is not in g, its index is -1.
+func newResidual(g graph.WeightedDirected, s, t graph.Node) (r *residual, si, ti int) {
+	if s.ID() == t.ID() {
+		panic("flow: source and sink are the same node")
+	}
+	nodes := g.Nodes()
+	sort.Sort(ordered.ByID(nodes))
+	r = &residual{
+		nodes:   nodes,
+		indexOf: make(map[int64]int, len(nodes)),
+		adj:     make([][]int, len(nodes)),
+		cap:     make([]map[int]float64, len(nodes)),
+		flow:    make([]map[int]float64, len(nodes)),
+	}
+	for i, n := range nodes {
+		r.indexOf[n.ID()] = i
+		r.cap[i] = make(map[int]float64)
+		r.flow[i] = make(map[int]float64)
+	}
+	for i, u := range nodes {
+		to := g.From(u)
+		sort.Sort(ordered.ByID(to))
+		for _, v := range to {
+			j := r.indexOf[v.ID()]
+			if i == j {
+				continue
+			}
+			c := g.WeightedEdge(u, v).Weight()
+			if c < 0 {
+				panic(fmt.Sprintf("flow: negative capacity on edge %d->%d", u.ID(), v.ID()))
+			}
+			r.link(i, j)
+			r.cap[i][j] = c
+		}
+	}
+
+	si, ti = -1, -1
+	if idx, ok := r.indexOf[s.ID()]; ok {
+		si = idx
+	}
+	if idx, ok := r.indexOf[t.ID()]; ok {
+		ti = idx
+	}
+	return r, si, ti
+}
+
+// link adds i and j to each other's adjacency lists
+// if they are not already adjacent.
+func (r *residual) link(i, j int) {
+	if _, ok := r.flow[i][j]; ok {
+		return
+	}
+	r.flow[i][j] = 0
+	r.flow[j][i] = 0
+	r.adj[i] = append(r.adj[i], j)
+	r.adj[j] = append(r.adj[j], i)
+}
+
+// residual returns the residual capacity from u to v.
+func (r *residual) residual(u, v int) float64 {
+	return r.cap[u][v] - r.flow[u][v]
+}
+
+// tol returns the residual capacity at or below which the arc from u
+// to v is considered saturated. The tolerance is relative to the larger
+// of the capacities between u and v, and is zero if either is infinite.
+func (r *residual) tol(u, v int) float64 {
+	return tolerance(math.Max(r.cap[u][v], r.cap[v][u]))
+}
+
+// push sends f units of flow from u to v. If f is equal to the
+// residual capacity from u to v, the arc is exactly saturated.
+func (r *residual) push(u, v int, f float64) {
+	if f == r.residual(u, v) {
+		r.flow[u][v] = r.cap[u][v]
+		r.flow[v][u] = -r.cap[u][v]
+		return
+	}
+	r.flow[u][v] += f
+	r.flow[v][u] -= f
+}
+
+// flowResult returns the Flow held by r with the minimum cut
+// found by search from the source in the residual network.
+func (r *residual) flowResult(si int) Flow {
+	f := Flow{
+		nodes:      r.nodes,
+		indexOf:    r.indexOf,
+		flow:       make([]map[int]float64, len(r.nodes)),
+		sourceSide: make([]bool, len(r.nodes)),
+	}
+	for i := range r.nodes {
+		for j, fl := range r.flow[i] {
+			if fl <= r.tol(i, j) {
+				continue
+			}
+			if f.flow[i] == nil {
+				f.flow[i] = make(map[int]float64)
+			}
+			f.flow[i][j] = fl
+		}
+	}
+	if si < 0 {
+		return f
+	}
+	for _, fl := range r.flow[si] {
+		f.Value += fl
+	}
+
+	f.sourceSide[si] = true
+	queue := []int{si}
+	for len(queue) != 0 {
+		u := queue[0]
+		queue = queue[1:]
+		for _, v := range r.adj[u] {
+			if !f.sourceSide[v] && r.residual(u, v) > r.tol(u, v) {
+				f.sourceSide[v] = true
+				queue = append(queue, v)
+			}
+		}
+	}
+	return f
+}
+
+// tolerance returns the residual capacity at or below which an arc
+// with the given capacity is considered saturated.
+func tolerance(capacity float64) float64 {
+	if math.IsInf(capacity, 1) {
+		return 0
+	}
+	return 1e-12 * capacity
+}
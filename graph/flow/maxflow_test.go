@@ -0,0 +1,224 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package flow
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/simple"
+)
+
+var maxFlowTests = []struct {
+	name  string
+	edges []simple.WeightedEdge
+	s, t  int64
+	want  float64
+	cut   []int64
+}{
+	{
+		// Figure 26.1 of Cormen, Leiserson, Rivest
+		// and Stein "Introduction to Algorithms".
+		name: "CLRS",
+		edges: []simple.WeightedEdge{
+			{F: simple.Node(0), T: simple.Node(1), W: 16},
+			{F: simple.Node(0), T: simple.Node(2), W: 13},
+			{F: simple.Node(2), T: simple.Node(1), W: 4},
+			{F: simple.Node(1), T: simple.Node(3), W: 12},
+			{F: simple.Node(3), T: simple.Node(2), W: 9},
+			{F: simple.Node(2), T: simple.Node(4), W: 14},
+			{F: simple.Node(4), T: simple.Node(3), W: 7},
+			{F: simple.Node(3), T: simple.Node(5), W: 20},
+			{F: simple.Node(4), T: simple.Node(5), W: 4},
+		},
+		s: 0, t: 5,
+		want: 23,
+		cut:  []int64{0, 1, 2, 4},
+	},
+	{
+		name: "antiparallel",
+		edges: []simple.WeightedEdge{
+			{F: simple.Node(0), T: simple.Node(1), W: 3},
+			{F: simple.Node(1), T: simple.Node(2), W: 2},
+			{F: simple.Node(2), T: simple.Node(1), W: 5},
+			{F: simple.Node(0), T: simple.Node(2), W: 4},
+			{F: simple.Node(1), T: simple.Node(3), W: 4},
+			{F: simple.Node(2), T: simple.Node(3), W: 1},
+		},
+		s: 0, t: 3,
+		want: 5,
+		cut:  []int64{0, 1, 2},
+	},
+	{
+		name: "disconnected",
+		edges: []simple.WeightedEdge{
+			{F: simple.Node(0), T: simple.Node(1), W: 3},
+			{F: simple.Node(2), T: simple.Node(3), W: 3},
+		},
+		s: 0, t: 3,
+		want: 0,
+		cut:  []int64{0, 1},
+	},
+	{
+		name: "infinite capacity",
+		edges: []simple.WeightedEdge{
+			{F: simple.Node(0), T: simple.Node(1), W: 3},
+			{F: simple.Node(1), T: simple.Node(2), W: math.Inf(1)},
+			{F: simple.Node(2), T: simple.Node(3), W: 2},
+		},
+		s: 0, t: 3,
+		want: 2,
+		cut:  []int64{0, 1, 2},
+	},
+	{
+		// The small capacities must not be treated
+		// as saturated relative to the large ones.
+		name: "mixed scale",
+		edges: []simple.WeightedEdge{
+			{F: simple.Node(0), T: simple.Node(1), W: 1 << 20},
+			{F: simple.Node(1), T: simple.Node(3), W: 1 << 20},
+			{F: simple.Node(0), T: simple.Node(2), W: 1.0 / (1 << 27)},
+			{F: simple.Node(2), T: simple.Node(3), W: 1.0 / (1 << 27)},
+		},
+		s: 0, t: 3,
+		want: 1<<20 + 1.0/(1<<27),
+		cut:  []int64{0},
+	},
+	{
+		name: "missing sink",
+		edges: []simple.WeightedEdge{
+			{F: simple.Node(0), T: simple.Node(1), W: 3},
+		},
+		s: 0, t: 5,
+		want: 0,
+		cut:  []int64{0, 1},
+	},
+}
+
+var maxFlowFuncs = []struct {
+	name string
+	fn   func(graph.WeightedDirected, graph.Node, graph.Node) Flow
+}{
+	{name: "EdmondsKarp", fn: EdmondsKarp},
+	{name: "PushRelabel", fn: PushRelabel},
+}
+
+func TestMaxFlow(t *testing.T) {
+	for _, test := range maxFlowTests {
+		g := simple.NewWeightedDirectedGraph(0, 0)
+		for _, e := range test.edges {
+			g.SetWeightedEdge(e)
+		}
+		for _, alg := range maxFlowFuncs {
+			name := fmt.Sprintf("%s %q", alg.name, test.name)
+			f := alg.fn(g, simple.Node(test.s), simple.Node(test.t))
+			if f.Value != test.want {
+				t.Errorf("unexpected flow value for %s: got:%v want:%v", name, f.Value, test.want)
+			}
+			src, _ := f.MinCut()
+			var cut []int64
+			for _, n := range src {
+				cut = append(cut, n.ID())
+			}
+			if fmt.Sprint(cut) != fmt.Sprint(test.cut) {
+				t.Errorf("unexpected source side of cut for %s: got:%v want:%v", name, cut, test.cut)
+			}
+			if g.Has(simple.Node(test.t)) {
+				checkFlow(t, name, g, f, simple.Node(test.s), simple.Node(test.t), 1e-12)
+			}
+		}
+	}
+}
+
+func TestMaxFlowRandom(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for trial := 0; trial < 20; trial++ {
+		const n = 40
+		g := simple.NewWeightedDirectedGraph(0, 0)
+		for i := 0; i < n; i++ {
+			g.AddNode(simple.Node(i))
+		}
+		for i := 0; i < 4*n; i++ {
+			u, v := rnd.Intn(n), rnd.Intn(n)
+			if u == v {
+				continue
+			}
+			w := float64(rnd.Intn(20))
+			if trial%2 == 1 {
+				w = 20 * rnd.Float64()
+			}
+			g.SetWeightedEdge(simple.WeightedEdge{F: simple.Node(u), T: simple.Node(v), W: w})
+		}
+		s, tn := simple.Node(0), simple.Node(n-1)
+		ek := EdmondsKarp(g, s, tn)
+		pr := PushRelabel(g, s, tn)
+		if math.Abs(ek.Value-pr.Value) > 1e-9 {
+			t.Errorf("mismatched flow values for trial %d: EdmondsKarp:%v PushRelabel:%v", trial, ek.Value, pr.Value)
+		}
+		checkFlow(t, fmt.Sprintf("EdmondsKarp trial %d", trial), g, ek, s, tn, 1e-9)
+		checkFlow(t, fmt.Sprintf("PushRelabel trial %d", trial), g, pr, s, tn, 1e-9)
+	}
+}
+
+// checkFlow checks that f is a valid flow from s to t in g that respects
+// edge capacities, and that the capacity of its minimum cut equals its value.
+func checkFlow(t *testing.T, name string, g graph.WeightedDirected, f Flow, s, tn graph.Node, tol float64) {
+	net := make(map[int64]float64)
+	for _, e := range f.Edges() {
+		u, v := e.From(), e.To()
+		if !g.HasEdgeFromTo(u, v) {
+			t.Errorf("flow on non-existent edge %d->%d for %s", u.ID(), v.ID(), name)
+			continue
+		}
+		if c := g.WeightedEdge(u, v).Weight(); e.Weight() > c+tol {
+			t.Errorf("flow exceeds capacity on edge %d->%d for %s: %v > %v", u.ID(), v.ID(), name, e.Weight(), c)
+		}
+		if e.Weight() != f.FlowOn(u, v) {
+			t.Errorf("inconsistent flow on edge %d->%d for %s", u.ID(), v.ID(), name)
+		}
+		net[u.ID()] -= e.Weight()
+		net[v.ID()] += e.Weight()
+	}
+	for id, v := range net {
+		switch id {
+		case s.ID():
+			if math.Abs(v+f.Value) > tol {
+				t.Errorf("unexpected net flow out of source for %s: got:%v want:%v", name, -v, f.Value)
+			}
+		case tn.ID():
+			if math.Abs(v-f.Value) > tol {
+				t.Errorf("unexpected net flow into sink for %s: got:%v want:%v", name, v, f.Value)
+			}
+		default:
+			if math.Abs(v) > tol {
+				t.Errorf("flow not conserved at node %d for %s: %v", id, name, v)
+			}
+		}
+	}
+
+	src, _ := f.MinCut()
+	inSource := make(map[int64]bool)
+	for _, n := range src {
+		inSource[n.ID()] = true
+	}
+	if !inSource[s.ID()] || inSource[tn.ID()] {
+		t.Errorf("cut does not separate source and sink for %s", name)
+	}
+	var cut float64
+	for _, u := range src {
+		for _, v := range g.From(u) {
+			if !inSource[v.ID()] {
+				cut += g.WeightedEdge(u, v).Weight()
+			}
+		}
+	}
+	if math.Abs(cut-f.Value) > tol {
+		t.Errorf("unexpected cut capacity for %s: got:%v want:%v", name, cut, f.Value)
+	}
+}
@@ -0,0 +1,308 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package flow
+
+import (
+	"container/heap"
+	"fmt"
+	"math"
+	"sort"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/internal/ordered"
+)
+
+// Cost is a function that returns the cost per unit of flow along the
+// edge from u to v.
+type Cost func(u, v graph.Node) float64
+
+// MinCostFlow returns a maximum flow from s to t in g that has the minimum
+// total cost among all maximum flows, and the total cost of the flow. The
+// capacity of each edge is given by its weight and the cost per unit of flow
+// along each edge is given by cost. If cost is nil, every edge has unit cost.
+//
+// MinCostFlow uses the successive shortest path algorithm, augmenting the
+// flow along least-cost residual paths found using Dijkstra's algorithm with
+// node potentials. Edge costs may be negative as long as g has no cycle of
+// negative total cost that is reachable from s. MinCostFlow will panic if s
+// and t are the same node, g has a negative edge weight or g has a negative
+// cost cycle reachable from s.
+//
+// Assignment and transportation problems can be solved by adding a source
+// connected to the supply nodes and a sink connected from the demand nodes,
+// with capacities set to the supplies and demands, and zero cost.
+//
+// The time complexity of MinCostFlow is O(F.|E|.log|V|) where F is the
+// number of augmenting paths, which is bounded by the flow value when edge
+// capacities are integral.
+func MinCostFlow(g graph.WeightedDirected, s, t graph.Node, cost Cost) (f Flow, c float64) {
+	if cost == nil {
+		cost = func(_, _ graph.Node) float64 { return 1 }
+	}
+	r, si, ti := newCostResidual(g, s, t, cost)
+	if si < 0 || ti < 0 {
+		return r.flowResult(si), 0
+	}
+
+	n := len(r.nodes)
+	potential := r.initialPotentials(si)
+	dist := make([]float64, n)
+	via := make([]int, n)
+	for {
+		// Find a least-cost augmenting path using
+		// costs reduced by the node potentials.
+		for i := range dist {
+			dist[i] = math.Inf(1)
+			via[i] = -1
+		}
+		dist[si] = 0
+		q := costQueue{{node: si, dist: 0}}
+		for q.Len() != 0 {
+			mid := heap.Pop(&q).(costNode)
+			u := mid.node
+			if mid.dist > dist[u] {
+				continue
+			}
+			for _, k := range r.adj[u] {
+				a := r.arcs[k]
+				if a.saturated() {
+					continue
+				}
+				// Reduced costs of residual arcs are
+				// non-negative up to rounding error.
+				rc := math.Max(0, a.cost+potential[u]-potential[a.to])
+				if d := dist[u] + rc; d < dist[a.to] {
+					dist[a.to] = d
+					via[a.to] = k
+					heap.Push(&q, costNode{node: a.to, dist: d})
+				}
+			}
+		}
+		if math.IsInf(dist[ti], 1) {
+			break
+		}
+		for i, d := range dist {
+			if !math.IsInf(d, 1) {
+				potential[i] += d
+			}
+		}
+
+		bottleneck := math.Inf(1)
+		for v := ti; v != si; v = r.arcs[via[v]^1].to {
+			bottleneck = math.Min(bottleneck, r.arcs[via[v]].residual())
+		}
+		for v := ti; v != si; v = r.arcs[via[v]^1].to {
+			k := via[v]
+			r.push(k, bottleneck)
+			c += bottleneck * r.arcs[k].cost
+		}
+	}
+
+	return r.flowResult(si), c
+}
+
+// costResidual is a residual network with arc costs. Each edge of the
+// network is represented by a forward arc and its paired reverse arc, so
+// that antiparallel edges with different costs are kept distinct.
+type costResidual struct {
+	nodes   []graph.Node
+	indexOf map[int64]int
+
+	// arcs holds the arcs of the network
+	// with the reverse of arc k at k^1,
+	// and adj holds the indices of the
+	// arcs leaving each node.
+	arcs []arc
+	adj  [][]int
+}
+
+// arc is an arc of a costResidual.
+type arc struct {
+	to        int
+	cap, flow float64
+	cost      float64
+
+	// scale is the capacity of the edge
+	// represented by the arc and its
+	// paired reverse arc.
+	scale float64
+}
+
+// residual returns the residual capacity of the arc.
+func (a arc) residual() float64 { return a.cap - a.flow }
+
+// saturated returns whether the residual capacity of the
+// arc is within the tolerance for its edge capacity.
+func (a arc) saturated() bool { return !(a.residual() > tolerance(a.scale)) }
+
+// newCostResidual returns the residual network for g with zero flow and the
+// indices of s and t. If either s or t is not in g, its index is -1.
+func newCostResidual(g graph.WeightedDirected, s, t graph.Node, cost Cost) (r *costResidual, si, ti int) {
+	if s.ID() == t.ID() {
+		panic("flow: source and sink are the same node")
+	}
+	nodes := g.Nodes()
+	sort.Sort(ordered.ByID(nodes))
+	r = &costResidual{
+		nodes:   nodes,
+		indexOf: make(map[int64]int, len(nodes)),
+		adj:     make([][]int, len(nodes)),
+	}
+	for i, n := range nodes {
+		r.indexOf[n.ID()] = i
+	}
+	for i, u := range nodes {
+		to := g.From(u)
+		sort.Sort(ordered.ByID(to))
+		for _, v := range to {
+			j := r.indexOf[v.ID()]
+			if i == j {
+				continue
+			}
+			c := g.WeightedEdge(u, v).Weight()
+			if c < 0 {
+				panic(fmt.Sprintf("flow: negative capacity on edge %d->%d", u.ID(), v.ID()))
+			}
+			w := cost(u, v)
+			r.adj[i] = append(r.adj[i], len(r.arcs))
+			r.arcs = append(r.arcs, arc{to: j, cap: c, cost: w, scale: c})
+			r.adj[j] = append(r.adj[j], len(r.arcs))
+			r.arcs = append(r.arcs, arc{to: i, cost: -w, scale: c})
+		}
+	}
+
+	si, ti = -1, -1
+	if idx, ok := r.indexOf[s.ID()]; ok {
+		si = idx
+	}
+	if idx, ok := r.indexOf[t.ID()]; ok {
+		ti = idx
+	}
+	return r, si, ti
+}
+
+// initialPotentials returns the least costs from si to each node over arcs
+// with positive capacity using the Bellman-Ford-Moore algorithm. Nodes not
+// reachable from si have a potential of zero. initialPotentials will panic
+// if a negative cost cycle is reachable from si.
+func (r *costResidual) initialPotentials(si int) []float64 {
+	n := len(r.nodes)
+	dist := make([]float64, n)
+	for i := range dist {
+		dist[i] = math.Inf(1)
+	}
+	dist[si] = 0
+	for i := 0; i < n; i++ {
+		changed := false
+		for u, adj := range r.adj {
+			if math.IsInf(dist[u], 1) {
+				continue
+			}
+			for _, k := range adj {
+				a := r.arcs[k]
+				if a.saturated() {
+					continue
+				}
+				if d := dist[u] + a.cost; d < dist[a.to] {
+					dist[a.to] = d
+					changed = true
+				}
+			}
+		}
+		if !changed {
+			break
+		}
+		if i == n-1 {
+			panic("flow: negative cost cycle")
+		}
+	}
+	for i, d := range dist {
+		if math.IsInf(d, 1) {
+			dist[i] = 0
+		}
+	}
+	return dist
+}
+
+// push sends f units of flow along arc k. If f is equal to the
+// residual capacity of the arc, the arc is exactly saturated.
+func (r *costResidual) push(k int, f float64) {
+	if f == r.arcs[k].residual() {
+		r.arcs[k].flow = r.arcs[k].cap
+	} else {
+		r.arcs[k].flow += f
+	}
+	r.arcs[k^1].flow -= f
+}
+
+// flowResult returns the Flow held by r with the minimum cut
+// found by search from the source in the residual network.
+func (r *costResidual) flowResult(si int) Flow {
+	f := Flow{
+		nodes:      r.nodes,
+		indexOf:    r.indexOf,
+		flow:       make([]map[int]float64, len(r.nodes)),
+		sourceSide: make([]bool, len(r.nodes)),
+	}
+	for u, adj := range r.adj {
+		for _, k := range adj {
+			if k&1 != 0 {
+				// Skip reverse arcs.
+				continue
+			}
+			a := r.arcs[k]
+			if a.flow <= tolerance(a.scale) {
+				continue
+			}
+			if f.flow[u] == nil {
+				f.flow[u] = make(map[int]float64)
+			}
+			f.flow[u][a.to] = a.flow
+		}
+	}
+	if si < 0 {
+		return f
+	}
+	for _, k := range r.adj[si] {
+		// Reverse arcs into si hold the
+		// negated flow along their edges.
+		f.Value += r.arcs[k].flow
+	}
+
+	f.sourceSide[si] = true
+	queue := []int{si}
+	for len(queue) != 0 {
+		u := queue[0]
+		queue = queue[1:]
+		for _, k := range r.adj[u] {
+			a := r.arcs[k]
+			if !f.sourceSide[a.to] && !a.saturated() {
+				f.sourceSide[a.to] = true
+				queue = append(queue, a.to)
+			}
+		}
+	}
+	return f
+}
+
+// costNode is a node index and its least cost from the source.
+type costNode struct {
+	node int
+	dist float64
+}
+
+// costQueue is a min-priority queue of costNodes.
+type costQueue []costNode
+
+func (q costQueue) Len() int            { return len(q) }
+func (q costQueue) Less(i, j int) bool  { return q[i].dist < q[j].dist }
+func (q costQueue) Swap(i, j int)       { q[i], q[j] = q[j], q[i] }
+func (q *costQueue) Push(n interface{}) { *q = append(*q, n.(costNode)) }
+func (q *costQueue) Pop() interface{} {
+	t := *q
+	var n interface{}
+	n, *q = t[len(t)-1], t[:len(t)-1]
+	return n
+}
@@ -0,0 +1,295 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package flow
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/simple"
+)
+
+type costEdge struct {
+	from, to  int64
+	cap, cost float64
+}
+
+var minCostFlowTests = []struct {
+	name     string
+	edges    []costEdge
+	s, t     int64
+	want     float64
+	wantCost float64
+}{
+	{
+		name: "two paths",
+		edges: []costEdge{
+			{from: 0, to: 1, cap: 4, cost: 1},
+			{from: 0, to: 2, cap: 2, cost: 2},
+			{from: 1, to: 2, cap: 2, cost: 1},
+			{from: 1, to: 3, cap: 3, cost: 3},
+			{from: 2, to: 3, cap: 5, cost: 1},
+		},
+		s: 0, t: 3,
+		// 2 units along 0-2-3 at cost 3, 2 units along
+		// 0-1-2-3 at cost 3 and 2 units along 0-1-3 at
+		// cost 4.
+		want:     6,
+		wantCost: 2*3 + 2*3 + 2*4,
+	},
+	{
+		name: "antiparallel costs",
+		edges: []costEdge{
+			{from: 0, to: 1, cap: 2, cost: 1},
+			{from: 0, to: 2, cap: 2, cost: 1},
+			{from: 1, to: 2, cap: 2, cost: 5},
+			{from: 2, to: 1, cap: 2, cost: 1},
+			{from: 1, to: 3, cap: 3, cost: 1},
+			{from: 2, to: 3, cap: 1, cost: 1},
+		},
+		s: 0, t: 3,
+		// 2 units along 0-1-3, 1 unit along 0-2-3
+		// and 1 unit along 0-2-1-3.
+		want:     4,
+		wantCost: 2*2 + 2 + 3,
+	},
+	{
+		name: "negative cost",
+		edges: []costEdge{
+			{from: 0, to: 1, cap: 1, cost: 4},
+			{from: 0, to: 2, cap: 1, cost: 1},
+			{from: 1, to: 3, cap: 1, cost: -3},
+			{from: 2, to: 3, cap: 1, cost: 1},
+		},
+		s: 0, t: 3,
+		want:     2,
+		wantCost: 1 + 2,
+	},
+	{
+		name: "infinite capacity",
+		edges: []costEdge{
+			{from: 0, to: 1, cap: 3, cost: 1},
+			{from: 1, to: 2, cap: math.Inf(1), cost: 1},
+			{from: 2, to: 3, cap: 2, cost: 1},
+		},
+		s: 0, t: 3,
+		want:     2,
+		wantCost: 2 * 3,
+	},
+	{
+		// The small capacities must not be treated
+		// as saturated relative to the large ones.
+		name: "mixed scale",
+		edges: []costEdge{
+			{from: 0, to: 1, cap: 1 << 20, cost: 1},
+			{from: 1, to: 3, cap: 1 << 20, cost: 1},
+			{from: 0, to: 2, cap: 1.0 / (1 << 27), cost: 1},
+			{from: 2, to: 3, cap: 1.0 / (1 << 27), cost: 1},
+		},
+		s: 0, t: 3,
+		want:     1<<20 + 1.0/(1<<27),
+		wantCost: 2 * (1<<20 + 1.0/(1<<27)),
+	},
+	{
+		name: "disconnected",
+		edges: []costEdge{
+			{from: 0, to: 1, cap: 3, cost: 1},
+			{from: 2, to: 3, cap: 3, cost: 1},
+		},
+		s: 0, t: 3,
+		want:     0,
+		wantCost: 0,
+	},
+}
+
+func costGraph(edges []costEdge) (*simple.WeightedDirectedGraph, Cost) {
+	g := simple.NewWeightedDirectedGraph(0, 0)
+	cost := make(map[[2]int64]float64)
+	for _, e := range edges {
+		g.SetWeightedEdge(simple.WeightedEdge{F: simple.Node(e.from), T: simple.Node(e.to), W: e.cap})
+		cost[[2]int64{e.from, e.to}] = e.cost
+	}
+	return g, func(u, v graph.Node) float64 { return cost[[2]int64{u.ID(), v.ID()}] }
+}
+
+func TestMinCostFlow(t *testing.T) {
+	for _, test := range minCostFlowTests {
+		g, cost := costGraph(test.edges)
+		f, c := MinCostFlow(g, simple.Node(test.s), simple.Node(test.t), cost)
+		if f.Value != test.want {
+			t.Errorf("unexpected flow value for %q: got:%v want:%v", test.name, f.Value, test.want)
+		}
+		if c != test.wantCost {
+			t.Errorf("unexpected flow cost for %q: got:%v want:%v", test.name, c, test.wantCost)
+		}
+		checkFlow(t, test.name, g, f, simple.Node(test.s), simple.Node(test.t), 1e-12)
+		checkMinCost(t, test.name, g, f, c, cost, 1e-12)
+	}
+}
+
+func TestMinCostFlowAssignment(t *testing.T) {
+	const n = 5
+	rnd := rand.New(rand.NewSource(1))
+	for trial := 0; trial < 10; trial++ {
+		var costs [n][n]float64
+		for i := range costs {
+			for j := range costs[i] {
+				costs[i][j] = float64(rnd.Intn(100))
+			}
+		}
+
+		// Workers are nodes 1 to n and jobs are nodes
+		// n+1 to 2n, with the source 0 and the sink 2n+1.
+		var edges []costEdge
+		for i := 0; i < n; i++ {
+			edges = append(edges,
+				costEdge{from: 0, to: int64(i + 1), cap: 1},
+				costEdge{from: int64(n + i + 1), to: 2*n + 1, cap: 1},
+			)
+			for j := 0; j < n; j++ {
+				edges = append(edges, costEdge{from: int64(i + 1), to: int64(n + j + 1), cap: 1, cost: costs[i][j]})
+			}
+		}
+		g, cost := costGraph(edges)
+		f, got := MinCostFlow(g, simple.Node(0), simple.Node(2*n+1), cost)
+		if f.Value != n {
+			t.Errorf("unexpected number of assignments for trial %d: got:%v want:%v", trial, f.Value, n)
+		}
+
+		want := math.Inf(1)
+		perm := make([]int, n)
+		for i := range perm {
+			perm[i] = i
+		}
+		permute(perm, 0, func(p []int) {
+			var c float64
+			for i, j := range p {
+				c += costs[i][j]
+			}
+			want = math.Min(want, c)
+		})
+		if got != want {
+			t.Errorf("unexpected assignment cost for trial %d: got:%v want:%v", trial, got, want)
+		}
+	}
+}
+
+// permute calls fn with each permutation of p[k:].
+func permute(p []int, k int, fn func([]int)) {
+	if k == len(p) {
+		fn(p)
+		return
+	}
+	for i := k; i < len(p); i++ {
+		p[k], p[i] = p[i], p[k]
+		permute(p, k+1, fn)
+		p[k], p[i] = p[i], p[k]
+	}
+}
+
+func TestMinCostFlowRandom(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for trial := 0; trial < 20; trial++ {
+		const n = 30
+		var edges []costEdge
+		seen := make(map[[2]int64]bool)
+		for i := 0; i < 4*n; i++ {
+			u, v := int64(rnd.Intn(n)), int64(rnd.Intn(n))
+			if u == v || seen[[2]int64{u, v}] {
+				continue
+			}
+			seen[[2]int64{u, v}] = true
+			e := costEdge{from: u, to: v, cap: float64(rnd.Intn(20)), cost: float64(rnd.Intn(10))}
+			if trial%2 == 1 {
+				e.cap = 20 * rnd.Float64()
+				e.cost = 10 * rnd.Float64()
+			}
+			edges = append(edges, e)
+		}
+		g, cost := costGraph(edges)
+		for i := 0; i < n; i++ {
+			if !g.Has(simple.Node(i)) {
+				g.AddNode(simple.Node(i))
+			}
+		}
+		s, tn := simple.Node(0), simple.Node(n-1)
+		name := fmt.Sprintf("trial %d", trial)
+		f, c := MinCostFlow(g, s, tn, cost)
+		if want := EdmondsKarp(g, s, tn).Value; math.Abs(f.Value-want) > 1e-9 {
+			t.Errorf("unexpected flow value for %s: got:%v want:%v", name, f.Value, want)
+		}
+		checkFlow(t, name, g, f, s, tn, 1e-9)
+		checkMinCost(t, name, g, f, c, cost, 1e-9)
+	}
+}
+
+// checkMinCost checks that c is the cost of the flow f and that f has
+// minimum cost by checking that the residual network of f has no negative
+// cost cycle.
+func checkMinCost(t *testing.T, name string, g graph.WeightedDirected, f Flow, c float64, cost Cost, tol float64) {
+	var got float64
+	for _, e := range f.Edges() {
+		got += e.Weight() * cost(e.From(), e.To())
+	}
+	if math.Abs(got-c) > tol*math.Max(1, math.Abs(c)) {
+		t.Errorf("unexpected flow cost for %s: got:%v want:%v", name, c, got)
+	}
+
+	type residualArc struct {
+		from, to int64
+		cost     float64
+	}
+	var arcs []residualArc
+	for _, u := range g.Nodes() {
+		for _, v := range g.From(u) {
+			fl := f.FlowOn(u, v)
+			w := cost(u, v)
+			if g.WeightedEdge(u, v).Weight()-fl > tol {
+				arcs = append(arcs, residualArc{from: u.ID(), to: v.ID(), cost: w})
+			}
+			if fl > tol {
+				arcs = append(arcs, residualArc{from: v.ID(), to: u.ID(), cost: -w})
+			}
+		}
+	}
+	// Bellman-Ford from a virtual node connected
+	// to every node with a zero cost arc.
+	dist := make(map[int64]float64)
+	for _, n := range g.Nodes() {
+		dist[n.ID()] = 0
+	}
+	for i := 0; i <= len(dist); i++ {
+		changed := false
+		for _, a := range arcs {
+			if d := dist[a.from] + a.cost; d < dist[a.to]-tol {
+				dist[a.to] = d
+				changed = true
+			}
+		}
+		if !changed {
+			return
+		}
+	}
+	t.Errorf("negative cost cycle in residual network for %s", name)
+}
+
+func TestMinCostFlowNegativeCycle(t *testing.T) {
+	g, cost := costGraph([]costEdge{
+		{from: 0, to: 1, cap: 1, cost: 1},
+		{from: 1, to: 2, cap: 1, cost: -3},
+		{from: 2, to: 1, cap: 1, cost: 1},
+		{from: 2, to: 3, cap: 1, cost: 1},
+	})
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("expected panic for negative cost cycle")
+		}
+	}()
+	MinCostFlow(g, simple.Node(0), simple.Node(3), cost)
+}
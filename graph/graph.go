@@ -25,6 +25,15 @@ type WeightedEdge interface {
 	Weight() float64
 }
 
+// TemporalEdge is a graph edge that is present during the closed time
+// interval [Start, End]. In directed graphs, the direction of the edge
+// is given from -> to, otherwise the edge is semantically unordered.
+type TemporalEdge interface {
+	Edge
+	Start() float64
+	End() float64
+}
+
 // Graph is a generalized graph.
 type Graph interface {
 	// Has returns whether the node exists within the graph.
@@ -111,6 +120,20 @@ type WeightedDirected interface {
 	To(Node) []Node
 }
 
+// EdgeSlicer is a graph that can return all its edges. For undirected
+// graphs each edge is returned once, in either orientation.
+type EdgeSlicer interface {
+	// Edges returns all the edges in the graph.
+	Edges() []Edge
+}
+
+// WeightedEdgeSlicer is a weighted graph that can return all its edges.
+// For undirected graphs each edge is returned once, in either orientation.
+type WeightedEdgeSlicer interface {
+	// WeightedEdges returns all the weighted edges in the graph.
+	WeightedEdges() []WeightedEdge
+}
+
 // NodeAdder is an interface for adding arbitrary nodes to a graph.
 type NodeAdder interface {
 	// NewNode returns a new Node with a unique
@@ -211,11 +234,25 @@ type DirectedWeightedBuilder interface {
 //
 // If the source is undirected and the destination is directed both directions will
 // be present in the destination after the copy is complete.
+//
+// If the source implements EdgeSlicer, its edges are obtained with a
+// single call to Edges.
 func Copy(dst Builder, src Graph) {
 	nodes := src.Nodes()
 	for _, n := range nodes {
 		dst.AddNode(n)
 	}
+	if es, ok := src.(EdgeSlicer); ok {
+		_, directed := src.(Directed)
+		for _, e := range es.Edges() {
+			u, v := e.From(), e.To()
+			dst.SetEdge(dst.NewEdge(u, v))
+			if !directed {
+				dst.SetEdge(dst.NewEdge(v, u))
+			}
+		}
+		return
+	}
 	for _, u := range nodes {
 		for _, v := range src.From(u) {
 			dst.SetEdge(dst.NewEdge(u, v))
@@ -234,14 +271,123 @@ func Copy(dst Builder, src Graph) {
 // cycle exists with two nodes where the edge weights differ, the resulting destination
 // graph's edge weight between those nodes is undefined. If there is a defined function
 // to resolve such conflicts, an UndirectWeighted may be used to do this.
+//
+// If the source implements WeightedEdgeSlicer, its edges are obtained with
+// a single call to WeightedEdges rather than a WeightedEdge call for each
+// pair of adjacent nodes.
 func CopyWeighted(dst WeightedBuilder, src Weighted) {
 	nodes := src.Nodes()
 	for _, n := range nodes {
 		dst.AddNode(n)
 	}
+	if es, ok := src.(WeightedEdgeSlicer); ok {
+		_, directed := src.(WeightedDirected)
+		for _, e := range es.WeightedEdges() {
+			u, v, w := e.From(), e.To(), e.Weight()
+			dst.SetWeightedEdge(dst.NewWeightedEdge(u, v, w))
+			if !directed {
+				dst.SetWeightedEdge(dst.NewWeightedEdge(v, u, w))
+			}
+		}
+		return
+	}
 	for _, u := range nodes {
 		for _, v := range src.From(u) {
 			dst.SetWeightedEdge(dst.NewWeightedEdge(u, v, src.WeightedEdge(u, v).Weight()))
 		}
 	}
 }
+
+// CopyRelabel copies nodes and edges from the source to the destination without
+// first clearing the destination, allocating a new node in the destination with
+// dst.NewNode for each source node. It returns a map from source node IDs to the
+// IDs of the corresponding destination nodes. Since nodes are relabeled, node IDs
+// in the source may collide with node IDs in the destination; the destination
+// nodes are those returned by dst.NewNode, not the source nodes.
+//
+// If the source is undirected and the destination is directed both directions will
+// be present in the destination after the copy is complete.
+func CopyRelabel(dst Builder, src Graph) map[int64]int64 {
+	nodes := sortedNodes(src.Nodes())
+	relabeled := make(map[int64]Node, len(nodes))
+	for _, n := range nodes {
+		r := dst.NewNode()
+		dst.AddNode(r)
+		relabeled[n.ID()] = r
+	}
+	for _, u := range nodes {
+		for _, v := range src.From(u) {
+			dst.SetEdge(dst.NewEdge(relabeled[u.ID()], relabeled[v.ID()]))
+		}
+	}
+	ids := make(map[int64]int64, len(relabeled))
+	for id, n := range relabeled {
+		ids[id] = n.ID()
+	}
+	return ids
+}
+
+// Merge copies the nodes and edges of each of the source graphs into the destination
+// using CopyRelabel, so the copies are disjoint in the destination regardless of the
+// node IDs of the sources. It returns the relabeling maps for the sources, in order.
+func Merge(dst Builder, src ...Graph) []map[int64]int64 {
+	ids := make([]map[int64]int64, len(src))
+	for i, g := range src {
+		ids[i] = CopyRelabel(dst, g)
+	}
+	return ids
+}
+
+// CopyFiltered copies nodes and edges from the source to the destination without first
+// clearing the destination, retaining only the nodes for which keepNode returns true
+// and the edges between retained nodes for which keepEdge returns true. If keepNode or
+// keepEdge is nil, all nodes or edges respectively are retained. CopyFiltered will panic
+// if a retained node ID in the source graph matches a node ID in the destination.
+//
+// The edges passed to keepEdge are those returned by src.Edge(u, v) for each node v
+// reachable from u, so for an undirected source keepEdge is called once for each
+// orientation of an edge and should not depend on the orientation.
+//
+// If the source is undirected and the destination is directed both directions of
+// each retained edge will be present in the destination after the copy is complete.
+func CopyFiltered(dst Builder, src Graph, keepNode func(Node) bool, keepEdge func(Edge) bool) {
+	nodes := src.Nodes()
+	kept := make(map[int64]struct{}, len(nodes))
+	for _, n := range nodes {
+		if keepNode != nil && !keepNode(n) {
+			continue
+		}
+		kept[n.ID()] = struct{}{}
+		dst.AddNode(n)
+	}
+	for _, u := range nodes {
+		if _, ok := kept[u.ID()]; !ok {
+			continue
+		}
+		for _, v := range src.From(u) {
+			if _, ok := kept[v.ID()]; !ok {
+				continue
+			}
+			if keepEdge != nil && !keepEdge(src.Edge(u, v)) {
+				continue
+			}
+			dst.SetEdge(dst.NewEdge(u, v))
+		}
+	}
+}
+
+// Induce copies the subgraph of the source induced by the given nodes to the
+// destination without first clearing the destination. The induced subgraph holds
+// the nodes and all the edges of the source between them. Nodes that are not in
+// the source are ignored. Induce will panic if a node ID in the induced subgraph
+// matches a node ID in the destination.
+func Induce(dst Builder, src Graph, nodes []Node) {
+	set := make(map[int64]struct{}, len(nodes))
+	for _, n := range nodes {
+		set[n.ID()] = struct{}{}
+	}
+	CopyFiltered(dst, src, func(n Node) bool {
+		_, ok := set[n.ID()]
+		return ok
+	}, nil)
+}
@@ -18,6 +18,11 @@ type graphBuilder interface {
 	graph.Builder
 }
 
+type weightedBuilder interface {
+	graph.Weighted
+	graph.WeightedBuilder
+}
+
 var copyTests = []struct {
 	desc string
 
@@ -296,3 +301,240 @@ func same(a, b graph.Graph) bool {
 	}
 	return true
 }
+
+func TestCopyFiltered(t *testing.T) {
+	src := simple.NewDirectedGraph()
+	for _, e := range []simple.Edge{
+		{F: simple.Node(0), T: simple.Node(1)},
+		{F: simple.Node(0), T: simple.Node(3)},
+		{F: simple.Node(1), T: simple.Node(2)},
+		{F: simple.Node(2), T: simple.Node(0)},
+		{F: simple.Node(3), T: simple.Node(4)},
+	} {
+		src.SetEdge(e)
+	}
+
+	for _, test := range []struct {
+		desc     string
+		keepNode func(graph.Node) bool
+		keepEdge func(graph.Edge) bool
+		want     []simple.Edge
+		nodes    []int64
+	}{
+		{
+			desc:  "keep all",
+			want:  []simple.Edge{{F: simple.Node(0), T: simple.Node(1)}, {F: simple.Node(0), T: simple.Node(3)}, {F: simple.Node(1), T: simple.Node(2)}, {F: simple.Node(2), T: simple.Node(0)}, {F: simple.Node(3), T: simple.Node(4)}},
+			nodes: []int64{0, 1, 2, 3, 4},
+		},
+		{
+			desc:     "drop node",
+			keepNode: func(n graph.Node) bool { return n.ID() != 0 },
+			want:     []simple.Edge{{F: simple.Node(1), T: simple.Node(2)}, {F: simple.Node(3), T: simple.Node(4)}},
+			nodes:    []int64{1, 2, 3, 4},
+		},
+		{
+			desc:     "drop edges from even nodes",
+			keepEdge: func(e graph.Edge) bool { return e.From().ID()%2 != 0 },
+			want:     []simple.Edge{{F: simple.Node(1), T: simple.Node(2)}, {F: simple.Node(3), T: simple.Node(4)}},
+			nodes:    []int64{0, 1, 2, 3, 4},
+		},
+		{
+			desc:     "drop node and edge",
+			keepNode: func(n graph.Node) bool { return n.ID() < 4 },
+			keepEdge: func(e graph.Edge) bool { return e.To().ID() != 1 },
+			want:     []simple.Edge{{F: simple.Node(0), T: simple.Node(3)}, {F: simple.Node(1), T: simple.Node(2)}, {F: simple.Node(2), T: simple.Node(0)}},
+			nodes:    []int64{0, 1, 2, 3},
+		},
+	} {
+		dst := simple.NewDirectedGraph()
+		graph.CopyFiltered(dst, src, test.keepNode, test.keepEdge)
+
+		want := simple.NewDirectedGraph()
+		for _, id := range test.nodes {
+			want.AddNode(simple.Node(id))
+		}
+		for _, e := range test.want {
+			want.SetEdge(e)
+		}
+		if !same(dst, want) {
+			t.Errorf("unexpected filtered copy result for %s", test.desc)
+		}
+	}
+}
+
+func TestInduce(t *testing.T) {
+	src := simple.NewUndirectedGraph()
+	for _, e := range []simple.Edge{
+		{F: simple.Node(0), T: simple.Node(1)},
+		{F: simple.Node(0), T: simple.Node(2)},
+		{F: simple.Node(0), T: simple.Node(3)},
+		{F: simple.Node(1), T: simple.Node(2)},
+		{F: simple.Node(3), T: simple.Node(4)},
+		{F: simple.Node(4), T: simple.Node(5)},
+	} {
+		src.SetEdge(e)
+	}
+
+	// The ego network of node 0 includes the edge
+	// between its neighbors 1 and 2 but not the
+	// edge from its neighbor 3 to node 4.
+	ego := append(src.From(simple.Node(0)), simple.Node(0), simple.Node(10))
+	dst := simple.NewUndirectedGraph()
+	graph.Induce(dst, src, ego)
+
+	want := simple.NewUndirectedGraph()
+	for _, e := range []simple.Edge{
+		{F: simple.Node(0), T: simple.Node(1)},
+		{F: simple.Node(0), T: simple.Node(2)},
+		{F: simple.Node(0), T: simple.Node(3)},
+		{F: simple.Node(1), T: simple.Node(2)},
+	} {
+		want.SetEdge(e)
+	}
+	if !same(dst, want) {
+		t.Error("unexpected induced subgraph")
+	}
+
+	// An induced subgraph copied to a directed
+	// graph holds both directions of each edge.
+	ddst := simple.NewDirectedGraph()
+	graph.Induce(ddst, src, []graph.Node{simple.Node(3), simple.Node(4), simple.Node(5)})
+	for _, e := range [][2]int64{{3, 4}, {4, 3}, {4, 5}, {5, 4}} {
+		if !ddst.HasEdgeFromTo(simple.Node(e[0]), simple.Node(e[1])) {
+			t.Errorf("missing induced edge %d->%d", e[0], e[1])
+		}
+	}
+	if n := len(ddst.Nodes()); n != 3 {
+		t.Errorf("unexpected number of induced nodes: got:%d want:3", n)
+	}
+}
+
+func TestCopyRelabel(t *testing.T) {
+	src := simple.NewDirectedGraph()
+	for _, e := range []simple.Edge{
+		{F: simple.Node(10), T: simple.Node(20)},
+		{F: simple.Node(20), T: simple.Node(30)},
+	} {
+		src.SetEdge(e)
+	}
+	src.AddNode(simple.Node(40))
+
+	// The destination already holds the node IDs of the
+	// source, so a plain Copy would panic.
+	dst := simple.NewDirectedGraph()
+	for _, id := range []int64{10, 20, 30, 40} {
+		dst.AddNode(simple.Node(id))
+	}
+	ids := graph.CopyRelabel(dst, src)
+	if len(ids) != 4 {
+		t.Fatalf("unexpected number of relabeled nodes: got:%d want:4", len(ids))
+	}
+	seen := make(map[int64]bool)
+	for old, id := range ids {
+		if seen[id] {
+			t.Errorf("node %d relabeled to duplicate ID %d", old, id)
+		}
+		seen[id] = true
+		if id == 10 || id == 20 || id == 30 || id == 40 {
+			t.Errorf("node %d relabeled to existing ID %d", old, id)
+		}
+		if !dst.Has(simple.Node(id)) {
+			t.Errorf("relabeled node %d missing from destination", id)
+		}
+	}
+	if n := len(dst.Nodes()); n != 8 {
+		t.Errorf("unexpected number of destination nodes: got:%d want:8", n)
+	}
+	for _, e := range [][2]int64{{10, 20}, {20, 30}} {
+		if !dst.HasEdgeFromTo(simple.Node(ids[e[0]]), simple.Node(ids[e[1]])) {
+			t.Errorf("missing relabeled edge %d->%d", e[0], e[1])
+		}
+	}
+	if n := len(dst.From(simple.Node(ids[40]))) + len(dst.To(simple.Node(ids[40]))); n != 0 {
+		t.Errorf("unexpected edges for isolated node: got:%d want:0", n)
+	}
+}
+
+func TestMerge(t *testing.T) {
+	a := simple.NewUndirectedGraph()
+	a.SetEdge(simple.Edge{F: simple.Node(0), T: simple.Node(1)})
+	b := simple.NewUndirectedGraph()
+	b.SetEdge(simple.Edge{F: simple.Node(0), T: simple.Node(1)})
+	b.SetEdge(simple.Edge{F: simple.Node(1), T: simple.Node(2)})
+
+	dst := simple.NewUndirectedGraph()
+	ids := graph.Merge(dst, a, b)
+	if len(ids) != 2 {
+		t.Fatalf("unexpected number of relabelings: got:%d want:2", len(ids))
+	}
+	if n := len(dst.Nodes()); n != 5 {
+		t.Errorf("unexpected number of merged nodes: got:%d want:5", n)
+	}
+	if dst.HasEdgeBetween(simple.Node(ids[0][1]), simple.Node(ids[1][1])) {
+		t.Error("unexpected edge between merged components")
+	}
+	for i, g := range []graph.Graph{a, b} {
+		for _, u := range g.Nodes() {
+			for _, v := range g.From(u) {
+				if !dst.HasEdgeBetween(simple.Node(ids[i][u.ID()]), simple.Node(ids[i][v.ID()])) {
+					t.Errorf("missing edge %d--%d from graph %d", u.ID(), v.ID(), i)
+				}
+			}
+		}
+	}
+}
+
+// hideEdges hides the Edges method of a graph.
+type hideEdges struct{ graph.Graph }
+
+// hideWeightedEdges hides the Edges and WeightedEdges
+// methods of a weighted graph.
+type hideWeightedEdges struct{ graph.Weighted }
+
+func TestCopyEdgeSlicer(t *testing.T) {
+	und := simple.NewWeightedUndirectedGraph(0, 0)
+	dir := simple.NewWeightedDirectedGraph(0, 0)
+	for _, e := range []simple.WeightedEdge{
+		{F: simple.Node(0), T: simple.Node(1), W: 1},
+		{F: simple.Node(1), T: simple.Node(2), W: 2},
+		{F: simple.Node(2), T: simple.Node(0), W: 3},
+		{F: simple.Node(2), T: simple.Node(3), W: 4},
+	} {
+		und.SetWeightedEdge(e)
+		dir.SetWeightedEdge(e)
+	}
+	und.AddNode(simple.Node(10))
+	dir.AddNode(simple.Node(10))
+
+	var (
+		_ graph.EdgeSlicer         = und
+		_ graph.WeightedEdgeSlicer = dir
+	)
+
+	for _, src := range []graph.Weighted{und, dir} {
+		for _, fn := range []func() graphBuilder{
+			func() graphBuilder { return simple.NewUndirectedGraph() },
+			func() graphBuilder { return simple.NewDirectedGraph() },
+		} {
+			fast := fn()
+			graph.Copy(fast, src)
+			slow := fn()
+			graph.Copy(slow, hideEdges{src})
+			if !same(fast, slow) || !same(slow, fast) {
+				t.Errorf("Copy from %T to %T differs with EdgeSlicer", src, fast)
+			}
+		}
+		for _, fn := range []func() weightedBuilder{
+			func() weightedBuilder { return simple.NewWeightedUndirectedGraph(0, 0) },
+			func() weightedBuilder { return simple.NewWeightedDirectedGraph(0, 0) },
+		} {
+			fast := fn()
+			graph.CopyWeighted(fast, src)
+			slow := fn()
+			graph.CopyWeighted(slow, hideWeightedEdges{src})
+			if !same(fast, slow) || !same(slow, fast) {
+				t.Errorf("CopyWeighted from %T to %T differs with WeightedEdgeSlicer", src, fast)
+			}
+		}
+	}
+}
@@ -0,0 +1,130 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gen
+
+import (
+	"fmt"
+	"math"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/simple"
+)
+
+// StochasticBlockModel constructs a stochastic block model graph in the
+// destination, dst. The nodes of the graph are partitioned into blocks with
+// the given sizes, and nodes are numbered from zero in block order so the
+// nodes of block i have IDs starting at the sum of the sizes of the blocks
+// before it. An edge from a node in block i to a node in block j is formed
+// with probability p[i][j]. If dst is undirected, p must be symmetric. If src
+// is not nil it is used as the random source, otherwise rand.Float64 is used.
+// The graph is constructed in O(n+m) time where n is the order of the graph
+// and m is the number of edges added.
+func StochasticBlockModel(dst GraphBuilder, sizes []int, p [][]float64, src *rand.Rand) error {
+	_, isDirected := dst.(graph.Directed)
+	if len(p) != len(sizes) {
+		return fmt.Errorf("gen: probability matrix size mismatch: %d blocks, %d rows", len(sizes), len(p))
+	}
+	for i, row := range p {
+		if len(row) != len(sizes) {
+			return fmt.Errorf("gen: probability matrix row %d length mismatch: %d blocks, %d columns", i, len(sizes), len(row))
+		}
+		for j, pij := range row {
+			if pij < 0 || pij > 1 {
+				return fmt.Errorf("gen: bad probability: p[%d][%d]=%v", i, j, pij)
+			}
+			if !isDirected && pij != p[j][i] {
+				return fmt.Errorf("gen: asymmetric probability for undirected graph: p[%d][%d]=%v p[%d][%d]=%v", i, j, pij, j, i, p[j][i])
+			}
+		}
+	}
+	offset := make([]int, len(sizes)+1)
+	for i, s := range sizes {
+		if s < 0 {
+			return fmt.Errorf("gen: bad block size: sizes[%d]=%d", i, s)
+		}
+		offset[i+1] = offset[i] + s
+	}
+
+	var r func() float64
+	if src == nil {
+		r = rand.Float64
+	} else {
+		r = src.Float64
+	}
+
+	for i := 0; i < offset[len(sizes)]; i++ {
+		if !dst.Has(simple.Node(i)) {
+			dst.AddNode(simple.Node(i))
+		}
+	}
+
+	for i, row := range p {
+		for j, pij := range row {
+			if !isDirected && j < i {
+				continue
+			}
+			si, sj := sizes[i], sizes[j]
+
+			// Each block pair has an enumeration of candidate
+			// edges, which is sampled by skipping a geometrically
+			// distributed number of candidates between edges as
+			// described for Gnp.
+			var (
+				n    int
+				edge func(k int) (u, v int)
+			)
+			switch {
+			case i != j:
+				n = si * sj
+				edge = func(k int) (u, v int) {
+					return offset[i] + k/sj, offset[j] + k%sj
+				}
+			case isDirected:
+				n = si * (si - 1)
+				edge = func(k int) (u, v int) {
+					u, v = k/(si-1), k%(si-1)
+					if v >= u {
+						v++
+					}
+					return offset[i] + u, offset[i] + v
+				}
+			default:
+				n = si * (si - 1) / 2
+				edge = func(k int) (u, v int) {
+					hi, lo := edgeNodesFor(k)
+					return offset[i] + int(lo), offset[i] + int(hi)
+				}
+			}
+			sample(n, pij, r, func(k int) {
+				u, v := edge(k)
+				dst.SetEdge(simple.Edge{F: simple.Node(u), T: simple.Node(v)})
+			})
+		}
+	}
+
+	return nil
+}
+
+// sample calls fn with the index of each of n candidates selected
+// independently with probability p, in increasing order of index.
+func sample(n int, p float64, r func() float64, fn func(k int)) {
+	if p == 0 {
+		return
+	}
+	lp := math.Log(1 - p)
+	for k := -1; ; {
+		// Check the skip before conversion
+		// to avoid integer overflow when p
+		// is small.
+		skip := math.Log(1-r()) / lp
+		if float64(k)+1+skip >= float64(n) {
+			return
+		}
+		k += 1 + int(skip)
+		fn(k)
+	}
+}
@@ -0,0 +1,159 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gen
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/simple"
+)
+
+func TestStochasticBlockModelUndirected(t *testing.T) {
+	sizes := []int{0, 1, 5, 8}
+	for _, q := range []float64{0, 0.1, 0.5, 1} {
+		p := [][]float64{
+			{q, q, q, q},
+			{q, 1, 0, q},
+			{q, 0, 1, q},
+			{q, q, q, 0},
+		}
+		g := &gnUndirected{UndirectedBuilder: simple.NewUndirectedGraph()}
+		err := StochasticBlockModel(g, sizes, p, nil)
+		if err != nil {
+			t.Fatalf("unexpected error: q=%v: %v", q, err)
+		}
+		if g.addBackwards {
+			t.Errorf("edge added with From.ID > To.ID: q=%v", q)
+		}
+		if g.addSelfLoop {
+			t.Errorf("unexpected self edge: q=%v", q)
+		}
+		if g.addMultipleEdge {
+			t.Errorf("unexpected multiple edge: q=%v", q)
+		}
+		if n := len(g.Nodes()); n != 14 {
+			t.Errorf("unexpected number of nodes: q=%v got:%d want:14", q, n)
+		}
+		checkBlocks(t, g, sizes, p)
+	}
+}
+
+func TestStochasticBlockModelDirected(t *testing.T) {
+	sizes := []int{1, 5, 8}
+	for _, q := range []float64{0, 0.1, 0.5, 1} {
+		p := [][]float64{
+			{1, 0, q},
+			{1, 1, 0},
+			{q, q, 0},
+		}
+		g := &gnDirected{DirectedBuilder: simple.NewDirectedGraph()}
+		err := StochasticBlockModel(g, sizes, p, nil)
+		if err != nil {
+			t.Fatalf("unexpected error: q=%v: %v", q, err)
+		}
+		if g.addSelfLoop {
+			t.Errorf("unexpected self edge: q=%v", q)
+		}
+		if g.addMultipleEdge {
+			t.Errorf("unexpected multiple edge: q=%v", q)
+		}
+		checkBlocks(t, g, sizes, p)
+	}
+}
+
+// checkBlocks checks that g has edges between all pairs of distinct nodes
+// in blocks with probability one and no edges between nodes in blocks with
+// probability zero.
+func checkBlocks(t *testing.T, g graph.Graph, sizes []int, p [][]float64) {
+	block := make(map[int64]int)
+	var id int64
+	for i, s := range sizes {
+		for k := 0; k < s; k++ {
+			block[id] = i
+			id++
+		}
+	}
+	hasEdge := g.HasEdgeBetween
+	if d, ok := g.(graph.Directed); ok {
+		hasEdge = d.HasEdgeFromTo
+	}
+	for _, u := range g.Nodes() {
+		for _, v := range g.Nodes() {
+			if u.ID() == v.ID() {
+				continue
+			}
+			switch pij := p[block[u.ID()]][block[v.ID()]]; {
+			case pij == 1 && !hasEdge(u, v):
+				t.Errorf("missing edge %d--%d with probability 1", u.ID(), v.ID())
+			case pij == 0 && hasEdge(u, v):
+				t.Errorf("unexpected edge %d--%d with probability 0", u.ID(), v.ID())
+			}
+		}
+	}
+}
+
+func TestStochasticBlockModelDensity(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	sizes := []int{200, 300}
+	p := [][]float64{
+		{0.1, 0.01},
+		{0.01, 0.2},
+	}
+	g := simple.NewUndirectedGraph()
+	err := StochasticBlockModel(g, sizes, p, rnd)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var count [2][2]float64
+	for _, e := range g.Edges() {
+		i, j := 0, 0
+		if e.From().ID() >= 200 {
+			i = 1
+		}
+		if e.To().ID() >= 200 {
+			j = 1
+		}
+		if i > j {
+			i, j = j, i
+		}
+		count[i][j]++
+	}
+	for i := 0; i < 2; i++ {
+		for j := i; j < 2; j++ {
+			n := float64(sizes[i] * sizes[j])
+			if i == j {
+				n = float64(sizes[i] * (sizes[i] - 1) / 2)
+			}
+			want := n * p[i][j]
+			// Allow four standard deviations.
+			if math.Abs(count[i][j]-want) > 4*math.Sqrt(n*p[i][j]*(1-p[i][j])) {
+				t.Errorf("unexpected number of edges between blocks %d and %d: got:%v want:%v", i, j, count[i][j], want)
+			}
+		}
+	}
+}
+
+func TestStochasticBlockModelErrors(t *testing.T) {
+	for _, test := range []struct {
+		name  string
+		sizes []int
+		p     [][]float64
+	}{
+		{name: "rows", sizes: []int{1, 2}, p: [][]float64{{0, 0}}},
+		{name: "columns", sizes: []int{1, 2}, p: [][]float64{{0, 0}, {0}}},
+		{name: "probability", sizes: []int{1, 2}, p: [][]float64{{0, 2}, {2, 0}}},
+		{name: "asymmetric", sizes: []int{1, 2}, p: [][]float64{{0, 0.5}, {0.1, 0}}},
+		{name: "size", sizes: []int{-1, 2}, p: [][]float64{{0, 0}, {0, 0}}},
+	} {
+		err := StochasticBlockModel(simple.NewUndirectedGraph(), test.sizes, test.p, nil)
+		if err == nil {
+			t.Errorf("expected error for bad %s", test.name)
+		}
+	}
+}
@@ -0,0 +1,31 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package instrument_test
+
+import (
+	"fmt"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/instrument"
+	"gonum.org/v1/gonum/graph/simple"
+	"gonum.org/v1/gonum/graph/topo"
+)
+
+func ExampleCounter() {
+	g := simple.NewUndirectedGraph()
+	for i := 0; i < 4; i++ {
+		g.SetEdge(simple.Edge{F: simple.Node(i), T: simple.Node(i + 1)})
+	}
+
+	// Count the graph queries made by an algorithm. The
+	// instrumented graph is undirected because g is.
+	var c instrument.Counter
+	ig := instrument.New(g, c.Hook).(graph.Undirected)
+	topo.ConnectedComponents(ig)
+	fmt.Printf("Nodes:%d From:%d\n", c.Count(instrument.Nodes), c.Count(instrument.From))
+
+	// Output:
+	// Nodes:1 From:5
+}
@@ -0,0 +1,231 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package instrument provides graphs that report the method calls made on
+// the graph they wrap.
+//
+// An instrumented graph can be passed to any graph algorithm in place of
+// the graph it wraps to measure the cost of the algorithm in terms of the
+// queries it makes, for example to expose operation counters from a
+// long-running service. Instrumentation is local to the wrapping graph;
+// other uses of the wrapped graph are not reported.
+package instrument // import "gonum.org/v1/gonum/graph/instrument"
+
+import (
+	"sync/atomic"
+
+	"gonum.org/v1/gonum/graph"
+)
+
+// Method is a graph method reported by an instrumented graph.
+type Method int
+
+const (
+	Has Method = iota
+	Nodes
+	From
+	To
+	HasEdgeBetween
+	HasEdgeFromTo
+	Edge
+	EdgeBetween
+	WeightedEdge
+	WeightedEdgeBetween
+	Weight
+
+	numMethods
+)
+
+var methodNames = [numMethods]string{
+	Has:                 "Has",
+	Nodes:               "Nodes",
+	From:                "From",
+	To:                  "To",
+	HasEdgeBetween:      "HasEdgeBetween",
+	HasEdgeFromTo:       "HasEdgeFromTo",
+	Edge:                "Edge",
+	EdgeBetween:         "EdgeBetween",
+	WeightedEdge:        "WeightedEdge",
+	WeightedEdgeBetween: "WeightedEdgeBetween",
+	Weight:              "Weight",
+}
+
+// String returns the name of the method.
+func (m Method) String() string {
+	if m < 0 || m >= numMethods {
+		return "Method(?)"
+	}
+	return methodNames[m]
+}
+
+// Hook is called by an instrumented graph before each call to a method
+// of the graph it wraps. A Hook must be safe for concurrent use if the
+// instrumented graph is used concurrently.
+type Hook func(m Method)
+
+// Counter counts the calls to each graph method. Counter is safe for
+// concurrent use.
+type Counter struct {
+	counts [numMethods]int64
+}
+
+// Hook records a call to m. Hook is intended to be passed to New as
+// c.Hook.
+func (c *Counter) Hook(m Method) {
+	atomic.AddInt64(&c.counts[m], 1)
+}
+
+// Count returns the number of recorded calls to m.
+func (c *Counter) Count(m Method) int64 {
+	return atomic.LoadInt64(&c.counts[m])
+}
+
+// Total returns the number of recorded calls to all methods.
+func (c *Counter) Total() int64 {
+	var n int64
+	for m := range c.counts {
+		n += atomic.LoadInt64(&c.counts[m])
+	}
+	return n
+}
+
+// Reset sets all the counts to zero.
+func (c *Counter) Reset() {
+	for m := range c.counts {
+		atomic.StoreInt64(&c.counts[m], 0)
+	}
+}
+
+// New returns a graph that calls hook before forwarding each method call
+// to g. The returned graph implements graph.Directed if g does, otherwise
+// graph.Undirected if g does, and graph.Weighted if g does, so algorithms
+// that depend on those interfaces behave as they would for g. A weighted
+// undirected g must implement graph.WeightedUndirected to be wrapped as an
+// undirected graph. Other interfaces implemented by g are not implemented
+// by the returned graph.
+// New panics if hook is nil.
+func New(g graph.Graph, hook Hook) graph.Graph {
+	if hook == nil {
+		panic("instrument: nil hook")
+	}
+	base := instrumented{g: g, hook: hook}
+	w, isWeighted := g.(graph.Weighted)
+	switch g := g.(type) {
+	case graph.Directed:
+		if isWeighted {
+			return weightedDirected{weighted: weighted{instrumented: base, w: w}, d: g}
+		}
+		return directed{instrumented: base, d: g}
+	case graph.Undirected:
+		if wu, ok := g.(graph.WeightedUndirected); ok {
+			return weightedUndirected{weighted: weighted{instrumented: base, w: w}, u: g, wu: wu}
+		}
+		return undirected{instrumented: base, u: g}
+	}
+	if isWeighted {
+		return weighted{instrumented: base, w: w}
+	}
+	return base
+}
+
+type instrumented struct {
+	g    graph.Graph
+	hook Hook
+}
+
+func (g instrumented) Has(n graph.Node) bool {
+	g.hook(Has)
+	return g.g.Has(n)
+}
+
+func (g instrumented) Nodes() []graph.Node {
+	g.hook(Nodes)
+	return g.g.Nodes()
+}
+
+func (g instrumented) From(n graph.Node) []graph.Node {
+	g.hook(From)
+	return g.g.From(n)
+}
+
+func (g instrumented) HasEdgeBetween(x, y graph.Node) bool {
+	g.hook(HasEdgeBetween)
+	return g.g.HasEdgeBetween(x, y)
+}
+
+func (g instrumented) Edge(u, v graph.Node) graph.Edge {
+	g.hook(Edge)
+	return g.g.Edge(u, v)
+}
+
+type weighted struct {
+	instrumented
+	w graph.Weighted
+}
+
+func (g weighted) WeightedEdge(u, v graph.Node) graph.WeightedEdge {
+	g.hook(WeightedEdge)
+	return g.w.WeightedEdge(u, v)
+}
+
+func (g weighted) Weight(x, y graph.Node) (w float64, ok bool) {
+	g.hook(Weight)
+	return g.w.Weight(x, y)
+}
+
+type directed struct {
+	instrumented
+	d graph.Directed
+}
+
+func (g directed) HasEdgeFromTo(u, v graph.Node) bool {
+	g.hook(HasEdgeFromTo)
+	return g.d.HasEdgeFromTo(u, v)
+}
+
+func (g directed) To(n graph.Node) []graph.Node {
+	g.hook(To)
+	return g.d.To(n)
+}
+
+type undirected struct {
+	instrumented
+	u graph.Undirected
+}
+
+func (g undirected) EdgeBetween(x, y graph.Node) graph.Edge {
+	g.hook(EdgeBetween)
+	return g.u.EdgeBetween(x, y)
+}
+
+type weightedDirected struct {
+	weighted
+	d graph.Directed
+}
+
+func (g weightedDirected) HasEdgeFromTo(u, v graph.Node) bool {
+	g.hook(HasEdgeFromTo)
+	return g.d.HasEdgeFromTo(u, v)
+}
+
+func (g weightedDirected) To(n graph.Node) []graph.Node {
+	g.hook(To)
+	return g.d.To(n)
+}
+
+type weightedUndirected struct {
+	weighted
+	u  graph.Undirected
+	wu graph.WeightedUndirected
+}
+
+func (g weightedUndirected) EdgeBetween(x, y graph.Node) graph.Edge {
+	g.hook(EdgeBetween)
+	return g.u.EdgeBetween(x, y)
+}
+
+func (g weightedUndirected) WeightedEdgeBetween(x, y graph.Node) graph.WeightedEdge {
+	g.hook(WeightedEdgeBetween)
+	return g.wu.WeightedEdgeBetween(x, y)
+}
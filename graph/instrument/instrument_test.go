@@ -0,0 +1,81 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package instrument
+
+import (
+	"testing"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/path"
+	"gonum.org/v1/gonum/graph/simple"
+)
+
+func TestNewInterfaces(t *testing.T) {
+	for _, test := range []struct {
+		name string
+		g    graph.Graph
+
+		directed, undirected, weighted, weightedUndirected bool
+	}{
+		{name: "directed", g: simple.NewDirectedGraph(), directed: true},
+		{name: "undirected", g: simple.NewUndirectedGraph(), undirected: true},
+		{name: "weighted directed", g: simple.NewWeightedDirectedGraph(0, 0), directed: true, weighted: true},
+		{name: "weighted undirected", g: simple.NewWeightedUndirectedGraph(0, 0), undirected: true, weighted: true, weightedUndirected: true},
+	} {
+		var c Counter
+		g := New(test.g, c.Hook)
+		if _, ok := g.(graph.Directed); ok != test.directed {
+			t.Errorf("unexpected graph.Directed implementation for %s: got:%t want:%t", test.name, ok, test.directed)
+		}
+		if _, ok := g.(graph.Undirected); ok != test.undirected {
+			t.Errorf("unexpected graph.Undirected implementation for %s: got:%t want:%t", test.name, ok, test.undirected)
+		}
+		if _, ok := g.(graph.Weighted); ok != test.weighted {
+			t.Errorf("unexpected graph.Weighted implementation for %s: got:%t want:%t", test.name, ok, test.weighted)
+		}
+		if _, ok := g.(graph.WeightedUndirected); ok != test.weightedUndirected {
+			t.Errorf("unexpected graph.WeightedUndirected implementation for %s: got:%t want:%t", test.name, ok, test.weightedUndirected)
+		}
+	}
+}
+
+func TestCounter(t *testing.T) {
+	g := simple.NewWeightedDirectedGraph(0, 0)
+	for _, e := range []simple.WeightedEdge{
+		{F: simple.Node(0), T: simple.Node(1), W: 1},
+		{F: simple.Node(1), T: simple.Node(2), W: 2},
+		{F: simple.Node(0), T: simple.Node(2), W: 4},
+	} {
+		g.SetWeightedEdge(e)
+	}
+
+	var c Counter
+	ig := New(g, c.Hook)
+	want := path.DijkstraFrom(simple.Node(0), g)
+	got := path.DijkstraFrom(simple.Node(0), ig)
+	if got.WeightTo(simple.Node(2)) != want.WeightTo(simple.Node(2)) {
+		t.Errorf("unexpected path weight: got:%v want:%v", got.WeightTo(simple.Node(2)), want.WeightTo(simple.Node(2)))
+	}
+	if c.Count(From) == 0 || c.Count(Weight) == 0 {
+		t.Errorf("expected calls to From and Weight: got From:%d Weight:%d", c.Count(From), c.Count(Weight))
+	}
+	var total int64
+	for m := Method(0); m < numMethods; m++ {
+		total += c.Count(m)
+	}
+	if c.Total() != total {
+		t.Errorf("unexpected total: got:%d want:%d", c.Total(), total)
+	}
+
+	c.Reset()
+	if c.Total() != 0 {
+		t.Errorf("unexpected total after reset: got:%d", c.Total())
+	}
+	ig.Has(simple.Node(0))
+	ig.(graph.Directed).To(simple.Node(2))
+	if c.Count(Has) != 1 || c.Count(To) != 1 || c.Total() != 2 {
+		t.Errorf("unexpected counts: got Has:%d To:%d total:%d", c.Count(Has), c.Count(To), c.Total())
+	}
+}
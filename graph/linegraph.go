@@ -0,0 +1,100 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package graph
+
+// LineGraph adds the line graph of the source to the destination without first
+// clearing the destination, and returns a map from the IDs of the nodes added to
+// the destination to the source edges they represent. The nodes of the line graph
+// are created with dst.NewNode, one for each edge of the source.
+//
+// If the source is directed, the line graph has an edge from the node for edge e
+// to the node for edge f for each pair of edges where e.To is f.From. Otherwise
+// the line graph has an edge between the nodes for each pair of distinct edges
+// sharing a terminal node; if the destination is directed both directions will
+// be present. Self edges of the line graph, which arise from source self edges,
+// are not added.
+func LineGraph(dst Builder, src Graph) map[int64]Edge {
+	return lineGraph(dst, src, func(x, y Node, _, _ Edge, both bool) {
+		dst.SetEdge(dst.NewEdge(x, y))
+		if both {
+			dst.SetEdge(dst.NewEdge(y, x))
+		}
+	})
+}
+
+// LineGraphWeighted adds the line graph of the source to the destination in the
+// same way as LineGraph, and returns a map from the IDs of the nodes added to the
+// destination to the source edges they represent. The weight of the line graph
+// edge between the nodes for source edges e and f is given by weight(e, f), where
+// e and f are the edges returned by src.Edge. For directed sources e.To is f.From.
+// For undirected sources weight is called once for each pair of adjacent edges and
+// the returned weight is used for both directions in a directed destination.
+func LineGraphWeighted(dst WeightedBuilder, src Graph, weight func(e, f Edge) float64) map[int64]Edge {
+	return lineGraph(dst, src, func(x, y Node, e, f Edge, both bool) {
+		w := weight(e, f)
+		dst.SetWeightedEdge(dst.NewWeightedEdge(x, y, w))
+		if both {
+			dst.SetWeightedEdge(dst.NewWeightedEdge(y, x, w))
+		}
+	})
+}
+
+// lineGraph adds the nodes of the line graph of src to dst and calls set for
+// each line graph edge from x to y corresponding to source edges e and f. If
+// both is true, the line graph edge is undirected.
+func lineGraph(dst NodeAdder, src Graph, set func(x, y Node, e, f Edge, both bool)) map[int64]Edge {
+	_, directed := src.(Directed)
+
+	nodes := sortedNodes(src.Nodes())
+	ids := make(map[[2]int64]Node)
+	edges := make(map[int64]Edge)
+	for _, u := range nodes {
+		for _, v := range sortedNodes(src.From(u)) {
+			if !directed && v.ID() < u.ID() {
+				continue
+			}
+			e := src.Edge(u, v)
+			n := dst.NewNode()
+			dst.AddNode(n)
+			ids[[2]int64{u.ID(), v.ID()}] = n
+			edges[n.ID()] = e
+		}
+	}
+
+	if directed {
+		d := src.(Directed)
+		for _, n := range nodes {
+			out := sortedNodes(d.From(n))
+			for _, p := range sortedNodes(d.To(n)) {
+				x := ids[[2]int64{p.ID(), n.ID()}]
+				for _, s := range out {
+					y := ids[[2]int64{n.ID(), s.ID()}]
+					if x.ID() == y.ID() {
+						continue
+					}
+					set(x, y, edges[x.ID()], edges[y.ID()], false)
+				}
+			}
+		}
+		return edges
+	}
+
+	for _, n := range nodes {
+		var incident []Node
+		for _, v := range sortedNodes(src.From(n)) {
+			key := [2]int64{n.ID(), v.ID()}
+			if v.ID() < n.ID() {
+				key[0], key[1] = key[1], key[0]
+			}
+			incident = append(incident, ids[key])
+		}
+		for i, x := range incident {
+			for _, y := range incident[i+1:] {
+				set(x, y, edges[x.ID()], edges[y.ID()], true)
+			}
+		}
+	}
+	return edges
+}
@@ -0,0 +1,160 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package graph_test
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/simple"
+)
+
+// lineEdges returns the edges of the line graph g as pairs of source
+// edge end point IDs, sorted.
+func lineEdges(g graph.Graph, edges map[int64]graph.Edge, undirected bool) [][2][2]int64 {
+	end := func(n graph.Node) [2]int64 {
+		e := edges[n.ID()]
+		ids := [2]int64{e.From().ID(), e.To().ID()}
+		if undirected && ids[0] > ids[1] {
+			ids[0], ids[1] = ids[1], ids[0]
+		}
+		return ids
+	}
+	var got [][2][2]int64
+	for _, u := range g.Nodes() {
+		for _, v := range g.From(u) {
+			got = append(got, [2][2]int64{end(u), end(v)})
+		}
+	}
+	less := func(a, b [2]int64) bool {
+		return a[0] < b[0] || (a[0] == b[0] && a[1] < b[1])
+	}
+	sort.Slice(got, func(i, j int) bool {
+		if got[i][0] != got[j][0] {
+			return less(got[i][0], got[j][0])
+		}
+		return less(got[i][1], got[j][1])
+	})
+	return got
+}
+
+func TestLineGraphDirected(t *testing.T) {
+	src := simple.NewDirectedGraph()
+	for _, e := range []simple.Edge{
+		{F: simple.Node(0), T: simple.Node(1)},
+		{F: simple.Node(1), T: simple.Node(2)},
+		{F: simple.Node(1), T: simple.Node(3)},
+		{F: simple.Node(2), T: simple.Node(0)},
+	} {
+		src.SetEdge(e)
+	}
+	dst := simple.NewDirectedGraph()
+	edges := graph.LineGraph(dst, src)
+	if len(edges) != 4 || len(dst.Nodes()) != 4 {
+		t.Fatalf("unexpected number of line graph nodes: got:%d want:4", len(dst.Nodes()))
+	}
+	got := lineEdges(dst, edges, false)
+	want := [][2][2]int64{
+		{{0, 1}, {1, 2}},
+		{{0, 1}, {1, 3}},
+		{{1, 2}, {2, 0}},
+		{{2, 0}, {0, 1}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected line graph edges:\ngot: %v\nwant:%v", got, want)
+	}
+}
+
+func TestLineGraphUndirected(t *testing.T) {
+	// The line graph of a star is complete.
+	src := simple.NewUndirectedGraph()
+	for _, e := range []simple.Edge{
+		{F: simple.Node(0), T: simple.Node(1)},
+		{F: simple.Node(0), T: simple.Node(2)},
+		{F: simple.Node(3), T: simple.Node(0)},
+		{F: simple.Node(3), T: simple.Node(4)},
+	} {
+		src.SetEdge(e)
+	}
+
+	for _, test := range []struct {
+		name string
+		dst  graphBuilder
+		want [][2][2]int64
+	}{
+		{
+			name: "undirected",
+			dst:  simple.NewUndirectedGraph(),
+			want: [][2][2]int64{
+				{{0, 1}, {0, 2}},
+				{{0, 1}, {0, 3}},
+				{{0, 2}, {0, 1}},
+				{{0, 2}, {0, 3}},
+				{{0, 3}, {0, 1}},
+				{{0, 3}, {0, 2}},
+				{{0, 3}, {3, 4}},
+				{{3, 4}, {0, 3}},
+			},
+		},
+		{
+			name: "directed",
+			dst:  simple.NewDirectedGraph(),
+			want: [][2][2]int64{
+				{{0, 1}, {0, 2}},
+				{{0, 1}, {0, 3}},
+				{{0, 2}, {0, 1}},
+				{{0, 2}, {0, 3}},
+				{{0, 3}, {0, 1}},
+				{{0, 3}, {0, 2}},
+				{{0, 3}, {3, 4}},
+				{{3, 4}, {0, 3}},
+			},
+		},
+	} {
+		edges := graph.LineGraph(test.dst, src)
+		if len(edges) != 4 {
+			t.Errorf("unexpected number of line graph nodes for %s destination: got:%d want:4", test.name, len(edges))
+		}
+		got := lineEdges(test.dst, edges, true)
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("unexpected line graph edges for %s destination:\ngot: %v\nwant:%v", test.name, got, test.want)
+		}
+	}
+}
+
+func TestLineGraphWeighted(t *testing.T) {
+	src := simple.NewWeightedDirectedGraph(0, 0)
+	for _, e := range []simple.WeightedEdge{
+		{F: simple.Node(0), T: simple.Node(1), W: 1},
+		{F: simple.Node(1), T: simple.Node(2), W: 2},
+		{F: simple.Node(1), T: simple.Node(3), W: 4},
+	} {
+		src.SetWeightedEdge(e)
+	}
+	dst := simple.NewWeightedDirectedGraph(0, 0)
+	edges := graph.LineGraphWeighted(dst, src, func(e, f graph.Edge) float64 {
+		if e.To().ID() != f.From().ID() {
+			t.Errorf("edges not adjacent: %v %v", e, f)
+		}
+		return e.(graph.WeightedEdge).Weight() + f.(graph.WeightedEdge).Weight()
+	})
+	want := map[[2][2]int64]float64{
+		{{0, 1}, {1, 2}}: 3,
+		{{0, 1}, {1, 3}}: 5,
+	}
+	got := make(map[[2][2]int64]float64)
+	for _, u := range dst.Nodes() {
+		for _, v := range dst.From(u) {
+			eu, ev := edges[u.ID()], edges[v.ID()]
+			key := [2][2]int64{{eu.From().ID(), eu.To().ID()}, {ev.From().ID(), ev.To().ID()}}
+			got[key] = dst.WeightedEdge(u, v).Weight()
+		}
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected line graph weights: got:%v want:%v", got, want)
+	}
+}
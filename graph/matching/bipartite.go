@@ -0,0 +1,54 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package matching
+
+import (
+	"sort"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/internal/ordered"
+)
+
+// Bipartite returns whether the undirected graph g is bipartite and, if it
+// is, a partition of its nodes into two color classes such that every edge
+// of g joins a node in u to a node in v. In each connected component, the
+// node with the lowest ID is placed in u. The color classes are sorted by ID.
+// A graph with a self edge is not bipartite.
+func Bipartite(g graph.Undirected) (u, v []graph.Node, ok bool) {
+	nodes := g.Nodes()
+	sort.Sort(ordered.ByID(nodes))
+	color := make(map[int64]bool, len(nodes))
+	for _, n := range nodes {
+		if _, seen := color[n.ID()]; seen {
+			continue
+		}
+		color[n.ID()] = false
+		queue := []graph.Node{n}
+		for len(queue) != 0 {
+			x := queue[0]
+			queue = queue[1:]
+			cx := color[x.ID()]
+			for _, y := range g.From(x) {
+				cy, seen := color[y.ID()]
+				if !seen {
+					color[y.ID()] = !cx
+					queue = append(queue, y)
+					continue
+				}
+				if cy == cx {
+					return nil, nil, false
+				}
+			}
+		}
+	}
+	for _, n := range nodes {
+		if color[n.ID()] {
+			v = append(v, n)
+		} else {
+			u = append(u, n)
+		}
+	}
+	return u, v, true
+}
@@ -0,0 +1,88 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package matching
+
+import (
+	"fmt"
+	"testing"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/simple"
+)
+
+var bipartiteTests = []struct {
+	name  string
+	nodes []int64
+	edges [][2]int64
+
+	wantOK bool
+	wantU  []int64
+	wantV  []int64
+}{
+	{
+		name:   "empty",
+		wantOK: true,
+	},
+	{
+		name:   "isolated",
+		nodes:  []int64{0, 1, 2},
+		wantOK: true,
+		wantU:  []int64{0, 1, 2},
+	},
+	{
+		name:   "even cycle",
+		edges:  [][2]int64{{0, 1}, {1, 2}, {2, 3}, {3, 0}},
+		wantOK: true,
+		wantU:  []int64{0, 2},
+		wantV:  []int64{1, 3},
+	},
+	{
+		name:  "odd cycle",
+		edges: [][2]int64{{0, 1}, {1, 2}, {2, 3}, {3, 4}, {4, 0}},
+	},
+	{
+		name:   "two components",
+		edges:  [][2]int64{{0, 1}, {1, 2}, {5, 3}, {3, 4}},
+		wantOK: true,
+		wantU:  []int64{0, 2, 3},
+		wantV:  []int64{1, 4, 5},
+	},
+}
+
+func undirectedFrom(nodes []int64, edges [][2]int64) *simple.UndirectedGraph {
+	g := simple.NewUndirectedGraph()
+	for _, id := range nodes {
+		g.AddNode(simple.Node(id))
+	}
+	for _, e := range edges {
+		g.SetEdge(simple.Edge{F: simple.Node(e[0]), T: simple.Node(e[1])})
+	}
+	return g
+}
+
+func ids(nodes []graph.Node) []int64 {
+	var ids []int64
+	for _, n := range nodes {
+		ids = append(ids, n.ID())
+	}
+	return ids
+}
+
+func TestBipartite(t *testing.T) {
+	for _, test := range bipartiteTests {
+		g := undirectedFrom(test.nodes, test.edges)
+		u, v, ok := Bipartite(g)
+		if ok != test.wantOK {
+			t.Errorf("unexpected bipartite result for %q: got:%t want:%t", test.name, ok, test.wantOK)
+			continue
+		}
+		if got := fmt.Sprint(ids(u)); got != fmt.Sprint(test.wantU) {
+			t.Errorf("unexpected first color class for %q: got:%v want:%v", test.name, got, test.wantU)
+		}
+		if got := fmt.Sprint(ids(v)); got != fmt.Sprint(test.wantV) {
+			t.Errorf("unexpected second color class for %q: got:%v want:%v", test.name, got, test.wantV)
+		}
+	}
+}
@@ -0,0 +1,179 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package matching
+
+import (
+	"sort"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/internal/ordered"
+)
+
+// MaximumMatching returns a maximum cardinality matching of the undirected
+// graph g using Edmonds' blossom algorithm doi:10.4153/CJM-1965-045-4.
+// Self edges are ignored.
+//
+// The time complexity of MaximumMatching is O(|V|^3).
+func MaximumMatching(g graph.Undirected) Matching {
+	nodes := g.Nodes()
+	sort.Sort(ordered.ByID(nodes))
+	indexOf := make(map[int64]int, len(nodes))
+	for i, n := range nodes {
+		indexOf[n.ID()] = i
+	}
+	b := &blossom{
+		adj:     make([][]int, len(nodes)),
+		match:   make([]int, len(nodes)),
+		parent:  make([]int, len(nodes)),
+		base:    make([]int, len(nodes)),
+		used:    make([]bool, len(nodes)),
+		inPath:  make([]bool, len(nodes)),
+		blossom: make([]bool, len(nodes)),
+	}
+	for i, u := range nodes {
+		b.match[i] = -1
+		to := g.From(u)
+		sort.Sort(ordered.ByID(to))
+		for _, v := range to {
+			if j := indexOf[v.ID()]; j != i {
+				b.adj[i] = append(b.adj[i], j)
+			}
+		}
+	}
+
+	for root := range nodes {
+		if b.match[root] != -1 {
+			continue
+		}
+		// Augment the matching along the path
+		// found from root to an unmatched node.
+		for v := b.augmentingPath(root); v != -1; {
+			pv := b.parent[v]
+			next := b.match[pv]
+			b.match[v] = pv
+			b.match[pv] = v
+			v = next
+		}
+	}
+
+	mate := make(map[int64]graph.Node)
+	for i, j := range b.match {
+		if j != -1 {
+			mate[nodes[i].ID()] = nodes[j]
+		}
+	}
+	return newMatching(g, mate)
+}
+
+// blossom holds the state of Edmonds' blossom algorithm.
+type blossom struct {
+	adj [][]int
+
+	// match holds the index of the mate of
+	// each node, or -1 if it is unmatched.
+	match []int
+
+	// parent holds the parent of each odd node
+	// in the alternating tree and base holds
+	// the base of the blossom containing each
+	// node.
+	parent []int
+	base   []int
+
+	// used marks the even nodes of the alternating
+	// tree, and inPath and blossom are work space
+	// for finding and contracting blossoms.
+	used    []bool
+	inPath  []bool
+	blossom []bool
+}
+
+// augmentingPath searches for an augmenting path from the unmatched node
+// root using an alternating tree, contracting blossoms as they are found.
+// It returns the unmatched node at the end of the path, or -1 if there is
+// no augmenting path. The path is held in the parent and match fields.
+func (b *blossom) augmentingPath(root int) int {
+	for i := range b.base {
+		b.used[i] = false
+		b.parent[i] = -1
+		b.base[i] = i
+	}
+	b.used[root] = true
+	queue := []int{root}
+	for len(queue) != 0 {
+		v := queue[0]
+		queue = queue[1:]
+		for _, to := range b.adj[v] {
+			if b.base[v] == b.base[to] || b.match[v] == to {
+				continue
+			}
+			if to == root || (b.match[to] != -1 && b.parent[b.match[to]] != -1) {
+				// v and to are both even, so the
+				// edge between them closes a blossom.
+				base := b.lca(v, to)
+				for i := range b.blossom {
+					b.blossom[i] = false
+				}
+				b.markPath(v, base, to)
+				b.markPath(to, base, v)
+				for i := range b.base {
+					if b.blossom[b.base[i]] {
+						b.base[i] = base
+						if !b.used[i] {
+							b.used[i] = true
+							queue = append(queue, i)
+						}
+					}
+				}
+				continue
+			}
+			if b.parent[to] == -1 {
+				b.parent[to] = v
+				if b.match[to] == -1 {
+					return to
+				}
+				b.used[b.match[to]] = true
+				queue = append(queue, b.match[to])
+			}
+		}
+	}
+	return -1
+}
+
+// lca returns the base of the lowest common ancestor
+// of the even nodes u and v in the alternating tree.
+func (b *blossom) lca(u, v int) int {
+	for i := range b.inPath {
+		b.inPath[i] = false
+	}
+	for {
+		u = b.base[u]
+		b.inPath[u] = true
+		if b.match[u] == -1 {
+			break
+		}
+		u = b.parent[b.match[u]]
+	}
+	for {
+		v = b.base[v]
+		if b.inPath[v] {
+			return v
+		}
+		v = b.parent[b.match[v]]
+	}
+}
+
+// markPath marks the blossoms on the path from v to the blossom base,
+// setting the parents of odd nodes on the path so that augmenting paths
+// may pass through the blossom in either direction.
+func (b *blossom) markPath(v, base, child int) {
+	for b.base[v] != base {
+		b.blossom[b.base[v]] = true
+		b.blossom[b.base[b.match[v]]] = true
+		b.parent[v] = child
+		child = b.match[v]
+		v = b.parent[b.match[v]]
+	}
+}
@@ -0,0 +1,140 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package matching
+
+import (
+	"fmt"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/simple"
+)
+
+var maximumMatchingTests = []struct {
+	name    string
+	nodes   []int64
+	edges   [][2]int64
+	wantLen int
+}{
+	{
+		name: "empty",
+	},
+	{
+		name:    "triangle",
+		edges:   [][2]int64{{0, 1}, {1, 2}, {2, 0}},
+		wantLen: 1,
+	},
+	{
+		// The matching 1-2, 3-4 must be augmented
+		// through the blossom 2-3-4 to 0 and 5.
+		name: "blossom",
+		edges: [][2]int64{
+			{0, 1}, {1, 2}, {2, 3}, {3, 4}, {4, 2}, {4, 5},
+		},
+		wantLen: 3,
+	},
+	{
+		name: "petersen",
+		edges: [][2]int64{
+			{0, 1}, {1, 2}, {2, 3}, {3, 4}, {4, 0},
+			{0, 5}, {1, 6}, {2, 7}, {3, 8}, {4, 9},
+			{5, 7}, {7, 9}, {9, 6}, {6, 8}, {8, 5},
+		},
+		wantLen: 5,
+	},
+	{
+		name:    "star with isolated node",
+		nodes:   []int64{10},
+		edges:   [][2]int64{{0, 1}, {0, 2}, {0, 3}},
+		wantLen: 1,
+	},
+}
+
+func TestMaximumMatching(t *testing.T) {
+	for _, test := range maximumMatchingTests {
+		g := undirectedFrom(test.nodes, test.edges)
+		m := MaximumMatching(g)
+		if m.Len() != test.wantLen {
+			t.Errorf("unexpected matching size for %q: got:%d want:%d", test.name, m.Len(), test.wantLen)
+		}
+		checkMatching(t, test.name, g, m)
+	}
+}
+
+func TestMaximumMatchingRandom(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for trial := 0; trial < 200; trial++ {
+		n := 2 + rnd.Intn(9)
+		g := simple.NewUndirectedGraph()
+		for i := 0; i < n; i++ {
+			g.AddNode(simple.Node(i))
+		}
+		p := rnd.Float64()
+		for i := 0; i < n; i++ {
+			for j := i + 1; j < n; j++ {
+				if rnd.Float64() < p {
+					g.SetEdge(simple.Edge{F: simple.Node(i), T: simple.Node(j)})
+				}
+			}
+		}
+		name := fmt.Sprintf("trial %d", trial)
+		m := MaximumMatching(g)
+		checkMatching(t, name, g, m)
+		want, _ := bruteForceMatching(g, false)
+		if m.Len() != want {
+			t.Errorf("unexpected matching size for %s: got:%d want:%d", name, m.Len(), want)
+		}
+		if hk, ok := HopcroftKarp(g); ok && hk.Len() != m.Len() {
+			t.Errorf("matching size does not agree with HopcroftKarp for bipartite %s: got:%d want:%d", name, m.Len(), hk.Len())
+		}
+	}
+}
+
+// bruteForceMatching returns the size of a maximum cardinality matching
+// of g and the maximum weight of a matching of g with that size. If
+// maxWeight is true, the maximum weight of any matching is returned
+// instead of the weight for the maximum cardinality.
+func bruteForceMatching(g graph.Undirected, maxWeight bool) (size int, weight float64) {
+	var edges [][3]float64
+	for _, u := range g.Nodes() {
+		for _, v := range g.From(u) {
+			if u.ID() >= v.ID() {
+				continue
+			}
+			w := 1.0
+			if wg, ok := g.(graph.Weighted); ok {
+				w, _ = wg.Weight(u, v)
+			}
+			edges = append(edges, [3]float64{float64(u.ID()), float64(v.ID()), w})
+		}
+	}
+	used := make(map[int64]bool)
+	first := true
+	var search func(k, n int, w float64)
+	search = func(k, n int, w float64) {
+		if k == len(edges) {
+			if maxWeight {
+				if first || w > weight {
+					size, weight = n, w
+				}
+			} else if first || n > size || (n == size && w > weight) {
+				size, weight = n, w
+			}
+			first = false
+			return
+		}
+		search(k+1, n, w)
+		u, v := int64(edges[k][0]), int64(edges[k][1])
+		if !used[u] && !used[v] {
+			used[u], used[v] = true, true
+			search(k+1, n+1, w+edges[k][2])
+			used[u], used[v] = false, false
+		}
+	}
+	search(0, 0, 0)
+	return size, weight
+}
@@ -0,0 +1,747 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package matching
+
+import (
+	"math"
+	"sort"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/internal/ordered"
+)
+
+// MaximumWeightMatching returns a maximum weight matching of the undirected
+// graph g and its total weight. If maxCardinality is true, the returned
+// matching has the maximum weight among all maximum cardinality matchings,
+// otherwise edges with a negative weight are never matched. Self edges are
+// ignored.
+//
+// MaximumWeightMatching uses the primal-dual blossom algorithm of Edmonds,
+// with the O(|V|^3) implementation described by Galil doi:10.1145/6462.6502.
+// Results are exact when edge weights are integers that are exactly
+// representable, otherwise they are subject to floating point rounding.
+func MaximumWeightMatching(g graph.WeightedUndirected, maxCardinality bool) (m Matching, weight float64) {
+	nodes := g.Nodes()
+	sort.Sort(ordered.ByID(nodes))
+	indexOf := make(map[int64]int, len(nodes))
+	for i, n := range nodes {
+		indexOf[n.ID()] = i
+	}
+	var edges []weightedBlossomEdge
+	for i, u := range nodes {
+		to := g.From(u)
+		sort.Sort(ordered.ByID(to))
+		for _, v := range to {
+			j := indexOf[v.ID()]
+			if j <= i {
+				continue
+			}
+			w, ok := g.Weight(u, v)
+			if !ok {
+				panic("matching: unexpected invalid weight")
+			}
+			edges = append(edges, weightedBlossomEdge{i: i, j: j, w: w})
+		}
+	}
+
+	mate := make(map[int64]graph.Node)
+	if len(edges) != 0 {
+		wb := newWeightedBlossom(len(nodes), edges)
+		wb.solve(maxCardinality)
+		for i, p := range wb.mate {
+			if p == -1 {
+				continue
+			}
+			j := wb.endpoint[p]
+			mate[nodes[i].ID()] = nodes[j]
+			if i < j {
+				weight += edges[p/2].w
+			}
+		}
+	}
+	return newMatching(g, mate), weight
+}
+
+// weightedBlossomEdge is an edge between the nodes
+// with indices i and j with weight w.
+type weightedBlossomEdge struct {
+	i, j int
+	w    float64
+}
+
+// Node and blossom labels.
+const (
+	free  = 0
+	outer = 1 // S-labeled, even.
+	inner = 2 // T-labeled, odd.
+
+	// breadcrumb marks blossoms visited during
+	// a call to scanBlossom.
+	breadcrumb = 4
+)
+
+// weightedBlossom holds the state of the primal-dual weighted blossom
+// algorithm. The implementation follows that of Joris van Rantwijk.
+//
+// Nodes are indexed 0 to n-1 and non-trivial blossoms are indexed n
+// to 2n-1. Each edge k has the end points 2k and 2k+1, where endpoint
+// 2k is edges[k].i and endpoint 2k+1 is edges[k].j, so the end point
+// at the other end of end point p is p^1.
+type weightedBlossom struct {
+	n     int
+	edges []weightedBlossomEdge
+
+	// endpoint holds the node of each end point
+	// and neighbend holds the remote end points
+	// of the edges incident to each node.
+	endpoint  []int
+	neighbend [][]int
+
+	// mate holds the remote end point of the
+	// matched edge of each node, or -1 if the
+	// node is not matched.
+	mate []int
+
+	// label holds the label of each top-level
+	// blossom and node, and labelend holds the
+	// end point through which the label was
+	// assigned, or -1 for a free root.
+	label    []int
+	labelend []int
+
+	// inblossom holds the top-level blossom
+	// containing each node.
+	inblossom []int
+
+	// blossomparent holds the immediate parent
+	// of each blossom or -1 for a top-level
+	// blossom, blossomchilds holds the ordered
+	// sub-blossoms of each blossom starting at
+	// the base, blossombase holds the base node
+	// of each blossom and blossomendps holds the
+	// end points of the edges joining children.
+	blossomparent []int
+	blossomchilds [][]int
+	blossombase   []int
+	blossomendps  [][]int
+
+	// bestedge holds the least-slack edge to an
+	// outer blossom for each node and top-level
+	// blossom, and blossombestedges holds the
+	// least-slack edges to neighboring outer
+	// blossoms for each outer blossom.
+	bestedge         []int
+	blossombestedges [][]int
+
+	unusedblossoms []int
+
+	// dualvar holds the dual variables of the
+	// nodes and blossoms. Node duals are twice
+	// the conventional values.
+	dualvar []float64
+
+	// allowedge marks edges with zero slack.
+	allowedge []bool
+
+	queue []int
+}
+
+// newWeightedBlossom returns a weightedBlossom for the n nodes and edges
+// with an empty matching.
+func newWeightedBlossom(n int, edges []weightedBlossomEdge) *weightedBlossom {
+	maxWeight := 0.0
+	for _, e := range edges {
+		maxWeight = math.Max(maxWeight, e.w)
+	}
+
+	wb := &weightedBlossom{
+		n:                n,
+		edges:            edges,
+		endpoint:         make([]int, 2*len(edges)),
+		neighbend:        make([][]int, n),
+		mate:             make([]int, n),
+		label:            make([]int, 2*n),
+		labelend:         make([]int, 2*n),
+		inblossom:        make([]int, n),
+		blossomparent:    make([]int, 2*n),
+		blossomchilds:    make([][]int, 2*n),
+		blossombase:      make([]int, 2*n),
+		blossomendps:     make([][]int, 2*n),
+		bestedge:         make([]int, 2*n),
+		blossombestedges: make([][]int, 2*n),
+		dualvar:          make([]float64, 2*n),
+		allowedge:        make([]bool, len(edges)),
+	}
+	for k, e := range edges {
+		wb.endpoint[2*k] = e.i
+		wb.endpoint[2*k+1] = e.j
+		wb.neighbend[e.i] = append(wb.neighbend[e.i], 2*k+1)
+		wb.neighbend[e.j] = append(wb.neighbend[e.j], 2*k)
+	}
+	for i := 0; i < 2*n; i++ {
+		wb.labelend[i] = -1
+		wb.blossomparent[i] = -1
+		wb.bestedge[i] = -1
+		if i < n {
+			wb.mate[i] = -1
+			wb.inblossom[i] = i
+			wb.blossombase[i] = i
+			wb.dualvar[i] = maxWeight
+		} else {
+			wb.blossombase[i] = -1
+			wb.unusedblossoms = append(wb.unusedblossoms, i)
+		}
+	}
+	return wb
+}
+
+// slack returns twice the slack of edge k.
+func (wb *weightedBlossom) slack(k int) float64 {
+	e := wb.edges[k]
+	return wb.dualvar[e.i] + wb.dualvar[e.j] - 2*e.w
+}
+
+// leaves returns the nodes contained in blossom b.
+func (wb *weightedBlossom) leaves(b int) []int {
+	if b < wb.n {
+		return []int{b}
+	}
+	var l []int
+	for _, t := range wb.blossomchilds[b] {
+		if t < wb.n {
+			l = append(l, t)
+		} else {
+			l = append(l, wb.leaves(t)...)
+		}
+	}
+	return l
+}
+
+// assignLabel assigns label t to the top-level blossom containing node w
+// via end point p, and labels the mate of an inner blossom's base as outer.
+func (wb *weightedBlossom) assignLabel(w, t, p int) {
+	b := wb.inblossom[w]
+	wb.label[w] = t
+	wb.label[b] = t
+	wb.labelend[w] = p
+	wb.labelend[b] = p
+	wb.bestedge[w] = -1
+	wb.bestedge[b] = -1
+	switch t {
+	case outer:
+		wb.queue = append(wb.queue, wb.leaves(b)...)
+	case inner:
+		base := wb.blossombase[b]
+		wb.assignLabel(wb.endpoint[wb.mate[base]], outer, wb.mate[base]^1)
+	}
+}
+
+// scanBlossom traces back from nodes v and w to discover either a new
+// blossom or an augmenting path. It returns the base node of the new
+// blossom or -1 if an augmenting path was found.
+func (wb *weightedBlossom) scanBlossom(v, w int) int {
+	var path []int
+	base := -1
+	for v != -1 || w != -1 {
+		b := wb.inblossom[v]
+		if wb.label[b]&breadcrumb != 0 {
+			base = wb.blossombase[b]
+			break
+		}
+		path = append(path, b)
+		wb.label[b] = outer | breadcrumb
+		if wb.labelend[b] == -1 {
+			// Reached a free root.
+			v = -1
+		} else {
+			v = wb.endpoint[wb.labelend[b]]
+			b = wb.inblossom[v]
+			v = wb.endpoint[wb.labelend[b]]
+		}
+		if w != -1 {
+			v, w = w, v
+		}
+	}
+	for _, b := range path {
+		wb.label[b] = outer
+	}
+	return base
+}
+
+// addBlossom constructs a new blossom with the given base, containing
+// edge k which connects a pair of outer nodes.
+func (wb *weightedBlossom) addBlossom(base, k int) {
+	v, w := wb.edges[k].i, wb.edges[k].j
+	bb := wb.inblossom[base]
+	bv := wb.inblossom[v]
+	bw := wb.inblossom[w]
+
+	b := wb.unusedblossoms[len(wb.unusedblossoms)-1]
+	wb.unusedblossoms = wb.unusedblossoms[:len(wb.unusedblossoms)-1]
+	wb.blossombase[b] = base
+	wb.blossomparent[b] = -1
+	wb.blossomparent[bb] = b
+
+	// Trace back from v to the base.
+	var path, endps []int
+	for bv != bb {
+		wb.blossomparent[bv] = b
+		path = append(path, bv)
+		endps = append(endps, wb.labelend[bv])
+		v = wb.endpoint[wb.labelend[bv]]
+		bv = wb.inblossom[v]
+	}
+	path = append(path, bb)
+	reverse(path)
+	reverse(endps)
+	endps = append(endps, 2*k)
+	// Trace back from w to the base.
+	for bw != bb {
+		wb.blossomparent[bw] = b
+		path = append(path, bw)
+		endps = append(endps, wb.labelend[bw]^1)
+		w = wb.endpoint[wb.labelend[bw]]
+		bw = wb.inblossom[w]
+	}
+	wb.blossomchilds[b] = path
+	wb.blossomendps[b] = endps
+
+	wb.label[b] = outer
+	wb.labelend[b] = wb.labelend[bb]
+	wb.dualvar[b] = 0
+	for _, v := range wb.leaves(b) {
+		if wb.label[wb.inblossom[v]] == inner {
+			// Former inner nodes are now outer
+			// and must be scanned.
+			wb.queue = append(wb.queue, v)
+		}
+		wb.inblossom[v] = b
+	}
+
+	// Compute the least-slack edges to
+	// neighboring outer blossoms.
+	bestedgeto := make([]int, 2*wb.n)
+	for i := range bestedgeto {
+		bestedgeto[i] = -1
+	}
+	for _, bv := range path {
+		var nblists [][]int
+		if wb.blossombestedges[bv] == nil {
+			for _, v := range wb.leaves(bv) {
+				nblist := make([]int, len(wb.neighbend[v]))
+				for m, p := range wb.neighbend[v] {
+					nblist[m] = p / 2
+				}
+				nblists = append(nblists, nblist)
+			}
+		} else {
+			nblists = [][]int{wb.blossombestedges[bv]}
+		}
+		for _, nblist := range nblists {
+			for _, k := range nblist {
+				j := wb.edges[k].j
+				if wb.inblossom[j] == b {
+					j = wb.edges[k].i
+				}
+				bj := wb.inblossom[j]
+				if bj != b && wb.label[bj] == outer && (bestedgeto[bj] == -1 || wb.slack(k) < wb.slack(bestedgeto[bj])) {
+					bestedgeto[bj] = k
+				}
+			}
+		}
+		wb.blossombestedges[bv] = nil
+		wb.bestedge[bv] = -1
+	}
+	best := []int{}
+	for _, k := range bestedgeto {
+		if k != -1 {
+			best = append(best, k)
+		}
+	}
+	wb.blossombestedges[b] = best
+	wb.bestedge[b] = -1
+	for _, k := range best {
+		if wb.bestedge[b] == -1 || wb.slack(k) < wb.slack(wb.bestedge[b]) {
+			wb.bestedge[b] = k
+		}
+	}
+}
+
+// expandBlossom expands the top-level blossom b into its sub-blossoms.
+// If endStage is true, sub-blossoms with zero dual are expanded
+// recursively.
+func (wb *weightedBlossom) expandBlossom(b int, endStage bool) {
+	for _, s := range wb.blossomchilds[b] {
+		wb.blossomparent[s] = -1
+		switch {
+		case s < wb.n:
+			wb.inblossom[s] = s
+		case endStage && wb.dualvar[s] == 0:
+			wb.expandBlossom(s, endStage)
+		default:
+			for _, v := range wb.leaves(s) {
+				wb.inblossom[v] = s
+			}
+		}
+	}
+
+	if !endStage && wb.label[b] == inner {
+		// Relabel the sub-blossoms on the even length path
+		// from the entry child to the base as alternating
+		// inner and outer, and the remaining sub-blossoms
+		// as free or reached.
+		childs := wb.blossomchilds[b]
+		endps := wb.blossomendps[b]
+		entrychild := wb.inblossom[wb.endpoint[wb.labelend[b]^1]]
+		j := index(childs, entrychild)
+		var jstep, endptrick int
+		if j&1 != 0 {
+			// Go forward and wrap around.
+			j -= len(childs)
+			jstep = 1
+		} else {
+			// Go backward.
+			jstep = -1
+			endptrick = 1
+		}
+		at := func(s []int, i int) int {
+			if i < 0 {
+				i += len(s)
+			}
+			return s[i]
+		}
+		p := wb.labelend[b]
+		for j != 0 {
+			wb.label[wb.endpoint[p^1]] = free
+			q := at(endps, j-endptrick) ^ endptrick ^ 1
+			wb.label[wb.endpoint[q]] = free
+			wb.assignLabel(wb.endpoint[p^1], inner, p)
+			wb.allowedge[at(endps, j-endptrick)/2] = true
+			j += jstep
+			p = at(endps, j-endptrick) ^ endptrick
+			wb.allowedge[p/2] = true
+			j += jstep
+		}
+		bv := at(childs, j)
+		wb.label[wb.endpoint[p^1]] = inner
+		wb.label[bv] = inner
+		wb.labelend[wb.endpoint[p^1]] = p
+		wb.labelend[bv] = p
+		wb.bestedge[bv] = -1
+		j += jstep
+		for at(childs, j) != entrychild {
+			bv = at(childs, j)
+			if wb.label[bv] == outer {
+				j += jstep
+				continue
+			}
+			v := -1
+			for _, v = range wb.leaves(bv) {
+				if wb.label[v] != free {
+					break
+				}
+			}
+			if wb.label[v] != free {
+				wb.label[v] = free
+				wb.label[wb.endpoint[wb.mate[wb.blossombase[bv]]]] = free
+				wb.assignLabel(v, inner, wb.labelend[v])
+			}
+			j += jstep
+		}
+	}
+
+	wb.label[b] = -1
+	wb.labelend[b] = -1
+	wb.blossomchilds[b] = nil
+	wb.blossomendps[b] = nil
+	wb.blossombase[b] = -1
+	wb.blossombestedges[b] = nil
+	wb.bestedge[b] = -1
+	wb.unusedblossoms = append(wb.unusedblossoms, b)
+}
+
+// augmentBlossom swaps matched and unmatched edges over an alternating
+// path through blossom b between node v and the base node.
+func (wb *weightedBlossom) augmentBlossom(b, v int) {
+	t := v
+	for wb.blossomparent[t] != b {
+		t = wb.blossomparent[t]
+	}
+	if t >= wb.n {
+		wb.augmentBlossom(t, v)
+	}
+
+	childs := wb.blossomchilds[b]
+	endps := wb.blossomendps[b]
+	i := index(childs, t)
+	j := i
+	var jstep, endptrick int
+	if i&1 != 0 {
+		j -= len(childs)
+		jstep = 1
+	} else {
+		jstep = -1
+		endptrick = 1
+	}
+	at := func(s []int, i int) int {
+		if i < 0 {
+			i += len(s)
+		}
+		return s[i]
+	}
+	for j != 0 {
+		j += jstep
+		t = at(childs, j)
+		p := at(endps, j-endptrick) ^ endptrick
+		if t >= wb.n {
+			wb.augmentBlossom(t, wb.endpoint[p])
+		}
+		j += jstep
+		t = at(childs, j)
+		if t >= wb.n {
+			wb.augmentBlossom(t, wb.endpoint[p^1])
+		}
+		wb.mate[wb.endpoint[p]] = p ^ 1
+		wb.mate[wb.endpoint[p^1]] = p
+	}
+
+	// Rotate the children so the new base is first.
+	wb.blossomchilds[b] = append(append([]int(nil), childs[i:]...), childs[:i]...)
+	wb.blossomendps[b] = append(append([]int(nil), endps[i:]...), endps[:i]...)
+	wb.blossombase[b] = wb.blossombase[wb.blossomchilds[b][0]]
+}
+
+// augmentMatching swaps matched and unmatched edges over the alternating
+// path through edge k between two free roots.
+func (wb *weightedBlossom) augmentMatching(k int) {
+	for _, sp := range [2][2]int{{wb.edges[k].i, 2*k + 1}, {wb.edges[k].j, 2 * k}} {
+		s, p := sp[0], sp[1]
+		for {
+			bs := wb.inblossom[s]
+			if bs >= wb.n {
+				wb.augmentBlossom(bs, s)
+			}
+			wb.mate[s] = p
+			if wb.labelend[bs] == -1 {
+				// Reached a free root.
+				break
+			}
+			t := wb.endpoint[wb.labelend[bs]]
+			bt := wb.inblossom[t]
+			s = wb.endpoint[wb.labelend[bt]]
+			j := wb.endpoint[wb.labelend[bt]^1]
+			if bt >= wb.n {
+				wb.augmentBlossom(bt, j)
+			}
+			wb.mate[j] = wb.labelend[bt]
+			p = wb.labelend[bt] ^ 1
+		}
+	}
+}
+
+// solve finds a maximum weight matching, holding it in the mate field.
+func (wb *weightedBlossom) solve(maxCardinality bool) {
+	n := wb.n
+	for stage := 0; stage < n; stage++ {
+		for i := range wb.label {
+			wb.label[i] = free
+			wb.bestedge[i] = -1
+			if i >= n {
+				wb.blossombestedges[i] = nil
+			}
+		}
+		for i := range wb.allowedge {
+			wb.allowedge[i] = false
+		}
+		wb.queue = wb.queue[:0]
+		for v := 0; v < n; v++ {
+			if wb.mate[v] == -1 && wb.label[wb.inblossom[v]] == free {
+				wb.assignLabel(v, outer, -1)
+			}
+		}
+
+		augmented := false
+		for {
+			// Grow the alternating forest along tight edges.
+			for len(wb.queue) != 0 && !augmented {
+				v := wb.queue[len(wb.queue)-1]
+				wb.queue = wb.queue[:len(wb.queue)-1]
+				for _, p := range wb.neighbend[v] {
+					k := p / 2
+					w := wb.endpoint[p]
+					if wb.inblossom[v] == wb.inblossom[w] {
+						continue
+					}
+					var kslack float64
+					if !wb.allowedge[k] {
+						kslack = wb.slack(k)
+						if kslack <= 0 {
+							wb.allowedge[k] = true
+						}
+					}
+					switch {
+					case wb.allowedge[k]:
+						switch {
+						case wb.label[wb.inblossom[w]] == free:
+							wb.assignLabel(w, inner, p^1)
+						case wb.label[wb.inblossom[w]] == outer:
+							base := wb.scanBlossom(v, w)
+							if base >= 0 {
+								wb.addBlossom(base, k)
+							} else {
+								wb.augmentMatching(k)
+								augmented = true
+							}
+						case wb.label[w] == free:
+							// w is in an inner blossom
+							// but has not been reached.
+							wb.label[w] = inner
+							wb.labelend[w] = p ^ 1
+						}
+					case wb.label[wb.inblossom[w]] == outer:
+						b := wb.inblossom[v]
+						if wb.bestedge[b] == -1 || kslack < wb.slack(wb.bestedge[b]) {
+							wb.bestedge[b] = k
+						}
+					case wb.label[w] == free:
+						if wb.bestedge[w] == -1 || kslack < wb.slack(wb.bestedge[w]) {
+							wb.bestedge[w] = k
+						}
+					}
+					if augmented {
+						break
+					}
+				}
+			}
+			if augmented {
+				break
+			}
+
+			// Find the dual variable update.
+			deltatype := -1
+			var delta float64
+			deltaedge, deltablossom := -1, -1
+			if !maxCardinality {
+				// The minimum outer node dual.
+				deltatype = 1
+				delta = wb.dualvar[0]
+				for _, d := range wb.dualvar[1:n] {
+					delta = math.Min(delta, d)
+				}
+			}
+			for v := 0; v < n; v++ {
+				// The minimum slack of edges between
+				// outer and free nodes.
+				if wb.label[wb.inblossom[v]] == free && wb.bestedge[v] != -1 {
+					d := wb.slack(wb.bestedge[v])
+					if deltatype == -1 || d < delta {
+						delta = d
+						deltatype = 2
+						deltaedge = wb.bestedge[v]
+					}
+				}
+			}
+			for b := 0; b < 2*n; b++ {
+				// Half the minimum slack of edges
+				// between outer blossoms.
+				if wb.blossomparent[b] == -1 && wb.label[b] == outer && wb.bestedge[b] != -1 {
+					d := wb.slack(wb.bestedge[b]) / 2
+					if deltatype == -1 || d < delta {
+						delta = d
+						deltatype = 3
+						deltaedge = wb.bestedge[b]
+					}
+				}
+			}
+			for b := n; b < 2*n; b++ {
+				// The minimum dual of inner blossoms.
+				if wb.blossombase[b] >= 0 && wb.blossomparent[b] == -1 && wb.label[b] == inner &&
+					(deltatype == -1 || wb.dualvar[b] < delta) {
+					delta = wb.dualvar[b]
+					deltatype = 4
+					deltablossom = b
+				}
+			}
+			if deltatype == -1 {
+				// No further improvement is possible
+				// with maximum cardinality.
+				deltatype = 1
+				delta = wb.dualvar[0]
+				for _, d := range wb.dualvar[1:n] {
+					delta = math.Min(delta, d)
+				}
+				delta = math.Max(0, delta)
+			}
+
+			// Update the dual variables.
+			for v := 0; v < n; v++ {
+				switch wb.label[wb.inblossom[v]] {
+				case outer:
+					wb.dualvar[v] -= delta
+				case inner:
+					wb.dualvar[v] += delta
+				}
+			}
+			for b := n; b < 2*n; b++ {
+				if wb.blossombase[b] >= 0 && wb.blossomparent[b] == -1 {
+					switch wb.label[b] {
+					case outer:
+						wb.dualvar[b] += delta
+					case inner:
+						wb.dualvar[b] -= delta
+					}
+				}
+			}
+
+			switch deltatype {
+			case 1:
+				// The optimum has been reached.
+			case 2:
+				wb.allowedge[deltaedge] = true
+				i := wb.edges[deltaedge].i
+				if wb.label[wb.inblossom[i]] == free {
+					i = wb.edges[deltaedge].j
+				}
+				wb.queue = append(wb.queue, i)
+			case 3:
+				wb.allowedge[deltaedge] = true
+				wb.queue = append(wb.queue, wb.edges[deltaedge].i)
+			case 4:
+				wb.expandBlossom(deltablossom, false)
+			}
+			if deltatype == 1 {
+				break
+			}
+		}
+		if !augmented {
+			break
+		}
+
+		// Expand outer blossoms with zero dual
+		// at the end of the stage.
+		for b := n; b < 2*n; b++ {
+			if wb.blossomparent[b] == -1 && wb.blossombase[b] >= 0 && wb.label[b] == outer && wb.dualvar[b] == 0 {
+				wb.expandBlossom(b, true)
+			}
+		}
+	}
+}
+
+// index returns the index of v in s or -1 if v is not present.
+func index(s []int, v int) int {
+	for i, e := range s {
+		if e == v {
+			return i
+		}
+	}
+	return -1
+}
+
+// reverse reverses the elements of s in place.
+func reverse(s []int) {
+	for i, j := 0, len(s)-1; i < j; i, j = i+1, j-1 {
+		s[i], s[j] = s[j], s[i]
+	}
+}
@@ -0,0 +1,162 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package matching
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/graph/simple"
+)
+
+var maximumWeightMatchingTests = []struct {
+	name           string
+	edges          []simple.WeightedEdge
+	maxCardinality bool
+
+	wantWeight float64
+	wantEdges  [][2]int64
+}{
+	{
+		name: "empty",
+	},
+	{
+		name:       "single edge",
+		edges:      []simple.WeightedEdge{{F: simple.Node(0), T: simple.Node(1), W: 2}},
+		wantWeight: 2,
+		wantEdges:  [][2]int64{{0, 1}},
+	},
+	{
+		name: "path prefers heavy middle",
+		edges: []simple.WeightedEdge{
+			{F: simple.Node(0), T: simple.Node(1), W: 2},
+			{F: simple.Node(1), T: simple.Node(2), W: 5},
+			{F: simple.Node(2), T: simple.Node(3), W: 2},
+		},
+		wantWeight: 5,
+		wantEdges:  [][2]int64{{1, 2}},
+	},
+	{
+		name: "path with max cardinality",
+		edges: []simple.WeightedEdge{
+			{F: simple.Node(0), T: simple.Node(1), W: 2},
+			{F: simple.Node(1), T: simple.Node(2), W: 5},
+			{F: simple.Node(2), T: simple.Node(3), W: 2},
+		},
+		maxCardinality: true,
+		wantWeight:     4,
+		wantEdges:      [][2]int64{{0, 1}, {2, 3}},
+	},
+	{
+		name: "negative weight",
+		edges: []simple.WeightedEdge{
+			{F: simple.Node(0), T: simple.Node(1), W: 2},
+			{F: simple.Node(2), T: simple.Node(3), W: -1},
+		},
+		wantWeight: 2,
+		wantEdges:  [][2]int64{{0, 1}},
+	},
+	{
+		// Test case from the van Rantwijk test suite
+		// creating and expanding an S-blossom.
+		name: "blossom",
+		edges: []simple.WeightedEdge{
+			{F: simple.Node(1), T: simple.Node(2), W: 8},
+			{F: simple.Node(1), T: simple.Node(3), W: 9},
+			{F: simple.Node(2), T: simple.Node(3), W: 10},
+			{F: simple.Node(3), T: simple.Node(4), W: 7},
+			{F: simple.Node(1), T: simple.Node(6), W: 5},
+			{F: simple.Node(4), T: simple.Node(5), W: 6},
+		},
+		wantWeight: 21,
+		wantEdges:  [][2]int64{{1, 6}, {2, 3}, {4, 5}},
+	},
+	{
+		// Test case from the van Rantwijk test suite
+		// creating a nested S-blossom and relabeling
+		// it as a T-blossom.
+		name: "nested blossom",
+		edges: []simple.WeightedEdge{
+			{F: simple.Node(1), T: simple.Node(2), W: 19},
+			{F: simple.Node(1), T: simple.Node(3), W: 20},
+			{F: simple.Node(1), T: simple.Node(8), W: 8},
+			{F: simple.Node(2), T: simple.Node(3), W: 25},
+			{F: simple.Node(2), T: simple.Node(4), W: 18},
+			{F: simple.Node(3), T: simple.Node(5), W: 18},
+			{F: simple.Node(4), T: simple.Node(5), W: 13},
+			{F: simple.Node(4), T: simple.Node(7), W: 7},
+			{F: simple.Node(5), T: simple.Node(6), W: 7},
+		},
+		wantWeight: 47,
+		wantEdges:  [][2]int64{{1, 8}, {2, 3}, {4, 7}, {5, 6}},
+	},
+}
+
+func TestMaximumWeightMatching(t *testing.T) {
+	for _, test := range maximumWeightMatchingTests {
+		g := simple.NewWeightedUndirectedGraph(0, 0)
+		for _, e := range test.edges {
+			g.SetWeightedEdge(e)
+		}
+		m, w := MaximumWeightMatching(g, test.maxCardinality)
+		if w != test.wantWeight {
+			t.Errorf("unexpected matching weight for %q: got:%v want:%v", test.name, w, test.wantWeight)
+		}
+		var got [][2]int64
+		for _, e := range m.Edges() {
+			lo, hi := endIDs(e)
+			got = append(got, [2]int64{lo, hi})
+		}
+		if fmt.Sprint(got) != fmt.Sprint(test.wantEdges) {
+			t.Errorf("unexpected matching for %q: got:%v want:%v", test.name, got, test.wantEdges)
+		}
+		checkMatching(t, test.name, g, m)
+	}
+}
+
+func TestMaximumWeightMatchingRandom(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for trial := 0; trial < 500; trial++ {
+		n := 2 + rnd.Intn(9)
+		g := simple.NewWeightedUndirectedGraph(0, 0)
+		for i := 0; i < n; i++ {
+			g.AddNode(simple.Node(i))
+		}
+		p := rnd.Float64()
+		for i := 0; i < n; i++ {
+			for j := i + 1; j < n; j++ {
+				if rnd.Float64() < p {
+					w := float64(rnd.Intn(20) - 3)
+					if trial%2 == 1 {
+						w = 10 * rnd.Float64()
+					}
+					g.SetWeightedEdge(simple.WeightedEdge{F: simple.Node(i), T: simple.Node(j), W: w})
+				}
+			}
+		}
+		for _, maxCardinality := range []bool{false, true} {
+			name := fmt.Sprintf("trial %d with maxCardinality=%t", trial, maxCardinality)
+			m, w := MaximumWeightMatching(g, maxCardinality)
+			checkMatching(t, name, g, m)
+			var sum float64
+			for _, e := range m.Edges() {
+				sum += e.(simple.WeightedEdge).W
+			}
+			if math.Abs(sum-w) > 1e-9 {
+				t.Errorf("returned weight does not match edges for %s: got:%v want:%v", name, w, sum)
+			}
+			size, want := bruteForceMatching(g, !maxCardinality)
+			if math.Abs(w-want) > 1e-9 {
+				t.Errorf("unexpected matching weight for %s: got:%v want:%v", name, w, want)
+			}
+			if maxCardinality && m.Len() != size {
+				t.Errorf("unexpected matching size for %s: got:%d want:%d", name, m.Len(), size)
+			}
+		}
+	}
+}
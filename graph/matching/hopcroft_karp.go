@@ -0,0 +1,199 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package matching
+
+import (
+	"sort"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/internal/ordered"
+)
+
+// BipartiteMatching is a maximum matching of a bipartite graph.
+type BipartiteMatching struct {
+	Matching
+
+	cover []graph.Node
+}
+
+// VertexCover returns a minimum vertex cover of the graph, sorted by ID.
+// By König's theorem, the size of a minimum vertex cover of a bipartite
+// graph is equal to the size of a maximum matching.
+func (m BipartiteMatching) VertexCover() []graph.Node {
+	return m.cover
+}
+
+// HopcroftKarp returns a maximum cardinality matching of the undirected
+// graph g using the Hopcroft-Karp algorithm doi:10.1137/0202019. If g
+// is not bipartite, HopcroftKarp returns a zero BipartiteMatching and
+// false.
+//
+// The time complexity of HopcroftKarp is O(|E|.sqrt(|V|)).
+func HopcroftKarp(g graph.Undirected) (m BipartiteMatching, ok bool) {
+	u, v, ok := Bipartite(g)
+	if !ok {
+		return BipartiteMatching{}, false
+	}
+
+	hk := newHopcroftKarp(g, u, v)
+	for hk.layer() {
+		for i := range hk.u {
+			if hk.mateU[i] == -1 {
+				hk.augment(i)
+			}
+		}
+	}
+
+	mate := make(map[int64]graph.Node)
+	for i, j := range hk.mateU {
+		if j == -1 {
+			continue
+		}
+		mate[u[i].ID()] = v[j]
+		mate[v[j].ID()] = u[i]
+	}
+	return BipartiteMatching{Matching: newMatching(g, mate), cover: hk.cover()}, true
+}
+
+// hopcroftKarp holds the state of the Hopcroft-Karp algorithm.
+type hopcroftKarp struct {
+	u, v []graph.Node
+
+	// adj holds the indices into v of the
+	// neighbors of each node in u.
+	adj [][]int
+
+	// mateU and mateV hold the index of the
+	// mate of each node, or -1 if the node
+	// is not matched.
+	mateU, mateV []int
+
+	// dist holds the layer of each node in
+	// u in the current breadth-first search.
+	dist []int
+}
+
+// newHopcroftKarp returns a hopcroftKarp for the bipartite graph g with
+// color classes u and v, and an empty matching.
+func newHopcroftKarp(g graph.Undirected, u, v []graph.Node) *hopcroftKarp {
+	hk := &hopcroftKarp{
+		u:     u,
+		v:     v,
+		adj:   make([][]int, len(u)),
+		mateU: make([]int, len(u)),
+		mateV: make([]int, len(v)),
+		dist:  make([]int, len(u)),
+	}
+	indexOf := make(map[int64]int, len(v))
+	for j, n := range v {
+		indexOf[n.ID()] = j
+		hk.mateV[j] = -1
+	}
+	for i, n := range u {
+		hk.mateU[i] = -1
+		to := g.From(n)
+		sort.Sort(ordered.ByID(to))
+		for _, w := range to {
+			hk.adj[i] = append(hk.adj[i], indexOf[w.ID()])
+		}
+	}
+	return hk
+}
+
+// layer performs a breadth-first search from the unmatched nodes in u
+// along alternating paths, labeling nodes in u with their layer, and
+// returns whether an augmenting path exists.
+func (hk *hopcroftKarp) layer() bool {
+	const inf = int(^uint(0) >> 1)
+	var queue []int
+	for i, j := range hk.mateU {
+		if j == -1 {
+			hk.dist[i] = 0
+			queue = append(queue, i)
+		} else {
+			hk.dist[i] = inf
+		}
+	}
+	found := false
+	for len(queue) != 0 {
+		i := queue[0]
+		queue = queue[1:]
+		for _, j := range hk.adj[i] {
+			k := hk.mateV[j]
+			if k == -1 {
+				found = true
+				continue
+			}
+			if hk.dist[k] == inf {
+				hk.dist[k] = hk.dist[i] + 1
+				queue = append(queue, k)
+			}
+		}
+	}
+	return found
+}
+
+// augment searches for an augmenting path from the node at index i in u
+// following the layers found by layer, and flips the matching along the
+// path if one is found, returning whether the matching was augmented.
+func (hk *hopcroftKarp) augment(i int) bool {
+	const inf = int(^uint(0) >> 1)
+	for _, j := range hk.adj[i] {
+		k := hk.mateV[j]
+		if k == -1 || (hk.dist[k] == hk.dist[i]+1 && hk.augment(k)) {
+			hk.mateU[i] = j
+			hk.mateV[j] = i
+			return true
+		}
+	}
+	// Remove i from the layering so it
+	// is not searched again in this phase.
+	hk.dist[i] = inf
+	return false
+}
+
+// cover returns a minimum vertex cover constructed from the maximum
+// matching using the proof of König's theorem. Nodes reachable from the
+// unmatched nodes in u by alternating paths are marked and the cover is
+// the unmarked nodes in u and the marked nodes in v.
+func (hk *hopcroftKarp) cover() []graph.Node {
+	markedU := make([]bool, len(hk.u))
+	markedV := make([]bool, len(hk.v))
+	var queue []int
+	for i, j := range hk.mateU {
+		if j == -1 {
+			markedU[i] = true
+			queue = append(queue, i)
+		}
+	}
+	for len(queue) != 0 {
+		i := queue[0]
+		queue = queue[1:]
+		for _, j := range hk.adj[i] {
+			if markedV[j] {
+				continue
+			}
+			markedV[j] = true
+			if k := hk.mateV[j]; k != -1 && !markedU[k] {
+				markedU[k] = true
+				queue = append(queue, k)
+			}
+		}
+	}
+
+	var cover []graph.Node
+	for i, n := range hk.u {
+		if !markedU[i] {
+			cover = append(cover, n)
+		}
+	}
+	for j, n := range hk.v {
+		if markedV[j] {
+			cover = append(cover, n)
+		}
+	}
+	sort.Sort(ordered.ByID(cover))
+	return cover
+}
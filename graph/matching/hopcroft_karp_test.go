@@ -0,0 +1,175 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package matching
+
+import (
+	"fmt"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/flow"
+	"gonum.org/v1/gonum/graph/simple"
+)
+
+var hopcroftKarpTests = []struct {
+	name  string
+	nodes []int64
+	edges [][2]int64
+
+	wantOK    bool
+	wantLen   int
+	wantCover []int64
+}{
+	{
+		name:   "empty",
+		wantOK: true,
+	},
+	{
+		name:   "odd cycle",
+		edges:  [][2]int64{{0, 1}, {1, 2}, {2, 0}},
+		wantOK: false,
+	},
+	{
+		name:      "path",
+		edges:     [][2]int64{{0, 1}, {1, 2}, {2, 3}},
+		wantOK:    true,
+		wantLen:   2,
+		wantCover: []int64{0, 2},
+	},
+	{
+		name:      "star",
+		nodes:     []int64{10},
+		edges:     [][2]int64{{0, 1}, {0, 2}, {0, 3}},
+		wantOK:    true,
+		wantLen:   1,
+		wantCover: []int64{0},
+	},
+	{
+		// A greedy matching of 0-4 and 1-5
+		// must be augmented along 2-4-0-5-1-6.
+		name: "augmenting path",
+		edges: [][2]int64{
+			{0, 4}, {0, 5},
+			{1, 5}, {1, 6},
+			{2, 4},
+			{3, 6},
+		},
+		wantOK:    true,
+		wantLen:   3,
+		wantCover: []int64{4, 5, 6},
+	},
+}
+
+func TestHopcroftKarp(t *testing.T) {
+	for _, test := range hopcroftKarpTests {
+		g := undirectedFrom(test.nodes, test.edges)
+		m, ok := HopcroftKarp(g)
+		if ok != test.wantOK {
+			t.Errorf("unexpected bipartite result for %q: got:%t want:%t", test.name, ok, test.wantOK)
+			continue
+		}
+		if m.Len() != test.wantLen {
+			t.Errorf("unexpected matching size for %q: got:%d want:%d", test.name, m.Len(), test.wantLen)
+		}
+		if got := fmt.Sprint(ids(m.VertexCover())); got != fmt.Sprint(test.wantCover) {
+			t.Errorf("unexpected vertex cover for %q: got:%v want:%v", test.name, got, test.wantCover)
+		}
+		if ok {
+			checkMatching(t, test.name, g, m.Matching)
+			checkCover(t, test.name, g, m.VertexCover())
+		}
+	}
+}
+
+func TestHopcroftKarpRandom(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for trial := 0; trial < 50; trial++ {
+		const nu, nv = 30, 40
+		g := simple.NewUndirectedGraph()
+		for i := 0; i < nu+nv; i++ {
+			g.AddNode(simple.Node(i))
+		}
+		p := 0.02 + 0.1*rnd.Float64()
+		for i := 0; i < nu; i++ {
+			for j := nu; j < nu+nv; j++ {
+				if rnd.Float64() < p {
+					g.SetEdge(simple.Edge{F: simple.Node(i), T: simple.Node(j)})
+				}
+			}
+		}
+
+		name := fmt.Sprintf("trial %d", trial)
+		m, ok := HopcroftKarp(g)
+		if !ok {
+			t.Errorf("unexpected non-bipartite result for %s", name)
+			continue
+		}
+		checkMatching(t, name, g, m.Matching)
+		cover := m.VertexCover()
+		checkCover(t, name, g, cover)
+		if len(cover) != m.Len() {
+			t.Errorf("vertex cover size does not match matching size for %s: %d != %d", name, len(cover), m.Len())
+		}
+
+		// Check the matching size against the maximum flow
+		// through the network with unit capacity edges from
+		// a source to u, from u to v and from v to a sink.
+		net := simple.NewWeightedDirectedGraph(0, 0)
+		s, tn := simple.Node(-1), simple.Node(-2)
+		for i := 0; i < nu; i++ {
+			net.SetWeightedEdge(simple.WeightedEdge{F: s, T: simple.Node(i), W: 1})
+			for _, v := range g.From(simple.Node(i)) {
+				net.SetWeightedEdge(simple.WeightedEdge{F: simple.Node(i), T: v, W: 1})
+			}
+		}
+		for j := nu; j < nu+nv; j++ {
+			net.SetWeightedEdge(simple.WeightedEdge{F: simple.Node(j), T: tn, W: 1})
+		}
+		if want := flow.EdmondsKarp(net, s, tn).Value; float64(m.Len()) != want {
+			t.Errorf("unexpected matching size for %s: got:%d want:%v", name, m.Len(), want)
+		}
+	}
+}
+
+// checkMatching checks that m is a valid matching of g.
+func checkMatching(t *testing.T, name string, g graph.Undirected, m Matching) {
+	seen := make(map[int64]bool)
+	for _, e := range m.Edges() {
+		u, v := e.From(), e.To()
+		if !g.HasEdgeBetween(u, v) {
+			t.Errorf("matched edge %d--%d not in graph for %s", u.ID(), v.ID(), name)
+		}
+		if seen[u.ID()] || seen[v.ID()] {
+			t.Errorf("node matched twice in edge %d--%d for %s", u.ID(), v.ID(), name)
+		}
+		seen[u.ID()] = true
+		seen[v.ID()] = true
+		if m.Mate(u) == nil || m.Mate(u).ID() != v.ID() || m.Mate(v) == nil || m.Mate(v).ID() != u.ID() {
+			t.Errorf("inconsistent mates for edge %d--%d for %s", u.ID(), v.ID(), name)
+		}
+	}
+	for _, n := range g.Nodes() {
+		if !seen[n.ID()] && m.Mate(n) != nil {
+			t.Errorf("unexpected mate for unmatched node %d for %s", n.ID(), name)
+		}
+	}
+}
+
+// checkCover checks that cover is a vertex cover of g.
+func checkCover(t *testing.T, name string, g graph.Undirected, cover []graph.Node) {
+	in := make(map[int64]bool)
+	for _, n := range cover {
+		in[n.ID()] = true
+	}
+	for _, u := range g.Nodes() {
+		for _, v := range g.From(u) {
+			if !in[u.ID()] && !in[v.ID()] {
+				t.Errorf("edge %d--%d not covered for %s", u.ID(), v.ID(), name)
+			}
+		}
+	}
+}
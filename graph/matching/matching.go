@@ -0,0 +1,71 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package matching
+
+import (
+	"sort"
+
+	"gonum.org/v1/gonum/graph"
+)
+
+// Matching is a set of edges of an undirected graph,
+// no two of which share an end point.
+type Matching struct {
+	mate  map[int64]graph.Node
+	edges []graph.Edge
+}
+
+// newMatching returns the Matching of g described by mate, which
+// must hold the mate of each matched node in both directions.
+func newMatching(g graph.Graph, mate map[int64]graph.Node) Matching {
+	m := Matching{mate: mate}
+	for uid, v := range mate {
+		if uid < v.ID() {
+			m.edges = append(m.edges, g.Edge(mate[v.ID()], v))
+		}
+	}
+	sort.Sort(byNodeIDs(m.edges))
+	return m
+}
+
+// Mate returns the node matched to n, or nil if n is not matched.
+func (m Matching) Mate(n graph.Node) graph.Node {
+	return m.mate[n.ID()]
+}
+
+// Len returns the number of edges in the matching.
+func (m Matching) Len() int {
+	return len(m.edges)
+}
+
+// Edges returns the edges of the matching sorted by the lower
+// and then higher ID of their end points.
+func (m Matching) Edges() []graph.Edge {
+	return m.edges
+}
+
+// byNodeIDs sorts undirected edges by the lower and then higher
+// ID of their end points.
+type byNodeIDs []graph.Edge
+
+func (e byNodeIDs) Len() int { return len(e) }
+func (e byNodeIDs) Less(i, j int) bool {
+	ilo, ihi := endIDs(e[i])
+	jlo, jhi := endIDs(e[j])
+	if ilo != jlo {
+		return ilo < jlo
+	}
+	return ihi < jhi
+}
+func (e byNodeIDs) Swap(i, j int) { e[i], e[j] = e[j], e[i] }
+
+// endIDs returns the IDs of the end points of e in ascending order.
+func endIDs(e graph.Edge) (lo, hi int64) {
+	lo, hi = e.From().ID(), e.To().ID()
+	if hi < lo {
+		lo, hi = hi, lo
+	}
+	return lo, hi
+}
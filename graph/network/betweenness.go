@@ -6,6 +6,8 @@ package network
 
 import (
 	"math"
+	"runtime"
+	"sync"
 
 	"gonum.org/v1/gonum/graph"
 	"gonum.org/v1/gonum/graph/internal/linear"
@@ -24,16 +26,39 @@ func Betweenness(g graph.Graph) map[int64]float64 {
 	//
 	// http://www.inf.uni-konstanz.de/algo/publications/b-fabc-01.pdf
 
-	// TODO(kortschak): Consider using the parallel algorithm when
-	// GOMAXPROCS != 1.
-	//
-	// http://htor.inf.ethz.ch/publications/img/edmonds-hoefler-lumsdaine-bc.pdf
-
 	// Also note special case for sparse networks:
 	// http://wwwold.iit.cnr.it/staff/marco.pellegrini/papiri/asonam-final.pdf
 
 	cb := make(map[int64]float64)
-	brandes(g, func(s graph.Node, stack linear.NodeStack, p map[int64][]graph.Node, delta, sigma map[int64]float64) {
+	brandes(g, g.Nodes(), nodeAccumulator(cb))
+	return cb
+}
+
+// ParallelBetweenness returns the non-zero betweenness centrality for nodes in
+// the unweighted graph g as described for Betweenness. The single source
+// shortest path computations for each node of g are partitioned between
+// workers goroutines. If workers is less than one, runtime.GOMAXPROCS(0)
+// workers are used. The methods of g must be safe for concurrent use.
+func ParallelBetweenness(g graph.Graph, workers int) map[int64]float64 {
+	var parts []map[int64]float64
+	parallelBrandes(g, workers, func() accumulator {
+		cb := make(map[int64]float64)
+		parts = append(parts, cb)
+		return nodeAccumulator(cb)
+	})
+	cb := make(map[int64]float64)
+	for _, part := range parts {
+		for id, c := range part {
+			cb[id] += c
+		}
+	}
+	return cb
+}
+
+// nodeAccumulator returns a Brandes accumulation function
+// that adds node betweenness contributions to cb.
+func nodeAccumulator(cb map[int64]float64) accumulator {
+	return func(s graph.Node, stack linear.NodeStack, p map[int64][]graph.Node, delta, sigma map[int64]float64) {
 		for stack.Len() != 0 {
 			w := stack.Pop()
 			for _, v := range p[w.ID()] {
@@ -45,8 +70,7 @@ func Betweenness(g graph.Graph) map[int64]float64 {
 				}
 			}
 		}
-	})
-	return cb
+	}
 }
 
 // EdgeBetweenness returns the non-zero betweenness centrality for edges in the
@@ -67,7 +91,37 @@ func EdgeBetweenness(g graph.Graph) map[[2]int64]float64 {
 
 	_, isUndirected := g.(graph.Undirected)
 	cb := make(map[[2]int64]float64)
-	brandes(g, func(s graph.Node, stack linear.NodeStack, p map[int64][]graph.Node, delta, sigma map[int64]float64) {
+	brandes(g, g.Nodes(), edgeAccumulator(cb, isUndirected))
+	return cb
+}
+
+// ParallelEdgeBetweenness returns the non-zero betweenness centrality for edges
+// in the unweighted graph g as described for EdgeBetweenness. The single source
+// shortest path computations for each node of g are partitioned between
+// workers goroutines. If workers is less than one, runtime.GOMAXPROCS(0)
+// workers are used. The methods of g must be safe for concurrent use.
+func ParallelEdgeBetweenness(g graph.Graph, workers int) map[[2]int64]float64 {
+	_, isUndirected := g.(graph.Undirected)
+	var parts []map[[2]int64]float64
+	parallelBrandes(g, workers, func() accumulator {
+		cb := make(map[[2]int64]float64)
+		parts = append(parts, cb)
+		return edgeAccumulator(cb, isUndirected)
+	})
+	cb := make(map[[2]int64]float64)
+	for _, part := range parts {
+		for e, c := range part {
+			cb[e] += c
+		}
+	}
+	return cb
+}
+
+// edgeAccumulator returns a Brandes accumulation function that adds edge
+// betweenness contributions to cb. If isUndirected is true, edges are keyed
+// with the lower node ID first.
+func edgeAccumulator(cb map[[2]int64]float64, isUndirected bool) accumulator {
+	return func(s graph.Node, stack linear.NodeStack, p map[int64][]graph.Node, delta, sigma map[int64]float64) {
 		for stack.Len() != 0 {
 			w := stack.Pop()
 			for _, v := range p[w.ID()] {
@@ -81,14 +135,21 @@ func EdgeBetweenness(g graph.Graph) map[[2]int64]float64 {
 				delta[v.ID()] += c
 			}
 		}
-	})
-	return cb
+	}
 }
 
+// accumulator is a Brandes accumulation function. It is called with each
+// source node s, a stack holding the nodes reached from s that pops them in
+// order of non-increasing distance, the shortest path predecessors of each
+// node, zeroed delta values and the number of shortest paths from s to each
+// node.
+type accumulator func(s graph.Node, stack linear.NodeStack, p map[int64][]graph.Node, delta, sigma map[int64]float64)
+
 // brandes is the common code for Betweenness and EdgeBetweenness. It corresponds
 // to algorithm 1 in http://algo.uni-konstanz.de/publications/b-vspbc-08.pdf with
-// the accumulation loop provided by the accumulate closure.
-func brandes(g graph.Graph, accumulate func(s graph.Node, stack linear.NodeStack, p map[int64][]graph.Node, delta, sigma map[int64]float64)) {
+// the accumulation loop provided by the accumulate closure, and the outer loop
+// over the nodes of g restricted to the given source nodes.
+func brandes(g graph.Graph, sources []graph.Node, accumulate accumulator) {
 	var (
 		nodes = g.Nodes()
 		stack linear.NodeStack
@@ -98,7 +159,7 @@ func brandes(g graph.Graph, accumulate func(s graph.Node, stack linear.NodeStack
 		delta = make(map[int64]float64, len(nodes))
 		queue linear.NodeQueue
 	)
-	for _, s := range nodes {
+	for _, s := range sources {
 		stack = stack[:0]
 
 		for _, w := range nodes {
@@ -139,6 +200,36 @@ func brandes(g graph.Graph, accumulate func(s graph.Node, stack linear.NodeStack
 	}
 }
 
+// parallelBrandes runs brandes concurrently with the nodes of g partitioned
+// as sources between at most workers goroutines. The accumulation function
+// for each partition is obtained from newAccumulator, which is called before
+// any partition is started. If workers is less than one, runtime.GOMAXPROCS(0)
+// workers are used.
+func parallelBrandes(g graph.Graph, workers int, newAccumulator func() accumulator) {
+	if workers < 1 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	nodes := g.Nodes()
+	if workers > len(nodes) {
+		workers = len(nodes)
+	}
+	accumulate := make([]accumulator, workers)
+	for i := range accumulate {
+		accumulate[i] = newAccumulator()
+	}
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for i, acc := range accumulate {
+		lo := i * len(nodes) / workers
+		hi := (i + 1) * len(nodes) / workers
+		go func(sources []graph.Node, acc accumulator) {
+			defer wg.Done()
+			brandes(g, sources, acc)
+		}(nodes[lo:hi], acc)
+	}
+	wg.Wait()
+}
+
 // BetweennessWeighted returns the non-zero betweenness centrality for nodes in the weighted
 // graph g used to construct the given shortest paths.
 //
@@ -10,7 +10,11 @@ import (
 	"sort"
 	"testing"
 
+	"golang.org/x/exp/rand"
+
 	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/graphs/gen"
 	"gonum.org/v1/gonum/graph/path"
 	"gonum.org/v1/gonum/graph/simple"
 )
@@ -235,6 +239,59 @@ func TestEdgeBetweenness(t *testing.T) {
 	}
 }
 
+func TestParallelBetweenness(t *testing.T) {
+	var graphs []graph.Graph
+	for _, test := range betweennessTests {
+		g := simple.NewUndirectedGraph()
+		for u, e := range test.g {
+			// Add nodes that are not defined by an edge.
+			if !g.Has(simple.Node(u)) {
+				g.AddNode(simple.Node(u))
+			}
+			for v := range e {
+				g.SetEdge(simple.Edge{F: simple.Node(u), T: simple.Node(v)})
+			}
+		}
+		graphs = append(graphs, g)
+	}
+	rnd := rand.New(rand.NewSource(1))
+	for _, p := range []float64{0.02, 0.1} {
+		d := simple.NewDirectedGraph()
+		gen.Gnp(d, 100, p, rnd)
+		graphs = append(graphs, d)
+	}
+
+	for i, g := range graphs {
+		want := Betweenness(g)
+		wantEdges := EdgeBetweenness(g)
+		for _, workers := range []int{0, 1, 3} {
+			got := ParallelBetweenness(g, workers)
+			if len(got) != len(want) {
+				t.Errorf("unexpected number of betweenness results for graph %d with %d workers: got:%d want:%d",
+					i, workers, len(got), len(want))
+			}
+			for id, w := range want {
+				if !floats.EqualWithinAbsOrRel(got[id], w, 1e-10, 1e-10) {
+					t.Errorf("unexpected betweenness result for graph %d node %d with %d workers: got:%v want:%v",
+						i, id, workers, got[id], w)
+				}
+			}
+
+			gotEdges := ParallelEdgeBetweenness(g, workers)
+			if len(gotEdges) != len(wantEdges) {
+				t.Errorf("unexpected number of edge betweenness results for graph %d with %d workers: got:%d want:%d",
+					i, workers, len(gotEdges), len(wantEdges))
+			}
+			for e, w := range wantEdges {
+				if !floats.EqualWithinAbsOrRel(gotEdges[e], w, 1e-10, 1e-10) {
+					t.Errorf("unexpected edge betweenness result for graph %d edge %v with %d workers: got:%v want:%v",
+						i, e, workers, gotEdges[e], w)
+				}
+			}
+		}
+	}
+}
+
 func TestBetweennessWeighted(t *testing.T) {
 	for i, test := range betweennessTests {
 		g := simple.NewWeightedUndirectedGraph(0, math.Inf(1))
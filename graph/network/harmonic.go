@@ -0,0 +1,152 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package network
+
+import "gonum.org/v1/gonum/graph"
+
+// HarmonicLabels returns the harmonic function label distributions of the
+// unlabeled nodes of the undirected graph g given the labeled nodes in labels,
+// using the method of Zhu, Ghahramani and Lafferty "Semi-supervised learning
+// using Gaussian fields and harmonic functions" (2003). Labels are indices
+// into the returned distributions, so the distribution of each unlabeled node
+// has an element for each label up to the largest in labels. Entries in labels
+// without a corresponding node in g are ignored.
+//
+// The distribution of an unlabeled node holds, for each label, the probability
+// that a random walk from the node is absorbed at a node with that label, where
+// the walk moves along edges with probability proportional to edge weight. If g
+// implements graph.Weighted, edge weights are used, otherwise each edge has unit
+// weight. Unlabeled nodes that are not connected to any labeled node have no
+// defined distribution and are not included in the returned map.
+//
+// The harmonic functions are found by solving the Laplacian system restricted
+// to the unlabeled nodes using the conjugate gradient method. The returned ok
+// is false if the relative residual norm of the solution for any label does not
+// fall below tol within iters iterations. If iters is less than one, 10n+100
+// iterations are allowed where n is the number of nodes. HarmonicLabels will
+// panic if a label is negative or g has a non-positive edge weight.
+func HarmonicLabels(g graph.Undirected, labels map[int64]int, tol float64, iters int) (dist map[int64][]float64, ok bool) {
+	l := NewLaplacianSolver(g, JacobiPreconditioner)
+	if iters < 1 {
+		iters = l.maxIter()
+	}
+
+	n := len(l.nodes)
+	label := make([]int, n)
+	for i := range label {
+		label[i] = -1
+	}
+	var k int
+	for id, c := range labels {
+		if c < 0 {
+			panic("network: negative label")
+		}
+		i, exists := l.indexOf[id]
+		if !exists {
+			continue
+		}
+		label[i] = c
+		if c >= k {
+			k = c + 1
+		}
+	}
+
+	// Find the unlabeled nodes connected to a labeled node.
+	reached := make([]bool, n)
+	var queue []int
+	for i, c := range label {
+		if c >= 0 {
+			reached[i] = true
+			queue = append(queue, i)
+		}
+	}
+	for len(queue) != 0 {
+		u := queue[0]
+		queue = queue[1:]
+		for _, v := range l.adj[u] {
+			if !reached[v] {
+				reached[v] = true
+				queue = append(queue, v)
+			}
+		}
+	}
+
+	h := harmonicSystem{LaplacianSolver: l, label: label}
+	f := make([][]float64, k)
+	ok = true
+	for c := range f {
+		// The right hand side for label c is the total
+		// weight of edges from each unlabeled node to
+		// nodes with label c.
+		b := make([]float64, n)
+		for i, adj := range l.adj {
+			if label[i] >= 0 {
+				continue
+			}
+			for m, j := range adj {
+				if label[j] == c {
+					b[i] += l.weight[i][m]
+				}
+			}
+		}
+		f[c] = make([]float64, n)
+		if !h.solve(f[c], b, tol, iters) {
+			ok = false
+		}
+	}
+
+	dist = make(map[int64][]float64)
+	for i, u := range l.nodes {
+		if label[i] >= 0 || !reached[i] {
+			continue
+		}
+		d := make([]float64, k)
+		for c := range d {
+			d[c] = f[c][i]
+		}
+		dist[u.ID()] = d
+	}
+	return dist, ok
+}
+
+// harmonicSystem is the Laplacian of a graph restricted
+// to its unlabeled nodes.
+type harmonicSystem struct {
+	*LaplacianSolver
+
+	// label holds the label of each node,
+	// or -1 if the node is unlabeled.
+	label []int
+}
+
+// mulVecTo stores the product of the restricted Laplacian and x in dst.
+// Elements of dst corresponding to labeled nodes are set to zero.
+func (h harmonicSystem) mulVecTo(dst, x []float64) {
+	for i, adj := range h.adj {
+		if h.label[i] >= 0 {
+			dst[i] = 0
+			continue
+		}
+		v := h.deg[i] * x[i]
+		for m, j := range adj {
+			if h.label[j] < 0 {
+				v -= h.weight[i][m] * x[j]
+			}
+		}
+		dst[i] = v
+	}
+}
+
+// solve solves the restricted system Lx = b using the Jacobi preconditioned
+// conjugate gradient method, storing the result in x, and returns whether the
+// relative residual norm fell below tol within maxIter iterations. Elements of
+// x and b corresponding to labeled nodes must be zero.
+//
+// The restricted Laplacian is singular on unlabeled components without a
+// labeled node, but b is zero on those components, so the iteration remains
+// within the range of the operator.
+func (h harmonicSystem) solve(x, b []float64, tol float64, maxIter int) (ok bool) {
+	return conjugateGradient(x, b, tol, maxIter, h.mulVecTo, h.precondition)
+}
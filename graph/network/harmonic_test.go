@@ -0,0 +1,144 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package network
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/graphs/gen"
+	"gonum.org/v1/gonum/graph/simple"
+)
+
+var harmonicLabelsTests = []struct {
+	name   string
+	g      func() graph.Undirected
+	labels map[int64]int
+	want   map[int64][]float64
+}{
+	{
+		name: "path",
+		g: func() graph.Undirected {
+			g := simple.NewUndirectedGraph()
+			for i := 0; i < 4; i++ {
+				g.SetEdge(simple.Edge{F: simple.Node(i), T: simple.Node(i + 1)})
+			}
+			return g
+		},
+		labels: map[int64]int{0: 0, 4: 1},
+		want: map[int64][]float64{
+			1: {0.75, 0.25},
+			2: {0.5, 0.5},
+			3: {0.25, 0.75},
+		},
+	},
+	{
+		name: "weighted path",
+		g: func() graph.Undirected {
+			g := simple.NewWeightedUndirectedGraph(0, 0)
+			g.SetWeightedEdge(simple.WeightedEdge{F: simple.Node(0), T: simple.Node(1), W: 3})
+			g.SetWeightedEdge(simple.WeightedEdge{F: simple.Node(1), T: simple.Node(2), W: 1})
+			return g
+		},
+		labels: map[int64]int{0: 1, 2: 0},
+		want: map[int64][]float64{
+			1: {0.25, 0.75},
+		},
+	},
+	{
+		name: "star with unlabeled component",
+		g: func() graph.Undirected {
+			g := simple.NewUndirectedGraph()
+			for i := 1; i < 4; i++ {
+				g.SetEdge(simple.Edge{F: simple.Node(0), T: simple.Node(i)})
+			}
+			g.SetEdge(simple.Edge{F: simple.Node(5), T: simple.Node(6)})
+			g.AddNode(simple.Node(7))
+			return g
+		},
+		labels: map[int64]int{1: 0, 2: 2, 3: 2, 10: 1},
+		want: map[int64][]float64{
+			0: {1.0 / 3, 0, 2.0 / 3},
+		},
+	},
+}
+
+func TestHarmonicLabels(t *testing.T) {
+	for _, test := range harmonicLabelsTests {
+		got, ok := HarmonicLabels(test.g(), test.labels, 1e-12, 0)
+		if !ok {
+			t.Errorf("failed to converge for %q", test.name)
+		}
+		if len(got) != len(test.want) {
+			t.Errorf("unexpected number of distributions for %q: got:%d want:%d", test.name, len(got), len(test.want))
+		}
+		for id, want := range test.want {
+			if !floats.EqualApprox(got[id], want, 1e-10) {
+				t.Errorf("unexpected distribution for node %d in %q: got:%v want:%v", id, test.name, got[id], want)
+			}
+		}
+	}
+}
+
+func TestHarmonicLabelsRandom(t *testing.T) {
+	const k = 3
+	rnd := rand.New(rand.NewSource(1))
+	u := simple.NewUndirectedGraph()
+	gen.Gnp(u, 200, 0.03, rnd)
+	g := simple.NewWeightedUndirectedGraph(0, 0)
+	for _, n := range u.Nodes() {
+		g.AddNode(n)
+	}
+	for _, e := range u.Edges() {
+		g.SetWeightedEdge(simple.WeightedEdge{F: e.From(), T: e.To(), W: 0.5 + rnd.Float64()})
+	}
+	labels := make(map[int64]int)
+	for _, n := range g.Nodes() {
+		if rnd.Float64() < 0.1 {
+			labels[n.ID()] = rnd.Intn(k)
+		}
+	}
+
+	dist, ok := HarmonicLabels(g, labels, 1e-12, 0)
+	if !ok {
+		t.Fatal("failed to converge")
+	}
+	value := func(id int64, c int) float64 {
+		if l, ok := labels[id]; ok {
+			if l == c {
+				return 1
+			}
+			return 0
+		}
+		return dist[id][c]
+	}
+	for id, d := range dist {
+		if _, ok := labels[id]; ok {
+			t.Errorf("unexpected distribution for labeled node %d", id)
+		}
+		if sum := floats.Sum(d); math.Abs(sum-1) > 1e-9 {
+			t.Errorf("distribution for node %d does not sum to one: %v", id, sum)
+		}
+		// Each distribution is the weighted
+		// mean of those of its neighbors.
+		n := simple.Node(id)
+		for c := range d {
+			var mean, deg float64
+			for _, v := range g.From(n) {
+				w, _ := g.Weight(n, v)
+				mean += w * value(v.ID(), c)
+				deg += w
+			}
+			mean /= deg
+			if math.Abs(d[c]-mean) > 1e-9 {
+				t.Errorf("distribution for node %d is not harmonic for label %d: got:%v want:%v", id, c, d[c], mean)
+			}
+		}
+	}
+}
@@ -0,0 +1,403 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package network
+
+import (
+	"sort"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/internal/ordered"
+)
+
+// LaplacianPreconditioner specifies the preconditioner
+// used by a LaplacianSolver.
+type LaplacianPreconditioner int
+
+const (
+	// JacobiPreconditioner uses the diagonal
+	// of the Laplacian as the preconditioner.
+	JacobiPreconditioner LaplacianPreconditioner = iota
+
+	// TreePreconditioner uses the Laplacian of a
+	// maximum weight spanning forest of the graph
+	// as the preconditioner. Systems in the forest
+	// Laplacian are solved exactly in linear time.
+	TreePreconditioner
+)
+
+// LaplacianSolver solves linear systems of the form Lx = b where L is the
+// Laplacian of a weighted undirected graph, using the preconditioned
+// conjugate gradient method.
+type LaplacianSolver struct {
+	nodes   []graph.Node
+	indexOf map[int64]int
+
+	// adj and weight hold the neighbor
+	// indices and edge weights for each
+	// node, and deg holds the weighted
+	// degree of each node.
+	adj    [][]int
+	weight [][]float64
+	deg    []float64
+
+	// comp holds the connected component
+	// of each node and size holds the
+	// number of nodes in each component.
+	comp []int
+	size []float64
+
+	pre LaplacianPreconditioner
+
+	// order holds the node indices of the
+	// preconditioning forest in breadth-first
+	// order from the roots, and parent and
+	// parentWeight hold the parent of each
+	// node and the weight of the edge to its
+	// parent. Roots have a parent of -1.
+	order        []int
+	parent       []int
+	parentWeight []float64
+}
+
+// NewLaplacianSolver returns a LaplacianSolver for the Laplacian of the
+// undirected graph g using the specified preconditioner. If g implements
+// graph.Weighted, edge weights are used as the off-diagonal elements of
+// the Laplacian, otherwise each edge has unit weight. Self edges do not
+// contribute to the Laplacian. NewLaplacianSolver will panic if g has a
+// non-positive edge weight.
+//
+// The TreePreconditioner is constructed from a maximum weight spanning
+// forest, a simple proxy for the low-stretch spanning trees used by
+// combinatorial preconditioning schemes, and typically requires far fewer
+// iterations than the JacobiPreconditioner for sparse graphs.
+func NewLaplacianSolver(g graph.Undirected, pre LaplacianPreconditioner) *LaplacianSolver {
+	nodes := g.Nodes()
+	sort.Sort(ordered.ByID(nodes))
+	l := &LaplacianSolver{
+		nodes:   nodes,
+		indexOf: make(map[int64]int, len(nodes)),
+		adj:     make([][]int, len(nodes)),
+		weight:  make([][]float64, len(nodes)),
+		deg:     make([]float64, len(nodes)),
+		pre:     pre,
+	}
+	for i, n := range nodes {
+		l.indexOf[n.ID()] = i
+	}
+	wg, isWeighted := g.(graph.Weighted)
+	for i, u := range nodes {
+		to := g.From(u)
+		sort.Sort(ordered.ByID(to))
+		for _, v := range to {
+			j := l.indexOf[v.ID()]
+			if i == j {
+				continue
+			}
+			w := 1.0
+			if isWeighted {
+				var ok bool
+				w, ok = wg.Weight(u, v)
+				if !ok {
+					panic("network: unexpected invalid weight")
+				}
+				if !(w > 0) {
+					panic("network: non-positive edge weight")
+				}
+			}
+			l.adj[i] = append(l.adj[i], j)
+			l.weight[i] = append(l.weight[i], w)
+			l.deg[i] += w
+		}
+	}
+	l.comp, l.size = l.components()
+
+	switch pre {
+	case JacobiPreconditioner:
+	case TreePreconditioner:
+		l.buildForest()
+	default:
+		panic("network: unknown laplacian preconditioner")
+	}
+
+	return l
+}
+
+// Solve solves Lx = b, placing the solution into dst and returning it, and
+// returning whether the relative residual norm of the solution fell below
+// tol within the given number of iterations. Indexing into b and dst is by
+// node ID and missing elements of b are treated as zero. If dst is nil, a
+// new map is created.
+//
+// The Laplacian is singular, so b is first projected onto the range of L by
+// subtracting the mean of b over each connected component of the graph, and
+// the returned solution has zero mean over each connected component. The
+// returned solution is then the minimum norm solution, L⁺b. If iters is less
+// than one, 10n+100 iterations are allowed where n is the number of nodes.
+func (l *LaplacianSolver) Solve(dst, b map[int64]float64, tol float64, iters int) (x map[int64]float64, ok bool) {
+	if dst == nil {
+		dst = make(map[int64]float64, len(l.nodes))
+	}
+	if iters < 1 {
+		iters = l.maxIter()
+	}
+	bv := make([]float64, len(l.nodes))
+	for id, v := range b {
+		i, exists := l.indexOf[id]
+		if !exists {
+			continue
+		}
+		bv[i] = v
+	}
+	l.project(bv)
+	xv := make([]float64, len(l.nodes))
+	ok = l.solve(xv, bv, tol, iters)
+	l.project(xv)
+	for i, n := range l.nodes {
+		dst[n.ID()] = xv[i]
+	}
+	return dst, ok
+}
+
+// project subtracts the mean of x over each
+// connected component from the elements of x.
+func (l *LaplacianSolver) project(x []float64) {
+	mean := make([]float64, len(l.size))
+	for i, c := range l.comp {
+		mean[c] += x[i]
+	}
+	for c, n := range l.size {
+		mean[c] /= n
+	}
+	for i, c := range l.comp {
+		x[i] -= mean[c]
+	}
+}
+
+// mulVecTo stores Lx in dst.
+func (l *LaplacianSolver) mulVecTo(dst, x []float64) {
+	for i, adj := range l.adj {
+		v := l.deg[i] * x[i]
+		for k, j := range adj {
+			v -= l.weight[i][k] * x[j]
+		}
+		dst[i] = v
+	}
+}
+
+// solve solves Lx = b using the preconditioned conjugate gradient method,
+// starting from the initial value held in x and storing the result in x.
+// The elements of b must sum to zero over each connected component of the
+// graph. solve returns whether the relative residual norm fell below tol
+// within maxIter iterations.
+func (l *LaplacianSolver) solve(x, b []float64, tol float64, maxIter int) (ok bool) {
+	return conjugateGradient(x, b, tol, maxIter, l.mulVecTo, l.precondition)
+}
+
+// conjugateGradient solves Ax = b using the preconditioned conjugate
+// gradient method, starting from the initial value held in x and storing
+// the result in x. The symmetric positive semi-definite operator A is
+// applied by mul and the preconditioner is applied by pre, which is passed
+// scratch space in its work parameter. b must be in the range of A.
+// conjugateGradient returns whether the relative residual norm fell below
+// tol within maxIter iterations.
+func conjugateGradient(x, b []float64, tol float64, maxIter int, mul func(dst, x []float64), pre func(dst, r, work []float64)) (ok bool) {
+	n := len(x)
+	r := make([]float64, n)
+	z := make([]float64, n)
+	p := make([]float64, n)
+	ap := make([]float64, n)
+
+	mul(r, x)
+	floats.SubTo(r, b, r)
+	bNorm := floats.Norm(b, 2)
+	if bNorm == 0 {
+		bNorm = 1
+	}
+	if floats.Norm(r, 2) <= tol*bNorm {
+		return true
+	}
+
+	pre(z, r, ap)
+	copy(p, z)
+	rz := floats.Dot(r, z)
+	for iter := 0; iter < maxIter; iter++ {
+		mul(ap, p)
+		pap := floats.Dot(p, ap)
+		if pap <= 0 {
+			// p is in the null space of A, so
+			// no further progress is possible.
+			return floats.Norm(r, 2) <= tol*bNorm
+		}
+		alpha := rz / pap
+		floats.AddScaled(x, alpha, p)
+		floats.AddScaled(r, -alpha, ap)
+		if floats.Norm(r, 2) <= tol*bNorm {
+			return true
+		}
+		pre(z, r, ap)
+		rzNext := floats.Dot(r, z)
+		beta := rzNext / rz
+		rz = rzNext
+		for i := range p {
+			p[i] = z[i] + beta*p[i]
+		}
+	}
+	return false
+}
+
+// precondition stores the preconditioned value of r in dst
+// using work as scratch space.
+func (l *LaplacianSolver) precondition(dst, r, work []float64) {
+	switch l.pre {
+	case JacobiPreconditioner:
+		for i, d := range l.deg {
+			if d == 0 {
+				dst[i] = 0
+				continue
+			}
+			dst[i] = r[i] / d
+		}
+	case TreePreconditioner:
+		// Accumulate the flow through each forest
+		// edge from the leaves towards the roots.
+		f := work
+		copy(f, r)
+		for k := len(l.order) - 1; k >= 0; k-- {
+			v := l.order[k]
+			if p := l.parent[v]; p >= 0 {
+				f[p] += f[v]
+			}
+		}
+		// Set potentials from the roots outwards
+		// so that each edge carries its flow.
+		for _, v := range l.order {
+			p := l.parent[v]
+			if p < 0 {
+				dst[v] = 0
+				continue
+			}
+			dst[v] = dst[p] + f[v]/l.parentWeight[v]
+		}
+	}
+}
+
+// buildForest constructs the maximum weight spanning forest
+// used by the TreePreconditioner.
+func (l *LaplacianSolver) buildForest() {
+	edges := l.edges()
+	sort.SliceStable(edges, func(i, j int) bool { return edges[i].w > edges[j].w })
+
+	ds := make([]int, len(l.nodes))
+	for i := range ds {
+		ds[i] = i
+	}
+	var find func(int) int
+	find = func(i int) int {
+		if ds[i] != i {
+			ds[i] = find(ds[i])
+		}
+		return ds[i]
+	}
+	type neighbor struct {
+		v int
+		w float64
+	}
+	forest := make([][]neighbor, len(l.nodes))
+	for _, e := range edges {
+		ru, rv := find(e.u), find(e.v)
+		if ru == rv {
+			continue
+		}
+		ds[ru] = rv
+		forest[e.u] = append(forest[e.u], neighbor{v: e.v, w: e.w})
+		forest[e.v] = append(forest[e.v], neighbor{v: e.u, w: e.w})
+	}
+
+	l.order = make([]int, 0, len(l.nodes))
+	l.parent = make([]int, len(l.nodes))
+	l.parentWeight = make([]float64, len(l.nodes))
+	seen := make([]bool, len(l.nodes))
+	for root := range l.nodes {
+		if seen[root] {
+			continue
+		}
+		seen[root] = true
+		l.parent[root] = -1
+		start := len(l.order)
+		l.order = append(l.order, root)
+		for k := start; k < len(l.order); k++ {
+			u := l.order[k]
+			for _, nb := range forest[u] {
+				if seen[nb.v] {
+					continue
+				}
+				seen[nb.v] = true
+				l.parent[nb.v] = u
+				l.parentWeight[nb.v] = nb.w
+				l.order = append(l.order, nb.v)
+			}
+		}
+	}
+}
+
+// laplacianEdge is an edge of a LaplacianSolver.
+type laplacianEdge struct {
+	u, v int
+	w    float64
+}
+
+// edges returns the edges of l.
+func (l *LaplacianSolver) edges() []laplacianEdge {
+	var edges []laplacianEdge
+	for u, adj := range l.adj {
+		for k, v := range adj {
+			if u < v {
+				edges = append(edges, laplacianEdge{u: u, v: v, w: l.weight[u][k]})
+			}
+		}
+	}
+	return edges
+}
+
+// components returns the connected component label of each
+// node and the number of nodes in each component.
+func (l *LaplacianSolver) components() (comp []int, size []float64) {
+	comp = make([]int, len(l.nodes))
+	for i := range comp {
+		comp[i] = -1
+	}
+	for i := range l.nodes {
+		if comp[i] != -1 {
+			continue
+		}
+		c := len(size)
+		size = append(size, 1)
+		comp[i] = c
+		stack := []int{i}
+		for len(stack) != 0 {
+			u := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			for _, v := range l.adj[u] {
+				if comp[v] == -1 {
+					comp[v] = c
+					size[c]++
+					stack = append(stack, v)
+				}
+			}
+		}
+	}
+	return comp, size
+}
+
+// maxIter returns the default maximum number of
+// conjugate gradient iterations for l.
+func (l *LaplacianSolver) maxIter() int {
+	return 10*len(l.nodes) + 100
+}
+
+// laplacianTol is the default relative residual
+// tolerance for Laplacian system solutions.
+const laplacianTol = 1e-10
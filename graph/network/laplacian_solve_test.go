@@ -0,0 +1,118 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package network
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/graphs/gen"
+	"gonum.org/v1/gonum/graph/simple"
+	"gonum.org/v1/gonum/graph/topo"
+)
+
+func TestLaplacianSolver(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	var graphs []graph.Undirected
+	for _, p := range []float64{0.02, 0.05, 0.2} {
+		u := simple.NewUndirectedGraph()
+		gen.Gnp(u, 100, p, rnd)
+		// Weight edges to give a range of conductances.
+		g := simple.NewWeightedUndirectedGraph(0, 0)
+		for _, n := range u.Nodes() {
+			g.AddNode(n)
+		}
+		for _, e := range u.Edges() {
+			g.SetWeightedEdge(simple.WeightedEdge{F: e.From(), T: e.To(), W: math.Exp(4 * rnd.Float64())})
+		}
+		graphs = append(graphs, g)
+	}
+	gg := simple.NewUndirectedGraph()
+	for u, e := range grid(10) {
+		for v := range e {
+			gg.SetEdge(simple.Edge{F: simple.Node(u), T: simple.Node(v)})
+		}
+	}
+	graphs = append(graphs, gg)
+
+	for i, g := range graphs {
+		for _, pre := range []LaplacianPreconditioner{JacobiPreconditioner, TreePreconditioner} {
+			test := fmt.Sprintf("graph %d with preconditioner %d", i, pre)
+			l := NewLaplacianSolver(g, pre)
+			b := make(map[int64]float64)
+			for _, n := range g.Nodes() {
+				b[n.ID()] = rnd.NormFloat64()
+			}
+			x, ok := l.Solve(nil, b, 1e-10, 0)
+			if !ok {
+				t.Errorf("failed to converge for %s", test)
+				continue
+			}
+			checkLaplacianSolution(t, test, g, x, b)
+		}
+	}
+}
+
+// checkLaplacianSolution checks that x is the minimum norm solution of Lx = b
+// by checking that Lx is equal to the projection of b onto the range of L and
+// that x has zero mean over each connected component of g.
+func checkLaplacianSolution(t *testing.T, test string, g graph.Undirected, x, b map[int64]float64) {
+	for _, cc := range topo.ConnectedComponents(g) {
+		var bMean, xMean float64
+		for _, n := range cc {
+			bMean += b[n.ID()]
+			xMean += x[n.ID()]
+		}
+		bMean /= float64(len(cc))
+		xMean /= float64(len(cc))
+		if math.Abs(xMean) > 1e-9 {
+			t.Errorf("unexpected non-zero component mean for %s: %v", test, xMean)
+		}
+		wg, isWeighted := g.(graph.Weighted)
+		for _, u := range cc {
+			var lx float64
+			for _, v := range g.From(u) {
+				w := 1.0
+				if isWeighted {
+					w, _ = wg.Weight(u, v)
+				}
+				lx += w * (x[u.ID()] - x[v.ID()])
+			}
+			if want := b[u.ID()] - bMean; math.Abs(lx-want) > 1e-7 {
+				t.Errorf("unexpected value of (Lx)[%d] for %s: got:%v want:%v", u.ID(), test, lx, want)
+			}
+		}
+	}
+}
+
+func TestLaplacianSolverTreeExact(t *testing.T) {
+	// The tree preconditioner is the exact inverse
+	// of the Laplacian of a forest, so a single
+	// iteration is sufficient.
+	g := simple.NewWeightedUndirectedGraph(0, 0)
+	rnd := rand.New(rand.NewSource(1))
+	for i := 1; i < 50; i++ {
+		g.SetWeightedEdge(simple.WeightedEdge{F: simple.Node(rnd.Intn(i)), T: simple.Node(i), W: 1 + rnd.Float64()})
+	}
+	for i := 50; i < 60; i++ {
+		g.SetWeightedEdge(simple.WeightedEdge{F: simple.Node(50 + rnd.Intn(i-49)), T: simple.Node(i + 1), W: 1 + rnd.Float64()})
+	}
+	g.AddNode(simple.Node(100))
+
+	b := make(map[int64]float64)
+	for _, n := range g.Nodes() {
+		b[n.ID()] = rnd.NormFloat64()
+	}
+	l := NewLaplacianSolver(g, TreePreconditioner)
+	x, ok := l.Solve(nil, b, 1e-10, 1)
+	if !ok {
+		t.Fatal("failed to converge in a single iteration")
+	}
+	checkLaplacianSolution(t, "forest", g, x, b)
+}
@@ -160,6 +160,31 @@ func PageRankSparse(g graph.Directed, damp, tol float64) map[int64]float64 {
 	return ranks
 }
 
+// PersonalizedPageRank returns the personalized PageRank weights for nodes of
+// the directed graph g using the given damping factor and personalization
+// vector, and terminating when the 2-norm of the vector difference between
+// iterations is below tol. The returned map is keyed on the graph node IDs
+// and its values sum to one.
+//
+// With probability 1-damp, and with certainty from nodes with no outgoing
+// edge, the random surfer teleports to a node chosen with probability
+// proportional to its value in personalization rather than uniformly.
+// Entries in personalization without a corresponding node in g are ignored.
+// PersonalizedPageRank is equivalent to RandomWalkWithRestart with a restart
+// probability of 1-damp, except that edge weights are ignored.
+//
+// PersonalizedPageRank will panic if damp is not in [0, 1), or the
+// personalization values are negative or do not have a positive sum over
+// the nodes of g.
+func PersonalizedPageRank(g graph.Directed, damp, tol float64, personalization map[int64]float64) map[int64]float64 {
+	if !(0 <= damp && damp < 1) {
+		panic("network: damping factor out of range")
+	}
+	// Hide any edge weights from RandomWalkWithRestart
+	// so that the surfer follows edges uniformly.
+	return RandomWalkWithRestart(struct{ graph.Directed }{g}, personalization, 1-damp, tol)
+}
+
 // rowCompressedMatrix implements row-compressed
 // matrix/vector multiplication.
 type rowCompressedMatrix []compressedRow
@@ -127,6 +127,66 @@ func TestPageRankSparse(t *testing.T) {
 	}
 }
 
+func TestPersonalizedPageRank(t *testing.T) {
+	for i, test := range pageRankTests {
+		g := simple.NewDirectedGraph()
+		for u, e := range test.g {
+			// Add nodes that are not defined by an edge.
+			if !g.Has(simple.Node(u)) {
+				g.AddNode(simple.Node(u))
+			}
+			for v := range e {
+				g.SetEdge(simple.Edge{F: simple.Node(u), T: simple.Node(v)})
+			}
+		}
+
+		// Uniform personalization gives the PageRank weights.
+		uniform := make(map[int64]float64)
+		for u := range test.g {
+			uniform[int64(u)] = 1
+		}
+		got := PersonalizedPageRank(g, test.damp, test.tol, uniform)
+		prec := 1 - int(math.Log10(test.wantTol))
+		for n := range test.g {
+			if !floats.EqualWithinAbsOrRel(got[int64(n)], test.want[int64(n)], test.wantTol, test.wantTol) {
+				t.Errorf("unexpected PersonalizedPageRank result for test %d:\ngot: %v\nwant:%v",
+					i, orderedFloats(got, prec), orderedFloats(test.want, prec))
+				break
+			}
+		}
+
+		// Personalized PageRank is a random walk with restart.
+		personalization := map[int64]float64{0: 1, 2: 3, 100: 5}
+		got = PersonalizedPageRank(g, test.damp, 1e-12, personalization)
+		want := RandomWalkWithRestart(g, personalization, 1-test.damp, 1e-12)
+		for n := range test.g {
+			if math.Abs(got[int64(n)]-want[int64(n)]) > 1e-10 {
+				t.Errorf("unexpected personalized result for test %d:\ngot: %v\nwant:%v",
+					i, orderedFloats(got, 10), orderedFloats(want, 10))
+				break
+			}
+		}
+
+		// Edge weights are ignored.
+		wg := simple.NewWeightedDirectedGraph(0, 0)
+		for _, n := range g.Nodes() {
+			wg.AddNode(n)
+		}
+		for _, e := range g.Edges() {
+			wg.SetWeightedEdge(simple.WeightedEdge{F: e.From(), T: e.To(), W: float64(e.To().ID() + 1)})
+		}
+		want = got
+		got = PersonalizedPageRank(wg, test.damp, 1e-12, personalization)
+		for n := range test.g {
+			if math.Abs(got[int64(n)]-want[int64(n)]) > 1e-10 {
+				t.Errorf("unexpected weighted personalized result for test %d:\ngot: %v\nwant:%v",
+					i, orderedFloats(got, 10), orderedFloats(want, 10))
+				break
+			}
+		}
+	}
+}
+
 func orderedFloats(w map[int64]float64, prec int) []keyFloatVal {
 	o := make(orderedFloatsMap, 0, len(w))
 	for k, v := range w {
@@ -0,0 +1,129 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package network
+
+import (
+	"sort"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/internal/ordered"
+	"gonum.org/v1/gonum/mat"
+)
+
+// RandomWalkWithRestart returns the random walk with restart scores for the
+// nodes of g from the seed nodes in seeds, using the given restart probability
+// and terminating when the 2-norm of the vector difference between iterations
+// is below tol. The returned map is keyed on the graph node IDs and its values
+// sum to one.
+//
+// The score of a node is the stationary probability of a random walker being
+// at the node, where at each step the walker returns to a seed node with
+// probability restart and otherwise moves to a node reachable from its current
+// node. Seed nodes are chosen with probability proportional to their value in
+// seeds, and entries in seeds without a corresponding node in g are ignored.
+// Walkers at nodes with no outgoing edge always restart. If g implements
+// graph.Weighted, each move is made with probability proportional to the
+// weight of the edge, otherwise edges are chosen uniformly.
+//
+// RandomWalkWithRestart will panic if restart is not in (0, 1], the seed
+// values are negative or do not have a positive sum over the nodes of g, or
+// g has a negative edge weight.
+func RandomWalkWithRestart(g graph.Graph, seeds map[int64]float64, restart, tol float64) map[int64]float64 {
+	if !(0 < restart && restart <= 1) {
+		panic("network: restart probability out of range")
+	}
+
+	nodes := g.Nodes()
+	sort.Sort(ordered.ByID(nodes))
+	indexOf := make(map[int64]int, len(nodes))
+	for i, n := range nodes {
+		indexOf[n.ID()] = i
+	}
+
+	e := make([]float64, len(nodes))
+	var sum float64
+	for id, s := range seeds {
+		if s < 0 {
+			panic("network: negative seed value")
+		}
+		i, ok := indexOf[id]
+		if !ok {
+			continue
+		}
+		e[i] = s
+		sum += s
+	}
+	if !(sum > 0) {
+		panic("network: no seed in graph")
+	}
+	for i := range e {
+		e[i] /= sum
+	}
+
+	// Build the column stochastic transition matrix
+	// scaled by the probability of not restarting.
+	wg, isWeighted := g.(graph.Weighted)
+	m := make(rowCompressedMatrix, len(nodes))
+	var dangling []int
+	for j, u := range nodes {
+		to := g.From(u)
+		sort.Sort(ordered.ByID(to))
+		w := make([]float64, len(to))
+		var deg float64
+		for k, v := range to {
+			w[k] = 1
+			if isWeighted {
+				var ok bool
+				w[k], ok = wg.Weight(u, v)
+				if !ok {
+					panic("network: unexpected invalid weight")
+				}
+				if w[k] < 0 {
+					panic("network: negative edge weight")
+				}
+			}
+			deg += w[k]
+		}
+		if deg == 0 {
+			dangling = append(dangling, j)
+			continue
+		}
+		f := (1 - restart) / deg
+		for k, v := range to {
+			m.addTo(indexOf[v.ID()], j, f*w[k])
+		}
+	}
+
+	last := make([]float64, len(nodes))
+	lastV := mat.NewVecDense(len(nodes), last)
+	vec := make([]float64, len(nodes))
+	copy(vec, e)
+	v := mat.NewVecDense(len(nodes), vec)
+
+	for {
+		lastV, v = v, lastV
+		last, vec = vec, last
+
+		// Walkers restart with probability restart,
+		// or with certainty from a dangling node.
+		r := restart
+		for _, j := range dangling {
+			r += (1 - restart) * last[j]
+		}
+		m.mulVecUnitary(v, lastV)
+		for i, p := range e {
+			vec[i] += r * p
+		}
+		if normDiff(vec, last) < tol {
+			break
+		}
+	}
+
+	scores := make(map[int64]float64, len(nodes))
+	for i, s := range vec {
+		scores[nodes[i].ID()] = s
+	}
+	return scores
+}
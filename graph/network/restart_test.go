@@ -0,0 +1,139 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package network
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/graphs/gen"
+	"gonum.org/v1/gonum/graph/simple"
+	"gonum.org/v1/gonum/mat"
+)
+
+var randomWalkWithRestartTests = []struct {
+	name    string
+	g       func() graph.Graph
+	seeds   map[int64]float64
+	restart float64
+	want    map[int64]float64
+}{
+	{
+		name: "edge",
+		g: func() graph.Graph {
+			g := simple.NewUndirectedGraph()
+			g.SetEdge(simple.Edge{F: simple.Node(0), T: simple.Node(1)})
+			return g
+		},
+		seeds:   map[int64]float64{0: 1},
+		restart: 0.5,
+		// x0 = c + (1-c)x1 and x1 = (1-c)x0.
+		want: map[int64]float64{0: 2.0 / 3, 1: 1.0 / 3},
+	},
+	{
+		name: "dangling",
+		g: func() graph.Graph {
+			g := simple.NewDirectedGraph()
+			g.SetEdge(simple.Edge{F: simple.Node(0), T: simple.Node(1)})
+			g.AddNode(simple.Node(2))
+			return g
+		},
+		seeds:   map[int64]float64{0: 1, 2: 1, 5: 1},
+		restart: 0.2,
+		// Walkers at 1 and 2 always restart, so
+		// x0 = x2 = (1-x1)/2 and x1 = 0.8x0.
+		want: map[int64]float64{0: 1 / 2.8, 1: 0.8 / 2.8, 2: 1 / 2.8},
+	},
+	{
+		name: "weighted star",
+		g: func() graph.Graph {
+			g := simple.NewWeightedUndirectedGraph(0, 0)
+			g.SetWeightedEdge(simple.WeightedEdge{F: simple.Node(0), T: simple.Node(1), W: 1})
+			g.SetWeightedEdge(simple.WeightedEdge{F: simple.Node(0), T: simple.Node(2), W: 3})
+			return g
+		},
+		seeds:   map[int64]float64{0: 1},
+		restart: 1,
+		want:    map[int64]float64{0: 1, 1: 0, 2: 0},
+	},
+}
+
+func TestRandomWalkWithRestart(t *testing.T) {
+	for _, test := range randomWalkWithRestartTests {
+		got := RandomWalkWithRestart(test.g(), test.seeds, test.restart, 1e-12)
+		if len(got) != len(test.want) {
+			t.Errorf("unexpected number of scores for %q: got:%d want:%d", test.name, len(got), len(test.want))
+		}
+		for id, want := range test.want {
+			if math.Abs(got[id]-want) > 1e-10 {
+				t.Errorf("unexpected score for node %d in %q: got:%v want:%v", id, test.name, got[id], want)
+			}
+		}
+	}
+}
+
+func TestRandomWalkWithRestartLinearSolve(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for trial := 0; trial < 5; trial++ {
+		g := simple.NewDirectedGraph()
+		gen.Gnp(g, 40, 0.08, rnd)
+		nodes := g.Nodes()
+		seeds := map[int64]float64{
+			nodes[rnd.Intn(len(nodes))].ID(): 1,
+			nodes[rnd.Intn(len(nodes))].ID(): 2,
+		}
+		const restart = 0.15
+		got := RandomWalkWithRestart(g, seeds, restart, 1e-12)
+
+		// Solve (I - (1-c)P - (1-c)e.dᵀ)x = c.e where P is the
+		// transition matrix, e is the normalized seed vector and
+		// d indicates the dangling nodes.
+		n := len(nodes)
+		indexOf := make(map[int64]int, n)
+		for i, u := range nodes {
+			indexOf[u.ID()] = i
+		}
+		var sum float64
+		for _, s := range seeds {
+			sum += s
+		}
+		e := mat.NewVecDense(n, nil)
+		for id, s := range seeds {
+			e.SetVec(indexOf[id], s/sum)
+		}
+		a := mat.NewDense(n, n, nil)
+		for j, u := range nodes {
+			a.Set(j, j, 1)
+			to := g.From(u)
+			if len(to) == 0 {
+				for i := 0; i < n; i++ {
+					a.Set(i, j, a.At(i, j)-(1-restart)*e.AtVec(i))
+				}
+				continue
+			}
+			for _, v := range to {
+				i := indexOf[v.ID()]
+				a.Set(i, j, a.At(i, j)-(1-restart)/float64(len(to)))
+			}
+		}
+		var b, x mat.VecDense
+		b.ScaleVec(restart, e)
+		err := x.SolveVec(a, &b)
+		if err != nil {
+			t.Fatalf("unexpected error solving linear system: %v", err)
+		}
+
+		name := fmt.Sprintf("trial %d", trial)
+		for i, u := range nodes {
+			if want := x.AtVec(i); math.Abs(got[u.ID()]-want) > 1e-9 {
+				t.Errorf("unexpected score for node %d in %s: got:%v want:%v", u.ID(), name, got[u.ID()], want)
+			}
+		}
+	}
+}
@@ -0,0 +1,185 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package network
+
+import (
+	"math"
+	"sort"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/graph"
+)
+
+// EffectiveResistance returns the effective resistance between u and v in the
+// undirected graph g, treating each edge as a resistor with a conductance equal
+// to its weight. If g does not implement graph.Weighted, each edge has unit
+// conductance. If u or v is not in g, or there is no path between u and v,
+// EffectiveResistance returns +Inf. EffectiveResistance will panic if g has a
+// non-positive edge weight.
+func EffectiveResistance(g graph.Undirected, u, v graph.Node) float64 {
+	if !g.Has(u) || !g.Has(v) {
+		return math.Inf(1)
+	}
+	if u.ID() == v.ID() {
+		return 0
+	}
+	l := NewLaplacianSolver(g, TreePreconditioner)
+	i, j := l.indexOf[u.ID()], l.indexOf[v.ID()]
+	if l.comp[i] != l.comp[j] {
+		return math.Inf(1)
+	}
+	b := make([]float64, len(l.nodes))
+	b[i] = 1
+	b[j] = -1
+	x := make([]float64, len(l.nodes))
+	if !l.solve(x, b, laplacianTol, l.maxIter()) {
+		panic("network: laplacian solve failed to converge")
+	}
+	return x[i] - x[j]
+}
+
+// Sparsify constructs a spectral sparsifier of the undirected graph g in dst
+// using the effective resistance sampling scheme described in Spielman and
+// Srivastava "Graph sparsification by effective resistances"
+// doi:10.1137/080734029. Edges of g are sampled with replacement with a
+// probability proportional to the product of their weight and effective
+// resistance, and sampled edges are added to dst with weights scaled so that
+// the Laplacian of dst is an unbiased estimator of the Laplacian of g. If g
+// does not implement graph.Weighted, each edge has unit weight.
+//
+// The number of samples is ⌈4 n ln(n) / eps²⌉ where n is the number of nodes
+// in g. With high probability the quadratic form of the Laplacian of dst, and
+// so the weight of every cut, is within a factor of 1±eps of the quadratic form
+// of the Laplacian of g. Effective resistances are approximated using random
+// projections when that is cheaper than computing them exactly. Sparsification
+// only reduces the number of edges when g has many more than n ln(n) / eps²
+// edges.
+//
+// If src is not nil it is used as the random source, otherwise rand.Float64 is
+// used. Sparsify will panic if eps is not positive, if g has a non-positive
+// edge weight or if a node ID in g matches a node ID in dst.
+func Sparsify(dst graph.WeightedBuilder, g graph.Undirected, eps float64, src *rand.Rand) {
+	if !(eps > 0) {
+		panic("network: non-positive sparsification tolerance")
+	}
+	var rnd func() float64
+	if src == nil {
+		rnd = rand.Float64
+	} else {
+		rnd = src.Float64
+	}
+
+	l := NewLaplacianSolver(g, TreePreconditioner)
+	for _, n := range l.nodes {
+		dst.AddNode(n)
+	}
+	edges := l.edges()
+	if len(edges) == 0 {
+		return
+	}
+
+	r := l.resistances(edges, rnd)
+	cum := make([]float64, len(edges))
+	var sum float64
+	for k, e := range edges {
+		sum += e.w * r[k]
+		cum[k] = sum
+	}
+
+	n := float64(len(l.nodes))
+	samples := int(math.Ceil(4 * n * math.Log(n) / (eps * eps)))
+	weight := make([]float64, len(edges))
+	for s := 0; s < samples; s++ {
+		k := sort.SearchFloat64s(cum, rnd()*sum)
+		if k == len(cum) {
+			k--
+		}
+		p := edges[k].w * r[k] / sum
+		weight[k] += edges[k].w / (float64(samples) * p)
+	}
+
+	for k, e := range edges {
+		if weight[k] == 0 {
+			continue
+		}
+		dst.SetWeightedEdge(dst.NewWeightedEdge(l.nodes[e.u], l.nodes[e.v], weight[k]))
+	}
+}
+
+// resistances returns the effective resistances of the given edges of l.
+// Resistances are calculated exactly if that requires no more Laplacian
+// solves than approximating them by random projection.
+func (l *LaplacianSolver) resistances(edges []laplacianEdge, rnd func() float64) []float64 {
+	// Use random projections with a Johnson-Lindenstrauss
+	// distortion of 0.5 to give constant factor
+	// approximations of the resistances.
+	const jlEps = 0.5
+	k := int(math.Ceil(24 * math.Log(float64(len(l.nodes))) / (jlEps * jlEps)))
+	if len(l.nodes) <= k {
+		return l.exactResistances(edges)
+	}
+	return l.approxResistances(edges, k, rnd)
+}
+
+// exactResistances returns the effective resistances of the given edges
+// of l using the columns of the pseudo-inverse of the Laplacian.
+func (l *LaplacianSolver) exactResistances(edges []laplacianEdge) []float64 {
+	n := len(l.nodes)
+	cols := make([][]float64, n)
+	for i := range cols {
+		// Project the basis vector onto the range
+		// of the Laplacian for the component of i.
+		b := make([]float64, n)
+		for j, c := range l.comp {
+			if c == l.comp[i] {
+				b[j] = -1 / l.size[c]
+			}
+		}
+		b[i]++
+		cols[i] = make([]float64, n)
+		if !l.solve(cols[i], b, laplacianTol, l.maxIter()) {
+			panic("network: laplacian solve failed to converge")
+		}
+	}
+	r := make([]float64, len(edges))
+	for i, e := range edges {
+		r[i] = cols[e.u][e.u] - cols[e.u][e.v] - cols[e.v][e.u] + cols[e.v][e.v]
+	}
+	return r
+}
+
+// approxResistances returns approximate effective resistances of the given
+// edges of l using k random projections as described in section 4 of the
+// Spielman and Srivastava paper.
+func (l *LaplacianSolver) approxResistances(edges []laplacianEdge, k int, rnd func() float64) []float64 {
+	n := len(l.nodes)
+	r := make([]float64, len(edges))
+	scale := 1 / math.Sqrt(float64(k))
+	y := make([]float64, n)
+	z := make([]float64, n)
+	for t := 0; t < k; t++ {
+		for i := range y {
+			y[i] = 0
+			z[i] = 0
+		}
+		for _, e := range edges {
+			q := scale * math.Sqrt(e.w)
+			if rnd() < 0.5 {
+				q = -q
+			}
+			y[e.u] += q
+			y[e.v] -= q
+		}
+		if !l.solve(z, y, laplacianTol, l.maxIter()) {
+			panic("network: laplacian solve failed to converge")
+		}
+		for i, e := range edges {
+			d := z[e.u] - z[e.v]
+			r[i] += d * d
+		}
+	}
+	return r
+}
@@ -0,0 +1,178 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package network
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/graphs/gen"
+	"gonum.org/v1/gonum/graph/simple"
+)
+
+var effectiveResistanceTests = []struct {
+	name string
+	g    func() graph.Undirected
+	u, v int64
+	want float64
+}{
+	{
+		name: "path",
+		g: func() graph.Undirected {
+			g := simple.NewUndirectedGraph()
+			for i := 0; i < 5; i++ {
+				g.SetEdge(simple.Edge{F: simple.Node(i), T: simple.Node(i + 1)})
+			}
+			return g
+		},
+		u: 0, v: 5,
+		want: 5,
+	},
+	{
+		name: "cycle",
+		g: func() graph.Undirected {
+			g := simple.NewUndirectedGraph()
+			for i := 0; i < 6; i++ {
+				g.SetEdge(simple.Edge{F: simple.Node(i), T: simple.Node((i + 1) % 6)})
+			}
+			return g
+		},
+		u: 0, v: 2,
+		want: 2.0 * 4 / 6,
+	},
+	{
+		name: "complete",
+		g: func() graph.Undirected {
+			g := simple.NewUndirectedGraph()
+			for i := 0; i < 8; i++ {
+				for j := i + 1; j < 8; j++ {
+					g.SetEdge(simple.Edge{F: simple.Node(i), T: simple.Node(j)})
+				}
+			}
+			return g
+		},
+		u: 3, v: 5,
+		want: 2.0 / 8,
+	},
+	{
+		name: "weighted triangle",
+		g: func() graph.Undirected {
+			g := simple.NewWeightedUndirectedGraph(0, 0)
+			g.SetWeightedEdge(simple.WeightedEdge{F: simple.Node(0), T: simple.Node(1), W: 1})
+			g.SetWeightedEdge(simple.WeightedEdge{F: simple.Node(1), T: simple.Node(2), W: 2})
+			g.SetWeightedEdge(simple.WeightedEdge{F: simple.Node(0), T: simple.Node(2), W: 4})
+			return g
+		},
+		// The path through 2 has a resistance of 1/2+1/4 in
+		// parallel with the unit resistance edge 0--1.
+		u: 0, v: 1,
+		want: 3.0 / 7,
+	},
+	{
+		name: "disconnected",
+		g: func() graph.Undirected {
+			g := simple.NewUndirectedGraph()
+			g.SetEdge(simple.Edge{F: simple.Node(0), T: simple.Node(1)})
+			g.SetEdge(simple.Edge{F: simple.Node(2), T: simple.Node(3)})
+			return g
+		},
+		u: 0, v: 3,
+		want: math.Inf(1),
+	},
+	{
+		name: "same node",
+		g: func() graph.Undirected {
+			g := simple.NewUndirectedGraph()
+			g.SetEdge(simple.Edge{F: simple.Node(0), T: simple.Node(1)})
+			return g
+		},
+		u: 1, v: 1,
+		want: 0,
+	},
+}
+
+func TestEffectiveResistance(t *testing.T) {
+	for _, test := range effectiveResistanceTests {
+		got := EffectiveResistance(test.g(), simple.Node(test.u), simple.Node(test.v))
+		if !floats.EqualWithinAbsOrRel(got, test.want, 1e-9, 1e-9) && got != test.want {
+			t.Errorf("unexpected effective resistance for %q: got:%v want:%v", test.name, got, test.want)
+		}
+	}
+}
+
+func TestApproxResistances(t *testing.T) {
+	g := simple.NewUndirectedGraph()
+	gen.Gnp(g, 60, 0.2, rand.New(rand.NewSource(1)))
+	l := NewLaplacianSolver(g, JacobiPreconditioner)
+	edges := l.edges()
+	want := l.exactResistances(edges)
+
+	// Distortion should be around 0.2 with 600 projections.
+	rnd := rand.New(rand.NewSource(1))
+	got := l.approxResistances(edges, 600, rnd.Float64)
+	for i, e := range edges {
+		if math.Abs(got[i]-want[i]) > 0.5*want[i] {
+			t.Errorf("unexpected approximate resistance for edge %d--%d: got:%v want:%v",
+				l.nodes[e.u].ID(), l.nodes[e.v].ID(), got[i], want[i])
+		}
+		if r := EffectiveResistance(g, l.nodes[e.u], l.nodes[e.v]); math.Abs(r-want[i]) > 1e-9 {
+			t.Errorf("unexpected exact resistance for edge %d--%d: got:%v want:%v",
+				l.nodes[e.u].ID(), l.nodes[e.v].ID(), r, want[i])
+		}
+	}
+}
+
+func TestSparsify(t *testing.T) {
+	const (
+		n   = 50
+		eps = 0.5
+	)
+	g := simple.NewUndirectedGraph()
+	for i := 0; i < n; i++ {
+		for j := i + 1; j < n; j++ {
+			g.SetEdge(simple.Edge{F: simple.Node(i), T: simple.Node(j)})
+		}
+	}
+	// Add a pendant path that must be retained.
+	g.SetEdge(simple.Edge{F: simple.Node(0), T: simple.Node(n)})
+	g.SetEdge(simple.Edge{F: simple.Node(n), T: simple.Node(n + 1)})
+
+	rnd := rand.New(rand.NewSource(1))
+	dst := simple.NewWeightedUndirectedGraph(0, 0)
+	Sparsify(dst, g, eps, rnd)
+
+	if len(dst.Nodes()) != len(g.Nodes()) {
+		t.Fatalf("unexpected number of nodes: got:%d want:%d", len(dst.Nodes()), len(g.Nodes()))
+	}
+	if got, max := len(dst.Edges()), len(g.Edges()); got >= max {
+		t.Errorf("graph not sparsified: got:%d edges from %d", got, max)
+	}
+	for _, e := range [][2]int64{{0, n}, {n, n + 1}} {
+		if !dst.HasEdgeBetween(simple.Node(e[0]), simple.Node(e[1])) {
+			t.Errorf("bridge %d--%d not retained", e[0], e[1])
+		}
+	}
+
+	lg := NewLaplacianSolver(g, JacobiPreconditioner)
+	lh := NewLaplacianSolver(dst, JacobiPreconditioner)
+	x := make([]float64, len(lg.nodes))
+	y := make([]float64, len(lg.nodes))
+	for i := 0; i < 50; i++ {
+		for j := range x {
+			x[j] = rnd.NormFloat64()
+		}
+		lg.mulVecTo(y, x)
+		want := floats.Dot(x, y)
+		lh.mulVecTo(y, x)
+		got := floats.Dot(x, y)
+		if got < (1-eps)*want || (1+eps)*want < got {
+			t.Errorf("quadratic form not preserved: got:%v want:%v±%v", got, want, eps*want)
+		}
+	}
+}
@@ -0,0 +1,402 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package network
+
+import (
+	"math"
+	"sort"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/internal/ordered"
+	"gonum.org/v1/gonum/mat"
+)
+
+// RandomWalk is a random walker on a graph. At each step the walker stays at
+// its current node with probability Lazy. Otherwise it teleports to a node of
+// the graph chosen uniformly at random with probability Teleport, and if it
+// does not teleport it moves along an edge from its current node. If the graph
+// implements graph.Weighted, edges are chosen with probability proportional
+// to their weight, otherwise edges are chosen uniformly. A walker at a node
+// with no outgoing edge teleports if Teleport is positive and otherwise stays
+// at its node.
+//
+// The neighbors of nodes are cached as the walk proceeds, so the graph must
+// not be modified while a RandomWalk is in use.
+type RandomWalk struct {
+	// Lazy is the probability of staying at
+	// the current node at each step. Lazy
+	// must be in [0, 1).
+	Lazy float64
+
+	// Teleport is the probability of a
+	// uniform jump when not staying at
+	// the current node. Teleport must be
+	// in [0, 1].
+	Teleport float64
+
+	g     graph.Graph
+	nodes []graph.Node
+	index map[int64]int
+	edges map[int64]walkEdges
+
+	uniform func() float64
+}
+
+// walkEdges holds the nodes reachable from a node
+// and the cumulative weights of the edges to them.
+type walkEdges struct {
+	to  []graph.Node
+	cum []float64
+}
+
+// NewRandomWalk returns a new random walker on g with no laziness or
+// teleportation. If src is not nil it is used as the random source,
+// otherwise the global random source is used. NewRandomWalk will panic
+// if g has a negative edge weight.
+func NewRandomWalk(g graph.Graph, src rand.Source) *RandomWalk {
+	nodes := g.Nodes()
+	sort.Sort(ordered.ByID(nodes))
+	index := make(map[int64]int, len(nodes))
+	for i, n := range nodes {
+		index[n.ID()] = i
+	}
+	uniform := rand.Float64
+	if src != nil {
+		uniform = rand.New(src).Float64
+	}
+	return &RandomWalk{
+		g:       g,
+		nodes:   nodes,
+		index:   index,
+		edges:   make(map[int64]walkEdges),
+		uniform: uniform,
+	}
+}
+
+// from returns the edges from u.
+func (w *RandomWalk) from(u graph.Node) walkEdges {
+	e, ok := w.edges[u.ID()]
+	if ok {
+		return e
+	}
+	to := w.g.From(u)
+	sort.Sort(ordered.ByID(to))
+	e = walkEdges{to: to, cum: make([]float64, len(to))}
+	wg, isWeighted := w.g.(graph.Weighted)
+	var sum float64
+	for i, v := range to {
+		weight := 1.0
+		if isWeighted {
+			weight, ok = wg.Weight(u, v)
+			if !ok {
+				panic("network: unexpected invalid weight")
+			}
+			if weight < 0 {
+				panic("network: negative edge weight")
+			}
+		}
+		sum += weight
+		e.cum[i] = sum
+	}
+	if sum == 0 {
+		e = walkEdges{}
+	}
+	w.edges[u.ID()] = e
+	return e
+}
+
+func (w *RandomWalk) checkParams() {
+	if !(0 <= w.Lazy && w.Lazy < 1) {
+		panic("network: laziness out of range")
+	}
+	if !(0 <= w.Teleport && w.Teleport <= 1) {
+		panic("network: teleport probability out of range")
+	}
+}
+
+// Step returns the node reached by a single step of the walk from u.
+// Step will panic if u is not in the graph.
+func (w *RandomWalk) Step(u graph.Node) graph.Node {
+	w.checkParams()
+	if _, ok := w.index[u.ID()]; !ok {
+		panic("network: node not in graph")
+	}
+	return w.step(u)
+}
+
+func (w *RandomWalk) step(u graph.Node) graph.Node {
+	if w.Lazy > 0 && w.uniform() < w.Lazy {
+		return u
+	}
+	e := w.from(u)
+	if w.Teleport > 0 && (len(e.to) == 0 || w.uniform() < w.Teleport) {
+		return w.nodes[int(w.uniform()*float64(len(w.nodes)))%len(w.nodes)]
+	}
+	if len(e.to) == 0 {
+		return u
+	}
+	r := w.uniform() * e.cum[len(e.cum)-1]
+	i := sort.Search(len(e.cum), func(i int) bool { return e.cum[i] > r })
+	if i == len(e.cum) {
+		i--
+	}
+	return e.to[i]
+}
+
+// Walk performs a walk of the given number of steps from start, returning
+// the visited nodes beginning with start. If dst is not nil, the nodes are
+// appended to dst[:0]. Walk will panic if start is not in the graph.
+func (w *RandomWalk) Walk(dst []graph.Node, start graph.Node, steps int) []graph.Node {
+	w.checkParams()
+	if _, ok := w.index[start.ID()]; !ok {
+		panic("network: node not in graph")
+	}
+	dst = append(dst[:0], start)
+	u := start
+	for i := 0; i < steps; i++ {
+		u = w.step(u)
+		dst = append(dst, u)
+	}
+	return dst
+}
+
+// HittingTime estimates the expected number of steps for the walker to
+// first reach the node to from the node from by performing the given number
+// of walks of at most maxSteps steps. It returns the mean number of steps
+// taken by the walks that reached to, and the fraction of walks that reached
+// to within maxSteps. The estimate is biased low when walks are truncated.
+// HittingTime will panic if from or to are not in the graph or walks is not
+// positive.
+func (w *RandomWalk) HittingTime(from, to graph.Node, walks, maxSteps int) (mean, reached float64) {
+	w.checkParams()
+	if _, ok := w.index[from.ID()]; !ok {
+		panic("network: node not in graph")
+	}
+	if _, ok := w.index[to.ID()]; !ok {
+		panic("network: node not in graph")
+	}
+	if walks <= 0 {
+		panic("network: non-positive number of walks")
+	}
+	var total, hits int
+	for i := 0; i < walks; i++ {
+		u := from
+		for step := 0; step <= maxSteps; step++ {
+			if u.ID() == to.ID() {
+				total += step
+				hits++
+				break
+			}
+			u = w.step(u)
+		}
+	}
+	if hits == 0 {
+		return math.NaN(), 0
+	}
+	return float64(total) / float64(hits), float64(hits) / float64(walks)
+}
+
+// ExpectedHittingTimes returns the expected number of steps for the walker
+// to first reach the node to from each node of the graph, computed exactly by
+// solving the linear system
+//  h_to = 0,
+//  h_u = 1 + \sum_v P_uv * h_v for u != to,
+// where P is the transition matrix of the walk. The expected hitting time is
+// +Inf from nodes with a positive probability of never reaching to. The
+// returned map is keyed on the graph node IDs. ExpectedHittingTimes uses dense
+// matrices and will panic if to is not in the graph.
+func (w *RandomWalk) ExpectedHittingTimes(to graph.Node) map[int64]float64 {
+	w.checkParams()
+	t, ok := w.index[to.ID()]
+	if !ok {
+		panic("network: node not in graph")
+	}
+	p := w.transitions()
+	n := len(w.nodes)
+
+	// Mark nodes that may never reach to: nodes from
+	// which to is unreachable, and nodes with a positive
+	// probability of moving to such a node.
+	reach := make([]bool, n)
+	reach[t] = true
+	for changed := true; changed; {
+		changed = false
+		for u := 0; u < n; u++ {
+			if reach[u] {
+				continue
+			}
+			for v := 0; v < n; v++ {
+				if reach[v] && p.At(u, v) > 0 {
+					reach[u] = true
+					changed = true
+					break
+				}
+			}
+		}
+	}
+	sure := make([]bool, n)
+	copy(sure, reach)
+	for changed := true; changed; {
+		changed = false
+		for u := 0; u < n; u++ {
+			if !sure[u] || u == t {
+				continue
+			}
+			for v := 0; v < n; v++ {
+				if !sure[v] && p.At(u, v) > 0 {
+					sure[u] = false
+					changed = true
+					break
+				}
+			}
+		}
+	}
+
+	var idx []int
+	for u, ok := range sure {
+		if ok && u != t {
+			idx = append(idx, u)
+		}
+	}
+	times := make(map[int64]float64, n)
+	for u, ok := range sure {
+		if !ok {
+			times[w.nodes[u].ID()] = math.Inf(1)
+		}
+	}
+	times[to.ID()] = 0
+	if len(idx) == 0 {
+		return times
+	}
+	a := mat.NewDense(len(idx), len(idx), nil)
+	b := mat.NewVecDense(len(idx), nil)
+	for i, u := range idx {
+		for j, v := range idx {
+			a.Set(i, j, -p.At(u, v))
+		}
+		a.Set(i, i, a.At(i, i)+1)
+		b.SetVec(i, 1)
+	}
+	var h mat.VecDense
+	err := h.SolveVec(a, b)
+	if err != nil {
+		if _, ok := err.(mat.Condition); !ok {
+			panic(err)
+		}
+	}
+	for i, u := range idx {
+		times[w.nodes[u].ID()] = h.AtVec(i)
+	}
+	return times
+}
+
+// transitions returns the dense transition matrix of the walk.
+func (w *RandomWalk) transitions() *mat.Dense {
+	n := len(w.nodes)
+	p := mat.NewDense(n, n, nil)
+	move := 1 - w.Lazy
+	for i, u := range w.nodes {
+		p.Set(i, i, w.Lazy)
+		e := w.from(u)
+		teleport := w.Teleport
+		if len(e.to) == 0 {
+			if teleport == 0 {
+				p.Set(i, i, 1)
+				continue
+			}
+			teleport = 1
+		}
+		for j := range w.nodes {
+			p.Set(i, j, p.At(i, j)+move*teleport/float64(n))
+		}
+		prev := 0.0
+		for k, v := range e.to {
+			j := w.index[v.ID()]
+			f := (e.cum[k] - prev) / e.cum[len(e.cum)-1]
+			prev = e.cum[k]
+			p.Set(i, j, p.At(i, j)+move*(1-teleport)*f)
+		}
+	}
+	return p
+}
+
+// Stationary returns the stationary distribution of the walk computed by
+// power iteration from the uniform distribution, terminating when the 2-norm
+// of the vector difference between iterations is below tol or after iters
+// iterations. The returned map is keyed on the graph node IDs and its values
+// sum to one, and ok reports whether the iteration converged.
+//
+// The iteration converges to the unique stationary distribution when the walk
+// is irreducible and aperiodic, which is guaranteed when Teleport is positive.
+// A positive Lazy makes the walk aperiodic. The walk on a connected bipartite
+// graph without laziness or teleportation is periodic and does not converge.
+func (w *RandomWalk) Stationary(tol float64, iters int) (dist map[int64]float64, ok bool) {
+	w.checkParams()
+	n := len(w.nodes)
+	if n == 0 {
+		return map[int64]float64{}, true
+	}
+
+	// Build the sparse transpose of the transition
+	// matrix for moves along edges, handling laziness,
+	// teleportation and dangling nodes separately.
+	m := make(rowCompressedMatrix, n)
+	var dangling []int
+	for j, u := range w.nodes {
+		e := w.from(u)
+		if len(e.to) == 0 {
+			dangling = append(dangling, j)
+			continue
+		}
+		prev := 0.0
+		for k, v := range e.to {
+			f := (e.cum[k] - prev) / e.cum[len(e.cum)-1]
+			prev = e.cum[k]
+			m.addTo(w.index[v.ID()], j, (1-w.Lazy)*(1-w.Teleport)*f)
+		}
+	}
+
+	last := make([]float64, n)
+	lastV := mat.NewVecDense(n, last)
+	vec := make([]float64, n)
+	for i := range vec {
+		vec[i] = 1 / float64(n)
+	}
+	v := mat.NewVecDense(n, vec)
+	for ; iters > 0; iters-- {
+		lastV, v = v, lastV
+		last, vec = vec, last
+
+		m.mulVecUnitary(v, lastV)
+		var jump float64
+		for _, j := range dangling {
+			if w.Teleport == 0 {
+				vec[j] += (1 - w.Lazy) * last[j]
+			} else {
+				jump += (1 - w.Lazy) * last[j]
+			}
+		}
+		for i, p := range last {
+			if len(w.edges[w.nodes[i].ID()].to) != 0 {
+				jump += (1 - w.Lazy) * w.Teleport * p
+			}
+		}
+		for i, p := range last {
+			vec[i] += w.Lazy*p + jump/float64(n)
+		}
+		if normDiff(vec, last) < tol {
+			ok = true
+			break
+		}
+	}
+
+	dist = make(map[int64]float64, n)
+	for i, p := range vec {
+		dist[w.nodes[i].ID()] = p
+	}
+	return dist, ok
+}
@@ -0,0 +1,234 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package network
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/graphs/gen"
+	"gonum.org/v1/gonum/graph/simple"
+)
+
+// pathGraph returns an undirected path graph on n nodes.
+func pathGraph(n int) *simple.UndirectedGraph {
+	g := simple.NewUndirectedGraph()
+	for i := 0; i < n-1; i++ {
+		g.SetEdge(simple.Edge{F: simple.Node(i), T: simple.Node(i + 1)})
+	}
+	return g
+}
+
+func TestRandomWalkWalk(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	g := simple.NewWeightedUndirectedGraph(0, 0)
+	gen.Gnp(weightedUndirected{g, rnd}, 30, 0.2, rnd)
+
+	w := NewRandomWalk(g, rand.NewSource(2))
+	walk := w.Walk(nil, simple.Node(0), 1000)
+	if len(walk) != 1001 {
+		t.Fatalf("unexpected walk length: got:%d want:1001", len(walk))
+	}
+	if walk[0].ID() != 0 {
+		t.Errorf("walk does not begin at start: %d", walk[0].ID())
+	}
+	for i := 1; i < len(walk); i++ {
+		if len(g.From(walk[i-1])) == 0 {
+			if walk[i].ID() != walk[i-1].ID() {
+				t.Errorf("walker left isolated node %d", walk[i-1].ID())
+			}
+			continue
+		}
+		if !g.HasEdgeBetween(walk[i-1], walk[i]) {
+			t.Errorf("walk step %d is not along an edge: %d--%d", i, walk[i-1].ID(), walk[i].ID())
+		}
+	}
+
+	// The same source gives the same walk.
+	again := NewRandomWalk(g, rand.NewSource(2)).Walk(nil, simple.Node(0), 1000)
+	for i := range walk {
+		if walk[i].ID() != again[i].ID() {
+			t.Errorf("walk not reproducible at step %d", i)
+			break
+		}
+	}
+
+	// Weighted steps are made in proportion to the edge weights.
+	star := simple.NewWeightedUndirectedGraph(0, 0)
+	for i, weight := range []float64{1, 2, 7} {
+		star.SetWeightedEdge(simple.WeightedEdge{F: simple.Node(0), T: simple.Node(i + 1), W: weight})
+	}
+	w = NewRandomWalk(star, rand.NewSource(1))
+	count := make(map[int64]int)
+	const n = 10000
+	for i := 0; i < n; i++ {
+		count[w.Step(simple.Node(0)).ID()]++
+	}
+	for id, want := range map[int64]float64{1: 0.1, 2: 0.2, 3: 0.7} {
+		if got := float64(count[id]) / n; math.Abs(got-want) > 0.02 {
+			t.Errorf("unexpected step frequency to %d: got:%v want:%v", id, got, want)
+		}
+	}
+
+	w.Lazy = 1
+	if !panics(func() { w.Step(simple.Node(0)) }) {
+		t.Error("expected panic for laziness of one")
+	}
+	w.Lazy = 0
+	if !panics(func() { w.Step(simple.Node(10)) }) {
+		t.Error("expected panic for node not in graph")
+	}
+}
+
+// weightedUndirected adds random weights to edges added by a generator.
+type weightedUndirected struct {
+	*simple.WeightedUndirectedGraph
+	rnd *rand.Rand
+}
+
+func (g weightedUndirected) NewEdge(from, to graph.Node) graph.Edge {
+	return simple.Edge{F: from, T: to}
+}
+
+func (g weightedUndirected) SetEdge(e graph.Edge) {
+	g.SetWeightedEdge(simple.WeightedEdge{F: e.From(), T: e.To(), W: 0.5 + g.rnd.Float64()})
+}
+
+func TestRandomWalkStationary(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+
+	// The stationary distribution of a walk on a connected
+	// undirected graph is proportional to the weighted degree.
+	for trial := 0; trial < 5; trial++ {
+		g := simple.NewWeightedUndirectedGraph(0, 0)
+		for {
+			gen.Gnp(weightedUndirected{g, rnd}, 20, 0.3, rnd)
+			if len(g.Nodes()) == 20 && isConnected(g) {
+				break
+			}
+			g = simple.NewWeightedUndirectedGraph(0, 0)
+		}
+		w := NewRandomWalk(g, nil)
+		w.Lazy = 0.5
+		dist, ok := w.Stationary(1e-12, 10000)
+		if !ok {
+			t.Fatalf("stationary distribution did not converge for trial %d", trial)
+		}
+		deg := make(map[int64]float64)
+		var total float64
+		for _, e := range g.Edges() {
+			weight := e.(graph.WeightedEdge).Weight()
+			deg[e.From().ID()] += weight
+			deg[e.To().ID()] += weight
+			total += 2 * weight
+		}
+		for id, p := range dist {
+			if want := deg[id] / total; math.Abs(p-want) > 1e-9 {
+				t.Errorf("unexpected stationary probability for node %d in trial %d: got:%v want:%v", id, trial, p, want)
+			}
+		}
+	}
+
+	// With teleportation the stationary distribution is PageRank.
+	for trial := 0; trial < 5; trial++ {
+		g := simple.NewDirectedGraph()
+		gen.Gnp(g, 30, 0.1, rnd)
+		w := NewRandomWalk(g, nil)
+		w.Teleport = 0.15
+		dist, ok := w.Stationary(1e-14, 10000)
+		if !ok {
+			t.Fatalf("stationary distribution did not converge for trial %d", trial)
+		}
+		rank := PageRank(g, 0.85, 1e-14)
+		for id, p := range dist {
+			if math.Abs(p-rank[id]) > 1e-9 {
+				t.Errorf("unexpected stationary probability for node %d in trial %d: got:%v want:%v", id, trial, p, rank[id])
+			}
+		}
+	}
+
+	// The walk on a bipartite graph is periodic.
+	w := NewRandomWalk(pathGraph(3), nil)
+	if _, ok := w.Stationary(1e-10, 1000); ok {
+		t.Error("unexpected convergence for periodic walk")
+	}
+}
+
+func isConnected(g graph.Undirected) bool {
+	nodes := g.Nodes()
+	seen := map[int64]bool{nodes[0].ID(): true}
+	stack := []graph.Node{nodes[0]}
+	for len(stack) != 0 {
+		u := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		for _, v := range g.From(u) {
+			if !seen[v.ID()] {
+				seen[v.ID()] = true
+				stack = append(stack, v)
+			}
+		}
+	}
+	return len(seen) == len(nodes)
+}
+
+func TestRandomWalkHittingTime(t *testing.T) {
+	// The expected hitting time from node i to the
+	// end n of a path is n² - i².
+	const n = 6
+	g := pathGraph(n + 1)
+	w := NewRandomWalk(g, rand.NewSource(1))
+	times := w.ExpectedHittingTimes(simple.Node(n))
+	for i := 0; i <= n; i++ {
+		if want := float64(n*n - i*i); math.Abs(times[int64(i)]-want) > 1e-9 {
+			t.Errorf("unexpected expected hitting time from %d: got:%v want:%v", i, times[int64(i)], want)
+		}
+	}
+	mean, reached := w.HittingTime(simple.Node(0), simple.Node(n), 5000, 10000)
+	if reached != 1 {
+		t.Errorf("unexpected fraction of walks reaching target: got:%v want:1", reached)
+	}
+	if math.Abs(mean-n*n) > 2 {
+		t.Errorf("unexpected estimated hitting time: got:%v want:%v", mean, n*n)
+	}
+
+	// A lazy walk takes proportionally longer.
+	w.Lazy = 0.5
+	times = w.ExpectedHittingTimes(simple.Node(n))
+	if want := float64(2 * n * n); math.Abs(times[0]-want) > 1e-9 {
+		t.Errorf("unexpected lazy expected hitting time: got:%v want:%v", times[0], want)
+	}
+
+	// Nodes that may fall into a sink never surely reach
+	// the target, and nodes that cannot reach it never do.
+	d := simple.NewDirectedGraph()
+	for _, e := range [][2]int64{{0, 1}, {1, 2}, {1, 3}, {4, 0}, {2, 5}} {
+		d.SetEdge(simple.Edge{F: simple.Node(e[0]), T: simple.Node(e[1])})
+	}
+	times = NewRandomWalk(d, nil).ExpectedHittingTimes(simple.Node(2))
+	want := map[int64]float64{0: math.Inf(1), 1: math.Inf(1), 2: 0, 3: math.Inf(1), 4: math.Inf(1), 5: math.Inf(1)}
+	for id, tm := range want {
+		if times[id] != tm {
+			t.Errorf("unexpected expected hitting time from %d: got:%v want:%v", id, times[id], tm)
+		}
+	}
+	times = NewRandomWalk(d, nil).ExpectedHittingTimes(simple.Node(1))
+	want = map[int64]float64{0: 1, 1: 0, 2: math.Inf(1), 3: math.Inf(1), 4: 2, 5: math.Inf(1)}
+	for id, tm := range want {
+		if times[id] != tm {
+			t.Errorf("unexpected expected hitting time to 1 from %d: got:%v want:%v", id, times[id], tm)
+		}
+	}
+}
+
+func panics(fn func()) (ok bool) {
+	defer func() {
+		ok = recover() != nil
+	}()
+	fn()
+	return
+}
@@ -0,0 +1,12 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package partition provides balanced graph partitioning.
+//
+// The functions in this package split the nodes of an undirected graph into
+// k parts of nearly equal size while minimizing the total weight of the edges
+// cut by the partition. Balanced partitioning is NP-hard, so the package
+// provides the Kernighan–Lin refinement heuristic and a multilevel scheme
+// that applies it to a hierarchy of coarsened graphs.
+package partition // import "gonum.org/v1/gonum/graph/partition"
@@ -0,0 +1,535 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package partition
+
+import (
+	"math"
+	"sort"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/coarsen"
+	"gonum.org/v1/gonum/graph/internal/ordered"
+)
+
+// imbalance is the fraction by which the total node
+// weight of a part may exceed the mean part weight
+// during refinement of a weighted coarse graph.
+const imbalance = 0.03
+
+// Stats holds statistics describing a partition of a graph.
+type Stats struct {
+	// Cut is the total weight of the edges
+	// joining nodes in different parts.
+	Cut float64
+
+	// Sizes holds the number of nodes
+	// in each part.
+	Sizes []int
+
+	// Imbalance is the ratio of the size of
+	// the largest part to the mean part size.
+	// A perfectly balanced partition has an
+	// imbalance of one.
+	Imbalance float64
+}
+
+// Evaluate returns the statistics of the partition of g into k parts
+// given by parts, which holds the part of each node of g numbered from
+// zero. If g implements graph.Weighted, edge weights are used, otherwise
+// each edge has unit weight. Self edges are ignored. Evaluate will panic
+// if a node of g has no part or its part is not in [0, k).
+func Evaluate(g graph.Undirected, parts map[int64]int, k int) Stats {
+	if k < 1 {
+		panic("partition: bad number of parts")
+	}
+	weight := weightFunc(g)
+	s := Stats{Sizes: make([]int, k)}
+	nodes := g.Nodes()
+	for _, u := range nodes {
+		p, ok := parts[u.ID()]
+		if !ok || p < 0 || k <= p {
+			panic("partition: invalid part for node")
+		}
+		s.Sizes[p]++
+		for _, v := range g.From(u) {
+			if u.ID() < v.ID() && parts[v.ID()] != p {
+				s.Cut += weight(u, v)
+			}
+		}
+	}
+	var max int
+	for _, n := range s.Sizes {
+		if n > max {
+			max = n
+		}
+	}
+	if len(nodes) != 0 {
+		s.Imbalance = float64(max) / (float64(len(nodes)) / float64(k))
+	}
+	return s
+}
+
+// KernighanLin returns a partition of the nodes of g into k balanced parts,
+// numbered from zero, found by refining a random balanced partition with the
+// Kernighan–Lin heuristic, and the statistics of the partition. Part sizes
+// differ by at most one. If g implements graph.Weighted, edge weights are
+// used and must be non-negative, otherwise each edge has unit weight. Self
+// edges are ignored. If src is not nil it is used as the random source,
+// otherwise rand.Perm is used. KernighanLin will panic if k is less than 1
+// or greater than the number of nodes of g.
+//
+// Parts are refined pairwise. For each pair of parts, a Kernighan–Lin pass
+// tentatively swaps the pair of nodes that most reduces the cut, locks them,
+// and repeats until no swaps remain, then keeps the prefix of swaps with the
+// greatest total reduction. Passes are repeated until no pass reduces the cut.
+//
+// See Kernighan, B. W. and Lin, S. "An efficient heuristic procedure for
+// partitioning graphs." Bell System Technical Journal 49(2):291–307 (1970).
+func KernighanLin(g graph.Undirected, k int, src *rand.Rand) (parts map[int64]int, stats Stats) {
+	l := newLocal(g, nil)
+	checkParts(len(l.nodes), k)
+	perm := rand.Perm
+	if src != nil {
+		perm = src.Perm
+	}
+	part := make([]int, len(l.nodes))
+	for i, j := range perm(len(l.nodes)) {
+		part[j] = i % k
+	}
+	l.refine(part, k)
+	parts = l.parts(part)
+	return parts, Evaluate(g, parts, k)
+}
+
+// Multilevel returns a partition of the nodes of g into k balanced parts,
+// numbered from zero, and the statistics of the partition. The graph is
+// coarsened by repeated heavy edge matching until it is small, the coarsest
+// graph is partitioned by growing parts in breadth-first order from random
+// seeds and refining them with the Kernighan–Lin heuristic, and the
+// partition is then projected back through each level of the hierarchy and
+// refined again. Parts of coarse graphs may differ in total node weight by up
+// to the weight of their heaviest node, and the final partition is rebalanced
+// before its refinement so that no part is larger than the mean part size by
+// more than three percent, or by more than rounding up to a whole node.
+//
+// If g implements graph.Weighted, edge weights are used and must be
+// non-negative, otherwise each edge has unit weight. Self edges are ignored.
+// If src is not nil it is used as the random source, otherwise the global
+// random source is used. Multilevel will panic if k is less than 1 or greater
+// than the number of nodes of g.
+//
+// See Karypis, G. and Kumar, V. "A fast and high quality multilevel scheme for
+// partitioning irregular graphs." SIAM J. Sci. Comput. 20(1):359–392 (1998).
+func Multilevel(g graph.Undirected, k int, src *rand.Rand) (parts map[int64]int, stats Stats) {
+	checkParts(len(g.Nodes()), k)
+	levels := coarsen.Hierarchy(g, 20*k, src)
+
+	// Partition the coarsest graph.
+	var coarsest *local
+	if len(levels) == 0 {
+		coarsest = newLocal(g, nil)
+	} else {
+		coarsest = newLocal(levels[len(levels)-1].Coarse, levels[len(levels)-1])
+	}
+	var best []int
+	bestCut := math.Inf(1)
+	for trial := 0; trial < 4; trial++ {
+		part := coarsest.grow(k, src)
+		coarsest.refine(part, k)
+		if c := coarsest.cut(part); c < bestCut {
+			best, bestCut = part, c
+		}
+	}
+	if len(levels) == 0 {
+		coarsest.balance(best, k)
+		coarsest.refine(best, k)
+	}
+	parts = coarsest.parts(best)
+
+	// Project and refine through the levels.
+	for i := len(levels) - 1; i >= 0; i-- {
+		parts = levels[i].ProlongLabels(nil, parts)
+		var l *local
+		if i == 0 {
+			l = newLocal(g, nil)
+		} else {
+			l = newLocal(levels[i-1].Coarse, levels[i-1])
+		}
+		part := make([]int, len(l.nodes))
+		for j, n := range l.nodes {
+			part[j] = parts[n.ID()]
+		}
+		if i == 0 {
+			l.balance(part, k)
+		}
+		l.refine(part, k)
+		parts = l.parts(part)
+	}
+	return parts, Evaluate(g, parts, k)
+}
+
+func checkParts(n, k int) {
+	if k < 1 || n < k {
+		panic("partition: bad number of parts")
+	}
+}
+
+// local is an index-based weighted representation of a graph.
+type local struct {
+	nodes   []graph.Node
+	adj     []map[int]float64
+	nodeW   []float64
+	totalW  float64
+	maxW    float64
+	indexOf map[int64]int
+}
+
+// newLocal returns the local representation of g. If level is not nil,
+// g is the coarse graph of level and node weights are taken from it,
+// otherwise nodes have unit weight.
+func newLocal(g graph.Undirected, level *coarsen.Level) *local {
+	nodes := g.Nodes()
+	sort.Sort(ordered.ByID(nodes))
+	l := &local{
+		nodes:   nodes,
+		adj:     make([]map[int]float64, len(nodes)),
+		nodeW:   make([]float64, len(nodes)),
+		indexOf: make(map[int64]int, len(nodes)),
+	}
+	for i, n := range nodes {
+		l.indexOf[n.ID()] = i
+		l.nodeW[i] = 1
+		if level != nil {
+			l.nodeW[i] = level.NodeWeight(n)
+		}
+		l.totalW += l.nodeW[i]
+		l.maxW = math.Max(l.maxW, l.nodeW[i])
+	}
+	weight := weightFunc(g)
+	for i, u := range nodes {
+		l.adj[i] = make(map[int]float64)
+		for _, v := range g.From(u) {
+			if v.ID() != u.ID() {
+				l.adj[i][l.indexOf[v.ID()]] = weight(u, v)
+			}
+		}
+	}
+	return l
+}
+
+// parts returns the part labels keyed by node ID.
+func (l *local) parts(part []int) map[int64]int {
+	parts := make(map[int64]int, len(part))
+	for i, p := range part {
+		parts[l.nodes[i].ID()] = p
+	}
+	return parts
+}
+
+// cut returns the total weight of edges cut by part.
+func (l *local) cut(part []int) float64 {
+	var c float64
+	for u, nbrs := range l.adj {
+		for v, w := range nbrs {
+			if u < v && part[u] != part[v] {
+				c += w
+			}
+		}
+	}
+	return c
+}
+
+// grow returns a partition of l into k parts grown in breadth-first order
+// from random seed nodes, each part taking nodes until it reaches the mean
+// part weight.
+func (l *local) grow(k int, src *rand.Rand) []int {
+	perm := rand.Perm
+	if src != nil {
+		perm = src.Perm
+	}
+	order := perm(len(l.nodes))
+	part := make([]int, len(l.nodes))
+	for i := range part {
+		part[i] = -1
+	}
+	target := l.totalW / float64(k)
+	next := 0
+	for p := 0; p < k; p++ {
+		var w float64
+		var queue []int
+		for w < target || p == k-1 {
+			if len(queue) == 0 {
+				// Seed from the next unassigned node.
+				for next < len(order) && part[order[next]] >= 0 {
+					next++
+				}
+				if next == len(order) {
+					break
+				}
+				queue = append(queue, order[next])
+				part[order[next]] = p
+				w += l.nodeW[order[next]]
+				continue
+			}
+			u := queue[0]
+			queue = queue[1:]
+			nbrs := make([]int, 0, len(l.adj[u]))
+			for v := range l.adj[u] {
+				nbrs = append(nbrs, v)
+			}
+			sort.Ints(nbrs)
+			for _, v := range nbrs {
+				if part[v] >= 0 || (w >= target && p != k-1) {
+					continue
+				}
+				part[v] = p
+				w += l.nodeW[v]
+				queue = append(queue, v)
+			}
+		}
+	}
+	return part
+}
+
+// limit returns the maximum total node weight of a part when l is
+// partitioned into k balanced parts.
+func (l *local) limit(k int) float64 {
+	mean := l.totalW / float64(k)
+	return math.Max((1+imbalance)*mean, math.Ceil(mean))
+}
+
+// balance moves nodes out of parts of the partition part of l into k parts
+// that are heavier than the balance limit, choosing at each step the move
+// that least increases the cut, until no part is too heavy or no move is
+// possible.
+func (l *local) balance(part []int, k int) {
+	limit := l.limit(k)
+	weights := make([]float64, k)
+	for i, p := range part {
+		weights[p] += l.nodeW[i]
+	}
+	conn := make([]float64, k)
+	for {
+		h := 0
+		for p, w := range weights {
+			if w > weights[h] {
+				h = p
+			}
+		}
+		if weights[h] <= limit {
+			return
+		}
+		u, to := -1, -1
+		bestGain := math.Inf(-1)
+		for i, p := range part {
+			if p != h {
+				continue
+			}
+			for p := range conn {
+				conn[p] = 0
+			}
+			for v, w := range l.adj[i] {
+				conn[part[v]] += w
+			}
+			for p, w := range weights {
+				if p == h || w+l.nodeW[i] > limit {
+					continue
+				}
+				if g := conn[p] - conn[h]; g > bestGain {
+					u, to, bestGain = i, p, g
+				}
+			}
+		}
+		if u < 0 {
+			return
+		}
+		part[u] = to
+		weights[h] -= l.nodeW[u]
+		weights[to] += l.nodeW[u]
+	}
+}
+
+// refine improves the partition part of l into k parts by repeated
+// pairwise Kernighan–Lin passes until no pass reduces the cut. Parts
+// are allowed to exceed the balance limit by the weight of the
+// heaviest node of l.
+func (l *local) refine(part []int, k int) {
+	limit := l.limit(k) + l.maxW
+	weights := make([]float64, k)
+	for i, p := range part {
+		weights[p] += l.nodeW[i]
+	}
+	for improved := true; improved; {
+		improved = false
+		for a := 0; a < k; a++ {
+			for b := a + 1; b < k; b++ {
+				if l.pass(part, weights, a, b, limit) {
+					improved = true
+				}
+			}
+		}
+	}
+}
+
+// pass performs a single Kernighan–Lin pass between parts a and b,
+// returning whether the cut was reduced.
+func (l *local) pass(part []int, weights []float64, a, b int, limit float64) bool {
+	// d holds the reduction in cut from moving each
+	// node of a or b to the other part, the external
+	// minus internal edge weight of the node.
+	d := make(map[int]float64)
+	var inA, inB []int
+	for i, p := range part {
+		if p != a && p != b {
+			continue
+		}
+		other := a
+		if p == a {
+			other = b
+			inA = append(inA, i)
+		} else {
+			inB = append(inB, i)
+		}
+		var gain float64
+		for v, w := range l.adj[i] {
+			switch part[v] {
+			case other:
+				gain += w
+			case p:
+				gain -= w
+			}
+		}
+		d[i] = gain
+	}
+	if len(inA) == 0 || len(inB) == 0 {
+		return false
+	}
+
+	type swap struct {
+		u, v int
+	}
+	var (
+		swaps  []swap
+		gains  []float64
+		locked = make(map[int]bool)
+		side   = make(map[int]int, len(d))
+		wa, wb = weights[a], weights[b]
+	)
+	for i := range d {
+		side[i] = part[i]
+	}
+	unlocked := func(dst, nodes []int) []int {
+		dst = dst[:0]
+		for _, i := range nodes {
+			if !locked[i] {
+				dst = append(dst, i)
+			}
+		}
+		sort.Slice(dst, func(i, j int) bool {
+			if d[dst[i]] != d[dst[j]] {
+				return d[dst[i]] > d[dst[j]]
+			}
+			return dst[i] < dst[j]
+		})
+		return dst
+	}
+	var candA, candB []int
+	for {
+		candA = unlocked(candA, inA)
+		candB = unlocked(candB, inB)
+		if len(candA) == 0 || len(candB) == 0 {
+			break
+		}
+
+		// Find the best balanced pair. Since edge weights
+		// are non-negative, the gain of a pair is at most
+		// the sum of the node gains.
+		best := swap{u: -1}
+		bestGain := math.Inf(-1)
+		for _, u := range candA {
+			if d[u]+d[candB[0]] <= bestGain {
+				break
+			}
+			for _, v := range candB {
+				if d[u]+d[v] <= bestGain {
+					break
+				}
+				nwa := wa - l.nodeW[u] + l.nodeW[v]
+				nwb := wb - l.nodeW[v] + l.nodeW[u]
+				if nwa > math.Max(limit, wa) || nwb > math.Max(limit, wb) {
+					continue
+				}
+				if g := d[u] + d[v] - 2*l.adj[u][v]; g > bestGain {
+					best, bestGain = swap{u: u, v: v}, g
+				}
+			}
+		}
+		if best.u < 0 {
+			break
+		}
+
+		// Tentatively swap the pair and update the
+		// gains of their unlocked neighbors.
+		u, v := best.u, best.v
+		locked[u], locked[v] = true, true
+		side[u], side[v] = b, a
+		wa += l.nodeW[v] - l.nodeW[u]
+		wb += l.nodeW[u] - l.nodeW[v]
+		for _, moved := range []int{u, v} {
+			from, to := a, b
+			if moved == v {
+				from, to = b, a
+			}
+			for x, w := range l.adj[moved] {
+				s, ok := side[x]
+				if !ok || locked[x] {
+					continue
+				}
+				switch s {
+				case from:
+					d[x] += 2 * w
+				case to:
+					d[x] -= 2 * w
+				}
+			}
+		}
+		swaps = append(swaps, swap{u: u, v: v})
+		gains = append(gains, bestGain)
+	}
+
+	// Keep the prefix of swaps with the greatest total gain.
+	bestLen := 0
+	var sum, bestSum float64
+	for i, g := range gains {
+		sum += g
+		if sum > bestSum+1e-12 {
+			bestLen, bestSum = i+1, sum
+		}
+	}
+	for _, s := range swaps[:bestLen] {
+		part[s.u], part[s.v] = b, a
+		weights[a] += l.nodeW[s.v] - l.nodeW[s.u]
+		weights[b] += l.nodeW[s.u] - l.nodeW[s.v]
+	}
+	return bestLen != 0
+}
+
+// weightFunc returns a function returning the weight of the edge
+// between u and v in g, or unit weight if g is not weighted.
+func weightFunc(g graph.Graph) func(u, v graph.Node) float64 {
+	wg, ok := g.(graph.Weighted)
+	if !ok {
+		return func(_, _ graph.Node) float64 { return 1 }
+	}
+	return func(u, v graph.Node) float64 {
+		w, ok := wg.Weight(u, v)
+		if !ok {
+			panic("partition: unexpected invalid weight")
+		}
+		return w
+	}
+}
@@ -0,0 +1,162 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package partition
+
+import (
+	"reflect"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/graphs/gen"
+	"gonum.org/v1/gonum/graph/simple"
+)
+
+// cliques returns a graph of k cliques of n nodes each, with
+// nodes numbered consecutively within cliques, joined in a ring
+// by single edges of weight w.
+func cliques(k, n int, w float64) *simple.WeightedUndirectedGraph {
+	g := simple.NewWeightedUndirectedGraph(0, 0)
+	for c := 0; c < k; c++ {
+		for i := 0; i < n; i++ {
+			for j := i + 1; j < n; j++ {
+				g.SetWeightedEdge(simple.WeightedEdge{F: simple.Node(c*n + i), T: simple.Node(c*n + j), W: 1})
+			}
+		}
+	}
+	if k > 1 {
+		for c := 0; c < k; c++ {
+			g.SetWeightedEdge(simple.WeightedEdge{F: simple.Node(c * n), T: simple.Node(((c+1)%k)*n + n - 1), W: w})
+		}
+	}
+	return g
+}
+
+func TestEvaluate(t *testing.T) {
+	g := simple.NewUndirectedGraph()
+	for _, e := range [][2]int64{{0, 1}, {1, 2}, {2, 3}, {3, 0}, {0, 4}} {
+		g.SetEdge(simple.Edge{F: simple.Node(e[0]), T: simple.Node(e[1])})
+	}
+	got := Evaluate(g, map[int64]int{0: 0, 1: 0, 2: 1, 3: 1, 4: 2}, 3)
+	want := Stats{Cut: 3, Sizes: []int{2, 2, 1}, Imbalance: 2 / (5.0 / 3)}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected stats: got:%+v want:%+v", got, want)
+	}
+
+	if !panics(func() { Evaluate(g, map[int64]int{0: 0, 1: 0, 2: 1, 3: 1}, 3) }) {
+		t.Error("expected panic for missing node")
+	}
+	if !panics(func() { Evaluate(g, map[int64]int{0: 0, 1: 0, 2: 1, 3: 1, 4: 3}, 3) }) {
+		t.Error("expected panic for part out of range")
+	}
+}
+
+var partitionTests = []struct {
+	name    string
+	k, n    int
+	weights float64
+}{
+	{name: "two", k: 2, n: 6, weights: 1},
+	{name: "three", k: 3, n: 10, weights: 2},
+	{name: "four", k: 4, n: 30, weights: 0.5},
+}
+
+func TestKernighanLin(t *testing.T) {
+	for _, test := range partitionTests {
+		g := cliques(test.k, test.n, test.weights)
+		for seed := uint64(0); seed < 5; seed++ {
+			parts, stats := KernighanLin(g, test.k, rand.New(rand.NewSource(seed)))
+			checkPartition(t, test.name, g, parts, stats, test.k)
+			for _, n := range stats.Sizes {
+				if n != test.n {
+					t.Errorf("unexpected part sizes for %s with seed %d: got:%v want all %d", test.name, seed, stats.Sizes, test.n)
+					break
+				}
+			}
+			if want := float64(test.k) * test.weights; stats.Cut != want {
+				t.Errorf("unexpected cut for %s with seed %d: got:%v want:%v", test.name, seed, stats.Cut, want)
+			}
+		}
+	}
+
+	if !panics(func() { KernighanLin(cliques(1, 3, 1), 4, nil) }) {
+		t.Error("expected panic for too many parts")
+	}
+	if !panics(func() { KernighanLin(cliques(1, 3, 1), 0, nil) }) {
+		t.Error("expected panic for no parts")
+	}
+}
+
+func TestMultilevel(t *testing.T) {
+	for _, test := range partitionTests {
+		g := cliques(test.k, test.n, test.weights)
+		for seed := uint64(0); seed < 5; seed++ {
+			parts, stats := Multilevel(g, test.k, rand.New(rand.NewSource(seed)))
+			checkPartition(t, test.name, g, parts, stats, test.k)
+			if stats.Imbalance > 1+imbalance+1e-12 {
+				t.Errorf("unexpected imbalance for %s with seed %d: got:%v", test.name, seed, stats.Imbalance)
+			}
+		}
+	}
+
+	// A partition of a sparse stochastic block model should
+	// have a cut close to the cut of the planted blocks.
+	const blocks, size = 4, 100
+	rnd := rand.New(rand.NewSource(1))
+	sizes := make([]int, blocks)
+	p := make([][]float64, blocks)
+	for i := range p {
+		sizes[i] = size
+		p[i] = make([]float64, blocks)
+		for j := range p[i] {
+			p[i][j] = 0.002
+		}
+		p[i][i] = 0.1
+	}
+	g := simple.NewUndirectedGraph()
+	err := gen.StochasticBlockModel(g, sizes, p, rnd)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	planted := make(map[int64]int)
+	for _, n := range g.Nodes() {
+		planted[n.ID()] = int(n.ID()) / size
+	}
+	want := Evaluate(g, planted, blocks).Cut
+	parts, stats := Multilevel(g, blocks, rnd)
+	checkPartition(t, "block model", g, parts, stats, blocks)
+	if stats.Cut > 1.2*want {
+		t.Errorf("unexpected cut for block model: got:%v planted:%v", stats.Cut, want)
+	}
+	if stats.Imbalance > 1+imbalance {
+		t.Errorf("unexpected imbalance for block model: got:%v", stats.Imbalance)
+	}
+}
+
+// checkPartition checks that parts assigns every node of g to a part
+// in [0, k) and that stats agrees with Evaluate.
+func checkPartition(t *testing.T, name string, g graph.Undirected, parts map[int64]int, stats Stats, k int) {
+	nodes := g.Nodes()
+	if len(parts) != len(nodes) {
+		t.Errorf("unexpected number of assigned nodes for %s: got:%d want:%d", name, len(parts), len(nodes))
+	}
+	for _, n := range nodes {
+		if p, ok := parts[n.ID()]; !ok || p < 0 || k <= p {
+			t.Errorf("invalid part for node %d in %s: %d", n.ID(), name, p)
+		}
+	}
+	if want := Evaluate(g, parts, k); !reflect.DeepEqual(stats, want) {
+		t.Errorf("unexpected stats for %s: got:%+v want:%+v", name, stats, want)
+	}
+}
+
+func panics(fn func()) (ok bool) {
+	defer func() {
+		ok = recover() != nil
+	}()
+	fn()
+	return
+}
@@ -207,3 +207,22 @@ func (d DominatorTree) DominatorOf(n graph.Node) graph.Node {
 func (d DominatorTree) DominatedBy(n graph.Node) []graph.Node {
 	return d.dominatedBy[n.ID()]
 }
+
+// Dominates returns whether u dominates v. A node dominates itself and
+// no node unreachable from the root dominates or is dominated by
+// another node.
+func (d DominatorTree) Dominates(u, v graph.Node) bool {
+	uid := u.ID()
+	vid := v.ID()
+	if vid != d.root.ID() {
+		if _, ok := d.dominatorOf[vid]; !ok {
+			return false
+		}
+	}
+	for n := v; n != nil; n = d.dominatorOf[n.ID()] {
+		if n.ID() == uid {
+			return true
+		}
+	}
+	return false
+}
@@ -156,6 +156,17 @@ func TestDominators(t *testing.T) {
 					alg.name, got.dominatorOf, test.want.dominatorOf)
 			}
 
+			for _, u := range g.Nodes() {
+				for _, v := range g.Nodes() {
+					gotDom := got.Dominates(u, v)
+					wantDom := dominatesByRemoval(g, test.n, u, v)
+					if gotDom != wantDom {
+						t.Errorf("unexpected dominance of %v over %v from %s: got:%t want:%t",
+							u, v, alg.name, gotDom, wantDom)
+					}
+				}
+			}
+
 			for _, nodes := range got.dominatedBy {
 				sort.Sort(ordered.ByID(nodes))
 			}
@@ -166,3 +177,34 @@ func TestDominators(t *testing.T) {
 		}
 	}
 }
+
+// dominatesByRemoval returns whether u dominates v in g when rooted at root
+// by testing whether v is unreachable from root when u is removed from g.
+func dominatesByRemoval(g graph.Directed, root, u, v graph.Node) bool {
+	reachable := func(without graph.Node) bool {
+		if without != nil && without.ID() == root.ID() {
+			return false
+		}
+		seen := map[int64]bool{root.ID(): true}
+		stack := []graph.Node{root}
+		for len(stack) != 0 {
+			n := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			if n.ID() == v.ID() {
+				return true
+			}
+			for _, w := range g.From(n) {
+				if seen[w.ID()] || (without != nil && w.ID() == without.ID()) {
+					continue
+				}
+				seen[w.ID()] = true
+				stack = append(stack, w)
+			}
+		}
+		return false
+	}
+	if !reachable(nil) {
+		return false
+	}
+	return u.ID() == v.ID() || !reachable(u)
+}
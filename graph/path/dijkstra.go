@@ -6,6 +6,7 @@ package path
 
 import (
 	"container/heap"
+	"context"
 
 	"gonum.org/v1/gonum/graph"
 )
@@ -16,8 +17,28 @@ import (
 //
 // The time complexity of DijkstrFrom is O(|E|.log|V|).
 func DijkstraFrom(u graph.Node, g graph.Graph) Shortest {
+	path, _ := dijkstraFrom(nil, u, g)
+	return path
+}
+
+// DijkstraFromContext is like DijkstraFrom, but returns a zero Shortest and
+// the error held by ctx if ctx is canceled before the search completes.
+func DijkstraFromContext(ctx context.Context, u graph.Node, g graph.Graph) (Shortest, error) {
+	if err := ctx.Err(); err != nil {
+		return Shortest{}, err
+	}
+	path, ok := dijkstraFrom(ctx.Done(), u, g)
+	if !ok {
+		return Shortest{}, ctx.Err()
+	}
+	return path, nil
+}
+
+// dijkstraFrom is the single-source implementation of Dijkstra. It returns
+// false if done is closed before the search completes.
+func dijkstraFrom(done <-chan struct{}, u graph.Node, g graph.Graph) (path Shortest, ok bool) {
 	if !g.Has(u) {
-		return Shortest{from: u}
+		return Shortest{from: u}, true
 	}
 	var weight Weighting
 	if wg, ok := g.(graph.Weighted); ok {
@@ -27,7 +48,7 @@ func DijkstraFrom(u graph.Node, g graph.Graph) Shortest {
 	}
 
 	nodes := g.Nodes()
-	path := newShortestFrom(u, nodes)
+	path = newShortestFrom(u, nodes)
 
 	// Dijkstra's algorithm here is implemented essentially as
 	// described in Function B.2 in figure 6 of UTCS Technical
@@ -41,6 +62,9 @@ func DijkstraFrom(u graph.Node, g graph.Graph) Shortest {
 	// http://www.cs.utexas.edu/ftp/techreports/tr07-54.pdf
 	Q := priorityQueue{{node: u, dist: 0}}
 	for Q.Len() != 0 {
+		if canceled(done) {
+			return Shortest{}, false
+		}
 		mid := heap.Pop(&Q).(distanceNode)
 		k := path.indexOf[mid.node.ID()]
 		if mid.dist > path.dist[k] {
@@ -63,7 +87,7 @@ func DijkstraFrom(u graph.Node, g graph.Graph) Shortest {
 		}
 	}
 
-	return path
+	return path, true
 }
 
 // DijkstraAllPaths returns a shortest-path tree for shortest paths in the graph g.
@@ -73,15 +97,30 @@ func DijkstraFrom(u graph.Node, g graph.Graph) Shortest {
 // The time complexity of DijkstrAllPaths is O(|V|.|E|+|V|^2.log|V|).
 func DijkstraAllPaths(g graph.Graph) (paths AllShortest) {
 	paths = newAllShortest(g.Nodes(), false)
-	dijkstraAllPaths(g, paths)
+	dijkstraAllPaths(nil, g, paths)
 	return paths
 }
 
+// DijkstraAllPathsContext is like DijkstraAllPaths, but returns a zero
+// AllShortest and the error held by ctx if ctx is canceled before the
+// search completes.
+func DijkstraAllPathsContext(ctx context.Context, g graph.Graph) (AllShortest, error) {
+	if err := ctx.Err(); err != nil {
+		return AllShortest{}, err
+	}
+	paths := newAllShortest(g.Nodes(), false)
+	if !dijkstraAllPaths(ctx.Done(), g, paths) {
+		return AllShortest{}, ctx.Err()
+	}
+	return paths, nil
+}
+
 // dijkstraAllPaths is the all-paths implementation of Dijkstra. It is shared
 // between DijkstraAllPaths and JohnsonAllPaths to avoid repeated allocation
-// of the nodes slice and the indexOf map. It returns nothing, but stores the
-// result of the work in the paths parameter which is a reference type.
-func dijkstraAllPaths(g graph.Graph, paths AllShortest) {
+// of the nodes slice and the indexOf map. It stores the result of the work
+// in the paths parameter which is a reference type, and returns false if
+// done is closed before the work is complete.
+func dijkstraAllPaths(done <-chan struct{}, g graph.Graph, paths AllShortest) (ok bool) {
 	var weight Weighting
 	if wg, ok := g.(graph.Weighted); ok {
 		weight = wg.Weight
@@ -101,6 +140,9 @@ func dijkstraAllPaths(g graph.Graph, paths AllShortest) {
 		// Q must be empty at this point.
 		heap.Push(&Q, distanceNode{node: u, dist: 0})
 		for Q.Len() != 0 {
+			if canceled(done) {
+				return false
+			}
 			mid := heap.Pop(&Q).(distanceNode)
 			k := paths.indexOf[mid.node.ID()]
 			if mid.dist < paths.dist.At(i, k) {
@@ -125,6 +167,18 @@ func dijkstraAllPaths(g graph.Graph, paths AllShortest) {
 			}
 		}
 	}
+	return true
+}
+
+// canceled returns whether done is closed. A nil
+// done channel is never closed.
+func canceled(done <-chan struct{}) bool {
+	select {
+	case <-done:
+		return true
+	default:
+		return false
+	}
 }
 
 type distanceNode struct {
@@ -5,6 +5,7 @@
 package path
 
 import (
+	"context"
 	"math"
 	"reflect"
 	"sort"
@@ -176,3 +177,46 @@ func TestDijkstraAllPaths(t *testing.T) {
 		}
 	}
 }
+
+func TestDijkstraContext(t *testing.T) {
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+	for _, test := range testgraphs.ShortestPathTests {
+		if test.HasNegativeWeight {
+			continue
+		}
+		g := test.Graph()
+		for _, e := range test.Edges {
+			g.SetWeightedEdge(e)
+		}
+
+		pt, err := DijkstraFromContext(context.Background(), test.Query.From(), g.(graph.Graph))
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", test.Name, err)
+		}
+		if weight := pt.WeightTo(test.Query.To()); weight != test.Weight {
+			t.Errorf("%q: unexpected weight from DijkstraFromContext: got:%f want:%f",
+				test.Name, weight, test.Weight)
+		}
+		pt, err = DijkstraFromContext(canceled, test.Query.From(), g.(graph.Graph))
+		if err != context.Canceled {
+			t.Errorf("%q: unexpected error for canceled context: got:%v want:%v", test.Name, err, context.Canceled)
+		}
+		if pt.From() != nil {
+			t.Errorf("%q: unexpected partial result for canceled context", test.Name)
+		}
+
+		apt, err := DijkstraAllPathsContext(context.Background(), g.(graph.Graph))
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", test.Name, err)
+		}
+		if weight := apt.Weight(test.Query.From(), test.Query.To()); weight != test.Weight {
+			t.Errorf("%q: unexpected weight from DijkstraAllPathsContext: got:%f want:%f",
+				test.Name, weight, test.Weight)
+		}
+		_, err = DijkstraAllPathsContext(canceled, g.(graph.Graph))
+		if err != context.Canceled {
+			t.Errorf("%q: unexpected error for canceled context: got:%v want:%v", test.Name, err, context.Canceled)
+		}
+	}
+}
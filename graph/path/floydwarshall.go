@@ -4,7 +4,11 @@
 
 package path
 
-import "gonum.org/v1/gonum/graph"
+import (
+	"context"
+
+	"gonum.org/v1/gonum/graph"
+)
 
 // FloydWarshall returns a shortest-path tree for the graph g or false indicating
 // that a negative cycle exists in the graph. If the graph does not implement
@@ -12,6 +16,26 @@ import "gonum.org/v1/gonum/graph"
 //
 // The time complexity of FloydWarshall is O(|V|^3).
 func FloydWarshall(g graph.Graph) (paths AllShortest, ok bool) {
+	paths, ok, _ = floydWarshall(nil, g)
+	return paths, ok
+}
+
+// FloydWarshallContext is like FloydWarshall, but returns a zero AllShortest
+// and the error held by ctx if ctx is canceled before the search completes.
+func FloydWarshallContext(ctx context.Context, g graph.Graph) (paths AllShortest, ok bool, err error) {
+	if err := ctx.Err(); err != nil {
+		return AllShortest{}, false, err
+	}
+	paths, ok, complete := floydWarshall(ctx.Done(), g)
+	if !complete {
+		return AllShortest{}, false, ctx.Err()
+	}
+	return paths, ok, nil
+}
+
+// floydWarshall is the implementation of FloydWarshall. The complete
+// return is false if done is closed before the search completes.
+func floydWarshall(done <-chan struct{}, g graph.Graph) (paths AllShortest, ok, complete bool) {
 	var weight Weighting
 	if wg, ok := g.(graph.Weighted); ok {
 		weight = wg.Weight
@@ -34,6 +58,9 @@ func FloydWarshall(g graph.Graph) (paths AllShortest, ok bool) {
 	}
 
 	for k := range nodes {
+		if canceled(done) {
+			return AllShortest{}, false, false
+		}
 		for i := range nodes {
 			for j := range nodes {
 				ij := paths.dist.At(i, j)
@@ -55,5 +82,5 @@ func FloydWarshall(g graph.Graph) (paths AllShortest, ok bool) {
 		}
 	}
 
-	return paths, ok
+	return paths, ok, true
 }
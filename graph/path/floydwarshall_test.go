@@ -5,6 +5,7 @@
 package path
 
 import (
+	"context"
 	"math"
 	"reflect"
 	"sort"
@@ -100,3 +101,38 @@ func TestFloydWarshall(t *testing.T) {
 		}
 	}
 }
+
+func TestFloydWarshallContext(t *testing.T) {
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+	for _, test := range testgraphs.ShortestPathTests {
+		g := test.Graph()
+		for _, e := range test.Edges {
+			g.SetWeightedEdge(e)
+		}
+
+		pt, ok, err := FloydWarshallContext(context.Background(), g.(graph.Graph))
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", test.Name, err)
+		}
+		if ok == test.HasNegativeCycle {
+			t.Errorf("%q: unexpected negative cycle result: got:%t want:%t", test.Name, !ok, test.HasNegativeCycle)
+		}
+		if ok {
+			if weight := pt.Weight(test.Query.From(), test.Query.To()); weight != test.Weight {
+				t.Errorf("%q: unexpected weight: got:%f want:%f", test.Name, weight, test.Weight)
+			}
+		}
+
+		if test.HasNegativeCycle {
+			continue
+		}
+		_, ok, err = FloydWarshallContext(canceled, g.(graph.Graph))
+		if err != context.Canceled {
+			t.Errorf("%q: unexpected error for canceled context: got:%v want:%v", test.Name, err, context.Canceled)
+		}
+		if ok {
+			t.Errorf("%q: unexpected ok for canceled context", test.Name)
+		}
+	}
+}
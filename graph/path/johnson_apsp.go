@@ -5,6 +5,7 @@
 package path
 
 import (
+	"context"
 	"math"
 
 	"golang.org/x/exp/rand"
@@ -18,6 +19,27 @@ import (
 //
 // The time complexity of JohnsonAllPaths is O(|V|.|E|+|V|^2.log|V|).
 func JohnsonAllPaths(g graph.Graph) (paths AllShortest, ok bool) {
+	paths, ok, _ = johnsonAllPaths(nil, g)
+	return paths, ok
+}
+
+// JohnsonAllPathsContext is like JohnsonAllPaths, but returns a zero
+// AllShortest and the error held by ctx if ctx is canceled before the
+// search completes.
+func JohnsonAllPathsContext(ctx context.Context, g graph.Graph) (paths AllShortest, ok bool, err error) {
+	if err := ctx.Err(); err != nil {
+		return AllShortest{}, false, err
+	}
+	paths, ok, complete := johnsonAllPaths(ctx.Done(), g)
+	if !complete {
+		return AllShortest{}, false, ctx.Err()
+	}
+	return paths, ok, nil
+}
+
+// johnsonAllPaths is the implementation of JohnsonAllPaths. The complete
+// return is false if done is closed before the search completes.
+func johnsonAllPaths(done <-chan struct{}, g graph.Graph) (paths AllShortest, ok, complete bool) {
 	jg := johnsonWeightAdjuster{
 		g:      g,
 		from:   g.From,
@@ -45,11 +67,13 @@ func JohnsonAllPaths(g graph.Graph) (paths AllShortest, ok bool) {
 	jg.bellmanFord = true
 	jg.adjustBy, ok = BellmanFordFrom(johnsonGraphNode(jg.q), jg)
 	if !ok {
-		return paths, false
+		return paths, false, true
 	}
 
 	jg.bellmanFord = false
-	dijkstraAllPaths(jg, paths)
+	if !dijkstraAllPaths(done, jg, paths) {
+		return AllShortest{}, false, false
+	}
 
 	for i, u := range paths.nodes {
 		hu := jg.adjustBy.WeightTo(u)
@@ -62,7 +86,7 @@ func JohnsonAllPaths(g graph.Graph) (paths AllShortest, ok bool) {
 		}
 	}
 
-	return paths, ok
+	return paths, ok, true
 }
 
 type johnsonWeightAdjuster struct {
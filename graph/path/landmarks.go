@@ -0,0 +1,270 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package path
+
+import (
+	"container/heap"
+	"math"
+	"sort"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/internal/ordered"
+)
+
+// LandmarkSelection specifies the strategy used to choose landmarks
+// for a Landmarks distance oracle.
+type LandmarkSelection int
+
+const (
+	// RandomLandmarks chooses landmarks
+	// uniformly at random.
+	RandomLandmarks LandmarkSelection = iota
+
+	// FarthestLandmarks chooses an initial
+	// random landmark and then repeatedly
+	// chooses the node farthest from all
+	// landmarks chosen so far.
+	FarthestLandmarks
+
+	// DegreeLandmarks chooses the nodes
+	// with the highest degree, breaking
+	// ties by lowest node ID.
+	DegreeLandmarks
+)
+
+// Landmarks is an approximate distance oracle based on the landmark and
+// triangle inequality (ALT) scheme described in Goldberg and Harrelson
+// "Computing the shortest path: A* search meets graph theory" (SODA 2005).
+//
+// After preprocessing, distance bounds between any pair of nodes are
+// computed in time proportional to the number of landmarks, independent
+// of the size of the graph.
+//
+// Distance estimates have bounded additive stretch. For each landmark L
+// the estimate d(u,L)+d(L,v) is at most d(u,v) + d(u,L)+d(L,u), so the
+// estimate exceeds the true distance by at most the round trip distance
+// between u and its nearest landmark. No bound is given on the ratio of
+// the estimate to the true distance.
+type Landmarks struct {
+	landmarks []graph.Node
+
+	indexOf map[int64]int
+
+	// from[i][j] holds the distance from
+	// landmark i to node j and to[i][j]
+	// holds the distance from node j to
+	// landmark i. For undirected graphs
+	// from and to are the same.
+	from, to [][]float64
+}
+
+// NewLandmarks returns a distance oracle for g using k landmarks chosen
+// with the given selection strategy. If k is greater than the number of
+// nodes in g, all nodes are used as landmarks. If src is not nil it is
+// used as the random source for landmark selection, otherwise rand.Intn
+// is used. If g does not implement graph.Weighted, UniformCost is used.
+// NewLandmarks will panic if g has a negative edge weight.
+//
+// Preprocessing requires 2k single source shortest path searches for
+// directed graphs and k searches for undirected graphs.
+func NewLandmarks(g graph.Graph, k int, sel LandmarkSelection, src *rand.Rand) Landmarks {
+	var intn func(int) int
+	if src == nil {
+		intn = rand.Intn
+	} else {
+		intn = src.Intn
+	}
+	var weight Weighting
+	if wg, ok := g.(graph.Weighted); ok {
+		weight = wg.Weight
+	} else {
+		weight = UniformCost(g)
+	}
+
+	nodes := g.Nodes()
+	sort.Sort(ordered.ByID(nodes))
+	l := Landmarks{indexOf: make(map[int64]int, len(nodes))}
+	for i, n := range nodes {
+		l.indexOf[n.ID()] = i
+	}
+	if k > len(nodes) {
+		k = len(nodes)
+	}
+	if k <= 0 {
+		return l
+	}
+
+	forward := func(n graph.Node) []float64 {
+		return l.distancesFrom(n, len(nodes), g.From, weight)
+	}
+	backward := forward
+	if dg, ok := g.(graph.Directed); ok {
+		backward = func(n graph.Node) []float64 {
+			return l.distancesFrom(n, len(nodes), dg.To, func(x, y graph.Node) (float64, bool) {
+				return weight(y, x)
+			})
+		}
+	}
+
+	switch sel {
+	case RandomLandmarks:
+		perm := make([]int, len(nodes))
+		for i := range perm {
+			perm[i] = i
+		}
+		for i := 0; i < k; i++ {
+			j := i + intn(len(perm)-i)
+			perm[i], perm[j] = perm[j], perm[i]
+			l.landmarks = append(l.landmarks, nodes[perm[i]])
+		}
+	case FarthestLandmarks:
+		// minDist holds the distance from each node
+		// to the nearest landmark chosen so far.
+		minDist := make([]float64, len(nodes))
+		for i := range minDist {
+			minDist[i] = math.Inf(1)
+		}
+		next := nodes[intn(len(nodes))]
+		for i := 0; i < k; i++ {
+			l.landmarks = append(l.landmarks, next)
+			d := forward(next)
+			l.from = append(l.from, d)
+			far := -1.0
+			for j, dj := range d {
+				if dj < minDist[j] {
+					minDist[j] = dj
+				}
+				// Unreachable nodes are farthest so that
+				// landmarks are spread over components.
+				if minDist[j] > far {
+					far = minDist[j]
+					next = nodes[j]
+				}
+			}
+		}
+	case DegreeLandmarks:
+		byDegree := make([]graph.Node, len(nodes))
+		copy(byDegree, nodes)
+		degree := func(n graph.Node) int {
+			d := len(g.From(n))
+			if dg, ok := g.(graph.Directed); ok {
+				d += len(dg.To(n))
+			}
+			return d
+		}
+		sort.SliceStable(byDegree, func(i, j int) bool {
+			return degree(byDegree[i]) > degree(byDegree[j])
+		})
+		l.landmarks = byDegree[:k]
+	default:
+		panic("path: unknown landmark selection")
+	}
+
+	if l.from == nil {
+		for _, n := range l.landmarks {
+			l.from = append(l.from, forward(n))
+		}
+	}
+	if _, ok := g.(graph.Directed); ok {
+		for _, n := range l.landmarks {
+			l.to = append(l.to, backward(n))
+		}
+	} else {
+		l.to = l.from
+	}
+
+	return l
+}
+
+// distancesFrom returns the shortest path distances from u to all
+// nodes following edges given by next with weights given by weight.
+func (l Landmarks) distancesFrom(u graph.Node, n int, next func(graph.Node) []graph.Node, weight Weighting) []float64 {
+	dist := make([]float64, n)
+	for i := range dist {
+		dist[i] = math.Inf(1)
+	}
+	dist[l.indexOf[u.ID()]] = 0
+	Q := priorityQueue{{node: u, dist: 0}}
+	for Q.Len() != 0 {
+		mid := heap.Pop(&Q).(distanceNode)
+		if mid.dist > dist[l.indexOf[mid.node.ID()]] {
+			continue
+		}
+		for _, v := range next(mid.node) {
+			w, ok := weight(mid.node, v)
+			if !ok {
+				panic("path: unexpected invalid weight")
+			}
+			if w < 0 {
+				panic("path: negative edge weight")
+			}
+			j := l.indexOf[v.ID()]
+			if joint := mid.dist + w; joint < dist[j] {
+				dist[j] = joint
+				heap.Push(&Q, distanceNode{node: v, dist: joint})
+			}
+		}
+	}
+	return dist
+}
+
+// Landmarks returns the landmark nodes used by the oracle.
+func (l Landmarks) Landmarks() []graph.Node { return l.landmarks }
+
+// Bounds returns lower and upper bounds on the shortest path distance
+// from u to v. If either node is not in the graph, Bounds returns
+// +Inf for both bounds. An infinite upper bound indicates that no path
+// through a landmark joins u to v, and an infinite lower bound indicates
+// that there is no path from u to v.
+func (l Landmarks) Bounds(u, v graph.Node) (lower, upper float64) {
+	i, ok := l.indexOf[u.ID()]
+	if !ok {
+		return math.Inf(1), math.Inf(1)
+	}
+	j, ok := l.indexOf[v.ID()]
+	if !ok {
+		return math.Inf(1), math.Inf(1)
+	}
+	if i == j {
+		return 0, 0
+	}
+	upper = math.Inf(1)
+	for k := range l.landmarks {
+		// d(L,v) <= d(L,u) + d(u,v)
+		if lb := l.from[k][j] - l.from[k][i]; lb > lower {
+			lower = lb
+		}
+		// d(u,L) <= d(u,v) + d(v,L)
+		if lb := l.to[k][i] - l.to[k][j]; lb > lower {
+			lower = lb
+		}
+		if ub := l.to[k][i] + l.from[k][j]; ub < upper {
+			upper = ub
+		}
+	}
+	return lower, upper
+}
+
+// Distance returns an estimate of the shortest path distance from u
+// to v. The returned value is the upper bound returned by Bounds, the
+// minimum over the landmarks L of d(u,L)+d(L,v). It is exact when a
+// shortest path from u to v passes through a landmark, and otherwise
+// exceeds the true distance by at most min over L of d(u,L)+d(L,u). The
+// estimate is finite whenever some landmark is reachable from u and
+// reaches v, which holds for all connected pairs of an undirected graph
+// when each connected component holds a landmark.
+func (l Landmarks) Distance(u, v graph.Node) float64 {
+	_, upper := l.Bounds(u, v)
+	return upper
+}
+
+// Heuristic returns the lower bound on the shortest path distance from
+// x to y. Heuristic is admissible and so may be used with AStar.
+func (l Landmarks) Heuristic(x, y graph.Node) float64 {
+	lower, _ := l.Bounds(x, y)
+	return lower
+}
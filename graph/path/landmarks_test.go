@@ -0,0 +1,93 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package path
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/simple"
+)
+
+func TestLandmarks(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for _, directed := range []bool{false, true} {
+		var g interface {
+			graph.Weighted
+			graph.WeightedBuilder
+		}
+		if directed {
+			g = simple.NewWeightedDirectedGraph(0, math.Inf(1))
+		} else {
+			g = simple.NewWeightedUndirectedGraph(0, math.Inf(1))
+		}
+		const n = 40
+		for i := 0; i < n; i++ {
+			g.AddNode(simple.Node(i))
+		}
+		for i := 0; i < 3*n; i++ {
+			u, v := rnd.Intn(n), rnd.Intn(n)
+			if u == v {
+				continue
+			}
+			g.SetWeightedEdge(simple.WeightedEdge{F: simple.Node(u), T: simple.Node(v), W: 1 + 10*rnd.Float64()})
+		}
+		paths := DijkstraAllPaths(g)
+
+		for _, sel := range []LandmarkSelection{RandomLandmarks, FarthestLandmarks, DegreeLandmarks} {
+			l := NewLandmarks(g, 4, sel, rand.New(rand.NewSource(1)))
+			if len(l.Landmarks()) != 4 {
+				t.Errorf("unexpected number of landmarks for selection %d: got:%d want:4", sel, len(l.Landmarks()))
+			}
+			for _, u := range g.Nodes() {
+				for _, v := range g.Nodes() {
+					want := paths.Weight(u, v)
+					lower, upper := l.Bounds(u, v)
+					if lower > want*(1+1e-12) || upper < want*(1-1e-12) {
+						t.Errorf("bounds do not hold for %d->%d with directed=%t selection=%d: %v <= %v <= %v",
+							u.ID(), v.ID(), directed, sel, lower, want, upper)
+					}
+				}
+			}
+			// The estimate has bounded additive stretch.
+			for _, u := range g.Nodes() {
+				stretch := math.Inf(1)
+				for _, lm := range l.Landmarks() {
+					stretch = math.Min(stretch, paths.Weight(u, lm)+paths.Weight(lm, u))
+				}
+				for _, v := range g.Nodes() {
+					got, want := l.Distance(u, v), paths.Weight(u, v)
+					if got > (want+stretch)*(1+1e-12) {
+						t.Errorf("distance stretch exceeded for %d->%d with directed=%t selection=%d: got:%v want:<=%v",
+							u.ID(), v.ID(), directed, sel, got, want+stretch)
+					}
+				}
+			}
+			for _, lm := range l.Landmarks() {
+				for _, v := range g.Nodes() {
+					got, want := l.Distance(lm, v), paths.Weight(lm, v)
+					if math.Abs(got-want) > 1e-12*want && !(math.IsInf(got, 1) && math.IsInf(want, 1)) {
+						t.Errorf("unexpected distance from landmark %d to %d: got:%v want:%v", lm.ID(), v.ID(), got, want)
+					}
+				}
+			}
+
+			// The lower bound is admissible for A* search.
+			for _, u := range g.Nodes()[:5] {
+				for _, v := range g.Nodes() {
+					pt, _ := AStar(u, v, g, l.Heuristic)
+					_, got := pt.To(v)
+					want := paths.Weight(u, v)
+					if math.Abs(got-want) > 1e-9*want && !(math.IsInf(got, 1) && math.IsInf(want, 1)) {
+						t.Errorf("unexpected A* path weight for %d->%d: got:%v want:%v", u.ID(), v.ID(), got, want)
+					}
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,165 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package path
+
+import (
+	"sort"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/internal/ordered"
+)
+
+// UniformSpanningTree generates a spanning tree of g sampled uniformly from all
+// spanning trees of g using Wilson's algorithm, placing the result in the
+// destination, dst. The destination is not cleared first. If g is not connected,
+// a spanning forest with a uniformly sampled spanning tree for each connected
+// component will be constructed in dst. If src is not nil it is used as the
+// random source, otherwise rand.Float64 is used.
+//
+// Nodes and Edges from g are used to construct dst, so if the Node and Edge
+// types used in g are pointer or reference-like, then the values will be shared
+// between the graphs.
+//
+// If dst has nodes that exist in g, UniformSpanningTree will panic.
+func UniformSpanningTree(dst graph.Builder, g graph.Undirected, src *rand.Rand) {
+	nodes, parent := wilson(g, nil, src)
+	for _, n := range nodes {
+		dst.AddNode(n)
+	}
+	for i, p := range parent {
+		if p < 0 {
+			continue
+		}
+		dst.SetEdge(g.EdgeBetween(nodes[i], nodes[p]))
+	}
+}
+
+// RandomSpanningTree generates a spanning tree of g using Wilson's algorithm,
+// placing the result in the destination, dst. The probability of a tree being
+// chosen is proportional to the product of its edge weights, so edge weights
+// act as conductances; when all weights are equal the tree is sampled uniformly.
+// The destination is not cleared first. The weight of the spanning tree is
+// returned. If g is not connected, a spanning forest will be constructed in dst
+// and the sum of the spanning tree weights will be returned. If src is not nil
+// it is used as the random source, otherwise rand.Float64 is used.
+//
+// Nodes and Edges from g are used to construct dst, so if the Node and Edge
+// types used in g are pointer or reference-like, then the values will be shared
+// between the graphs.
+//
+// If dst has nodes that exist in g or g has a non-positive edge weight,
+// RandomSpanningTree will panic.
+func RandomSpanningTree(dst WeightedBuilder, g graph.WeightedUndirected, src *rand.Rand) float64 {
+	nodes, parent := wilson(g, g.Weight, src)
+	for _, n := range nodes {
+		dst.AddNode(n)
+	}
+	var w float64
+	for i, p := range parent {
+		if p < 0 {
+			continue
+		}
+		e := g.WeightedEdgeBetween(nodes[i], nodes[p])
+		dst.SetWeightedEdge(e)
+		w += e.Weight()
+	}
+	return w
+}
+
+// wilson returns the nodes of g sorted by ID and the index of the parent of
+// each node in a random spanning forest of g. Roots have a parent of -1. If
+// weight is nil, all edges have unit weight.
+func wilson(g graph.Graph, weight Weighting, src *rand.Rand) (nodes []graph.Node, parent []int) {
+	var rnd func() float64
+	if src == nil {
+		rnd = rand.Float64
+	} else {
+		rnd = src.Float64
+	}
+
+	nodes = g.Nodes()
+	sort.Sort(ordered.ByID(nodes))
+	indexOf := make(map[int64]int, len(nodes))
+	for i, n := range nodes {
+		indexOf[n.ID()] = i
+	}
+
+	// Build adjacency lists holding cumulative
+	// weights for sampling random walk steps.
+	adj := make([][]int, len(nodes))
+	cum := make([][]float64, len(nodes))
+	for i, u := range nodes {
+		to := g.From(u)
+		sort.Sort(ordered.ByID(to))
+		var sum float64
+		for _, v := range to {
+			j := indexOf[v.ID()]
+			if j == i {
+				continue
+			}
+			w := 1.0
+			if weight != nil {
+				var ok bool
+				w, ok = weight(u, v)
+				if !ok {
+					panic("wilson: unexpected invalid weight")
+				}
+				if !(w > 0) {
+					panic("wilson: non-positive edge weight")
+				}
+			}
+			sum += w
+			adj[i] = append(adj[i], j)
+			cum[i] = append(cum[i], sum)
+		}
+	}
+
+	// Mark the lowest ID node of each connected
+	// component as the root of its tree.
+	parent = make([]int, len(nodes))
+	inTree := make([]bool, len(nodes))
+	for i := range parent {
+		parent[i] = -2
+	}
+	for i := range nodes {
+		if parent[i] != -2 {
+			continue
+		}
+		parent[i] = -1
+		inTree[i] = true
+		stack := []int{i}
+		for len(stack) != 0 {
+			u := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			for _, v := range adj[u] {
+				if parent[v] == -2 {
+					parent[v] = -3
+					stack = append(stack, v)
+				}
+			}
+		}
+	}
+
+	// Perform loop-erased random walks from each node
+	// not yet in the tree until the tree is reached.
+	// Overwriting the next step on revisiting a node
+	// erases the loop.
+	next := make([]int, len(nodes))
+	for i := range nodes {
+		for u := i; !inTree[u]; u = next[u] {
+			c := cum[u]
+			r := rnd() * c[len(c)-1]
+			next[u] = adj[u][sort.SearchFloat64s(c, r)]
+		}
+		for u := i; !inTree[u]; u = next[u] {
+			inTree[u] = true
+			parent[u] = next[u]
+		}
+	}
+
+	return nodes, parent
+}
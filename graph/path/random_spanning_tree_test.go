@@ -0,0 +1,139 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package path
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/simple"
+	"gonum.org/v1/gonum/graph/topo"
+)
+
+func TestRandomSpanningTreeValid(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for _, test := range spanningTreeTests {
+		g := simple.NewWeightedUndirectedGraph(0, math.Inf(1))
+		for _, e := range test.edges {
+			g.SetWeightedEdge(e)
+		}
+		// Add an isolated node and a disconnected edge.
+		g.AddNode(simple.Node(-1))
+		g.SetWeightedEdge(simple.WeightedEdge{F: simple.Node(-2), T: simple.Node(-3), W: 1})
+		wantEdges := len(g.Nodes()) - len(topo.ConnectedComponents(g))
+
+		for i := 0; i < 10; i++ {
+			dst := simple.NewWeightedUndirectedGraph(0, math.Inf(1))
+			w := RandomSpanningTree(dst, g, rnd)
+			checkSpanningForest(t, test.name, dst, g, wantEdges)
+			var sum float64
+			for _, e := range dst.WeightedEdges() {
+				sum += e.Weight()
+			}
+			if w != sum {
+				t.Errorf("unexpected tree weight for %q: got:%v want:%v", test.name, w, sum)
+			}
+
+			udst := simple.NewUndirectedGraph()
+			UniformSpanningTree(udst, g, rnd)
+			checkSpanningForest(t, test.name, udst, g, wantEdges)
+		}
+	}
+}
+
+func checkSpanningForest(t *testing.T, name string, dst, g graph.Undirected, wantEdges int) {
+	if len(dst.Nodes()) != len(g.Nodes()) {
+		t.Errorf("unexpected number of nodes for %q: got:%d want:%d", name, len(dst.Nodes()), len(g.Nodes()))
+	}
+	var edges int
+	for _, u := range dst.Nodes() {
+		for _, v := range dst.From(u) {
+			if !g.HasEdgeBetween(u, v) {
+				t.Errorf("unexpected edge %d--%d in spanning tree of %q", u.ID(), v.ID(), name)
+			}
+			edges++
+		}
+	}
+	if edges/2 != wantEdges {
+		t.Errorf("unexpected number of edges for %q: got:%d want:%d", name, edges/2, wantEdges)
+	}
+	if got, want := len(topo.ConnectedComponents(dst)), len(topo.ConnectedComponents(g)); got != want {
+		t.Errorf("unexpected number of components for %q: got:%d want:%d", name, got, want)
+	}
+}
+
+func TestUniformSpanningTreeDistribution(t *testing.T) {
+	// K4 has 16 spanning trees.
+	g := simple.NewUndirectedGraph()
+	for u := 0; u < 4; u++ {
+		for v := u + 1; v < 4; v++ {
+			g.SetEdge(simple.Edge{F: simple.Node(u), T: simple.Node(v)})
+		}
+	}
+	const n = 32000
+	rnd := rand.New(rand.NewSource(1))
+	counts := make(map[string]int)
+	for i := 0; i < n; i++ {
+		dst := simple.NewUndirectedGraph()
+		UniformSpanningTree(dst, g, rnd)
+		counts[treeKey(dst)]++
+	}
+	if len(counts) != 16 {
+		t.Errorf("unexpected number of distinct spanning trees: got:%d want:16", len(counts))
+	}
+	for k, c := range counts {
+		if math.Abs(float64(c)-n/16) > 0.1*n/16 {
+			t.Errorf("unexpected frequency of tree %s: got:%d want:%d", k, c, n/16)
+		}
+	}
+}
+
+func TestRandomSpanningTreeDistribution(t *testing.T) {
+	g := simple.NewWeightedUndirectedGraph(0, math.Inf(1))
+	g.SetWeightedEdge(simple.WeightedEdge{F: simple.Node(0), T: simple.Node(1), W: 1})
+	g.SetWeightedEdge(simple.WeightedEdge{F: simple.Node(1), T: simple.Node(2), W: 2})
+	g.SetWeightedEdge(simple.WeightedEdge{F: simple.Node(0), T: simple.Node(2), W: 4})
+
+	// The probability of each tree is proportional
+	// to the product of its edge weights.
+	want := map[string]float64{
+		"[0-2 1-2]": 8.0 / 14,
+		"[0-1 0-2]": 4.0 / 14,
+		"[0-1 1-2]": 2.0 / 14,
+	}
+	const n = 20000
+	rnd := rand.New(rand.NewSource(1))
+	counts := make(map[string]int)
+	for i := 0; i < n; i++ {
+		dst := simple.NewWeightedUndirectedGraph(0, math.Inf(1))
+		RandomSpanningTree(dst, g, rnd)
+		counts[treeKey(dst)]++
+	}
+	for k, p := range want {
+		got := float64(counts[k]) / n
+		if math.Abs(got-p) > 0.02 {
+			t.Errorf("unexpected frequency of tree %s: got:%.3f want:%.3f", k, got, p)
+		}
+	}
+}
+
+// treeKey returns a canonical string representation of the edges of g.
+func treeKey(g graph.Undirected) string {
+	var edges []string
+	for _, u := range g.Nodes() {
+		for _, v := range g.From(u) {
+			if u.ID() < v.ID() {
+				edges = append(edges, fmt.Sprintf("%d-%d", u.ID(), v.ID()))
+			}
+		}
+	}
+	sort.Strings(edges)
+	return fmt.Sprint(edges)
+}
@@ -158,9 +158,18 @@ type UndirectedWeightLister interface {
 // types used in g are pointer or reference-like, then the values will be shared
 // between the graphs.
 //
+// If g implements UndirectedWeightLister, its WeightedEdges method is used to
+// obtain the edges of g, otherwise the edges are found by iterating over the
+// nodes of g.
+//
 // If dst has nodes that exist in g, Kruskal will panic.
-func Kruskal(dst WeightedBuilder, g UndirectedWeightLister) float64 {
-	edges := g.WeightedEdges()
+func Kruskal(dst WeightedBuilder, g graph.WeightedUndirected) float64 {
+	var edges []graph.WeightedEdge
+	if l, ok := g.(UndirectedWeightLister); ok {
+		edges = l.WeightedEdges()
+	} else {
+		edges = weightedEdgesOf(g)
+	}
 	sort.Sort(byWeight(edges))
 
 	ds := newDisjointSet()
@@ -180,6 +189,22 @@ func Kruskal(dst WeightedBuilder, g UndirectedWeightLister) float64 {
 	return w
 }
 
+// weightedEdgesOf returns the edges of the undirected graph g, each
+// reported once.
+func weightedEdgesOf(g graph.WeightedUndirected) []graph.WeightedEdge {
+	var edges []graph.WeightedEdge
+	for _, u := range g.Nodes() {
+		uid := u.ID()
+		for _, v := range g.From(u) {
+			if v.ID() < uid {
+				continue
+			}
+			edges = append(edges, g.WeightedEdgeBetween(u, v))
+		}
+	}
+	return edges
+}
+
 type byWeight []graph.WeightedEdge
 
 func (e byWeight) Len() int           { return len(e) }
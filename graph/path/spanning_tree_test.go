@@ -287,6 +287,18 @@ func TestKruskal(t *testing.T) {
 	}, t)
 }
 
+func TestKruskalWithoutLister(t *testing.T) {
+	testMinumumSpanning(func(dst WeightedBuilder, g spanningGraph) float64 {
+		return Kruskal(dst, weightedUndirectedOnly{g})
+	}, t)
+}
+
+// weightedUndirectedOnly hides the WeightedEdges
+// method of an UndirectedWeightLister.
+type weightedUndirectedOnly struct {
+	graph.WeightedUndirected
+}
+
 func TestPrim(t *testing.T) {
 	testMinumumSpanning(func(dst WeightedBuilder, g spanningGraph) float64 {
 		return Prim(dst, g)
@@ -0,0 +1,11 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package product implements graph product functions.
+//
+// All the graph products in this package are graph product operations
+// of the form P = A ⊗ B, where the nodes of P are the pairs of nodes
+// (a, b) with a in A and b in B, and the edges of P are determined by
+// the adjacency of the paired nodes in A and B.
+package product // import "gonum.org/v1/gonum/graph/product"
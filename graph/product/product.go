@@ -0,0 +1,175 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package product
+
+import (
+	"sort"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/internal/ordered"
+)
+
+// Node is a product of two graph nodes.
+type Node struct {
+	UID  int64
+	A, B graph.Node
+}
+
+// ID implements the graph.Node interface.
+func (n Node) ID() int64 { return n.UID }
+
+// Cartesian constructs the Cartesian product of a and b in dst.
+//
+// The Cartesian product of A and B, A □ B is a graph where (a₁, b₁) and
+// (a₂, b₂) are adjacent if a₁ = a₂ and b₁ is adjacent to b₂ in B, or
+// b₁ = b₂ and a₁ is adjacent to a₂ in A.
+//
+// The nodes of the product are added to dst as Node values, in the
+// ordering and with the IDs described for NodeID.
+func Cartesian(dst graph.Builder, a, b graph.Graph) {
+	p := newProduct(a, b)
+	p.addNodes(dst)
+	for i, u := range p.a {
+		for j, v := range p.b {
+			x := p.nodes[i][j]
+			for _, w := range a.From(u) {
+				p.setEdge(dst, x, p.nodes[p.aIdx[w.ID()]][j])
+			}
+			for _, w := range b.From(v) {
+				p.setEdge(dst, x, p.nodes[i][p.bIdx[w.ID()]])
+			}
+		}
+	}
+}
+
+// Tensor constructs the tensor, or categorical, product of a and b in dst.
+//
+// The tensor product of A and B, A ⨯ B is a graph where (a₁, b₁) and
+// (a₂, b₂) are adjacent if a₁ is adjacent to a₂ in A and b₁ is adjacent
+// to b₂ in B.
+//
+// The nodes of the product are added to dst as Node values, in the
+// ordering and with the IDs described for NodeID.
+func Tensor(dst graph.Builder, a, b graph.Graph) {
+	p := newProduct(a, b)
+	p.addNodes(dst)
+	for i, u := range p.a {
+		for j, v := range p.b {
+			x := p.nodes[i][j]
+			to := b.From(v)
+			for _, w := range a.From(u) {
+				for _, z := range to {
+					p.setEdge(dst, x, p.nodes[p.aIdx[w.ID()]][p.bIdx[z.ID()]])
+				}
+			}
+		}
+	}
+}
+
+// Strong constructs the strong product of a and b in dst.
+//
+// The strong product of A and B, A ⊠ B is a graph where (a₁, b₁) and
+// (a₂, b₂) are adjacent if they are adjacent in either the Cartesian
+// product or the tensor product of A and B.
+//
+// The nodes of the product are added to dst as Node values, in the
+// ordering and with the IDs described for NodeID.
+func Strong(dst graph.Builder, a, b graph.Graph) {
+	p := newProduct(a, b)
+	p.addNodes(dst)
+	for i, u := range p.a {
+		for j, v := range p.b {
+			x := p.nodes[i][j]
+			fromA := a.From(u)
+			fromB := b.From(v)
+			for _, w := range fromA {
+				p.setEdge(dst, x, p.nodes[p.aIdx[w.ID()]][j])
+			}
+			for _, z := range fromB {
+				p.setEdge(dst, x, p.nodes[i][p.bIdx[z.ID()]])
+			}
+			for _, w := range fromA {
+				for _, z := range fromB {
+					p.setEdge(dst, x, p.nodes[p.aIdx[w.ID()]][p.bIdx[z.ID()]])
+				}
+			}
+		}
+	}
+}
+
+// NodeID returns the ID of the product node pairing the ith node of A
+// with the jth node of B, where the nodes of each factor are ordered by
+// ascending ID and nb is the number of nodes in B. The ID is i*nb + j.
+// The factors of a product node can be retrieved from the A and B fields
+// of the Node, or from its ID with Factors.
+func NodeID(i, j, nb int) int64 {
+	return int64(i)*int64(nb) + int64(j)
+}
+
+// Factors returns the nodes of a and b paired by the product node with
+// the given ID, according to the scheme described for NodeID. If id is
+// not a valid product node ID for a and b, Factors returns nil nodes.
+func Factors(id int64, a, b graph.Graph) (na, nb graph.Node) {
+	an := sortedNodes(a)
+	bn := sortedNodes(b)
+	if id < 0 || id >= int64(len(an))*int64(len(bn)) {
+		return nil, nil
+	}
+	return an[id/int64(len(bn))], bn[id%int64(len(bn))]
+}
+
+// product holds the node pairing for a graph product.
+type product struct {
+	a, b       []graph.Node
+	aIdx, bIdx map[int64]int
+	nodes      [][]Node
+}
+
+func newProduct(a, b graph.Graph) product {
+	p := product{
+		a: sortedNodes(a),
+		b: sortedNodes(b),
+	}
+	p.aIdx = indexOf(p.a)
+	p.bIdx = indexOf(p.b)
+	p.nodes = make([][]Node, len(p.a))
+	for i, u := range p.a {
+		p.nodes[i] = make([]Node, len(p.b))
+		for j, v := range p.b {
+			p.nodes[i][j] = Node{UID: NodeID(i, j, len(p.b)), A: u, B: v}
+		}
+	}
+	return p
+}
+
+func (p product) addNodes(dst graph.Builder) {
+	for _, row := range p.nodes {
+		for _, n := range row {
+			dst.AddNode(n)
+		}
+	}
+}
+
+// setEdge adds an edge from x to y in dst unless x and y are the same.
+func (p product) setEdge(dst graph.Builder, x, y Node) {
+	if x.UID == y.UID {
+		return
+	}
+	dst.SetEdge(dst.NewEdge(x, y))
+}
+
+func sortedNodes(g graph.Graph) []graph.Node {
+	nodes := g.Nodes()
+	sort.Sort(ordered.ByID(nodes))
+	return nodes
+}
+
+func indexOf(nodes []graph.Node) map[int64]int {
+	idx := make(map[int64]int, len(nodes))
+	for i, n := range nodes {
+		idx[n.ID()] = i
+	}
+	return idx
+}
@@ -0,0 +1,159 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package product
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/simple"
+)
+
+// path returns an undirected path graph with n nodes,
+// with node IDs offset by off.
+func path(n int, off int64) graph.Graph {
+	g := simple.NewUndirectedGraph()
+	g.AddNode(simple.Node(off))
+	for i := int64(1); i < int64(n); i++ {
+		g.SetEdge(simple.Edge{F: simple.Node(off + i - 1), T: simple.Node(off + i)})
+	}
+	return g
+}
+
+// edgePairs returns the edges of g as factor ID pairs, sorted.
+func edgePairs(g graph.Graph, undirected bool) [][2][2]int64 {
+	var pairs [][2][2]int64
+	for _, u := range g.Nodes() {
+		pu := u.(Node)
+		for _, v := range g.From(u) {
+			if undirected && v.ID() < u.ID() {
+				continue
+			}
+			pv := v.(Node)
+			pairs = append(pairs, [2][2]int64{
+				{pu.A.ID(), pu.B.ID()},
+				{pv.A.ID(), pv.B.ID()},
+			})
+		}
+	}
+	sort.Slice(pairs, func(i, j int) bool {
+		a, b := pairs[i], pairs[j]
+		for k := 0; k < 2; k++ {
+			for l := 0; l < 2; l++ {
+				if a[k][l] != b[k][l] {
+					return a[k][l] < b[k][l]
+				}
+			}
+		}
+		return false
+	})
+	return pairs
+}
+
+var productTests = []struct {
+	name    string
+	product func(dst graph.Builder, a, b graph.Graph)
+	a, b    graph.Graph
+	nodes   int
+	edges   int
+}{
+	{name: "Cartesian K2 K2", product: Cartesian, a: path(2, 0), b: path(2, 10), nodes: 4, edges: 4},
+	{name: "Cartesian P3 P2", product: Cartesian, a: path(3, 0), b: path(2, 10), nodes: 6, edges: 7},
+	{name: "Cartesian P3 K1", product: Cartesian, a: path(3, 0), b: path(1, 10), nodes: 3, edges: 2},
+	{name: "Tensor K2 K2", product: Tensor, a: path(2, 0), b: path(2, 10), nodes: 4, edges: 2},
+	{name: "Tensor P3 P3", product: Tensor, a: path(3, 0), b: path(3, 10), nodes: 9, edges: 8},
+	{name: "Strong K2 K2", product: Strong, a: path(2, 0), b: path(2, 10), nodes: 4, edges: 6},
+	{name: "Strong P3 P2", product: Strong, a: path(3, 0), b: path(2, 10), nodes: 6, edges: 11},
+}
+
+func TestProductUndirected(t *testing.T) {
+	for _, test := range productTests {
+		dst := simple.NewUndirectedGraph()
+		test.product(dst, test.a, test.b)
+		if got := len(dst.Nodes()); got != test.nodes {
+			t.Errorf("unexpected number of nodes for %s: got:%d want:%d", test.name, got, test.nodes)
+		}
+		if got := len(edgePairs(dst, true)); got != test.edges {
+			t.Errorf("unexpected number of edges for %s: got:%d want:%d", test.name, got, test.edges)
+		}
+		for _, n := range dst.Nodes() {
+			a, b := Factors(n.ID(), test.a, test.b)
+			pn := n.(Node)
+			if a.ID() != pn.A.ID() || b.ID() != pn.B.ID() {
+				t.Errorf("unexpected factors for %s node %d: got:(%d,%d) want:(%d,%d)",
+					test.name, n.ID(), a.ID(), b.ID(), pn.A.ID(), pn.B.ID())
+			}
+		}
+	}
+}
+
+func TestProductDirected(t *testing.T) {
+	a := simple.NewDirectedGraph()
+	a.SetEdge(simple.Edge{F: simple.Node(0), T: simple.Node(1)})
+	b := simple.NewDirectedGraph()
+	b.SetEdge(simple.Edge{F: simple.Node(10), T: simple.Node(11)})
+
+	for _, test := range []struct {
+		name    string
+		product func(dst graph.Builder, a, b graph.Graph)
+		want    [][2][2]int64
+	}{
+		{
+			name:    "Cartesian",
+			product: Cartesian,
+			want: [][2][2]int64{
+				{{0, 10}, {0, 11}},
+				{{0, 10}, {1, 10}},
+				{{0, 11}, {1, 11}},
+				{{1, 10}, {1, 11}},
+			},
+		},
+		{
+			name:    "Tensor",
+			product: Tensor,
+			want: [][2][2]int64{
+				{{0, 10}, {1, 11}},
+			},
+		},
+		{
+			name:    "Strong",
+			product: Strong,
+			want: [][2][2]int64{
+				{{0, 10}, {0, 11}},
+				{{0, 10}, {1, 10}},
+				{{0, 10}, {1, 11}},
+				{{0, 11}, {1, 11}},
+				{{1, 10}, {1, 11}},
+			},
+		},
+	} {
+		dst := simple.NewDirectedGraph()
+		test.product(dst, a, b)
+		got := edgePairs(dst, false)
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("unexpected edges for directed %s product:\ngot: %v\nwant:%v", test.name, got, test.want)
+		}
+	}
+}
+
+func TestNodeID(t *testing.T) {
+	a := path(3, 5)
+	b := path(2, 20)
+	dst := simple.NewUndirectedGraph()
+	Cartesian(dst, a, b)
+	for i, u := range []int64{5, 6, 7} {
+		for j, v := range []int64{20, 21} {
+			n := dst.Node(NodeID(i, j, 2)).(Node)
+			if n.A.ID() != u || n.B.ID() != v {
+				t.Errorf("unexpected factors for (%d,%d): got:(%d,%d) want:(%d,%d)", i, j, n.A.ID(), n.B.ID(), u, v)
+			}
+		}
+	}
+	if a, b := Factors(6, a, b); a != nil || b != nil {
+		t.Errorf("unexpected factors for invalid ID: got:(%v,%v) want:(nil,nil)", a, b)
+	}
+}
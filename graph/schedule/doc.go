@@ -0,0 +1,6 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package schedule provides task scheduling functions for directed acyclic graphs.
+package schedule // import "gonum.org/v1/gonum/graph/schedule"
@@ -0,0 +1,187 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package schedule
+
+import (
+	"math"
+	"sort"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/topo"
+)
+
+// Task is a task placed on a machine by a schedule.
+type Task struct {
+	Node graph.Node
+
+	// Machine is the index of the
+	// machine executing the task.
+	Machine int
+
+	// Start and Finish are the times
+	// the task starts and finishes.
+	Start, Finish float64
+}
+
+// Schedule is an assignment of tasks to a set of identical machines.
+type Schedule struct {
+	// Tasks holds the scheduled tasks
+	// ordered by start time and then by
+	// machine index.
+	Tasks []Task
+
+	// Makespan is the time at which
+	// the last task finishes.
+	Makespan float64
+
+	indexOf map[int64]int
+}
+
+// TaskOf returns the scheduled task for the node n and whether n
+// was scheduled.
+func (s Schedule) TaskOf(n graph.Node) (t Task, ok bool) {
+	i, ok := s.indexOf[n.ID()]
+	if !ok {
+		return Task{}, false
+	}
+	return s.Tasks[i], true
+}
+
+// ListSchedule schedules the tasks represented by the nodes of the directed
+// acyclic graph g onto the given number of identical machines using the
+// Heterogeneous Earliest Finish Time (HEFT) list scheduling heuristic.
+//
+// The execution time of each task is given by cost. The weight of an edge
+// in g is the communication time needed to transfer the result of the from
+// task to the to task when the two tasks are placed on different machines;
+// communication between tasks on the same machine is free. Tasks are
+// prioritized by their upward rank and each is placed, possibly into an idle
+// gap between already placed tasks, on the machine that gives it the earliest
+// finish time.
+//
+// If g is not acyclic, ListSchedule returns a topo.Unorderable error. ListSchedule
+// will panic if machines is less than one or if a task cost or edge weight is
+// negative.
+//
+// See doi:10.1109/71.993206 for details of the algorithm.
+func ListSchedule(g graph.WeightedDirected, machines int, cost func(graph.Node) float64) (Schedule, error) {
+	if machines < 1 {
+		panic("schedule: too few machines")
+	}
+	order, err := topo.Sort(g)
+	if err != nil {
+		return Schedule{}, err
+	}
+
+	comm := func(u, v graph.Node) float64 {
+		w := g.WeightedEdge(u, v).Weight()
+		if w < 0 {
+			panic("schedule: negative communication cost")
+		}
+		return w
+	}
+
+	// Calculate upward ranks in reverse topological order.
+	costs := make(map[int64]float64, len(order))
+	rank := make(map[int64]float64, len(order))
+	for i := len(order) - 1; i >= 0; i-- {
+		u := order[i]
+		c := cost(u)
+		if c < 0 {
+			panic("schedule: negative task cost")
+		}
+		costs[u.ID()] = c
+		var max float64
+		for _, v := range g.From(u) {
+			max = math.Max(max, comm(u, v)+rank[v.ID()])
+		}
+		rank[u.ID()] = c + max
+	}
+
+	// Prioritize by decreasing rank. The stable sort
+	// ensures that tasks with equal rank retain their
+	// topological ordering so that precedence holds
+	// when costs are zero.
+	sort.SliceStable(order, func(i, j int) bool {
+		return rank[order[i].ID()] > rank[order[j].ID()]
+	})
+
+	busy := make([][]Task, machines)
+	placed := make(map[int64]Task, len(order))
+	for _, u := range order {
+		uid := u.ID()
+		c := costs[uid]
+		best := Task{Node: u, Finish: math.Inf(1)}
+		for m := range busy {
+			var ready float64
+			for _, p := range g.To(u) {
+				pt := placed[p.ID()]
+				t := pt.Finish
+				if pt.Machine != m {
+					t += comm(p, u)
+				}
+				ready = math.Max(ready, t)
+			}
+			start := earliestGap(busy[m], ready, c)
+			if start+c < best.Finish {
+				best = Task{Node: u, Machine: m, Start: start, Finish: start + c}
+			}
+		}
+		placed[uid] = best
+		busy[best.Machine] = insertTask(busy[best.Machine], best)
+	}
+
+	s := Schedule{
+		Tasks:   make([]Task, 0, len(order)),
+		indexOf: make(map[int64]int, len(order)),
+	}
+	for _, t := range placed {
+		s.Tasks = append(s.Tasks, t)
+		s.Makespan = math.Max(s.Makespan, t.Finish)
+	}
+	sort.Sort(byStart(s.Tasks))
+	for i, t := range s.Tasks {
+		s.indexOf[t.Node.ID()] = i
+	}
+	return s, nil
+}
+
+// earliestGap returns the earliest time no earlier than ready that a
+// task of the given duration can start between the tasks in busy, which
+// must be sorted by start time.
+func earliestGap(busy []Task, ready, duration float64) float64 {
+	start := ready
+	for _, t := range busy {
+		if start+duration <= t.Start {
+			return start
+		}
+		start = math.Max(start, t.Finish)
+	}
+	return start
+}
+
+// insertTask inserts t into busy maintaining start time order.
+func insertTask(busy []Task, t Task) []Task {
+	i := sort.Search(len(busy), func(i int) bool { return busy[i].Start > t.Start })
+	busy = append(busy, Task{})
+	copy(busy[i+1:], busy[i:])
+	busy[i] = t
+	return busy
+}
+
+// byStart sorts tasks by start time, machine index and then node ID.
+type byStart []Task
+
+func (t byStart) Len() int { return len(t) }
+func (t byStart) Less(i, j int) bool {
+	if t[i].Start != t[j].Start {
+		return t[i].Start < t[j].Start
+	}
+	if t[i].Machine != t[j].Machine {
+		return t[i].Machine < t[j].Machine
+	}
+	return t[i].Node.ID() < t[j].Node.ID()
+}
+func (t byStart) Swap(i, j int) { t[i], t[j] = t[j], t[i] }
@@ -0,0 +1,170 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package schedule
+
+import (
+	"testing"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/simple"
+	"gonum.org/v1/gonum/graph/topo"
+)
+
+var listScheduleTests = []struct {
+	name     string
+	edges    []simple.WeightedEdge
+	nodes    []int64
+	costs    map[int64]float64
+	machines int
+
+	wantMakespan float64
+}{
+	{
+		name:         "independent",
+		nodes:        []int64{0, 1, 2, 3},
+		costs:        map[int64]float64{0: 1, 1: 2, 2: 3, 3: 4},
+		machines:     2,
+		wantMakespan: 5,
+	},
+	{
+		name: "chain",
+		edges: []simple.WeightedEdge{
+			{F: simple.Node(0), T: simple.Node(1), W: 10},
+			{F: simple.Node(1), T: simple.Node(2), W: 10},
+		},
+		costs:        map[int64]float64{0: 1, 1: 2, 2: 3},
+		machines:     3,
+		wantMakespan: 6,
+	},
+	{
+		name: "fork-join expensive communication",
+		edges: []simple.WeightedEdge{
+			{F: simple.Node(0), T: simple.Node(1), W: 10},
+			{F: simple.Node(0), T: simple.Node(2), W: 10},
+			{F: simple.Node(1), T: simple.Node(3), W: 10},
+			{F: simple.Node(2), T: simple.Node(3), W: 10},
+		},
+		costs:        map[int64]float64{0: 1, 1: 2, 2: 2, 3: 1},
+		machines:     2,
+		wantMakespan: 6,
+	},
+	{
+		name: "fork-join cheap communication",
+		edges: []simple.WeightedEdge{
+			{F: simple.Node(0), T: simple.Node(1), W: 1},
+			{F: simple.Node(0), T: simple.Node(2), W: 1},
+			{F: simple.Node(1), T: simple.Node(3), W: 1},
+			{F: simple.Node(2), T: simple.Node(3), W: 1},
+		},
+		costs:        map[int64]float64{0: 1, 1: 4, 2: 4, 3: 1},
+		machines:     2,
+		wantMakespan: 7,
+	},
+	{
+		name: "single machine",
+		edges: []simple.WeightedEdge{
+			{F: simple.Node(0), T: simple.Node(1), W: 0},
+			{F: simple.Node(1), T: simple.Node(2), W: 0},
+			{F: simple.Node(3), T: simple.Node(2), W: 0},
+		},
+		costs:        map[int64]float64{0: 2, 1: 2, 2: 2, 3: 1},
+		machines:     1,
+		wantMakespan: 7,
+	},
+}
+
+func TestListSchedule(t *testing.T) {
+	for _, test := range listScheduleTests {
+		g := simple.NewWeightedDirectedGraph(0, 0)
+		for _, id := range test.nodes {
+			g.AddNode(simple.Node(id))
+		}
+		for _, e := range test.edges {
+			g.SetWeightedEdge(e)
+		}
+		cost := func(n graph.Node) float64 { return test.costs[n.ID()] }
+
+		s, err := ListSchedule(g, test.machines, cost)
+		if err != nil {
+			t.Errorf("unexpected error for %q: %v", test.name, err)
+			continue
+		}
+		if s.Makespan != test.wantMakespan {
+			t.Errorf("unexpected makespan for %q: got:%v want:%v", test.name, s.Makespan, test.wantMakespan)
+		}
+		checkSchedule(t, test.name, g, s, test.machines, cost)
+	}
+}
+
+func TestListScheduleCyclic(t *testing.T) {
+	g := simple.NewWeightedDirectedGraph(0, 0)
+	g.SetWeightedEdge(simple.WeightedEdge{F: simple.Node(0), T: simple.Node(1), W: 1})
+	g.SetWeightedEdge(simple.WeightedEdge{F: simple.Node(1), T: simple.Node(0), W: 1})
+	_, err := ListSchedule(g, 2, func(graph.Node) float64 { return 1 })
+	if _, ok := err.(topo.Unorderable); !ok {
+		t.Errorf("expected topo.Unorderable error for cyclic graph: got:%v", err)
+	}
+}
+
+func checkSchedule(t *testing.T, name string, g graph.WeightedDirected, s Schedule, machines int, cost func(graph.Node) float64) {
+	if len(s.Tasks) != len(g.Nodes()) {
+		t.Errorf("unexpected number of tasks for %q: got:%d want:%d", name, len(s.Tasks), len(g.Nodes()))
+	}
+	for i, a := range s.Tasks {
+		if a.Machine < 0 || a.Machine >= machines {
+			t.Errorf("invalid machine for %q task %d: %d", name, a.Node.ID(), a.Machine)
+		}
+		if a.Finish-a.Start != cost(a.Node) {
+			t.Errorf("unexpected task duration for %q task %d: got:%v want:%v", name, a.Node.ID(), a.Finish-a.Start, cost(a.Node))
+		}
+		if i > 0 && s.Tasks[i-1].Start > a.Start {
+			t.Errorf("tasks not ordered by start time for %q", name)
+		}
+		for _, b := range s.Tasks[i+1:] {
+			if a.Machine == b.Machine && a.Start < b.Finish && b.Start < a.Finish {
+				t.Errorf("overlapping tasks for %q: %d and %d", name, a.Node.ID(), b.Node.ID())
+			}
+		}
+		for _, p := range g.To(a.Node) {
+			pt, ok := s.TaskOf(p)
+			if !ok {
+				t.Errorf("missing task for %q node %d", name, p.ID())
+				continue
+			}
+			ready := pt.Finish
+			if pt.Machine != a.Machine {
+				ready += g.WeightedEdge(p, a.Node).Weight()
+			}
+			if a.Start < ready {
+				t.Errorf("precedence violated for %q: %d starts at %v before %v", name, a.Node.ID(), a.Start, ready)
+			}
+		}
+	}
+}
+
+func TestEarliestGap(t *testing.T) {
+	busy := []Task{
+		{Start: 0, Finish: 2},
+		{Start: 4, Finish: 5},
+		{Start: 6, Finish: 9},
+	}
+	for _, test := range []struct {
+		ready, duration float64
+		want            float64
+	}{
+		{ready: 0, duration: 1, want: 2},
+		{ready: 0, duration: 2, want: 2},
+		{ready: 0, duration: 3, want: 9},
+		{ready: 3, duration: 1, want: 3},
+		{ready: 3, duration: 2, want: 9},
+		{ready: 5, duration: 1, want: 5},
+		{ready: 10, duration: 1, want: 10},
+	} {
+		got := earliestGap(busy, test.ready, test.duration)
+		if got != test.want {
+			t.Errorf("unexpected start for ready=%v duration=%v: got:%v want:%v", test.ready, test.duration, got, test.want)
+		}
+	}
+}
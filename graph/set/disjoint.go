@@ -0,0 +1,179 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package set
+
+import "sort"
+
+// DisjointSet is a collection of non-overlapping sets of IDs, also known as
+// a union-find structure. Find and Union run in amortized time that is
+// effectively constant, using path compression and union by rank.
+//
+// DisjointSet is suitable for Kruskal's minimum spanning tree algorithm,
+// for finding connected components and for incremental clustering, where
+// elements are merged into sets as edges are discovered.
+type DisjointSet struct {
+	elems map[int64]*disjointElem
+	sets  int
+}
+
+// disjointElem is an element of a DisjointSet. The elements of each
+// set are linked in a circular list through next so that the members
+// of a set can be listed in time proportional to its size.
+type disjointElem struct {
+	id     int64
+	parent *disjointElem
+	next   *disjointElem
+	rank   int
+}
+
+// NewDisjointSet returns a new empty DisjointSet.
+func NewDisjointSet() *DisjointSet {
+	return &DisjointSet{elems: make(map[int64]*disjointElem)}
+}
+
+// MakeSet adds id to the disjoint set as the sole member of a new set.
+// If id is already in the disjoint set, MakeSet is a no-op.
+func (s *DisjointSet) MakeSet(id int64) {
+	if _, ok := s.elems[id]; ok {
+		return
+	}
+	e := &disjointElem{id: id}
+	e.parent = e
+	e.next = e
+	s.elems[id] = e
+	s.sets++
+}
+
+// Has returns whether id is in the disjoint set.
+func (s *DisjointSet) Has(id int64) bool {
+	_, ok := s.elems[id]
+	return ok
+}
+
+// Len returns the number of elements in the disjoint set.
+func (s *DisjointSet) Len() int {
+	return len(s.elems)
+}
+
+// Count returns the number of sets in the disjoint set.
+func (s *DisjointSet) Count() int {
+	return s.sets
+}
+
+// Find returns the representative ID of the set containing id. Two IDs
+// are in the same set if and only if they have the same representative.
+// The representative of a set may change when sets are merged. If id is
+// not in the disjoint set, ok is false.
+func (s *DisjointSet) Find(id int64) (rep int64, ok bool) {
+	e, ok := s.elems[id]
+	if !ok {
+		return 0, false
+	}
+	return find(e).id, true
+}
+
+// find returns the root of the tree containing e, compressing the
+// path from e to the root.
+func find(e *disjointElem) *disjointElem {
+	root := e
+	for root.parent != root {
+		root = root.parent
+	}
+	for e != root {
+		e, e.parent = e.parent, root
+	}
+	return root
+}
+
+// Same returns whether x and y are in the same set. Same returns false if
+// either x or y is not in the disjoint set.
+func (s *DisjointSet) Same(x, y int64) bool {
+	ex, ok := s.elems[x]
+	if !ok {
+		return false
+	}
+	ey, ok := s.elems[y]
+	if !ok {
+		return false
+	}
+	return find(ex) == find(ey)
+}
+
+// Union merges the sets containing x and y, returning whether they were
+// previously in different sets. Union will panic if either x or y is not
+// in the disjoint set.
+func (s *DisjointSet) Union(x, y int64) bool {
+	ex, ok := s.elems[x]
+	if !ok {
+		panic("set: union with missing element")
+	}
+	ey, ok := s.elems[y]
+	if !ok {
+		panic("set: union with missing element")
+	}
+	rx := find(ex)
+	ry := find(ey)
+	if rx == ry {
+		return false
+	}
+
+	switch {
+	case rx.rank < ry.rank:
+		rx.parent = ry
+	case ry.rank < rx.rank:
+		ry.parent = rx
+	default:
+		ry.parent = rx
+		rx.rank++
+	}
+	// Splice the two circular member lists.
+	rx.next, ry.next = ry.next, rx.next
+	s.sets--
+	return true
+}
+
+// SetOf returns the IDs in the set containing id, sorted ascending. If id
+// is not in the disjoint set, SetOf returns nil.
+func (s *DisjointSet) SetOf(id int64) []int64 {
+	e, ok := s.elems[id]
+	if !ok {
+		return nil
+	}
+	members := []int64{e.id}
+	for m := e.next; m != e; m = m.next {
+		members = append(members, m.id)
+	}
+	sort.Sort(int64s(members))
+	return members
+}
+
+// Sets returns all the sets in the disjoint set. The IDs of each set are
+// sorted ascending and the sets are ordered by their lowest ID.
+func (s *DisjointSet) Sets() [][]int64 {
+	sets := make([][]int64, 0, s.sets)
+	seen := make(map[*disjointElem]bool, s.sets)
+	for _, e := range s.elems {
+		r := find(e)
+		if seen[r] {
+			continue
+		}
+		seen[r] = true
+		sets = append(sets, s.SetOf(e.id))
+	}
+	sort.Sort(byFirst(sets))
+	return sets
+}
+
+type int64s []int64
+
+func (s int64s) Len() int           { return len(s) }
+func (s int64s) Less(i, j int) bool { return s[i] < s[j] }
+func (s int64s) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+
+type byFirst [][]int64
+
+func (s byFirst) Len() int           { return len(s) }
+func (s byFirst) Less(i, j int) bool { return s[i][0] < s[j][0] }
+func (s byFirst) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
@@ -0,0 +1,125 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package set
+
+import (
+	"reflect"
+	"testing"
+
+	"golang.org/x/exp/rand"
+)
+
+func TestDisjointSet(t *testing.T) {
+	s := NewDisjointSet()
+	for id := int64(0); id < 10; id++ {
+		s.MakeSet(id)
+	}
+	s.MakeSet(3)
+	if s.Len() != 10 || s.Count() != 10 {
+		t.Fatalf("unexpected size: got:%d elements %d sets want:10 elements 10 sets", s.Len(), s.Count())
+	}
+	if s.Same(1, 2) {
+		t.Error("unexpected shared set before union")
+	}
+	for _, u := range [][2]int64{{1, 2}, {3, 4}, {2, 4}, {7, 8}, {9, 7}} {
+		if !s.Union(u[0], u[1]) {
+			t.Errorf("unexpected failure to merge %d and %d", u[0], u[1])
+		}
+	}
+	if s.Union(1, 3) {
+		t.Error("unexpected merge of elements already in the same set")
+	}
+	if s.Count() != 5 {
+		t.Errorf("unexpected number of sets: got:%d want:5", s.Count())
+	}
+	if !s.Same(1, 3) || s.Same(1, 7) || s.Same(1, 100) {
+		t.Error("unexpected set membership")
+	}
+	r1, ok1 := s.Find(1)
+	r4, ok4 := s.Find(4)
+	if !ok1 || !ok4 || r1 != r4 {
+		t.Errorf("unexpected representatives: %d %d", r1, r4)
+	}
+	if _, ok := s.Find(100); ok {
+		t.Error("unexpected representative for missing element")
+	}
+	if got, want := s.SetOf(4), []int64{1, 2, 3, 4}; !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected set: got:%v want:%v", got, want)
+	}
+	if s.SetOf(100) != nil {
+		t.Error("unexpected set for missing element")
+	}
+	want := [][]int64{{0}, {1, 2, 3, 4}, {5}, {6}, {7, 8, 9}}
+	if got := s.Sets(); !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected sets: got:%v want:%v", got, want)
+	}
+	if !panics(func() { s.Union(1, 100) }) {
+		t.Error("expected panic for union with missing element")
+	}
+}
+
+func TestDisjointSetRandom(t *testing.T) {
+	const n = 1000
+	rnd := rand.New(rand.NewSource(1))
+	s := NewDisjointSet()
+	label := make([]int, n)
+	for i := range label {
+		s.MakeSet(int64(i))
+		label[i] = i
+	}
+	for k := 0; k < 700; k++ {
+		x, y := rnd.Intn(n), rnd.Intn(n)
+		merged := s.Union(int64(x), int64(y))
+		lx, ly := label[x], label[y]
+		if merged != (lx != ly) {
+			t.Fatalf("unexpected merge result for %d and %d: got:%t", x, y, merged)
+		}
+		for i := range label {
+			if label[i] == ly {
+				label[i] = lx
+			}
+		}
+	}
+	sets := make(map[int]int)
+	for i := range label {
+		sets[label[i]]++
+		for j := i + 1; j < n; j += 37 {
+			if s.Same(int64(i), int64(j)) != (label[i] == label[j]) {
+				t.Fatalf("unexpected membership for %d and %d", i, j)
+			}
+		}
+		if len(s.SetOf(int64(i))) != countLabel(label, label[i]) {
+			t.Fatalf("unexpected set size for %d", i)
+		}
+	}
+	if s.Count() != len(sets) {
+		t.Errorf("unexpected number of sets: got:%d want:%d", s.Count(), len(sets))
+	}
+	var total int
+	for _, set := range s.Sets() {
+		total += len(set)
+	}
+	if total != n {
+		t.Errorf("unexpected total size of sets: got:%d want:%d", total, n)
+	}
+}
+
+func countLabel(label []int, l int) int {
+	var n int
+	for _, v := range label {
+		if v == l {
+			n++
+		}
+	}
+	return n
+}
+
+func panics(fn func()) (ok bool) {
+	defer func() {
+		ok = recover() != nil
+	}()
+	fn()
+	return
+}
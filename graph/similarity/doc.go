@@ -0,0 +1,12 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package similarity provides measures of structural similarity between
+// graphs.
+//
+// The Jaccard and degree sequence measures are cheap to compute and are
+// suitable for screening large collections of graphs for near duplicates.
+// EditDistance gives a more discriminating, but more expensive, approximate
+// graph edit distance.
+package similarity // import "gonum.org/v1/gonum/graph/similarity"
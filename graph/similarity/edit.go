@@ -0,0 +1,213 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package similarity
+
+import (
+	"sort"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/internal/ordered"
+)
+
+// EditDistance returns an approximation to the graph edit distance between
+// a and b, the minimum number of node and edge insertions and deletions
+// needed to transform a into a graph isomorphic to b, and the mapping from
+// the IDs of nodes of a to the IDs of their matched nodes in b. Nodes of a
+// that are absent from the mapping are deleted, and nodes of b that are not
+// the image of any node of a are inserted.
+//
+// The distance is found by a beam search over assignments of the nodes of a,
+// in order of decreasing degree, to nodes of b or to deletion, retaining at
+// most beam partial assignments with the lowest cost at each step. The
+// returned distance is an upper bound on the graph edit distance and is
+// exact when beam is large enough to retain every partial assignment. The
+// search takes O(beam·n_a²·n_b) time for graphs with n_a and n_b nodes.
+//
+// EditDistance will panic if beam is not positive or if one of a and b is
+// directed and the other is not.
+func EditDistance(a, b graph.Graph, beam int) (dist float64, mapping map[int64]int64) {
+	if beam <= 0 {
+		panic("similarity: non-positive beam width")
+	}
+	directed := isDirected(a, b)
+
+	na := a.Nodes()
+	sort.Sort(ordered.ByID(na))
+	degA := make(map[int64]int, len(na))
+	for _, n := range na {
+		degA[n.ID()] = len(a.From(n))
+		if directed {
+			degA[n.ID()] += len(a.(graph.Directed).To(n))
+		}
+	}
+	sort.Stable(byDegree{nodes: na, deg: degA})
+	nb := b.Nodes()
+	sort.Sort(ordered.ByID(nb))
+
+	// Record adjacency by node position.
+	adjA := adjacency(a, na)
+	adjB := adjacency(b, nb)
+
+	states := []editState{{used: make([]bool, len(nb))}}
+	for i := range na {
+		var next []editState
+		for _, s := range states {
+			// Delete node i of a.
+			cost := s.cost + 1
+			for j := 0; j < i; j++ {
+				cost += float64(edgesBetween(adjA, i, j, directed))
+			}
+			next = append(next, s.extend(-1, cost))
+
+			// Substitute node i of a with node v of b.
+			for v, used := range s.used {
+				if used {
+					continue
+				}
+				cost := s.cost
+				for j, w := range s.assign {
+					if w < 0 {
+						// Node j was deleted with
+						// its edges to node i.
+						cost += float64(edgesBetween(adjA, i, j, directed))
+						continue
+					}
+					cost += float64(abs(adjA[i][j] - adjB[v][w]))
+					if directed {
+						cost += float64(abs(adjA[j][i] - adjB[w][v]))
+					}
+				}
+				next = append(next, s.extend(v, cost))
+			}
+		}
+
+		// Retain the best states, ranked by cost plus the
+		// number of nodes that must still be inserted or
+		// deleted.
+		remA := len(na) - i - 1
+		for k := range next {
+			remB := len(nb) - next[k].mapped
+			next[k].rank = next[k].cost + float64(abs(remA-remB))
+		}
+		sort.Stable(byRank(next))
+		if len(next) > beam {
+			next = next[:beam]
+		}
+		states = next
+	}
+
+	// Complete each assignment by inserting the unused
+	// nodes of b and their incident edges.
+	best := -1
+	for k := range states {
+		s := &states[k]
+		for v, used := range s.used {
+			if used {
+				continue
+			}
+			s.cost++
+			// Count each edge incident to an
+			// unused node once.
+			for w := range nb {
+				if s.used[w] || w > v {
+					s.cost += float64(edgesBetween(adjB, v, w, directed))
+				}
+			}
+		}
+		if best < 0 || s.cost < states[best].cost {
+			best = k
+		}
+	}
+
+	s := states[best]
+	mapping = make(map[int64]int64)
+	for i, v := range s.assign {
+		if v >= 0 {
+			mapping[na[i].ID()] = nb[v].ID()
+		}
+	}
+	return s.cost, mapping
+}
+
+// adjacency returns the adjacency matrix of g with rows and columns in the
+// order of nodes.
+func adjacency(g graph.Graph, nodes []graph.Node) [][]int {
+	idx := make(map[int64]int, len(nodes))
+	for i, n := range nodes {
+		idx[n.ID()] = i
+	}
+	adj := make([][]int, len(nodes))
+	for i := range adj {
+		adj[i] = make([]int, len(nodes))
+	}
+	for i, u := range nodes {
+		for _, v := range g.From(u) {
+			adj[i][idx[v.ID()]] = 1
+		}
+	}
+	return adj
+}
+
+// editState is a partial assignment of the nodes of the first graph
+// to nodes of the second graph or to deletion.
+type editState struct {
+	assign []int
+	used   []bool
+	mapped int
+	cost   float64
+	rank   float64
+}
+
+// extend returns a copy of s with the next node assigned to v, or deleted
+// if v is negative, with the given cost.
+func (s editState) extend(v int, cost float64) editState {
+	n := editState{
+		assign: make([]int, len(s.assign), len(s.assign)+1),
+		used:   make([]bool, len(s.used)),
+		mapped: s.mapped,
+		cost:   cost,
+	}
+	copy(n.assign, s.assign)
+	copy(n.used, s.used)
+	n.assign = append(n.assign, v)
+	if v >= 0 {
+		n.used[v] = true
+		n.mapped++
+	}
+	return n
+}
+
+// edgesBetween returns the number of edges between the nodes
+// at positions i and j of the adjacency matrix adj.
+func edgesBetween(adj [][]int, i, j int, directed bool) int {
+	if directed {
+		return adj[i][j] + adj[j][i]
+	}
+	return adj[i][j]
+}
+
+func abs(a int) int {
+	if a < 0 {
+		return -a
+	}
+	return a
+}
+
+type byDegree struct {
+	nodes []graph.Node
+	deg   map[int64]int
+}
+
+func (s byDegree) Len() int { return len(s.nodes) }
+func (s byDegree) Less(i, j int) bool {
+	return s.deg[s.nodes[i].ID()] > s.deg[s.nodes[j].ID()]
+}
+func (s byDegree) Swap(i, j int) { s.nodes[i], s.nodes[j] = s.nodes[j], s.nodes[i] }
+
+type byRank []editState
+
+func (s byRank) Len() int           { return len(s) }
+func (s byRank) Less(i, j int) bool { return s[i].rank < s[j].rank }
+func (s byRank) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
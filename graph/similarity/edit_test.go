@@ -0,0 +1,160 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package similarity
+
+import (
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/simple"
+)
+
+var editDistanceTests = []struct {
+	name string
+	a, b graph.Graph
+	want float64
+}{
+	{
+		name: "identical",
+		a:    undirected([][2]int64{{0, 1}, {1, 2}, {2, 0}, {2, 3}}),
+		b:    undirected([][2]int64{{0, 1}, {1, 2}, {2, 0}, {2, 3}}),
+		want: 0,
+	},
+	{
+		name: "isomorphic",
+		a:    undirected([][2]int64{{0, 1}, {1, 2}, {2, 0}, {2, 3}}),
+		b:    undirected([][2]int64{{13, 12}, {12, 10}, {10, 11}, {11, 12}}),
+		want: 0,
+	},
+	{
+		name: "extra edge",
+		a:    undirected([][2]int64{{0, 1}, {1, 2}, {2, 3}}),
+		b:    undirected([][2]int64{{0, 1}, {1, 2}, {2, 3}, {3, 0}}),
+		want: 1,
+	},
+	{
+		name: "extra node",
+		a:    undirected([][2]int64{{0, 1}, {1, 2}, {2, 3}, {1, 4}, {2, 4}}),
+		b:    undirected([][2]int64{{0, 1}, {1, 2}, {2, 3}}),
+		want: 3,
+	},
+	{
+		name: "empty",
+		a:    simple.NewUndirectedGraph(),
+		b:    undirected([][2]int64{{0, 1}, {1, 2}}),
+		want: 5,
+	},
+	{
+		name: "reversed edge",
+		a:    directed([][2]int64{{0, 1}, {1, 2}, {2, 0}}),
+		b:    directed([][2]int64{{0, 1}, {1, 2}, {0, 2}}),
+		want: 2,
+	},
+	{
+		name: "directed isomorphic",
+		a:    directed([][2]int64{{0, 1}, {1, 2}, {1, 3}}),
+		b:    directed([][2]int64{{7, 5}, {7, 6}, {4, 7}}),
+		want: 0,
+	},
+}
+
+func TestEditDistance(t *testing.T) {
+	for _, test := range editDistanceTests {
+		got, mapping := EditDistance(test.a, test.b, 1000)
+		if got != test.want {
+			t.Errorf("unexpected edit distance for %s: got:%v want:%v", test.name, got, test.want)
+		}
+		if cost := mappingCost(test.a, test.b, mapping); cost != got {
+			t.Errorf("unexpected cost of returned mapping for %s: got:%v want:%v", test.name, cost, got)
+		}
+	}
+	if !panics(func() { EditDistance(simple.NewUndirectedGraph(), simple.NewUndirectedGraph(), 0) }) {
+		t.Error("expected panic for non-positive beam width")
+	}
+}
+
+func TestEditDistanceBeam(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for trial := 0; trial < 10; trial++ {
+		a := simple.NewUndirectedGraph()
+		b := simple.NewUndirectedGraph()
+		for i := 0; i < 7; i++ {
+			a.AddNode(simple.Node(i))
+			b.AddNode(simple.Node(i))
+		}
+		for k := 0; k < 10; k++ {
+			u, v := rnd.Intn(7), rnd.Intn(7)
+			if u != v {
+				a.SetEdge(simple.Edge{F: simple.Node(u), T: simple.Node(v)})
+			}
+			u, v = rnd.Intn(7), rnd.Intn(7)
+			if u != v {
+				b.SetEdge(simple.Edge{F: simple.Node(u), T: simple.Node(v)})
+			}
+		}
+
+		// A beam retaining every partial assignment is exact.
+		exact, _ := EditDistance(a, b, 1<<20)
+		for _, beam := range []int{1, 5, 50, 1 << 20} {
+			got, mapping := EditDistance(a, b, beam)
+			if got < exact {
+				t.Errorf("edit distance with beam %d below exact distance: got:%v exact:%v", beam, got, exact)
+			}
+			if cost := mappingCost(a, b, mapping); cost != got {
+				t.Errorf("unexpected cost of returned mapping with beam %d: got:%v want:%v", beam, cost, got)
+			}
+		}
+		// The edit distance is bounded by deleting
+		// everything in a and inserting all of b.
+		bound := float64(len(a.Nodes()) + len(a.Edges()) + len(b.Nodes()) + len(b.Edges()))
+		if exact > bound {
+			t.Errorf("edit distance exceeds trivial bound: got:%v bound:%v", exact, bound)
+		}
+	}
+}
+
+// mappingCost returns the edit cost induced by a node mapping
+// between undirected or directed graphs a and b.
+func mappingCost(a, b graph.Graph, mapping map[int64]int64) float64 {
+	inverse := make(map[int64]int64)
+	for u, v := range mapping {
+		inverse[v] = u
+	}
+	cost := float64(len(a.Nodes()) - len(mapping) + len(b.Nodes()) - len(mapping))
+	directed := isDirected(a, b)
+	ea := edgeSet(a, directed)
+	eb := edgeSet(b, directed)
+	key := func(u, v int64) [2]int64 {
+		if !directed && v < u {
+			u, v = v, u
+		}
+		return [2]int64{u, v}
+	}
+	for e := range ea {
+		u, uok := mapping[e[0]]
+		v, vok := mapping[e[1]]
+		if !uok || !vok {
+			cost++
+			continue
+		}
+		if _, ok := eb[key(u, v)]; !ok {
+			cost++
+		}
+	}
+	for e := range eb {
+		u, uok := inverse[e[0]]
+		v, vok := inverse[e[1]]
+		if !uok || !vok {
+			cost++
+			continue
+		}
+		if _, ok := ea[key(u, v)]; !ok {
+			cost++
+		}
+	}
+	return cost
+}
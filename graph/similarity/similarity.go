@@ -0,0 +1,117 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package similarity
+
+import (
+	"math"
+	"sort"
+
+	"gonum.org/v1/gonum/graph"
+)
+
+// EdgeJaccard returns the Jaccard index of the edge sets of a and b,
+//  |E_a ∩ E_b| / |E_a ∪ E_b|,
+// where edges are identified by the IDs of their end points. Edge
+// direction is ignored if a and b are undirected. EdgeJaccard returns
+// 1 if neither graph has any edges. EdgeJaccard will panic if one of
+// a and b is directed and the other is not.
+func EdgeJaccard(a, b graph.Graph) float64 {
+	directed := isDirected(a, b)
+	ea := edgeSet(a, directed)
+	eb := edgeSet(b, directed)
+	if len(ea) == 0 && len(eb) == 0 {
+		return 1
+	}
+	var common int
+	for e := range ea {
+		if _, ok := eb[e]; ok {
+			common++
+		}
+	}
+	return float64(common) / float64(len(ea)+len(eb)-common)
+}
+
+// NodeJaccard returns the Jaccard index of the node ID sets of a and b.
+// NodeJaccard returns 1 if neither graph has any nodes.
+func NodeJaccard(a, b graph.Graph) float64 {
+	na := a.Nodes()
+	nb := b.Nodes()
+	if len(na) == 0 && len(nb) == 0 {
+		return 1
+	}
+	var common int
+	for _, n := range na {
+		if b.Has(n) {
+			common++
+		}
+	}
+	return float64(common) / float64(len(na)+len(nb)-common)
+}
+
+// DegreeDistance returns the L1 distance between the degree sequences of a
+// and b, each sorted in decreasing order and padded with zeros to the
+// length of the longer sequence. The degree of a node in a directed graph
+// is the sum of its in-degree and out-degree. DegreeDistance is zero for
+// isomorphic graphs, and is independent of node IDs. DegreeDistance will
+// panic if one of a and b is directed and the other is not.
+func DegreeDistance(a, b graph.Graph) float64 {
+	directed := isDirected(a, b)
+	da := degrees(a, directed)
+	db := degrees(b, directed)
+	if len(da) < len(db) {
+		da, db = db, da
+	}
+	var dist float64
+	for i, d := range da {
+		var e int
+		if i < len(db) {
+			e = db[i]
+		}
+		dist += math.Abs(float64(d - e))
+	}
+	return dist
+}
+
+// degrees returns the degree sequence of g sorted in decreasing order.
+func degrees(g graph.Graph, directed bool) []int {
+	nodes := g.Nodes()
+	deg := make([]int, len(nodes))
+	for i, n := range nodes {
+		deg[i] = len(g.From(n))
+		if directed {
+			deg[i] += len(g.(graph.Directed).To(n))
+		}
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(deg)))
+	return deg
+}
+
+// edgeSet returns the set of end point ID pairs of the edges of g. If
+// directed is false, the lower ID is held first.
+func edgeSet(g graph.Graph, directed bool) map[[2]int64]struct{} {
+	edges := make(map[[2]int64]struct{})
+	for _, u := range g.Nodes() {
+		uid := u.ID()
+		for _, v := range g.From(u) {
+			vid := v.ID()
+			if !directed && vid < uid {
+				continue
+			}
+			edges[[2]int64{uid, vid}] = struct{}{}
+		}
+	}
+	return edges
+}
+
+// isDirected returns whether a and b are directed graphs. It panics
+// if one is directed and the other is not.
+func isDirected(a, b graph.Graph) bool {
+	_, ad := a.(graph.Directed)
+	_, bd := b.(graph.Directed)
+	if ad != bd {
+		panic("similarity: mixed directed and undirected graphs")
+	}
+	return ad
+}
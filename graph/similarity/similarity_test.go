@@ -0,0 +1,103 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package similarity
+
+import (
+	"math"
+	"testing"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/simple"
+)
+
+// undirected returns an undirected graph with the given edges.
+func undirected(edges [][2]int64) *simple.UndirectedGraph {
+	g := simple.NewUndirectedGraph()
+	for _, e := range edges {
+		g.SetEdge(simple.Edge{F: simple.Node(e[0]), T: simple.Node(e[1])})
+	}
+	return g
+}
+
+// directed returns a directed graph with the given edges.
+func directed(edges [][2]int64) *simple.DirectedGraph {
+	g := simple.NewDirectedGraph()
+	for _, e := range edges {
+		g.SetEdge(simple.Edge{F: simple.Node(e[0]), T: simple.Node(e[1])})
+	}
+	return g
+}
+
+func TestEdgeJaccard(t *testing.T) {
+	for i, test := range []struct {
+		a, b graph.Graph
+		want float64
+	}{
+		{a: simple.NewUndirectedGraph(), b: simple.NewUndirectedGraph(), want: 1},
+		{
+			a:    undirected([][2]int64{{0, 1}, {1, 2}, {2, 3}}),
+			b:    undirected([][2]int64{{1, 0}, {2, 1}, {3, 2}}),
+			want: 1,
+		},
+		{
+			a:    undirected([][2]int64{{0, 1}, {1, 2}, {2, 3}}),
+			b:    undirected([][2]int64{{0, 1}, {1, 2}, {2, 4}}),
+			want: 0.5,
+		},
+		{
+			a:    directed([][2]int64{{0, 1}, {1, 2}}),
+			b:    directed([][2]int64{{1, 0}, {1, 2}}),
+			want: 1.0 / 3,
+		},
+	} {
+		if got := EdgeJaccard(test.a, test.b); math.Abs(got-test.want) > 1e-15 {
+			t.Errorf("unexpected edge Jaccard index for test %d: got:%v want:%v", i, got, test.want)
+		}
+	}
+	if !panics(func() { EdgeJaccard(simple.NewUndirectedGraph(), simple.NewDirectedGraph()) }) {
+		t.Error("expected panic for mixed graphs")
+	}
+}
+
+func TestNodeJaccard(t *testing.T) {
+	a := undirected([][2]int64{{0, 1}, {1, 2}})
+	b := undirected([][2]int64{{1, 2}, {2, 3}})
+	if got := NodeJaccard(a, b); got != 0.5 {
+		t.Errorf("unexpected node Jaccard index: got:%v want:0.5", got)
+	}
+	if got := NodeJaccard(simple.NewUndirectedGraph(), simple.NewDirectedGraph()); got != 1 {
+		t.Errorf("unexpected node Jaccard index for empty graphs: got:%v want:1", got)
+	}
+}
+
+func TestDegreeDistance(t *testing.T) {
+	path := undirected([][2]int64{{0, 1}, {1, 2}, {2, 3}})
+	relabeled := undirected([][2]int64{{10, 12}, {12, 11}, {11, 13}})
+	star := undirected([][2]int64{{0, 1}, {0, 2}, {0, 3}})
+	if got := DegreeDistance(path, relabeled); got != 0 {
+		t.Errorf("unexpected distance between isomorphic graphs: got:%v want:0", got)
+	}
+	// Degree sequences 3,1,1,1 and 2,2,1,1.
+	if got := DegreeDistance(star, path); got != 2 {
+		t.Errorf("unexpected distance between star and path: got:%v want:2", got)
+	}
+	// Degree sequences 2,2,1,1 and 2,1,1,0,0.
+	short := undirected([][2]int64{{0, 1}, {1, 2}})
+	if got := DegreeDistance(path, short); got != 2 {
+		t.Errorf("unexpected distance between paths: got:%v want:2", got)
+	}
+	// Total degree sequences 2,1,1 and 2,1,1.
+	if got := DegreeDistance(directed([][2]int64{{0, 1}, {1, 2}}), directed([][2]int64{{1, 0}, {1, 2}})); got != 0 {
+		t.Errorf("unexpected distance between directed graphs: got:%v want:0", got)
+	}
+}
+
+func panics(fn func()) (ok bool) {
+	defer func() {
+		ok = recover() != nil
+	}()
+	fn()
+	return
+}
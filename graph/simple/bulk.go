@@ -0,0 +1,310 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package simple
+
+import (
+	"fmt"
+
+	"gonum.org/v1/gonum/graph"
+)
+
+// The storage hints below size the node maps of an empty graph and the
+// adjacency maps of nodes added after the hint is given. Go maps cannot be
+// grown without copying, so the storage of nodes already in the graph is
+// left untouched.
+
+// ReserveNodes indicates that n more nodes will be added to the graph. If
+// the graph has no nodes, its node storage is allocated to hold them.
+func (g *DirectedGraph) ReserveNodes(n int) {
+	if n <= 0 {
+		return
+	}
+	if len(g.nodes) == 0 && n > g.reserved {
+		g.nodes = make(map[int64]graph.Node, n)
+		g.from = make(map[int64]map[int64]graph.Edge, n)
+		g.to = make(map[int64]map[int64]graph.Edge, n)
+	}
+	if len(g.nodes)+n > g.reserved {
+		g.reserved = len(g.nodes) + n
+	}
+}
+
+// ReserveEdges indicates that n more edges will be added to the graph. The
+// adjacency storage of nodes added after the call is sized for the n edges
+// spread evenly over the nodes in the graph or reserved by ReserveNodes, so
+// ReserveEdges should be called after ReserveNodes.
+func (g *DirectedGraph) ReserveEdges(n int) {
+	nodes := g.reserved
+	if len(g.nodes) > nodes {
+		nodes = len(g.nodes)
+	}
+	if n <= 0 || nodes == 0 {
+		return
+	}
+	g.degree = (n + nodes - 1) / nodes
+}
+
+// AddNodes adds the nodes to the graph. It panics if the ID of any of the
+// nodes matches an existing node ID or the ID of another node in nodes, in
+// which case the graph is not modified.
+func (g *DirectedGraph) AddNodes(nodes []graph.Node) {
+	checkNewNodes(g.nodes, nodes)
+	g.ReserveNodes(len(nodes))
+	for _, n := range nodes {
+		g.AddNode(n)
+	}
+}
+
+// SetEdges adds the edges to the graph. If the nodes of an edge do not exist,
+// they are added. SetEdges is equivalent to calling SetEdge for each edge in
+// order. It will panic if the IDs of the From and To nodes of any edge are
+// equal, in which case the graph is not modified.
+func (g *DirectedGraph) SetEdges(edges []graph.Edge) {
+	for _, e := range edges {
+		if e.From().ID() == e.To().ID() {
+			panic("simple: adding self edge")
+		}
+	}
+	for _, e := range edges {
+		from, to := e.From(), e.To()
+		fid, tid := from.ID(), to.ID()
+		fm, ok := g.from[fid]
+		if !ok {
+			g.AddNode(from)
+			fm = g.from[fid]
+		}
+		tm, ok := g.to[tid]
+		if !ok {
+			g.AddNode(to)
+			tm = g.to[tid]
+		}
+		fm[tid] = e
+		tm[fid] = e
+	}
+}
+
+// ReserveNodes indicates that n more nodes will be added to the graph. If
+// the graph has no nodes, its node storage is allocated to hold them.
+func (g *UndirectedGraph) ReserveNodes(n int) {
+	if n <= 0 {
+		return
+	}
+	if len(g.nodes) == 0 && n > g.reserved {
+		g.nodes = make(map[int64]graph.Node, n)
+		g.edges = make(map[int64]map[int64]graph.Edge, n)
+	}
+	if len(g.nodes)+n > g.reserved {
+		g.reserved = len(g.nodes) + n
+	}
+}
+
+// ReserveEdges indicates that n more edges will be added to the graph. The
+// adjacency storage of nodes added after the call is sized for the n edges
+// spread evenly over the nodes in the graph or reserved by ReserveNodes, so
+// ReserveEdges should be called after ReserveNodes.
+func (g *UndirectedGraph) ReserveEdges(n int) {
+	nodes := g.reserved
+	if len(g.nodes) > nodes {
+		nodes = len(g.nodes)
+	}
+	if n <= 0 || nodes == 0 {
+		return
+	}
+	g.degree = (2*n + nodes - 1) / nodes
+}
+
+// AddNodes adds the nodes to the graph. It panics if the ID of any of the
+// nodes matches an existing node ID or the ID of another node in nodes, in
+// which case the graph is not modified.
+func (g *UndirectedGraph) AddNodes(nodes []graph.Node) {
+	checkNewNodes(g.nodes, nodes)
+	g.ReserveNodes(len(nodes))
+	for _, n := range nodes {
+		g.AddNode(n)
+	}
+}
+
+// SetEdges adds the edges to the graph. If the nodes of an edge do not exist,
+// they are added. SetEdges is equivalent to calling SetEdge for each edge in
+// order. It will panic if the IDs of the From and To nodes of any edge are
+// equal, in which case the graph is not modified.
+func (g *UndirectedGraph) SetEdges(edges []graph.Edge) {
+	for _, e := range edges {
+		if e.From().ID() == e.To().ID() {
+			panic("simple: adding self edge")
+		}
+	}
+	for _, e := range edges {
+		from, to := e.From(), e.To()
+		fid, tid := from.ID(), to.ID()
+		fm, ok := g.edges[fid]
+		if !ok {
+			g.AddNode(from)
+			fm = g.edges[fid]
+		}
+		tm, ok := g.edges[tid]
+		if !ok {
+			g.AddNode(to)
+			tm = g.edges[tid]
+		}
+		fm[tid] = e
+		tm[fid] = e
+	}
+}
+
+// ReserveNodes indicates that n more nodes will be added to the graph. If
+// the graph has no nodes, its node storage is allocated to hold them.
+func (g *WeightedDirectedGraph) ReserveNodes(n int) {
+	if n <= 0 {
+		return
+	}
+	if len(g.nodes) == 0 && n > g.reserved {
+		g.nodes = make(map[int64]graph.Node, n)
+		g.from = make(map[int64]map[int64]graph.WeightedEdge, n)
+		g.to = make(map[int64]map[int64]graph.WeightedEdge, n)
+	}
+	if len(g.nodes)+n > g.reserved {
+		g.reserved = len(g.nodes) + n
+	}
+}
+
+// ReserveEdges indicates that n more edges will be added to the graph. The
+// adjacency storage of nodes added after the call is sized for the n edges
+// spread evenly over the nodes in the graph or reserved by ReserveNodes, so
+// ReserveEdges should be called after ReserveNodes.
+func (g *WeightedDirectedGraph) ReserveEdges(n int) {
+	nodes := g.reserved
+	if len(g.nodes) > nodes {
+		nodes = len(g.nodes)
+	}
+	if n <= 0 || nodes == 0 {
+		return
+	}
+	g.degree = (n + nodes - 1) / nodes
+}
+
+// AddNodes adds the nodes to the graph. It panics if the ID of any of the
+// nodes matches an existing node ID or the ID of another node in nodes, in
+// which case the graph is not modified.
+func (g *WeightedDirectedGraph) AddNodes(nodes []graph.Node) {
+	checkNewNodes(g.nodes, nodes)
+	g.ReserveNodes(len(nodes))
+	for _, n := range nodes {
+		g.AddNode(n)
+	}
+}
+
+// SetWeightedEdges adds the weighted edges to the graph. If the nodes of an
+// edge do not exist, they are added. SetWeightedEdges is equivalent to calling
+// SetWeightedEdge for each edge in order. It will panic if the IDs of the From
+// and To nodes of any edge are equal, in which case the graph is not modified.
+func (g *WeightedDirectedGraph) SetWeightedEdges(edges []graph.WeightedEdge) {
+	for _, e := range edges {
+		if e.From().ID() == e.To().ID() {
+			panic("simple: adding self edge")
+		}
+	}
+	for _, e := range edges {
+		from, to := e.From(), e.To()
+		fid, tid := from.ID(), to.ID()
+		fm, ok := g.from[fid]
+		if !ok {
+			g.AddNode(from)
+			fm = g.from[fid]
+		}
+		tm, ok := g.to[tid]
+		if !ok {
+			g.AddNode(to)
+			tm = g.to[tid]
+		}
+		fm[tid] = e
+		tm[fid] = e
+	}
+}
+
+// ReserveNodes indicates that n more nodes will be added to the graph. If
+// the graph has no nodes, its node storage is allocated to hold them.
+func (g *WeightedUndirectedGraph) ReserveNodes(n int) {
+	if n <= 0 {
+		return
+	}
+	if len(g.nodes) == 0 && n > g.reserved {
+		g.nodes = make(map[int64]graph.Node, n)
+		g.edges = make(map[int64]map[int64]graph.WeightedEdge, n)
+	}
+	if len(g.nodes)+n > g.reserved {
+		g.reserved = len(g.nodes) + n
+	}
+}
+
+// ReserveEdges indicates that n more edges will be added to the graph. The
+// adjacency storage of nodes added after the call is sized for the n edges
+// spread evenly over the nodes in the graph or reserved by ReserveNodes, so
+// ReserveEdges should be called after ReserveNodes.
+func (g *WeightedUndirectedGraph) ReserveEdges(n int) {
+	nodes := g.reserved
+	if len(g.nodes) > nodes {
+		nodes = len(g.nodes)
+	}
+	if n <= 0 || nodes == 0 {
+		return
+	}
+	g.degree = (2*n + nodes - 1) / nodes
+}
+
+// AddNodes adds the nodes to the graph. It panics if the ID of any of the
+// nodes matches an existing node ID or the ID of another node in nodes, in
+// which case the graph is not modified.
+func (g *WeightedUndirectedGraph) AddNodes(nodes []graph.Node) {
+	checkNewNodes(g.nodes, nodes)
+	g.ReserveNodes(len(nodes))
+	for _, n := range nodes {
+		g.AddNode(n)
+	}
+}
+
+// SetWeightedEdges adds the weighted edges to the graph. If the nodes of an
+// edge do not exist, they are added. SetWeightedEdges is equivalent to calling
+// SetWeightedEdge for each edge in order. It will panic if the IDs of the From
+// and To nodes of any edge are equal, in which case the graph is not modified.
+func (g *WeightedUndirectedGraph) SetWeightedEdges(edges []graph.WeightedEdge) {
+	for _, e := range edges {
+		if e.From().ID() == e.To().ID() {
+			panic("simple: adding self edge")
+		}
+	}
+	for _, e := range edges {
+		from, to := e.From(), e.To()
+		fid, tid := from.ID(), to.ID()
+		fm, ok := g.edges[fid]
+		if !ok {
+			g.AddNode(from)
+			fm = g.edges[fid]
+		}
+		tm, ok := g.edges[tid]
+		if !ok {
+			g.AddNode(to)
+			tm = g.edges[tid]
+		}
+		fm[tid] = e
+		tm[fid] = e
+	}
+}
+
+// checkNewNodes panics if any of the nodes has an ID in nodes or
+// an ID held by another node in the batch.
+func checkNewNodes(nodes map[int64]graph.Node, batch []graph.Node) {
+	seen := make(map[int64]struct{}, len(batch))
+	for _, n := range batch {
+		id := n.ID()
+		if _, exists := nodes[id]; exists {
+			panic(fmt.Sprintf("simple: node ID collision: %d", id))
+		}
+		if _, exists := seen[id]; exists {
+			panic(fmt.Sprintf("simple: node ID collision: %d", id))
+		}
+		seen[id] = struct{}{}
+	}
+}
@@ -0,0 +1,172 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package simple
+
+import (
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/graph"
+)
+
+// randomEdges returns n edges between nodes with IDs in [0, nodes).
+func randomEdges(n, nodes int, src rand.Source) []graph.WeightedEdge {
+	rnd := rand.New(src)
+	edges := make([]graph.WeightedEdge, 0, n)
+	for len(edges) < n {
+		u, v := rnd.Intn(nodes), rnd.Intn(nodes)
+		if u == v {
+			continue
+		}
+		edges = append(edges, WeightedEdge{F: Node(u), T: Node(v), W: float64(len(edges))})
+	}
+	return edges
+}
+
+func TestBulkDirected(t *testing.T) {
+	edges := randomEdges(500, 100, rand.NewSource(1))
+	plain := make([]graph.Edge, len(edges))
+	for i, e := range edges {
+		plain[i] = Edge{F: e.From(), T: e.To()}
+	}
+
+	want := NewDirectedGraph()
+	got := NewDirectedGraph()
+	for _, n := range []graph.Node{Node(200), Node(201)} {
+		want.AddNode(n)
+	}
+	got.AddNodes([]graph.Node{Node(200), Node(201)})
+	for _, e := range plain {
+		want.SetEdge(e)
+	}
+	got.SetEdges(plain[:10])
+	got.SetEdges(plain[10:])
+	if !graph.Equal(got, want) {
+		t.Error("unexpected directed graph from bulk construction")
+	}
+
+	wwant := NewWeightedDirectedGraph(0, 0)
+	wgot := NewWeightedDirectedGraph(0, 0)
+	wgot.ReserveNodes(100)
+	for _, e := range edges {
+		wwant.SetWeightedEdge(e)
+	}
+	wgot.SetWeightedEdges(edges)
+	if !graph.Equal(wgot, wwant) {
+		t.Error("unexpected weighted directed graph from bulk construction")
+	}
+	for _, e := range wwant.WeightedEdges() {
+		if w, _ := wgot.Weight(e.From(), e.To()); w != e.Weight() {
+			t.Errorf("unexpected weight for edge %d-%d: got:%v want:%v", e.From().ID(), e.To().ID(), w, e.Weight())
+		}
+	}
+}
+
+func TestBulkUndirected(t *testing.T) {
+	edges := randomEdges(500, 100, rand.NewSource(1))
+	plain := make([]graph.Edge, len(edges))
+	for i, e := range edges {
+		plain[i] = Edge{F: e.From(), T: e.To()}
+	}
+
+	want := NewUndirectedGraph()
+	got := NewUndirectedGraph()
+	got.ReserveNodes(100)
+	got.ReserveEdges(len(plain))
+	for _, e := range plain {
+		want.SetEdge(e)
+	}
+	got.SetEdges(plain)
+	if !graph.Equal(got, want) {
+		t.Error("unexpected undirected graph from bulk construction")
+	}
+
+	wwant := NewWeightedUndirectedGraph(0, 0)
+	wgot := NewWeightedUndirectedGraph(0, 0)
+	wgot.AddNodes([]graph.Node{Node(0), Node(1)})
+	wwant.AddNode(Node(0))
+	wwant.AddNode(Node(1))
+	for _, e := range edges {
+		wwant.SetWeightedEdge(e)
+	}
+	wgot.SetWeightedEdges(edges[:250])
+	wgot.SetWeightedEdges(edges[250:])
+	if !graph.Equal(wgot, wwant) {
+		t.Error("unexpected weighted undirected graph from bulk construction")
+	}
+	for _, e := range wwant.WeightedEdges() {
+		if w, _ := wgot.Weight(e.From(), e.To()); w != e.Weight() {
+			t.Errorf("unexpected weight for edge %d-%d: got:%v want:%v", e.From().ID(), e.To().ID(), w, e.Weight())
+		}
+	}
+}
+
+func TestBulkPanics(t *testing.T) {
+	g := NewDirectedGraph()
+	g.AddNode(Node(0))
+	for _, nodes := range [][]graph.Node{
+		{Node(1), Node(0)},
+		{Node(1), Node(2), Node(1)},
+	} {
+		if !panics(func() { g.AddNodes(nodes) }) {
+			t.Errorf("expected panic for node collision in %v", nodes)
+		}
+		if len(g.Nodes()) != 1 {
+			t.Errorf("graph modified by failed AddNodes: %v", g.Nodes())
+		}
+	}
+	if !panics(func() { g.SetEdges([]graph.Edge{Edge{F: Node(1), T: Node(2)}, Edge{F: Node(3), T: Node(3)}}) }) {
+		t.Error("expected panic for self edge")
+	}
+	if len(g.Nodes()) != 1 {
+		t.Errorf("graph modified by failed SetEdges: %v", g.Nodes())
+	}
+}
+
+func BenchmarkSetEdge(b *testing.B) {
+	edges := randomEdges(1e5, 1e4, rand.NewSource(1))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		g := NewWeightedDirectedGraph(0, 0)
+		for _, e := range edges {
+			g.SetWeightedEdge(e)
+		}
+	}
+}
+
+func BenchmarkSetWeightedEdges(b *testing.B) {
+	edges := randomEdges(1e5, 1e4, rand.NewSource(1))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		g := NewWeightedDirectedGraph(0, 0)
+		g.ReserveNodes(1e4)
+		g.ReserveEdges(len(edges))
+		g.SetWeightedEdges(edges)
+	}
+}
+
+func BenchmarkAddNodeRandomIDs(b *testing.B) {
+	rnd := rand.New(rand.NewSource(1))
+	nodes := make([]graph.Node, 2e5)
+	for i := range nodes {
+		nodes[i] = Node(rnd.Int63())
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		g := NewDirectedGraph()
+		for _, n := range nodes {
+			g.AddNode(n)
+		}
+	}
+}
+
+func panics(fn func()) (ok bool) {
+	defer func() {
+		ok = recover() != nil
+	}()
+	fn()
+	return
+}
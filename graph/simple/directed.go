@@ -8,7 +8,7 @@ import (
 	"fmt"
 
 	"gonum.org/v1/gonum/graph"
-	"gonum.org/v1/gonum/graph/internal/uid"
+	"gonum.org/v1/gonum/graph/uid"
 )
 
 // DirectedGraph implements a generalized directed graph.
@@ -18,6 +18,11 @@ type DirectedGraph struct {
 	to    map[int64]map[int64]graph.Edge
 
 	nodeIDs uid.Set
+
+	// reserved and degree hold the node and
+	// adjacency storage hints given by
+	// ReserveNodes and ReserveEdges.
+	reserved, degree int
 }
 
 // NewDirectedGraph returns a DirectedGraph.
@@ -49,8 +54,8 @@ func (g *DirectedGraph) AddNode(n graph.Node) {
 		panic(fmt.Sprintf("simple: node ID collision: %d", n.ID()))
 	}
 	g.nodes[n.ID()] = n
-	g.from[n.ID()] = make(map[int64]graph.Edge)
-	g.to[n.ID()] = make(map[int64]graph.Edge)
+	g.from[n.ID()] = make(map[int64]graph.Edge, g.degree)
+	g.to[n.ID()] = make(map[int64]graph.Edge, g.degree)
 	g.nodeIDs.Use(n.ID())
 }
 
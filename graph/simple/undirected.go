@@ -8,7 +8,7 @@ import (
 	"fmt"
 
 	"gonum.org/v1/gonum/graph"
-	"gonum.org/v1/gonum/graph/internal/uid"
+	"gonum.org/v1/gonum/graph/uid"
 )
 
 // UndirectedGraph implements a generalized undirected graph.
@@ -17,6 +17,11 @@ type UndirectedGraph struct {
 	edges map[int64]map[int64]graph.Edge
 
 	nodeIDs uid.Set
+
+	// reserved and degree hold the node and
+	// adjacency storage hints given by
+	// ReserveNodes and ReserveEdges.
+	reserved, degree int
 }
 
 // NewUndirectedGraph returns an UndirectedGraph.
@@ -47,7 +52,7 @@ func (g *UndirectedGraph) AddNode(n graph.Node) {
 		panic(fmt.Sprintf("simple: node ID collision: %d", n.ID()))
 	}
 	g.nodes[n.ID()] = n
-	g.edges[n.ID()] = make(map[int64]graph.Edge)
+	g.edges[n.ID()] = make(map[int64]graph.Edge, g.degree)
 	g.nodeIDs.Use(n.ID())
 }
 
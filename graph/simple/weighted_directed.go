@@ -8,7 +8,7 @@ import (
 	"fmt"
 
 	"gonum.org/v1/gonum/graph"
-	"gonum.org/v1/gonum/graph/internal/uid"
+	"gonum.org/v1/gonum/graph/uid"
 )
 
 // WeightedDirectedGraph implements a generalized weighted directed graph.
@@ -20,6 +20,11 @@ type WeightedDirectedGraph struct {
 	self, absent float64
 
 	nodeIDs uid.Set
+
+	// reserved and degree hold the node and
+	// adjacency storage hints given by
+	// ReserveNodes and ReserveEdges.
+	reserved, degree int
 }
 
 // NewWeightedDirectedGraph returns a WeightedDirectedGraph with the specified self and absent
@@ -55,8 +60,8 @@ func (g *WeightedDirectedGraph) AddNode(n graph.Node) {
 		panic(fmt.Sprintf("simple: node ID collision: %d", n.ID()))
 	}
 	g.nodes[n.ID()] = n
-	g.from[n.ID()] = make(map[int64]graph.WeightedEdge)
-	g.to[n.ID()] = make(map[int64]graph.WeightedEdge)
+	g.from[n.ID()] = make(map[int64]graph.WeightedEdge, g.degree)
+	g.to[n.ID()] = make(map[int64]graph.WeightedEdge, g.degree)
 	g.nodeIDs.Use(n.ID())
 }
 
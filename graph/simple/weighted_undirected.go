@@ -8,7 +8,7 @@ import (
 	"fmt"
 
 	"gonum.org/v1/gonum/graph"
-	"gonum.org/v1/gonum/graph/internal/uid"
+	"gonum.org/v1/gonum/graph/uid"
 )
 
 // WeightedUndirectedGraph implements a generalized weighted undirected graph.
@@ -19,6 +19,11 @@ type WeightedUndirectedGraph struct {
 	self, absent float64
 
 	nodeIDs uid.Set
+
+	// reserved and degree hold the node and
+	// adjacency storage hints given by
+	// ReserveNodes and ReserveEdges.
+	reserved, degree int
 }
 
 // NewWeightedUndirectedGraph returns an WeightedUndirectedGraph with the specified self and absent
@@ -53,7 +58,7 @@ func (g *WeightedUndirectedGraph) AddNode(n graph.Node) {
 		panic(fmt.Sprintf("simple: node ID collision: %d", n.ID()))
 	}
 	g.nodes[n.ID()] = n
-	g.edges[n.ID()] = make(map[int64]graph.WeightedEdge)
+	g.edges[n.ID()] = make(map[int64]graph.WeightedEdge, g.degree)
 	g.nodeIDs.Use(n.ID())
 }
 
@@ -0,0 +1,169 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package spectral
+
+import (
+	"math"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/mat"
+)
+
+// Cluster partitions the nodes of the undirected graph g into k clusters by
+// normalized spectral clustering, returning the cluster of each node,
+// numbered from zero, and whether the eigendecomposition of the Laplacian
+// succeeded. The nodes are embedded in k dimensions using the eigenvectors
+// of the k smallest eigenvalues of the normalized Laplacian of g, each
+// embedded point is scaled to unit length, and the points are clustered by
+// k-means with k-means++ seeding, keeping the best of several restarts.
+//
+// If g implements graph.Weighted, edge weights are used and must be
+// non-negative, otherwise each edge has unit weight. Self edges are ignored.
+// If src is not nil it is used as the random source, otherwise the global
+// random source is used. Cluster will panic if k is less than 1 or greater
+// than the number of nodes of g.
+//
+// See Ng, A. Y., Jordan, M. I. and Weiss, Y. "On spectral clustering:
+// Analysis and an algorithm." Advances in Neural Information Processing
+// Systems 14:849–856 (2002).
+func Cluster(g graph.Undirected, k int, src *rand.Rand) (clusters map[int64]int, ok bool) {
+	l := NormalizedLaplacian(g)
+	n := len(l.Nodes)
+	if k < 1 || n < k {
+		panic("spectral: bad number of clusters")
+	}
+
+	var eig mat.EigenSym
+	if !eig.Factorize(l.Matrix.(*mat.SymDense), true) {
+		return nil, false
+	}
+	var vecs mat.Dense
+	vecs.EigenvectorsSym(&eig)
+
+	// Eigenvalues are in ascending order so the
+	// first k columns span the embedding.
+	x := mat.NewDense(n, k, nil)
+	x.Copy(vecs.Slice(0, n, 0, k))
+	for i := 0; i < n; i++ {
+		row := x.RawRowView(i)
+		if norm := floats.Norm(row, 2); norm != 0 {
+			floats.Scale(1/norm, row)
+		}
+	}
+
+	const restarts = 10
+	labels := kmeans(x, k, restarts, src)
+	clusters = make(map[int64]int, n)
+	for i, c := range labels {
+		clusters[l.Nodes[i].ID()] = c
+	}
+	return clusters, true
+}
+
+// kmeans returns the cluster of each row of x in the lowest within-cluster
+// sum of squares clustering into k clusters found by Lloyd's algorithm over
+// the given number of k-means++ seeded restarts.
+func kmeans(x *mat.Dense, k, restarts int, src *rand.Rand) []int {
+	uniform, intn := rand.Float64, rand.Intn
+	if src != nil {
+		uniform, intn = src.Float64, src.Intn
+	}
+	n, d := x.Dims()
+	var best []int
+	bestCost := math.Inf(1)
+	centers := mat.NewDense(k, d, nil)
+	labels := make([]int, n)
+	dist := make([]float64, n)
+	sums := mat.NewDense(k, d, nil)
+	count := make([]int, k)
+	for r := 0; r < restarts; r++ {
+		// Choose the initial centers by k-means++ seeding,
+		// each new center sampled with probability proportional
+		// to its squared distance to the nearest center.
+		centers.SetRow(0, x.RawRowView(intn(n)))
+		for i := range dist {
+			dist[i] = sqDist(x.RawRowView(i), centers.RawRowView(0))
+		}
+		for c := 1; c < k; c++ {
+			next := n - 1
+			if sum := floats.Sum(dist); sum > 0 {
+				u := uniform() * sum
+				for i, v := range dist {
+					u -= v
+					if u < 0 {
+						next = i
+						break
+					}
+				}
+			} else {
+				next = intn(n)
+			}
+			centers.SetRow(c, x.RawRowView(next))
+			for i := range dist {
+				dist[i] = math.Min(dist[i], sqDist(x.RawRowView(i), centers.RawRowView(c)))
+			}
+		}
+
+		// Lloyd iterations.
+		for i := range labels {
+			labels[i] = -1
+		}
+		var cost float64
+		for iter := 0; iter < 100; iter++ {
+			changed := false
+			cost = 0
+			for i := range labels {
+				row := x.RawRowView(i)
+				nearest, min := 0, math.Inf(1)
+				for c := 0; c < k; c++ {
+					if dc := sqDist(row, centers.RawRowView(c)); dc < min {
+						nearest, min = c, dc
+					}
+				}
+				if labels[i] != nearest {
+					labels[i] = nearest
+					changed = true
+				}
+				cost += min
+			}
+			if !changed {
+				break
+			}
+			// Move each center to the mean of its points,
+			// leaving the centers of empty clusters in place.
+			for c := range count {
+				count[c] = 0
+				floats.Scale(0, sums.RawRowView(c))
+			}
+			for i, c := range labels {
+				floats.Add(sums.RawRowView(c), x.RawRowView(i))
+				count[c]++
+			}
+			for c, m := range count {
+				if m != 0 {
+					center := centers.RawRowView(c)
+					copy(center, sums.RawRowView(c))
+					floats.Scale(1/float64(m), center)
+				}
+			}
+		}
+		if cost < bestCost {
+			best, bestCost = append(best[:0], labels...), cost
+		}
+	}
+	return best
+}
+
+// sqDist returns the squared Euclidean distance between a and b.
+func sqDist(a, b []float64) float64 {
+	var d float64
+	for i, v := range a {
+		d += (v - b[i]) * (v - b[i])
+	}
+	return d
+}
@@ -0,0 +1,130 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package spectral
+
+import (
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/graphs/gen"
+	"gonum.org/v1/gonum/graph/simple"
+)
+
+func TestCluster(t *testing.T) {
+	for _, test := range []struct {
+		sizes []int
+		in    float64
+		out   float64
+		seed  uint64
+	}{
+		{sizes: []int{10, 10}, in: 1, out: 0.05, seed: 1},
+		{sizes: []int{20, 30, 25}, in: 0.5, out: 0.02, seed: 2},
+		{sizes: []int{15, 15, 15, 15}, in: 0.6, out: 0.03, seed: 3},
+	} {
+		rnd := rand.New(rand.NewSource(test.seed))
+		k := len(test.sizes)
+		p := make([][]float64, k)
+		for i := range p {
+			p[i] = make([]float64, k)
+			for j := range p[i] {
+				p[i][j] = test.out
+			}
+			p[i][i] = test.in
+		}
+		g := simple.NewUndirectedGraph()
+		err := gen.StochasticBlockModel(g, test.sizes, p, rnd)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		block := make(map[int64]int)
+		var id int64
+		for b, s := range test.sizes {
+			for i := 0; i < s; i++ {
+				block[id] = b
+				id++
+			}
+		}
+
+		clusters, ok := Cluster(g, k, rnd)
+		if !ok {
+			t.Fatalf("unexpected failure for sizes %v", test.sizes)
+		}
+		checkClusters(t, g, clusters, block, k)
+	}
+
+	g := simple.NewUndirectedGraph()
+	g.AddNode(simple.Node(0))
+	for _, k := range []int{0, 2} {
+		if !panics(func() { Cluster(g, k, nil) }) {
+			t.Errorf("expected panic for k=%d", k)
+		}
+	}
+}
+
+func TestClusterComponents(t *testing.T) {
+	// Disconnected cliques of different sizes
+	// are always separated.
+	g := simple.NewWeightedUndirectedGraph(0, 0)
+	block := make(map[int64]int)
+	var id int64
+	for b, s := range []int{3, 5, 8} {
+		first := id
+		for i := 0; i < s; i++ {
+			block[id] = b
+			for j := first; j < id; j++ {
+				g.SetWeightedEdge(simple.WeightedEdge{F: simple.Node(j), T: simple.Node(id), W: float64(b + 1)})
+			}
+			id++
+		}
+	}
+	for seed := uint64(0); seed < 5; seed++ {
+		clusters, ok := Cluster(g, 3, rand.New(rand.NewSource(seed)))
+		if !ok {
+			t.Fatalf("unexpected failure for seed %d", seed)
+		}
+		checkClusters(t, g, clusters, block, 3)
+	}
+}
+
+// checkClusters checks that clusters is the same partition of the nodes of g
+// into k clusters as block up to relabeling.
+func checkClusters(t *testing.T, g graph.Graph, clusters, block map[int64]int, k int) {
+	if len(clusters) != len(g.Nodes()) {
+		t.Errorf("unexpected number of clustered nodes: got:%d want:%d", len(clusters), len(g.Nodes()))
+	}
+	label := make(map[int]int)
+	used := make(map[int]bool)
+	for id, b := range block {
+		c, ok := clusters[id]
+		if !ok || c < 0 || k <= c {
+			t.Errorf("invalid cluster for node %d: %d", id, c)
+			continue
+		}
+		l, ok := label[b]
+		if !ok {
+			if used[c] {
+				t.Errorf("cluster %d contains nodes of more than one block", c)
+				return
+			}
+			label[b] = c
+			used[c] = true
+			continue
+		}
+		if l != c {
+			t.Errorf("block %d split across clusters %d and %d", b, l, c)
+			return
+		}
+	}
+}
+
+func panics(fn func()) (ok bool) {
+	defer func() {
+		ok = recover() != nil
+	}()
+	fn()
+	return
+}
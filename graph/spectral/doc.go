@@ -0,0 +1,12 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package spectral provides matrix representations of graphs and spectral
+// graph algorithms.
+//
+// The functions in this package construct the adjacency, degree and
+// Laplacian matrices of a graph as mat.Matrix values so that the linear
+// algebra routines of the mat package can be applied to graphs, and use the
+// spectra of those matrices to cluster the nodes of a graph.
+package spectral // import "gonum.org/v1/gonum/graph/spectral"
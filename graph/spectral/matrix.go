@@ -0,0 +1,172 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package spectral
+
+import (
+	"math"
+	"sort"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/internal/ordered"
+	"gonum.org/v1/gonum/mat"
+)
+
+// GraphMatrix is a matrix representation of a graph.
+type GraphMatrix struct {
+	// Matrix holds the matrix. For undirected
+	// graphs the matrix is a *mat.SymDense.
+	mat.Matrix
+
+	// Nodes holds the graph nodes in
+	// row and column order, sorted by ID.
+	Nodes []graph.Node
+
+	// Index is a mapping from the graph
+	// node IDs to row and column indices.
+	Index map[int64]int
+}
+
+// Adjacency returns the adjacency matrix of g. The element at row i and column
+// j is the weight of the edge from Nodes[i] to Nodes[j], or zero if there is
+// no such edge. If g implements graph.Weighted, edge weights are used,
+// otherwise each edge has unit weight. If g is a graph.Directed the matrix is
+// a *mat.Dense, otherwise it is a symmetric *mat.SymDense. Self edges are
+// ignored.
+func Adjacency(g graph.Graph) GraphMatrix {
+	nodes, index := indexNodes(g)
+	weight := weightFunc(g)
+	n := len(nodes)
+	if _, ok := g.(graph.Directed); ok {
+		a := mat.NewDense(n, n, nil)
+		for i, u := range nodes {
+			for _, v := range g.From(u) {
+				if v.ID() != u.ID() {
+					a.Set(i, index[v.ID()], weight(u, v))
+				}
+			}
+		}
+		return GraphMatrix{Matrix: a, Nodes: nodes, Index: index}
+	}
+	a := mat.NewSymDense(n, nil)
+	for i, u := range nodes {
+		for _, v := range g.From(u) {
+			if j := index[v.ID()]; i < j {
+				a.SetSym(i, j, weight(u, v))
+			}
+		}
+	}
+	return GraphMatrix{Matrix: a, Nodes: nodes, Index: index}
+}
+
+// Degree returns the diagonal degree matrix of g as a *mat.SymDense. The
+// diagonal element for each node is the total weight of the edges from the
+// node, its out-degree when g is a graph.Directed. If g implements
+// graph.Weighted, edge weights are used, otherwise each edge has unit
+// weight. Self edges are ignored.
+func Degree(g graph.Graph) GraphMatrix {
+	nodes, index := indexNodes(g)
+	deg := degrees(g, nodes)
+	d := mat.NewSymDense(len(nodes), nil)
+	for i, v := range deg {
+		d.SetSym(i, i, v)
+	}
+	return GraphMatrix{Matrix: d, Nodes: nodes, Index: index}
+}
+
+// Laplacian returns the combinatorial Laplacian matrix of the undirected graph
+// g as a *mat.SymDense,
+//  L = D - A,
+// where D is the degree matrix and A is the adjacency matrix of g. If g
+// implements graph.Weighted, edge weights are used, otherwise each edge has
+// unit weight. Self edges are ignored.
+//
+// The Laplacian is positive semi-definite, and the multiplicity of its zero
+// eigenvalue is the number of connected components of g.
+func Laplacian(g graph.Undirected) GraphMatrix {
+	nodes, index := indexNodes(g)
+	weight := weightFunc(g)
+	l := mat.NewSymDense(len(nodes), nil)
+	for i, v := range degrees(g, nodes) {
+		l.SetSym(i, i, v)
+	}
+	for i, u := range nodes {
+		for _, v := range g.From(u) {
+			if j := index[v.ID()]; i < j {
+				l.SetSym(i, j, -weight(u, v))
+			}
+		}
+	}
+	return GraphMatrix{Matrix: l, Nodes: nodes, Index: index}
+}
+
+// NormalizedLaplacian returns the symmetric normalized Laplacian matrix of the
+// undirected graph g as a *mat.SymDense,
+//  L = I - D^-1/2 * A * D^-1/2,
+// where D is the degree matrix and A is the adjacency matrix of g. The rows
+// and columns of isolated nodes are zero. If g implements graph.Weighted,
+// edge weights are used, otherwise each edge has unit weight. Self edges are
+// ignored.
+//
+// The eigenvalues of the normalized Laplacian lie in [0, 2].
+func NormalizedLaplacian(g graph.Undirected) GraphMatrix {
+	nodes, index := indexNodes(g)
+	weight := weightFunc(g)
+	deg := degrees(g, nodes)
+	l := mat.NewSymDense(len(nodes), nil)
+	for i, u := range nodes {
+		if deg[i] == 0 {
+			continue
+		}
+		l.SetSym(i, i, 1)
+		for _, v := range g.From(u) {
+			if j := index[v.ID()]; i < j {
+				l.SetSym(i, j, -weight(u, v)/math.Sqrt(deg[i]*deg[j]))
+			}
+		}
+	}
+	return GraphMatrix{Matrix: l, Nodes: nodes, Index: index}
+}
+
+// indexNodes returns the nodes of g sorted by ID and
+// a mapping from node IDs to their positions.
+func indexNodes(g graph.Graph) ([]graph.Node, map[int64]int) {
+	nodes := g.Nodes()
+	sort.Sort(ordered.ByID(nodes))
+	index := make(map[int64]int, len(nodes))
+	for i, n := range nodes {
+		index[n.ID()] = i
+	}
+	return nodes, index
+}
+
+// degrees returns the weighted degrees of nodes in g, ignoring self edges.
+func degrees(g graph.Graph, nodes []graph.Node) []float64 {
+	weight := weightFunc(g)
+	deg := make([]float64, len(nodes))
+	for i, u := range nodes {
+		for _, v := range g.From(u) {
+			if v.ID() != u.ID() {
+				deg[i] += weight(u, v)
+			}
+		}
+	}
+	return deg
+}
+
+// weightFunc returns a function returning the weight of the edge
+// from u to v in g, or unit weight if g is not weighted.
+func weightFunc(g graph.Graph) func(u, v graph.Node) float64 {
+	wg, ok := g.(graph.Weighted)
+	if !ok {
+		return func(_, _ graph.Node) float64 { return 1 }
+	}
+	return func(u, v graph.Node) float64 {
+		w, ok := wg.Weight(u, v)
+		if !ok {
+			panic("spectral: unexpected invalid weight")
+		}
+		return w
+	}
+}
@@ -0,0 +1,175 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package spectral
+
+import (
+	"math"
+	"testing"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/graph/network"
+	"gonum.org/v1/gonum/graph/simple"
+	"gonum.org/v1/gonum/mat"
+)
+
+// weighted returns a weighted undirected graph of a triangle
+// 0--1--2 with a pendant node 3 attached to 2 and an isolated
+// node 4.
+func weighted() *simple.WeightedUndirectedGraph {
+	g := simple.NewWeightedUndirectedGraph(0, 0)
+	for _, e := range []simple.WeightedEdge{
+		{F: simple.Node(0), T: simple.Node(1), W: 1},
+		{F: simple.Node(1), T: simple.Node(2), W: 2},
+		{F: simple.Node(0), T: simple.Node(2), W: 3},
+		{F: simple.Node(2), T: simple.Node(3), W: 4},
+	} {
+		g.SetWeightedEdge(e)
+	}
+	g.AddNode(simple.Node(4))
+	return g
+}
+
+func TestAdjacencyDegreeLaplacian(t *testing.T) {
+	g := weighted()
+
+	a := Adjacency(g)
+	if _, ok := a.Matrix.(*mat.SymDense); !ok {
+		t.Errorf("unexpected adjacency matrix type for undirected graph: %T", a.Matrix)
+	}
+	wantA := mat.NewDense(5, 5, []float64{
+		0, 1, 3, 0, 0,
+		1, 0, 2, 0, 0,
+		3, 2, 0, 4, 0,
+		0, 0, 4, 0, 0,
+		0, 0, 0, 0, 0,
+	})
+	if !mat.Equal(a, wantA) {
+		t.Errorf("unexpected adjacency matrix:\ngot:\n%v\nwant:\n%v", mat.Formatted(a), mat.Formatted(wantA))
+	}
+	for i, n := range a.Nodes {
+		if n.ID() != int64(i) || a.Index[n.ID()] != i {
+			t.Errorf("unexpected node order: node %d at %d", n.ID(), i)
+		}
+	}
+
+	d := Degree(g)
+	wantD := mat.NewDense(5, 5, nil)
+	for i, v := range []float64{4, 3, 9, 4, 0} {
+		wantD.Set(i, i, v)
+	}
+	if !mat.Equal(d, wantD) {
+		t.Errorf("unexpected degree matrix:\ngot:\n%v\nwant:\n%v", mat.Formatted(d), mat.Formatted(wantD))
+	}
+
+	var wantL mat.Dense
+	wantL.Sub(wantD, wantA)
+	if l := Laplacian(g); !mat.Equal(l, &wantL) {
+		t.Errorf("unexpected Laplacian:\ngot:\n%v\nwant:\n%v", mat.Formatted(l), mat.Formatted(&wantL))
+	}
+
+	nl := NormalizedLaplacian(g)
+	for i := 0; i < 5; i++ {
+		for j := 0; j < 5; j++ {
+			var want float64
+			switch {
+			case wantD.At(i, i) == 0 || wantD.At(j, j) == 0:
+			case i == j:
+				want = 1
+			default:
+				want = -wantA.At(i, j) / math.Sqrt(wantD.At(i, i)*wantD.At(j, j))
+			}
+			if got := nl.At(i, j); math.Abs(got-want) > 1e-15 {
+				t.Errorf("unexpected normalized Laplacian element at (%d, %d): got:%v want:%v", i, j, got, want)
+			}
+		}
+	}
+
+	// The multiplicity of the zero eigenvalue of the Laplacian
+	// is the number of connected components, and the eigenvalues
+	// of the normalized Laplacian lie in [0, 2].
+	for _, test := range []struct {
+		name string
+		m    GraphMatrix
+		max  float64
+	}{
+		{name: "Laplacian", m: Laplacian(g), max: math.Inf(1)},
+		{name: "normalized Laplacian", m: nl, max: 2},
+	} {
+		var eig mat.EigenSym
+		if !eig.Factorize(test.m.Matrix.(*mat.SymDense), false) {
+			t.Fatalf("unexpected eigendecomposition failure for %s", test.name)
+		}
+		var zeros int
+		for _, v := range eig.Values(nil) {
+			if math.Abs(v) < 1e-12 {
+				zeros++
+			}
+			if v < -1e-12 || test.max+1e-12 < v {
+				t.Errorf("eigenvalue of %s out of range: %v", test.name, v)
+			}
+		}
+		if zeros != 2 {
+			t.Errorf("unexpected number of zero eigenvalues of %s: got:%d want:2", test.name, zeros)
+		}
+	}
+}
+
+func TestLaplacianUnweighted(t *testing.T) {
+	// For unweighted graphs the Laplacians agree
+	// with those of the network package.
+	g := simple.NewUndirectedGraph()
+	for _, e := range [][2]int64{{0, 1}, {0, 2}, {1, 2}, {2, 5}, {5, 3}, {3, 4}, {4, 5}} {
+		g.SetEdge(simple.Edge{F: simple.Node(e[0]), T: simple.Node(e[1])})
+	}
+	for _, test := range []struct {
+		name string
+		got  GraphMatrix
+		want network.Laplacian
+	}{
+		{name: "Laplacian", got: Laplacian(g), want: network.NewLaplacian(g)},
+		{name: "normalized Laplacian", got: NormalizedLaplacian(g), want: network.NewSymNormLaplacian(g)},
+	} {
+		for _, u := range test.got.Nodes {
+			for _, v := range test.got.Nodes {
+				got := test.got.At(test.got.Index[u.ID()], test.got.Index[v.ID()])
+				want := test.want.At(test.want.Index[u.ID()], test.want.Index[v.ID()])
+				if !floats.EqualWithinAbsOrRel(got, want, 1e-15, 1e-15) {
+					t.Errorf("unexpected %s element for %d--%d: got:%v want:%v", test.name, u.ID(), v.ID(), got, want)
+				}
+			}
+		}
+	}
+}
+
+func TestAdjacencyDirected(t *testing.T) {
+	g := simple.NewWeightedDirectedGraph(0, 0)
+	for _, e := range []simple.WeightedEdge{
+		{F: simple.Node(0), T: simple.Node(1), W: 2},
+		{F: simple.Node(1), T: simple.Node(0), W: 3},
+		{F: simple.Node(1), T: simple.Node(2), W: 5},
+	} {
+		g.SetWeightedEdge(e)
+	}
+	a := Adjacency(g)
+	if _, ok := a.Matrix.(*mat.Dense); !ok {
+		t.Errorf("unexpected adjacency matrix type for directed graph: %T", a.Matrix)
+	}
+	wantA := mat.NewDense(3, 3, []float64{
+		0, 2, 0,
+		3, 0, 5,
+		0, 0, 0,
+	})
+	if !mat.Equal(a, wantA) {
+		t.Errorf("unexpected adjacency matrix:\ngot:\n%v\nwant:\n%v", mat.Formatted(a), mat.Formatted(wantA))
+	}
+	wantD := mat.NewDense(3, 3, []float64{
+		2, 0, 0,
+		0, 8, 0,
+		0, 0, 0,
+	})
+	if d := Degree(g); !mat.Equal(d, wantD) {
+		t.Errorf("unexpected out-degree matrix:\ngot:\n%v\nwant:\n%v", mat.Formatted(d), mat.Formatted(wantD))
+	}
+}
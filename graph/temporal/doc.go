@@ -0,0 +1,12 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package temporal provides a temporal graph, a graph whose edges are present
+// only during time intervals, and time-respecting path queries on temporal
+// graphs.
+//
+// A journey in a temporal graph is a path whose edges are traversed at
+// non-decreasing times, each edge being traversed at a time within its
+// interval. Traversal of an edge is instantaneous.
+package temporal // import "gonum.org/v1/gonum/graph/temporal"
@@ -0,0 +1,227 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package temporal
+
+import (
+	"container/heap"
+	"math"
+
+	"gonum.org/v1/gonum/graph"
+)
+
+// Journeys is a tree of time-respecting paths from a source node created
+// by the EarliestArrival or FewestHops functions.
+type Journeys struct {
+	from  graph.Node
+	start float64
+
+	g *Graph
+
+	// states holds the states in which each
+	// reached node was reached, in order of
+	// decreasing arrival time. The first state
+	// of each node ends the journey held for
+	// the node.
+	states map[int64][]journeyState
+}
+
+// journeyState is a node reached at a time and
+// number of hops by traversing edge from the
+// state prevState of the node prev.
+type journeyState struct {
+	arrival   float64
+	hops      int
+	prev      int64
+	prevState int
+	edge      graph.TemporalEdge
+}
+
+func newJourneys(g *Graph, u graph.Node, start float64) Journeys {
+	if !g.Has(u) {
+		panic("temporal: source node not in graph")
+	}
+	j := Journeys{
+		from:   g.Node(u.ID()),
+		start:  start,
+		g:      g,
+		states: make(map[int64][]journeyState),
+	}
+	j.states[u.ID()] = []journeyState{{arrival: start, prevState: -1}}
+	return j
+}
+
+// From returns the starting node of the journeys held by the Journeys.
+func (j Journeys) From() graph.Node { return j.from }
+
+// Start returns the time at which the journeys held by the Journeys start.
+func (j Journeys) Start() float64 { return j.start }
+
+// state returns the state at the end of the journey to v.
+func (j Journeys) state(v int64) (journeyState, bool) {
+	states, ok := j.states[v]
+	if !ok {
+		return journeyState{}, false
+	}
+	return states[0], true
+}
+
+// ArrivalTime returns the time at which the journey to v arrives at v.
+// If v is not reachable, ArrivalTime returns +Inf.
+func (j Journeys) ArrivalTime(v graph.Node) float64 {
+	s, ok := j.state(v.ID())
+	if !ok {
+		return math.Inf(1)
+	}
+	return s.arrival
+}
+
+// Hops returns the number of edges in the journey to v. If v is not
+// reachable, Hops returns -1.
+func (j Journeys) Hops(v graph.Node) int {
+	s, ok := j.state(v.ID())
+	if !ok {
+		return -1
+	}
+	return s.hops
+}
+
+// To returns the nodes of the journey to v and the time at which it arrives
+// at v. If v is not reachable, To returns a nil path and +Inf.
+func (j Journeys) To(v graph.Node) (path []graph.Node, arrival float64) {
+	edges, arrival := j.EdgesTo(v)
+	if math.IsInf(arrival, 1) {
+		return nil, arrival
+	}
+	path = make([]graph.Node, len(edges)+1)
+	path[0] = j.from
+	id := j.from.ID()
+	for i, e := range edges {
+		n := other(e, id)
+		path[i+1] = j.g.Node(n.ID())
+		id = n.ID()
+	}
+	return path, arrival
+}
+
+// EdgesTo returns the edges traversed by the journey to v in order and the
+// time at which it arrives at v. If v is not reachable, EdgesTo returns nil
+// edges and +Inf.
+func (j Journeys) EdgesTo(v graph.Node) (edges []graph.TemporalEdge, arrival float64) {
+	id := v.ID()
+	s, ok := j.state(id)
+	if !ok {
+		return nil, math.Inf(1)
+	}
+	arrival = s.arrival
+	edges = make([]graph.TemporalEdge, s.hops)
+	for i := s.hops - 1; i >= 0; i-- {
+		edges[i] = s.edge
+		id = s.prev
+		s = j.states[id][s.prevState]
+	}
+	return edges, arrival
+}
+
+// departure returns the earliest time at or after t that e may be
+// traversed, and whether it may be traversed at all.
+func departure(e graph.TemporalEdge, t float64) (float64, bool) {
+	d := math.Max(t, e.Start())
+	return d, d <= e.End()
+}
+
+// EarliestArrival returns the journeys from u starting at time start that
+// arrive at each node of g as early as possible. EarliestArrival will panic
+// if u is not in g.
+func EarliestArrival(g *Graph, u graph.Node, start float64) Journeys {
+	j := newJourneys(g, u, start)
+
+	done := make(map[int64]bool)
+	q := &arrivalQueue{{id: u.ID(), arrival: start}}
+	for q.Len() != 0 {
+		mid := heap.Pop(q).(arrival)
+		if done[mid.id] {
+			continue
+		}
+		done[mid.id] = true
+		t := j.states[mid.id][0].arrival
+		hops := j.states[mid.id][0].hops
+		for _, e := range g.from[mid.id] {
+			d, ok := departure(e, t)
+			if !ok {
+				continue
+			}
+			v := other(e, mid.id).ID()
+			if s, ok := j.states[v]; ok && s[0].arrival <= d {
+				continue
+			}
+			j.states[v] = []journeyState{{arrival: d, hops: hops + 1, prev: mid.id, edge: e}}
+			heap.Push(q, arrival{id: v, arrival: d})
+		}
+	}
+	return j
+}
+
+// FewestHops returns the journeys from u starting at time start that
+// traverse the fewest edges to reach each node of g. Among journeys with the
+// fewest edges, the journey arriving earliest is returned. FewestHops will
+// panic if u is not in g.
+func FewestHops(g *Graph, u graph.Node, start float64) Journeys {
+	j := newJourneys(g, u, start)
+
+	// Each round extends the journeys that were improved
+	// by the previous round by a single edge, so the nodes
+	// first reached in round k are reached with k hops and
+	// the earliest possible arrival for k hops.
+	changed := []int64{u.ID()}
+	for hops := 1; len(changed) != 0; hops++ {
+		improved := make(map[int64]journeyState)
+		for _, uid := range changed {
+			states := j.states[uid]
+			last := len(states) - 1
+			t := states[last].arrival
+			for _, e := range g.from[uid] {
+				d, ok := departure(e, t)
+				if !ok {
+					continue
+				}
+				v := other(e, uid).ID()
+				if s, ok := j.states[v]; ok && s[len(s)-1].arrival <= d {
+					continue
+				}
+				if s, ok := improved[v]; ok && s.arrival <= d {
+					continue
+				}
+				improved[v] = journeyState{arrival: d, hops: hops, prev: uid, prevState: last, edge: e}
+			}
+		}
+		changed = changed[:0]
+		for v, s := range improved {
+			j.states[v] = append(j.states[v], s)
+			changed = append(changed, v)
+		}
+	}
+	return j
+}
+
+// arrival is a node reached at a time.
+type arrival struct {
+	id      int64
+	arrival float64
+}
+
+// arrivalQueue is a priority queue of arrivals
+// ordered by ascending arrival time.
+type arrivalQueue []arrival
+
+func (q arrivalQueue) Len() int            { return len(q) }
+func (q arrivalQueue) Less(i, j int) bool  { return q[i].arrival < q[j].arrival }
+func (q arrivalQueue) Swap(i, j int)       { q[i], q[j] = q[j], q[i] }
+func (q *arrivalQueue) Push(x interface{}) { *q = append(*q, x.(arrival)) }
+func (q *arrivalQueue) Pop() interface{} {
+	t := *q
+	var x arrival
+	x, *q = t[len(t)-1], t[:len(t)-1]
+	return x
+}
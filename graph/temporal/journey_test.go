@@ -0,0 +1,197 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package temporal
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/simple"
+)
+
+var journeyTests = []struct {
+	name     string
+	directed bool
+	from     int64
+	start    float64
+	fewest   bool
+
+	want map[int64]struct {
+		arrival float64
+		path    []int64
+	}
+}{
+	{
+		name: "earliest", directed: true, from: 0, start: 0,
+		want: map[int64]struct {
+			arrival float64
+			path    []int64
+		}{
+			0: {arrival: 0, path: []int64{0}},
+			1: {arrival: 1, path: []int64{0, 1}},
+			2: {arrival: 3, path: []int64{0, 1, 2}},
+			3: {arrival: 5, path: []int64{0, 1, 2, 3}},
+			4: {arrival: math.Inf(1)},
+		},
+	},
+	{
+		name: "fewest", directed: true, from: 0, start: 0, fewest: true,
+		want: map[int64]struct {
+			arrival float64
+			path    []int64
+		}{
+			0: {arrival: 0, path: []int64{0}},
+			1: {arrival: 1, path: []int64{0, 1}},
+			2: {arrival: 3, path: []int64{0, 1, 2}},
+			3: {arrival: 20, path: []int64{0, 3}},
+			4: {arrival: math.Inf(1)},
+		},
+	},
+	{
+		name: "late start", directed: true, from: 0, start: 2.5,
+		want: map[int64]struct {
+			arrival float64
+			path    []int64
+		}{
+			1: {arrival: math.Inf(1)},
+			3: {arrival: 20, path: []int64{0, 3}},
+		},
+	},
+	{
+		name: "interval end", directed: true, from: 0, start: 2,
+		want: map[int64]struct {
+			arrival float64
+			path    []int64
+		}{
+			1: {arrival: 2, path: []int64{0, 1}},
+			3: {arrival: 5, path: []int64{0, 1, 2, 3}},
+		},
+	},
+	{
+		name: "undirected", directed: false, from: 3, start: 0,
+		want: map[int64]struct {
+			arrival float64
+			path    []int64
+		}{
+			4: {arrival: 0, path: []int64{3, 4}},
+			2: {arrival: 5, path: []int64{3, 2}},
+			0: {arrival: 20, path: []int64{3, 0}},
+			1: {arrival: 20, path: []int64{3, 0, 1}},
+		},
+	},
+}
+
+func TestJourneys(t *testing.T) {
+	for _, test := range journeyTests {
+		g := newContactGraph(test.directed)
+		var j Journeys
+		if test.fewest {
+			j = FewestHops(g, simple.Node(test.from), test.start)
+		} else {
+			j = EarliestArrival(g, simple.Node(test.from), test.start)
+		}
+		if j.From().ID() != test.from || j.Start() != test.start {
+			t.Errorf("unexpected source for %s: got:%d at %v", test.name, j.From().ID(), j.Start())
+		}
+		for v, want := range test.want {
+			path, arrival := j.To(simple.Node(v))
+			if arrival != want.arrival {
+				t.Errorf("unexpected arrival at %d for %s: got:%v want:%v", v, test.name, arrival, want.arrival)
+			}
+			if got := j.ArrivalTime(simple.Node(v)); got != want.arrival {
+				t.Errorf("unexpected arrival time at %d for %s: got:%v want:%v", v, test.name, got, want.arrival)
+			}
+			var got []int64
+			for _, n := range path {
+				got = append(got, n.ID())
+			}
+			if !sameIDs(got, want.path) {
+				t.Errorf("unexpected path to %d for %s: got:%v want:%v", v, test.name, got, want.path)
+			}
+			if hops := j.Hops(simple.Node(v)); hops != len(want.path)-1 {
+				t.Errorf("unexpected hops to %d for %s: got:%d want:%d", v, test.name, hops, len(want.path)-1)
+			}
+		}
+	}
+}
+
+func TestJourneysRandom(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for trial := 0; trial < 20; trial++ {
+		for _, directed := range []bool{true, false} {
+			var g *Graph
+			if directed {
+				g = NewDirected()
+			} else {
+				g = NewUndirected()
+			}
+			const n = 20
+			for i := 0; i < n; i++ {
+				g.AddNode(simple.Node(i))
+			}
+			for i := 0; i < 80; i++ {
+				u, v := rnd.Intn(n), rnd.Intn(n)
+				if u == v {
+					continue
+				}
+				s := 100 * rnd.Float64()
+				g.SetTemporalEdge(Edge{F: simple.Node(u), T: simple.Node(v), S: s, E: s + 10*rnd.Float64()})
+			}
+
+			start := 20 * rnd.Float64()
+			earliest := EarliestArrival(g, simple.Node(0), start)
+			fewest := FewestHops(g, simple.Node(0), start)
+			for _, v := range g.Nodes() {
+				for _, j := range []Journeys{earliest, fewest} {
+					checkJourney(t, j, v, directed)
+				}
+				ea := earliest.ArrivalTime(v)
+				fa := fewest.ArrivalTime(v)
+				if math.IsInf(ea, 1) != math.IsInf(fa, 1) {
+					t.Errorf("inconsistent reachability of %d: earliest:%v fewest:%v", v.ID(), ea, fa)
+					continue
+				}
+				if fa < ea {
+					t.Errorf("fewest hop journey to %d arrives before earliest arrival: %v < %v", v.ID(), fa, ea)
+				}
+				if fewest.Hops(v) > earliest.Hops(v) {
+					t.Errorf("fewest hop journey to %d has more hops than earliest arrival journey: %d > %d",
+						v.ID(), fewest.Hops(v), earliest.Hops(v))
+				}
+			}
+		}
+	}
+}
+
+// checkJourney checks that the journey to v held by j is time-respecting.
+func checkJourney(t *testing.T, j Journeys, v graph.Node, directed bool) {
+	edges, arrival := j.EdgesTo(v)
+	if math.IsInf(arrival, 1) {
+		return
+	}
+	if len(edges) != j.Hops(v) {
+		t.Errorf("unexpected number of edges to %d: got:%d want:%d", v.ID(), len(edges), j.Hops(v))
+	}
+	now := j.Start()
+	at := j.From().ID()
+	for _, e := range edges {
+		if e.From().ID() != at && (directed || e.To().ID() != at) {
+			t.Errorf("journey to %d is not a path: edge %d-%d does not leave %d", v.ID(), e.From().ID(), e.To().ID(), at)
+			return
+		}
+		now = math.Max(now, e.Start())
+		if now > e.End() {
+			t.Errorf("journey to %d is not time-respecting", v.ID())
+			return
+		}
+		at = other(e, at).ID()
+	}
+	if at != v.ID() || now != arrival {
+		t.Errorf("journey to %d ends at %d at %v, reported arrival %v", v.ID(), at, now, arrival)
+	}
+}
@@ -0,0 +1,277 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package temporal
+
+import (
+	"fmt"
+
+	"gonum.org/v1/gonum/graph"
+)
+
+// Edge is a temporal graph edge.
+type Edge struct {
+	F, T graph.Node
+
+	// S and E are the start and end
+	// of the interval during which
+	// the edge is present.
+	S, E float64
+}
+
+// From returns the from-node of the edge.
+func (e Edge) From() graph.Node { return e.F }
+
+// To returns the to-node of the edge.
+func (e Edge) To() graph.Node { return e.T }
+
+// Start returns the start of the interval during which the edge is present.
+func (e Edge) Start() float64 { return e.S }
+
+// End returns the end of the interval during which the edge is present.
+func (e Edge) End() float64 { return e.E }
+
+// Graph is a temporal graph. Each pair of nodes may be joined by
+// any number of temporal edges.
+type Graph struct {
+	directed bool
+
+	nodes map[int64]graph.Node
+
+	// from holds the edges leaving each node, and
+	// to holds the edges entering each node. In an
+	// undirected graph each edge is held in from for
+	// both of its nodes and to is not used.
+	from map[int64][]graph.TemporalEdge
+	to   map[int64][]graph.TemporalEdge
+
+	edges []graph.TemporalEdge
+}
+
+// NewDirected returns a directed temporal graph.
+func NewDirected() *Graph {
+	return &Graph{
+		directed: true,
+		nodes:    make(map[int64]graph.Node),
+		from:     make(map[int64][]graph.TemporalEdge),
+		to:       make(map[int64][]graph.TemporalEdge),
+	}
+}
+
+// NewUndirected returns an undirected temporal graph.
+func NewUndirected() *Graph {
+	return &Graph{
+		nodes: make(map[int64]graph.Node),
+		from:  make(map[int64][]graph.TemporalEdge),
+	}
+}
+
+// IsDirected returns whether the graph is directed.
+func (g *Graph) IsDirected() bool { return g.directed }
+
+// AddNode adds n to the graph. It panics if the added node ID matches an existing node ID.
+func (g *Graph) AddNode(n graph.Node) {
+	if _, exists := g.nodes[n.ID()]; exists {
+		panic(fmt.Sprintf("temporal: node ID collision: %d", n.ID()))
+	}
+	g.nodes[n.ID()] = n
+}
+
+// SetTemporalEdge adds e to the graph. If the nodes of e do not exist, they
+// are added. Edges joining the same nodes, including edges with overlapping
+// intervals, are held separately. SetTemporalEdge will panic if the IDs of
+// e.From and e.To are equal, or if e.End is before e.Start.
+func (g *Graph) SetTemporalEdge(e graph.TemporalEdge) {
+	var (
+		from = e.From()
+		fid  = from.ID()
+		to   = e.To()
+		tid  = to.ID()
+	)
+
+	if fid == tid {
+		panic("temporal: adding self edge")
+	}
+	if !(e.Start() <= e.End()) {
+		panic("temporal: invalid edge interval")
+	}
+
+	if _, ok := g.nodes[fid]; !ok {
+		g.AddNode(from)
+	}
+	if _, ok := g.nodes[tid]; !ok {
+		g.AddNode(to)
+	}
+
+	g.from[fid] = append(g.from[fid], e)
+	if g.directed {
+		g.to[tid] = append(g.to[tid], e)
+	} else {
+		g.from[tid] = append(g.from[tid], e)
+	}
+	g.edges = append(g.edges, e)
+}
+
+// Node returns the node in the graph with the given ID.
+func (g *Graph) Node(id int64) graph.Node {
+	return g.nodes[id]
+}
+
+// Has returns whether the node exists within the graph.
+func (g *Graph) Has(n graph.Node) bool {
+	_, ok := g.nodes[n.ID()]
+	return ok
+}
+
+// Nodes returns all the nodes in the graph.
+func (g *Graph) Nodes() []graph.Node {
+	if len(g.nodes) == 0 {
+		return nil
+	}
+	nodes := make([]graph.Node, 0, len(g.nodes))
+	for _, n := range g.nodes {
+		nodes = append(nodes, n)
+	}
+	return nodes
+}
+
+// TemporalEdges returns all the edges in the graph in the order they were added.
+func (g *Graph) TemporalEdges() []graph.TemporalEdge {
+	if len(g.edges) == 0 {
+		return nil
+	}
+	return append([]graph.TemporalEdge(nil), g.edges...)
+}
+
+// TemporalEdgesFrom returns the edges that may be traversed from n. In an
+// undirected graph these are all the edges incident to n.
+func (g *Graph) TemporalEdgesFrom(n graph.Node) []graph.TemporalEdge {
+	edges := g.from[n.ID()]
+	if len(edges) == 0 {
+		return nil
+	}
+	return append([]graph.TemporalEdge(nil), edges...)
+}
+
+// other returns the node of e that is not n.
+func other(e graph.Edge, n int64) graph.Node {
+	if e.From().ID() == n {
+		return e.To()
+	}
+	return e.From()
+}
+
+// active returns whether e is present at time t.
+func active(e graph.TemporalEdge, t float64) bool {
+	return e.Start() <= t && t <= e.End()
+}
+
+// Snapshot returns a view of the graph at time t, holding all the nodes of g
+// and the edges of g that are present at t. The returned graph implements
+// graph.Directed if g is directed and graph.Undirected otherwise. When more
+// than one edge joining a pair of nodes is present at t, the edge that was
+// added to g first is returned by edge queries. The snapshot reflects
+// subsequent changes to g.
+func (g *Graph) Snapshot(t float64) graph.Graph {
+	if g.directed {
+		return directedSnapshot{snapshot{g: g, t: t}}
+	}
+	return undirectedSnapshot{snapshot{g: g, t: t}}
+}
+
+// snapshot holds the behaviour common to directed
+// and undirected snapshots.
+type snapshot struct {
+	g *Graph
+	t float64
+}
+
+func (s snapshot) Has(n graph.Node) bool { return s.g.Has(n) }
+
+func (s snapshot) Nodes() []graph.Node { return s.g.Nodes() }
+
+// adjacent returns the nodes joined to n by edges
+// in adj that are present at the snapshot time.
+func (s snapshot) adjacent(n graph.Node, adj map[int64][]graph.TemporalEdge) []graph.Node {
+	id := n.ID()
+	seen := make(map[int64]bool)
+	var nodes []graph.Node
+	for _, e := range adj[id] {
+		if !active(e, s.t) {
+			continue
+		}
+		v := other(e, id).ID()
+		if seen[v] {
+			continue
+		}
+		seen[v] = true
+		nodes = append(nodes, s.g.nodes[v])
+	}
+	return nodes
+}
+
+func (s snapshot) From(n graph.Node) []graph.Node {
+	return s.adjacent(n, s.g.from)
+}
+
+// edge returns the first edge from u to v present at the snapshot
+// time. If directed is false, edges from v to u are also considered.
+func (s snapshot) edge(u, v graph.Node, directed bool) graph.TemporalEdge {
+	uid, vid := u.ID(), v.ID()
+	for _, e := range s.g.from[uid] {
+		if !active(e, s.t) {
+			continue
+		}
+		if e.To().ID() == vid || (!directed && e.From().ID() == vid) {
+			return e
+		}
+	}
+	return nil
+}
+
+// directedSnapshot is a view of a directed temporal
+// graph at a time. It implements graph.Directed.
+type directedSnapshot struct {
+	snapshot
+}
+
+func (s directedSnapshot) HasEdgeBetween(x, y graph.Node) bool {
+	return s.edge(x, y, true) != nil || s.edge(y, x, true) != nil
+}
+
+func (s directedSnapshot) Edge(u, v graph.Node) graph.Edge {
+	if e := s.edge(u, v, true); e != nil {
+		return e
+	}
+	return nil
+}
+
+func (s directedSnapshot) HasEdgeFromTo(u, v graph.Node) bool {
+	return s.edge(u, v, true) != nil
+}
+
+func (s directedSnapshot) To(n graph.Node) []graph.Node {
+	return s.adjacent(n, s.g.to)
+}
+
+// undirectedSnapshot is a view of an undirected temporal
+// graph at a time. It implements graph.Undirected.
+type undirectedSnapshot struct {
+	snapshot
+}
+
+func (s undirectedSnapshot) HasEdgeBetween(x, y graph.Node) bool {
+	return s.edge(x, y, false) != nil
+}
+
+func (s undirectedSnapshot) Edge(u, v graph.Node) graph.Edge {
+	return s.EdgeBetween(u, v)
+}
+
+func (s undirectedSnapshot) EdgeBetween(x, y graph.Node) graph.Edge {
+	if e := s.edge(x, y, false); e != nil {
+		return e
+	}
+	return nil
+}
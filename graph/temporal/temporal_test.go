@@ -0,0 +1,145 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package temporal
+
+import (
+	"sort"
+	"testing"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/internal/ordered"
+	"gonum.org/v1/gonum/graph/simple"
+	"gonum.org/v1/gonum/graph/topo"
+)
+
+var contacts = []Edge{
+	{F: simple.Node(0), T: simple.Node(1), S: 1, E: 2},
+	{F: simple.Node(1), T: simple.Node(2), S: 3, E: 4},
+	{F: simple.Node(2), T: simple.Node(3), S: 5, E: 6},
+	{F: simple.Node(0), T: simple.Node(3), S: 20, E: 30},
+	{F: simple.Node(3), T: simple.Node(4), S: 0, E: 1},
+	{F: simple.Node(1), T: simple.Node(0), S: 0, E: 100},
+}
+
+func newContactGraph(directed bool) *Graph {
+	var g *Graph
+	if directed {
+		g = NewDirected()
+	} else {
+		g = NewUndirected()
+	}
+	for _, e := range contacts {
+		g.SetTemporalEdge(e)
+	}
+	return g
+}
+
+func ids(nodes []graph.Node) []int64 {
+	sort.Sort(ordered.ByID(nodes))
+	var ids []int64
+	for _, n := range nodes {
+		ids = append(ids, n.ID())
+	}
+	return ids
+}
+
+func sameIDs(a, b []int64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestDirectedSnapshot(t *testing.T) {
+	g := newContactGraph(true)
+	if len(g.Nodes()) != 5 || len(g.TemporalEdges()) != len(contacts) {
+		t.Fatalf("unexpected graph size: nodes:%d edges:%d", len(g.Nodes()), len(g.TemporalEdges()))
+	}
+
+	s, ok := g.Snapshot(3.5).(graph.Directed)
+	if !ok {
+		t.Fatal("snapshot of directed graph is not a graph.Directed")
+	}
+	if got := ids(s.From(simple.Node(1))); !sameIDs(got, []int64{0, 2}) {
+		t.Errorf("unexpected nodes from 1: got:%v want:[0 2]", got)
+	}
+	if got := ids(s.To(simple.Node(0))); !sameIDs(got, []int64{1}) {
+		t.Errorf("unexpected nodes to 0: got:%v want:[1]", got)
+	}
+	if got := s.From(simple.Node(0)); len(got) != 0 {
+		t.Errorf("unexpected nodes from 0: got:%v", got)
+	}
+	if !s.HasEdgeBetween(simple.Node(0), simple.Node(1)) || s.HasEdgeFromTo(simple.Node(0), simple.Node(1)) {
+		t.Error("unexpected edge between 0 and 1")
+	}
+	if e := s.Edge(simple.Node(0), simple.Node(1)); e != nil {
+		t.Errorf("unexpected edge from 0 to 1: %v", e)
+	}
+	if e, ok := s.Edge(simple.Node(1), simple.Node(2)).(graph.TemporalEdge); !ok || e.Start() != 3 {
+		t.Errorf("unexpected edge from 1 to 2: %v", e)
+	}
+
+	// Interval ends are included in the snapshot.
+	s = g.Snapshot(2).(graph.Directed)
+	if !s.HasEdgeFromTo(simple.Node(0), simple.Node(1)) {
+		t.Error("expected edge from 0 to 1 at the end of its interval")
+	}
+
+	// Snapshots reflect later changes to the graph.
+	g.SetTemporalEdge(Edge{F: simple.Node(4), T: simple.Node(5), S: 0, E: 10})
+	if !s.Has(simple.Node(5)) || !s.HasEdgeFromTo(simple.Node(4), simple.Node(5)) {
+		t.Error("snapshot does not reflect added edge")
+	}
+
+	panics := func(fn func()) (ok bool) {
+		defer func() {
+			ok = recover() != nil
+		}()
+		fn()
+		return
+	}
+	if !panics(func() { g.SetTemporalEdge(Edge{F: simple.Node(0), T: simple.Node(0)}) }) {
+		t.Error("expected panic for self edge")
+	}
+	if !panics(func() { g.SetTemporalEdge(Edge{F: simple.Node(0), T: simple.Node(1), S: 2, E: 1}) }) {
+		t.Error("expected panic for invalid interval")
+	}
+	if !panics(func() { g.AddNode(simple.Node(0)) }) {
+		t.Error("expected panic for node ID collision")
+	}
+}
+
+func TestUndirectedSnapshot(t *testing.T) {
+	g := newContactGraph(false)
+	s, ok := g.Snapshot(5).(graph.Undirected)
+	if !ok {
+		t.Fatal("snapshot of undirected graph is not a graph.Undirected")
+	}
+	if got := ids(s.From(simple.Node(2))); !sameIDs(got, []int64{3}) {
+		t.Errorf("unexpected nodes from 2: got:%v want:[3]", got)
+	}
+	if e := s.EdgeBetween(simple.Node(3), simple.Node(2)); e == nil {
+		t.Error("expected edge between 3 and 2")
+	}
+	if got := ids(s.From(simple.Node(0))); !sameIDs(got, []int64{1}) {
+		t.Errorf("unexpected nodes from 0: got:%v want:[1]", got)
+	}
+
+	// Snapshots can be analysed by graph algorithms.
+	cc := topo.ConnectedComponents(s)
+	var sizes []int
+	for _, c := range cc {
+		sizes = append(sizes, len(c))
+	}
+	sort.Ints(sizes)
+	if want := []int{1, 2, 2}; len(sizes) != len(want) || sizes[0] != 1 || sizes[1] != 2 || sizes[2] != 2 {
+		t.Errorf("unexpected connected component sizes: got:%v want:%v", sizes, want)
+	}
+}
@@ -0,0 +1,174 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package topo
+
+import (
+	"sort"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/internal/ordered"
+	"gonum.org/v1/gonum/graph/internal/set"
+)
+
+// ArticulationPoints returns the articulation points of the undirected
+// graph g, sorted by ID. An articulation point, or cut vertex, is a node
+// whose removal increases the number of connected components of g.
+func ArticulationPoints(g graph.Undirected) []graph.Node {
+	var h hopcroftTarjan
+	h.walk(g)
+	return h.cuts
+}
+
+// Bridges returns the bridges of the undirected graph g. A bridge is an
+// edge whose removal increases the number of connected components of g.
+// Bridges are returned sorted by the lower and then higher ID of their
+// end points.
+func Bridges(g graph.Undirected) []graph.Edge {
+	var h hopcroftTarjan
+	h.walk(g)
+	return h.bridges
+}
+
+// BiconnectedComponents returns the biconnected components of the undirected
+// graph g. A biconnected component is a maximal subgraph that has no
+// articulation point. Each component is returned as its node set sorted by
+// ID and components are sorted lexically by their node IDs. Nodes with no
+// edges do not belong to any biconnected component.
+func BiconnectedComponents(g graph.Undirected) [][]graph.Node {
+	var h hopcroftTarjan
+	h.walk(g)
+	return h.components
+}
+
+// hopcroftTarjan implements the Hopcroft and Tarjan linear time
+// depth-first search for biconnected components, articulation points
+// and bridges described in doi:10.1145/362248.362272.
+type hopcroftTarjan struct {
+	g graph.Undirected
+
+	index int
+	disc  map[int64]int
+	low   map[int64]int
+
+	// edges holds the tree and back edges
+	// of the component under construction.
+	edges [][2]graph.Node
+
+	cuts       []graph.Node
+	bridges    []graph.Edge
+	components [][]graph.Node
+}
+
+func (h *hopcroftTarjan) walk(g graph.Undirected) {
+	nodes := g.Nodes()
+	sort.Sort(ordered.ByID(nodes))
+
+	h.g = g
+	h.disc = make(map[int64]int, len(nodes))
+	h.low = make(map[int64]int, len(nodes))
+	isCut := make(set.Int64s)
+	for _, u := range nodes {
+		if h.disc[u.ID()] != 0 {
+			continue
+		}
+		h.visit(u, nil, isCut)
+	}
+
+	for _, n := range nodes {
+		if isCut.Has(n.ID()) {
+			h.cuts = append(h.cuts, n)
+		}
+	}
+	sort.Sort(byNodeIDs(h.bridges))
+	sort.Sort(ordered.BySliceIDs(h.components))
+}
+
+// visit performs the depth-first search from u, which was reached
+// from parent. The parent of a search root is nil.
+func (h *hopcroftTarjan) visit(u, parent graph.Node, isCut set.Int64s) {
+	uid := u.ID()
+	h.index++
+	h.disc[uid] = h.index
+	h.low[uid] = h.index
+
+	var children int
+	for _, v := range h.g.From(u) {
+		vid := v.ID()
+		if parent != nil && vid == parent.ID() {
+			continue
+		}
+		if h.disc[vid] == 0 {
+			children++
+			h.edges = append(h.edges, [2]graph.Node{u, v})
+			h.visit(v, u, isCut)
+			h.low[uid] = min(h.low[uid], h.low[vid])
+
+			if h.low[vid] >= h.disc[uid] {
+				// u separates v's subtree from the
+				// rest of the graph, so the edges
+				// stacked since uv form a component.
+				if parent != nil {
+					isCut.Add(uid)
+				}
+				h.popComponent(u, v)
+			}
+			if h.low[vid] > h.disc[uid] {
+				h.bridges = append(h.bridges, h.g.EdgeBetween(u, v))
+			}
+		} else if h.disc[vid] < h.disc[uid] {
+			// Back edge to an ancestor.
+			h.edges = append(h.edges, [2]graph.Node{u, v})
+			h.low[uid] = min(h.low[uid], h.disc[vid])
+		}
+	}
+	if parent == nil && children > 1 {
+		isCut.Add(uid)
+	}
+}
+
+// popComponent pops edges from the edge stack up to and including
+// the edge uv, and adds the component formed by their end points.
+func (h *hopcroftTarjan) popComponent(u, v graph.Node) {
+	c := make(set.Nodes)
+	for {
+		e := h.edges[len(h.edges)-1]
+		h.edges = h.edges[:len(h.edges)-1]
+		c.Add(e[0])
+		c.Add(e[1])
+		if e[0].ID() == u.ID() && e[1].ID() == v.ID() {
+			break
+		}
+	}
+	nodes := make([]graph.Node, 0, len(c))
+	for _, n := range c {
+		nodes = append(nodes, n)
+	}
+	sort.Sort(ordered.ByID(nodes))
+	h.components = append(h.components, nodes)
+}
+
+// byNodeIDs sorts undirected edges by the lower and then higher
+// ID of their end points.
+type byNodeIDs []graph.Edge
+
+func (e byNodeIDs) Len() int { return len(e) }
+func (e byNodeIDs) Less(i, j int) bool {
+	ilo, ihi := endIDs(e[i])
+	jlo, jhi := endIDs(e[j])
+	if ilo != jlo {
+		return ilo < jlo
+	}
+	return ihi < jhi
+}
+func (e byNodeIDs) Swap(i, j int) { e[i], e[j] = e[j], e[i] }
+
+// endIDs returns the IDs of the end points of e in ascending order.
+func endIDs(e graph.Edge) (lo, hi int64) {
+	lo, hi = e.From().ID(), e.To().ID()
+	if hi < lo {
+		lo, hi = hi, lo
+	}
+	return lo, hi
+}
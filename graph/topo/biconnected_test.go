@@ -0,0 +1,140 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package topo
+
+import (
+	"reflect"
+	"testing"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/simple"
+)
+
+var biconnectedTests = []struct {
+	name string
+	g    []intset
+
+	wantCuts       []int64
+	wantBridges    [][2]int64
+	wantComponents [][]int64
+}{
+	{
+		name: "empty",
+	},
+	{
+		name: "isolated",
+		g: []intset{
+			0: nil,
+			1: nil,
+		},
+	},
+	{
+		name: "path",
+		g: []intset{
+			0: linksTo(1),
+			1: linksTo(2),
+			2: nil,
+		},
+		wantCuts:       []int64{1},
+		wantBridges:    [][2]int64{{0, 1}, {1, 2}},
+		wantComponents: [][]int64{{0, 1}, {1, 2}},
+	},
+	{
+		name: "bowtie",
+		g: []intset{
+			0: linksTo(1, 2),
+			1: linksTo(2),
+			2: linksTo(3, 4),
+			3: linksTo(4),
+			4: nil,
+		},
+		wantCuts:       []int64{2},
+		wantComponents: [][]int64{{0, 1, 2}, {2, 3, 4}},
+	},
+	{
+		name: "cycles joined by bridge with pendant",
+		g: []intset{
+			0: linksTo(1, 2),
+			1: linksTo(2),
+			2: linksTo(3),
+			3: linksTo(4, 5),
+			4: linksTo(5),
+			5: linksTo(6),
+			6: nil,
+
+			7: linksTo(8),
+			8: nil,
+		},
+		wantCuts:       []int64{2, 3, 5},
+		wantBridges:    [][2]int64{{2, 3}, {5, 6}, {7, 8}},
+		wantComponents: [][]int64{{0, 1, 2}, {2, 3}, {3, 4, 5}, {5, 6}, {7, 8}},
+	},
+	{
+		name: "Batagelj-Zaversnik",
+		g:    batageljZaversnikGraph,
+		wantCuts: []int64{
+			4, 11, 15,
+		},
+		wantBridges: [][2]int64{
+			{4, 5}, {9, 11}, {10, 11}, {15, 16},
+		},
+		wantComponents: [][]int64{
+			{1, 2, 3, 4},
+			{4, 5},
+			{6, 7, 8, 11, 12, 13, 14, 15, 17, 18, 19, 20},
+			{9, 11},
+			{10, 11},
+			{15, 16},
+		},
+	},
+}
+
+func TestBiconnected(t *testing.T) {
+	for _, test := range biconnectedTests {
+		g := simple.NewUndirectedGraph()
+		for u, e := range test.g {
+			// Add nodes that are not defined by an edge.
+			if !g.Has(simple.Node(u)) {
+				g.AddNode(simple.Node(u))
+			}
+			for v := range e {
+				g.SetEdge(simple.Edge{F: simple.Node(u), T: simple.Node(v)})
+			}
+		}
+
+		gotCuts := ids(ArticulationPoints(g))
+		if !reflect.DeepEqual(gotCuts, test.wantCuts) {
+			t.Errorf("unexpected articulation points for %q:\ngot: %v\nwant:%v", test.name, gotCuts, test.wantCuts)
+		}
+
+		var gotBridges [][2]int64
+		for _, e := range Bridges(g) {
+			lo, hi := endIDs(e)
+			gotBridges = append(gotBridges, [2]int64{lo, hi})
+		}
+		if !reflect.DeepEqual(gotBridges, test.wantBridges) {
+			t.Errorf("unexpected bridges for %q:\ngot: %v\nwant:%v", test.name, gotBridges, test.wantBridges)
+		}
+
+		var gotComponents [][]int64
+		for _, c := range BiconnectedComponents(g) {
+			gotComponents = append(gotComponents, ids(c))
+		}
+		if !reflect.DeepEqual(gotComponents, test.wantComponents) {
+			t.Errorf("unexpected biconnected components for %q:\ngot: %v\nwant:%v", test.name, gotComponents, test.wantComponents)
+		}
+	}
+}
+
+func ids(nodes []graph.Node) []int64 {
+	if nodes == nil {
+		return nil
+	}
+	ids := make([]int64, len(nodes))
+	for i, n := range nodes {
+		ids[i] = n.ID()
+	}
+	return ids
+}
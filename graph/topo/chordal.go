@@ -0,0 +1,262 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package topo
+
+import (
+	"sort"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/internal/ordered"
+)
+
+// LexBFS returns a lexicographic breadth-first search ordering of the nodes
+// of the undirected graph g. Ties are broken in favour of the node with
+// the lowest ID. The implementation uses partition refinement and runs in
+// O(|V|+|E|) time, excluding the cost of sorting nodes by ID.
+func LexBFS(g graph.Undirected) []graph.Node {
+	nodes := g.Nodes()
+	if len(nodes) == 0 {
+		return nil
+	}
+	sort.Sort(ordered.ByID(nodes))
+
+	// Each node is held in exactly one partition class. Classes
+	// are kept in a linked list ordered by decreasing label, and
+	// within a class nodes are held in a linked list ordered by ID.
+	head := &lexClass{}
+	elems := make(map[int64]*lexElem, len(nodes))
+	for _, n := range nodes {
+		e := &lexElem{node: n}
+		elems[n.ID()] = e
+		head.push(e)
+	}
+
+	order := make([]graph.Node, 0, len(nodes))
+	var touched []*lexClass
+	for step := 1; head != nil; step++ {
+		e := head.first
+		head.remove(e)
+		e.class = nil
+		v := e.node
+		order = append(order, v)
+
+		touched = append(touched[:0], head)
+		to := g.From(v)
+		sort.Sort(ordered.ByID(to))
+		for _, w := range to {
+			e := elems[w.ID()]
+			c := e.class
+			if c == nil {
+				// Already visited.
+				continue
+			}
+			if c.stamp != step {
+				c.stamp = step
+				c.split = &lexClass{prev: c.prev, next: c}
+				if c.prev != nil {
+					c.prev.next = c.split
+				} else {
+					head = c.split
+				}
+				c.prev = c.split
+				touched = append(touched, c)
+			}
+			c.remove(e)
+			c.split.push(e)
+		}
+
+		// Unlink any classes that have been emptied.
+		for _, c := range touched {
+			if c.first != nil {
+				continue
+			}
+			if c.prev != nil {
+				c.prev.next = c.next
+			} else if head == c {
+				head = c.next
+			}
+			if c.next != nil {
+				c.next.prev = c.prev
+			}
+			c.prev, c.next = nil, nil
+		}
+	}
+
+	return order
+}
+
+// lexClass is a partition class used by LexBFS.
+type lexClass struct {
+	first, last *lexElem
+	prev, next  *lexClass
+
+	// split is the class most recently split
+	// from this class during the step given
+	// by stamp.
+	split *lexClass
+	stamp int
+}
+
+// lexElem is a node held in a LexBFS partition class.
+type lexElem struct {
+	node       graph.Node
+	class      *lexClass
+	prev, next *lexElem
+}
+
+// push adds e to the end of the class.
+func (c *lexClass) push(e *lexElem) {
+	e.class = c
+	e.prev = c.last
+	e.next = nil
+	if c.last != nil {
+		c.last.next = e
+	} else {
+		c.first = e
+	}
+	c.last = e
+}
+
+// remove removes e from the class.
+func (c *lexClass) remove(e *lexElem) {
+	if e.prev != nil {
+		e.prev.next = e.next
+	} else {
+		c.first = e.next
+	}
+	if e.next != nil {
+		e.next.prev = e.prev
+	} else {
+		c.last = e.prev
+	}
+	e.prev, e.next = nil, nil
+}
+
+// IsChordal returns whether the undirected graph g is chordal; that is,
+// whether every cycle in g of length four or more has a chord.
+func IsChordal(g graph.Undirected) bool {
+	_, ok := PerfectEliminationOrdering(g)
+	return ok
+}
+
+// PerfectEliminationOrdering returns a perfect elimination ordering of the
+// nodes of the undirected graph g and true if g is chordal. If g is not
+// chordal, PerfectEliminationOrdering returns nil and false.
+//
+// In a perfect elimination ordering, each node and its neighbors that
+// occur after it in the ordering form a clique.
+func PerfectEliminationOrdering(g graph.Undirected) (order []graph.Node, ok bool) {
+	order = LexBFS(g)
+	ordered.Reverse(order)
+	if !isPerfectEliminationOrdering(g, order) {
+		return nil, false
+	}
+	return order, true
+}
+
+// isPerfectEliminationOrdering returns whether order is a perfect
+// elimination ordering of g. The check is performed using the approach
+// described in Rose, Tarjan and Lueker doi:10.1137/0205021 where only the
+// earliest later neighbor of each node is checked for adjacency to the
+// node's other later neighbors.
+func isPerfectEliminationOrdering(g graph.Undirected, order []graph.Node) bool {
+	idx := make(map[int64]int, len(order))
+	for i, n := range order {
+		idx[n.ID()] = i
+	}
+	for i, v := range order {
+		later := laterNeighbors(g, v, i, idx)
+		if len(later) < 2 {
+			continue
+		}
+		u := later[0]
+		for _, w := range later {
+			if idx[w.ID()] < idx[u.ID()] {
+				u = w
+			}
+		}
+		for _, w := range later {
+			if w.ID() == u.ID() {
+				continue
+			}
+			if !g.HasEdgeBetween(u, w) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// laterNeighbors returns the neighbors of v in g that are after
+// position i in the ordering indexed by idx.
+func laterNeighbors(g graph.Undirected, v graph.Node, i int, idx map[int64]int) []graph.Node {
+	var later []graph.Node
+	for _, w := range g.From(v) {
+		if idx[w.ID()] > i {
+			later = append(later, w)
+		}
+	}
+	return later
+}
+
+// ChordalMaximumClique returns a maximum clique of the undirected graph g
+// and true if g is chordal. If g is not chordal ChordalMaximumClique returns
+// nil and false. The nodes of the returned clique are sorted by ID.
+//
+// Finding a maximum clique in a chordal graph takes linear time.
+func ChordalMaximumClique(g graph.Undirected) (clique []graph.Node, ok bool) {
+	order, ok := PerfectEliminationOrdering(g)
+	if !ok {
+		return nil, false
+	}
+	idx := make(map[int64]int, len(order))
+	for i, n := range order {
+		idx[n.ID()] = i
+	}
+	for i, v := range order {
+		later := laterNeighbors(g, v, i, idx)
+		if len(later)+1 > len(clique) {
+			clique = append(later, v)
+		}
+	}
+	sort.Sort(ordered.ByID(clique))
+	return clique, true
+}
+
+// ChordalColoring returns an optimal vertex coloring of the undirected graph g
+// and true if g is chordal. The colors are returned as a map of node IDs to
+// colors in [0, k) where k is the chromatic number of g. If g is not chordal
+// ChordalColoring returns nil, 0 and false.
+//
+// Optimal coloring of a chordal graph is performed by greedily coloring nodes
+// in the reverse of a perfect elimination ordering.
+func ChordalColoring(g graph.Undirected) (colors map[int64]int, k int, ok bool) {
+	order, ok := PerfectEliminationOrdering(g)
+	if !ok {
+		return nil, 0, false
+	}
+	colors = make(map[int64]int, len(order))
+	used := make(map[int]bool)
+	for i := len(order) - 1; i >= 0; i-- {
+		v := order[i]
+		for c := range used {
+			delete(used, c)
+		}
+		for _, w := range g.From(v) {
+			if c, ok := colors[w.ID()]; ok {
+				used[c] = true
+			}
+		}
+		var c int
+		for used[c] {
+			c++
+		}
+		colors[v.ID()] = c
+		if c+1 > k {
+			k = c + 1
+		}
+	}
+	return colors, k, true
+}
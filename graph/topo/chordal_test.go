@@ -0,0 +1,226 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package topo
+
+import (
+	"reflect"
+	"testing"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/simple"
+)
+
+var chordalTests = []struct {
+	name string
+	g    []intset
+
+	wantChordal bool
+	wantClique  []int64
+	wantColors  int
+}{
+	{
+		name:        "empty",
+		g:           nil,
+		wantChordal: true,
+		wantClique:  nil,
+		wantColors:  0,
+	},
+	{
+		name: "path",
+		g: []intset{
+			0: linksTo(1),
+			1: linksTo(2),
+			2: linksTo(3),
+			3: nil,
+		},
+		wantChordal: true,
+		wantClique:  []int64{2, 3},
+		wantColors:  2,
+	},
+	{
+		name: "square",
+		g: []intset{
+			0: linksTo(1, 3),
+			1: linksTo(2),
+			2: linksTo(3),
+			3: nil,
+		},
+		wantChordal: false,
+	},
+	{
+		name: "square with chord",
+		g: []intset{
+			0: linksTo(1, 2, 3),
+			1: linksTo(2),
+			2: linksTo(3),
+			3: nil,
+		},
+		wantChordal: true,
+		wantClique:  []int64{0, 2, 3},
+		wantColors:  3,
+	},
+	{
+		name: "pentagon",
+		g: []intset{
+			0: linksTo(1, 4),
+			1: linksTo(2),
+			2: linksTo(3),
+			3: linksTo(4),
+			4: nil,
+		},
+		wantChordal: false,
+	},
+	{
+		name: "triangulated hexagon with K4",
+		g: []intset{
+			0: linksTo(1, 2, 5),
+			1: linksTo(2),
+			2: linksTo(3, 4, 5),
+			3: linksTo(4),
+			4: linksTo(5),
+			5: nil,
+
+			6: linksTo(7, 8, 9),
+			7: linksTo(8, 9),
+			8: linksTo(9),
+			9: nil,
+		},
+		wantChordal: true,
+		wantClique:  []int64{6, 7, 8, 9},
+		wantColors:  4,
+	},
+	{
+		name:        "Batagelj-Zaversnik",
+		g:           batageljZaversnikGraph,
+		wantChordal: false,
+	},
+}
+
+func TestChordal(t *testing.T) {
+	for _, test := range chordalTests {
+		g := simple.NewUndirectedGraph()
+		for u, e := range test.g {
+			// Add nodes that are not defined by an edge.
+			if !g.Has(simple.Node(u)) {
+				g.AddNode(simple.Node(u))
+			}
+			for v := range e {
+				g.SetEdge(simple.Edge{F: simple.Node(u), T: simple.Node(v)})
+			}
+		}
+
+		if got := IsChordal(g); got != test.wantChordal {
+			t.Errorf("unexpected chordality for %q: got:%t want:%t", test.name, got, test.wantChordal)
+		}
+
+		order, ok := PerfectEliminationOrdering(g)
+		if ok != test.wantChordal {
+			t.Errorf("unexpected perfect elimination ordering existence for %q: got:%t want:%t", test.name, ok, test.wantChordal)
+		}
+		if !ok {
+			if order != nil {
+				t.Errorf("unexpected non-nil ordering for non-chordal graph %q", test.name)
+			}
+			if _, ok := ChordalMaximumClique(g); ok {
+				t.Errorf("unexpected maximum clique for non-chordal graph %q", test.name)
+			}
+			if _, _, ok := ChordalColoring(g); ok {
+				t.Errorf("unexpected coloring for non-chordal graph %q", test.name)
+			}
+			continue
+		}
+		if len(order) != len(test.g) {
+			t.Errorf("unexpected ordering length for %q: got:%d want:%d", test.name, len(order), len(test.g))
+		}
+		if !isPerfectEliminationOrdering(g, order) {
+			t.Errorf("returned ordering for %q is not a perfect elimination ordering: %v", test.name, order)
+		}
+
+		clique, ok := ChordalMaximumClique(g)
+		if !ok {
+			t.Errorf("failed to find maximum clique for %q", test.name)
+		}
+		var got []int64
+		for _, n := range clique {
+			got = append(got, n.ID())
+		}
+		if !reflect.DeepEqual(got, test.wantClique) {
+			t.Errorf("unexpected maximum clique for %q: got:%v want:%v", test.name, got, test.wantClique)
+		}
+
+		colors, k, ok := ChordalColoring(g)
+		if !ok {
+			t.Errorf("failed to color %q", test.name)
+		}
+		if k != test.wantColors {
+			t.Errorf("unexpected number of colors for %q: got:%d want:%d", test.name, k, test.wantColors)
+		}
+		for _, u := range g.Nodes() {
+			for _, v := range g.From(u) {
+				if colors[u.ID()] == colors[v.ID()] {
+					t.Errorf("unexpected color conflict for %q: %d--%d", test.name, u.ID(), v.ID())
+				}
+			}
+		}
+	}
+}
+
+func TestLexBFS(t *testing.T) {
+	g := simple.NewUndirectedGraph()
+	for _, e := range []simple.Edge{
+		{F: simple.Node(0), T: simple.Node(3)},
+		{F: simple.Node(0), T: simple.Node(4)},
+		{F: simple.Node(1), T: simple.Node(2)},
+		{F: simple.Node(1), T: simple.Node(4)},
+		{F: simple.Node(2), T: simple.Node(4)},
+		{F: simple.Node(3), T: simple.Node(4)},
+	} {
+		g.SetEdge(e)
+	}
+	order := LexBFS(g)
+
+	// Every node after the first must have a lexicographic label
+	// that is not less than any node that follows it.
+	idx := make(map[int64]int)
+	for i, n := range order {
+		idx[n.ID()] = i
+	}
+	label := func(n graph.Node, before int) []int {
+		var l []int
+		for _, m := range order[:before] {
+			if g.HasEdgeBetween(n, m) {
+				l = append(l, len(order)-idx[m.ID()])
+			}
+		}
+		return l
+	}
+	for i := 1; i < len(order); i++ {
+		for j := i + 1; j < len(order); j++ {
+			if lexLess(label(order[i], i), label(order[j], i)) {
+				t.Errorf("invalid LexBFS ordering %v: node %d has smaller label than node %d",
+					order, order[i].ID(), order[j].ID())
+			}
+		}
+	}
+	want := []int64{0, 3, 4, 1, 2}
+	var got []int64
+	for _, n := range order {
+		got = append(got, n.ID())
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected LexBFS ordering: got:%v want:%v", got, want)
+	}
+}
+
+// lexLess returns whether a is lexicographically less than b when
+// both are ordered by descending value.
+func lexLess(a, b []int) bool {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		if a[i] != b[i] {
+			return a[i] < b[i]
+		}
+	}
+	return len(a) < len(b)
+}
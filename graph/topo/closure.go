@@ -0,0 +1,171 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package topo
+
+import (
+	"math"
+	"sort"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/internal/ordered"
+)
+
+// TransitiveClosure builds the transitive closure of g in dst. The nodes of
+// g are added to dst and an edge from u to v is set in dst for each pair of
+// distinct nodes u and v where v is reachable from u in g. Self edges are not
+// set for nodes on cycles. The dst graph is not cleared. TransitiveClosure
+// will panic if a node ID in g matches a node ID in dst.
+func TransitiveClosure(dst graph.Builder, g graph.Directed) {
+	r := NewReachabilityIndex(g)
+	for _, n := range r.nodes {
+		dst.AddNode(n)
+	}
+	for cu, u := range r.components {
+		for cv := cu; cv < len(r.components); cv++ {
+			if cv != cu && !r.reachable(cu, cv) {
+				continue
+			}
+			for _, x := range u {
+				for _, y := range r.components[cv] {
+					if x.ID() != y.ID() {
+						dst.SetEdge(dst.NewEdge(x, y))
+					}
+				}
+			}
+		}
+	}
+}
+
+// ReachabilityIndex answers reachability queries on a directed graph in
+// constant time after preprocessing.
+//
+// The index is built on the condensation of the graph, so any directed graph
+// may be indexed. The condensation is decomposed into chains, paths in which
+// each component reaches all following components, and each component is
+// labeled with the first position it reaches on every chain. The index uses
+// O(n·k) space and O(m·k) preprocessing time, where n and m are the number of
+// components and edges of the condensation and k is the number of chains, so
+// it is best suited to graphs whose condensation is narrow.
+type ReachabilityIndex struct {
+	nodes       []graph.Node
+	components  [][]graph.Node
+	componentOf map[int64]int
+
+	chains int
+	chain  []int
+	pos    []int
+
+	// reach holds the first position on
+	// each chain reachable from each
+	// component, in row-major order.
+	reach []int32
+}
+
+// NewReachabilityIndex returns a ReachabilityIndex for the directed graph g.
+// The index is not updated when g is modified.
+func NewReachabilityIndex(g graph.Directed) *ReachabilityIndex {
+	sccs := TarjanSCC(g)
+	n := len(sccs)
+	r := &ReachabilityIndex{
+		components:  make([][]graph.Node, n),
+		componentOf: make(map[int64]int),
+		chain:       make([]int, n),
+		pos:         make([]int, n),
+	}
+
+	// Number the components in topological order.
+	// TarjanSCC returns components in reverse
+	// topological order.
+	for i, scc := range sccs {
+		c := n - 1 - i
+		sort.Sort(ordered.ByID(scc))
+		r.components[c] = scc
+		for _, u := range scc {
+			r.componentOf[u.ID()] = c
+			r.nodes = append(r.nodes, u)
+		}
+	}
+	sort.Sort(ordered.ByID(r.nodes))
+
+	succ := make([][]int, n)
+	mark := make([]int, n)
+	for c, scc := range r.components {
+		for _, u := range scc {
+			for _, v := range g.From(u) {
+				d := r.componentOf[v.ID()]
+				if d == c || mark[d] == c+1 {
+					continue
+				}
+				mark[d] = c + 1
+				succ[c] = append(succ[c], d)
+			}
+		}
+	}
+
+	// Greedily decompose the condensation into chains
+	// following edges to unassigned successors.
+	for c := range r.chain {
+		r.chain[c] = -1
+	}
+	for c := range r.components {
+		if r.chain[c] >= 0 {
+			continue
+		}
+		k := r.chains
+		r.chains++
+		for u, p := c, 0; u >= 0; p++ {
+			r.chain[u] = k
+			r.pos[u] = p
+			next := -1
+			for _, v := range succ[u] {
+				if r.chain[v] < 0 {
+					next = v
+					break
+				}
+			}
+			u = next
+		}
+	}
+
+	// Label components in reverse topological order
+	// so that successors are labeled first.
+	r.reach = make([]int32, n*r.chains)
+	for c := n - 1; c >= 0; c-- {
+		row := r.reach[c*r.chains : (c+1)*r.chains]
+		for k := range row {
+			row[k] = math.MaxInt32
+		}
+		row[r.chain[c]] = int32(r.pos[c])
+		for _, d := range succ[c] {
+			for k, p := range r.reach[d*r.chains : (d+1)*r.chains] {
+				if p < row[k] {
+					row[k] = p
+				}
+			}
+		}
+	}
+
+	return r
+}
+
+// Reachable returns whether v is reachable from u. A node is reachable from
+// itself. Reachable returns false if either u or v was not in the indexed
+// graph.
+func (r *ReachabilityIndex) Reachable(u, v graph.Node) bool {
+	cu, ok := r.componentOf[u.ID()]
+	if !ok {
+		return false
+	}
+	cv, ok := r.componentOf[v.ID()]
+	if !ok {
+		return false
+	}
+	return r.reachable(cu, cv)
+}
+
+// reachable returns whether component cv is reachable from component cu.
+func (r *ReachabilityIndex) reachable(cu, cv int) bool {
+	return int(r.reach[cu*r.chains+r.chain[cv]]) <= r.pos[cv]
+}
@@ -0,0 +1,90 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package topo
+
+import (
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/simple"
+)
+
+// reachabilityGraphs returns the Tarjan test graphs and random
+// directed graphs, both cyclic and acyclic.
+func reachabilityGraphs() []graph.Directed {
+	var graphs []graph.Directed
+	for _, test := range tarjanTests {
+		g := simple.NewDirectedGraph()
+		for u, e := range test.g {
+			if !g.Has(simple.Node(u)) {
+				g.AddNode(simple.Node(u))
+			}
+			for v := range e {
+				g.SetEdge(simple.Edge{F: simple.Node(u), T: simple.Node(v)})
+			}
+		}
+		graphs = append(graphs, g)
+	}
+	rnd := rand.New(rand.NewSource(1))
+	for _, acyclic := range []bool{true, false} {
+		for trial := 0; trial < 5; trial++ {
+			const n = 40
+			g := simple.NewDirectedGraph()
+			for i := 0; i < n; i++ {
+				g.AddNode(simple.Node(i))
+			}
+			for k := 0; k < 60; k++ {
+				u, v := rnd.Intn(n), rnd.Intn(n)
+				if u == v || (acyclic && u > v) {
+					continue
+				}
+				g.SetEdge(simple.Edge{F: simple.Node(u), T: simple.Node(v)})
+			}
+			graphs = append(graphs, g)
+		}
+	}
+	return graphs
+}
+
+func TestReachabilityIndex(t *testing.T) {
+	for i, g := range reachabilityGraphs() {
+		r := NewReachabilityIndex(g)
+		nodes := g.Nodes()
+		for _, u := range nodes {
+			for _, v := range nodes {
+				want := u.ID() == v.ID() || PathExistsIn(g, u, v)
+				if got := r.Reachable(u, v); got != want {
+					t.Errorf("unexpected reachability for test %d from %d to %d: got:%t want:%t",
+						i, u.ID(), v.ID(), got, want)
+				}
+			}
+		}
+		if r.Reachable(simple.Node(-1), nodes[0]) || r.Reachable(nodes[0], simple.Node(-1)) {
+			t.Errorf("unexpected reachability for missing node in test %d", i)
+		}
+	}
+}
+
+func TestTransitiveClosure(t *testing.T) {
+	for i, g := range reachabilityGraphs() {
+		dst := simple.NewDirectedGraph()
+		TransitiveClosure(dst, g)
+		nodes := g.Nodes()
+		if len(dst.Nodes()) != len(nodes) {
+			t.Errorf("unexpected number of nodes for test %d: got:%d want:%d", i, len(dst.Nodes()), len(nodes))
+		}
+		for _, u := range nodes {
+			for _, v := range nodes {
+				want := u.ID() != v.ID() && PathExistsIn(g, u, v)
+				if got := dst.HasEdgeFromTo(u, v); got != want {
+					t.Errorf("unexpected closure edge for test %d from %d to %d: got:%t want:%t",
+						i, u.ID(), v.ID(), got, want)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,186 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package topo
+
+import (
+	"sort"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/internal/ordered"
+)
+
+// Condensation is the condensation of a directed graph. Each node of a
+// Condensation is a Component holding the nodes of a strongly connected
+// component of the original graph, and an edge exists from one Component to
+// another if any edge exists in the original graph from a member of the first
+// to a member of the second. A Condensation is always acyclic.
+type Condensation struct {
+	components  []Component
+	componentOf map[int64]int64
+
+	from map[int64]map[int64]*CondensationEdge
+	to   map[int64]map[int64]*CondensationEdge
+}
+
+var _ graph.Directed = (*Condensation)(nil)
+
+// Condense returns the condensation of the directed graph g. The IDs of the
+// Components of the returned graph are numbered from zero in a topological
+// ordering of the condensation.
+func Condense(g graph.Directed) *Condensation {
+	sccs := TarjanSCC(g)
+
+	c := &Condensation{
+		components:  make([]Component, len(sccs)),
+		componentOf: make(map[int64]int64),
+		from:        make(map[int64]map[int64]*CondensationEdge, len(sccs)),
+		to:          make(map[int64]map[int64]*CondensationEdge, len(sccs)),
+	}
+
+	// TarjanSCC returns components in reverse topological order.
+	for i, scc := range sccs {
+		id := int64(len(sccs) - 1 - i)
+		sort.Sort(ordered.ByID(scc))
+		c.components[id] = Component{id: id, nodes: scc}
+		for _, n := range scc {
+			c.componentOf[n.ID()] = id
+		}
+		c.from[id] = make(map[int64]*CondensationEdge)
+		c.to[id] = make(map[int64]*CondensationEdge)
+	}
+
+	for _, u := range c.components {
+		for _, n := range u.nodes {
+			for _, m := range g.From(n) {
+				vid := c.componentOf[m.ID()]
+				if vid == u.id {
+					continue
+				}
+				e, ok := c.from[u.id][vid]
+				if !ok {
+					e = &CondensationEdge{from: u, to: c.components[vid]}
+					c.from[u.id][vid] = e
+					c.to[vid][u.id] = e
+				}
+				e.edges = append(e.edges, g.Edge(n, m))
+			}
+		}
+	}
+
+	return c
+}
+
+// ComponentOf returns the Component of the condensation holding the node n
+// of the original graph, and whether n was in the original graph.
+func (c *Condensation) ComponentOf(n graph.Node) (Component, bool) {
+	id, ok := c.componentOf[n.ID()]
+	if !ok {
+		return Component{}, false
+	}
+	return c.components[id], true
+}
+
+// Component returns the Component with the given ID, and whether it exists
+// in the condensation.
+func (c *Condensation) Component(id int64) (Component, bool) {
+	if id < 0 || int64(len(c.components)) <= id {
+		return Component{}, false
+	}
+	return c.components[id], true
+}
+
+// Has returns whether the node exists within the graph.
+func (c *Condensation) Has(n graph.Node) bool {
+	id := n.ID()
+	return 0 <= id && id < int64(len(c.components))
+}
+
+// Nodes returns all the Components in the graph ordered by ID.
+func (c *Condensation) Nodes() []graph.Node {
+	if len(c.components) == 0 {
+		return nil
+	}
+	nodes := make([]graph.Node, len(c.components))
+	for i, n := range c.components {
+		nodes[i] = n
+	}
+	return nodes
+}
+
+// From returns all Components in c that can be reached directly from n.
+func (c *Condensation) From(n graph.Node) []graph.Node {
+	if !c.Has(n) {
+		return nil
+	}
+	from := make([]graph.Node, 0, len(c.from[n.ID()]))
+	for id := range c.from[n.ID()] {
+		from = append(from, c.components[id])
+	}
+	return from
+}
+
+// To returns all Components in c that can reach directly to n.
+func (c *Condensation) To(n graph.Node) []graph.Node {
+	if !c.Has(n) {
+		return nil
+	}
+	to := make([]graph.Node, 0, len(c.to[n.ID()]))
+	for id := range c.to[n.ID()] {
+		to = append(to, c.components[id])
+	}
+	return to
+}
+
+// HasEdgeBetween returns whether an edge exists between nodes x and y without
+// considering direction.
+func (c *Condensation) HasEdgeBetween(x, y graph.Node) bool {
+	return c.HasEdgeFromTo(x, y) || c.HasEdgeFromTo(y, x)
+}
+
+// HasEdgeFromTo returns whether an edge exists in the graph from u to v.
+func (c *Condensation) HasEdgeFromTo(u, v graph.Node) bool {
+	_, ok := c.from[u.ID()][v.ID()]
+	return ok
+}
+
+// Edge returns the edge from u to v if such an edge exists and nil otherwise.
+// The node v must be directly reachable from u as defined by the From method.
+// The returned edge is a *CondensationEdge.
+func (c *Condensation) Edge(u, v graph.Node) graph.Edge {
+	e, ok := c.from[u.ID()][v.ID()]
+	if !ok {
+		return nil
+	}
+	return e
+}
+
+// Component is a node in a condensation graph.
+type Component struct {
+	id    int64
+	nodes []graph.Node
+}
+
+// ID returns the node ID.
+func (n Component) ID() int64 { return n.id }
+
+// Nodes returns the nodes of the original graph in the strongly connected
+// component, sorted by ID.
+func (n Component) Nodes() []graph.Node { return n.nodes }
+
+// CondensationEdge is an edge in a condensation graph.
+type CondensationEdge struct {
+	from, to Component
+	edges    []graph.Edge
+}
+
+// From returns the from node of the edge.
+func (e *CondensationEdge) From() graph.Node { return e.from }
+
+// To returns the to node of the edge.
+func (e *CondensationEdge) To() graph.Node { return e.to }
+
+// Edges returns the edges of the original graph that join members of the
+// from and to Components.
+func (e *CondensationEdge) Edges() []graph.Edge { return e.edges }
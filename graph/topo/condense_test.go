@@ -0,0 +1,87 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package topo
+
+import (
+	"testing"
+
+	"gonum.org/v1/gonum/graph/simple"
+)
+
+func TestCondense(t *testing.T) {
+	for i, test := range tarjanTests {
+		g := simple.NewDirectedGraph()
+		for u, e := range test.g {
+			// Add nodes that are not defined by an edge.
+			if !g.Has(simple.Node(u)) {
+				g.AddNode(simple.Node(u))
+			}
+			for v := range e {
+				g.SetEdge(simple.Edge{F: simple.Node(u), T: simple.Node(v)})
+			}
+		}
+
+		c := Condense(g)
+		if len(c.Nodes()) != len(test.want) {
+			t.Errorf("unexpected number of components for test %d: got:%d want:%d", i, len(c.Nodes()), len(test.want))
+		}
+
+		// Components must partition the nodes of g.
+		seen := make(map[int64]bool)
+		for _, cn := range c.Nodes() {
+			comp, ok := c.Component(cn.ID())
+			if !ok {
+				t.Errorf("missing component %d for test %d", cn.ID(), i)
+				continue
+			}
+			for _, n := range comp.Nodes() {
+				if seen[n.ID()] {
+					t.Errorf("node %d in more than one component for test %d", n.ID(), i)
+				}
+				seen[n.ID()] = true
+				got, ok := c.ComponentOf(n)
+				if !ok || got.ID() != comp.ID() {
+					t.Errorf("unexpected component for node %d in test %d: got:%d want:%d", n.ID(), i, got.ID(), comp.ID())
+				}
+			}
+		}
+		if len(seen) != len(g.Nodes()) {
+			t.Errorf("unexpected number of nodes in components for test %d: got:%d want:%d", i, len(seen), len(g.Nodes()))
+		}
+
+		// Component IDs must be a topological ordering
+		// and edges must reflect the original graph.
+		if !DirectedAcyclic(c) {
+			t.Errorf("condensation of test %d is not acyclic", i)
+		}
+		for _, u := range c.Nodes() {
+			for _, v := range c.From(u) {
+				if u.ID() >= v.ID() {
+					t.Errorf("component IDs not topologically ordered for test %d: %d->%d", i, u.ID(), v.ID())
+				}
+				e := c.Edge(u, v).(*CondensationEdge)
+				if len(e.Edges()) == 0 {
+					t.Errorf("no underlying edges for %d->%d in test %d", u.ID(), v.ID(), i)
+				}
+				for _, ue := range e.Edges() {
+					fc, _ := c.ComponentOf(ue.From())
+					tc, _ := c.ComponentOf(ue.To())
+					if fc.ID() != u.ID() || tc.ID() != v.ID() {
+						t.Errorf("unexpected underlying edge for %d->%d in test %d: %d->%d", u.ID(), v.ID(), i, ue.From().ID(), ue.To().ID())
+					}
+				}
+			}
+		}
+		for _, u := range g.Nodes() {
+			uc, _ := c.ComponentOf(u)
+			for _, v := range g.From(u) {
+				vc, _ := c.ComponentOf(v)
+				if uc.ID() != vc.ID() && !c.HasEdgeFromTo(uc, vc) {
+					t.Errorf("missing condensation edge for %d->%d in test %d", u.ID(), v.ID(), i)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,211 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package topo
+
+import (
+	"fmt"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/internal/set"
+	"gonum.org/v1/gonum/graph/uid"
+)
+
+// DynamicConnectivity maintains the connected components of an undirected
+// graph under node insertion and edge insertion and removal.
+//
+// Edge insertion merges the components of the end points by relabeling the
+// nodes of the smaller component, so a sequence of insertions costs
+// O(n log n) relabelings in total. Edge removal interleaves breadth-first
+// searches from both end points and stops when the searches meet or when one
+// of them exhausts its side of a split component. When the removal splits the
+// component, the work done is bounded by the size of the smaller side. When
+// it does not, the searches may each explore most of the component before
+// meeting, so the work done is bounded only by the size of the component.
+type DynamicConnectivity struct {
+	nodes map[int64]graph.Node
+	adj   map[int64]set.Int64s
+
+	// comp holds the component ID of each node and
+	// members holds the node IDs of each component.
+	comp    map[int64]int64
+	members map[int64]set.Int64s
+	ids     uid.Set
+}
+
+// NewDynamicConnectivity returns a new empty DynamicConnectivity.
+func NewDynamicConnectivity() *DynamicConnectivity {
+	return &DynamicConnectivity{
+		nodes:   make(map[int64]graph.Node),
+		adj:     make(map[int64]set.Int64s),
+		comp:    make(map[int64]int64),
+		members: make(map[int64]set.Int64s),
+		ids:     uid.NewSet(),
+	}
+}
+
+// AddNode adds n as a new single node component. It panics if the added
+// node ID matches an existing node ID.
+func (c *DynamicConnectivity) AddNode(n graph.Node) {
+	id := n.ID()
+	if _, exists := c.nodes[id]; exists {
+		panic(fmt.Sprintf("topo: node ID collision: %d", id))
+	}
+	c.nodes[id] = n
+	c.adj[id] = make(set.Int64s)
+	cid := c.ids.NewID()
+	c.ids.Use(cid)
+	c.comp[id] = cid
+	c.members[cid] = set.Int64s{id: struct{}{}}
+}
+
+// Has returns whether the node exists within the graph.
+func (c *DynamicConnectivity) Has(n graph.Node) bool {
+	_, ok := c.nodes[n.ID()]
+	return ok
+}
+
+// HasEdgeBetween returns whether an edge exists between nodes x and y.
+func (c *DynamicConnectivity) HasEdgeBetween(x, y graph.Node) bool {
+	return c.adj[x.ID()].Has(y.ID())
+}
+
+// AddEdge adds an edge between the From and To nodes of e, merging their
+// components if they differ. Nodes that do not exist are added. Adding an
+// existing edge or a self edge does not alter the components.
+func (c *DynamicConnectivity) AddEdge(e graph.Edge) {
+	u, v := e.From(), e.To()
+	if !c.Has(u) {
+		c.AddNode(u)
+	}
+	if !c.Has(v) {
+		c.AddNode(v)
+	}
+	uid, vid := u.ID(), v.ID()
+	if uid == vid || c.adj[uid].Has(vid) {
+		return
+	}
+	c.adj[uid].Add(vid)
+	c.adj[vid].Add(uid)
+
+	cu, cv := c.comp[uid], c.comp[vid]
+	if cu == cv {
+		return
+	}
+	if c.members[cu].Count() < c.members[cv].Count() {
+		cu, cv = cv, cu
+	}
+	dst := c.members[cu]
+	for id := range c.members[cv] {
+		dst.Add(id)
+		c.comp[id] = cu
+	}
+	delete(c.members, cv)
+	c.ids.Release(cv)
+}
+
+// RemoveEdge removes the edge between the From and To nodes of e, splitting
+// their component if the edge was the only path between them. Removing an
+// edge that does not exist is a no-op.
+func (c *DynamicConnectivity) RemoveEdge(e graph.Edge) {
+	uid, vid := e.From().ID(), e.To().ID()
+	if !c.adj[uid].Has(vid) {
+		return
+	}
+	c.adj[uid].Remove(vid)
+	c.adj[vid].Remove(uid)
+
+	// Search outwards from both end points one node at
+	// a time. If the searches meet the component is intact,
+	// otherwise the first search to run out of nodes has
+	// found the whole of its new component.
+	a := newConnSearch(uid)
+	b := newConnSearch(vid)
+	for {
+		for _, s := range [2]*connSearch{a, b} {
+			other := b
+			if s == b {
+				other = a
+			}
+			if len(s.queue) == 0 {
+				c.split(s.seen)
+				return
+			}
+			n := s.queue[0]
+			s.queue = s.queue[1:]
+			for w := range c.adj[n] {
+				if other.seen.Has(w) {
+					return
+				}
+				if !s.seen.Has(w) {
+					s.seen.Add(w)
+					s.queue = append(s.queue, w)
+				}
+			}
+		}
+	}
+}
+
+// connSearch holds the state of a breadth-first search.
+type connSearch struct {
+	seen  set.Int64s
+	queue []int64
+}
+
+func newConnSearch(id int64) *connSearch {
+	return &connSearch{seen: set.Int64s{id: struct{}{}}, queue: []int64{id}}
+}
+
+// split moves the nodes in part from their current component to
+// a new component.
+func (c *DynamicConnectivity) split(part set.Int64s) {
+	cid := c.ids.NewID()
+	c.ids.Use(cid)
+	for id := range part {
+		c.members[c.comp[id]].Remove(id)
+		c.comp[id] = cid
+	}
+	c.members[cid] = part
+}
+
+// Connected returns whether u and v are in the same connected component.
+// Connected returns false if either node does not exist.
+func (c *DynamicConnectivity) Connected(u, v graph.Node) bool {
+	cu, ok := c.comp[u.ID()]
+	if !ok {
+		return false
+	}
+	cv, ok := c.comp[v.ID()]
+	return ok && cu == cv
+}
+
+// ComponentID returns the ID of the connected component holding n, or -1
+// if n does not exist. Component IDs are stable until the component is
+// merged or split by an edge insertion or removal, and the IDs of
+// components that no longer exist may be reused.
+func (c *DynamicConnectivity) ComponentID(n graph.Node) int64 {
+	cid, ok := c.comp[n.ID()]
+	if !ok {
+		return -1
+	}
+	return cid
+}
+
+// Count returns the number of connected components.
+func (c *DynamicConnectivity) Count() int {
+	return len(c.members)
+}
+
+// Component returns the nodes in the connected component holding n.
+func (c *DynamicConnectivity) Component(n graph.Node) []graph.Node {
+	cid, ok := c.comp[n.ID()]
+	if !ok {
+		return nil
+	}
+	nodes := make([]graph.Node, 0, c.members[cid].Count())
+	for id := range c.members[cid] {
+		nodes = append(nodes, c.nodes[id])
+	}
+	return nodes
+}
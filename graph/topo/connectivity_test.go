@@ -0,0 +1,79 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package topo
+
+import (
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/graph/simple"
+)
+
+func TestDynamicConnectivity(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for trial := 0; trial < 20; trial++ {
+		const n = 30
+		c := NewDynamicConnectivity()
+		g := simple.NewUndirectedGraph()
+		for i := 0; i < n; i++ {
+			c.AddNode(simple.Node(i))
+			g.AddNode(simple.Node(i))
+		}
+		for k := 0; k < 300; k++ {
+			u := simple.Node(rnd.Intn(n))
+			v := simple.Node(rnd.Intn(n))
+			e := simple.Edge{F: u, T: v}
+			if rnd.Float64() < 0.4 {
+				c.RemoveEdge(e)
+				g.RemoveEdge(e)
+			} else {
+				c.AddEdge(e)
+				if u != v {
+					g.SetEdge(e)
+				}
+			}
+
+			cc := ConnectedComponents(g)
+			if c.Count() != len(cc) {
+				t.Fatalf("unexpected number of components in trial %d step %d: got:%d want:%d",
+					trial, k, c.Count(), len(cc))
+			}
+			for _, comp := range cc {
+				id := c.ComponentID(comp[0])
+				for _, x := range comp {
+					if c.ComponentID(x) != id {
+						t.Fatalf("unexpected component ID for %d in trial %d step %d: got:%d want:%d",
+							x.ID(), trial, k, c.ComponentID(x), id)
+					}
+				}
+				if len(c.Component(comp[0])) != len(comp) {
+					t.Fatalf("unexpected component size in trial %d step %d: got:%d want:%d",
+						trial, k, len(c.Component(comp[0])), len(comp))
+				}
+			}
+			want := PathExistsIn(g, u, v)
+			if got := c.Connected(u, v); got != want {
+				t.Fatalf("unexpected connectivity between %d and %d in trial %d step %d: got:%t want:%t",
+					u, v, trial, k, got, want)
+			}
+		}
+	}
+}
+
+func TestDynamicConnectivityAbsent(t *testing.T) {
+	c := NewDynamicConnectivity()
+	c.AddEdge(simple.Edge{F: simple.Node(0), T: simple.Node(1)})
+	if c.Connected(simple.Node(0), simple.Node(2)) {
+		t.Error("unexpected connection to absent node")
+	}
+	if id := c.ComponentID(simple.Node(2)); id != -1 {
+		t.Errorf("unexpected component ID for absent node: got:%d want:-1", id)
+	}
+	c.RemoveEdge(simple.Edge{F: simple.Node(0), T: simple.Node(2)})
+	if !c.Connected(simple.Node(0), simple.Node(1)) {
+		t.Error("unexpected disconnection after removing absent edge")
+	}
+}
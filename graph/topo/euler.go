@@ -0,0 +1,178 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package topo
+
+import (
+	"sort"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/internal/ordered"
+)
+
+// HasEulerianPath returns whether g has an Eulerian path, a walk that uses
+// every edge of g exactly once. If g is a graph.Directed, edge directions are
+// respected. A graph with no edges has an empty Eulerian path.
+func HasEulerianPath(g graph.Graph) bool {
+	_, _, ok := eulerian(g, false)
+	return ok
+}
+
+// HasEulerianCircuit returns whether g has an Eulerian circuit, a closed walk
+// that uses every edge of g exactly once. If g is a graph.Directed, edge
+// directions are respected. A graph with no edges has an empty Eulerian
+// circuit.
+func HasEulerianCircuit(g graph.Graph) bool {
+	_, _, ok := eulerian(g, true)
+	return ok
+}
+
+// EulerianPath returns an Eulerian path of g, a walk that uses every edge
+// of g exactly once, constructed using Hierholzer's algorithm. The walk is
+// returned as the sequence of nodes visited and the sequence of edges
+// traversed, with edges[i] joining walk[i] and walk[i+1]. If g is a
+// graph.Directed, edge directions are respected; otherwise the edges are
+// those returned by g.Edge and may be oriented against the direction of the
+// walk.
+//
+// If g has an Eulerian circuit, a circuit is returned. Otherwise, for an
+// undirected graph the path starts at the lower ID of the two nodes with odd
+// degree, and for a directed graph it starts at the node with one more
+// outgoing than incoming edge. If g has no Eulerian path, ok is false. If g
+// has no edges, walk and edges are empty and ok is true.
+func EulerianPath(g graph.Graph) (walk []graph.Node, edges []graph.Edge, ok bool) {
+	return eulerian(g, false)
+}
+
+// EulerianCircuit returns an Eulerian circuit of g, a closed walk that uses
+// every edge of g exactly once, constructed using Hierholzer's algorithm.
+// The walk is returned as for EulerianPath and starts and ends at the lowest
+// ID node with an edge. If g has no Eulerian circuit, ok is false. If g has
+// no edges, walk and edges are empty and ok is true.
+func EulerianCircuit(g graph.Graph) (walk []graph.Node, edges []graph.Edge, ok bool) {
+	return eulerian(g, true)
+}
+
+// eulerian returns an Eulerian path of g, or an Eulerian circuit if circuit
+// is true, and whether one exists.
+func eulerian(g graph.Graph, circuit bool) (walk []graph.Node, edges []graph.Edge, ok bool) {
+	_, isDirected := g.(graph.Directed)
+
+	nodes := g.Nodes()
+	sort.Sort(ordered.ByID(nodes))
+	indexOf := make(map[int64]int, len(nodes))
+	for i, n := range nodes {
+		indexOf[n.ID()] = i
+	}
+
+	// Construct an edge-indexed adjacency list with
+	// the number of edges leaving and entering each
+	// node. For undirected graphs the in and out
+	// counts are both the degree.
+	type halfEdge struct {
+		to, edge int
+	}
+	adj := make([][]halfEdge, len(nodes))
+	out := make([]int, len(nodes))
+	in := make([]int, len(nodes))
+	var ends [][2]int
+	for i, u := range nodes {
+		to := g.From(u)
+		sort.Sort(ordered.ByID(to))
+		for _, v := range to {
+			j := indexOf[v.ID()]
+			if !isDirected && j < i {
+				continue
+			}
+			e := len(ends)
+			ends = append(ends, [2]int{i, j})
+			adj[i] = append(adj[i], halfEdge{to: j, edge: e})
+			out[i]++
+			in[j]++
+			if !isDirected {
+				if j != i {
+					adj[j] = append(adj[j], halfEdge{to: i, edge: e})
+				}
+				out[j]++
+				in[i]++
+			}
+		}
+	}
+	if len(ends) == 0 {
+		return nil, nil, true
+	}
+
+	// Count the nodes with unbalanced degree. For a
+	// directed graph these are nodes whose out and in
+	// degrees differ, and for an undirected graph they
+	// are nodes with odd degree. A path must start at
+	// an unbalanced node if there is one.
+	balanced := func(i int) bool {
+		if isDirected {
+			return out[i] == in[i]
+		}
+		return out[i]%2 == 0
+	}
+	start := -1
+	var unbalanced int
+	for i := range nodes {
+		if d := out[i] - in[i]; d < -1 || 1 < d {
+			return nil, nil, false
+		}
+		switch {
+		case !balanced(i):
+			unbalanced++
+			if (!isDirected || out[i] > in[i]) && (start < 0 || balanced(start)) {
+				start = i
+			}
+		case start < 0 && out[i] != 0:
+			start = i
+		}
+	}
+	if unbalanced != 0 && (circuit || unbalanced != 2) {
+		return nil, nil, false
+	}
+
+	// Walk the edges using Hierholzer's algorithm,
+	// collecting the walk in reverse as nodes are
+	// retired from the stack.
+	used := make([]bool, len(ends))
+	next := make([]int, len(nodes))
+	type step struct {
+		node, edge int
+	}
+	stack := []step{{node: start, edge: -1}}
+	var rev []step
+	for len(stack) != 0 {
+		s := stack[len(stack)-1]
+		u := s.node
+		for next[u] < len(adj[u]) && used[adj[u][next[u]].edge] {
+			next[u]++
+		}
+		if next[u] == len(adj[u]) {
+			stack = stack[:len(stack)-1]
+			rev = append(rev, s)
+			continue
+		}
+		h := adj[u][next[u]]
+		used[h.edge] = true
+		stack = append(stack, step{node: h.to, edge: h.edge})
+	}
+	if len(rev) != len(ends)+1 {
+		// Edges are in more than one component.
+		return nil, nil, false
+	}
+
+	walk = make([]graph.Node, len(rev))
+	edges = make([]graph.Edge, len(ends))
+	for i := range rev {
+		s := rev[len(rev)-1-i]
+		walk[i] = nodes[s.node]
+		if s.edge >= 0 {
+			e := ends[s.edge]
+			edges[i-1] = g.Edge(nodes[e[0]], nodes[e[1]])
+		}
+	}
+	return walk, edges, true
+}
@@ -0,0 +1,206 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package topo
+
+import (
+	"testing"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/simple"
+)
+
+var eulerianTests = []struct {
+	name     string
+	directed bool
+	nodes    []int64
+	edges    [][2]int64
+
+	wantPath    bool
+	wantCircuit bool
+	wantStart   int64
+}{
+	{
+		name:        "empty",
+		wantPath:    true,
+		wantCircuit: true,
+	},
+	{
+		name:        "isolated",
+		nodes:       []int64{0, 1},
+		wantPath:    true,
+		wantCircuit: true,
+	},
+	{
+		name:      "path",
+		edges:     [][2]int64{{0, 1}, {1, 2}, {2, 3}},
+		wantPath:  true,
+		wantStart: 0,
+	},
+	{
+		name:        "triangle",
+		edges:       [][2]int64{{0, 1}, {1, 2}, {2, 0}},
+		wantPath:    true,
+		wantCircuit: true,
+		wantStart:   0,
+	},
+	{
+		// Two triangles sharing node 2.
+		name:        "bowtie",
+		nodes:       []int64{7},
+		edges:       [][2]int64{{0, 1}, {1, 2}, {2, 0}, {2, 3}, {3, 4}, {4, 2}},
+		wantPath:    true,
+		wantCircuit: true,
+		wantStart:   0,
+	},
+	{
+		// The house graph has odd degree nodes 2 and 3.
+		name:      "house",
+		edges:     [][2]int64{{0, 1}, {1, 3}, {3, 2}, {2, 0}, {2, 4}, {3, 4}, {1, 2}},
+		wantPath:  true,
+		wantStart: 1,
+	},
+	{
+		name:  "star",
+		edges: [][2]int64{{0, 1}, {0, 2}, {0, 3}},
+	},
+	{
+		name:  "disconnected",
+		edges: [][2]int64{{0, 1}, {1, 2}, {2, 0}, {3, 4}, {4, 5}, {5, 3}},
+	},
+	{
+		name:     "directed path",
+		directed: true,
+		edges:    [][2]int64{{2, 1}, {1, 0}, {0, 3}},
+		wantPath: true,
+		// The path must start at the node
+		// with excess out degree.
+		wantStart: 2,
+	},
+	{
+		name:        "directed cycle",
+		directed:    true,
+		edges:       [][2]int64{{0, 1}, {1, 2}, {2, 0}},
+		wantPath:    true,
+		wantCircuit: true,
+		wantStart:   0,
+	},
+	{
+		name:        "directed two cycles",
+		directed:    true,
+		edges:       [][2]int64{{0, 1}, {1, 0}, {1, 2}, {2, 3}, {3, 1}},
+		wantPath:    true,
+		wantCircuit: true,
+		wantStart:   0,
+	},
+	{
+		name:     "directed converging",
+		directed: true,
+		edges:    [][2]int64{{0, 2}, {1, 2}},
+	},
+	{
+		name:     "directed large imbalance",
+		directed: true,
+		edges:    [][2]int64{{0, 1}, {0, 2}, {1, 3}, {2, 3}},
+	},
+	{
+		name:     "directed weakly disconnected",
+		directed: true,
+		edges:    [][2]int64{{0, 1}, {1, 0}, {2, 3}, {3, 2}},
+	},
+}
+
+func TestEulerian(t *testing.T) {
+	for _, test := range eulerianTests {
+		var g graph.Graph
+		if test.directed {
+			d := simple.NewDirectedGraph()
+			for _, id := range test.nodes {
+				d.AddNode(simple.Node(id))
+			}
+			for _, e := range test.edges {
+				d.SetEdge(simple.Edge{F: simple.Node(e[0]), T: simple.Node(e[1])})
+			}
+			g = d
+		} else {
+			u := simple.NewUndirectedGraph()
+			for _, id := range test.nodes {
+				u.AddNode(simple.Node(id))
+			}
+			for _, e := range test.edges {
+				u.SetEdge(simple.Edge{F: simple.Node(e[0]), T: simple.Node(e[1])})
+			}
+			g = u
+		}
+
+		if got := HasEulerianPath(g); got != test.wantPath {
+			t.Errorf("unexpected HasEulerianPath result for %q: got:%t want:%t", test.name, got, test.wantPath)
+		}
+		if got := HasEulerianCircuit(g); got != test.wantCircuit {
+			t.Errorf("unexpected HasEulerianCircuit result for %q: got:%t want:%t", test.name, got, test.wantCircuit)
+		}
+
+		walk, edges, ok := EulerianPath(g)
+		if ok != test.wantPath {
+			t.Errorf("unexpected EulerianPath ok for %q: got:%t want:%t", test.name, ok, test.wantPath)
+		}
+		if ok {
+			checkEulerianWalk(t, test.name, g, test.directed, len(test.edges), walk, edges, test.wantCircuit)
+			if len(walk) != 0 && walk[0].ID() != test.wantStart {
+				t.Errorf("unexpected path start for %q: got:%d want:%d", test.name, walk[0].ID(), test.wantStart)
+			}
+		} else if walk != nil || edges != nil {
+			t.Errorf("unexpected path for %q with no Eulerian path", test.name)
+		}
+
+		walk, edges, ok = EulerianCircuit(g)
+		if ok != test.wantCircuit {
+			t.Errorf("unexpected EulerianCircuit ok for %q: got:%t want:%t", test.name, ok, test.wantCircuit)
+		}
+		if ok {
+			checkEulerianWalk(t, test.name, g, test.directed, len(test.edges), walk, edges, true)
+		}
+	}
+}
+
+// checkEulerianWalk checks that walk and edges describe a walk in g that
+// uses each of its m edges exactly once and, if closed is true, that the
+// walk ends where it starts.
+func checkEulerianWalk(t *testing.T, name string, g graph.Graph, directed bool, m int, walk []graph.Node, edges []graph.Edge, closed bool) {
+	if len(edges) != m {
+		t.Errorf("unexpected number of edges for %q: got:%d want:%d", name, len(edges), m)
+		return
+	}
+	if m == 0 {
+		if len(walk) != 0 {
+			t.Errorf("unexpected non-empty walk for %q: %v", name, walk)
+		}
+		return
+	}
+	if len(walk) != m+1 {
+		t.Errorf("unexpected walk length for %q: got:%d want:%d", name, len(walk), m+1)
+		return
+	}
+	seen := make(map[[2]int64]bool)
+	for i, e := range edges {
+		u, v := walk[i].ID(), walk[i+1].ID()
+		f, to := e.From().ID(), e.To().ID()
+		switch {
+		case f == u && to == v:
+		case !directed && f == v && to == u:
+		default:
+			t.Errorf("edge %d of %q does not join walk nodes: edge:%d->%d walk:%d->%d", i, name, f, to, u, v)
+		}
+		if !directed && f > to {
+			f, to = to, f
+		}
+		if seen[[2]int64{f, to}] {
+			t.Errorf("edge %d->%d used more than once in %q", f, to, name)
+		}
+		seen[[2]int64{f, to}] = true
+	}
+	if closed && walk[0].ID() != walk[len(walk)-1].ID() {
+		t.Errorf("walk for %q not closed: starts at %d, ends at %d", name, walk[0].ID(), walk[len(walk)-1].ID())
+	}
+}
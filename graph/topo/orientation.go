@@ -0,0 +1,122 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package topo
+
+import (
+	"fmt"
+	"sort"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/internal/ordered"
+)
+
+// AcyclicOrientation copies the nodes of the undirected graph g into dst
+// and adds each edge of g to dst directed from the end point that occurs
+// earlier in order to the end point that occurs later. The resulting
+// directed graph is acyclic and order is a topological ordering of it.
+// If order is nil, nodes are ordered by ID. AcyclicOrientation will panic
+// if a node ID in g matches a node ID in dst or if order does not hold
+// every node of g.
+func AcyclicOrientation(dst graph.Builder, g graph.Undirected, order []graph.Node) {
+	nodes := g.Nodes()
+	if order == nil {
+		sort.Sort(ordered.ByID(nodes))
+		order = nodes
+	}
+	pos := make(map[int64]int, len(order))
+	for i, n := range order {
+		pos[n.ID()] = i
+	}
+	for _, n := range nodes {
+		if _, ok := pos[n.ID()]; !ok {
+			panic(fmt.Sprintf("topo: node %d missing from order", n.ID()))
+		}
+		dst.AddNode(n)
+	}
+	for _, u := range nodes {
+		for _, v := range g.From(u) {
+			if pos[u.ID()] < pos[v.ID()] {
+				dst.SetEdge(dst.NewEdge(u, v))
+			}
+		}
+	}
+}
+
+// BalancedOrientation copies the nodes of the undirected graph g into dst
+// and adds each edge of g to dst with a direction chosen so that the in and
+// out degrees of every node differ by at most one. When every node of g has
+// even degree, the in and out degree of every node are equal and the
+// orientation is Eulerian. BalancedOrientation will panic if a node ID in g
+// matches a node ID in dst.
+//
+// The orientation is constructed by joining odd degree nodes to a virtual
+// node and orienting edges along Euler circuits of the augmented graph.
+func BalancedOrientation(dst graph.Builder, g graph.Undirected) {
+	nodes := g.Nodes()
+	sort.Sort(ordered.ByID(nodes))
+	indexOf := make(map[int64]int, len(nodes))
+	for i, n := range nodes {
+		indexOf[n.ID()] = i
+		dst.AddNode(n)
+	}
+
+	// Construct an edge-indexed adjacency list, using
+	// index len(nodes) for the virtual node. Virtual
+	// edges are held after the edges of g in ends.
+	type halfEdge struct {
+		to, edge int
+	}
+	virtual := len(nodes)
+	adj := make([][]halfEdge, len(nodes)+1)
+	var ends [][2]int
+	for i, u := range nodes {
+		to := g.From(u)
+		sort.Sort(ordered.ByID(to))
+		for _, v := range to {
+			j := indexOf[v.ID()]
+			if j < i {
+				continue
+			}
+			e := len(ends)
+			ends = append(ends, [2]int{i, j})
+			adj[i] = append(adj[i], halfEdge{to: j, edge: e})
+			adj[j] = append(adj[j], halfEdge{to: i, edge: e})
+		}
+	}
+	nReal := len(ends)
+	for i := range nodes {
+		if len(adj[i])%2 == 0 {
+			continue
+		}
+		e := len(ends)
+		ends = append(ends, [2]int{i, virtual})
+		adj[i] = append(adj[i], halfEdge{to: virtual, edge: e})
+		adj[virtual] = append(adj[virtual], halfEdge{to: i, edge: e})
+	}
+
+	// Walk Euler circuits using Hierholzer's algorithm,
+	// orienting each edge in the direction it is walked.
+	used := make([]bool, len(ends))
+	next := make([]int, len(adj))
+	for start := range adj {
+		stack := []int{start}
+		for len(stack) != 0 {
+			u := stack[len(stack)-1]
+			for next[u] < len(adj[u]) && used[adj[u][next[u]].edge] {
+				next[u]++
+			}
+			if next[u] == len(adj[u]) {
+				stack = stack[:len(stack)-1]
+				continue
+			}
+			h := adj[u][next[u]]
+			used[h.edge] = true
+			if h.edge < nReal {
+				dst.SetEdge(dst.NewEdge(nodes[u], nodes[h.to]))
+			}
+			stack = append(stack, h.to)
+		}
+	}
+}
@@ -0,0 +1,129 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package topo
+
+import (
+	"testing"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/simple"
+)
+
+var orientationTests = []struct {
+	name string
+	g    []intset
+}{
+	{
+		name: "empty",
+	},
+	{
+		name: "triangle",
+		g: []intset{
+			0: linksTo(1, 2),
+			1: linksTo(2),
+			2: nil,
+		},
+	},
+	{
+		name: "star",
+		g: []intset{
+			0: linksTo(1, 2, 3, 4, 5),
+			1: nil,
+			2: nil,
+			3: nil,
+			4: nil,
+			5: nil,
+		},
+	},
+	{
+		name: "K5",
+		g: []intset{
+			0: linksTo(1, 2, 3, 4),
+			1: linksTo(2, 3, 4),
+			2: linksTo(3, 4),
+			3: linksTo(4),
+			4: nil,
+		},
+	},
+	{
+		name: "Batagelj-Zaversnik",
+		g:    batageljZaversnikGraph,
+	},
+}
+
+func undirectedFrom(g []intset) *simple.UndirectedGraph {
+	dg := simple.NewUndirectedGraph()
+	for u, e := range g {
+		// Add nodes that are not defined by an edge.
+		if !dg.Has(simple.Node(u)) {
+			dg.AddNode(simple.Node(u))
+		}
+		for v := range e {
+			dg.SetEdge(simple.Edge{F: simple.Node(u), T: simple.Node(v)})
+		}
+	}
+	return dg
+}
+
+func TestAcyclicOrientation(t *testing.T) {
+	for _, test := range orientationTests {
+		g := undirectedFrom(test.g)
+		order := g.Nodes()
+		dst := simple.NewDirectedGraph()
+		AcyclicOrientation(dst, g, order)
+		checkOrientation(t, test.name, dst, g)
+		if !DirectedAcyclic(dst) {
+			t.Errorf("orientation of %q is not acyclic", test.name)
+		}
+		pos := make(map[int64]int)
+		for i, n := range order {
+			pos[n.ID()] = i
+		}
+		for _, e := range dst.Edges() {
+			if pos[e.From().ID()] > pos[e.To().ID()] {
+				t.Errorf("edge %d->%d of %q does not follow order", e.From().ID(), e.To().ID(), test.name)
+			}
+		}
+	}
+}
+
+func TestBalancedOrientation(t *testing.T) {
+	for _, test := range orientationTests {
+		g := undirectedFrom(test.g)
+		dst := simple.NewDirectedGraph()
+		BalancedOrientation(dst, g)
+		checkOrientation(t, test.name, dst, g)
+		for _, n := range dst.Nodes() {
+			in, out := len(dst.To(n)), len(dst.From(n))
+			if in-out > 1 || out-in > 1 {
+				t.Errorf("unbalanced node %d in %q: in=%d out=%d", n.ID(), test.name, in, out)
+			}
+			if len(g.From(n))%2 == 0 && in != out {
+				t.Errorf("unbalanced even degree node %d in %q: in=%d out=%d", n.ID(), test.name, in, out)
+			}
+		}
+	}
+}
+
+// checkOrientation checks that dst is an orientation of g.
+func checkOrientation(t *testing.T, name string, dst graph.Directed, g graph.Undirected) {
+	if len(dst.Nodes()) != len(g.Nodes()) {
+		t.Errorf("unexpected number of nodes in orientation of %q: got:%d want:%d", name, len(dst.Nodes()), len(g.Nodes()))
+	}
+	for _, u := range g.Nodes() {
+		for _, v := range g.From(u) {
+			if dst.HasEdgeFromTo(u, v) == dst.HasEdgeFromTo(v, u) {
+				t.Errorf("edge %d--%d of %q not oriented exactly once", u.ID(), v.ID(), name)
+			}
+		}
+	}
+	for _, u := range dst.Nodes() {
+		for _, v := range dst.From(u) {
+			if !g.HasEdgeBetween(u, v) {
+				t.Errorf("unexpected edge %d->%d in orientation of %q", u.ID(), v.ID(), name)
+			}
+		}
+	}
+}
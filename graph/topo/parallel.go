@@ -0,0 +1,171 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package topo
+
+import (
+	"runtime"
+	"sort"
+	"sync"
+	"sync/atomic"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/internal/ordered"
+)
+
+// ParallelConnectedComponents returns the connected components of the
+// undirected graph g using concurrent label propagation over a compressed
+// sparse row representation of g. Work is partitioned between workers
+// goroutines. If workers is less than one, runtime.GOMAXPROCS(0) workers
+// are used.
+//
+// Each node is initially labeled with its own index and labels are lowered
+// to the least label among the neighbors of each node using atomic updates,
+// with labels shortcut to the label of their labeling node, until no label
+// changes. The returned components are ordered by their lowest node ID and
+// the nodes of each component are sorted by ID. The methods of g must be
+// safe for concurrent use.
+func ParallelConnectedComponents(g graph.Undirected, workers int) [][]graph.Node {
+	if workers < 1 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	nodes := g.Nodes()
+	sort.Sort(ordered.ByID(nodes))
+	c := newCSR(g, nodes, workers)
+
+	label := make([]int64, len(nodes))
+	for i := range label {
+		label[i] = int64(i)
+	}
+	for {
+		var changed int32
+		parallelFor(len(nodes), workers, func(lo, hi int) {
+			var local bool
+			for u := lo; u < hi; u++ {
+				lu := atomic.LoadInt64(&label[u])
+				for _, v := range c.adj[c.off[u]:c.off[u+1]] {
+					lv := atomic.LoadInt64(&label[v])
+					switch {
+					case lv < lu:
+						if lowerLabel(label, u, lv) {
+							local = true
+						}
+						lu = lv
+					case lu < lv:
+						if lowerLabel(label, int(v), lu) {
+							local = true
+						}
+					}
+				}
+				// Shortcut to the label of the node
+				// labeling u, which is in the same
+				// component and may have been lowered.
+				if ll := atomic.LoadInt64(&label[lu]); ll < lu {
+					if lowerLabel(label, u, ll) {
+						local = true
+					}
+				}
+			}
+			if local {
+				atomic.StoreInt32(&changed, 1)
+			}
+		})
+		if changed == 0 {
+			break
+		}
+	}
+
+	// Nodes are sorted by ID, so each component
+	// is labeled with the index of its lowest
+	// ID node and components are found in order.
+	var cc [][]graph.Node
+	index := make(map[int64]int)
+	for i, n := range nodes {
+		l := label[i]
+		k, ok := index[l]
+		if !ok {
+			k = len(cc)
+			index[l] = k
+			cc = append(cc, nil)
+		}
+		cc[k] = append(cc[k], n)
+	}
+	return cc
+}
+
+// lowerLabel atomically sets the label of node i to l if l is
+// less than its current label and returns whether the label was
+// changed.
+func lowerLabel(label []int64, i int, l int64) bool {
+	for {
+		old := atomic.LoadInt64(&label[i])
+		if old <= l {
+			return false
+		}
+		if atomic.CompareAndSwapInt64(&label[i], old, l) {
+			return true
+		}
+	}
+}
+
+// csr is a compressed sparse row adjacency representation of a graph.
+// The neighbors of the node with index i are held in adj[off[i]:off[i+1]].
+type csr struct {
+	off []int
+	adj []int64
+}
+
+// newCSR returns the compressed sparse row representation of g with node
+// indices given by the positions of the nodes in nodes. The neighbors of
+// nodes are found concurrently by workers goroutines.
+func newCSR(g graph.Undirected, nodes []graph.Node, workers int) csr {
+	indexOf := make(map[int64]int64, len(nodes))
+	for i, n := range nodes {
+		indexOf[n.ID()] = int64(i)
+	}
+	to := make([][]int64, len(nodes))
+	parallelFor(len(nodes), workers, func(lo, hi int) {
+		for i := lo; i < hi; i++ {
+			from := g.From(nodes[i])
+			adj := make([]int64, 0, len(from))
+			for _, v := range from {
+				adj = append(adj, indexOf[v.ID()])
+			}
+			to[i] = adj
+		}
+	})
+
+	c := csr{off: make([]int, len(nodes)+1)}
+	for i, adj := range to {
+		c.off[i+1] = c.off[i] + len(adj)
+	}
+	c.adj = make([]int64, c.off[len(nodes)])
+	parallelFor(len(nodes), workers, func(lo, hi int) {
+		for i := lo; i < hi; i++ {
+			copy(c.adj[c.off[i]:], to[i])
+		}
+	})
+	return c
+}
+
+// parallelFor splits the range [0, n) into at most workers contiguous
+// parts and calls fn concurrently on the bounds of each part.
+func parallelFor(n, workers int, fn func(lo, hi int)) {
+	if workers > n {
+		workers = n
+	}
+	if workers <= 1 {
+		fn(0, n)
+		return
+	}
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for i := 0; i < workers; i++ {
+		go func(lo, hi int) {
+			defer wg.Done()
+			fn(lo, hi)
+		}(i*n/workers, (i+1)*n/workers)
+	}
+	wg.Wait()
+}
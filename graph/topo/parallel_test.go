@@ -0,0 +1,98 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package topo
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/graphs/gen"
+	"gonum.org/v1/gonum/graph/internal/ordered"
+	"gonum.org/v1/gonum/graph/simple"
+)
+
+func TestParallelConnectedComponents(t *testing.T) {
+	var graphs []graph.Undirected
+	for _, test := range connectedComponentTests {
+		g := simple.NewUndirectedGraph()
+		for u, e := range test.g {
+			if !g.Has(simple.Node(u)) {
+				g.AddNode(simple.Node(u))
+			}
+			for v := range e {
+				if !g.Has(simple.Node(v)) {
+					g.AddNode(simple.Node(v))
+				}
+				g.SetEdge(simple.Edge{F: simple.Node(u), T: simple.Node(v)})
+			}
+		}
+		graphs = append(graphs, g)
+	}
+	rnd := rand.New(rand.NewSource(1))
+	for _, p := range []float64{0.001, 0.002, 0.005} {
+		g := simple.NewUndirectedGraph()
+		gen.Gnp(g, 1000, p, rnd)
+		graphs = append(graphs, g)
+	}
+	graphs = append(graphs, simple.NewUndirectedGraph())
+
+	for i, g := range graphs {
+		want := ConnectedComponents(g)
+		for _, c := range want {
+			sort.Sort(ordered.ByID(c))
+		}
+		sort.Sort(byFirstID(want))
+		for _, workers := range []int{0, 1, 4} {
+			test := fmt.Sprintf("graph %d with %d workers", i, workers)
+			got := ParallelConnectedComponents(g, workers)
+			if !reflect.DeepEqual(componentIDs(got), componentIDs(want)) {
+				t.Errorf("unexpected connected components for %s:\ngot: %v\nwant:%v",
+					test, componentIDs(got), componentIDs(want))
+			}
+		}
+	}
+}
+
+// byFirstID sorts components by the ID
+// of the first node of each component.
+type byFirstID [][]graph.Node
+
+func (c byFirstID) Len() int           { return len(c) }
+func (c byFirstID) Less(i, j int) bool { return c[i][0].ID() < c[j][0].ID() }
+func (c byFirstID) Swap(i, j int)      { c[i], c[j] = c[j], c[i] }
+
+func componentIDs(cc [][]graph.Node) [][]int64 {
+	ids := make([][]int64, len(cc))
+	for i, c := range cc {
+		ids[i] = make([]int64, len(c))
+		for j, n := range c {
+			ids[i][j] = n.ID()
+		}
+	}
+	return ids
+}
+
+var gnpUndirected_100000_sparse = func() graph.Undirected {
+	g := simple.NewUndirectedGraph()
+	gen.Gnp(g, 100000, 1e-5, rand.New(rand.NewSource(1)))
+	return g
+}()
+
+func BenchmarkConnectedComponents(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		ConnectedComponents(gnpUndirected_100000_sparse)
+	}
+}
+
+func BenchmarkParallelConnectedComponents(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		ParallelConnectedComponents(gnpUndirected_100000_sparse, 0)
+	}
+}
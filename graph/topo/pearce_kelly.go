@@ -0,0 +1,204 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package topo
+
+import (
+	"fmt"
+	"sort"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/internal/ordered"
+	"gonum.org/v1/gonum/graph/internal/set"
+)
+
+// IncrementalOrder maintains a topological ordering of a directed acyclic
+// graph under node and edge insertion using the Pearce-Kelly dynamic
+// topological sort algorithm described in doi:10.1145/1187436.1210590.
+//
+// Only the region of the ordering between the end points of an inserted
+// edge is examined when the edge violates the current ordering, so each
+// insertion is typically much cheaper than a full topological sort.
+type IncrementalOrder struct {
+	nodes map[int64]graph.Node
+	from  map[int64]set.Int64s
+	to    map[int64]set.Int64s
+
+	// order holds node IDs in topological order
+	// and pos holds the position of each node ID
+	// in order.
+	order []int64
+	pos   map[int64]int
+}
+
+// NewIncrementalOrder returns a new empty IncrementalOrder.
+func NewIncrementalOrder() *IncrementalOrder {
+	return &IncrementalOrder{
+		nodes: make(map[int64]graph.Node),
+		from:  make(map[int64]set.Int64s),
+		to:    make(map[int64]set.Int64s),
+		pos:   make(map[int64]int),
+	}
+}
+
+// AddNode adds n to the end of the ordering. It panics if the added node
+// ID matches an existing node ID.
+func (o *IncrementalOrder) AddNode(n graph.Node) {
+	id := n.ID()
+	if _, exists := o.nodes[id]; exists {
+		panic(fmt.Sprintf("topo: node ID collision: %d", id))
+	}
+	o.nodes[id] = n
+	o.from[id] = make(set.Int64s)
+	o.to[id] = make(set.Int64s)
+	o.pos[id] = len(o.order)
+	o.order = append(o.order, id)
+}
+
+// Has returns whether the node exists within the ordering.
+func (o *IncrementalOrder) Has(n graph.Node) bool {
+	_, ok := o.nodes[n.ID()]
+	return ok
+}
+
+// HasEdgeFromTo returns whether an edge exists from u to v.
+func (o *IncrementalOrder) HasEdgeFromTo(u, v graph.Node) bool {
+	return o.from[u.ID()].Has(v.ID())
+}
+
+// AddEdge adds an edge from the From node to the To node of e, updating
+// the topological ordering. Nodes that do not exist are added. If adding
+// the edge would create a cycle, the edge is not added and an Unorderable
+// error holding the nodes of the cycle, sorted by ID, is returned.
+func (o *IncrementalOrder) AddEdge(e graph.Edge) error {
+	u, v := e.From(), e.To()
+	if !o.Has(u) {
+		o.AddNode(u)
+	}
+	if !o.Has(v) {
+		o.AddNode(v)
+	}
+	uid, vid := u.ID(), v.ID()
+	if uid == vid {
+		return Unorderable{{u}}
+	}
+	if o.from[uid].Has(vid) {
+		return nil
+	}
+
+	lb, ub := o.pos[vid], o.pos[uid]
+	if lb < ub {
+		// Discover the nodes reachable from v that are
+		// before u in the current ordering. If u is
+		// reachable the new edge would close a cycle.
+		fwd, cycle := o.forward(vid, uid, ub)
+		if cycle != nil {
+			return cycle
+		}
+		back := o.backward(uid, lb)
+		o.reorder(back, fwd)
+	}
+
+	o.from[uid].Add(vid)
+	o.to[vid].Add(uid)
+	return nil
+}
+
+// forward returns the nodes reachable from start with positions no
+// greater than ub. If target is reachable the nodes on a path from
+// start to target are returned as an Unorderable.
+func (o *IncrementalOrder) forward(start, target int64, ub int) ([]int64, Unorderable) {
+	parent := map[int64]int64{start: start}
+	visited := []int64{start}
+	stack := []int64{start}
+	for len(stack) != 0 {
+		n := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		for w := range o.from[n] {
+			if w == target {
+				cycle := []graph.Node{o.nodes[target]}
+				for p := n; ; p = parent[p] {
+					cycle = append(cycle, o.nodes[p])
+					if p == start {
+						break
+					}
+				}
+				sort.Sort(ordered.ByID(cycle))
+				return nil, Unorderable{cycle}
+			}
+			if _, seen := parent[w]; seen || o.pos[w] > ub {
+				continue
+			}
+			parent[w] = n
+			visited = append(visited, w)
+			stack = append(stack, w)
+		}
+	}
+	return visited, nil
+}
+
+// backward returns the nodes that can reach start with positions no
+// less than lb.
+func (o *IncrementalOrder) backward(start int64, lb int) []int64 {
+	seen := set.Int64s{start: struct{}{}}
+	visited := []int64{start}
+	stack := []int64{start}
+	for len(stack) != 0 {
+		n := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		for w := range o.to[n] {
+			if seen.Has(w) || o.pos[w] < lb {
+				continue
+			}
+			seen.Add(w)
+			visited = append(visited, w)
+			stack = append(stack, w)
+		}
+	}
+	return visited
+}
+
+// reorder moves the nodes in back ahead of the nodes in fwd, reusing
+// the positions they jointly occupy and retaining the relative order
+// within each set.
+func (o *IncrementalOrder) reorder(back, fwd []int64) {
+	byPos := func(ids []int64) {
+		sort.Slice(ids, func(i, j int) bool { return o.pos[ids[i]] < o.pos[ids[j]] })
+	}
+	byPos(back)
+	byPos(fwd)
+
+	ids := append(back, fwd...)
+	slots := make([]int, len(ids))
+	for i, id := range ids {
+		slots[i] = o.pos[id]
+	}
+	sort.Ints(slots)
+	for i, id := range ids {
+		o.pos[id] = slots[i]
+		o.order[slots[i]] = id
+	}
+}
+
+// Order returns the nodes in the current topological ordering.
+func (o *IncrementalOrder) Order() []graph.Node {
+	if len(o.order) == 0 {
+		return nil
+	}
+	nodes := make([]graph.Node, len(o.order))
+	for i, id := range o.order {
+		nodes[i] = o.nodes[id]
+	}
+	return nodes
+}
+
+// Position returns the position of n in the current topological ordering,
+// or -1 if n is not in the ordering.
+func (o *IncrementalOrder) Position(n graph.Node) int {
+	p, ok := o.pos[n.ID()]
+	if !ok {
+		return -1
+	}
+	return p
+}
@@ -0,0 +1,122 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package topo
+
+import (
+	"reflect"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/simple"
+)
+
+func TestIncrementalOrder(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for trial := 0; trial < 20; trial++ {
+		const n = 30
+		o := NewIncrementalOrder()
+		g := simple.NewDirectedGraph()
+		for i := 0; i < n; i++ {
+			o.AddNode(simple.Node(i))
+			g.AddNode(simple.Node(i))
+		}
+		for k := 0; k < 200; k++ {
+			u := simple.Node(rnd.Intn(n))
+			v := simple.Node(rnd.Intn(n))
+			wantCycle := u == v || PathExistsIn(g, v, u)
+
+			err := o.AddEdge(simple.Edge{F: u, T: v})
+			if (err != nil) != wantCycle {
+				t.Fatalf("unexpected cycle detection result for %d->%d in trial %d: got:%v want cycle:%t",
+					u, v, trial, err, wantCycle)
+			}
+			if err != nil {
+				cycle := err.(Unorderable)[0]
+				if !isCycleClosedBy(g, cycle, u, v) {
+					t.Errorf("returned nodes %v are not a cycle closed by %d->%d", cycle, u, v)
+				}
+				if o.HasEdgeFromTo(u, v) {
+					t.Errorf("unexpected edge %d->%d added after cycle detection", u, v)
+				}
+				continue
+			}
+			g.SetEdge(simple.Edge{F: u, T: v})
+
+			order := o.Order()
+			for i, n := range order {
+				if o.Position(n) != i {
+					t.Fatalf("inconsistent position for node %d: got:%d want:%d", n.ID(), o.Position(n), i)
+				}
+			}
+			for _, e := range g.Edges() {
+				if o.Position(e.From()) >= o.Position(e.To()) {
+					t.Fatalf("ordering violated by edge %d->%d after inserting %d->%d in trial %d",
+						e.From().ID(), e.To().ID(), u, v, trial)
+				}
+			}
+		}
+	}
+}
+
+// isCycleClosedBy returns whether the nodes in cycle form a simple path
+// from v to u in g, so that adding uv closes a cycle through all of them.
+func isCycleClosedBy(g graph.Directed, cycle []graph.Node, u, v graph.Node) bool {
+	in := make(map[int64]bool)
+	for _, n := range cycle {
+		in[n.ID()] = true
+	}
+	if !in[u.ID()] || !in[v.ID()] {
+		return false
+	}
+	// Walk from v to u within the cycle nodes.
+	seen := map[int64]bool{v.ID(): true}
+	stack := []graph.Node{v}
+	for len(stack) != 0 {
+		n := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if n.ID() == u.ID() {
+			return true
+		}
+		for _, w := range g.From(n) {
+			if in[w.ID()] && !seen[w.ID()] {
+				seen[w.ID()] = true
+				stack = append(stack, w)
+			}
+		}
+	}
+	return false
+}
+
+func TestIncrementalOrderSmall(t *testing.T) {
+	o := NewIncrementalOrder()
+	for _, e := range []simple.Edge{
+		{F: simple.Node(3), T: simple.Node(4)},
+		{F: simple.Node(1), T: simple.Node(2)},
+		{F: simple.Node(2), T: simple.Node(3)},
+		{F: simple.Node(4), T: simple.Node(1)},
+	} {
+		err := o.AddEdge(e)
+		if e.F.ID() == 4 {
+			want := Unorderable{{simple.Node(1), simple.Node(2), simple.Node(3), simple.Node(4)}}
+			if !reflect.DeepEqual(err, want) {
+				t.Errorf("unexpected error for cycle creating edge: got:%v want:%v", err, want)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("unexpected error for edge %d->%d: %v", e.F.ID(), e.T.ID(), err)
+		}
+	}
+	var got []int64
+	for _, n := range o.Order() {
+		got = append(got, n.ID())
+	}
+	want := []int64{1, 2, 3, 4}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected ordering: got:%v want:%v", got, want)
+	}
+}
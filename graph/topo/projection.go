@@ -0,0 +1,95 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package topo
+
+import (
+	"sort"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/internal/ordered"
+)
+
+// ProjectionWeighting specifies the weighting of the
+// edges of a bipartite projection.
+type ProjectionWeighting int
+
+const (
+	// SharedNeighbors weights each edge of a projection by
+	// the number of neighbors shared by its end points.
+	SharedNeighbors ProjectionWeighting = iota
+
+	// Newman weights each edge of a projection by the sum over
+	// shared neighbors of 1/(d-1) where d is the degree of the
+	// shared neighbor, as described in doi:10.1103/PhysRevE.64.016132.
+	Newman
+
+	// Jaccard weights each edge of a projection by the number
+	// of shared neighbors divided by the number of nodes that
+	// are neighbors of either end point.
+	Jaccard
+)
+
+// BipartiteProjection builds the projection of the bipartite graph g onto
+// the given nodes in dst. Two nodes are joined in the projection if they
+// have a common neighbor in g, with the edge weight determined by weighting.
+// The nodes must be one side of a bipartition of g, so no two of them may be
+// adjacent. The dst graph is not cleared.
+//
+// BipartiteProjection will panic if two of the nodes are adjacent in g or
+// weighting is not a valid ProjectionWeighting.
+func BipartiteProjection(dst graph.WeightedBuilder, g graph.Undirected, nodes []graph.Node, weighting ProjectionWeighting) {
+	switch weighting {
+	case SharedNeighbors, Newman, Jaccard:
+	default:
+		panic("topo: unknown projection weighting")
+	}
+
+	nodes = append([]graph.Node(nil), nodes...)
+	sort.Sort(ordered.ByID(nodes))
+	in := make(map[int64]bool, len(nodes))
+	for _, n := range nodes {
+		in[n.ID()] = true
+	}
+	for _, u := range nodes {
+		dst.AddNode(u)
+		for _, v := range g.From(u) {
+			if in[v.ID()] {
+				panic("topo: projection nodes are adjacent")
+			}
+		}
+	}
+
+	for _, u := range nodes {
+		// Accumulate the weight to each later
+		// node sharing a neighbor with u.
+		weight := make(map[int64]float64)
+		var to []graph.Node
+		for _, k := range g.From(u) {
+			shared := g.From(k)
+			for _, v := range shared {
+				if id := v.ID(); id > u.ID() && in[id] {
+					if _, ok := weight[id]; !ok {
+						to = append(to, v)
+					}
+					switch weighting {
+					case SharedNeighbors, Jaccard:
+						weight[id]++
+					case Newman:
+						weight[id] += 1 / float64(len(shared)-1)
+					}
+				}
+			}
+		}
+		sort.Sort(ordered.ByID(to))
+		du := len(g.From(u))
+		for _, v := range to {
+			w := weight[v.ID()]
+			if weighting == Jaccard {
+				w /= float64(du + len(g.From(v)) - int(w))
+			}
+			dst.SetWeightedEdge(dst.NewWeightedEdge(u, v, w))
+		}
+	}
+}
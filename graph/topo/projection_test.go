@@ -0,0 +1,95 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package topo
+
+import (
+	"math"
+	"testing"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/simple"
+)
+
+var bipartiteProjectionTests = []struct {
+	name      string
+	weighting ProjectionWeighting
+	want      map[[2]int64]float64
+}{
+	{
+		name:      "shared neighbors",
+		weighting: SharedNeighbors,
+		want: map[[2]int64]float64{
+			{0, 1}: 2,
+			{0, 2}: 1,
+			{1, 2}: 1,
+		},
+	},
+	{
+		name:      "newman",
+		weighting: Newman,
+		want: map[[2]int64]float64{
+			// Authors 0 and 1 share paper 10 with
+			// three authors and paper 11 with two.
+			{0, 1}: 1.0/2 + 1,
+			{0, 2}: 1.0 / 2,
+			{1, 2}: 1.0 / 2,
+		},
+	},
+	{
+		name:      "jaccard",
+		weighting: Jaccard,
+		want: map[[2]int64]float64{
+			{0, 1}: 2.0 / 2,
+			{0, 2}: 1.0 / 3,
+			{1, 2}: 1.0 / 3,
+		},
+	},
+}
+
+func TestBipartiteProjection(t *testing.T) {
+	// Authors 0, 1, 2 and 3 and papers 10, 11 and 12.
+	g := simple.NewUndirectedGraph()
+	for _, e := range [][2]int64{
+		{0, 10}, {1, 10}, {2, 10},
+		{0, 11}, {1, 11},
+		{2, 12},
+		{3, 13},
+	} {
+		g.SetEdge(simple.Edge{F: simple.Node(e[0]), T: simple.Node(e[1])})
+	}
+	authors := []graph.Node{simple.Node(3), simple.Node(2), simple.Node(1), simple.Node(0)}
+
+	for _, test := range bipartiteProjectionTests {
+		dst := simple.NewWeightedUndirectedGraph(0, 0)
+		BipartiteProjection(dst, g, authors, test.weighting)
+		if len(dst.Nodes()) != len(authors) {
+			t.Errorf("unexpected number of nodes for %q: got:%d want:%d", test.name, len(dst.Nodes()), len(authors))
+		}
+		if len(dst.Edges()) != len(test.want) {
+			t.Errorf("unexpected number of edges for %q: got:%d want:%d", test.name, len(dst.Edges()), len(test.want))
+		}
+		for e, want := range test.want {
+			got, ok := dst.Weight(simple.Node(e[0]), simple.Node(e[1]))
+			if !ok {
+				t.Errorf("missing edge %d--%d for %q", e[0], e[1], test.name)
+				continue
+			}
+			if math.Abs(got-want) > 1e-12 {
+				t.Errorf("unexpected weight for edge %d--%d for %q: got:%v want:%v", e[0], e[1], test.name, got, want)
+			}
+		}
+	}
+}
+
+func TestBipartiteProjectionPanics(t *testing.T) {
+	g := simple.NewUndirectedGraph()
+	g.SetEdge(simple.Edge{F: simple.Node(0), T: simple.Node(1)})
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("expected panic for adjacent projection nodes")
+		}
+	}()
+	BipartiteProjection(simple.NewWeightedUndirectedGraph(0, 0), g, g.Nodes(), SharedNeighbors)
+}
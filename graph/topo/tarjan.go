@@ -42,6 +42,38 @@ func Sort(g graph.Directed) (sorted []graph.Node, err error) {
 	return sortedFrom(sccs, lexical)
 }
 
+// DirectedAcyclic returns whether the directed graph g is acyclic. A graph
+// containing a self edge is not acyclic. DirectedAcyclic runs in
+// O(|V|+|E|) time and is cheaper than Sort when only the existence of a
+// topological ordering is needed.
+func DirectedAcyclic(g graph.Directed) bool {
+	nodes := g.Nodes()
+	indegree := make(map[int64]int, len(nodes))
+	var ready []graph.Node
+	for _, n := range nodes {
+		d := len(g.To(n))
+		if d == 0 {
+			ready = append(ready, n)
+			continue
+		}
+		indegree[n.ID()] = d
+	}
+	removed := 0
+	for len(ready) != 0 {
+		var u graph.Node
+		u, ready = ready[len(ready)-1], ready[:len(ready)-1]
+		removed++
+		for _, v := range g.From(u) {
+			vid := v.ID()
+			indegree[vid]--
+			if indegree[vid] == 0 {
+				ready = append(ready, v)
+			}
+		}
+	}
+	return removed == len(nodes)
+}
+
 // SortStabilized performs a topological sort of the directed graph g returning the 'from'
 // to 'to' sort order, or the order defined by the in place order sort function where there
 // is no unambiguous topological ordering. If a topological ordering is not possible, an
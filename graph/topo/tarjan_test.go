@@ -158,6 +158,24 @@ func TestSort(t *testing.T) {
 	}
 }
 
+func TestDirectedAcyclic(t *testing.T) {
+	for i, test := range tarjanTests {
+		g := simple.NewDirectedGraph()
+		for u, e := range test.g {
+			// Add nodes that are not defined by an edge.
+			if !g.Has(simple.Node(u)) {
+				g.AddNode(simple.Node(u))
+			}
+			for v := range e {
+				g.SetEdge(simple.Edge{F: simple.Node(u), T: simple.Node(v)})
+			}
+		}
+		if got := DirectedAcyclic(g); got != test.sortable {
+			t.Errorf("unexpected acyclicity for test %d: got:%t want:%t", i, got, test.sortable)
+		}
+	}
+}
+
 func TestTarjanSCC(t *testing.T) {
 	for i, test := range tarjanTests {
 		g := simple.NewDirectedGraph()
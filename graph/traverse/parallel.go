@@ -0,0 +1,199 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package traverse
+
+import (
+	"runtime"
+	"sync"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/internal/set"
+)
+
+// BreadthFirstTree is the result of a level-synchronous breadth-first
+// traversal.
+type BreadthFirstTree struct {
+	levels [][]graph.Node
+	parent map[int64]graph.Node
+	depth  map[int64]int
+}
+
+// Levels returns the nodes reached by the traversal grouped by their
+// depth from the root. The first level holds only the root.
+func (t BreadthFirstTree) Levels() [][]graph.Node { return t.levels }
+
+// Parent returns the parent of n in the breadth-first tree. If n is the
+// root or was not reached, Parent returns nil.
+func (t BreadthFirstTree) Parent(n graph.Node) graph.Node { return t.parent[n.ID()] }
+
+// Depth returns the depth of n in the breadth-first tree, or -1 if n
+// was not reached.
+func (t BreadthFirstTree) Depth(n graph.Node) int {
+	d, ok := t.depth[n.ID()]
+	if !ok {
+		return -1
+	}
+	return d
+}
+
+// Direction-optimization parameters from Beamer, Asanović and Patterson
+// "Direction-optimizing breadth-first search" doi:10.1109/SC.2012.50.
+const (
+	// bottomUpAlpha is the ratio of unvisited
+	// nodes to frontier nodes below which the
+	// traversal switches to bottom-up steps.
+	bottomUpAlpha = 14
+
+	// topDownBeta is the ratio of all nodes
+	// to frontier nodes above which the
+	// traversal switches back to top-down.
+	topDownBeta = 24
+)
+
+// ParallelBreadthFirst performs a concurrent level-synchronous breadth-first
+// traversal of g starting from the given node and returns the resulting
+// breadth-first tree. Each level of the traversal is partitioned between
+// workers goroutines. If workers is less than one, runtime.GOMAXPROCS(0)
+// workers are used.
+//
+// If g is a graph.Directed or a graph.Undirected, the traversal is
+// direction-optimizing; when the frontier becomes large relative to the
+// unvisited part of the graph, levels are expanded bottom-up by searching
+// for a parent of each unvisited node in the frontier, rather than top-down
+// from the frontier.
+//
+// The depth of each node is the same as for a sequential BreadthFirst walk,
+// but when a node has more than one candidate parent in the previous level,
+// the parent chosen is not specified. The methods of g must be safe for
+// concurrent use.
+func ParallelBreadthFirst(g graph.Graph, from graph.Node, workers int) BreadthFirstTree {
+	if workers < 1 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	t := BreadthFirstTree{
+		parent: make(map[int64]graph.Node),
+		depth:  make(map[int64]int),
+	}
+	if !g.Has(from) {
+		return t
+	}
+	t.depth[from.ID()] = 0
+
+	var to func(graph.Node) []graph.Node
+	switch g := g.(type) {
+	case graph.Directed:
+		to = g.To
+	case graph.Undirected:
+		to = g.From
+	}
+	var (
+		nodes     []graph.Node
+		unvisited []graph.Node
+	)
+	if to != nil {
+		nodes = g.Nodes()
+	}
+
+	frontier := []graph.Node{from}
+	bottomUp := false
+	for len(frontier) != 0 {
+		t.levels = append(t.levels, frontier)
+		if to != nil {
+			remain := len(nodes) - len(t.depth)
+			if !bottomUp && len(frontier)*bottomUpAlpha > remain {
+				bottomUp = true
+			} else if bottomUp && len(frontier)*topDownBeta < len(nodes) {
+				bottomUp = false
+			}
+		}
+
+		var found [][]claim
+		if bottomUp {
+			unvisited = unvisited[:0]
+			for _, n := range nodes {
+				if _, ok := t.depth[n.ID()]; !ok {
+					unvisited = append(unvisited, n)
+				}
+			}
+			inFrontier := make(set.Int64s, len(frontier))
+			for _, n := range frontier {
+				inFrontier.Add(n.ID())
+			}
+			found = partition(unvisited, workers, func(part []graph.Node) []claim {
+				var c []claim
+				for _, v := range part {
+					for _, u := range to(v) {
+						if inFrontier.Has(u.ID()) {
+							c = append(c, claim{node: v, parent: u})
+							break
+						}
+					}
+				}
+				return c
+			})
+		} else {
+			found = partition(frontier, workers, func(part []graph.Node) []claim {
+				var c []claim
+				for _, u := range part {
+					for _, v := range g.From(u) {
+						if _, ok := t.depth[v.ID()]; !ok {
+							c = append(c, claim{node: v, parent: u})
+						}
+					}
+				}
+				return c
+			})
+		}
+
+		// Merge the claims made by each worker, keeping
+		// the first claim for each newly reached node.
+		depth := len(t.levels)
+		var next []graph.Node
+		for _, c := range found {
+			for _, e := range c {
+				id := e.node.ID()
+				if _, ok := t.depth[id]; ok {
+					continue
+				}
+				t.depth[id] = depth
+				t.parent[id] = e.parent
+				next = append(next, e.node)
+			}
+		}
+		frontier = next
+	}
+
+	return t
+}
+
+// claim is a proposal that node be
+// added to the tree as a child of parent.
+type claim struct {
+	node, parent graph.Node
+}
+
+// partition splits nodes into at most workers contiguous parts and
+// calls fn concurrently on each part, returning the results in order.
+func partition(nodes []graph.Node, workers int, fn func([]graph.Node) []claim) [][]claim {
+	if workers > len(nodes) {
+		workers = len(nodes)
+	}
+	if workers <= 1 {
+		return [][]claim{fn(nodes)}
+	}
+	results := make([][]claim, workers)
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for i := 0; i < workers; i++ {
+		lo := i * len(nodes) / workers
+		hi := (i + 1) * len(nodes) / workers
+		go func(i int, part []graph.Node) {
+			defer wg.Done()
+			results[i] = fn(part)
+		}(i, nodes[lo:hi])
+	}
+	wg.Wait()
+	return results
+}
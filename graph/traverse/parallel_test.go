@@ -0,0 +1,125 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package traverse
+
+import (
+	"fmt"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/graphs/gen"
+	"gonum.org/v1/gonum/graph/simple"
+)
+
+// graphOnly hides any interfaces other than
+// graph.Graph implemented by the embedded value.
+type graphOnly struct {
+	graph.Graph
+}
+
+func TestParallelBreadthFirst(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	var graphs []graph.Graph
+	for _, p := range []float64{0.002, 0.01, 0.1} {
+		u := simple.NewUndirectedGraph()
+		gen.Gnp(u, 500, p, rnd)
+		d := simple.NewDirectedGraph()
+		gen.Gnp(d, 500, p, rnd)
+		graphs = append(graphs, u, d, graphOnly{d})
+	}
+	bz := simple.NewUndirectedGraph()
+	for u, e := range batageljZaversnikGraph {
+		if !bz.Has(simple.Node(u)) {
+			bz.AddNode(simple.Node(u))
+		}
+		for v := range e {
+			bz.SetEdge(simple.Edge{F: simple.Node(u), T: simple.Node(v)})
+		}
+	}
+	graphs = append(graphs, bz)
+
+	for i, g := range graphs {
+		for _, workers := range []int{0, 1, 4} {
+			from := simple.Node(1)
+			test := fmt.Sprintf("graph %d with %d workers", i, workers)
+
+			want := make(map[int64]int)
+			var bft BreadthFirst
+			bft.Walk(g, from, func(n graph.Node, d int) bool {
+				want[n.ID()] = d
+				return false
+			})
+
+			tree := ParallelBreadthFirst(g, from, workers)
+			var reached int
+			for d, level := range tree.Levels() {
+				for _, n := range level {
+					reached++
+					if tree.Depth(n) != d {
+						t.Errorf("inconsistent depth for node %d in %s: got:%d want:%d", n.ID(), test, tree.Depth(n), d)
+					}
+				}
+			}
+			if reached != len(want) {
+				t.Errorf("unexpected number of nodes reached in %s: got:%d want:%d", test, reached, len(want))
+			}
+			for _, n := range g.Nodes() {
+				d, ok := want[n.ID()]
+				if !ok {
+					d = -1
+				}
+				if got := tree.Depth(n); got != d {
+					t.Errorf("unexpected depth for node %d in %s: got:%d want:%d", n.ID(), test, got, d)
+				}
+				p := tree.Parent(n)
+				if d <= 0 {
+					if p != nil {
+						t.Errorf("unexpected parent for node %d in %s: got:%d want:nil", n.ID(), test, p.ID())
+					}
+					continue
+				}
+				if p == nil {
+					t.Errorf("missing parent for node %d in %s", n.ID(), test)
+					continue
+				}
+				if !g.HasEdgeBetween(p, n) || g.Edge(p, n) == nil {
+					t.Errorf("parent %d of node %d in %s is not joined to it", p.ID(), n.ID(), test)
+				}
+				if tree.Depth(p) != d-1 {
+					t.Errorf("parent %d of node %d in %s is not in previous level", p.ID(), n.ID(), test)
+				}
+			}
+		}
+	}
+}
+
+func TestParallelBreadthFirstMissingRoot(t *testing.T) {
+	g := simple.NewUndirectedGraph()
+	g.AddNode(simple.Node(0))
+	tree := ParallelBreadthFirst(g, simple.Node(1), 0)
+	if tree.Levels() != nil {
+		t.Errorf("unexpected levels for missing root: %v", tree.Levels())
+	}
+	if tree.Depth(simple.Node(0)) != -1 {
+		t.Errorf("unexpected depth for unreached node: got:%d want:-1", tree.Depth(simple.Node(0)))
+	}
+}
+
+func benchmarkParallelBreadthFirst(b *testing.B, g graph.Undirected) {
+	from := g.Nodes()[0]
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		ParallelBreadthFirst(g, from, 0)
+	}
+}
+
+func BenchmarkParallelBreadthFirstGnp_1000_tenth(b *testing.B) {
+	benchmarkParallelBreadthFirst(b, gnpUndirected_1000_tenth)
+}
+func BenchmarkParallelBreadthFirstGnp_1000_half(b *testing.B) {
+	benchmarkParallelBreadthFirst(b, gnpUndirected_1000_half)
+}
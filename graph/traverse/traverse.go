@@ -5,6 +5,8 @@
 package traverse
 
 import (
+	"context"
+
 	"gonum.org/v1/gonum/graph"
 	"gonum.org/v1/gonum/graph/internal/linear"
 	"gonum.org/v1/gonum/graph/internal/set"
@@ -12,18 +14,44 @@ import (
 
 // BreadthFirst implements stateful breadth-first graph traversal.
 type BreadthFirst struct {
-	EdgeFilter func(graph.Edge) bool
-	Visit      func(u, v graph.Node)
-	queue      linear.NodeQueue
-	visited    set.Int64s
+	// Traverse is used to determine whether
+	// an edge should be followed. If Traverse
+	// is nil, all edges are followed.
+	Traverse func(graph.Edge) bool
+
+	// Visit is called with the nodes joined
+	// by each followed edge if it is non-nil.
+	Visit func(u, v graph.Node)
+
+	queue   linear.NodeQueue
+	visited set.Int64s
 }
 
 // Walk performs a breadth-first traversal of the graph g starting from the given node,
-// depending on the the EdgeFilter field and the until parameter if they are non-nil. The
-// traversal follows edges for which EdgeFilter(edge) is true and returns the first node
+// depending on the the Traverse field and the until parameter if they are non-nil. The
+// traversal follows edges for which Traverse(edge) is true and returns the first node
 // for which until(node, depth) is true. During the traversal, if the Visit field is
 // non-nil, it is called with the nodes joined by each followed edge.
 func (b *BreadthFirst) Walk(g graph.Graph, from graph.Node, until func(n graph.Node, d int) bool) graph.Node {
+	n, _ := b.walk(nil, g, from, until)
+	return n
+}
+
+// WalkContext is like Walk, but returns nil and the error held by ctx if ctx
+// is canceled before the traversal completes. After cancellation, the state
+// of the traverser reflects the partial traversal and should be Reset before
+// reuse.
+func (b *BreadthFirst) WalkContext(ctx context.Context, g graph.Graph, from graph.Node, until func(n graph.Node, d int) bool) (graph.Node, error) {
+	n, ok := b.walk(ctx.Done(), g, from, until)
+	if !ok {
+		return nil, ctx.Err()
+	}
+	return n, nil
+}
+
+// walk is the implementation of Walk. It returns false
+// if done is closed before the traversal completes.
+func (b *BreadthFirst) walk(done <-chan struct{}, g graph.Graph, from graph.Node, until func(n graph.Node, d int) bool) (graph.Node, bool) {
 	if b.visited == nil {
 		b.visited = make(set.Int64s)
 	}
@@ -36,12 +64,15 @@ func (b *BreadthFirst) Walk(g graph.Graph, from graph.Node, until func(n graph.N
 		untilNext = 1
 	)
 	for b.queue.Len() > 0 {
+		if canceled(done) {
+			return nil, false
+		}
 		t := b.queue.Dequeue()
 		if until != nil && until(t, depth) {
-			return t
+			return t, true
 		}
 		for _, n := range g.From(t) {
-			if b.EdgeFilter != nil && !b.EdgeFilter(g.Edge(t, n)) {
+			if b.Traverse != nil && !b.Traverse(g.Edge(t, n)) {
 				continue
 			}
 			if b.visited.Has(n.ID()) {
@@ -61,7 +92,7 @@ func (b *BreadthFirst) Walk(g graph.Graph, from graph.Node, until func(n graph.N
 		}
 	}
 
-	return nil
+	return nil, true
 }
 
 // WalkAll calls Walk for each unvisited node of the graph g using edges independent
@@ -102,18 +133,44 @@ func (b *BreadthFirst) Reset() {
 
 // DepthFirst implements stateful depth-first graph traversal.
 type DepthFirst struct {
-	EdgeFilter func(graph.Edge) bool
-	Visit      func(u, v graph.Node)
-	stack      linear.NodeStack
-	visited    set.Int64s
+	// Traverse is used to determine whether
+	// an edge should be followed. If Traverse
+	// is nil, all edges are followed.
+	Traverse func(graph.Edge) bool
+
+	// Visit is called with the nodes joined
+	// by each followed edge if it is non-nil.
+	Visit func(u, v graph.Node)
+
+	stack   linear.NodeStack
+	visited set.Int64s
 }
 
 // Walk performs a depth-first traversal of the graph g starting from the given node,
-// depending on the the EdgeFilter field and the until parameter if they are non-nil. The
-// traversal follows edges for which EdgeFilter(edge) is true and returns the first node
+// depending on the the Traverse field and the until parameter if they are non-nil. The
+// traversal follows edges for which Traverse(edge) is true and returns the first node
 // for which until(node) is true. During the traversal, if the Visit field is non-nil, it
 // is called with the nodes joined by each followed edge.
 func (d *DepthFirst) Walk(g graph.Graph, from graph.Node, until func(graph.Node) bool) graph.Node {
+	n, _ := d.walk(nil, g, from, until)
+	return n
+}
+
+// WalkContext is like Walk, but returns nil and the error held by ctx if ctx
+// is canceled before the traversal completes. After cancellation, the state
+// of the traverser reflects the partial traversal and should be Reset before
+// reuse.
+func (d *DepthFirst) WalkContext(ctx context.Context, g graph.Graph, from graph.Node, until func(graph.Node) bool) (graph.Node, error) {
+	n, ok := d.walk(ctx.Done(), g, from, until)
+	if !ok {
+		return nil, ctx.Err()
+	}
+	return n, nil
+}
+
+// walk is the implementation of Walk. It returns false
+// if done is closed before the traversal completes.
+func (d *DepthFirst) walk(done <-chan struct{}, g graph.Graph, from graph.Node, until func(graph.Node) bool) (graph.Node, bool) {
 	if d.visited == nil {
 		d.visited = make(set.Int64s)
 	}
@@ -121,12 +178,15 @@ func (d *DepthFirst) Walk(g graph.Graph, from graph.Node, until func(graph.Node)
 	d.visited.Add(from.ID())
 
 	for d.stack.Len() > 0 {
+		if canceled(done) {
+			return nil, false
+		}
 		t := d.stack.Pop()
 		if until != nil && until(t) {
-			return t
+			return t, true
 		}
 		for _, n := range g.From(t) {
-			if d.EdgeFilter != nil && !d.EdgeFilter(g.Edge(t, n)) {
+			if d.Traverse != nil && !d.Traverse(g.Edge(t, n)) {
 				continue
 			}
 			if d.visited.Has(n.ID()) {
@@ -140,7 +200,7 @@ func (d *DepthFirst) Walk(g graph.Graph, from graph.Node, until func(graph.Node)
 		}
 	}
 
-	return nil
+	return nil, true
 }
 
 // WalkAll calls Walk for each unvisited node of the graph g using edges independent
@@ -178,3 +238,14 @@ func (d *DepthFirst) Reset() {
 	d.stack = d.stack[:0]
 	d.visited = nil
 }
+
+// canceled returns whether done is closed. A nil
+// done channel is never closed.
+func canceled(done <-chan struct{}) bool {
+	select {
+	case <-done:
+		return true
+	default:
+		return false
+	}
+}
@@ -5,6 +5,7 @@
 package traverse
 
 import (
+	"context"
 	"fmt"
 	"reflect"
 	"sort"
@@ -144,7 +145,7 @@ func TestBreadthFirst(t *testing.T) {
 			}
 		}
 		w := BreadthFirst{
-			EdgeFilter: test.edge,
+			Traverse: test.edge,
 		}
 		var got [][]int64
 		final := w.Walk(g, test.from, func(n graph.Node, d int) bool {
@@ -232,7 +233,7 @@ func TestDepthFirst(t *testing.T) {
 			}
 		}
 		w := DepthFirst{
-			EdgeFilter: test.edge,
+			Traverse: test.edge,
 		}
 		var got []int64
 		final := w.Walk(g, test.from, func(n graph.Node) bool {
@@ -252,6 +253,71 @@ func TestDepthFirst(t *testing.T) {
 	}
 }
 
+func TestWalkContext(t *testing.T) {
+	g := simple.NewUndirectedGraph()
+	for u, e := range batageljZaversnikGraph {
+		if !g.Has(simple.Node(u)) {
+			g.AddNode(simple.Node(u))
+		}
+		for v := range e {
+			g.SetEdge(simple.Edge{F: simple.Node(u), T: simple.Node(v)})
+		}
+	}
+
+	const stopAfter = 3
+	walkers := []struct {
+		name string
+		walk func(ctx context.Context, until func(graph.Node) bool) (graph.Node, error)
+	}{
+		{
+			name: "BreadthFirst",
+			walk: func(ctx context.Context, until func(graph.Node) bool) (graph.Node, error) {
+				var w BreadthFirst
+				return w.WalkContext(ctx, g, simple.Node(6), func(n graph.Node, _ int) bool { return until(n) })
+			},
+		},
+		{
+			name: "DepthFirst",
+			walk: func(ctx context.Context, until func(graph.Node) bool) (graph.Node, error) {
+				var w DepthFirst
+				return w.WalkContext(ctx, g, simple.Node(6), until)
+			},
+		},
+	}
+	for _, w := range walkers {
+		var visited int
+		final, err := w.walk(context.Background(), func(n graph.Node) bool {
+			visited++
+			return n.ID() == 20
+		})
+		if err != nil {
+			t.Errorf("unexpected error for %s: %v", w.name, err)
+		}
+		if final == nil || final.ID() != 20 {
+			t.Errorf("unexpected final node for %s: got:%v want:20", w.name, final)
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		visited = 0
+		final, err = w.walk(ctx, func(n graph.Node) bool {
+			visited++
+			if visited == stopAfter {
+				cancel()
+			}
+			return false
+		})
+		if err != context.Canceled {
+			t.Errorf("unexpected error for canceled %s: got:%v want:%v", w.name, err, context.Canceled)
+		}
+		if final != nil {
+			t.Errorf("unexpected final node for canceled %s: got:%v want:<nil>", w.name, final)
+		}
+		if visited != stopAfter {
+			t.Errorf("unexpected number of nodes visited after cancellation of %s: got:%d want:%d", w.name, visited, stopAfter)
+		}
+	}
+}
+
 var walkAllTests = []struct {
 	g    []intset
 	edge func(graph.Edge) bool
@@ -308,9 +374,9 @@ func TestWalkAll(t *testing.T) {
 			)
 			switch w := w.(type) {
 			case *BreadthFirst:
-				w.EdgeFilter = test.edge
+				w.Traverse = test.edge
 			case *DepthFirst:
-				w.EdgeFilter = test.edge
+				w.Traverse = test.edge
 			default:
 				panic(fmt.Sprintf("bad walker type: %T", w))
 			}
@@ -0,0 +1,13 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package tsp provides heuristics for the travelling salesman problem.
+//
+// The functions in this package construct and improve tours, closed walks
+// that visit every node of a complete weighted undirected graph exactly
+// once. A tour is represented by the sequence of its nodes, with the return
+// from the last node to the first implied. Finding a tour of minimum weight
+// is NP-hard, so the package provides tour construction heuristics and local
+// search operators that improve an existing tour.
+package tsp // import "gonum.org/v1/gonum/graph/tsp"
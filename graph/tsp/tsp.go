@@ -0,0 +1,325 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package tsp
+
+import (
+	"math"
+	"sort"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/internal/ordered"
+	"gonum.org/v1/gonum/graph/matching"
+	"gonum.org/v1/gonum/graph/simple"
+)
+
+// Weight returns the total weight of the closed tour in g. The weight of a
+// missing edge is taken to be +Inf.
+func Weight(g graph.Weighted, tour []graph.Node) float64 {
+	if len(tour) < 2 {
+		return 0
+	}
+	var w float64
+	for i, u := range tour {
+		w += weight(g, u, tour[(i+1)%len(tour)])
+	}
+	return w
+}
+
+// NearestNeighbor returns a tour of the nodes of g constructed by starting
+// at start and repeatedly moving to the nearest unvisited node, breaking
+// ties by lowest ID, and the total weight of the tour. g should be complete;
+// missing edges are treated as having weight +Inf. NearestNeighbor will panic
+// if start is not a node of g.
+func NearestNeighbor(g graph.WeightedUndirected, start graph.Node) (tour []graph.Node, weight float64) {
+	if !g.Has(start) {
+		panic("tsp: start node not in graph")
+	}
+	nodes := g.Nodes()
+	sort.Sort(ordered.ByID(nodes))
+	w := weights(g, nodes)
+	var u int
+	for i, n := range nodes {
+		if n.ID() == start.ID() {
+			u = i
+		}
+	}
+	visited := make([]bool, len(nodes))
+	visited[u] = true
+	tour = append(tour, nodes[u])
+	for len(tour) < len(nodes) {
+		next := -1
+		for v := range nodes {
+			if !visited[v] && (next < 0 || w[u][v] < w[u][next]) {
+				next = v
+			}
+		}
+		visited[next] = true
+		tour = append(tour, nodes[next])
+		u = next
+	}
+	return tour, Weight(g, tour)
+}
+
+// Christofides returns a tour of the nodes of g constructed with the
+// algorithm of Christofides and its total weight. The tour starts at the
+// node with the lowest ID. g must be complete and, for the tour weight to be
+// within a factor of 3/2 of the optimum, its weights must be a metric,
+// satisfying the triangle inequality.
+//
+// The algorithm finds a minimum spanning tree of g, adds a minimum weight
+// perfect matching of the nodes with odd degree in the tree, and shortcuts
+// an Eulerian circuit of the resulting multigraph to a tour.
+//
+// See Christofides, N. "Worst-case analysis of a new heuristic for the
+// travelling salesman problem." Report 388, Graduate School of Industrial
+// Administration, Carnegie Mellon University (1976).
+func Christofides(g graph.WeightedUndirected) (tour []graph.Node, weight float64) {
+	nodes := g.Nodes()
+	sort.Sort(ordered.ByID(nodes))
+	n := len(nodes)
+	if n < 3 {
+		return nodes, Weight(g, nodes)
+	}
+	w := weights(g, nodes)
+
+	// Find a minimum spanning tree using Prim's
+	// algorithm on the complete graph.
+	var edges [][2]int
+	degree := make([]int, n)
+	inTree := make([]bool, n)
+	dist := make([]float64, n)
+	parent := make([]int, n)
+	for i := range dist {
+		dist[i] = math.Inf(1)
+	}
+	dist[0] = 0
+	parent[0] = -1
+	for k := 0; k < n; k++ {
+		u := -1
+		for i := range nodes {
+			if !inTree[i] && (u < 0 || dist[i] < dist[u]) {
+				u = i
+			}
+		}
+		inTree[u] = true
+		if parent[u] >= 0 {
+			edges = append(edges, [2]int{parent[u], u})
+			degree[parent[u]]++
+			degree[u]++
+		}
+		for v := range nodes {
+			if !inTree[v] && w[u][v] < dist[v] {
+				dist[v] = w[u][v]
+				parent[v] = u
+			}
+		}
+	}
+
+	// Add a minimum weight perfect matching of the odd
+	// degree nodes, found as a maximum cardinality matching
+	// of maximum negated weight.
+	var odd []int
+	for i, d := range degree {
+		if d%2 != 0 {
+			odd = append(odd, i)
+		}
+	}
+	mg := simple.NewWeightedUndirectedGraph(0, math.Inf(1))
+	for i := range odd {
+		mg.AddNode(simple.Node(i))
+	}
+	for i, u := range odd {
+		for j := i + 1; j < len(odd); j++ {
+			mg.SetWeightedEdge(simple.WeightedEdge{F: simple.Node(i), T: simple.Node(j), W: -w[u][odd[j]]})
+		}
+	}
+	m, _ := matching.MaximumWeightMatching(mg, true)
+	for _, e := range m.Edges() {
+		edges = append(edges, [2]int{odd[e.From().ID()], odd[e.To().ID()]})
+	}
+
+	// Walk an Eulerian circuit of the multigraph using
+	// Hierholzer's algorithm, shortcutting visited nodes.
+	type halfEdge struct {
+		to, edge int
+	}
+	adj := make([][]halfEdge, n)
+	for k, e := range edges {
+		adj[e[0]] = append(adj[e[0]], halfEdge{to: e[1], edge: k})
+		adj[e[1]] = append(adj[e[1]], halfEdge{to: e[0], edge: k})
+	}
+	used := make([]bool, len(edges))
+	next := make([]int, n)
+	visited := make([]bool, n)
+	stack := []int{0}
+	var circuit []int
+	for len(stack) != 0 {
+		u := stack[len(stack)-1]
+		for next[u] < len(adj[u]) && used[adj[u][next[u]].edge] {
+			next[u]++
+		}
+		if next[u] == len(adj[u]) {
+			stack = stack[:len(stack)-1]
+			circuit = append(circuit, u)
+			continue
+		}
+		h := adj[u][next[u]]
+		used[h.edge] = true
+		stack = append(stack, h.to)
+	}
+	for i := len(circuit) - 1; i >= 0; i-- {
+		u := circuit[i]
+		if !visited[u] {
+			visited[u] = true
+			tour = append(tour, nodes[u])
+		}
+	}
+	return tour, Weight(g, tour)
+}
+
+// TwoOpt returns the tour obtained by improving the given tour of g with the
+// 2-opt local search, and its total weight. A 2-opt move removes two edges
+// of the tour and reconnects the two resulting paths the other way, which
+// reverses one of them. Improving moves are made until none remains, so the
+// returned tour has no crossing edges when the weights are Euclidean
+// distances. The input tour is not modified. Missing edges are treated as
+// having weight +Inf.
+func TwoOpt(g graph.Weighted, tour []graph.Node) (improved []graph.Node, weight float64) {
+	improved = append([]graph.Node(nil), tour...)
+	n := len(improved)
+	if n < 4 {
+		return improved, Weight(g, improved)
+	}
+	w := weights(g, improved)
+	pos := make([]int, n)
+	for i := range pos {
+		pos[i] = i
+	}
+	for changed := true; changed; {
+		changed = false
+		for i := 0; i < n-1; i++ {
+			for j := i + 2; j < n; j++ {
+				if i == 0 && j == n-1 {
+					// These edges are adjacent.
+					continue
+				}
+				a, b := pos[i], pos[i+1]
+				c, d := pos[j], pos[(j+1)%n]
+				delta := w[a][c] + w[b][d] - w[a][b] - w[c][d]
+				if delta < -1e-12*math.Abs(w[a][b]+w[c][d]) {
+					reverse(pos[i+1 : j+1])
+					changed = true
+				}
+			}
+		}
+	}
+	for i, p := range pos {
+		improved[i] = tour[p]
+	}
+	return improved, Weight(g, improved)
+}
+
+// OrOpt returns the tour obtained by improving the given tour of g with the
+// Or-opt local search, and its total weight. An Or-opt move removes a segment
+// of one, two or three consecutive nodes from the tour and reinserts it,
+// possibly reversed, between two other adjacent nodes. Improving moves are
+// made until none remains. The input tour is not modified. Missing edges are
+// treated as having weight +Inf.
+//
+// See Or, I. "Traveling salesman-type combinatorial problems and their
+// relation to the logistics of regional blood banking." PhD thesis,
+// Northwestern University (1976).
+func OrOpt(g graph.Weighted, tour []graph.Node) (improved []graph.Node, weight float64) {
+	improved = append([]graph.Node(nil), tour...)
+	n := len(improved)
+	if n < 4 {
+		return improved, Weight(g, improved)
+	}
+	w := weights(g, improved)
+	pos := make([]int, n)
+	for i := range pos {
+		pos[i] = i
+	}
+	for changed := true; changed; {
+		changed = false
+	search:
+		for length := 1; length <= 3 && length <= n-3; length++ {
+			for i := 0; i < n; i++ {
+				// The segment is pos[i:i+length], taken
+				// cyclically, between nodes p and q.
+				seg := make([]int, length)
+				for k := range seg {
+					seg[k] = pos[(i+k)%n]
+				}
+				p := pos[(i-1+n)%n]
+				q := pos[(i+length)%n]
+				first, last := seg[0], seg[length-1]
+				removeGain := w[p][first] + w[last][q] - w[p][q]
+
+				// Try inserting between each pair of adjacent
+				// nodes of the remaining tour.
+				rest := make([]int, 0, n-length)
+				for k := 0; k < n-length; k++ {
+					rest = append(rest, pos[(i+length+k)%n])
+				}
+				for k := 0; k < len(rest)-1; k++ {
+					x, y := rest[k], rest[k+1]
+					forward := w[x][first] + w[last][y] - w[x][y]
+					backward := w[x][last] + w[first][y] - w[x][y]
+					tol := 1e-12 * math.Abs(removeGain)
+					var rev bool
+					switch {
+					case forward < removeGain-tol && forward <= backward:
+					case backward < removeGain-tol:
+						rev = true
+					default:
+						continue
+					}
+					if rev {
+						reverse(seg)
+					}
+					pos = append(append(append(pos[:0:0], rest[:k+1]...), seg...), rest[k+1:]...)
+					changed = true
+					break search
+				}
+			}
+		}
+	}
+	for i, p := range pos {
+		improved[i] = tour[p]
+	}
+	return improved, Weight(g, improved)
+}
+
+// weights returns the matrix of edge weights between the given nodes of g,
+// with +Inf for missing edges.
+func weights(g graph.Weighted, nodes []graph.Node) [][]float64 {
+	w := make([][]float64, len(nodes))
+	for i, u := range nodes {
+		w[i] = make([]float64, len(nodes))
+		for j, v := range nodes {
+			if i != j {
+				w[i][j] = weight(g, u, v)
+			}
+		}
+	}
+	return w
+}
+
+// weight returns the weight of the edge between u and v in g, or +Inf
+// if there is no such edge.
+func weight(g graph.Weighted, u, v graph.Node) float64 {
+	w, ok := g.Weight(u, v)
+	if !ok {
+		return math.Inf(1)
+	}
+	return w
+}
+
+func reverse(s []int) {
+	for i, j := 0, len(s)-1; i < j; i, j = i+1, j-1 {
+		s[i], s[j] = s[j], s[i]
+	}
+}
@@ -0,0 +1,182 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package tsp
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/simple"
+)
+
+// euclidean returns a complete graph with n nodes at random points
+// in the unit square weighted by Euclidean distance.
+func euclidean(n int, rnd *rand.Rand) (*simple.WeightedUndirectedGraph, [][2]float64) {
+	pts := make([][2]float64, n)
+	for i := range pts {
+		pts[i] = [2]float64{rnd.Float64(), rnd.Float64()}
+	}
+	return euclideanFrom(pts), pts
+}
+
+func euclideanFrom(pts [][2]float64) *simple.WeightedUndirectedGraph {
+	g := simple.NewWeightedUndirectedGraph(0, math.Inf(1))
+	for i := range pts {
+		g.AddNode(simple.Node(i))
+	}
+	for i := range pts {
+		for j := i + 1; j < len(pts); j++ {
+			d := math.Hypot(pts[i][0]-pts[j][0], pts[i][1]-pts[j][1])
+			g.SetWeightedEdge(simple.WeightedEdge{F: simple.Node(i), T: simple.Node(j), W: d})
+		}
+	}
+	return g
+}
+
+// optimal returns the weight of a minimum weight tour of g
+// found by exhaustive search.
+func optimal(g graph.Weighted) float64 {
+	nodes := g.Nodes()
+	best := math.Inf(1)
+	var permute func(k int)
+	permute = func(k int) {
+		if k == len(nodes) {
+			best = math.Min(best, Weight(g, nodes))
+			return
+		}
+		for i := k; i < len(nodes); i++ {
+			nodes[k], nodes[i] = nodes[i], nodes[k]
+			permute(k + 1)
+			nodes[k], nodes[i] = nodes[i], nodes[k]
+		}
+	}
+	if len(nodes) == 0 {
+		return 0
+	}
+	// Fix the first node since tours are cyclic.
+	permute(1)
+	return best
+}
+
+func TestHeuristics(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for _, n := range []int{0, 1, 2, 3, 5, 8} {
+		for trial := 0; trial < 5; trial++ {
+			g, _ := euclidean(n, rnd)
+			opt := optimal(g)
+
+			type result struct {
+				name   string
+				tour   []graph.Node
+				weight float64
+			}
+			var tours []result
+			add := func(name string, tour []graph.Node, weight float64) {
+				tours = append(tours, result{name: name, tour: tour, weight: weight})
+			}
+			christofides, cw := Christofides(g)
+			add("Christofides", christofides, cw)
+			if cw > 1.5*opt+1e-12 {
+				t.Errorf("Christofides tour exceeds bound for n=%d: got:%v optimal:%v", n, cw, opt)
+			}
+			if n != 0 {
+				nn, nw := NearestNeighbor(g, simple.Node(n-1))
+				add("NearestNeighbor", nn, nw)
+				if nn[0].ID() != int64(n-1) {
+					t.Errorf("nearest neighbor tour does not start at start node for n=%d", n)
+				}
+				two, tw := TwoOpt(g, nn)
+				add("TwoOpt", two, tw)
+				if tw > nw+1e-12 {
+					t.Errorf("2-opt increased tour weight for n=%d: %v > %v", n, tw, nw)
+				}
+				or, ow := OrOpt(g, nn)
+				add("OrOpt", or, ow)
+				if ow > nw+1e-12 {
+					t.Errorf("Or-opt increased tour weight for n=%d: %v > %v", n, ow, nw)
+				}
+			}
+
+			for _, tour := range tours {
+				checkTour(t, tour.name, g, tour.tour, n)
+				if got := Weight(g, tour.tour); math.Abs(got-tour.weight) > 1e-12 {
+					t.Errorf("%s: unexpected tour weight for n=%d: got:%v want:%v", tour.name, n, tour.weight, got)
+				}
+				if tour.weight < opt-1e-12 {
+					t.Errorf("%s: tour weight less than optimal for n=%d: %v < %v", tour.name, n, tour.weight, opt)
+				}
+			}
+		}
+	}
+}
+
+func TestTwoOptConvex(t *testing.T) {
+	// The optimal tour of points in convex position
+	// visits them in order around their hull, and
+	// is the only tour without crossing edges.
+	const n = 12
+	pts := make([][2]float64, n)
+	for i := range pts {
+		theta := 2 * math.Pi * float64(i) / n
+		pts[i] = [2]float64{math.Cos(theta), math.Sin(theta)}
+	}
+	g := euclideanFrom(pts)
+	want := 2 * n * math.Sin(math.Pi/n)
+
+	rnd := rand.New(rand.NewSource(1))
+	for trial := 0; trial < 10; trial++ {
+		tour := make([]graph.Node, n)
+		for i, p := range rnd.Perm(n) {
+			tour[i] = simple.Node(p)
+		}
+		orig := append([]graph.Node(nil), tour...)
+		got, w := TwoOpt(g, tour)
+		checkTour(t, "TwoOpt", g, got, n)
+		if math.Abs(w-want) > 1e-12 {
+			t.Errorf("2-opt did not find optimal convex tour: got:%v want:%v", w, want)
+		}
+		for i := range tour {
+			if tour[i] != orig[i] {
+				t.Error("input tour modified")
+				break
+			}
+		}
+	}
+}
+
+func TestOrOpt(t *testing.T) {
+	// Nodes on a line visited in order except for one
+	// node that is out of place. Moving the single node
+	// gives the optimal tour.
+	pts := [][2]float64{{0, 0}, {1, 0}, {2, 0}, {3, 0}, {4, 0}, {5, 0}}
+	g := euclideanFrom(pts)
+	tour := []graph.Node{simple.Node(0), simple.Node(3), simple.Node(1), simple.Node(2), simple.Node(4), simple.Node(5)}
+	got, w := OrOpt(g, tour)
+	checkTour(t, "OrOpt", g, got, len(pts))
+	if w != 10 {
+		t.Errorf("Or-opt did not find optimal tour: got:%v want:10 tour:%v", w, got)
+	}
+}
+
+// checkTour checks that tour visits each of the n nodes of g once.
+func checkTour(t *testing.T, name string, g graph.Graph, tour []graph.Node, n int) {
+	if len(tour) != n {
+		t.Errorf("%s: unexpected tour length: got:%d want:%d", name, len(tour), n)
+		return
+	}
+	seen := make(map[int64]bool)
+	for _, u := range tour {
+		if !g.Has(u) {
+			t.Errorf("%s: node %d not in graph", name, u.ID())
+		}
+		if seen[u.ID()] {
+			t.Errorf("%s: node %d visited more than once", name, u.ID())
+		}
+		seen[u.ID()] = true
+	}
+}
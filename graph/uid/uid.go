@@ -0,0 +1,106 @@
+// Copyright ©2014 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package uid implements unique ID provision for graphs.
+//
+// A Set tracks the IDs in use by a graph. It hands out IDs that are
+// guaranteed to be unused and reclaims released IDs, handing out the
+// lowest released ID first. Use, Release and Has take constant time,
+// and NewID takes time logarithmic in the number of released IDs.
+package uid // import "gonum.org/v1/gonum/graph/uid"
+
+import (
+	"container/heap"
+
+	"gonum.org/v1/gonum/graph/internal/set"
+)
+
+// Max is the maximum value of int64.
+const Max = int64(^uint64(0) >> 1)
+
+// Set implements available ID storage.
+type Set struct {
+	maxID int64
+	used  set.Int64s
+
+	// free is a min-heap of released IDs and queued
+	// holds the IDs in free. IDs in free that have been
+	// used again are removed lazily by NewID.
+	free   int64s
+	queued set.Int64s
+}
+
+// NewSet returns a new Set. The returned value should not be passed except by pointer.
+func NewSet() Set {
+	return Set{maxID: -1, used: make(set.Int64s), queued: make(set.Int64s)}
+}
+
+// NewID returns a new unique ID. The ID returned is not considered used
+// until passed in a call to Use.
+//
+// NewID returns the lowest released non-negative ID if there is one,
+// otherwise one more than the highest ID that has been used. If that
+// would overflow, the lowest unused non-negative ID is returned. NewID
+// will panic if all non-negative IDs are in use.
+func (s *Set) NewID() int64 {
+	for len(s.free) != 0 {
+		id := s.free[0]
+		if !s.used.Has(id) {
+			return id
+		}
+		heap.Pop(&s.free)
+		s.queued.Remove(id)
+	}
+	if s.maxID != Max {
+		return s.maxID + 1
+	}
+	for id := int64(0); id >= 0; id++ {
+		if !s.used.Has(id) {
+			return id
+		}
+	}
+	panic("uid: no unused ID")
+}
+
+// Use adds the id to the used IDs in the Set.
+func (s *Set) Use(id int64) {
+	s.used.Add(id)
+	if id > s.maxID {
+		s.maxID = id
+	}
+}
+
+// Release frees the id for reuse. Releasing an ID that is
+// not in use is a no-op. Since NewID only returns non-negative
+// IDs, released negative IDs are not returned by NewID.
+func (s *Set) Release(id int64) {
+	if !s.used.Has(id) {
+		return
+	}
+	s.used.Remove(id)
+	if id >= 0 && !s.queued.Has(id) {
+		s.queued.Add(id)
+		heap.Push(&s.free, id)
+	}
+}
+
+// Has returns whether the id is in use.
+func (s *Set) Has(id int64) bool {
+	return s.used.Has(id)
+}
+
+// int64s is a min-heap of IDs.
+type int64s []int64
+
+func (h int64s) Len() int            { return len(h) }
+func (h int64s) Less(i, j int) bool  { return h[i] < h[j] }
+func (h int64s) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *int64s) Push(x interface{}) { *h = append(*h, x.(int64)) }
+func (h *int64s) Pop() interface{} {
+	old := *h
+	n := len(old) - 1
+	x := old[n]
+	*h = old[:n]
+	return x
+}
@@ -0,0 +1,119 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package uid
+
+import (
+	"testing"
+
+	"golang.org/x/exp/rand"
+)
+
+func TestSetNewID(t *testing.T) {
+	s := NewSet()
+	if id := s.NewID(); id != 0 {
+		t.Errorf("unexpected first ID: got:%d want:0", id)
+	}
+	for _, id := range []int64{0, 1, 2, 10} {
+		s.Use(id)
+	}
+	if id := s.NewID(); id != 11 {
+		t.Errorf("unexpected ID after use: got:%d want:11", id)
+	}
+	s.Release(10)
+	s.Release(1)
+	s.Release(5) // Not in use.
+	if id := s.NewID(); id != 1 {
+		t.Errorf("unexpected ID after release: got:%d want:1", id)
+	}
+	s.Use(1)
+	if id := s.NewID(); id != 10 {
+		t.Errorf("unexpected ID after reuse: got:%d want:10", id)
+	}
+	s.Use(10)
+	if id := s.NewID(); id != 11 {
+		t.Errorf("unexpected ID after all released IDs reused: got:%d want:11", id)
+	}
+
+	// Once the maximum ID has been used, the lowest
+	// unused non-negative ID is returned.
+	s = NewSet()
+	for _, id := range []int64{-1, 0, 1, 2, 4, Max} {
+		s.Use(id)
+	}
+	if id := s.NewID(); id != 3 {
+		t.Errorf("unexpected ID after use of Max: got:%d want:3", id)
+	}
+	s.Use(3)
+	if id := s.NewID(); id != 5 {
+		t.Errorf("unexpected ID after use of Max: got:%d want:5", id)
+	}
+}
+
+func TestSetRandom(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	s := NewSet()
+	ref := make(map[int64]bool)
+	var maxID int64 = -1
+	for i := 0; i < 10000; i++ {
+		id := int64(rnd.Intn(100)) - 50
+		switch rnd.Intn(3) {
+		case 0:
+			s.Use(id)
+			ref[id] = true
+			if id > maxID {
+				maxID = id
+			}
+		case 1:
+			s.Release(id)
+			delete(ref, id)
+		case 2:
+			id = s.NewID()
+			if id < 0 || ref[id] {
+				t.Fatalf("NewID returned invalid ID %d after %d operations", id, i)
+			}
+			s.Use(id)
+			ref[id] = true
+			if id > maxID {
+				maxID = id
+			}
+		}
+	}
+	for id := int64(-60); id <= maxID+10; id++ {
+		if s.Has(id) != ref[id] {
+			t.Errorf("unexpected membership for %d: got:%t want:%t", id, s.Has(id), ref[id])
+		}
+	}
+}
+
+func BenchmarkSetUseRandom(b *testing.B) {
+	rnd := rand.New(rand.NewSource(1))
+	ids := make([]int64, 1e5)
+	for i := range ids {
+		ids[i] = rnd.Int63()
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		s := NewSet()
+		for _, id := range ids {
+			s.Use(id)
+		}
+		for _, id := range ids {
+			s.Release(id)
+		}
+	}
+}
+
+func TestSetReleaseNegative(t *testing.T) {
+	s := NewSet()
+	s.Use(-3)
+	s.Use(0)
+	s.Release(-3)
+	if s.Has(-3) {
+		t.Error("released ID still in use")
+	}
+	if id := s.NewID(); id != 1 {
+		t.Errorf("unexpected new ID after releasing negative ID: got:%d want:1", id)
+	}
+}
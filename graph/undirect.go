@@ -41,14 +41,13 @@ func (g Undirect) HasEdgeBetween(x, y Node) bool { return g.G.HasEdgeBetween(x,
 
 // Edge returns the edge from u to v if such an edge exists and nil otherwise.
 // The node v must be directly reachable from u as defined by the From method.
-// If an edge exists, the Edge returned is an EdgePair. The weight of
-// the edge is determined by applying the Merge func to the weights of the
-// edges between u and v.
+// If an edge exists, the Edge returned is an EdgePair holding the edges from
+// u to v and from v to u in G, either of which may be nil.
 func (g Undirect) Edge(u, v Node) Edge { return g.EdgeBetween(u, v) }
 
 // EdgeBetween returns the edge between nodes x and y. If an edge exists, the
-// Edge returned is an EdgePair. The weight of the edge is determined by
-// applying the Merge func to the weights of edges between x and y.
+// Edge returned is an EdgePair holding the edges from x to y and from y to x
+// in G, either of which may be nil.
 func (g Undirect) EdgeBetween(x, y Node) Edge {
 	fe := g.G.Edge(x, y)
 	re := g.G.Edge(y, x)
@@ -168,9 +167,12 @@ func (g UndirectWeighted) WeightedEdgeBetween(x, y Node) WeightedEdge {
 }
 
 // Weight returns the weight for the edge between x and y if Edge(x, y) returns a non-nil Edge.
-// If x and y are the same node the internal node weight is returned. If there is no joining
-// edge between the two nodes the weight value returned is zero. Weight returns true if an edge
-// exists between x and y or if x and y have the same ID, false otherwise.
+// The weight is determined by applying the Merge func to the weights reported by G for the
+// edges from x to y and from y to x, with Absent used in place of the weight of a missing edge.
+// If x and y are the same node the merged internal node weights of G are returned. If there is
+// no joining edge between the two nodes the weight value returned is the merge of two Absent
+// values. Weight returns true if an edge exists between x and y or if x and y have the same ID,
+// false otherwise.
 func (g UndirectWeighted) Weight(x, y Node) (w float64, ok bool) {
 	fe := g.G.Edge(x, y)
 	re := g.G.Edge(y, x)
@@ -162,6 +162,41 @@ func TestUndirectWeighted(t *testing.T) {
 	}
 }
 
+func TestUndirectWeightedWeight(t *testing.T) {
+	for i, test := range weightedDirectedGraphs {
+		g := test.g()
+		for _, e := range test.edges {
+			g.SetWeightedEdge(e)
+		}
+
+		src := graph.UndirectWeighted{G: g, Absent: test.absent, Merge: test.merge}
+		for _, u := range src.Nodes() {
+			for _, v := range src.Nodes() {
+				if u.ID() == v.ID() {
+					continue
+				}
+				w, ok := src.Weight(u, v)
+				if ok != src.HasEdgeBetween(u, v) {
+					t.Errorf("unexpected ok for case %d edge %d--%d: got:%t", i, u.ID(), v.ID(), ok)
+				}
+				want := test.want.At(int(u.ID()), int(v.ID()))
+				if !ok {
+					want = (test.absent + test.absent) / 2
+					if test.merge != nil {
+						want = test.merge(test.absent, test.absent, nil, nil)
+					}
+				}
+				if w != want {
+					t.Errorf("unexpected weight for case %d edge %d--%d: got:%v want:%v", i, u.ID(), v.ID(), w, want)
+				}
+				if rw, _ := src.Weight(v, u); rw != w {
+					t.Errorf("asymmetric weight for case %d edge %d--%d: got:%v and %v", i, u.ID(), v.ID(), w, rw)
+				}
+			}
+		}
+	}
+}
+
 type unit struct {
 	mat.Matrix
 }
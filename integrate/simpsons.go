@@ -0,0 +1,125 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package integrate
+
+import "sort"
+
+// Simpsons estimates the integral of a function f
+//  \int_a^b f(x) dx
+// from a set of evaluations of the function using Simpson's rule.
+// Simpson's rule makes piecewise quadratic approximations to the function
+// through consecutive triples of points, and is exact for polynomials of
+// degree up to two. The x values need not be evenly spaced. If the number
+// of intervals is odd, the final interval is estimated from the quadratic
+// through the last three points. The estimate is also exact for polynomials
+// of degree three when the points are evenly spaced and the number of
+// intervals is even.
+// More details on Simpson's rule can be found at:
+// https://en.wikipedia.org/wiki/Simpson%27s_rule
+//
+// The (x,f) input data points must be sorted along x.
+// The x and f slices must be of equal length and have length > 2.
+func Simpsons(x, f []float64) float64 {
+	switch {
+	case len(x) != len(f):
+		panic("integrate: slice length mismatch")
+	case len(x) < 3:
+		panic("integrate: input data too small")
+	case !sort.Float64sAreSorted(x):
+		panic("integrate: input must be sorted")
+	}
+
+	integral := 0.0
+	i := 0
+	for ; i+2 < len(x); i += 2 {
+		a, b := quadraticIntegrals(x[i:i+3], f[i:i+3])
+		integral += a + b
+	}
+	if i+1 < len(x) {
+		_, b := quadraticIntegrals(x[i-1:i+2], f[i-1:i+2])
+		integral += b
+	}
+
+	return integral
+}
+
+// CumulativeSimpsons returns the running integral of the function f
+// estimated using piecewise quadratic approximations, so that element i of
+// the result is
+//  \int_x[0]^x[i] f(x) dx
+// and the first element is zero. The integral over each interior interval
+// is the mean of the integrals of the quadratics through the interval and
+// its preceding and following neighbor points, and the integrals over the
+// first and last intervals use the quadratic through the three points at
+// that end of the data. The estimate is exact for polynomials of degree up
+// to two, and for polynomials of degree three over interior intervals when
+// the points are evenly spaced.
+//
+// If dst is not nil, the result is stored in dst and dst is returned;
+// CumulativeSimpsons will panic if dst is not nil and its length is not
+// equal to the length of x.
+//
+// The (x,f) input data points must be sorted along x.
+// The x and f slices must be of equal length and have length > 2.
+func CumulativeSimpsons(dst, x, f []float64) []float64 {
+	switch {
+	case len(x) != len(f):
+		panic("integrate: slice length mismatch")
+	case len(x) < 3:
+		panic("integrate: input data too small")
+	case !sort.Float64sAreSorted(x):
+		panic("integrate: input must be sorted")
+	}
+	if dst == nil {
+		dst = make([]float64, len(x))
+	}
+	if len(dst) != len(x) {
+		panic("integrate: destination length mismatch")
+	}
+
+	// back holds the integral over [x[i], x[i+1]] of
+	// the quadratic through x[i-1], x[i] and x[i+1].
+	var back float64
+	integral := 0.0
+	dst[0] = 0
+	for i := 0; i < len(x)-1; i++ {
+		var v float64
+		switch {
+		case i+2 < len(x):
+			forward, next := quadraticIntegrals(x[i:i+3], f[i:i+3])
+			if i == 0 {
+				v = forward
+			} else {
+				v = 0.5 * (forward + back)
+			}
+			back = next
+		default:
+			v = back
+		}
+		integral += v
+		dst[i+1] = integral
+	}
+
+	return dst
+}
+
+// quadraticIntegrals returns the integrals over [x[0], x[1]] and
+// [x[1], x[2]] of the quadratic passing through the three points
+// (x[i], f[i]).
+func quadraticIntegrals(x, f []float64) (first, second float64) {
+	h0 := x[1] - x[0]
+	h1 := x[2] - x[1]
+	h := h0 + h1
+
+	// The quadratic in Newton form about x[0] is
+	//  p(t) = f[0] + d1*t + d2*t*(t-h0)
+	// with t = x-x[0].
+	d1 := (f[1] - f[0]) / h0
+	d2 := ((f[2]-f[1])/h1 - d1) / h
+
+	first = f[0]*h0 + d1*h0*h0/2 - d2*h0*h0*h0/6
+	second = f[0]*h1 + d1*(h*h-h0*h0)/2 + d2*((h*h*h-h0*h0*h0)/3-h0*(h*h-h0*h0)/2)
+	return first, second
+}
@@ -0,0 +1,120 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package integrate
+
+import (
+	"math"
+	"testing"
+
+	"gonum.org/v1/gonum/floats"
+)
+
+func TestSimpsons(t *testing.T) {
+	const N = 1e4
+	for i, test := range []struct {
+		x    []float64
+		f    func(x float64) float64
+		want float64
+		tol  float64
+	}{
+		{
+			x:    []float64{0, 1, 2},
+			f:    func(x float64) float64 { return x * x * x },
+			want: 4,
+			tol:  1e-14,
+		},
+		{
+			x:    []float64{0, 0.25, 1, 1.5, 3},
+			f:    func(x float64) float64 { return 3*x*x - x + 2 },
+			want: 28.5,
+			tol:  1e-13,
+		},
+		{
+			// Odd number of intervals.
+			x:    []float64{0, 0.25, 1, 3},
+			f:    func(x float64) float64 { return 3*x*x - x + 2 },
+			want: 28.5,
+			tol:  1e-13,
+		},
+		{
+			x:    floats.Span(make([]float64, N+1), 0, math.Pi),
+			f:    math.Sin,
+			want: 2,
+			tol:  1e-14,
+		},
+		{
+			x:    floats.Span(make([]float64, N), 0, 1),
+			f:    math.Exp,
+			want: math.E - 1,
+			tol:  1e-12,
+		},
+	} {
+		y := make([]float64, len(test.x))
+		for j, v := range test.x {
+			y[j] = test.f(v)
+		}
+		v := Simpsons(test.x, y)
+		if !floats.EqualWithinAbs(v, test.want, test.tol) {
+			t.Errorf("test #%d: got=%v want=%v", i, v, test.want)
+		}
+	}
+	if !panics(func() { Simpsons([]float64{0, 1}, []float64{0, 1}) }) {
+		t.Error("expected panic for too few points")
+	}
+}
+
+func TestCumulativeSimpsons(t *testing.T) {
+	// Quadratics are integrated exactly on uneven grids.
+	x := []float64{0, 0.1, 0.5, 0.6, 1.5, 2, 2.2}
+	f := make([]float64, len(x))
+	for i, v := range x {
+		f[i] = 3*v*v - 4*v + 1
+	}
+	got := CumulativeSimpsons(nil, x, f)
+	for i, v := range x {
+		want := v*v*v - 2*v*v + v
+		if !floats.EqualWithinAbs(got[i], want, 1e-14) {
+			t.Errorf("unexpected quadratic integral to %v: got:%v want:%v", v, got[i], want)
+		}
+	}
+
+	// Displacement from constant acceleration by repeated integration.
+	const n = 101
+	time := floats.Span(make([]float64, n), 0, 10)
+	accel := make([]float64, n)
+	for i := range accel {
+		accel[i] = 9.8
+	}
+	vel := CumulativeSimpsons(nil, time, accel)
+	disp := CumulativeSimpsons(nil, time, vel)
+	for i, v := range time {
+		want := 0.5 * 9.8 * v * v
+		if !floats.EqualWithinAbs(disp[i], want, 1e-10) {
+			t.Errorf("unexpected displacement at %v: got:%v want:%v", v, disp[i], want)
+		}
+	}
+
+	// The error is much smaller than that of the trapezoidal rule.
+	x = floats.Span(make([]float64, 51), 0, math.Pi)
+	f = make([]float64, len(x))
+	for i, v := range x {
+		f[i] = math.Sin(v)
+	}
+	simp := CumulativeSimpsons(nil, x, f)
+	trap := CumulativeTrapezoidal(nil, x, f)
+	var simpErr, trapErr float64
+	for i, v := range x {
+		want := 1 - math.Cos(v)
+		simpErr = math.Max(simpErr, math.Abs(simp[i]-want))
+		trapErr = math.Max(trapErr, math.Abs(trap[i]-want))
+	}
+	if simpErr > trapErr/100 {
+		t.Errorf("unexpectedly large error: got:%v trapezoidal:%v", simpErr, trapErr)
+	}
+
+	if !panics(func() { CumulativeSimpsons(make([]float64, 1), x, f) }) {
+		t.Error("expected panic for destination length mismatch")
+	}
+}
@@ -38,3 +38,41 @@ func Trapezoidal(x, f []float64) float64 {
 
 	return integral
 }
+
+// CumulativeTrapezoidal returns the running integral of the function f
+// estimated using the trapezoidal rule, so that element i of the result is
+//  \int_x[0]^x[i] f(x) dx
+// and the first element is zero. The result can be integrated again to
+// obtain, for example, displacement from sampled acceleration.
+//
+// If dst is not nil, the result is stored in dst and dst is returned;
+// CumulativeTrapezoidal will panic if dst is not nil and its length is not
+// equal to the length of x.
+//
+// The (x,f) input data points must be sorted along x.
+// The x and f slices must be of equal length and have length > 1.
+func CumulativeTrapezoidal(dst, x, f []float64) []float64 {
+	switch {
+	case len(x) != len(f):
+		panic("integrate: slice length mismatch")
+	case len(x) < 2:
+		panic("integrate: input data too small")
+	case !sort.Float64sAreSorted(x):
+		panic("integrate: input must be sorted")
+	}
+	if dst == nil {
+		dst = make([]float64, len(x))
+	}
+	if len(dst) != len(x) {
+		panic("integrate: destination length mismatch")
+	}
+
+	integral := 0.0
+	dst[0] = 0
+	for i := 0; i < len(x)-1; i++ {
+		integral += 0.5 * (x[i+1] - x[i]) * (f[i+1] + f[i])
+		dst[i+1] = integral
+	}
+
+	return dst
+}
@@ -80,3 +80,36 @@ func TestTrapezoidal(t *testing.T) {
 		}
 	}
 }
+
+func TestCumulativeTrapezoidal(t *testing.T) {
+	x := []float64{0, 0.5, 1.5, 2, 3.25}
+	f := make([]float64, len(x))
+	for i, v := range x {
+		f[i] = 2*v + 1
+	}
+	got := CumulativeTrapezoidal(nil, x, f)
+	for i, v := range x {
+		want := v*v + v
+		if !floats.EqualWithinAbs(got[i], want, 1e-14) {
+			t.Errorf("unexpected integral to %v: got:%v want:%v", v, got[i], want)
+		}
+	}
+	if last := got[len(got)-1]; last != Trapezoidal(x, f) {
+		t.Errorf("cumulative integral does not match total: got:%v want:%v", last, Trapezoidal(x, f))
+	}
+	dst := make([]float64, len(x))
+	if CumulativeTrapezoidal(dst, x, f); !floats.Equal(dst, got) {
+		t.Errorf("unexpected result in dst: got:%v want:%v", dst, got)
+	}
+	if !panics(func() { CumulativeTrapezoidal(make([]float64, 2), x, f) }) {
+		t.Error("expected panic for destination length mismatch")
+	}
+}
+
+func panics(fn func()) (ok bool) {
+	defer func() {
+		ok = recover() != nil
+	}()
+	fn()
+	return
+}
@@ -0,0 +1,32 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package instrument_test
+
+import (
+	"fmt"
+
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/mat/instrument"
+)
+
+func ExampleCounter() {
+	a := mat.NewSymDense(2, []float64{
+		4, 1,
+		1, 3,
+	})
+
+	// Count the factorizations made through f. The time
+	// spent in each kind is available from c.Elapsed.
+	var c instrument.Counter
+	f := instrument.Factorizer{Hook: c.Hook}
+	var chol mat.Cholesky
+	f.Cholesky(&chol, a)
+	var eig mat.EigenSym
+	f.EigenSym(&eig, a, false)
+	fmt.Printf("Cholesky:%d EigenSym:%d Total:%d\n", c.Count(instrument.Cholesky), c.Count(instrument.EigenSym), c.Total())
+
+	// Output:
+	// Cholesky:1 EigenSym:1 Total:2
+}
@@ -0,0 +1,202 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package instrument provides matrix factorizations that report their
+// start and completion to a caller supplied hook.
+//
+// A Factorizer wraps the Factorize methods of the mat decompositions so that
+// the cost of each factorization can be measured, for example to expose
+// operation counters and timing metrics from a long-running service. A
+// Counter provides such counters and timings. Factorizations are reported
+// as a whole; progress within a factorization is not reported.
+// Instrumentation is local to the Factorizer; factorizations performed by
+// other code, including those performed internally by mat routines such as
+// Dense.Solve, are not reported.
+package instrument // import "gonum.org/v1/gonum/mat/instrument"
+
+import (
+	"sync/atomic"
+	"time"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// Op is a factorization reported by a Factorizer.
+type Op int
+
+const (
+	Cholesky Op = iota
+	Eigen
+	EigenSym
+	GSVD
+	HOGSVD
+	LQ
+	LU
+	QR
+	SVD
+
+	numOps
+)
+
+var opNames = [numOps]string{
+	Cholesky: "Cholesky",
+	Eigen:    "Eigen",
+	EigenSym: "EigenSym",
+	GSVD:     "GSVD",
+	HOGSVD:   "HOGSVD",
+	LQ:       "LQ",
+	LU:       "LU",
+	QR:       "QR",
+	SVD:      "SVD",
+}
+
+// String returns the name of the factorization.
+func (op Op) String() string {
+	if op < 0 || op >= numOps {
+		return "Op(?)"
+	}
+	return opNames[op]
+}
+
+// Hook is called at the start of each factorization performed by a
+// Factorizer with the factorization and the dimensions of its input. If
+// the returned function is not nil, it is called when the factorization
+// completes. A Hook must be safe for concurrent use if the Factorizer
+// is used concurrently.
+type Hook func(op Op, r, c int) (done func())
+
+// Counter counts the factorizations of each kind and the total time spent
+// in them. Counter is safe for concurrent use.
+type Counter struct {
+	counts  [numOps]int64
+	elapsed [numOps]int64
+}
+
+// Hook records the start of op and returns a function that records the
+// time taken by op when it completes. Hook is intended to be used as the
+// Hook field of a Factorizer.
+func (c *Counter) Hook(op Op, _, _ int) (done func()) {
+	atomic.AddInt64(&c.counts[op], 1)
+	start := time.Now()
+	return func() {
+		atomic.AddInt64(&c.elapsed[op], int64(time.Since(start)))
+	}
+}
+
+// Count returns the number of recorded factorizations of kind op.
+func (c *Counter) Count(op Op) int64 {
+	return atomic.LoadInt64(&c.counts[op])
+}
+
+// Elapsed returns the total time spent in completed factorizations of
+// kind op.
+func (c *Counter) Elapsed(op Op) time.Duration {
+	return time.Duration(atomic.LoadInt64(&c.elapsed[op]))
+}
+
+// Total returns the number of recorded factorizations of all kinds.
+func (c *Counter) Total() int64 {
+	var n int64
+	for op := range c.counts {
+		n += atomic.LoadInt64(&c.counts[op])
+	}
+	return n
+}
+
+// Reset sets all the counts and times to zero.
+func (c *Counter) Reset() {
+	for op := range c.counts {
+		atomic.StoreInt64(&c.counts[op], 0)
+		atomic.StoreInt64(&c.elapsed[op], 0)
+	}
+}
+
+// Factorizer performs matrix factorizations, reporting each to Hook.
+// If Hook is nil, factorizations are not reported.
+type Factorizer struct {
+	Hook Hook
+}
+
+// start calls the hook for the operation op with dimensions r and c and
+// returns a function to be called when the operation completes.
+func (f Factorizer) start(op Op, r, c int) func() {
+	if f.Hook == nil {
+		return noop
+	}
+	done := f.Hook(op, r, c)
+	if done == nil {
+		return noop
+	}
+	return done
+}
+
+func noop() {}
+
+// Cholesky calls dst.Factorize(a) and returns its result.
+func (f Factorizer) Cholesky(dst *mat.Cholesky, a mat.Symmetric) (ok bool) {
+	n := a.Symmetric()
+	defer f.start(Cholesky, n, n)()
+	return dst.Factorize(a)
+}
+
+// Eigen calls dst.Factorize(a, left, right) and returns its result.
+func (f Factorizer) Eigen(dst *mat.Eigen, a mat.Matrix, left, right bool) (ok bool) {
+	r, c := a.Dims()
+	defer f.start(Eigen, r, c)()
+	return dst.Factorize(a, left, right)
+}
+
+// EigenSym calls dst.Factorize(a, vectors) and returns its result.
+func (f Factorizer) EigenSym(dst *mat.EigenSym, a mat.Symmetric, vectors bool) (ok bool) {
+	n := a.Symmetric()
+	defer f.start(EigenSym, n, n)()
+	return dst.Factorize(a, vectors)
+}
+
+// GSVD calls dst.Factorize(a, b, kind) and returns its result. The
+// reported dimensions are those of a.
+func (f Factorizer) GSVD(dst *mat.GSVD, a, b mat.Matrix, kind mat.GSVDKind) (ok bool) {
+	r, c := a.Dims()
+	defer f.start(GSVD, r, c)()
+	return dst.Factorize(a, b, kind)
+}
+
+// HOGSVD calls dst.Factorize(m...) and returns its result. The reported
+// dimensions are those of the first matrix, or zero if m is empty.
+func (f Factorizer) HOGSVD(dst *mat.HOGSVD, m ...mat.Matrix) (ok bool) {
+	var r, c int
+	if len(m) != 0 {
+		r, c = m[0].Dims()
+	}
+	defer f.start(HOGSVD, r, c)()
+	return dst.Factorize(m...)
+}
+
+// LQ calls dst.Factorize(a).
+func (f Factorizer) LQ(dst *mat.LQ, a mat.Matrix) {
+	r, c := a.Dims()
+	defer f.start(LQ, r, c)()
+	dst.Factorize(a)
+}
+
+// LU calls dst.Factorize(a).
+func (f Factorizer) LU(dst *mat.LU, a mat.Matrix) {
+	r, c := a.Dims()
+	defer f.start(LU, r, c)()
+	dst.Factorize(a)
+}
+
+// QR calls dst.Factorize(a).
+func (f Factorizer) QR(dst *mat.QR, a mat.Matrix) {
+	r, c := a.Dims()
+	defer f.start(QR, r, c)()
+	dst.Factorize(a)
+}
+
+// SVD calls dst.Factorize(a, kind) and returns its result.
+func (f Factorizer) SVD(dst *mat.SVD, a mat.Matrix, kind mat.SVDKind) (ok bool) {
+	r, c := a.Dims()
+	defer f.start(SVD, r, c)()
+	return dst.Factorize(a, kind)
+}
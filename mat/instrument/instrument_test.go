@@ -0,0 +1,100 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package instrument
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestFactorizer(t *testing.T) {
+	var (
+		mu      sync.Mutex
+		started []Op
+		dims    [][2]int
+		done    int
+	)
+	f := Factorizer{Hook: func(op Op, r, c int) func() {
+		mu.Lock()
+		started = append(started, op)
+		dims = append(dims, [2]int{r, c})
+		mu.Unlock()
+		return func() {
+			mu.Lock()
+			done++
+			mu.Unlock()
+		}
+	}}
+
+	a := mat.NewSymDense(3, []float64{
+		4, 1, 0,
+		1, 4, 1,
+		0, 1, 4,
+	})
+	var chol mat.Cholesky
+	if !f.Cholesky(&chol, a) {
+		t.Error("unexpected Cholesky failure")
+	}
+	var want mat.Cholesky
+	want.Factorize(a)
+	if chol.LogDet() != want.LogDet() {
+		t.Errorf("unexpected Cholesky factorization: got log det:%v want:%v", chol.LogDet(), want.LogDet())
+	}
+	var lu mat.LU
+	f.LU(&lu, a)
+	var svd mat.SVD
+	f.SVD(&svd, mat.NewDense(3, 2, []float64{1, 2, 3, 4, 5, 6}), mat.SVDThin)
+
+	wantOps := []Op{Cholesky, LU, SVD}
+	if !reflect.DeepEqual(started, wantOps) {
+		t.Errorf("unexpected operations: got:%v want:%v", started, wantOps)
+	}
+	wantDims := [][2]int{{3, 3}, {3, 3}, {3, 2}}
+	if !reflect.DeepEqual(dims, wantDims) {
+		t.Errorf("unexpected dimensions: got:%v want:%v", dims, wantDims)
+	}
+	if done != len(wantOps) {
+		t.Errorf("unexpected number of completions: got:%d want:%d", done, len(wantOps))
+	}
+
+	// A nil done function and a nil hook are both allowed.
+	f = Factorizer{Hook: func(Op, int, int) func() { return nil }}
+	f.Cholesky(&chol, a)
+	f = Factorizer{}
+	f.Cholesky(&chol, a)
+}
+
+func TestCounter(t *testing.T) {
+	var c Counter
+	f := Factorizer{Hook: c.Hook}
+	a := mat.NewDense(2, 2, []float64{2, 1, 1, 3})
+	var qr mat.QR
+	var lu mat.LU
+	f.QR(&qr, a)
+	f.QR(&qr, a)
+	f.LU(&lu, a)
+	if got := c.Count(QR); got != 2 {
+		t.Errorf("unexpected QR count: got:%d want:2", got)
+	}
+	if got := c.Count(LU); got != 1 {
+		t.Errorf("unexpected LU count: got:%d want:1", got)
+	}
+	if got := c.Total(); got != 3 {
+		t.Errorf("unexpected total count: got:%d want:3", got)
+	}
+	if c.Elapsed(QR) < 0 || c.Elapsed(SVD) != 0 {
+		t.Errorf("unexpected elapsed times: QR:%v SVD:%v", c.Elapsed(QR), c.Elapsed(SVD))
+	}
+	c.Reset()
+	if c.Total() != 0 || c.Elapsed(QR) != 0 {
+		t.Errorf("counter not reset: total:%d QR elapsed:%v", c.Total(), c.Elapsed(QR))
+	}
+	if got := Op(numOps).String(); got != "Op(?)" {
+		t.Errorf("unexpected name for invalid op: %q", got)
+	}
+}
@@ -0,0 +1,125 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import "math"
+
+const badWeight = "mat: negative weight"
+
+// WeightedLeastSquares finds the weighted least squares solution x of the
+// overdetermined system of linear equations A * x = b that minimizes
+//  \sum_i weights[i] * (b_i - (A * x)_i)^2,
+// storing x into beta. If cov is not nil, the unscaled covariance of the
+// coefficients
+//  (A^T * W * A)^-1,
+// where W is the diagonal matrix of weights, is stored into cov. This is the
+// covariance of x when the weights are the reciprocals of the variances of
+// the elements of b. If the weights are only known up to a common factor,
+// cov must be scaled by an estimate of that factor, for example the weighted
+// residual sum of squares divided by the number of degrees of freedom.
+//
+// If A * diag(sqrt(weights)) is singular or near-singular a Condition error
+// is returned. See the documentation for Condition for more information.
+//
+// WeightedLeastSquares panics if A has fewer rows than columns, if the number
+// of rows of A is not equal to the length of b and weights, if any weight is
+// negative, or if cov is not empty and its size is not the number of columns
+// of A.
+func WeightedLeastSquares(beta *VecDense, cov *SymDense, a Matrix, b Vector, weights []float64) error {
+	r, c := a.Dims()
+	if r < c || b.Len() != r || len(weights) != r {
+		panic(ErrShape)
+	}
+
+	// Whiten the system by scaling each
+	// row by the square root of its weight.
+	aw := NewDense(r, c, nil)
+	bw := NewVecDense(r, nil)
+	for i, w := range weights {
+		if w < 0 {
+			panic(badWeight)
+		}
+		s := math.Sqrt(w)
+		for j := 0; j < c; j++ {
+			aw.set(i, j, s*a.At(i, j))
+		}
+		bw.setVec(i, s*b.AtVec(i))
+	}
+	return leastSquares(beta, cov, aw, bw)
+}
+
+// GeneralizedLeastSquares finds the generalized least squares solution x of
+// the overdetermined system of linear equations A * x = b where the errors of
+// b have the positive definite covariance matrix sigma. The solution minimizes
+//  (b - A * x)^T * sigma^-1 * (b - A * x),
+// and is stored into beta. If cov is not nil, the covariance of the
+// coefficients
+//  (A^T * sigma^-1 * A)^-1
+// is stored into cov.
+//
+// If sigma is not positive definite, ErrNotPSD is returned. If the whitened
+// design matrix L^-1 * A, where L * L^T = sigma, is singular or near-singular
+// a Condition error is returned. See the documentation for Condition for more
+// information.
+//
+// GeneralizedLeastSquares panics if A has fewer rows than columns, if the
+// number of rows of A is not equal to the length of b or the size of sigma,
+// or if cov is not empty and its size is not the number of columns of A.
+func GeneralizedLeastSquares(beta *VecDense, cov *SymDense, a Matrix, b Vector, sigma Symmetric) error {
+	r, c := a.Dims()
+	if r < c || b.Len() != r || sigma.Symmetric() != r {
+		panic(ErrShape)
+	}
+
+	// Whiten the system using the Cholesky
+	// factor of sigma, so the errors of the
+	// whitened system are uncorrelated with
+	// unit variance.
+	var chol Cholesky
+	if !chol.Factorize(sigma) {
+		return ErrNotPSD
+	}
+	l := chol.LTo(nil)
+	var aw Dense
+	err := aw.Solve(l, a)
+	if err != nil {
+		return err
+	}
+	var bw VecDense
+	err = bw.SolveVec(l, b)
+	if err != nil {
+		return err
+	}
+	return leastSquares(beta, cov, &aw, &bw)
+}
+
+// leastSquares finds the least squares solution x of a * x = b, storing x into
+// beta and, if cov is not nil, (a^T * a)^-1 into cov.
+func leastSquares(beta *VecDense, cov *SymDense, a *Dense, b *VecDense) error {
+	var qr QR
+	qr.Factorize(a)
+	err := qr.SolveVec(beta, false, b)
+	if err != nil {
+		return err
+	}
+	if cov == nil {
+		return nil
+	}
+
+	// If a = Q * R then a^T * a = R^T * R,
+	// so (a^T * a)^-1 = R^-1 * R^-T.
+	_, c := a.Dims()
+	rr := qr.RTo(nil)
+	u := NewTriDense(c, Upper, nil)
+	for i := 0; i < c; i++ {
+		for j := i; j < c; j++ {
+			u.SetTri(i, j, rr.At(i, j))
+		}
+	}
+	var rinv TriDense
+	err = rinv.InverseTri(u)
+	cov.SymOuterK(1, &rinv)
+	return err
+}
@@ -0,0 +1,184 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"testing"
+
+	"golang.org/x/exp/rand"
+)
+
+// normalEquations returns the solution of (A^T * W * A) * x = A^T * W * b
+// and (A^T * W * A)^-1 for the symmetric weight matrix W.
+func normalEquations(a Matrix, b Vector, w Symmetric) (*VecDense, *Dense) {
+	var aw, ata, inv Dense
+	aw.Mul(a.T(), w)
+	ata.Mul(&aw, a)
+	err := inv.Inverse(&ata)
+	if err != nil {
+		panic(err)
+	}
+	var atb, x VecDense
+	atb.MulVec(&aw, b)
+	x.MulVec(&inv, &atb)
+	return &x, &inv
+}
+
+func TestWeightedLeastSquares(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for cas, test := range []struct{ r, c int }{
+		{r: 1, c: 1},
+		{r: 5, c: 2},
+		{r: 10, c: 4},
+		{r: 20, c: 10},
+	} {
+		a := NewDense(test.r, test.c, nil)
+		for i := 0; i < test.r; i++ {
+			for j := 0; j < test.c; j++ {
+				a.Set(i, j, rnd.NormFloat64())
+			}
+		}
+		b := NewVecDense(test.r, nil)
+		weights := make([]float64, test.r)
+		w := NewSymDense(test.r, nil)
+		for i := range weights {
+			b.SetVec(i, rnd.NormFloat64())
+			weights[i] = rnd.Float64() + 0.1
+			w.SetSym(i, i, weights[i])
+		}
+
+		var beta VecDense
+		var cov SymDense
+		err := WeightedLeastSquares(&beta, &cov, a, b, weights)
+		if err != nil {
+			t.Fatalf("Case %d: unexpected error: %v", cas, err)
+		}
+		wantBeta, wantCov := normalEquations(a, b, w)
+		if !EqualApprox(&beta, wantBeta, 1e-10) {
+			t.Errorf("Case %d: unexpected coefficients:\ngot: %v\nwant:%v", cas, Formatted(&beta), Formatted(wantBeta))
+		}
+		if !EqualApprox(&cov, wantCov, 1e-10) {
+			t.Errorf("Case %d: unexpected covariance:\ngot: %v\nwant:%v", cas, Formatted(&cov), Formatted(wantCov))
+		}
+
+		// Unit weights give the ordinary least squares solution.
+		for i := range weights {
+			weights[i] = 1
+		}
+		err = WeightedLeastSquares(&beta, nil, a, b, weights)
+		if err != nil {
+			t.Fatalf("Case %d: unexpected error: %v", cas, err)
+		}
+		var ols VecDense
+		err = ols.SolveVec(a, b)
+		if err != nil {
+			t.Fatalf("Case %d: unexpected error: %v", cas, err)
+		}
+		if !EqualApprox(&beta, &ols, 1e-10) {
+			t.Errorf("Case %d: unexpected unit weight coefficients:\ngot: %v\nwant:%v", cas, Formatted(&beta), Formatted(&ols))
+		}
+	}
+
+	a := NewDense(3, 2, []float64{1, 0, 0, 1, 1, 1})
+	b := NewVecDense(3, nil)
+	for _, weights := range [][]float64{{1, 1}, {1, -1, 1}} {
+		if panicked, _ := panics(func() { WeightedLeastSquares(&VecDense{}, nil, a, b, weights) }); !panicked {
+			t.Errorf("expected panic for weights %v", weights)
+		}
+	}
+	if panicked, _ := panics(func() { WeightedLeastSquares(&VecDense{}, nil, a.T(), b, []float64{1, 1}) }); !panicked {
+		t.Error("expected panic for underdetermined system")
+	}
+}
+
+func TestGeneralizedLeastSquares(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for cas, test := range []struct{ r, c int }{
+		{r: 1, c: 1},
+		{r: 5, c: 2},
+		{r: 10, c: 4},
+		{r: 20, c: 10},
+	} {
+		a := NewDense(test.r, test.c, nil)
+		for i := 0; i < test.r; i++ {
+			for j := 0; j < test.c; j++ {
+				a.Set(i, j, rnd.NormFloat64())
+			}
+		}
+		b := NewVecDense(test.r, nil)
+		for i := 0; i < test.r; i++ {
+			b.SetVec(i, rnd.NormFloat64())
+		}
+
+		// Construct a random positive definite covariance.
+		f := NewDense(test.r, test.r, nil)
+		for i := 0; i < test.r; i++ {
+			for j := 0; j < test.r; j++ {
+				f.Set(i, j, rnd.NormFloat64())
+			}
+		}
+		sigma := NewSymDense(test.r, nil)
+		sigma.SymOuterK(1, f)
+		for i := 0; i < test.r; i++ {
+			sigma.SetSym(i, i, sigma.At(i, i)+1)
+		}
+
+		var beta VecDense
+		var cov SymDense
+		err := GeneralizedLeastSquares(&beta, &cov, a, b, sigma)
+		if err != nil {
+			t.Fatalf("Case %d: unexpected error: %v", cas, err)
+		}
+		var chol Cholesky
+		chol.Factorize(sigma)
+		var w SymDense
+		err = chol.InverseTo(&w)
+		if err != nil {
+			t.Fatalf("Case %d: unexpected error: %v", cas, err)
+		}
+		wantBeta, wantCov := normalEquations(a, b, &w)
+		if !EqualApprox(&beta, wantBeta, 1e-8) {
+			t.Errorf("Case %d: unexpected coefficients:\ngot: %v\nwant:%v", cas, Formatted(&beta), Formatted(wantBeta))
+		}
+		if !EqualApprox(&cov, wantCov, 1e-8) {
+			t.Errorf("Case %d: unexpected covariance:\ngot: %v\nwant:%v", cas, Formatted(&cov), Formatted(wantCov))
+		}
+
+		// A diagonal covariance is equivalent to weighting by
+		// the reciprocals of the variances.
+		diag := NewSymDense(test.r, nil)
+		weights := make([]float64, test.r)
+		for i := range weights {
+			diag.SetSym(i, i, sigma.At(i, i))
+			weights[i] = 1 / sigma.At(i, i)
+		}
+		err = GeneralizedLeastSquares(&beta, &cov, a, b, diag)
+		if err != nil {
+			t.Fatalf("Case %d: unexpected error: %v", cas, err)
+		}
+		var wlsBeta VecDense
+		var wlsCov SymDense
+		err = WeightedLeastSquares(&wlsBeta, &wlsCov, a, b, weights)
+		if err != nil {
+			t.Fatalf("Case %d: unexpected error: %v", cas, err)
+		}
+		if !EqualApprox(&beta, &wlsBeta, 1e-10) {
+			t.Errorf("Case %d: unexpected diagonal coefficients:\ngot: %v\nwant:%v", cas, Formatted(&beta), Formatted(&wlsBeta))
+		}
+		if !EqualApprox(&cov, &wlsCov, 1e-10) {
+			t.Errorf("Case %d: unexpected diagonal covariance:\ngot: %v\nwant:%v", cas, Formatted(&cov), Formatted(&wlsCov))
+		}
+	}
+
+	a := NewDense(2, 1, []float64{1, 1})
+	b := NewVecDense(2, nil)
+	notPD := NewSymDense(2, []float64{1, 2, 2, 1})
+	if err := GeneralizedLeastSquares(&VecDense{}, nil, a, b, notPD); err != ErrNotPSD {
+		t.Errorf("unexpected error for covariance that is not positive definite: got:%v want:%v", err, ErrNotPSD)
+	}
+	if panicked, _ := panics(func() { GeneralizedLeastSquares(&VecDense{}, nil, a, b, NewSymDense(3, nil)) }); !panicked {
+		t.Error("expected panic for covariance size mismatch")
+	}
+}
@@ -0,0 +1,7 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package matgen provides generators of structured and random matrices
+// with known properties for testing and benchmarking matrix algorithms.
+package matgen // import "gonum.org/v1/gonum/mat/matgen"
@@ -0,0 +1,80 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package matgen
+
+import (
+	"math"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// RandomOrthogonal returns an n×n random orthogonal matrix distributed
+// according to the Haar measure, the uniform distribution over the
+// orthogonal group. The matrix is computed from the QR factorization of a
+// matrix of independent standard normal elements with the signs of the
+// columns of Q chosen so that the diagonal of R is positive, which makes
+// the factorization unique and the distribution of Q uniform.
+//
+// If src is not nil it is used as the random source, otherwise
+// rand.NormFloat64 is used. RandomOrthogonal will panic if n is not
+// positive.
+func RandomOrthogonal(n int, src *rand.Rand) *mat.Dense {
+	if n <= 0 {
+		panic("matgen: bad orthogonal matrix size")
+	}
+	norm := rand.NormFloat64
+	if src != nil {
+		norm = src.NormFloat64
+	}
+	g := mat.NewDense(n, n, nil)
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			g.Set(i, j, norm())
+		}
+	}
+	var qr mat.QR
+	qr.Factorize(g)
+	q := qr.QTo(nil)
+	r := qr.RTo(nil)
+	q.Apply(func(_, j int, v float64) float64 {
+		if r.At(j, j) < 0 {
+			return -v
+		}
+		return v
+	}, q)
+	return q
+}
+
+// RandomSPD returns an n×n random symmetric positive definite matrix with
+// 2-norm condition number cond. The matrix is
+//  Q * D * Q^T,
+// where Q is a random orthogonal matrix returned by RandomOrthogonal and D is
+// diagonal with eigenvalues spaced geometrically from 1 down to 1/cond. When
+// n is 1 the single eigenvalue is 1 and cond is ignored.
+//
+// If src is not nil it is used as the random source, otherwise
+// rand.NormFloat64 is used. RandomSPD will panic if n is not positive or
+// cond is less than 1.
+func RandomSPD(n int, cond float64, src *rand.Rand) *mat.SymDense {
+	if n <= 0 {
+		panic("matgen: bad SPD matrix size")
+	}
+	if !(cond >= 1) || math.IsInf(cond, 1) {
+		panic("matgen: bad condition number")
+	}
+	q := RandomOrthogonal(n, src)
+	if n > 1 {
+		// Scale the columns of Q by the square roots of the
+		// eigenvalues so that Q * Q^T is Q * D * Q^T.
+		q.Apply(func(_, j int, v float64) float64 {
+			return v * math.Pow(cond, -0.5*float64(j)/float64(n-1))
+		}, q)
+	}
+	a := mat.NewSymDense(n, nil)
+	a.SymOuterK(1, q)
+	return a
+}
@@ -0,0 +1,105 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package matgen
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestRandomOrthogonal(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for _, n := range []int{1, 2, 5, 20} {
+		q := RandomOrthogonal(n, rnd)
+		var qtq mat.Dense
+		qtq.Mul(q.T(), q)
+		if !mat.EqualApprox(&qtq, eye(n), 1e-13) {
+			t.Errorf("matrix not orthogonal for n=%d:\n%v", n, mat.Formatted(&qtq))
+		}
+	}
+
+	// Under the Haar measure each element of an n×n orthogonal
+	// matrix has mean zero and variance 1/n.
+	const (
+		n      = 4
+		trials = 2000
+	)
+	var sum, sumSq float64
+	for i := 0; i < trials; i++ {
+		v := RandomOrthogonal(n, rnd).At(0, 0)
+		sum += v
+		sumSq += v * v
+	}
+	mean := sum / trials
+	variance := sumSq / trials
+	if math.Abs(mean) > 4/math.Sqrt(n*trials) {
+		t.Errorf("unexpected element mean: got:%v want:0", mean)
+	}
+	if math.Abs(variance-1.0/n) > 0.02 {
+		t.Errorf("unexpected element variance: got:%v want:%v", variance, 1.0/n)
+	}
+
+	if !panics(func() { RandomOrthogonal(0, nil) }) {
+		t.Error("expected panic for zero size")
+	}
+}
+
+func TestRandomSPD(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for _, test := range []struct {
+		n    int
+		cond float64
+	}{
+		{n: 1, cond: 1},
+		{n: 1, cond: 100},
+		{n: 2, cond: 10},
+		{n: 5, cond: 1},
+		{n: 10, cond: 1e3},
+		{n: 20, cond: 1e8},
+	} {
+		a := RandomSPD(test.n, test.cond, rnd)
+		var eig mat.EigenSym
+		if !eig.Factorize(a, false) {
+			t.Fatalf("eigendecomposition failed for n=%d cond=%v", test.n, test.cond)
+		}
+		values := eig.Values(nil)
+		if values[0] <= 0 {
+			t.Errorf("matrix not positive definite for n=%d cond=%v: smallest eigenvalue %v", test.n, test.cond, values[0])
+		}
+		want := test.cond
+		if test.n == 1 {
+			want = 1
+		}
+		got := values[len(values)-1] / values[0]
+		if !floats.EqualWithinRel(got, want, 1e-6) {
+			t.Errorf("unexpected condition number for n=%d: got:%v want:%v", test.n, got, want)
+		}
+		if !floats.EqualWithinAbsOrRel(values[len(values)-1], 1, 1e-12, 1e-12) {
+			t.Errorf("unexpected largest eigenvalue for n=%d cond=%v: got:%v want:1", test.n, test.cond, values[len(values)-1])
+		}
+	}
+
+	for _, cond := range []float64{0.5, math.NaN(), math.Inf(1)} {
+		if !panics(func() { RandomSPD(3, cond, nil) }) {
+			t.Errorf("expected panic for condition number %v", cond)
+		}
+	}
+	if !panics(func() { RandomSPD(0, 1, nil) }) {
+		t.Error("expected panic for zero size")
+	}
+}
+
+func eye(n int) *mat.Dense {
+	m := mat.NewDense(n, n, nil)
+	for i := 0; i < n; i++ {
+		m.Set(i, i, 1)
+	}
+	return m
+}
@@ -0,0 +1,88 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package matgen
+
+import "gonum.org/v1/gonum/mat"
+
+// Vandermonde returns the len(x)×c Vandermonde matrix of x with elements
+//  a_ij = x_i^j,
+// for j = 0, ..., c-1. Vandermonde matrices are the design matrices of
+// polynomial least squares problems and become severely ill-conditioned
+// as c grows. Vandermonde will panic if x is empty or c is not positive.
+func Vandermonde(x []float64, c int) *mat.Dense {
+	if len(x) == 0 || c <= 0 {
+		panic("matgen: bad Vandermonde size")
+	}
+	a := mat.NewDense(len(x), c, nil)
+	for i, v := range x {
+		p := 1.0
+		for j := 0; j < c; j++ {
+			a.Set(i, j, p)
+			p *= v
+		}
+	}
+	return a
+}
+
+// Hilbert returns the n×n Hilbert matrix with elements
+//  a_ij = 1 / (i + j + 1).
+// The Hilbert matrix is symmetric positive definite and its condition
+// number grows exponentially with n, reaching about 1.6e13 when n is 10.
+// Hilbert will panic if n is not positive.
+func Hilbert(n int) *mat.SymDense {
+	if n <= 0 {
+		panic("matgen: bad Hilbert size")
+	}
+	a := mat.NewSymDense(n, nil)
+	for i := 0; i < n; i++ {
+		for j := i; j < n; j++ {
+			a.SetSym(i, j, 1/float64(i+j+1))
+		}
+	}
+	return a
+}
+
+// Toeplitz returns the len(c)×len(r) Toeplitz matrix with first column c
+// and first row r, so that
+//  a_ij = c[i-j] if i >= j,
+//  a_ij = r[j-i] otherwise.
+// Toeplitz will panic if c or r is empty or if c[0] != r[0].
+func Toeplitz(c, r []float64) *mat.Dense {
+	if len(c) == 0 || len(r) == 0 {
+		panic("matgen: bad Toeplitz size")
+	}
+	if c[0] != r[0] {
+		panic("matgen: Toeplitz diagonal mismatch")
+	}
+	a := mat.NewDense(len(c), len(r), nil)
+	for i := range c {
+		for j := range r {
+			if i >= j {
+				a.Set(i, j, c[i-j])
+			} else {
+				a.Set(i, j, r[j-i])
+			}
+		}
+	}
+	return a
+}
+
+// SymToeplitz returns the len(v)×len(v) symmetric Toeplitz matrix with
+// first row v, so that
+//  a_ij = v[|i-j|].
+// SymToeplitz will panic if v is empty.
+func SymToeplitz(v []float64) *mat.SymDense {
+	if len(v) == 0 {
+		panic("matgen: bad Toeplitz size")
+	}
+	n := len(v)
+	a := mat.NewSymDense(n, nil)
+	for i := 0; i < n; i++ {
+		for j := i; j < n; j++ {
+			a.SetSym(i, j, v[j-i])
+		}
+	}
+	return a
+}
@@ -0,0 +1,105 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package matgen
+
+import (
+	"math"
+	"testing"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+func panics(fn func()) (panicked bool) {
+	defer func() {
+		panicked = recover() != nil
+	}()
+	fn()
+	return
+}
+
+func TestVandermonde(t *testing.T) {
+	got := Vandermonde([]float64{1, 2, -3}, 4)
+	want := mat.NewDense(3, 4, []float64{
+		1, 1, 1, 1,
+		1, 2, 4, 8,
+		1, -3, 9, -27,
+	})
+	if !mat.Equal(got, want) {
+		t.Errorf("unexpected Vandermonde matrix:\ngot: %v\nwant:%v", mat.Formatted(got), mat.Formatted(want))
+	}
+	if !panics(func() { Vandermonde(nil, 2) }) {
+		t.Error("expected panic for empty x")
+	}
+	if !panics(func() { Vandermonde([]float64{1}, 0) }) {
+		t.Error("expected panic for zero columns")
+	}
+}
+
+func TestHilbert(t *testing.T) {
+	got := Hilbert(3)
+	want := mat.NewSymDense(3, []float64{
+		1, 1.0 / 2, 1.0 / 3,
+		1.0 / 2, 1.0 / 3, 1.0 / 4,
+		1.0 / 3, 1.0 / 4, 1.0 / 5,
+	})
+	if !mat.Equal(got, want) {
+		t.Errorf("unexpected Hilbert matrix:\ngot: %v\nwant:%v", mat.Formatted(got), mat.Formatted(want))
+	}
+
+	// The inverse of the Hilbert matrix has integer elements.
+	var inv mat.Dense
+	err := inv.Inverse(got)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	wantInv := mat.NewDense(3, 3, []float64{
+		9, -36, 30,
+		-36, 192, -180,
+		30, -180, 180,
+	})
+	if !mat.EqualApprox(&inv, wantInv, 1e-10) {
+		t.Errorf("unexpected Hilbert inverse:\ngot: %v\nwant:%v", mat.Formatted(&inv), mat.Formatted(wantInv))
+	}
+
+	// The condition number grows exponentially.
+	prev := 1.0
+	for n := 2; n <= 8; n++ {
+		c := mat.Cond(Hilbert(n), 2)
+		if c < 10*prev {
+			t.Errorf("unexpected condition number growth for n=%d: got:%v previous:%v", n, c, prev)
+		}
+		prev = c
+	}
+	if !panics(func() { Hilbert(0) }) {
+		t.Error("expected panic for zero size")
+	}
+}
+
+func TestToeplitz(t *testing.T) {
+	got := Toeplitz([]float64{1, 2, 3}, []float64{1, 4, 5, 6})
+	want := mat.NewDense(3, 4, []float64{
+		1, 4, 5, 6,
+		2, 1, 4, 5,
+		3, 2, 1, 4,
+	})
+	if !mat.Equal(got, want) {
+		t.Errorf("unexpected Toeplitz matrix:\ngot: %v\nwant:%v", mat.Formatted(got), mat.Formatted(want))
+	}
+	if !panics(func() { Toeplitz([]float64{1, 2}, []float64{2, 1}) }) {
+		t.Error("expected panic for diagonal mismatch")
+	}
+	if !panics(func() { Toeplitz(nil, []float64{1}) }) {
+		t.Error("expected panic for empty column")
+	}
+
+	v := []float64{4, -1, 0.5, math.Pi}
+	sym := SymToeplitz(v)
+	if !mat.Equal(sym, Toeplitz(v, v)) {
+		t.Errorf("symmetric Toeplitz matrix does not match Toeplitz matrix:\ngot: %v\nwant:%v", mat.Formatted(sym), mat.Formatted(Toeplitz(v, v)))
+	}
+	if !panics(func() { SymToeplitz(nil) }) {
+		t.Error("expected panic for empty vector")
+	}
+}
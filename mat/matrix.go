@@ -491,6 +491,127 @@ func EqualApprox(a, b Matrix, epsilon float64) bool {
 	return true
 }
 
+// Discrepancy describes the greatest element-wise difference
+// between two matrices.
+type Discrepancy struct {
+	// Row and Col are the indices of the element with the greatest
+	// difference. They are -1 if the matrices have different sizes
+	// or no elements.
+	Row, Col int
+
+	// A and B are the values of the element in each matrix.
+	A, B float64
+
+	// Abs and Rel are the absolute and relative differences
+	// between A and B. The relative difference is the absolute
+	// difference divided by the greater magnitude of A and B.
+	Abs, Rel float64
+}
+
+// EqualApproxDiscrepancy returns whether the matrices a and b have the same size
+// and contain all equal elements with tolerance for element-wise equality
+// specified by epsilon, as for EqualApprox, and the element that differs the
+// most. Elements are compared by the lesser of their absolute and relative
+// differences, so the returned element is the one that most exceeds epsilon
+// when a and b are not approximately equal. A NaN element is treated as
+// differing by an infinite amount.
+func EqualApproxDiscrepancy(a, b Matrix, epsilon float64) (bool, Discrepancy) {
+	d := Discrepancy{Row: -1, Col: -1}
+	ar, ac := a.Dims()
+	br, bc := b.Dims()
+	if ar != br || ac != bc {
+		return false, d
+	}
+	equal := true
+	worst := math.Inf(-1)
+	for i := 0; i < ar; i++ {
+		for j := 0; j < ac; j++ {
+			av := a.At(i, j)
+			bv := b.At(i, j)
+			equal = equal && floats.EqualWithinAbsOrRel(av, bv, epsilon, epsilon)
+			abs := math.Abs(av - bv)
+			rel := abs / math.Max(math.Abs(av), math.Abs(bv))
+			if av == bv {
+				// Handle equal infinities and zeros.
+				abs, rel = 0, 0
+			}
+			diff := math.Min(abs, rel)
+			if math.IsNaN(abs) {
+				diff = math.Inf(1)
+			}
+			if diff > worst {
+				worst = diff
+				d = Discrepancy{Row: i, Col: j, A: av, B: bv, Abs: abs, Rel: rel}
+			}
+		}
+	}
+	return equal, d
+}
+
+// NormDiff returns the specified norm of the difference a - b without
+// allocating. The valid norms are the same as for Norm.
+//
+// NormDiff will panic with ErrNormOrder if an illegal norm order is specified
+// and with ErrShape if the matrices have different sizes or zero size.
+func NormDiff(a, b Matrix, norm float64) float64 {
+	r, c := a.Dims()
+	br, bc := b.Dims()
+	if r != br || c != bc || r == 0 || c == 0 {
+		panic(ErrShape)
+	}
+	switch norm {
+	default:
+		panic(ErrNormOrder)
+	case 1:
+		var max float64
+		for j := 0; j < c; j++ {
+			var sum float64
+			for i := 0; i < r; i++ {
+				sum += math.Abs(a.At(i, j) - b.At(i, j))
+			}
+			if sum > max || math.IsNaN(sum) {
+				max = sum
+			}
+		}
+		return max
+	case 2:
+		// Accumulate the scaled sum of squares
+		// to avoid overflow and underflow.
+		scale, ssq := 0.0, 1.0
+		for i := 0; i < r; i++ {
+			for j := 0; j < c; j++ {
+				v := math.Abs(a.At(i, j) - b.At(i, j))
+				switch {
+				case math.IsNaN(v):
+					return v
+				case v == 0:
+				case scale < v:
+					ssq = 1 + ssq*(scale/v)*(scale/v)
+					scale = v
+				default:
+					ssq += (v / scale) * (v / scale)
+				}
+			}
+		}
+		if math.IsInf(scale, 1) {
+			return scale
+		}
+		return scale * math.Sqrt(ssq)
+	case math.Inf(1):
+		var max float64
+		for i := 0; i < r; i++ {
+			var sum float64
+			for j := 0; j < c; j++ {
+				sum += math.Abs(a.At(i, j) - b.At(i, j))
+			}
+			if sum > max || math.IsNaN(sum) {
+				max = sum
+			}
+		}
+		return max
+	}
+}
+
 // LogDet returns the log of the determinant and the sign of the determinant
 // for the matrix that has been factorized. Numerical stability in product and
 // division expressions is generally improved by working in log space.
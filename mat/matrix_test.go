@@ -10,6 +10,8 @@ import (
 	"reflect"
 	"testing"
 
+	"golang.org/x/exp/rand"
+
 	"gonum.org/v1/gonum/blas"
 	"gonum.org/v1/gonum/blas/blas64"
 	"gonum.org/v1/gonum/floats"
@@ -528,6 +530,161 @@ func TestNormZero(t *testing.T) {
 	}
 }
 
+func TestEqualApproxDiscrepancy(t *testing.T) {
+	for cas, test := range []struct {
+		a, b    Matrix
+		epsilon float64
+		equal   bool
+		want    Discrepancy
+	}{
+		{
+			a:       NewDense(2, 2, []float64{1, 2, 3, 4}),
+			b:       NewDense(2, 2, []float64{1, 2, 3, 4}),
+			epsilon: 1e-14,
+			equal:   true,
+			want:    Discrepancy{Row: 0, Col: 0, A: 1, B: 1},
+		},
+		{
+			a:       NewDense(2, 3, []float64{1, 2, 3, 4, 5, 6}),
+			b:       NewDense(2, 3, []float64{1, 2.1, 3, 4, 5, 6.5}),
+			epsilon: 1e-14,
+			equal:   false,
+			want:    Discrepancy{Row: 1, Col: 2, A: 6, B: 6.5, Abs: 0.5, Rel: 0.5 / 6.5},
+		},
+		{
+			a:       NewDense(2, 2, []float64{1, 2, 3, 4}),
+			b:       NewDense(2, 2, []float64{1, 2 + 1e-10, 3, 4}),
+			epsilon: 1e-8,
+			equal:   true,
+			want:    Discrepancy{Row: 0, Col: 1, A: 2, B: 2 + 1e-10, Abs: 1e-10, Rel: 1e-10 / (2 + 1e-10)},
+		},
+		{
+			// Large elements are compared by relative difference.
+			a:       NewDense(1, 2, []float64{1e10, 1}),
+			b:       NewDense(1, 2, []float64{1e10 + 1, 1.5}),
+			epsilon: 1e-4,
+			equal:   false,
+			want:    Discrepancy{Row: 0, Col: 1, A: 1, B: 1.5, Abs: 0.5, Rel: 0.5 / 1.5},
+		},
+		{
+			a:       NewDense(2, 2, []float64{1, 2, math.Inf(1), 4}),
+			b:       NewDense(2, 2, []float64{1, 2, math.Inf(1), 4.5}),
+			epsilon: 1e-14,
+			equal:   false,
+			want:    Discrepancy{Row: 1, Col: 1, A: 4, B: 4.5, Abs: 0.5, Rel: 0.5 / 4.5},
+		},
+		{
+			a:       NewDense(1, 3, []float64{1, math.NaN(), 3}),
+			b:       NewDense(1, 3, []float64{10, 2, 3}),
+			epsilon: 1e-14,
+			equal:   false,
+			want:    Discrepancy{Row: 0, Col: 1, A: math.NaN(), B: 2, Abs: math.NaN(), Rel: math.NaN()},
+		},
+		{
+			a:       NewDense(2, 2, []float64{1, 2, 3, 4}),
+			b:       NewDense(2, 2, []float64{1, 3, 2, 4}).T(),
+			epsilon: 1e-14,
+			equal:   true,
+			want:    Discrepancy{Row: 0, Col: 0, A: 1, B: 1},
+		},
+		{
+			a:       NewDense(2, 2, []float64{1, 2, 3, 4}),
+			b:       NewDense(2, 1, []float64{1, 3}),
+			epsilon: 1e-14,
+			equal:   false,
+			want:    Discrepancy{Row: -1, Col: -1},
+		},
+		{
+			a:       &Dense{},
+			b:       &Dense{},
+			epsilon: 1e-14,
+			equal:   true,
+			want:    Discrepancy{Row: -1, Col: -1},
+		},
+	} {
+		equal, d := EqualApproxDiscrepancy(test.a, test.b, test.epsilon)
+		if equal != test.equal {
+			t.Errorf("Case %d: unexpected equality: got:%t want:%t", cas, equal, test.equal)
+		}
+		if want := EqualApprox(test.a, test.b, test.epsilon); equal != want {
+			t.Errorf("Case %d: result does not match EqualApprox: got:%t want:%t", cas, equal, want)
+		}
+		if !sameDiscrepancy(d, test.want) {
+			t.Errorf("Case %d: unexpected discrepancy:\ngot: %+v\nwant:%+v", cas, d, test.want)
+		}
+	}
+}
+
+func sameDiscrepancy(a, b Discrepancy) bool {
+	same := func(x, y float64) bool {
+		return (math.IsNaN(x) && math.IsNaN(y)) || floats.EqualWithinAbsOrRel(x, y, 1e-15, 1e-15)
+	}
+	return a.Row == b.Row && a.Col == b.Col &&
+		same(a.A, b.A) && same(a.B, b.B) &&
+		same(a.Abs, b.Abs) && same(a.Rel, b.Rel)
+}
+
+func TestNormDiff(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for _, test := range []struct{ r, c int }{
+		{r: 1, c: 1},
+		{r: 3, c: 5},
+		{r: 10, c: 10},
+		{r: 7, c: 2},
+	} {
+		a := NewDense(test.r, test.c, nil)
+		b := NewDense(test.r, test.c, nil)
+		for i := 0; i < test.r; i++ {
+			for j := 0; j < test.c; j++ {
+				a.Set(i, j, rnd.NormFloat64())
+				b.Set(i, j, rnd.NormFloat64())
+			}
+		}
+		var d Dense
+		d.Sub(a, b)
+		for _, norm := range []float64{1, 2, math.Inf(1)} {
+			got := NormDiff(a, b, norm)
+			want := Norm(&d, norm)
+			if !floats.EqualWithinAbsOrRel(got, want, 1e-14, 1e-14) {
+				t.Errorf("unexpected norm for %d×%d matrices with norm %v: got:%v want:%v", test.r, test.c, norm, got, want)
+			}
+			if got := NormDiff(a, a, norm); got != 0 {
+				t.Errorf("unexpected norm for identical %d×%d matrices with norm %v: got:%v want:0", test.r, test.c, norm, got)
+			}
+			allocs := testing.AllocsPerRun(10, func() { NormDiff(a, b, norm) })
+			if allocs != 0 {
+				t.Errorf("unexpected allocations for %d×%d matrices with norm %v: %v", test.r, test.c, norm, allocs)
+			}
+		}
+	}
+
+	// Scaling avoids overflow of the Frobenius norm.
+	a := NewDense(1, 2, []float64{1e300, 1e300})
+	b := NewDense(1, 2, []float64{-1e300, -1e300})
+	if got, want := NormDiff(a, b, 2), 2*math.Sqrt2*1e300; !floats.EqualWithinRel(got, want, 1e-14) {
+		t.Errorf("unexpected norm for large elements: got:%v want:%v", got, want)
+	}
+
+	a = NewDense(2, 2, nil)
+	for _, test := range []struct {
+		name string
+		fn   func()
+		want string
+	}{
+		{name: "shape", fn: func() { NormDiff(a, NewDense(2, 3, nil), 1) }, want: ErrShape.Error()},
+		{name: "zero size", fn: func() { NormDiff(&Dense{}, &Dense{}, 1) }, want: ErrShape.Error()},
+		{name: "norm", fn: func() { NormDiff(a, a, 3) }, want: ErrNormOrder.Error()},
+	} {
+		panicked, message := panics(test.fn)
+		if !panicked {
+			t.Errorf("expected panic for bad %s", test.name)
+		}
+		if message != test.want {
+			t.Errorf("unexpected panic string for bad %s: got:%s want:%s", test.name, message, test.want)
+		}
+	}
+}
+
 func TestSum(t *testing.T) {
 	f := func(a Matrix) interface{} {
 		return Sum(a)
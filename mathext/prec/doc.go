@@ -0,0 +1,15 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package prec provides extended precision implementations of numerical
+// kernels that are sensitive to rounding error.
+//
+// The routines are considerably slower than their float64 counterparts
+// and are intended as a fallback to be selected when a float64 result
+// fails an accuracy check, for example when the residual of a linear
+// solve computed with Residual is too large, or when a sum suffers from
+// catastrophic cancellation. Intermediate values are held as big.Float
+// values with a caller-specified precision in bits, and results are
+// rounded to float64 on return.
+package prec // import "gonum.org/v1/gonum/mathext/prec"
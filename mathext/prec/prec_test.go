@@ -0,0 +1,158 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package prec
+
+import (
+	"math"
+	"math/cmplx"
+	"sort"
+	"testing"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestSum(t *testing.T) {
+	for i, test := range []struct {
+		s    []float64
+		want float64
+	}{
+		{s: nil, want: 0},
+		{s: []float64{1, 2, 3}, want: 6},
+		{s: []float64{1e100, 1, -1e100}, want: 1},
+		{s: []float64{1, 1e-16, 1e-16, -1}, want: 2e-16},
+		{s: []float64{math.MaxFloat64, math.MaxFloat64, -math.MaxFloat64}, want: math.MaxFloat64},
+		{s: []float64{1, math.Inf(1)}, want: math.Inf(1)},
+	} {
+		if got := Sum(test.s); got != test.want {
+			t.Errorf("unexpected sum for test %d: got:%v want:%v", i, got, test.want)
+		}
+	}
+	if got := Sum([]float64{math.Inf(1), math.Inf(-1)}); !math.IsNaN(got) {
+		t.Errorf("unexpected sum of opposite infinities: got:%v want:NaN", got)
+	}
+}
+
+func TestDot(t *testing.T) {
+	x := []float64{1e100, 1, -1e100, 3}
+	y := []float64{1, 1, 1, 1e-20}
+	if got, want := Dot(x, y), 1+3e-20; got != want {
+		t.Errorf("unexpected dot product: got:%v want:%v", got, want)
+	}
+}
+
+func TestSolve(t *testing.T) {
+	// The Hilbert matrix is notoriously ill-conditioned.
+	for _, n := range []int{2, 5, 10, 12} {
+		a := mat.NewDense(n, n, nil)
+		for i := 0; i < n; i++ {
+			for j := 0; j < n; j++ {
+				a.Set(i, j, 1/float64(i+j+1))
+			}
+		}
+		want := make([]float64, n)
+		for i := range want {
+			want[i] = 1
+		}
+		b := mat.NewVecDense(n, nil)
+		for i := 0; i < n; i++ {
+			b.SetVec(i, Dot(a.RawRowView(i), want))
+		}
+
+		x, err := Solve(a, b, 0)
+		if err != nil {
+			t.Fatalf("unexpected error for n=%d: %v", n, err)
+		}
+		if r := Residual(a, x, b); r > 1e-15 {
+			t.Errorf("unexpected residual for n=%d: got:%v", n, r)
+		}
+	}
+
+	sing := mat.NewDense(2, 2, []float64{1, 2, 2, 4})
+	if _, err := Solve(sing, mat.NewVecDense(2, []float64{1, 1}), 0); err != mat.ErrSingular {
+		t.Errorf("unexpected error for singular matrix: got:%v want:%v", err, mat.ErrSingular)
+	}
+}
+
+func TestRoots(t *testing.T) {
+	const tol = 1e-12
+	for _, test := range []struct {
+		name string
+		c    []float64
+		want []complex128
+	}{
+		{name: "constant", c: []float64{3}},
+		{name: "linear", c: []float64{-2, 1}, want: []complex128{2}},
+		{name: "quadratic", c: []float64{1, 0, 1}, want: []complex128{-1i, 1i}},
+		{name: "leading zeros", c: []float64{-6, 1, 1, 0, 0}, want: []complex128{-3, 2}},
+		{name: "double root", c: []float64{1, -2, 1}, want: []complex128{1, 1}},
+		{name: "triple root", c: []float64{-1, 3, -3, 1}, want: []complex128{1, 1, 1}},
+		{name: "zero root", c: []float64{0, 0, 1}, want: []complex128{0, 0}},
+	} {
+		got, err := Roots(test.c, 0)
+		if err != nil {
+			t.Errorf("unexpected error for %s: %v", test.name, err)
+			continue
+		}
+		if !rootsEqual(got, test.want, tol) {
+			t.Errorf("unexpected roots for %s: got:%v want:%v", test.name, got, test.want)
+		}
+	}
+
+	// Wilkinson's polynomial (x-1)(x-2)...(x-20) has roots that are
+	// extremely sensitive to perturbation of the float64 coefficients.
+	// The roots of the coefficients as represented are found accurately,
+	// which we check by their agreement across working precisions.
+	c := []float64{1}
+	for k := 1; k <= 20; k++ {
+		next := make([]float64, len(c)+1)
+		for i, v := range c {
+			next[i+1] += v
+			next[i] -= float64(k) * v
+		}
+		c = next
+	}
+	lo, err := Roots(c, 256)
+	if err != nil {
+		t.Fatalf("unexpected error for Wilkinson polynomial: %v", err)
+	}
+	hi, err := Roots(c, 512)
+	if err != nil {
+		t.Fatalf("unexpected error for Wilkinson polynomial: %v", err)
+	}
+	if !rootsEqual(lo, hi, 1e-14) {
+		t.Errorf("roots of Wilkinson polynomial depend on precision:\n%v\n%v", lo, hi)
+	}
+	re := make([]float64, len(lo))
+	for i, r := range lo {
+		re[i] = real(r)
+	}
+	sort.Float64s(re)
+	for i := 0; i < 6; i++ {
+		if !floats.EqualWithinAbsOrRel(re[i], float64(i+1), 1e-6, 1e-6) {
+			t.Errorf("unexpected small root of Wilkinson polynomial: got:%v want:%d", re[i], i+1)
+		}
+	}
+}
+
+// rootsEqual returns whether a and b hold the same roots
+// within tol, in any order.
+func rootsEqual(a, b []complex128, tol float64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	used := make([]bool, len(b))
+outer:
+	for _, x := range a {
+		for j, y := range b {
+			if !used[j] && cmplx.Abs(x-y) <= tol*math.Max(1, cmplx.Abs(y)) {
+				used[j] = true
+				continue outer
+			}
+		}
+		return false
+	}
+	return true
+}
@@ -0,0 +1,215 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package prec
+
+import (
+	"errors"
+	"math"
+	"math/big"
+	"math/cmplx"
+)
+
+// ErrNoConvergence is returned by Roots when the iteration fails
+// to converge.
+var ErrNoConvergence = errors.New("prec: root iteration did not converge")
+
+// perturbation is the relative size of the displacement applied to a root
+// estimate that coincides with another estimate or a stationary point.
+const perturbation = 1.0 / (1 << 20)
+
+// Roots returns the complex roots of the polynomial
+//  c[0] + c[1]*x + c[2]*x^2 + ... + c[n]*x^n
+// computed with the Aberth-Ehrlich method using prec bits of precision.
+// If prec is zero, DefaultPrec is used. Leading zero coefficients are
+// ignored, and roots are returned with their multiplicity. Roots will
+// panic if all the coefficients are zero or any is not finite.
+//
+// Roots is intended for polynomials whose roots are ill-conditioned,
+// such as those with clustered or multiple roots, where float64
+// evaluation of the polynomial is too inaccurate to locate them.
+func Roots(c []float64, prec uint) ([]complex128, error) {
+	for _, v := range c {
+		if !isFinite(v) {
+			panic("prec: non-finite coefficient")
+		}
+	}
+	n := len(c) - 1
+	for n >= 0 && c[n] == 0 {
+		n--
+	}
+	if n < 0 {
+		panic("prec: zero polynomial")
+	}
+	if n == 0 {
+		return nil, nil
+	}
+	if prec == 0 {
+		prec = DefaultPrec
+	}
+
+	coef := make([]*big.Float, n+1)
+	for i := range coef {
+		coef[i] = newFloat(c[i], prec)
+	}
+
+	// Place the initial estimates on a circle enclosing all
+	// the roots, with an offset to break symmetry.
+	var bound float64
+	for _, v := range c[:n] {
+		bound = math.Max(bound, math.Abs(v/c[n]))
+	}
+	bound++
+	z := make([]bigComplex, n)
+	for k := range z {
+		z[k] = newComplex(cmplx.Rect(bound, 2*math.Pi*float64(k)/float64(n)+0.4), prec)
+	}
+
+	// The iteration stops when all corrections are small relative
+	// to the precision of the returned float64 values, or of the
+	// working precision if that is lower.
+	tolBits := prec / 2
+	if tolBits > 64 {
+		tolBits = 64
+	}
+	tol := math.Ldexp(1, -int(tolBits))
+
+	maxIter := 100 + 4*int(prec)
+	done := make([]bool, n)
+	w := newComplex(0, prec)
+	for iter := 0; iter < maxIter; iter++ {
+		converged := true
+		for k := range z {
+			if done[k] {
+				continue
+			}
+			p, dp := eval(coef, z[k], prec)
+			if p.isZero() {
+				done[k] = true
+				continue
+			}
+			// w = p / (p' - p * Σ_{j≠k} 1/(z_k - z_j)).
+			sum := newComplex(0, prec)
+			d := newComplex(0, prec)
+			one := newComplex(1, prec)
+			ok := true
+			for j := range z {
+				if j == k {
+					continue
+				}
+				d.sub(z[k], z[j])
+				if d.isZero() {
+					ok = false
+					break
+				}
+				d.quo(one, d)
+				sum.add(sum, d)
+			}
+			if ok {
+				sum.mul(p, sum)
+				sum.sub(dp, sum)
+				ok = !sum.isZero()
+			}
+			if !ok {
+				// Perturb the estimate away from a coincident
+				// estimate or a stationary point.
+				z[k].mul(z[k], newComplex(complex(1+perturbation, perturbation), prec))
+				z[k].add(z[k], newComplex(perturbation, prec))
+				converged = false
+				continue
+			}
+			w.quo(p, sum)
+			z[k].sub(z[k], w)
+
+			wa := w.complex128()
+			za := z[k].complex128()
+			if cmplx.Abs(wa) <= tol*math.Max(1, cmplx.Abs(za)) {
+				done[k] = true
+			} else {
+				converged = false
+			}
+		}
+		if converged {
+			roots := make([]complex128, n)
+			for k, v := range z {
+				roots[k] = v.complex128()
+			}
+			return roots, nil
+		}
+	}
+	return nil, ErrNoConvergence
+}
+
+// eval returns the value of the polynomial with the given coefficients
+// and its derivative at z, using Horner's method.
+func eval(coef []*big.Float, z bigComplex, prec uint) (p, dp bigComplex) {
+	n := len(coef) - 1
+	p = newComplex(0, prec)
+	p.re.Set(coef[n])
+	dp = newComplex(0, prec)
+	for i := n - 1; i >= 0; i-- {
+		dp.mul(dp, z)
+		dp.add(dp, p)
+		p.mul(p, z)
+		p.re.Add(p.re, coef[i])
+	}
+	return p, dp
+}
+
+// bigComplex is an extended precision complex number.
+type bigComplex struct {
+	re, im *big.Float
+}
+
+func newComplex(v complex128, prec uint) bigComplex {
+	return bigComplex{re: newFloat(real(v), prec), im: newFloat(imag(v), prec)}
+}
+
+func (z bigComplex) isZero() bool {
+	return z.re.Sign() == 0 && z.im.Sign() == 0
+}
+
+func (z bigComplex) complex128() complex128 {
+	re, _ := z.re.Float64()
+	im, _ := z.im.Float64()
+	return complex(re, im)
+}
+
+// add sets z to x+y. The receiver may alias the arguments.
+func (z bigComplex) add(x, y bigComplex) {
+	z.re.Add(x.re, y.re)
+	z.im.Add(x.im, y.im)
+}
+
+// sub sets z to x-y. The receiver may alias the arguments.
+func (z bigComplex) sub(x, y bigComplex) {
+	z.re.Sub(x.re, y.re)
+	z.im.Sub(x.im, y.im)
+}
+
+// mul sets z to x*y. The receiver may alias the arguments.
+func (z bigComplex) mul(x, y bigComplex) {
+	prec := z.re.Prec()
+	var ac, bd, ad, bc big.Float
+	ac.SetPrec(prec).Mul(x.re, y.re)
+	bd.SetPrec(prec).Mul(x.im, y.im)
+	ad.SetPrec(prec).Mul(x.re, y.im)
+	bc.SetPrec(prec).Mul(x.im, y.re)
+	z.re.Sub(&ac, &bd)
+	z.im.Add(&ad, &bc)
+}
+
+// quo sets z to x/y. The receiver may alias the arguments.
+func (z bigComplex) quo(x, y bigComplex) {
+	prec := z.re.Prec()
+	var den, t, re, im big.Float
+	den.SetPrec(prec).Mul(y.re, y.re)
+	den.Add(&den, t.SetPrec(prec).Mul(y.im, y.im))
+	re.SetPrec(prec).Mul(x.re, y.re)
+	re.Add(&re, t.Mul(x.im, y.im))
+	im.SetPrec(prec).Mul(x.im, y.re)
+	im.Sub(&im, t.Mul(x.re, y.im))
+	z.re.Quo(&re, &den)
+	z.im.Quo(&im, &den)
+}
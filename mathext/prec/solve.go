@@ -0,0 +1,117 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package prec
+
+import (
+	"math"
+	"math/big"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// Solve returns the solution x of the square linear system A * x = b,
+// computed by Gaussian elimination with partial pivoting using prec bits
+// of precision. If prec is zero, DefaultPrec is used. Solve returns
+// mat.ErrSingular if A is singular at the working precision.
+//
+// Solve is intended for small ill-conditioned systems where the float64
+// solution has an unacceptable residual. Its time complexity is O(n^3)
+// extended precision operations. Solve will panic if A is not square or
+// the length of b does not match the dimensions of A.
+func Solve(a mat.Matrix, b mat.Vector, prec uint) (*mat.VecDense, error) {
+	n, c := a.Dims()
+	if n != c {
+		panic(mat.ErrSquare)
+	}
+	if b.Len() != n {
+		panic(mat.ErrShape)
+	}
+	if prec == 0 {
+		prec = DefaultPrec
+	}
+
+	// Form the augmented matrix [A|b].
+	m := make([][]*big.Float, n)
+	for i := range m {
+		m[i] = make([]*big.Float, n+1)
+		for j := 0; j < n; j++ {
+			m[i][j] = newFloat(a.At(i, j), prec)
+		}
+		m[i][n] = newFloat(b.AtVec(i), prec)
+	}
+
+	var t, abs, max big.Float
+	t.SetPrec(prec)
+	for k := 0; k < n; k++ {
+		p := k
+		max.Abs(m[k][k])
+		for i := k + 1; i < n; i++ {
+			if abs.Abs(m[i][k]).Cmp(&max) > 0 {
+				p = i
+				max.Set(&abs)
+			}
+		}
+		if max.Sign() == 0 {
+			return nil, mat.ErrSingular
+		}
+		m[k], m[p] = m[p], m[k]
+		for i := k + 1; i < n; i++ {
+			if m[i][k].Sign() == 0 {
+				continue
+			}
+			f := new(big.Float).SetPrec(prec).Quo(m[i][k], m[k][k])
+			for j := k + 1; j <= n; j++ {
+				t.Mul(f, m[k][j])
+				m[i][j].Sub(m[i][j], &t)
+			}
+			m[i][k].SetInt64(0)
+		}
+	}
+
+	// Back substitution.
+	x := make([]*big.Float, n)
+	for i := n - 1; i >= 0; i-- {
+		s := new(big.Float).SetPrec(prec).Set(m[i][n])
+		for j := i + 1; j < n; j++ {
+			t.Mul(m[i][j], x[j])
+			s.Sub(s, &t)
+		}
+		x[i] = s.Quo(s, m[i][i])
+	}
+	data := make([]float64, n)
+	for i, v := range x {
+		data[i], _ = v.Float64()
+	}
+	return mat.NewVecDense(n, data), nil
+}
+
+// Residual returns the infinity norm of b - A * x, with each element
+// computed in exact arithmetic and correctly rounded to float64. Residual
+// will panic if the dimensions of A, x and b do not match.
+func Residual(a mat.Matrix, x, b mat.Vector) float64 {
+	r, c := a.Dims()
+	if x.Len() != c || b.Len() != r {
+		panic(mat.ErrShape)
+	}
+	row := make([]float64, c+1)
+	col := make([]float64, c+1)
+	for j := 0; j < c; j++ {
+		col[j] = x.AtVec(j)
+	}
+	col[c] = -1
+	var norm float64
+	for i := 0; i < r; i++ {
+		for j := 0; j < c; j++ {
+			row[j] = a.At(i, j)
+		}
+		row[c] = b.AtVec(i)
+		norm = math.Max(norm, math.Abs(Dot(row, col)))
+	}
+	return norm
+}
+
+func newFloat(v float64, prec uint) *big.Float {
+	return new(big.Float).SetPrec(prec).SetFloat64(v)
+}
@@ -0,0 +1,76 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package prec
+
+import (
+	"math"
+	"math/big"
+)
+
+// DefaultPrec is the precision in bits used when a precision
+// of zero is passed to a routine.
+const DefaultPrec = 256
+
+// exactPrec is a precision sufficient to hold the exact sum of
+// up to 2^64 float64 values or 2^64 products of float64 values.
+const exactPrec = 2*(1074+1024) + 64
+
+// Sum returns the sum of the elements of s, correctly rounded to float64.
+// If s contains a NaN or infinite element, Sum returns the float64 sum
+// of the elements.
+func Sum(s []float64) float64 {
+	acc := new(big.Float).SetPrec(exactPrec)
+	var x big.Float
+	for _, v := range s {
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return naiveSum(s)
+		}
+		acc.Add(acc, x.SetFloat64(v))
+	}
+	f, _ := acc.Float64()
+	return f
+}
+
+// Dot returns the dot product of x and y, correctly rounded to float64.
+// If x or y contains a NaN or infinite element, Dot returns the float64
+// dot product. Dot will panic if the lengths of x and y differ.
+func Dot(x, y []float64) float64 {
+	if len(x) != len(y) {
+		panic("prec: slice length mismatch")
+	}
+	acc := new(big.Float).SetPrec(exactPrec)
+	var a, b big.Float
+	a.SetPrec(exactPrec)
+	for i, v := range x {
+		if !isFinite(v) || !isFinite(y[i]) {
+			return naiveDot(x, y)
+		}
+		a.SetFloat64(v)
+		a.Mul(&a, b.SetFloat64(y[i]))
+		acc.Add(acc, &a)
+	}
+	f, _ := acc.Float64()
+	return f
+}
+
+func isFinite(v float64) bool {
+	return !math.IsNaN(v) && !math.IsInf(v, 0)
+}
+
+func naiveSum(s []float64) float64 {
+	var sum float64
+	for _, v := range s {
+		sum += v
+	}
+	return sum
+}
+
+func naiveDot(x, y []float64) float64 {
+	var sum float64
+	for i, v := range x {
+		sum += v * y[i]
+	}
+	return sum
+}
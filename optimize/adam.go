@@ -0,0 +1,128 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package optimize
+
+import "math"
+
+// Adam implements the Adam method for first-order optimization of objective
+// functions with noisy gradients, such as the stochastic gradients of
+// simulation-based or subsampled objectives. Adam keeps exponential moving
+// averages of the gradient and its elementwise square and takes steps
+//
+//  x_{k+1} = x_k - a_k m̂_k / (sqrt(v̂_k) + ε),
+//
+// where m̂_k and v̂_k are the bias-corrected first and second moment
+// estimates. If AMSGrad is true, the running maximum of v̂_k is used in
+// place of v̂_k, which guarantees non-increasing effective step sizes.
+// After each step the objective function and gradient are evaluated at the
+// new location, which is reported at each major iteration.
+//
+// The methods are described in
+//
+//  Kingma, D. P. and Ba, J. "Adam: A method for stochastic optimization."
+//  arXiv:1412.6980 (2014).
+//  Reddi, S. J., Kale, S. and Kumar, S. "On the convergence of Adam and
+//  beyond." arXiv:1904.09237 (2019).
+//
+// Because the gradients are noisy, the GradientThreshold and FunctionConverge
+// settings may terminate the optimization early, so the optimization is
+// usually limited by the number of evaluations or major iterations instead.
+type Adam struct {
+	// LearningRate is the schedule of step sizes a_k. If LearningRate is
+	// nil, ConstantSchedule{Size: 0.001} is used.
+	LearningRate StepSchedule
+	// Beta1 and Beta2 are the decay rates of the moving averages of the
+	// gradient and of its square. They must be in [0, 1). If they are
+	// zero, they will be set to 0.9 and 0.999 respectively.
+	Beta1, Beta2 float64
+	// Epsilon is added to the denominator of the step to avoid division
+	// by zero. If Epsilon is zero, it will be set to 1e-8.
+	Epsilon float64
+	// AMSGrad specifies whether to use the AMSGrad variant.
+	AMSGrad bool
+
+	learningRate      StepSchedule
+	beta1, beta2, eps float64
+	pow1, pow2        float64
+	m, v, vMax        []float64
+	k                 int
+	evaluating        bool
+}
+
+func (a *Adam) Init(loc *Location) (Operation, error) {
+	dim := len(loc.X)
+	a.learningRate = a.LearningRate
+	if a.learningRate == nil {
+		a.learningRate = ConstantSchedule{Size: 0.001}
+	}
+	a.beta1 = a.Beta1
+	if a.beta1 == 0 {
+		a.beta1 = 0.9
+	}
+	a.beta2 = a.Beta2
+	if a.beta2 == 0 {
+		a.beta2 = 0.999
+	}
+	if a.beta1 < 0 || a.beta1 >= 1 || a.beta2 < 0 || a.beta2 >= 1 {
+		panic("adam: decay rate out of range")
+	}
+	a.eps = a.Epsilon
+	if a.eps == 0 {
+		a.eps = 1e-8
+	}
+
+	a.m = resize(a.m, dim)
+	a.v = resize(a.v, dim)
+	a.vMax = resize(a.vMax, dim)
+	for i := range a.m {
+		a.m[i] = 0
+		a.v[i] = 0
+		a.vMax[i] = 0
+	}
+	a.pow1, a.pow2 = 1, 1
+	a.k = 0
+	return a.step(loc), nil
+}
+
+// step updates the moment estimates with the gradient at
+// loc, moves loc.X and requests an evaluation at the new
+// location.
+func (a *Adam) step(loc *Location) Operation {
+	rate := a.learningRate.Step(a.k)
+	a.k++
+	a.pow1 *= a.beta1
+	a.pow2 *= a.beta2
+	for i, g := range loc.Gradient {
+		a.m[i] = a.beta1*a.m[i] + (1-a.beta1)*g
+		a.v[i] = a.beta2*a.v[i] + (1-a.beta2)*g*g
+		m := a.m[i] / (1 - a.pow1)
+		v := a.v[i] / (1 - a.pow2)
+		if a.AMSGrad {
+			a.vMax[i] = math.Max(a.vMax[i], v)
+			v = a.vMax[i]
+		}
+		loc.X[i] -= rate * m / (math.Sqrt(v) + a.eps)
+	}
+	a.evaluating = true
+	return FuncEvaluation | GradEvaluation
+}
+
+func (a *Adam) Iterate(loc *Location) (Operation, error) {
+	if a.evaluating {
+		a.evaluating = false
+		return MajorIteration, nil
+	}
+	return a.step(loc), nil
+}
+
+func (*Adam) Needs() struct {
+	Gradient bool
+	Hessian  bool
+} {
+	return struct {
+		Gradient bool
+		Hessian  bool
+	}{true, false}
+}
@@ -15,6 +15,9 @@ import (
 // estimate of the inverse Hessian of the objective function. It exhibits
 // super-linear convergence when in proximity to a local minimum. It has memory
 // cost that is O(n^2) relative to the input dimension.
+//
+// BFGS is a WarmStarter. A warm started BFGS uses the inverse Hessian
+// estimate from the end of the earlier optimization for its first step.
 type BFGS struct {
 	// Linesearcher selects suitable steps along the descent direction.
 	// Accepted steps should satisfy the strong Wolfe conditions.
@@ -33,8 +36,20 @@ type BFGS struct {
 	invHess *mat.SymDense
 
 	first bool // Indicator of the first iteration.
+
+	warm *bfgsState // Inverse Hessian for a warm start.
+}
+
+var _ WarmStarter = (*BFGS)(nil)
+
+// bfgsState is the internal state of BFGS
+// used for warm starts.
+type bfgsState struct {
+	invHess *mat.SymDense
 }
 
+func (s *bfgsState) dims() int { return s.invHess.Symmetric() }
+
 func (b *BFGS) Init(loc *Location) (Operation, error) {
 	if b.Linesearcher == nil {
 		b.Linesearcher = &Bisection{}
@@ -71,12 +86,24 @@ func (b *BFGS) InitDirection(loc *Location, dir []float64) (stepSize float64) {
 	} else {
 		b.invHess = mat.NewSymDense(dim, b.invHess.RawSymmetric().Data[:dim*dim])
 	}
+
+	d := mat.NewVecDense(dim, dir)
+	warm := b.warm
+	b.warm = nil
+	if warm != nil && warm.invHess.Symmetric() == dim {
+		// Take a quasi-Newton step using the
+		// warm start inverse Hessian.
+		b.invHess.CopySym(warm.invHess)
+		b.first = false
+		d.MulVec(b.invHess, grad)
+		d.ScaleVec(-1, d)
+		return 1
+	}
 	// The values of the inverse Hessian are initialized in the first call to
 	// NextDirection.
 
 	// Initial direction is just negative of the gradient because the Hessian
 	// is an identity matrix.
-	d := mat.NewVecDense(dim, dir)
 	d.ScaleVec(-1, grad)
 	return 1 / mat.Norm(d, 2)
 }
@@ -149,6 +176,26 @@ func (b *BFGS) NextDirection(loc *Location, dir []float64) (stepSize float64) {
 	return 1
 }
 
+// State returns the internal state of the method for use with WarmStart.
+func (b *BFGS) State() interface{} {
+	if b.invHess == nil || b.first {
+		return nil
+	}
+	invHess := mat.NewSymDense(b.dim, nil)
+	invHess.CopySym(b.invHess)
+	return &bfgsState{invHess: invHess}
+}
+
+// WarmStart sets the state used to initialize the next optimization.
+func (b *BFGS) WarmStart(state interface{}) bool {
+	s, ok := state.(*bfgsState)
+	if !ok {
+		return false
+	}
+	b.warm = s
+	return true
+}
+
 func (*BFGS) Needs() struct {
 	Gradient bool
 	Hessian  bool
@@ -45,6 +45,10 @@ import (
 // The evolution of the multi-variate normal will be similar to the baseline
 // CMA-ES algorithm, but the covariance update equation is not identical.
 //
+// CmaEsChol is a WarmStarter. A warm started CmaEsChol begins with the
+// sampling distribution, step size and evolution paths from the end of the
+// earlier optimization in place of InitMean, InitCholesky and InitStepSize.
+//
 // For more information about the CMA-ES algorithm, see
 //  https://en.wikipedia.org/wiki/CMA-ES
 //  https://arxiv.org/pdf/1604.00772.pdf
@@ -111,13 +115,28 @@ type CmaEsChol struct {
 	receivedIdx int
 	operation   chan<- GlobalTask
 	updateErr   error
+
+	// State for a warm start.
+	warm *cmaState
 }
 
 var (
 	_ Statuser     = (*CmaEsChol)(nil)
 	_ GlobalMethod = (*CmaEsChol)(nil)
+	_ WarmStarter  = (*CmaEsChol)(nil)
 )
 
+// cmaState is the internal state of CmaEsChol
+// used for warm starts.
+type cmaState struct {
+	invSigma float64
+	pc, ps   []float64
+	mean     []float64
+	chol     mat.Cholesky
+}
+
+func (s *cmaState) dims() int { return len(s.mean) }
+
 func (cma *CmaEsChol) Needs() struct{ Gradient, Hessian bool } {
 	return struct{ Gradient, Hessian bool }{false, false}
 }
@@ -144,6 +163,31 @@ func (cma *CmaEsChol) Status() (Status, error) {
 	return cma.methodConverged(), nil
 }
 
+// State returns the internal state of the method for use with WarmStartGlobal.
+func (cma *CmaEsChol) State() interface{} {
+	if cma.dim == 0 {
+		return nil
+	}
+	state := &cmaState{
+		invSigma: cma.invSigma,
+		pc:       append([]float64(nil), cma.pc...),
+		ps:       append([]float64(nil), cma.ps...),
+		mean:     append([]float64(nil), cma.mean...),
+	}
+	state.chol.Clone(&cma.chol)
+	return state
+}
+
+// WarmStart sets the state used to initialize the next optimization.
+func (cma *CmaEsChol) WarmStart(state interface{}) bool {
+	s, ok := state.(*cmaState)
+	if !ok {
+		return false
+	}
+	cma.warm = s
+	return true
+}
+
 func (cma *CmaEsChol) InitGlobal(dim, tasks int) int {
 	if dim <= 0 {
 		panic(nonpositiveDimension)
@@ -230,6 +274,16 @@ func (cma *CmaEsChol) InitGlobal(dim, tasks int) int {
 		cma.chol = chol
 	}
 
+	warm := cma.warm
+	cma.warm = nil
+	if warm != nil && len(warm.mean) == dim {
+		cma.invSigma = warm.invSigma
+		copy(cma.pc, warm.pc)
+		copy(cma.ps, warm.ps)
+		copy(cma.mean, warm.mean)
+		cma.chol.Clone(&warm.chol)
+	}
+
 	cma.bestX = resize(cma.bestX, dim)
 	cma.bestF = math.Inf(1)
 
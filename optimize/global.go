@@ -143,10 +143,15 @@ func Global(p Problem, dim int, settings *Settings, method GlobalMethod) (*Resul
 		err = settings.Recorder.Record(optLoc, PostIteration, stats)
 	}
 	stats.Runtime = time.Since(startTime)
+	var state interface{}
+	if ws, ok := method.(WarmStarter); ok {
+		state = ws.State()
+	}
 	return &Result{
-		Location: *optLoc,
-		Stats:    *stats,
-		Status:   status,
+		Location:    *optLoc,
+		Stats:       *stats,
+		Status:      status,
+		MethodState: state,
 	}, err
 }
 
@@ -122,6 +122,29 @@ type StepSizer interface {
 	StepSize(loc *Location, dir []float64) float64
 }
 
+// WarmStarter is a method whose internal state at the end of an optimization
+// can initialize a later optimization of the same or a closely related
+// problem, so that the later optimization does not start from a cold state.
+type WarmStarter interface {
+	// State returns a copy of the internal state of the method at the end
+	// of the most recent optimization, or nil if there has been none.
+	State() interface{}
+
+	// WarmStart sets the state used to initialize the next optimization,
+	// returning whether the state is of the type used by the method and
+	// compatible with its settings. If WarmStart returns false the state
+	// is not used. The state is used only by the next optimization, and is
+	// ignored if its dimension does not match the dimension of the problem.
+	WarmStart(state interface{}) bool
+}
+
+// StepSchedule returns a predetermined sequence of step sizes for stochastic
+// approximation methods that do not adapt the step size to the objective
+// function. The iteration k starts at zero. Returned step size must be positive.
+type StepSchedule interface {
+	Step(k int) float64
+}
+
 // A Recorder can record the progress of the optimization, for example to print
 // the progress to StdOut or to a log file. A Recorder must not modify any data.
 type Recorder interface {
@@ -18,6 +18,10 @@ import (
 // O(Store * dim) while BFGS scales as O(dim^2). The "forgetful" nature of
 // LBFGS may also make it perform better than BFGS for functions with Hessians
 // that vary rapidly spatially.
+//
+// LBFGS is a WarmStarter. A warm started LBFGS uses the history of the
+// earlier optimization for its first step, and the history is retained
+// until it is replaced by new updates.
 type LBFGS struct {
 	// Linesearcher selects suitable steps along the descent direction.
 	// Accepted steps should satisfy the strong Wolfe conditions.
@@ -39,8 +43,22 @@ type LBFGS struct {
 	s      [][]float64 // Last Store values of s
 	rho    []float64   // Last Store values of rho
 	a      []float64   // Cache of Hessian updates
+
+	warm *lbfgsState // History for a warm start.
+}
+
+var _ WarmStarter = (*LBFGS)(nil)
+
+// lbfgsState is the internal state of LBFGS
+// used for warm starts.
+type lbfgsState struct {
+	oldest int
+	y, s   [][]float64
+	rho    []float64
 }
 
+func (s *lbfgsState) dims() int { return len(s.y[0]) }
+
 func (l *LBFGS) Init(loc *Location) (Operation, error) {
 	if l.Linesearcher == nil {
 		l.Linesearcher = &Bisection{}
@@ -69,6 +87,9 @@ func (l *LBFGS) InitDirection(loc *Location, dir []float64) (stepSize float64) {
 
 	l.a = resize(l.a, l.Store)
 	l.rho = resize(l.rho, l.Store)
+	for i := range l.rho {
+		l.rho[i] = 0
+	}
 	l.y = l.initHistory(l.y)
 	l.s = l.initHistory(l.s)
 
@@ -79,6 +100,29 @@ func (l *LBFGS) InitDirection(loc *Location, dir []float64) (stepSize float64) {
 	copy(l.grad, loc.Gradient)
 
 	copy(dir, loc.Gradient)
+
+	warm := l.warm
+	l.warm = nil
+	if warm != nil && len(warm.rho) == l.Store && len(warm.y[0]) == dim {
+		// Take a quasi-Newton step using the
+		// warm start history.
+		l.oldest = warm.oldest
+		copy(l.rho, warm.rho)
+		for i := range l.y {
+			copy(l.y[i], warm.y[i])
+			copy(l.s[i], warm.s[i])
+		}
+		last := l.oldest - 1
+		if last < 0 {
+			last += l.Store
+		}
+		if yDotY := floats.Dot(l.y[last], l.y[last]); yDotY != 0 {
+			l.applyHistory(dir, floats.Dot(l.s[last], l.y[last])/yDotY)
+			floats.Scale(-1, dir)
+			return 1
+		}
+	}
+
 	floats.Scale(-1, dir)
 	return 1 / floats.Norm(dir, 2)
 }
@@ -126,6 +170,19 @@ func (l *LBFGS) NextDirection(loc *Location, dir []float64) (stepSize float64) {
 	copy(l.grad, loc.Gradient)
 	copy(dir, loc.Gradient)
 
+	// Scale the initial Hessian.
+	gamma := sDotY / floats.Dot(y, y)
+	l.applyHistory(dir, gamma)
+
+	// dir contains H^{-1} * g, so flip the direction for minimization.
+	floats.Scale(-1, dir)
+
+	return 1
+}
+
+// applyHistory multiplies dir in place by the inverse Hessian approximation
+// held in the history, with the initial inverse Hessian gamma * I.
+func (l *LBFGS) applyHistory(dir []float64, gamma float64) {
 	// Start with the most recent element and go backward,
 	for i := 0; i < l.Store; i++ {
 		idx := l.oldest - i - 1
@@ -136,8 +193,6 @@ func (l *LBFGS) NextDirection(loc *Location, dir []float64) (stepSize float64) {
 		floats.AddScaled(dir, -l.a[idx], l.y[idx])
 	}
 
-	// Scale the initial Hessian.
-	gamma := sDotY / floats.Dot(y, y)
 	floats.Scale(gamma, dir)
 
 	// Start with the oldest element and go forward.
@@ -149,11 +204,51 @@ func (l *LBFGS) NextDirection(loc *Location, dir []float64) (stepSize float64) {
 		beta := l.rho[idx] * floats.Dot(l.y[idx], dir)
 		floats.AddScaled(dir, l.a[idx]-beta, l.s[idx])
 	}
+}
 
-	// dir contains H^{-1} * g, so flip the direction for minimization.
-	floats.Scale(-1, dir)
+// State returns the internal state of the method for use with WarmStart.
+func (l *LBFGS) State() interface{} {
+	if l.dim == 0 {
+		return nil
+	}
+	last := l.oldest - 1
+	if last < 0 {
+		last += l.Store
+	}
+	if floats.Dot(l.y[last], l.y[last]) == 0 {
+		// There is no history to warm start from.
+		return nil
+	}
+	state := &lbfgsState{
+		oldest: l.oldest,
+		y:      make([][]float64, len(l.y)),
+		s:      make([][]float64, len(l.s)),
+		rho:    make([]float64, len(l.rho)),
+	}
+	copy(state.rho, l.rho)
+	for i := range l.y {
+		state.y[i] = append([]float64(nil), l.y[i]...)
+		state.s[i] = append([]float64(nil), l.s[i]...)
+	}
+	return state
+}
 
-	return 1
+// WarmStart sets the state used to initialize the next optimization. The
+// state is not used if it was produced by an LBFGS with a different Store.
+func (l *LBFGS) WarmStart(state interface{}) bool {
+	s, ok := state.(*lbfgsState)
+	if !ok {
+		return false
+	}
+	store := l.Store
+	if store == 0 {
+		store = 15
+	}
+	if len(s.rho) != store {
+		return false
+	}
+	l.warm = s
+	return true
 }
 
 func (*LBFGS) Needs() struct {
@@ -111,10 +111,15 @@ func Local(p Problem, initX []float64, settings *Settings, method Method) (*Resu
 		err = settings.Recorder.Record(optLoc, PostIteration, stats)
 	}
 	stats.Runtime = time.Since(startTime)
+	var state interface{}
+	if ws, ok := method.(WarmStarter); ok {
+		state = ws.State()
+	}
 	return &Result{
-		Location: *optLoc,
-		Stats:    *stats,
-		Status:   status,
+		Location:    *optLoc,
+		Stats:       *stats,
+		Status:      status,
+		MethodState: state,
 	}, err
 }
 
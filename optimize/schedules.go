@@ -0,0 +1,45 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package optimize
+
+import "math"
+
+// ConstantSchedule is a StepSchedule that returns the same step size for
+// every iteration.
+type ConstantSchedule struct {
+	Size float64
+}
+
+func (c ConstantSchedule) Step(k int) float64 {
+	return c.Size
+}
+
+// PowerSchedule is a StepSchedule whose step sizes decay as a power of the
+// iteration number,
+//  step_k = Initial / (k + 1 + Offset)^Power.
+// The step sizes of a PowerSchedule with Power in (0.5, 1] satisfy the
+// conditions for convergence of stochastic approximation methods,
+//  Σ step_k = ∞ and Σ step_k^2 < ∞.
+type PowerSchedule struct {
+	Initial float64
+	Offset  float64
+	Power   float64
+}
+
+func (p PowerSchedule) Step(k int) float64 {
+	return p.Initial / math.Pow(float64(k)+1+p.Offset, p.Power)
+}
+
+// ExponentialSchedule is a StepSchedule whose step sizes decay exponentially,
+//  step_k = Initial * Decay^k.
+// Decay must be in (0, 1].
+type ExponentialSchedule struct {
+	Initial float64
+	Decay   float64
+}
+
+func (e ExponentialSchedule) Step(k int) float64 {
+	return e.Initial * math.Pow(e.Decay, float64(k))
+}
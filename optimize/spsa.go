@@ -0,0 +1,147 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package optimize
+
+import (
+	"golang.org/x/exp/rand"
+)
+
+// spsaStage is the evaluation stage of an SPSA iteration.
+type spsaStage int
+
+const (
+	spsaPlus spsaStage = iota
+	spsaMinus
+	spsaCenter
+	spsaMajor
+)
+
+// SPSA implements the simultaneous perturbation stochastic approximation
+// method for gradient-free optimization of noisy objective functions, such
+// as simulation-based objectives. At each iteration k the gradient is
+// estimated from two function evaluations at a random perturbation of the
+// current location,
+//  g_k = (f(x_k + c_k Δ_k) - f(x_k - c_k Δ_k)) / (2 c_k) Δ_k^-1,
+// where the elements of Δ_k are independently ±1 with equal probability and
+// Δ_k^-1 is the elementwise inverse, and the location is updated as
+//  x_{k+1} = x_k - a_k g_k.
+// The objective function is then evaluated at x_{k+1} so that a valid
+// location is reported at each major iteration.
+//
+// The method is described in
+//  Spall, J. C. "Implementation of the simultaneous perturbation algorithm
+//  for stochastic optimization." IEEE Transactions on Aerospace and
+//  Electronic Systems 34(3):817-823 (1998).
+//
+// Because the function values are noisy, the FunctionConverge settings may
+// terminate the optimization early, so the optimization is usually limited
+// by the number of function evaluations or major iterations instead.
+type SPSA struct {
+	// Gain is the schedule of step sizes a_k. If Gain is nil,
+	// PowerSchedule{Initial: 0.1, Power: 0.602} is used.
+	Gain StepSchedule
+	// Perturbation is the schedule of perturbation sizes c_k, which
+	// should be approximately the standard deviation of the noise in
+	// the function values. If Perturbation is nil,
+	// PowerSchedule{Initial: 0.1, Power: 0.101} is used.
+	Perturbation StepSchedule
+
+	// Src allows a random number generator to be supplied for generating
+	// perturbations. If Src is nil the generator in golang.org/x/exp/rand
+	// is used.
+	Src *rand.Rand
+
+	gain, perturbation StepSchedule
+
+	k     int
+	stage spsaStage
+	x     []float64
+	delta []float64
+	c     float64
+	fPlus float64
+}
+
+func (s *SPSA) Init(loc *Location) (Operation, error) {
+	dim := len(loc.X)
+	s.x = resize(s.x, dim)
+	copy(s.x, loc.X)
+	s.delta = resize(s.delta, dim)
+
+	s.gain = s.Gain
+	if s.gain == nil {
+		s.gain = PowerSchedule{Initial: 0.1, Power: 0.602}
+	}
+	s.perturbation = s.Perturbation
+	if s.perturbation == nil {
+		s.perturbation = PowerSchedule{Initial: 0.1, Power: 0.101}
+	}
+
+	s.k = 0
+	return s.perturb(loc), nil
+}
+
+// perturb starts a new iteration by drawing a perturbation
+// and requesting an evaluation at x + c_k Δ_k.
+func (s *SPSA) perturb(loc *Location) Operation {
+	s.c = s.perturbation.Step(s.k)
+	for i := range s.delta {
+		var u float64
+		if s.Src == nil {
+			u = rand.Float64()
+		} else {
+			u = s.Src.Float64()
+		}
+		if u < 0.5 {
+			s.delta[i] = -1
+		} else {
+			s.delta[i] = 1
+		}
+	}
+	for i, v := range s.x {
+		loc.X[i] = v + s.c*s.delta[i]
+	}
+	s.stage = spsaPlus
+	return FuncEvaluation
+}
+
+func (s *SPSA) Iterate(loc *Location) (Operation, error) {
+	switch s.stage {
+	case spsaPlus:
+		s.fPlus = loc.F
+		for i, v := range s.x {
+			loc.X[i] = v - s.c*s.delta[i]
+		}
+		s.stage = spsaMinus
+		return FuncEvaluation, nil
+	case spsaMinus:
+		a := s.gain.Step(s.k)
+		diff := (s.fPlus - loc.F) / (2 * s.c)
+		for i, d := range s.delta {
+			// The inverse of ±1 is itself.
+			s.x[i] -= a * diff * d
+		}
+		s.k++
+		copy(loc.X, s.x)
+		s.stage = spsaCenter
+		return FuncEvaluation, nil
+	case spsaCenter:
+		s.stage = spsaMajor
+		return MajorIteration, nil
+	case spsaMajor:
+		return s.perturb(loc), nil
+	default:
+		panic("spsa: unknown stage")
+	}
+}
+
+func (*SPSA) Needs() struct {
+	Gradient bool
+	Hessian  bool
+} {
+	return struct {
+		Gradient bool
+		Hessian  bool
+	}{false, false}
+}
@@ -0,0 +1,167 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package optimize
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/optimize/functions"
+)
+
+func TestStepSchedules(t *testing.T) {
+	for _, test := range []struct {
+		name     string
+		schedule StepSchedule
+		want     []float64
+	}{
+		{
+			name:     "constant",
+			schedule: ConstantSchedule{Size: 0.5},
+			want:     []float64{0.5, 0.5, 0.5},
+		},
+		{
+			name:     "power",
+			schedule: PowerSchedule{Initial: 2, Offset: 1, Power: 1},
+			want:     []float64{1, 2.0 / 3, 0.5},
+		},
+		{
+			name:     "exponential",
+			schedule: ExponentialSchedule{Initial: 2, Decay: 0.5},
+			want:     []float64{2, 1, 0.5},
+		},
+	} {
+		for k, want := range test.want {
+			if got := test.schedule.Step(k); math.Abs(got-want) > 1e-14 {
+				t.Errorf("unexpected step for %s schedule at iteration %d: got:%v want:%v", test.name, k, got, want)
+			}
+		}
+	}
+}
+
+// noisyQuadratic is the function Σ_i (x_i - Min_i)^2 with additive
+// Gaussian noise of standard deviation Noise on its value and gradient.
+type noisyQuadratic struct {
+	Min   []float64
+	Noise float64
+	rnd   *rand.Rand
+}
+
+func (q noisyQuadratic) Func(x []float64) float64 {
+	var f float64
+	for i, v := range x {
+		d := v - q.Min[i]
+		f += d * d
+	}
+	return f + q.Noise*q.rnd.NormFloat64()
+}
+
+func (q noisyQuadratic) Grad(grad, x []float64) {
+	for i, v := range x {
+		grad[i] = 2*(v-q.Min[i]) + q.Noise*q.rnd.NormFloat64()
+	}
+}
+
+func stochasticSettings(iters int) *Settings {
+	return &Settings{
+		FunctionThreshold: math.Inf(-1),
+		MajorIterations:   iters,
+	}
+}
+
+func TestSPSA(t *testing.T) {
+	q := noisyQuadratic{
+		Min:   []float64{1, -2, 0.5, 3},
+		Noise: 0.1,
+		rnd:   rand.New(rand.NewSource(1)),
+	}
+	method := &SPSA{
+		Gain:         PowerSchedule{Initial: 0.5, Offset: 50, Power: 0.602},
+		Perturbation: PowerSchedule{Initial: 0.2, Power: 0.101},
+		Src:          rand.New(rand.NewSource(2)),
+	}
+	const iters = 2000
+	result, err := Local(Problem{Func: q.Func}, make([]float64, len(q.Min)), stochasticSettings(iters), method)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Status != IterationLimit {
+		t.Errorf("unexpected status: got:%v want:%v", result.Status, IterationLimit)
+	}
+	if !floats.EqualApprox(result.X, q.Min, 0.1) {
+		t.Errorf("unexpected minimum: got:%v want:%v", result.X, q.Min)
+	}
+	// Each iteration uses two evaluations for the gradient
+	// estimate and one at the new location, in addition to
+	// the evaluation at the initial location.
+	if want := 3*iters + 1; result.FuncEvaluations != want {
+		t.Errorf("unexpected number of function evaluations: got:%d want:%d", result.FuncEvaluations, want)
+	}
+
+	// Without noise the iterates converge to the minimum.
+	q.Noise = 0
+	method = &SPSA{
+		Gain: PowerSchedule{Initial: 0.5, Offset: 50, Power: 0.602},
+		Src:  rand.New(rand.NewSource(1)),
+	}
+	result, err = Local(Problem{Func: q.Func}, make([]float64, len(q.Min)), stochasticSettings(iters), method)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !floats.EqualApprox(result.X, q.Min, 1e-6) {
+		t.Errorf("unexpected minimum without noise: got:%v want:%v", result.X, q.Min)
+	}
+}
+
+func TestAdam(t *testing.T) {
+	for _, amsgrad := range []bool{false, true} {
+		q := noisyQuadratic{
+			Min:   []float64{1, -2, 0.5, 3},
+			Noise: 0.5,
+			rnd:   rand.New(rand.NewSource(1)),
+		}
+		method := &Adam{
+			LearningRate: PowerSchedule{Initial: 0.5, Offset: 10, Power: 0.6},
+			AMSGrad:      amsgrad,
+		}
+		const iters = 2000
+		result, err := Local(Problem{Func: q.Func, Grad: q.Grad}, make([]float64, len(q.Min)), stochasticSettings(iters), method)
+		if err != nil {
+			t.Fatalf("unexpected error for AMSGrad=%t: %v", amsgrad, err)
+		}
+		if result.Status != IterationLimit {
+			t.Errorf("unexpected status for AMSGrad=%t: got:%v want:%v", amsgrad, result.Status, IterationLimit)
+		}
+		if !floats.EqualApprox(result.X, q.Min, 0.05) {
+			t.Errorf("unexpected minimum for AMSGrad=%t: got:%v want:%v", amsgrad, result.X, q.Min)
+		}
+		if want := iters + 1; result.GradEvaluations != want {
+			t.Errorf("unexpected number of gradient evaluations for AMSGrad=%t: got:%d want:%d", amsgrad, result.GradEvaluations, want)
+		}
+	}
+
+	// Adam with exact gradients converges to the gradient threshold.
+	p := Problem{
+		Func: functions.ExtendedRosenbrock{}.Func,
+		Grad: functions.ExtendedRosenbrock{}.Grad,
+	}
+	settings := DefaultSettings()
+	settings.FunctionConverge = nil
+	settings.GradientThreshold = 1e-4
+	settings.MajorIterations = 100000
+	result, err := Local(p, []float64{-1.2, 1}, settings, &Adam{LearningRate: ConstantSchedule{Size: 0.01}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Status != GradientThreshold {
+		t.Errorf("unexpected status: got:%v want:%v", result.Status, GradientThreshold)
+	}
+	if !floats.EqualApprox(result.X, []float64{1, 1}, 1e-3) {
+		t.Errorf("unexpected Rosenbrock minimum: got:%v want:[1 1]", result.X)
+	}
+}
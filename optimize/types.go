@@ -101,6 +101,12 @@ type Result struct {
 	Location
 	Stats
 	Status Status
+
+	// MethodState is the internal state of the method at the
+	// end of the optimization if the method is a WarmStarter,
+	// and nil otherwise. It may be used to warm start a later
+	// optimization with WarmStart or WarmStartGlobal.
+	MethodState interface{}
 }
 
 // Stats contains the statistics of the run.
@@ -0,0 +1,69 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package optimize
+
+// WarmStart finds a local minimum of p using Local, starting from the
+// location of prev, the result of an earlier optimization of the same or a
+// closely related problem. If method is a WarmStarter and prev holds the
+// state of a method of the same type, method is initialized from that state
+// rather than from a cold state. This allows a sequence of related problems,
+// for example tracking a drifting optimum, to be solved without each starting
+// cold, and allows the result of a global optimization to be polished by a
+// local method.
+//
+// Only methods that implement WarmStarter use the state in prev. Of the
+// methods in this package these are BFGS and LBFGS, and CmaEsChol which is
+// warm started with WarmStartGlobal. Other methods start from the location of
+// prev with a cold state. The returned warm is true if method was initialized
+// from the state in prev, and false if the state was not applied because
+// method is not a WarmStarter, prev holds no state, or the state is of a
+// different method, a different dimension or is incompatible with the
+// settings of method, for example an LBFGS with a different Store.
+//
+// The arguments p, settings and method and the returned result and error are
+// as for Local. The function value and gradient in prev are not reused, so p
+// may differ from the problem that produced prev.
+func WarmStart(p Problem, prev *Result, settings *Settings, method Method) (result *Result, warm bool, err error) {
+	if method == nil {
+		method = getDefaultMethod(&p)
+	}
+	warm = warmStart(method, prev, len(prev.X))
+	result, err = Local(p, prev.X, settings, method)
+	return result, warm, err
+}
+
+// WarmStartGlobal finds a global minimum of p using Global, with method
+// initialized from the state in prev, the result of an earlier optimization
+// of the same or a closely related problem, if method is a WarmStarter and
+// the state is of a method of the same type. The dimension of the problem is
+// the length of prev.X. Of the global methods in this package only CmaEsChol
+// is a WarmStarter, and other methods start with a cold state. The returned
+// warm reports whether the state in prev was applied, as for WarmStart.
+//
+// The arguments p, settings and method and the returned result and error are
+// as for Global.
+func WarmStartGlobal(p Problem, prev *Result, settings *Settings, method GlobalMethod) (result *Result, warm bool, err error) {
+	if method == nil {
+		method = &GuessAndCheck{}
+	}
+	warm = warmStart(method, prev, len(prev.X))
+	result, err = Global(p, len(prev.X), settings, method)
+	return result, warm, err
+}
+
+// warmStart sets the warm start state of method from prev for a problem
+// of dimension dim, and returns whether the state will be applied.
+func warmStart(method interface{}, prev *Result, dim int) bool {
+	ws, ok := method.(WarmStarter)
+	if !ok || prev.MethodState == nil {
+		return false
+	}
+	if s, ok := prev.MethodState.(interface {
+		dims() int
+	}); ok && s.dims() != dim {
+		return false
+	}
+	return ws.WarmStart(prev.MethodState)
+}
@@ -0,0 +1,304 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package optimize
+
+import (
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/optimize/functions"
+	"gonum.org/v1/gonum/stat/distmv"
+)
+
+// driftingQuadratic is the function (x-c)^T A (x-c) for a fixed
+// ill-conditioned positive definite A and a moving center c.
+type driftingQuadratic struct {
+	a *mat.SymDense
+	c []float64
+}
+
+func newDriftingQuadratic(dim int, rnd *rand.Rand) *driftingQuadratic {
+	b := mat.NewDense(dim, dim, nil)
+	for i := 0; i < dim; i++ {
+		for j := 0; j < dim; j++ {
+			b.Set(i, j, rnd.NormFloat64())
+		}
+	}
+	a := mat.NewSymDense(dim, nil)
+	a.SymOuterK(1, b)
+	for i := 0; i < dim; i++ {
+		a.SetSym(i, i, a.At(i, i)+float64(i+1)*float64(i+1))
+	}
+	return &driftingQuadratic{a: a, c: make([]float64, dim)}
+}
+
+func (q *driftingQuadratic) problem() Problem {
+	return Problem{
+		Func: func(x []float64) float64 {
+			d := make([]float64, len(x))
+			floats.SubTo(d, x, q.c)
+			dv := mat.NewVecDense(len(d), d)
+			return mat.Inner(dv, q.a, dv)
+		},
+		Grad: func(grad, x []float64) {
+			d := make([]float64, len(x))
+			floats.SubTo(d, x, q.c)
+			g := mat.NewVecDense(len(grad), grad)
+			g.MulVec(q.a, mat.NewVecDense(len(d), d))
+			g.ScaleVec(2, g)
+		},
+	}
+}
+
+func TestWarmStartTracking(t *testing.T) {
+	for _, test := range []struct {
+		name   string
+		method func() Method
+	}{
+		{name: "BFGS", method: func() Method { return &BFGS{} }},
+		{name: "LBFGS", method: func() Method { return &LBFGS{} }},
+	} {
+		rnd := rand.New(rand.NewSource(1))
+		const dim = 10
+		q := newDriftingQuadratic(dim, rnd)
+		for i := range q.c {
+			q.c[i] = rnd.NormFloat64()
+		}
+		settings := DefaultSettings()
+		settings.GradientThreshold = 1e-8
+
+		var cold, warm int
+		prevCold, err := Local(q.problem(), make([]float64, dim), settings, test.method())
+		if err != nil {
+			t.Fatalf("unexpected error for %s: %v", test.name, err)
+		}
+		prevWarm := prevCold
+		if prevWarm.MethodState == nil {
+			t.Fatalf("no method state returned for %s", test.name)
+		}
+		warmMethod := test.method()
+		for step := 0; step < 10; step++ {
+			for i := range q.c {
+				q.c[i] += 0.1 * rnd.NormFloat64()
+			}
+
+			res, err := Local(q.problem(), prevCold.X, settings, test.method())
+			if err != nil {
+				t.Fatalf("unexpected error for cold %s: %v", test.name, err)
+			}
+			cold += res.GradEvaluations
+			prevCold = res
+
+			res, ok, err := WarmStart(q.problem(), prevWarm, settings, warmMethod)
+			if err != nil {
+				t.Fatalf("unexpected error for warm %s: %v", test.name, err)
+			}
+			if !ok {
+				t.Errorf("state not applied for warm %s", test.name)
+			}
+			if !floats.EqualApprox(res.X, q.c, 1e-6) {
+				t.Errorf("unexpected warm started minimum for %s: got:%v want:%v", test.name, res.X, q.c)
+			}
+			warm += res.GradEvaluations
+			prevWarm = res
+		}
+		if warm >= cold {
+			t.Errorf("warm starts did not reduce gradient evaluations for %s: warm:%d cold:%d", test.name, warm, cold)
+		}
+	}
+}
+
+func TestWarmStartState(t *testing.T) {
+	// States are only accepted by methods of the matching type.
+	var b BFGS
+	if b.State() != nil {
+		t.Error("unexpected state before optimization")
+	}
+	p := Problem{
+		Func: functions.ExtendedRosenbrock{}.Func,
+		Grad: functions.ExtendedRosenbrock{}.Grad,
+	}
+	res, err := Local(p, []float64{-1.2, 1}, nil, &b)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !b.WarmStart(res.MethodState) {
+		t.Error("BFGS rejected BFGS state")
+	}
+	if (&LBFGS{}).WarmStart(res.MethodState) {
+		t.Error("LBFGS accepted BFGS state")
+	}
+
+	// A state of the wrong dimension is ignored.
+	res, err = Local(p, []float64{-1.2, 1, -1.2, 1}, nil, &b)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !floats.EqualApprox(res.X, []float64{1, 1, 1, 1}, 1e-4) {
+		t.Errorf("unexpected minimum after ignored warm start: got:%v", res.X)
+	}
+}
+
+func TestWarmStartCmaEsChol(t *testing.T) {
+	// Polish the result of a global method with a local method.
+	p := Problem{
+		Func: functions.ExtendedRosenbrock{}.Func,
+		Grad: functions.ExtendedRosenbrock{}.Grad,
+	}
+	const dim = 4
+	cma := &CmaEsChol{
+		InitMean: []float64{-1, 0.5, -1, 0.5},
+		Src:      rand.New(rand.NewSource(1)),
+	}
+	settings := DefaultSettingsGlobal()
+	settings.FuncEvaluations = 500
+	global, err := Global(Problem{Func: p.Func}, dim, settings, cma)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	state, ok := global.MethodState.(*cmaState)
+	if !ok {
+		t.Fatalf("unexpected method state type: %T", global.MethodState)
+	}
+
+	polished, ok, err := WarmStart(p, global, nil, &BFGS{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ok {
+		t.Error("BFGS reported applying CmaEsChol state")
+	}
+	if polished.F > global.F {
+		t.Errorf("polishing increased function value: %v > %v", polished.F, global.F)
+	}
+	if !floats.EqualApprox(polished.X, []float64{1, 1, 1, 1}, 1e-4) {
+		t.Errorf("unexpected polished minimum: got:%v", polished.X)
+	}
+
+	// A warm started CmaEsChol begins from the earlier distribution.
+	warm := &CmaEsChol{Src: rand.New(rand.NewSource(2))}
+	if !warm.WarmStart(global.MethodState) {
+		t.Fatal("CmaEsChol rejected CmaEsChol state")
+	}
+	warm.InitGlobal(dim, 1)
+	if !floats.Equal(warm.mean, state.mean) || warm.invSigma != state.invSigma {
+		t.Errorf("warm start distribution not used: mean:%v want:%v", warm.mean, state.mean)
+	}
+	if warm.chol.LogDet() != state.chol.LogDet() {
+		t.Errorf("warm start covariance not used: log det:%v want:%v", warm.chol.LogDet(), state.chol.LogDet())
+	}
+}
+
+func TestWarmStartApplied(t *testing.T) {
+	p := Problem{
+		Func: functions.ExtendedRosenbrock{}.Func,
+		Grad: functions.ExtendedRosenbrock{}.Grad,
+	}
+	prev, err := Local(p, []float64{-1.2, 1}, nil, &BFGS{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, test := range []struct {
+		name   string
+		method Method
+		want   bool
+	}{
+		{name: "BFGS", method: &BFGS{}, want: true},
+		{name: "LBFGS", method: &LBFGS{}, want: false},
+		{name: "NelderMead", method: &NelderMead{}, want: false},
+	} {
+		_, ok, err := WarmStart(p, prev, nil, test.method)
+		if err != nil {
+			t.Errorf("unexpected error for %s: %v", test.name, err)
+		}
+		if ok != test.want {
+			t.Errorf("unexpected warm start report for %s: got:%t want:%t", test.name, ok, test.want)
+		}
+	}
+
+	// LBFGS state is only applied to an LBFGS
+	// with the same Store and dimension.
+	prevL, err := Local(p, []float64{-1.2, 1}, nil, &LBFGS{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, test := range []struct {
+		name   string
+		prev   *Result
+		method Method
+		want   bool
+	}{
+		{name: "same Store", prev: prevL, method: &LBFGS{}, want: true},
+		{name: "explicit default Store", prev: prevL, method: &LBFGS{Store: 15}, want: true},
+		{name: "different Store", prev: prevL, method: &LBFGS{Store: 5}, want: false},
+		{
+			name:   "different dimension",
+			prev:   &Result{Location: Location{X: []float64{-1.2, 1, -1.2, 1}}, MethodState: prevL.MethodState},
+			method: &LBFGS{},
+			want:   false,
+		},
+	} {
+		_, ok, err := WarmStart(p, test.prev, nil, test.method)
+		if err != nil {
+			t.Errorf("unexpected error for LBFGS %s: %v", test.name, err)
+		}
+		if ok != test.want {
+			t.Errorf("unexpected warm start report for LBFGS %s: got:%t want:%t", test.name, ok, test.want)
+		}
+	}
+
+	// A Result without state is never applied.
+	_, ok, err := WarmStart(p, &Result{Location: Location{X: []float64{-1.2, 1}}}, nil, &BFGS{})
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if ok {
+		t.Error("state reported applied for Result without state")
+	}
+}
+
+func TestWarmStartGlobal(t *testing.T) {
+	p := Problem{Func: functions.ExtendedRosenbrock{}.Func}
+	const dim = 4
+	settings := DefaultSettingsGlobal()
+	settings.FuncEvaluations = 500
+	cma := &CmaEsChol{
+		InitMean: []float64{-1, 0.5, -1, 0.5},
+		Src:      rand.New(rand.NewSource(1)),
+	}
+	prev, err := Global(p, dim, settings, cma)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	settings = DefaultSettingsGlobal()
+	settings.FuncEvaluations = 500
+	res, ok, err := WarmStartGlobal(p, prev, settings, &CmaEsChol{Src: rand.New(rand.NewSource(2))})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !ok {
+		t.Error("CmaEsChol state not applied")
+	}
+	if len(res.X) != dim {
+		t.Errorf("unexpected dimension: got:%d want:%d", len(res.X), dim)
+	}
+	if res.F > prev.F {
+		t.Errorf("warm started CmaEsChol increased function value: %v > %v", res.F, prev.F)
+	}
+
+	settings = DefaultSettingsGlobal()
+	settings.FuncEvaluations = 100
+	_, ok, err = WarmStartGlobal(p, prev, settings, &GuessAndCheck{Rander: distmv.NewUniform([]distmv.Bound{{Min: -2, Max: 2}, {Min: -2, Max: 2}, {Min: -2, Max: 2}, {Min: -2, Max: 2}}, nil)})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ok {
+		t.Error("GuessAndCheck reported applying CmaEsChol state")
+	}
+}
@@ -0,0 +1,12 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package poly provides univariate polynomials with real coefficients.
+//
+// Polynomials are represented in the monomial basis by Poly, and in the
+// Chebyshev basis over an interval by Chebyshev. Least-squares fitting in
+// the Chebyshev basis is better conditioned than in the monomial basis
+// and should be preferred for fits of high degree or over intervals far
+// from the origin.
+package poly // import "gonum.org/v1/gonum/poly"
@@ -0,0 +1,159 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package poly
+
+import (
+	"math"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// Fit returns the polynomial of degree at most deg that minimizes the
+// sum of squared residuals at the points (x[i], y[i]), solved in the
+// monomial basis. If the least-squares problem is singular or nearly so,
+// a mat.Condition error is returned; FitChebyshev is better conditioned.
+// Fit will panic if the lengths of x and y differ, deg is negative or
+// there are fewer than deg+1 points.
+func Fit(x, y []float64, deg int) (Poly, error) {
+	checkFit(x, y, deg)
+	a := mat.NewDense(len(x), deg+1, nil)
+	for i, v := range x {
+		p := 1.0
+		for j := 0; j <= deg; j++ {
+			a.Set(i, j, p)
+			p *= v
+		}
+	}
+	c, err := solve(a, y)
+	if err != nil {
+		return nil, err
+	}
+	return Poly(c), nil
+}
+
+// Chebyshev is a polynomial in the Chebyshev basis over the interval
+// [Lo, Hi]. The polynomial is
+//  C[0]*T_0(t) + C[1]*T_1(t) + ... + C[n]*T_n(t)
+// where T_k is the Chebyshev polynomial of the first kind of degree k
+// and t = (2*x - (Lo + Hi)) / (Hi - Lo) maps [Lo, Hi] onto [-1, 1].
+type Chebyshev struct {
+	C      []float64
+	Lo, Hi float64
+}
+
+// scale returns x mapped from [c.Lo, c.Hi] onto [-1, 1].
+func (c Chebyshev) scale(x float64) float64 {
+	return (2*x - (c.Lo + c.Hi)) / (c.Hi - c.Lo)
+}
+
+// Eval returns the value of the polynomial at x, computed
+// using Clenshaw's recurrence.
+func (c Chebyshev) Eval(x float64) float64 {
+	if len(c.C) == 0 {
+		return 0
+	}
+	t := c.scale(x)
+	var b1, b2 float64
+	for i := len(c.C) - 1; i >= 1; i-- {
+		b1, b2 = 2*t*b1-b2+c.C[i], b1
+	}
+	return t*b1 - b2 + c.C[0]
+}
+
+// Poly returns the polynomial in the monomial basis in x.
+// The conversion may lose accuracy for high degrees.
+func (c Chebyshev) Poly() Poly {
+	// The monomial coefficients are accumulated in t and
+	// then the substitution t = a*x + b is applied.
+	var p, tk, tkm1 Poly
+	for k, v := range c.C {
+		switch k {
+		case 0:
+			tk = Poly{1}
+		case 1:
+			tkm1, tk = tk, Poly{0, 1}
+		default:
+			tkm1, tk = tk, Sub(Mul(Poly{0, 2}, tk), tkm1)
+		}
+		p = Add(p, scale(v, tk))
+	}
+	a := 2 / (c.Hi - c.Lo)
+	b := -(c.Lo + c.Hi) / (c.Hi - c.Lo)
+	lin := Poly{b, a}
+	var q Poly
+	pow := Poly{1}
+	for _, v := range p {
+		q = Add(q, scale(v, pow))
+		pow = Mul(pow, lin)
+	}
+	return q
+}
+
+func scale(f float64, p Poly) Poly {
+	s := make(Poly, len(p))
+	for i, v := range p {
+		s[i] = f * v
+	}
+	return s
+}
+
+// FitChebyshev returns the polynomial of degree at most deg in the
+// Chebyshev basis over the range of x that minimizes the sum of squared
+// residuals at the points (x[i], y[i]). If the least-squares problem is
+// singular or nearly so, a mat.Condition error is returned. FitChebyshev
+// will panic if the lengths of x and y differ, deg is negative, there
+// are fewer than deg+1 points or all the x values are equal.
+func FitChebyshev(x, y []float64, deg int) (Chebyshev, error) {
+	checkFit(x, y, deg)
+	c := Chebyshev{Lo: math.Inf(1), Hi: math.Inf(-1)}
+	for _, v := range x {
+		c.Lo = math.Min(c.Lo, v)
+		c.Hi = math.Max(c.Hi, v)
+	}
+	if c.Lo == c.Hi {
+		panic("poly: degenerate interval")
+	}
+	a := mat.NewDense(len(x), deg+1, nil)
+	for i, v := range x {
+		t := c.scale(v)
+		tkm1, tk := 0.0, 1.0
+		for j := 0; j <= deg; j++ {
+			a.Set(i, j, tk)
+			if j == 0 {
+				tkm1, tk = tk, t
+			} else {
+				tkm1, tk = tk, 2*t*tk-tkm1
+			}
+		}
+	}
+	coef, err := solve(a, y)
+	if err != nil {
+		return Chebyshev{}, err
+	}
+	c.C = coef
+	return c, nil
+}
+
+func checkFit(x, y []float64, deg int) {
+	if len(x) != len(y) {
+		panic("poly: slice length mismatch")
+	}
+	if deg < 0 {
+		panic("poly: negative degree")
+	}
+	if len(x) < deg+1 {
+		panic("poly: too few points for degree")
+	}
+}
+
+// solve returns the least-squares solution of a * c = y.
+func solve(a *mat.Dense, y []float64) ([]float64, error) {
+	var c mat.VecDense
+	err := c.SolveVec(a, mat.NewVecDense(len(y), y))
+	if err != nil {
+		return nil, err
+	}
+	return c.RawVector().Data, nil
+}
@@ -0,0 +1,145 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package poly
+
+import (
+	"errors"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// Poly is a polynomial in the monomial basis. The element at index i
+// is the coefficient of x^i, so the polynomial is
+//  p[0] + p[1]*x + p[2]*x^2 + ... + p[n]*x^n
+// The zero value and an empty Poly are the zero polynomial.
+type Poly []float64
+
+// Degree returns the degree of the polynomial, ignoring trailing zero
+// coefficients. The degree of the zero polynomial is -1.
+func (p Poly) Degree() int {
+	n := len(p) - 1
+	for n >= 0 && p[n] == 0 {
+		n--
+	}
+	return n
+}
+
+// Eval returns the value of the polynomial at x, computed
+// using Horner's method.
+func (p Poly) Eval(x float64) float64 {
+	var v float64
+	for i := len(p) - 1; i >= 0; i-- {
+		v = v*x + p[i]
+	}
+	return v
+}
+
+// Add returns the sum of p and q.
+func Add(p, q Poly) Poly {
+	if len(p) < len(q) {
+		p, q = q, p
+	}
+	s := make(Poly, len(p))
+	copy(s, p)
+	for i, v := range q {
+		s[i] += v
+	}
+	return s
+}
+
+// Sub returns the difference p - q.
+func Sub(p, q Poly) Poly {
+	n := len(p)
+	if len(q) > n {
+		n = len(q)
+	}
+	s := make(Poly, n)
+	copy(s, p)
+	for i, v := range q {
+		s[i] -= v
+	}
+	return s
+}
+
+// Mul returns the product of p and q.
+func Mul(p, q Poly) Poly {
+	if len(p) == 0 || len(q) == 0 {
+		return nil
+	}
+	m := make(Poly, len(p)+len(q)-1)
+	for i, a := range p {
+		if a == 0 {
+			continue
+		}
+		for j, b := range q {
+			m[i+j] += a * b
+		}
+	}
+	return m
+}
+
+// Deriv returns the derivative of p.
+func (p Poly) Deriv() Poly {
+	if len(p) < 2 {
+		return nil
+	}
+	d := make(Poly, len(p)-1)
+	for i := range d {
+		d[i] = float64(i+1) * p[i+1]
+	}
+	return d
+}
+
+// Integ returns the antiderivative of p with constant term c.
+func (p Poly) Integ(c float64) Poly {
+	q := make(Poly, len(p)+1)
+	q[0] = c
+	for i, v := range p {
+		q[i+1] = v / float64(i+1)
+	}
+	return q
+}
+
+// ErrNoConvergence is returned when the eigenvalue
+// decomposition used to find polynomial roots fails.
+var ErrNoConvergence = errors.New("poly: eigenvalue decomposition failed")
+
+// Roots returns the complex roots of p as the eigenvalues of its companion
+// matrix, with each root repeated according to its multiplicity. Roots
+// returns nil for polynomials of degree less than one. Roots with a large
+// condition number, for example multiple roots, are found with reduced
+// accuracy; the mathext/prec package provides an extended precision
+// alternative.
+func (p Poly) Roots() ([]complex128, error) {
+	n := p.Degree()
+	if n < 1 {
+		return nil, nil
+	}
+	// Roots at zero correspond to leading zero coefficients
+	// and are removed to reduce the size of the eigenproblem.
+	var zeros int
+	for zeros < n && p[zeros] == 0 {
+		zeros++
+	}
+	roots := make([]complex128, zeros, n)
+	c := p[zeros : n+1]
+	m := len(c) - 1
+	if m == 0 {
+		return roots, nil
+	}
+
+	comp := mat.NewDense(m, m, nil)
+	for i := 1; i < m; i++ {
+		comp.Set(i, i-1, 1)
+	}
+	for i := 0; i < m; i++ {
+		comp.Set(i, m-1, -c[i]/c[m])
+	}
+	var eig mat.Eigen
+	if !eig.Factorize(comp, false, false) {
+		return nil, ErrNoConvergence
+	}
+	return append(roots, eig.Values(nil)...), nil
+}
@@ -0,0 +1,159 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package poly
+
+import (
+	"math"
+	"math/cmplx"
+	"reflect"
+	"testing"
+)
+
+func TestPolyArithmetic(t *testing.T) {
+	p := Poly{1, 2, 3}
+	q := Poly{-1, 0, 0, 1}
+
+	if got := p.Eval(2); got != 17 {
+		t.Errorf("unexpected value: got:%v want:17", got)
+	}
+	if got, want := Add(p, q), (Poly{0, 2, 3, 1}); !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected sum: got:%v want:%v", got, want)
+	}
+	if got, want := Sub(p, q), (Poly{2, 2, 3, -1}); !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected difference: got:%v want:%v", got, want)
+	}
+	if got, want := Mul(p, q), (Poly{-1, -2, -3, 1, 2, 3}); !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected product: got:%v want:%v", got, want)
+	}
+	if got, want := p.Deriv(), (Poly{2, 6}); !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected derivative: got:%v want:%v", got, want)
+	}
+	if got, want := p.Integ(5), (Poly{5, 1, 1, 1}); !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected integral: got:%v want:%v", got, want)
+	}
+	if got := p.Integ(0).Deriv(); !reflect.DeepEqual(got, p) {
+		t.Errorf("derivative of integral not identity: got:%v want:%v", got, p)
+	}
+	for _, test := range []struct {
+		p    Poly
+		want int
+	}{
+		{p: nil, want: -1},
+		{p: Poly{0, 0}, want: -1},
+		{p: Poly{3}, want: 0},
+		{p: Poly{1, 2, 0, 0}, want: 1},
+	} {
+		if got := test.p.Degree(); got != test.want {
+			t.Errorf("unexpected degree for %v: got:%d want:%d", test.p, got, test.want)
+		}
+	}
+}
+
+func TestRoots(t *testing.T) {
+	const tol = 1e-10
+	for _, test := range []struct {
+		p    Poly
+		want []complex128
+	}{
+		{p: Poly{5}},
+		{p: Poly{-2, 1}, want: []complex128{2}},
+		{p: Poly{1, 0, 1}, want: []complex128{1i, -1i}},
+		{p: Poly{6, -5, 1, 0}, want: []complex128{2, 3}},
+		{p: Poly{0, 0, -1, 1}, want: []complex128{0, 0, 1}},
+		{p: Poly{-6, 11, -6, 1}, want: []complex128{1, 2, 3}},
+	} {
+		got, err := test.p.Roots()
+		if err != nil {
+			t.Errorf("unexpected error for %v: %v", test.p, err)
+			continue
+		}
+		if len(got) != len(test.want) {
+			t.Errorf("unexpected number of roots for %v: got:%v want:%v", test.p, got, test.want)
+			continue
+		}
+		for _, r := range got {
+			if v := evalComplex(test.p, r); cmplx.Abs(v) > tol {
+				t.Errorf("root %v of %v has value %v", r, test.p, v)
+			}
+		}
+		for _, w := range test.want {
+			var found bool
+			for _, r := range got {
+				if cmplx.Abs(r-w) < tol {
+					found = true
+					break
+				}
+			}
+			if !found {
+				t.Errorf("missing root %v of %v: got:%v", w, test.p, got)
+			}
+		}
+	}
+}
+
+func evalComplex(p Poly, z complex128) complex128 {
+	var v complex128
+	for i := len(p) - 1; i >= 0; i-- {
+		v = v*z + complex(p[i], 0)
+	}
+	return v
+}
+
+func TestFit(t *testing.T) {
+	want := Poly{1, -2, 0.5}
+	var x, y []float64
+	for i := 0; i < 20; i++ {
+		v := float64(i)/4 - 2
+		x = append(x, v)
+		y = append(y, want.Eval(v))
+	}
+	got, err := Fit(x, y, 2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for i, v := range want {
+		if math.Abs(got[i]-v) > 1e-12 {
+			t.Errorf("unexpected coefficient %d: got:%v want:%v", i, got[i], v)
+		}
+	}
+
+	c, err := FitChebyshev(x, y, 2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, v := range x {
+		if math.Abs(c.Eval(v)-want.Eval(v)) > 1e-12 {
+			t.Errorf("unexpected Chebyshev value at %v: got:%v want:%v", v, c.Eval(v), want.Eval(v))
+		}
+	}
+	p := c.Poly()
+	for i, v := range want {
+		if math.Abs(p[i]-v) > 1e-12 {
+			t.Errorf("unexpected converted coefficient %d: got:%v want:%v", i, p[i], v)
+		}
+	}
+}
+
+func TestFitChebyshevStability(t *testing.T) {
+	// Fitting a high degree polynomial far from the origin
+	// is ill-conditioned in the monomial basis.
+	const deg = 12
+	f := func(x float64) float64 { return math.Sin((x - 1000) / 3) }
+	var x, y []float64
+	for i := 0; i <= 200; i++ {
+		v := 1000 + 10*float64(i)/200
+		x = append(x, v)
+		y = append(y, f(v))
+	}
+	c, err := FitChebyshev(x, y, deg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, v := range x {
+		if math.Abs(c.Eval(v)-f(v)) > 1e-6 {
+			t.Errorf("unexpected Chebyshev fit at %v: got:%v want:%v", v, c.Eval(v), f(v))
+		}
+	}
+}
@@ -0,0 +1,276 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package balltree
+
+import (
+	"container/heap"
+	"math"
+	"sort"
+)
+
+// DefaultLeafSize is the maximum number of points held in a leaf
+// of a Tree when a non-positive leaf size is given to New.
+const DefaultLeafSize = 16
+
+// Tree is a ball tree over a set of points.
+type Tree struct {
+	points [][]float64
+	dim    int
+	root   *node
+}
+
+// node is a ball of the tree. The ball holds the points indexed
+// by idx, either directly for a leaf or in its two children.
+type node struct {
+	center []float64
+	radius float64
+
+	left, right *node
+	idx         []int
+}
+
+// New returns a ball tree holding the given points, with at most leafSize
+// points in each leaf. If leafSize is not positive, DefaultLeafSize is used.
+// The points are not copied and must not be modified while the tree is in
+// use. New will panic if points is empty or the points do not all have the
+// same non-zero dimension.
+func New(points [][]float64, leafSize int) *Tree {
+	if len(points) == 0 {
+		panic("balltree: no points")
+	}
+	dim := len(points[0])
+	if dim == 0 {
+		panic("balltree: zero dimension")
+	}
+	for _, p := range points {
+		if len(p) != dim {
+			panic("balltree: dimension mismatch")
+		}
+	}
+	if leafSize <= 0 {
+		leafSize = DefaultLeafSize
+	}
+	idx := make([]int, len(points))
+	for i := range idx {
+		idx[i] = i
+	}
+	t := &Tree{points: points, dim: dim}
+	t.root = t.build(idx, leafSize)
+	return t
+}
+
+// build returns a node holding the points indexed by idx.
+func (t *Tree) build(idx []int, leafSize int) *node {
+	n := &node{center: make([]float64, t.dim)}
+	for _, i := range idx {
+		for j, v := range t.points[i] {
+			n.center[j] += v
+		}
+	}
+	for j := range n.center {
+		n.center[j] /= float64(len(idx))
+	}
+	for _, i := range idx {
+		n.radius = math.Max(n.radius, distance(n.center, t.points[i]))
+	}
+	if len(idx) <= leafSize || n.radius == 0 {
+		n.idx = idx
+		return n
+	}
+
+	// Split at the median of the projections onto the
+	// direction between two approximately farthest points.
+	a := farthest(t.points, idx, n.center)
+	b := farthest(t.points, idx, t.points[a])
+	dir := make([]float64, t.dim)
+	for j := range dir {
+		dir[j] = t.points[b][j] - t.points[a][j]
+	}
+	proj := make([]float64, len(idx))
+	for k, i := range idx {
+		for j, v := range t.points[i] {
+			proj[k] += v * dir[j]
+		}
+	}
+	sort.Sort(byProj{idx: idx, proj: proj})
+	mid := len(idx) / 2
+	n.left = t.build(idx[:mid], leafSize)
+	n.right = t.build(idx[mid:], leafSize)
+	return n
+}
+
+// Len returns the number of points in the tree.
+func (t *Tree) Len() int {
+	return len(t.points)
+}
+
+// Neighbor is a point found by a nearest neighbor search.
+type Neighbor struct {
+	// Index is the index of the point
+	// in the slice passed to New.
+	Index int
+
+	// Point is the point.
+	Point []float64
+
+	// Dist is the Euclidean distance from
+	// the query to the point.
+	Dist float64
+}
+
+// Nearest returns the k points of the tree nearest to q, sorted by
+// increasing distance. If the tree holds fewer than k points, all the
+// points are returned. Nearest will panic if the dimension of q does not
+// match the dimension of the tree or k is not positive.
+func (t *Tree) Nearest(q []float64, k int) []Neighbor {
+	return t.search(q, k, -1)
+}
+
+// ApproxNearest returns approximations to the k points of the tree nearest
+// to q, sorted by increasing distance. Nodes of the tree are examined in
+// best-bin-first order, nearest ball first, and the search stops after
+// the points of budget leaves have been examined. The result is exact if the search
+// completes within the budget. ApproxNearest will panic if the dimension
+// of q does not match the dimension of the tree, k is not positive or
+// budget is not positive.
+func (t *Tree) ApproxNearest(q []float64, k, budget int) []Neighbor {
+	if budget <= 0 {
+		panic("balltree: non-positive budget")
+	}
+	return t.search(q, k, budget)
+}
+
+// search performs a best-bin-first search for the k nearest neighbors of q,
+// examining at most budget leaves if budget is positive.
+func (t *Tree) search(q []float64, k, budget int) []Neighbor {
+	if len(q) != t.dim {
+		panic("balltree: dimension mismatch")
+	}
+	if k <= 0 {
+		panic("balltree: non-positive number of neighbors")
+	}
+
+	best := make(maxDistHeap, 0, k)
+	queue := minBoundHeap{{node: t.root, bound: bound(q, t.root)}}
+	for examined := 0; len(queue) != 0; examined++ {
+		if budget > 0 && examined == budget {
+			break
+		}
+		b := heap.Pop(&queue).(ball)
+		if len(best) == k && b.bound >= best[0].Dist {
+			break
+		}
+		// Descend to the nearest leaf, deferring
+		// the farther child at each level.
+		n := b.node
+		for n.idx == nil {
+			near, far := n.left, n.right
+			nb, fb := bound(q, near), bound(q, far)
+			if fb < nb {
+				near, far = far, near
+				fb = nb
+			}
+			heap.Push(&queue, ball{node: far, bound: fb})
+			n = near
+		}
+		for _, i := range n.idx {
+			d := distance(q, t.points[i])
+			switch {
+			case len(best) < k:
+				heap.Push(&best, Neighbor{Index: i, Point: t.points[i], Dist: d})
+			case d < best[0].Dist:
+				best[0] = Neighbor{Index: i, Point: t.points[i], Dist: d}
+				heap.Fix(&best, 0)
+			}
+		}
+	}
+
+	sort.Sort(byDist(best))
+	return []Neighbor(best)
+}
+
+// bound returns a lower bound on the distance from q
+// to any point held by n.
+func bound(q []float64, n *node) float64 {
+	return math.Max(0, distance(q, n.center)-n.radius)
+}
+
+// distance returns the Euclidean distance between a and b.
+func distance(a, b []float64) float64 {
+	var sum float64
+	for i, v := range a {
+		d := v - b[i]
+		sum += d * d
+	}
+	return math.Sqrt(sum)
+}
+
+// farthest returns the element of idx indexing the point
+// farthest from x.
+func farthest(points [][]float64, idx []int, x []float64) int {
+	var (
+		far  = idx[0]
+		dist = -1.0
+	)
+	for _, i := range idx {
+		if d := distance(x, points[i]); d > dist {
+			far, dist = i, d
+		}
+	}
+	return far
+}
+
+type byProj struct {
+	idx  []int
+	proj []float64
+}
+
+func (s byProj) Len() int           { return len(s.idx) }
+func (s byProj) Less(i, j int) bool { return s.proj[i] < s.proj[j] }
+func (s byProj) Swap(i, j int) {
+	s.idx[i], s.idx[j] = s.idx[j], s.idx[i]
+	s.proj[i], s.proj[j] = s.proj[j], s.proj[i]
+}
+
+type byDist []Neighbor
+
+func (s byDist) Len() int           { return len(s) }
+func (s byDist) Less(i, j int) bool { return s[i].Dist < s[j].Dist }
+func (s byDist) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+
+// maxDistHeap is a max-heap of the best neighbors found.
+type maxDistHeap []Neighbor
+
+func (h maxDistHeap) Len() int            { return len(h) }
+func (h maxDistHeap) Less(i, j int) bool  { return h[i].Dist > h[j].Dist }
+func (h maxDistHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *maxDistHeap) Push(x interface{}) { *h = append(*h, x.(Neighbor)) }
+func (h *maxDistHeap) Pop() interface{} {
+	old := *h
+	n := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return n
+}
+
+// ball is a node awaiting examination, with the lower
+// bound on the distance to the points it holds.
+type ball struct {
+	node  *node
+	bound float64
+}
+
+// minBoundHeap is a min-heap of nodes awaiting examination.
+type minBoundHeap []ball
+
+func (h minBoundHeap) Len() int            { return len(h) }
+func (h minBoundHeap) Less(i, j int) bool  { return h[i].bound < h[j].bound }
+func (h minBoundHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *minBoundHeap) Push(x interface{}) { *h = append(*h, x.(ball)) }
+func (h *minBoundHeap) Pop() interface{} {
+	old := *h
+	n := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return n
+}
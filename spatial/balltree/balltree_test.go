@@ -0,0 +1,181 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package balltree
+
+import (
+	"sort"
+	"testing"
+
+	"golang.org/x/exp/rand"
+)
+
+// clustered returns n points in dim dimensions drawn
+// from Gaussian clusters about random centers.
+func clustered(n, dim, clusters int, rnd *rand.Rand) [][]float64 {
+	centers := make([][]float64, clusters)
+	for i := range centers {
+		centers[i] = make([]float64, dim)
+		for j := range centers[i] {
+			centers[i][j] = 20 * rnd.Float64()
+		}
+	}
+	points := make([][]float64, n)
+	for i := range points {
+		c := centers[rnd.Intn(clusters)]
+		points[i] = make([]float64, dim)
+		for j := range points[i] {
+			points[i][j] = c[j] + rnd.NormFloat64()
+		}
+	}
+	return points
+}
+
+// bruteNearest returns the k points nearest to q by linear scan.
+func bruteNearest(points [][]float64, q []float64, k int) []Neighbor {
+	all := make([]Neighbor, len(points))
+	for i, p := range points {
+		all[i] = Neighbor{Index: i, Point: p, Dist: distance(q, p)}
+	}
+	sort.Sort(byDist(all))
+	if k > len(all) {
+		k = len(all)
+	}
+	return all[:k]
+}
+
+func TestNearest(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for _, test := range []struct {
+		n, dim, clusters, leaf, k int
+	}{
+		{n: 1, dim: 3, clusters: 1, leaf: 1, k: 1},
+		{n: 10, dim: 2, clusters: 2, leaf: 1, k: 20},
+		{n: 500, dim: 3, clusters: 5, leaf: 0, k: 5},
+		{n: 2000, dim: 50, clusters: 10, leaf: 8, k: 10},
+	} {
+		points := clustered(test.n, test.dim, test.clusters, rnd)
+		tree := New(points, test.leaf)
+		if tree.Len() != test.n {
+			t.Errorf("unexpected tree size: got:%d want:%d", tree.Len(), test.n)
+		}
+		for trial := 0; trial < 20; trial++ {
+			q := clustered(1, test.dim, 1, rnd)[0]
+			if trial%2 == 0 {
+				q = points[rnd.Intn(test.n)]
+			}
+			got := tree.Nearest(q, test.k)
+			want := bruteNearest(points, q, test.k)
+			if len(got) != len(want) {
+				t.Fatalf("unexpected number of neighbors: got:%d want:%d", len(got), len(want))
+			}
+			for i := range want {
+				if got[i].Dist != want[i].Dist {
+					t.Errorf("n=%d dim=%d: unexpected distance of neighbor %d: got:%v want:%v",
+						test.n, test.dim, i, got[i].Dist, want[i].Dist)
+				}
+			}
+		}
+	}
+
+	// Duplicate points do not prevent construction.
+	dup := [][]float64{{1, 1}, {1, 1}, {1, 1}, {2, 2}}
+	if got := New(dup, 1).Nearest([]float64{1, 1}, 3); got[2].Dist != 0 {
+		t.Errorf("unexpected neighbors of duplicated point: %v", got)
+	}
+
+	for _, fn := range []func(){
+		func() { New(nil, 0) },
+		func() { New([][]float64{{}}, 0) },
+		func() { New([][]float64{{1}, {1, 2}}, 0) },
+		func() { New(dup, 0).Nearest([]float64{1}, 1) },
+		func() { New(dup, 0).Nearest([]float64{1, 1}, 0) },
+		func() { New(dup, 0).ApproxNearest([]float64{1, 1}, 1, 0) },
+	} {
+		if !panics(fn) {
+			t.Error("expected panic")
+		}
+	}
+}
+
+func TestApproxNearest(t *testing.T) {
+	const (
+		n   = 5000
+		dim = 64
+		k   = 5
+	)
+	rnd := rand.New(rand.NewSource(1))
+	points := clustered(n, dim, 20, rnd)
+	tree := New(points, 16)
+
+	var hits, total int
+	for trial := 0; trial < 50; trial++ {
+		q := points[rnd.Intn(n)]
+		exact := tree.Nearest(q, k)
+
+		// An ample budget gives the exact result.
+		full := tree.ApproxNearest(q, k, 2*n)
+		for i := range exact {
+			if full[i].Dist != exact[i].Dist {
+				t.Errorf("unexpected distance of neighbor %d with ample budget: got:%v want:%v", i, full[i].Dist, exact[i].Dist)
+			}
+		}
+
+		got := tree.ApproxNearest(q, k, 40)
+		if len(got) != k {
+			t.Fatalf("unexpected number of neighbors: got:%d want:%d", len(got), k)
+		}
+		for i, nb := range got {
+			if nb.Dist != distance(q, points[nb.Index]) {
+				t.Errorf("unexpected distance for neighbor %d", i)
+			}
+			if i > 0 && nb.Dist < got[i-1].Dist {
+				t.Error("neighbors not sorted by distance")
+			}
+		}
+		want := make(map[int]bool)
+		for _, nb := range exact {
+			want[nb.Index] = true
+		}
+		for _, nb := range got {
+			if want[nb.Index] {
+				hits++
+			}
+		}
+		total += k
+	}
+	if recall := float64(hits) / float64(total); recall < 0.9 {
+		t.Errorf("unexpectedly low recall: got:%v", recall)
+	}
+}
+
+func BenchmarkNearest(b *testing.B) {
+	rnd := rand.New(rand.NewSource(1))
+	points := clustered(10000, 64, 20, rnd)
+	tree := New(points, 16)
+	q := clustered(1, 64, 1, rnd)[0]
+	b.Run("Exact", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			tree.Nearest(q, 10)
+		}
+	})
+	b.Run("Approx", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			tree.ApproxNearest(q, 10, 50)
+		}
+	})
+	b.Run("Linear", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			bruteNearest(points, q, 10)
+		}
+	})
+}
+
+func panics(fn func()) (ok bool) {
+	defer func() {
+		ok = recover() != nil
+	}()
+	fn()
+	return
+}
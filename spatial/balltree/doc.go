@@ -0,0 +1,14 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package balltree implements a ball tree for nearest neighbor search
+// in high-dimensional Euclidean spaces.
+//
+// A ball tree recursively partitions points into nested hyperspheres.
+// Unlike the axis-aligned cells of a k-d tree, the balls adapt to the
+// intrinsic dimension of the data, so pruning remains effective in spaces
+// where exact k-d tree search degrades to a linear scan. For further
+// speed, an approximate search examines a bounded number of tree nodes
+// in best-bin-first order.
+package balltree // import "gonum.org/v1/gonum/spatial/balltree"
@@ -0,0 +1,13 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package r2 provides two-dimensional vectors and computational geometry
+// primitives.
+//
+// The orientation and in-circle predicates are robust: they return the
+// sign of their determinant exactly, using floating point arithmetic when
+// its result is certain and exact rational arithmetic otherwise. Geometric
+// constructions built on them, such as triangulations and convex hulls,
+// are therefore free of inconsistencies caused by rounding.
+package r2 // import "gonum.org/v1/gonum/spatial/r2"
@@ -0,0 +1,145 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package r2
+
+import "math"
+
+// Intersects returns whether the closed segments ab and cd share at
+// least one point. The result is exact.
+func Intersects(a, b, c, d Vec) bool {
+	o1 := Orient(a, b, c)
+	o2 := Orient(a, b, d)
+	o3 := Orient(c, d, a)
+	o4 := Orient(c, d, b)
+	if o1 != o2 && o3 != o4 && o1*o2 <= 0 && o3*o4 <= 0 {
+		return true
+	}
+	return (o1 == 0 && inBox(a, b, c)) ||
+		(o2 == 0 && inBox(a, b, d)) ||
+		(o3 == 0 && inBox(c, d, a)) ||
+		(o4 == 0 && inBox(c, d, b))
+}
+
+// Intersection returns the point at which the segments ab and cd
+// intersect. If the segments do not intersect, or they are collinear
+// and overlap in more than a single point, ok is returned false.
+// Whether the segments intersect is decided exactly; the returned
+// point is subject to floating point rounding.
+func Intersection(a, b, c, d Vec) (p Vec, ok bool) {
+	if !Intersects(a, b, c, d) {
+		return Vec{}, false
+	}
+	r := b.Sub(a)
+	s := d.Sub(c)
+	den := r.Cross(s)
+	if Orient(a, b, c) == 0 && Orient(a, b, d) == 0 {
+		// The segments are collinear, so they can only
+		// meet in a single point at shared endpoints.
+		switch {
+		case a == b:
+			return a, true
+		case c == d:
+			return c, true
+		case (a == c || a == d) && !overlaps(a, b, c, d):
+			return a, true
+		case (b == c || b == d) && !overlaps(b, a, c, d):
+			return b, true
+		}
+		return Vec{}, false
+	}
+	t := c.Sub(a).Cross(s) / den
+	return a.Add(r.Scale(t)), true
+}
+
+// overlaps returns whether the collinear segments pq and cd share
+// more than the endpoint p.
+func overlaps(p, q, c, d Vec) bool {
+	dir := q.Sub(p)
+	return dir.Dot(c.Sub(p)) > 0 || dir.Dot(d.Sub(p)) > 0
+}
+
+// inBox returns whether c lies within the axis-aligned bounding box
+// of a and b.
+func inBox(a, b, c Vec) bool {
+	return math.Min(a.X, b.X) <= c.X && c.X <= math.Max(a.X, b.X) &&
+		math.Min(a.Y, b.Y) <= c.Y && c.Y <= math.Max(a.Y, b.Y)
+}
+
+// Location is the location of a point relative to a polygon.
+type Location int
+
+const (
+	Outside  Location = iota // The point is outside the polygon.
+	Boundary                 // The point is on an edge or vertex.
+	Inside                   // The point is inside the polygon.
+)
+
+// Polygon is a simple polygon described by its vertices in order.
+// The last vertex is implicitly joined to the first.
+type Polygon []Vec
+
+// Area returns the signed area of the polygon. The area is positive
+// if the vertices are in counter-clockwise order and negative if they
+// are in clockwise order.
+func (p Polygon) Area() float64 {
+	if len(p) < 3 {
+		return 0
+	}
+	// Coordinates are taken relative to the first vertex
+	// to reduce cancellation for polygons far from the origin.
+	var sum float64
+	o := p[0]
+	for i := 1; i < len(p)-1; i++ {
+		sum += p[i].Sub(o).Cross(p[i+1].Sub(o))
+	}
+	return sum / 2
+}
+
+// Centroid returns the centroid of the area enclosed by the polygon.
+// If the polygon has zero area, both coordinates of the result are NaN.
+func (p Polygon) Centroid() Vec {
+	if len(p) < 3 {
+		return Vec{X: math.NaN(), Y: math.NaN()}
+	}
+	var c Vec
+	var area float64
+	o := p[0]
+	for i := 1; i < len(p)-1; i++ {
+		u := p[i].Sub(o)
+		v := p[i+1].Sub(o)
+		w := u.Cross(v)
+		area += w
+		c = c.Add(u.Add(v).Scale(w))
+	}
+	if area == 0 {
+		return Vec{X: math.NaN(), Y: math.NaN()}
+	}
+	return o.Add(c.Scale(1 / (3 * area)))
+}
+
+// Locate returns the location of q relative to the polygon. The
+// result is exact and does not depend on the orientation of the
+// polygon.
+func (p Polygon) Locate(q Vec) Location {
+	var winding int
+	for i, a := range p {
+		b := p[(i+1)%len(p)]
+		o := Orient(a, b, q)
+		if o == 0 && inBox(a, b, q) {
+			return Boundary
+		}
+		if a.Y <= q.Y {
+			if b.Y > q.Y && o > 0 {
+				winding++
+			}
+		} else if b.Y <= q.Y && o < 0 {
+			winding--
+		}
+	}
+	if winding != 0 {
+		return Inside
+	}
+	return Outside
+}
@@ -0,0 +1,105 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package r2
+
+import (
+	"math"
+	"testing"
+)
+
+func TestIntersection(t *testing.T) {
+	for _, test := range []struct {
+		name       string
+		a, b, c, d Vec
+		intersects bool
+		ok         bool
+		want       Vec
+	}{
+		{name: "cross", a: Vec{0, 0}, b: Vec{2, 2}, c: Vec{0, 2}, d: Vec{2, 0}, intersects: true, ok: true, want: Vec{1, 1}},
+		{name: "disjoint", a: Vec{0, 0}, b: Vec{1, 1}, c: Vec{0, 2}, d: Vec{0.9, 1.1}},
+		{name: "parallel", a: Vec{0, 0}, b: Vec{1, 0}, c: Vec{0, 1}, d: Vec{1, 1}},
+		{name: "touch", a: Vec{0, 0}, b: Vec{2, 0}, c: Vec{1, 0}, d: Vec{1, 1}, intersects: true, ok: true, want: Vec{1, 0}},
+		{name: "shared endpoint", a: Vec{0, 0}, b: Vec{1, 0}, c: Vec{1, 0}, d: Vec{1, 1}, intersects: true, ok: true, want: Vec{1, 0}},
+		{name: "collinear disjoint", a: Vec{0, 0}, b: Vec{1, 0}, c: Vec{2, 0}, d: Vec{3, 0}},
+		{name: "collinear endpoint", a: Vec{0, 0}, b: Vec{1, 0}, c: Vec{1, 0}, d: Vec{3, 0}, intersects: true, ok: true, want: Vec{1, 0}},
+		{name: "collinear overlap", a: Vec{0, 0}, b: Vec{2, 0}, c: Vec{1, 0}, d: Vec{3, 0}, intersects: true},
+		{name: "point on segment", a: Vec{0, 0}, b: Vec{2, 2}, c: Vec{1, 1}, d: Vec{1, 1}, intersects: true, ok: true, want: Vec{1, 1}},
+	} {
+		if got := Intersects(test.a, test.b, test.c, test.d); got != test.intersects {
+			t.Errorf("unexpected intersection test for %s: got:%t want:%t", test.name, got, test.intersects)
+		}
+		if got := Intersects(test.c, test.d, test.a, test.b); got != test.intersects {
+			t.Errorf("unexpected intersection test for swapped %s: got:%t want:%t", test.name, got, test.intersects)
+		}
+		p, ok := Intersection(test.a, test.b, test.c, test.d)
+		if ok != test.ok {
+			t.Errorf("unexpected intersection status for %s: got:%t want:%t", test.name, ok, test.ok)
+			continue
+		}
+		if ok && p != test.want {
+			t.Errorf("unexpected intersection point for %s: got:%v want:%v", test.name, p, test.want)
+		}
+	}
+}
+
+func TestPolygon(t *testing.T) {
+	const tol = 1e-12
+	for _, test := range []struct {
+		name     string
+		p        Polygon
+		area     float64
+		centroid Vec
+	}{
+		{name: "unit square", p: Polygon{{0, 0}, {1, 0}, {1, 1}, {0, 1}}, area: 1, centroid: Vec{0.5, 0.5}},
+		{name: "clockwise square", p: Polygon{{0, 0}, {0, 1}, {1, 1}, {1, 0}}, area: -1, centroid: Vec{0.5, 0.5}},
+		{name: "triangle", p: Polygon{{0, 0}, {3, 0}, {0, 3}}, area: 4.5, centroid: Vec{1, 1}},
+		{name: "L shape", p: Polygon{{0, 0}, {2, 0}, {2, 1}, {1, 1}, {1, 2}, {0, 2}}, area: 3, centroid: Vec{5.0 / 6, 5.0 / 6}},
+		{name: "offset square", p: Polygon{{1e8, 1e8}, {1e8 + 1, 1e8}, {1e8 + 1, 1e8 + 1}, {1e8, 1e8 + 1}}, area: 1, centroid: Vec{1e8 + 0.5, 1e8 + 0.5}},
+	} {
+		if got := test.p.Area(); math.Abs(got-test.area) > tol {
+			t.Errorf("unexpected area for %s: got:%v want:%v", test.name, got, test.area)
+		}
+		got := test.p.Centroid()
+		if math.Abs(got.X-test.centroid.X) > tol || math.Abs(got.Y-test.centroid.Y) > tol {
+			t.Errorf("unexpected centroid for %s: got:%v want:%v", test.name, got, test.centroid)
+		}
+	}
+
+	c := Polygon{{0, 0}, {1, 1}, {2, 2}}.Centroid()
+	if !math.IsNaN(c.X) || !math.IsNaN(c.Y) {
+		t.Errorf("unexpected centroid for degenerate polygon: got:%v want NaN", c)
+	}
+}
+
+func TestPolygonLocate(t *testing.T) {
+	// A U-shaped polygon.
+	p := Polygon{{0, 0}, {3, 0}, {3, 3}, {2, 3}, {2, 1}, {1, 1}, {1, 3}, {0, 3}}
+	for _, test := range []struct {
+		q    Vec
+		want Location
+	}{
+		{q: Vec{0.5, 0.5}, want: Inside},
+		{q: Vec{0.5, 2}, want: Inside},
+		{q: Vec{2.5, 2}, want: Inside},
+		{q: Vec{1.5, 2}, want: Outside},
+		{q: Vec{1.5, 1}, want: Boundary},
+		{q: Vec{0, 1}, want: Boundary},
+		{q: Vec{3, 3}, want: Boundary},
+		{q: Vec{-1, 1}, want: Outside},
+		{q: Vec{4, 3}, want: Outside},
+		{q: Vec{1.5, 0.5}, want: Inside},
+	} {
+		if got := p.Locate(test.q); got != test.want {
+			t.Errorf("unexpected location for %v: got:%v want:%v", test.q, got, test.want)
+		}
+		r := make(Polygon, len(p))
+		for i, v := range p {
+			r[len(p)-1-i] = v
+		}
+		if got := r.Locate(test.q); got != test.want {
+			t.Errorf("unexpected location for %v in reversed polygon: got:%v want:%v", test.q, got, test.want)
+		}
+	}
+}
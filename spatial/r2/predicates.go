@@ -0,0 +1,115 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package r2
+
+import (
+	"math"
+	"math/big"
+)
+
+// epsilon is half the machine epsilon, the relative rounding
+// error of a floating point operation.
+const epsilon = 1.0 / (1 << 53)
+
+// Error bounds for the floating point evaluation of the predicates,
+// from Shewchuk, "Adaptive Precision Floating-Point Arithmetic and
+// Fast Robust Geometric Predicates", Discrete & Computational Geometry
+// 18:305-363, 1997.
+const (
+	orientErrBound   = (3 + 16*epsilon) * epsilon
+	inCircleErrBound = (10 + 96*epsilon) * epsilon
+)
+
+// Orient returns the orientation of the triangle a, b, c: 1 if the points
+// are in counter-clockwise order, -1 if they are in clockwise order and 0
+// if they are collinear. The result is exact.
+func Orient(a, b, c Vec) int {
+	l := (a.X - c.X) * (b.Y - c.Y)
+	r := (a.Y - c.Y) * (b.X - c.X)
+	det := l - r
+	if bound := orientErrBound * (math.Abs(l) + math.Abs(r)); det > bound || -det > bound {
+		return sign(det)
+	}
+	return orientExact(a, b, c)
+}
+
+// orientExact returns the orientation of a, b, c computed with
+// exact rational arithmetic.
+func orientExact(a, b, c Vec) int {
+	acx := sub(a.X, c.X)
+	acy := sub(a.Y, c.Y)
+	bcx := sub(b.X, c.X)
+	bcy := sub(b.Y, c.Y)
+	l := new(big.Rat).Mul(acx, bcy)
+	r := new(big.Rat).Mul(acy, bcx)
+	return l.Cmp(r)
+}
+
+// InCircle returns 1 if d lies inside the circle through a, b and c,
+// -1 if it lies outside and 0 if the four points are cocircular, when a,
+// b and c are in counter-clockwise order. The sign of the result is
+// reversed when a, b and c are in clockwise order. The result is exact.
+func InCircle(a, b, c, d Vec) int {
+	adx, ady := a.X-d.X, a.Y-d.Y
+	bdx, bdy := b.X-d.X, b.Y-d.Y
+	cdx, cdy := c.X-d.X, c.Y-d.Y
+
+	bdxcdy, cdxbdy := bdx*cdy, cdx*bdy
+	alift := adx*adx + ady*ady
+	cdxady, adxcdy := cdx*ady, adx*cdy
+	blift := bdx*bdx + bdy*bdy
+	adxbdy, bdxady := adx*bdy, bdx*ady
+	clift := cdx*cdx + cdy*cdy
+
+	det := alift*(bdxcdy-cdxbdy) + blift*(cdxady-adxcdy) + clift*(adxbdy-bdxady)
+	permanent := (math.Abs(bdxcdy)+math.Abs(cdxbdy))*alift +
+		(math.Abs(cdxady)+math.Abs(adxcdy))*blift +
+		(math.Abs(adxbdy)+math.Abs(bdxady))*clift
+	if bound := inCircleErrBound * permanent; det > bound || -det > bound {
+		return sign(det)
+	}
+	return inCircleExact(a, b, c, d)
+}
+
+// inCircleExact returns the in-circle test of a, b, c and d
+// computed with exact rational arithmetic.
+func inCircleExact(a, b, c, d Vec) int {
+	adx, ady := sub(a.X, d.X), sub(a.Y, d.Y)
+	bdx, bdy := sub(b.X, d.X), sub(b.Y, d.Y)
+	cdx, cdy := sub(c.X, d.X), sub(c.Y, d.Y)
+
+	det := inCircleTerm(adx, ady, bdx, bdy, cdx, cdy)
+	det.Add(det, inCircleTerm(bdx, bdy, cdx, cdy, adx, ady))
+	det.Add(det, inCircleTerm(cdx, cdy, adx, ady, bdx, bdy))
+	return det.Sign()
+}
+
+// inCircleTerm returns the term of the in-circle determinant
+//  (px² + py²) * (qx*ry - rx*qy)
+// for the point p with the following points q and r.
+func inCircleTerm(px, py, qx, qy, rx, ry *big.Rat) *big.Rat {
+	lift := new(big.Rat).Mul(px, px)
+	lift.Add(lift, new(big.Rat).Mul(py, py))
+	m := new(big.Rat).Mul(qx, ry)
+	m.Sub(m, new(big.Rat).Mul(rx, qy))
+	return lift.Mul(lift, m)
+}
+
+// sub returns the exact difference a-b.
+func sub(a, b float64) *big.Rat {
+	x := new(big.Rat).SetFloat64(a)
+	return x.Sub(x, new(big.Rat).SetFloat64(b))
+}
+
+func sign(x float64) int {
+	switch {
+	case x > 0:
+		return 1
+	case x < 0:
+		return -1
+	default:
+		return 0
+	}
+}
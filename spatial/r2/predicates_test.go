@@ -0,0 +1,77 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package r2
+
+import (
+	"math"
+	"testing"
+)
+
+func TestOrient(t *testing.T) {
+	for i, test := range []struct {
+		a, b, c Vec
+		want    int
+	}{
+		{a: Vec{0, 0}, b: Vec{1, 0}, c: Vec{0, 1}, want: 1},
+		{a: Vec{0, 0}, b: Vec{0, 1}, c: Vec{1, 0}, want: -1},
+		{a: Vec{0, 0}, b: Vec{1, 1}, c: Vec{2, 2}, want: 0},
+		{a: Vec{0.1, 0.1}, b: Vec{0.2, 0.2}, c: Vec{0.3, 0.3}, want: 0},
+		{a: Vec{12, 12}, b: Vec{24, 24}, c: Vec{0.5, 0.5 + math.Ldexp(1, -52)}, want: 1},
+		{a: Vec{12, 12}, b: Vec{24, 24}, c: Vec{0.5, 0.5 - math.Ldexp(1, -53)}, want: -1},
+	} {
+		got := Orient(test.a, test.b, test.c)
+		if got != test.want {
+			t.Errorf("unexpected orientation for test %d: got:%d want:%d", i, got, test.want)
+		}
+		if got, want := Orient(test.b, test.a, test.c), -test.want; got != want {
+			t.Errorf("unexpected orientation for reversed test %d: got:%d want:%d", i, got, want)
+		}
+	}
+}
+
+func TestOrientNearDegenerate(t *testing.T) {
+	// Points near the line y = x are classified consistently
+	// with exact arithmetic, unlike the naive determinant.
+	a := Vec{12, 12}
+	b := Vec{24, 24}
+	for i := 0; i < 256; i++ {
+		for j := 0; j < 256; j++ {
+			c := Vec{
+				0.5 + float64(i)*math.Ldexp(1, -53),
+				0.5 + float64(j)*math.Ldexp(1, -53),
+			}
+			got := Orient(a, b, c)
+			want := orientExact(a, b, c)
+			if got != want {
+				t.Fatalf("unexpected orientation for %v: got:%d want:%d", c, got, want)
+			}
+		}
+	}
+}
+
+func TestInCircle(t *testing.T) {
+	a := Vec{1, 0}
+	b := Vec{0, 1}
+	c := Vec{-1, 0}
+	for i, test := range []struct {
+		d    Vec
+		want int
+	}{
+		{d: Vec{0, 0}, want: 1},
+		{d: Vec{0, -1}, want: 0},
+		{d: Vec{2, 2}, want: -1},
+		{d: Vec{0, -1 - math.Ldexp(1, -52)}, want: -1},
+		{d: Vec{0, -1 + math.Ldexp(1, -53)}, want: 1},
+		{d: Vec{math.Sqrt2 / 2, -math.Sqrt2 / 2}, want: inCircleExact(a, b, c, Vec{math.Sqrt2 / 2, -math.Sqrt2 / 2})},
+	} {
+		got := InCircle(a, b, c, test.d)
+		if got != test.want {
+			t.Errorf("unexpected in-circle result for test %d: got:%d want:%d", i, got, test.want)
+		}
+		if got, want := InCircle(b, a, c, test.d), -test.want; got != want {
+			t.Errorf("unexpected in-circle result for clockwise test %d: got:%d want:%d", i, got, want)
+		}
+	}
+}
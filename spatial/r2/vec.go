@@ -0,0 +1,42 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package r2
+
+import "math"
+
+// Vec is a 2D vector.
+type Vec struct {
+	X, Y float64
+}
+
+// Add returns the vector sum of p and q.
+func (p Vec) Add(q Vec) Vec {
+	return Vec{X: p.X + q.X, Y: p.Y + q.Y}
+}
+
+// Sub returns the vector sum of p and -q.
+func (p Vec) Sub(q Vec) Vec {
+	return Vec{X: p.X - q.X, Y: p.Y - q.Y}
+}
+
+// Scale returns the vector p scaled by f.
+func (p Vec) Scale(f float64) Vec {
+	return Vec{X: f * p.X, Y: f * p.Y}
+}
+
+// Dot returns the dot product p·q.
+func (p Vec) Dot(q Vec) float64 {
+	return p.X*q.X + p.Y*q.Y
+}
+
+// Cross returns the z component of the cross product p×q.
+func (p Vec) Cross(q Vec) float64 {
+	return p.X*q.Y - p.Y*q.X
+}
+
+// Norm returns the Euclidean norm of p.
+func (p Vec) Norm() float64 {
+	return math.Hypot(p.X, p.Y)
+}
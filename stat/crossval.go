@@ -0,0 +1,175 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package stat
+
+import (
+	"math"
+	"sort"
+
+	"golang.org/x/exp/rand"
+)
+
+// Fold is a split of sample indices into a training set and a test set
+// for evaluating a model. The indices of each set are in increasing order
+// and the two sets are disjoint.
+type Fold struct {
+	Train []int
+	Test  []int
+}
+
+// KFold partitions the sample indices [0, n) into k folds for k-fold
+// cross-validation. The test sets of the folds are disjoint and cover
+// all n indices, and each has either n/k or n/k+1 elements. The training
+// set of each fold holds the indices that are not in its test set.
+//
+// If shuffle is false the test sets are contiguous runs of indices.
+// Otherwise the indices are randomly permuted before partitioning. If src
+// is not nil it is used as the random source, otherwise rand.Perm is used.
+//
+// KFold will panic if k is less than 2 or greater than n.
+func KFold(n, k int, shuffle bool, src *rand.Rand) []Fold {
+	if k < 2 || n < k {
+		panic("stat: bad number of folds")
+	}
+	var idx []int
+	if shuffle {
+		idx = perm(n, src)
+	} else {
+		idx = make([]int, n)
+		for i := range idx {
+			idx[i] = i
+		}
+	}
+	fold := make([]int, n)
+	start := 0
+	for f := 0; f < k; f++ {
+		size := n / k
+		if f < n%k {
+			size++
+		}
+		for _, i := range idx[start : start+size] {
+			fold[i] = f
+		}
+		start += size
+	}
+	return folds(fold, k)
+}
+
+// StratifiedKFold partitions the sample indices [0, len(labels)) into k
+// folds for k-fold cross-validation, preserving the proportion of each
+// class label in every fold as closely as possible. The test sets of the
+// folds are disjoint and cover all indices, and each has either
+// len(labels)/k or len(labels)/k+1 elements.
+//
+// If shuffle is true the indices within each class are randomly permuted
+// before they are assigned to folds. If src is not nil it is used as the
+// random source, otherwise rand.Perm is used.
+//
+// StratifiedKFold will panic if k is less than 2 or greater than
+// len(labels).
+func StratifiedKFold(labels []int, k int, shuffle bool, src *rand.Rand) []Fold {
+	n := len(labels)
+	if k < 2 || n < k {
+		panic("stat: bad number of folds")
+	}
+
+	// Group the indices by class in order
+	// of first appearance of each label.
+	class := make(map[int]int)
+	var members [][]int
+	for i, l := range labels {
+		c, ok := class[l]
+		if !ok {
+			c = len(members)
+			class[l] = c
+			members = append(members, nil)
+		}
+		members[c] = append(members[c], i)
+	}
+
+	// Deal the indices of each class to the
+	// folds in turn, continuing from the fold
+	// the previous class stopped at so that
+	// fold sizes remain balanced.
+	fold := make([]int, n)
+	var next int
+	for _, m := range members {
+		if shuffle {
+			p := perm(len(m), src)
+			shuffled := make([]int, len(m))
+			for i, j := range p {
+				shuffled[i] = m[j]
+			}
+			m = shuffled
+		}
+		for _, i := range m {
+			fold[i] = next
+			next = (next + 1) % k
+		}
+	}
+	return folds(fold, k)
+}
+
+// ShuffleSplit returns splits independent random splits of the sample
+// indices [0, n) into a training set and a test set. Each test set has
+// ceil(testFrac*n) elements and each training set holds the remaining
+// indices. Unlike the test sets of KFold, the test sets of different
+// splits may overlap. A single split is a random train/test split of
+// the samples.
+//
+// If src is not nil it is used as the random source, otherwise rand.Perm
+// is used.
+//
+// ShuffleSplit will panic if splits is less than 1 or if testFrac does not
+// give a test set and a training set that are both non-empty.
+func ShuffleSplit(n, splits int, testFrac float64, src *rand.Rand) []Fold {
+	if splits < 1 {
+		panic("stat: bad number of splits")
+	}
+	if !(0 < testFrac && testFrac < 1) {
+		panic("stat: bad test fraction")
+	}
+	nTest := int(math.Ceil(testFrac * float64(n)))
+	if nTest < 1 || n <= nTest {
+		panic("stat: bad test fraction")
+	}
+	f := make([]Fold, splits)
+	for s := range f {
+		p := perm(n, src)
+		// Give test its own capacity so that appending
+		// to it does not overwrite train.
+		test := p[:nTest:nTest]
+		train := p[nTest:]
+		sort.Ints(test)
+		sort.Ints(train)
+		f[s] = Fold{Train: train, Test: test}
+	}
+	return f
+}
+
+// folds returns the k folds described by the fold assignment
+// of each index.
+func folds(fold []int, k int) []Fold {
+	f := make([]Fold, k)
+	for i, t := range fold {
+		for j := range f {
+			if j == t {
+				f[j].Test = append(f[j].Test, i)
+			} else {
+				f[j].Train = append(f[j].Train, i)
+			}
+		}
+	}
+	return f
+}
+
+// perm returns a random permutation of [0, n) using src
+// if it is not nil.
+func perm(n int, src *rand.Rand) []int {
+	if src == nil {
+		return rand.Perm(n)
+	}
+	return src.Perm(n)
+}
@@ -0,0 +1,171 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package stat
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+
+	"golang.org/x/exp/rand"
+)
+
+func TestKFold(t *testing.T) {
+	got := KFold(7, 3, false, nil)
+	want := []Fold{
+		{Train: []int{3, 4, 5, 6}, Test: []int{0, 1, 2}},
+		{Train: []int{0, 1, 2, 5, 6}, Test: []int{3, 4}},
+		{Train: []int{0, 1, 2, 3, 4}, Test: []int{5, 6}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected unshuffled folds:\ngot: %v\nwant:%v", got, want)
+	}
+
+	rnd := rand.New(rand.NewSource(1))
+	for _, test := range []struct{ n, k int }{
+		{n: 2, k: 2},
+		{n: 10, k: 5},
+		{n: 23, k: 4},
+		{n: 100, k: 10},
+	} {
+		f := KFold(test.n, test.k, true, rnd)
+		checkFolds(t, f, test.n, test.k, true)
+		a := KFold(test.n, test.k, true, rand.New(rand.NewSource(2)))
+		b := KFold(test.n, test.k, true, rand.New(rand.NewSource(2)))
+		if !reflect.DeepEqual(a, b) {
+			t.Errorf("folds not reproducible for n=%d k=%d", test.n, test.k)
+		}
+	}
+
+	for _, test := range []struct{ n, k int }{{n: 5, k: 1}, {n: 3, k: 4}} {
+		if !panics(func() { KFold(test.n, test.k, false, nil) }) {
+			t.Errorf("expected panic for n=%d k=%d", test.n, test.k)
+		}
+	}
+}
+
+func TestStratifiedKFold(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for _, test := range []struct {
+		labels []int
+		k      int
+	}{
+		{labels: []int{0, 0, 1, 1}, k: 2},
+		{labels: []int{0, 1, 0, 1, 0, 1, 0, 1, 0, 1}, k: 5},
+		{labels: []int{2, 2, 2, 2, 2, 2, 7, 7, 7, -1, -1, -1, -1}, k: 3},
+	} {
+		for _, shuffle := range []bool{false, true} {
+			f := StratifiedKFold(test.labels, test.k, shuffle, rnd)
+			checkFolds(t, f, len(test.labels), test.k, true)
+
+			count := make(map[int]int)
+			for _, l := range test.labels {
+				count[l]++
+			}
+			for i, fold := range f {
+				inFold := make(map[int]int)
+				for _, j := range fold.Test {
+					inFold[test.labels[j]]++
+				}
+				for l, c := range count {
+					lo := c / test.k
+					if n := inFold[l]; n < lo || lo+1 < n {
+						t.Errorf("unbalanced class %d in fold %d of %v: got:%d want:%d or %d", l, i, test.labels, n, lo, lo+1)
+					}
+				}
+			}
+		}
+	}
+	if !panics(func() { StratifiedKFold([]int{0, 1}, 3, false, nil) }) {
+		t.Error("expected panic for too many folds")
+	}
+}
+
+func TestShuffleSplit(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for _, test := range []struct {
+		n, splits int
+		frac      float64
+		nTest     int
+	}{
+		{n: 2, splits: 1, frac: 0.5, nTest: 1},
+		{n: 10, splits: 3, frac: 0.25, nTest: 3},
+		{n: 100, splits: 5, frac: 0.2, nTest: 20},
+	} {
+		f := ShuffleSplit(test.n, test.splits, test.frac, rnd)
+		if len(f) != test.splits {
+			t.Errorf("unexpected number of splits: got:%d want:%d", len(f), test.splits)
+		}
+		checkFolds(t, f, test.n, test.splits, false)
+		for i, fold := range f {
+			if len(fold.Test) != test.nTest {
+				t.Errorf("unexpected test size for split %d of n=%d: got:%d want:%d", i, test.n, len(fold.Test), test.nTest)
+			}
+		}
+	}
+
+	// Appending to a test set must not alter its training set.
+	f := ShuffleSplit(10, 1, 0.3, rnd)
+	train := append([]int(nil), f[0].Train...)
+	_ = append(f[0].Test, -1)
+	for i, v := range f[0].Train {
+		if v != train[i] {
+			t.Errorf("training set modified by append to test set: got:%v want:%v", f[0].Train, train)
+			break
+		}
+	}
+
+	for _, test := range []struct {
+		n, splits int
+		frac      float64
+	}{
+		{n: 10, splits: 0, frac: 0.5},
+		{n: 10, splits: 1, frac: 0},
+		{n: 10, splits: 1, frac: 1},
+		{n: 1, splits: 1, frac: 0.5},
+	} {
+		if !panics(func() { ShuffleSplit(test.n, test.splits, test.frac, nil) }) {
+			t.Errorf("expected panic for n=%d splits=%d frac=%v", test.n, test.splits, test.frac)
+		}
+	}
+}
+
+// checkFolds checks that each fold is a sorted disjoint partition of
+// [0, n) into training and test sets and, if partition is true, that the
+// test sets partition [0, n) into k sets of balanced size.
+func checkFolds(t *testing.T, folds []Fold, n, k int, partition bool) {
+	if len(folds) != k {
+		t.Errorf("unexpected number of folds: got:%d want:%d", len(folds), k)
+	}
+	seen := make([]int, n)
+	for i, f := range folds {
+		if !sort.IntsAreSorted(f.Train) || !sort.IntsAreSorted(f.Test) {
+			t.Errorf("fold %d not sorted: %v", i, f)
+		}
+		all := append(append([]int(nil), f.Train...), f.Test...)
+		sort.Ints(all)
+		for j, v := range all {
+			if v != j {
+				t.Errorf("fold %d does not partition [0, %d): %v", i, n, f)
+				break
+			}
+		}
+		for _, j := range f.Test {
+			seen[j]++
+		}
+		if partition {
+			if size := len(f.Test); size < n/k || n/k+1 < size {
+				t.Errorf("unbalanced test set size for fold %d: got:%d want:%d or %d", i, size, n/k, n/k+1)
+			}
+		}
+	}
+	if partition {
+		for j, c := range seen {
+			if c != 1 {
+				t.Errorf("index %d in %d test sets", j, c)
+			}
+		}
+	}
+}
@@ -0,0 +1,428 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package distmv
+
+import (
+	"math"
+	"sort"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// SparseSymmetric is a sparse symmetric matrix storing only its non-zero
+// elements. It is used to specify the precision matrix of a GMRF.
+// SparseSymmetric implements the mat.Symmetric interface.
+type SparseSymmetric struct {
+	n    int
+	rows []map[int]float64
+}
+
+// NewSparseSymmetric returns a new n×n sparse symmetric matrix with all
+// elements zero. NewSparseSymmetric will panic if n is not positive.
+func NewSparseSymmetric(n int) *SparseSymmetric {
+	if n <= 0 {
+		panic(nonPosDimension)
+	}
+	rows := make([]map[int]float64, n)
+	for i := range rows {
+		rows[i] = make(map[int]float64)
+	}
+	return &SparseSymmetric{n: n, rows: rows}
+}
+
+// At returns the value of the element at row i and column j.
+func (s *SparseSymmetric) At(i, j int) float64 {
+	if uint(i) >= uint(s.n) || uint(j) >= uint(s.n) {
+		panic(mat.ErrIndexOutOfRange)
+	}
+	return s.rows[i][j]
+}
+
+// Dims returns the dimensions of the matrix.
+func (s *SparseSymmetric) Dims() (r, c int) { return s.n, s.n }
+
+// T returns the receiver, the transpose of a symmetric matrix.
+func (s *SparseSymmetric) T() mat.Matrix { return s }
+
+// Symmetric returns the number of rows and columns of the matrix.
+func (s *SparseSymmetric) Symmetric() int { return s.n }
+
+// SetSym sets the elements at (i, j) and (j, i) to v. Setting an
+// element to zero removes it from the sparsity structure.
+func (s *SparseSymmetric) SetSym(i, j int, v float64) {
+	if uint(i) >= uint(s.n) || uint(j) >= uint(s.n) {
+		panic(mat.ErrIndexOutOfRange)
+	}
+	if v == 0 {
+		delete(s.rows[i], j)
+		delete(s.rows[j], i)
+		return
+	}
+	s.rows[i][j] = v
+	s.rows[j][i] = v
+}
+
+// NonZero returns the number of non-zero elements in the matrix.
+func (s *SparseSymmetric) NonZero() int {
+	var nnz int
+	for _, r := range s.rows {
+		nnz += len(r)
+	}
+	return nnz
+}
+
+// clone returns a copy of the receiver.
+func (s *SparseSymmetric) clone() *SparseSymmetric {
+	c := &SparseSymmetric{n: s.n, rows: make([]map[int]float64, s.n)}
+	for i, r := range s.rows {
+		c.rows[i] = make(map[int]float64, len(r))
+		for j, v := range r {
+			c.rows[i][j] = v
+		}
+	}
+	return c
+}
+
+// GMRF is a Gaussian Markov random field, a multivariate normal distribution
+// parameterized by its mean and a sparse precision matrix Q. Its pdf in k
+// dimensions is given by
+//  (2 π)^(-k/2) |Q|^(1/2) exp(-1/2 (x-μ)'Q(x-μ))
+// where μ is the mean vector. Q must be symmetric and positive definite. The
+// zero elements of Q encode the conditional independence structure of the
+// field; Q_ij is zero if x_i and x_j are independent given all other
+// elements of x.
+//
+// The precision matrix is factorized with a sparse envelope Cholesky
+// decomposition after a reverse Cuthill-McKee reordering of the variables,
+// so GMRFs whose precision matrices have small bandwidth after reordering,
+// such as those defined on spatial lattices, may have many dimensions.
+// Use NewGMRF to construct.
+type GMRF struct {
+	mu   []float64
+	prec *SparseSymmetric
+	dim  int
+	src  *rand.Rand
+
+	// perm holds the factorization ordering of the variables;
+	// perm[k] is the variable in position k of the ordering.
+	perm []int
+
+	// first and l hold the envelope Cholesky factor L of the
+	// reordered precision matrix. Row i of L has non-zero
+	// elements in columns first[i] through i, stored in l[i].
+	first []int
+	l     [][]float64
+
+	logDet float64
+}
+
+// NewGMRF creates a new GMRF with the given mean and sparse precision matrix.
+// NewGMRF panics if len(mu) == 0, or if len(mu) != prec.Symmetric(). If the
+// precision matrix is not positive-definite, NewGMRF returns nil for g and
+// false for ok. The precision matrix is copied, so subsequent changes to prec
+// do not affect the distribution.
+func NewGMRF(mu []float64, prec *SparseSymmetric, src *rand.Rand) (g *GMRF, ok bool) {
+	if len(mu) == 0 {
+		panic(badZeroDimension)
+	}
+	dim := prec.Symmetric()
+	if dim != len(mu) {
+		panic(badSizeMismatch)
+	}
+	g = &GMRF{
+		mu:   make([]float64, dim),
+		prec: prec.clone(),
+		dim:  dim,
+		src:  src,
+	}
+	copy(g.mu, mu)
+	if !g.factorize() {
+		return nil, false
+	}
+	return g, true
+}
+
+// factorize computes the reordering and the envelope Cholesky factor of the
+// precision matrix, returning whether the precision matrix is positive
+// definite.
+func (g *GMRF) factorize() bool {
+	n := g.dim
+	g.perm = reverseCuthillMcKee(g.prec)
+	inv := make([]int, n)
+	for k, p := range g.perm {
+		inv[p] = k
+	}
+
+	g.first = make([]int, n)
+	g.l = make([][]float64, n)
+	for i, p := range g.perm {
+		first := i
+		for j := range g.prec.rows[p] {
+			if k := inv[j]; k < first {
+				first = k
+			}
+		}
+		row := make([]float64, i-first+1)
+		for j, v := range g.prec.rows[p] {
+			if k := inv[j]; k <= i {
+				row[k-first] = v
+			}
+		}
+		g.first[i] = first
+		g.l[i] = row
+	}
+
+	g.logDet = 0
+	for i, li := range g.l {
+		fi := g.first[i]
+		for j := fi; j < i; j++ {
+			lj := g.l[j]
+			fj := g.first[j]
+			lo := fi
+			if fj > lo {
+				lo = fj
+			}
+			s := li[j-fi]
+			for k := lo; k < j; k++ {
+				s -= li[k-fi] * lj[k-fj]
+			}
+			li[j-fi] = s / lj[j-fj]
+		}
+		s := li[i-fi]
+		for _, v := range li[:i-fi] {
+			s -= v * v
+		}
+		if !(s > 0) {
+			return false
+		}
+		li[i-fi] = math.Sqrt(s)
+		g.logDet += 2 * math.Log(li[i-fi])
+	}
+	return true
+}
+
+// reverseCuthillMcKee returns a reverse Cuthill-McKee ordering of the
+// variables of s, reducing the bandwidth of the reordered matrix.
+func reverseCuthillMcKee(s *SparseSymmetric) []int {
+	n := s.n
+	deg := make([]int, n)
+	for i, r := range s.rows {
+		deg[i] = len(r)
+		if _, ok := r[i]; ok {
+			deg[i]--
+		}
+	}
+	byDegree := func(nodes []int) {
+		sort.Slice(nodes, func(a, b int) bool {
+			if deg[nodes[a]] != deg[nodes[b]] {
+				return deg[nodes[a]] < deg[nodes[b]]
+			}
+			return nodes[a] < nodes[b]
+		})
+	}
+	starts := make([]int, n)
+	for i := range starts {
+		starts[i] = i
+	}
+	byDegree(starts)
+
+	visited := make([]bool, n)
+	order := make([]int, 0, n)
+	var next []int
+	for _, start := range starts {
+		if visited[start] {
+			continue
+		}
+		visited[start] = true
+		order = append(order, start)
+		for head := len(order) - 1; head < len(order); head++ {
+			next = next[:0]
+			for j := range s.rows[order[head]] {
+				if !visited[j] {
+					visited[j] = true
+					next = append(next, j)
+				}
+			}
+			byDegree(next)
+			order = append(order, next...)
+		}
+	}
+	for i, j := 0, n-1; i < j; i, j = i+1, j-1 {
+		order[i], order[j] = order[j], order[i]
+	}
+	return order
+}
+
+// solveLower solves L x = b in place, in the factorization ordering.
+func (g *GMRF) solveLower(b []float64) {
+	for i, li := range g.l {
+		fi := g.first[i]
+		s := b[i]
+		for k, v := range li[:i-fi] {
+			s -= v * b[k+fi]
+		}
+		b[i] = s / li[i-fi]
+	}
+}
+
+// solveUpper solves L^T x = b in place, in the factorization ordering.
+func (g *GMRF) solveUpper(b []float64) {
+	for i := len(g.l) - 1; i >= 0; i-- {
+		li := g.l[i]
+		fi := g.first[i]
+		b[i] /= li[i-fi]
+		for k, v := range li[:i-fi] {
+			b[k+fi] -= v * b[i]
+		}
+	}
+}
+
+// ConditionGMRF returns the GMRF that is the receiver conditioned on the input
+// evidence. The returned GMRF has dimension n - len(observed), where n is the
+// dimension of the original receiver. The precision matrix of the conditional
+// distribution is Q_{un,un}, the unobserved subset of the precision matrix,
+// and its mean is
+//  mu = mu_un - Q_{un,un}^-1 * Q_{un,ob} (v - mu_ob)
+// where mu_un and mu_ob are the original means of the unobserved and observed
+// variables respectively, Q_{un,ob} are the cross terms of the precision matrix,
+// and the observed variables have values v. The sparsity of the precision matrix
+// is preserved by conditioning. The dimension order is preserved during
+// conditioning, so if the value of dimension 1 is observed, the returned GMRF
+// represents dimensions {0, 2, ...} of the original GMRF.
+//
+// ConditionGMRF panics if no dimension or every dimension is observed, if an
+// observed dimension is out of bounds or repeated, or if len(observed) is not
+// equal to len(values). ConditionGMRF returns {nil, false} if there is a failure
+// during the update. Mathematically this is impossible, but can occur with finite
+// precision arithmetic.
+func (g *GMRF) ConditionGMRF(observed []int, values []float64, src *rand.Rand) (*GMRF, bool) {
+	if len(observed) == 0 {
+		panic("distmv: no observed value")
+	}
+	if len(observed) != len(values) {
+		panic(badInputLength)
+	}
+	obs := make(map[int]float64, len(observed))
+	for i, v := range observed {
+		if v < 0 || v >= g.dim {
+			panic("distmv: observed value out of bounds")
+		}
+		if _, ok := obs[v]; ok {
+			panic("distmv: repeated observed value")
+		}
+		obs[v] = values[i] - g.mu[v]
+	}
+	if len(obs) == g.dim {
+		panic("distmv: all dimensions observed")
+	}
+
+	index := make([]int, g.dim)
+	var un []int
+	for i := 0; i < g.dim; i++ {
+		if _, ok := obs[i]; ok {
+			index[i] = -1
+			continue
+		}
+		index[i] = len(un)
+		un = append(un, i)
+	}
+
+	prec := NewSparseSymmetric(len(un))
+	mu := make([]float64, len(un))
+	rhs := make([]float64, len(un))
+	for i, p := range un {
+		mu[i] = g.mu[p]
+		for j, v := range g.prec.rows[p] {
+			if k := index[j]; k >= 0 {
+				prec.rows[i][k] = v
+			} else {
+				rhs[i] -= v * obs[j]
+			}
+		}
+	}
+
+	c := &GMRF{mu: mu, prec: prec, dim: len(un), src: src}
+	if !c.factorize() {
+		return nil, false
+	}
+	b := make([]float64, c.dim)
+	for k, p := range c.perm {
+		b[k] = rhs[p]
+	}
+	c.solveLower(b)
+	c.solveUpper(b)
+	for k, p := range c.perm {
+		c.mu[p] += b[k]
+	}
+	return c, true
+}
+
+// Dim returns the dimension of the distribution.
+func (g *GMRF) Dim() int {
+	return g.dim
+}
+
+// LogProb computes the log of the pdf of the point x.
+func (g *GMRF) LogProb(x []float64) float64 {
+	if len(x) != g.dim {
+		panic(badSizeMismatch)
+	}
+	var quad float64
+	for i, r := range g.prec.rows {
+		di := x[i] - g.mu[i]
+		for j, v := range r {
+			quad += di * v * (x[j] - g.mu[j])
+		}
+	}
+	return -0.5*float64(g.dim)*logTwoPi + 0.5*g.logDet - 0.5*quad
+}
+
+// Mean returns the mean of the probability distribution at x. If the
+// input argument is nil, a new slice will be allocated, otherwise the result
+// will be put in-place into the receiver.
+func (g *GMRF) Mean(x []float64) []float64 {
+	x = reuseAs(x, g.dim)
+	copy(x, g.mu)
+	return x
+}
+
+// PrecisionMatrix returns a copy of the sparse precision matrix of the
+// distribution.
+func (g *GMRF) PrecisionMatrix() *SparseSymmetric {
+	return g.prec.clone()
+}
+
+// Prob computes the value of the probability density function at x.
+func (g *GMRF) Prob(x []float64) float64 {
+	return math.Exp(g.LogProb(x))
+}
+
+// Rand generates a random number according to the distribution.
+// If the input slice is nil, new memory is allocated, otherwise the result is stored
+// in place.
+//
+// Samples are drawn by solving L^T z = e, where L is the sparse Cholesky
+// factor of the precision matrix and e is a vector of independent standard
+// normal variates.
+func (g *GMRF) Rand(x []float64) []float64 {
+	x = reuseAs(x, g.dim)
+	z := make([]float64, g.dim)
+	if g.src == nil {
+		for i := range z {
+			z[i] = rand.NormFloat64()
+		}
+	} else {
+		for i := range z {
+			z[i] = g.src.NormFloat64()
+		}
+	}
+	g.solveUpper(z)
+	for k, p := range g.perm {
+		x[p] = g.mu[p] + z[k]
+	}
+	return x
+}
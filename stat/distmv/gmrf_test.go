@@ -0,0 +1,276 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package distmv
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/stat"
+)
+
+// latticePrecision returns the precision matrix of a first order intrinsic
+// autoregression on an r×c lattice with additional diagonal weight delta.
+func latticePrecision(r, c int, kappa, delta float64) *SparseSymmetric {
+	q := NewSparseSymmetric(r * c)
+	for i := 0; i < r; i++ {
+		for j := 0; j < c; j++ {
+			u := i*c + j
+			q.SetSym(u, u, q.At(u, u)+kappa*delta)
+			if j+1 < c {
+				v := u + 1
+				q.SetSym(u, v, -kappa)
+				q.SetSym(u, u, q.At(u, u)+kappa)
+				q.SetSym(v, v, q.At(v, v)+kappa)
+			}
+			if i+1 < r {
+				v := u + c
+				q.SetSym(u, v, -kappa)
+				q.SetSym(u, u, q.At(u, u)+kappa)
+				q.SetSym(v, v, q.At(v, v)+kappa)
+			}
+		}
+	}
+	return q
+}
+
+func TestSparseSymmetric(t *testing.T) {
+	s := NewSparseSymmetric(3)
+	s.SetSym(0, 2, 4)
+	s.SetSym(1, 1, 2)
+	if s.At(2, 0) != 4 || s.At(0, 2) != 4 || s.At(1, 1) != 2 || s.At(0, 1) != 0 {
+		t.Errorf("unexpected elements: %v", mat.Formatted(s))
+	}
+	if s.NonZero() != 3 {
+		t.Errorf("unexpected number of non-zero elements: got:%d want:3", s.NonZero())
+	}
+	s.SetSym(2, 0, 0)
+	if s.At(0, 2) != 0 || s.NonZero() != 1 {
+		t.Errorf("unexpected elements after removal: %v", mat.Formatted(s))
+	}
+	if !panics(func() { s.At(3, 0) }) {
+		t.Error("expected panic for out of range index")
+	}
+	if !panics(func() { NewSparseSymmetric(0) }) {
+		t.Error("expected panic for zero dimension")
+	}
+}
+
+func TestGMRF(t *testing.T) {
+	const r, c = 4, 5
+	q := latticePrecision(r, c, 2, 0.3)
+	dim := q.Symmetric()
+	rnd := rand.New(rand.NewSource(1))
+	mu := make([]float64, dim)
+	for i := range mu {
+		mu[i] = rnd.NormFloat64()
+	}
+	g, ok := NewGMRF(mu, q, nil)
+	if !ok {
+		t.Fatal("unexpected failure to construct GMRF")
+	}
+	if g.Dim() != dim {
+		t.Errorf("unexpected dimension: got:%d want:%d", g.Dim(), dim)
+	}
+	if !floats.Equal(g.Mean(nil), mu) {
+		t.Errorf("unexpected mean: got:%v want:%v", g.Mean(nil), mu)
+	}
+
+	// Changes to the input do not affect the distribution.
+	q.SetSym(0, 0, 100)
+	if got := g.PrecisionMatrix().At(0, 0); got == 100 {
+		t.Error("precision matrix not copied")
+	}
+	q.SetSym(0, 0, 2*(2+0.3))
+
+	dense := mat.NewSymDense(dim, nil)
+	dense.CopySym(q)
+	norm, ok := NewNormalPrecision(mu, dense, nil)
+	if !ok {
+		t.Fatal("unexpected failure to construct Normal")
+	}
+	x := make([]float64, dim)
+	for trial := 0; trial < 10; trial++ {
+		for i := range x {
+			x[i] = mu[i] + rnd.NormFloat64()
+		}
+		got := g.LogProb(x)
+		want := norm.LogProb(x)
+		if math.Abs(got-want) > 1e-10 {
+			t.Errorf("unexpected log probability: got:%v want:%v", got, want)
+		}
+		if math.Abs(g.Prob(x)-math.Exp(want)) > 1e-10*math.Exp(want) {
+			t.Errorf("unexpected probability: got:%v want:%v", g.Prob(x), math.Exp(want))
+		}
+	}
+
+	// The envelope of the reordered lattice is much smaller
+	// than the dense lower triangle.
+	var env int
+	for _, li := range g.l {
+		env += len(li)
+	}
+	if env >= dim*(dim+1)/2 {
+		t.Errorf("unexpectedly large envelope: got:%d dense:%d", env, dim*(dim+1)/2)
+	}
+
+	// Indefinite precision matrices are rejected.
+	bad := NewSparseSymmetric(2)
+	bad.SetSym(0, 0, 1)
+	bad.SetSym(1, 1, 1)
+	bad.SetSym(0, 1, 2)
+	if _, ok := NewGMRF([]float64{0, 0}, bad, nil); ok {
+		t.Error("expected failure for indefinite precision")
+	}
+	if !panics(func() { NewGMRF([]float64{0}, q, nil) }) {
+		t.Error("expected panic for size mismatch")
+	}
+}
+
+func TestGMRFRand(t *testing.T) {
+	// A disconnected field checks the ordering of separate components.
+	q := latticePrecision(3, 3, 1, 0.5)
+	q2 := NewSparseSymmetric(11)
+	for i := 0; i < 9; i++ {
+		for j := 0; j < 9; j++ {
+			if v := q.At(i, j); v != 0 {
+				q2.SetSym(i, j, v)
+			}
+		}
+	}
+	q2.SetSym(9, 9, 2)
+	q2.SetSym(10, 10, 3)
+	q2.SetSym(9, 10, 1)
+	dim := q2.Symmetric()
+	mu := make([]float64, dim)
+	for i := range mu {
+		mu[i] = float64(i) - 5
+	}
+	g, ok := NewGMRF(mu, q2, rand.New(rand.NewSource(1)))
+	if !ok {
+		t.Fatal("unexpected failure to construct GMRF")
+	}
+
+	const n = 100000
+	x := mat.NewDense(n, dim, nil)
+	for i := 0; i < n; i++ {
+		g.Rand(x.RawRowView(i))
+	}
+	for j := 0; j < dim; j++ {
+		m := stat.Mean(mat.Col(nil, j, x), nil)
+		if math.Abs(m-mu[j]) > 0.02 {
+			t.Errorf("unexpected sample mean for dimension %d: got:%v want:%v", j, m, mu[j])
+		}
+	}
+	var cov mat.SymDense
+	stat.CovarianceMatrix(&cov, x, nil)
+	dense := mat.NewSymDense(dim, nil)
+	dense.CopySym(q2)
+	var chol mat.Cholesky
+	chol.Factorize(dense)
+	var want mat.SymDense
+	chol.InverseTo(&want)
+	if !mat.EqualApprox(&cov, &want, 0.02) {
+		t.Errorf("unexpected sample covariance:\ngot: %v\nwant:%v", mat.Formatted(&cov, mat.Prefix("     ")), mat.Formatted(&want, mat.Prefix("     ")))
+	}
+}
+
+func TestConditionGMRF(t *testing.T) {
+	const r, c = 4, 4
+	q := latticePrecision(r, c, 1.5, 0.2)
+	dim := q.Symmetric()
+	rnd := rand.New(rand.NewSource(1))
+	mu := make([]float64, dim)
+	for i := range mu {
+		mu[i] = rnd.NormFloat64()
+	}
+	g, ok := NewGMRF(mu, q, nil)
+	if !ok {
+		t.Fatal("unexpected failure to construct GMRF")
+	}
+	dense := mat.NewSymDense(dim, nil)
+	dense.CopySym(q)
+	norm, ok := NewNormalPrecision(mu, dense, nil)
+	if !ok {
+		t.Fatal("unexpected failure to construct Normal")
+	}
+
+	for _, observed := range [][]int{
+		{0},
+		{5, 1, 14},
+		{0, 3, 12, 15},
+		{4, 5, 6, 7, 8, 9, 10, 11},
+	} {
+		values := make([]float64, len(observed))
+		for i := range values {
+			values[i] = rnd.NormFloat64()
+		}
+		cg, ok := g.ConditionGMRF(observed, values, nil)
+		if !ok {
+			t.Fatalf("unexpected failure to condition on %v", observed)
+		}
+		cn, ok := norm.ConditionNormal(observed, values, nil)
+		if !ok {
+			t.Fatalf("unexpected failure to condition Normal on %v", observed)
+		}
+		if cg.Dim() != cn.Dim() {
+			t.Fatalf("unexpected dimension: got:%d want:%d", cg.Dim(), cn.Dim())
+		}
+		if !floats.EqualApprox(cg.Mean(nil), cn.Mean(nil), 1e-10) {
+			t.Errorf("unexpected conditional mean for %v:\ngot: %v\nwant:%v", observed, cg.Mean(nil), cn.Mean(nil))
+		}
+		prec := mat.NewSymDense(cg.Dim(), nil)
+		prec.CopySym(cg.PrecisionMatrix())
+		var chol mat.Cholesky
+		chol.Factorize(prec)
+		var got mat.SymDense
+		chol.InverseTo(&got)
+		want := mat.NewSymDense(cn.Dim(), nil)
+		cn.CovarianceMatrix(want)
+		if !mat.EqualApprox(&got, want, 1e-10) {
+			t.Errorf("unexpected conditional covariance for %v", observed)
+		}
+		x := cg.Mean(nil)
+		for i := range x {
+			x[i] += rnd.NormFloat64()
+		}
+		if got, want := cg.LogProb(x), cn.LogProb(x); math.Abs(got-want) > 1e-10 {
+			t.Errorf("unexpected conditional log probability for %v: got:%v want:%v", observed, got, want)
+		}
+	}
+
+	for _, test := range []struct {
+		observed []int
+		values   []float64
+	}{
+		{observed: nil, values: nil},
+		{observed: []int{0}, values: []float64{1, 2}},
+		{observed: []int{dim}, values: []float64{1}},
+		{observed: []int{1, 1}, values: []float64{1, 1}},
+	} {
+		if !panics(func() { g.ConditionGMRF(test.observed, test.values, nil) }) {
+			t.Errorf("expected panic for observed:%v values:%v", test.observed, test.values)
+		}
+	}
+	all := make([]int, dim)
+	for i := range all {
+		all[i] = i
+	}
+	if !panics(func() { g.ConditionGMRF(all, make([]float64, dim), nil) }) {
+		t.Error("expected panic for all dimensions observed")
+	}
+}
+
+func panics(fn func()) (ok bool) {
+	defer func() {
+		ok = recover() != nil
+	}()
+	fn()
+	return
+}
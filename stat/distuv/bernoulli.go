@@ -8,6 +8,8 @@ import (
 	"math"
 
 	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/stat"
 )
 
 // Bernoulli represents a random variable whose value is 1 with probability p and
@@ -47,6 +49,24 @@ func (b Bernoulli) ExKurtosis() float64 {
 	return (1 - 6*pq) / pq
 }
 
+// Fit sets the parameters of the probability distribution from the
+// data samples x with relative weights w. P is set to the maximum
+// likelihood estimate, the weighted proportion of samples equal to 1.
+// Fit will panic if a sample is not 0 or 1.
+// If weights is nil, then all the weights are 1.
+// If weights is not nil, then the len(weights) must equal len(samples).
+func (b *Bernoulli) Fit(samples, weights []float64) {
+	if weights != nil && len(samples) != len(weights) {
+		panic(badLength)
+	}
+	for _, x := range samples {
+		if x != 0 && x != 1 {
+			panic(badSupport)
+		}
+	}
+	b.P = stat.Mean(samples, weights)
+}
+
 // LogProb computes the natural logarithm of the value of the probability density function at x.
 func (b Bernoulli) LogProb(x float64) float64 {
 	if x == 0 {
@@ -58,6 +78,15 @@ func (b Bernoulli) LogProb(x float64) float64 {
 	return math.Inf(-1)
 }
 
+// MarshalParameters implements the ParameterMarshaler interface
+func (b Bernoulli) MarshalParameters(p []Parameter) {
+	if len(p) != b.NumParameters() {
+		panic(badLength)
+	}
+	p[0].Name = "P"
+	p[0].Value = b.P
+}
+
 // Mean returns the mean of the probability distribution.
 func (b Bernoulli) Mean() float64 {
 	return b.P
@@ -126,6 +155,17 @@ func (b Bernoulli) Survival(x float64) float64 {
 	return 1 - b.CDF(x)
 }
 
+// UnmarshalParameters implements the ParameterMarshaler interface
+func (b *Bernoulli) UnmarshalParameters(p []Parameter) {
+	if len(p) != b.NumParameters() {
+		panic(badLength)
+	}
+	if p[0].Name != "P" {
+		panic("bernoulli: " + panicNameMismatch)
+	}
+	b.P = p[0].Value
+}
+
 // Variance returns the variance of the probability distribution.
 func (b Bernoulli) Variance() float64 {
 	return b.P * (1 - b.P)
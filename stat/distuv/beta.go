@@ -10,6 +10,7 @@ import (
 	"golang.org/x/exp/rand"
 
 	"gonum.org/v1/gonum/mathext"
+	"gonum.org/v1/gonum/stat"
 )
 
 // Beta implements the Beta distribution, a two-parameter continuous distribution
@@ -48,6 +49,46 @@ func (b Beta) ExKurtosis() float64 {
 	return num / den
 }
 
+// Fit sets the parameters of the probability distribution from the
+// data samples x with relative weights w to their maximum likelihood
+// estimates. The likelihood is maximized using the Nelder–Mead method
+// starting from the method of moments estimates. Fit will panic if a
+// sample is not in (0, 1).
+// If weights is nil, then all the weights are 1.
+// If weights is not nil, then the len(weights) must equal len(samples).
+func (b *Beta) Fit(samples, weights []float64) {
+	if weights != nil && len(samples) != len(weights) {
+		panic(badLength)
+	}
+	for _, x := range samples {
+		if !(0 < x && x < 1) {
+			panic(badSupport)
+		}
+	}
+	mean, variance := stat.MeanVariance(samples, weights)
+	a, c := 1.0, 1.0
+	if f := mean*(1-mean)/variance - 1; f > 0 {
+		a, c = mean*f, (1-mean)*f
+	}
+	nll := func(p []float64) float64 {
+		d := Beta{Alpha: math.Exp(p[0]), Beta: math.Exp(p[1])}
+		var ll float64
+		for i, x := range samples {
+			w := 1.0
+			if weights != nil {
+				w = weights[i]
+			}
+			ll += w * d.LogProb(x)
+		}
+		if math.IsNaN(ll) {
+			return math.Inf(1)
+		}
+		return -ll
+	}
+	p := nelderMead(nll, []float64{math.Log(a), math.Log(c)}, []float64{0.1, 0.1})
+	b.Alpha, b.Beta = math.Exp(p[0]), math.Exp(p[1])
+}
+
 // LogProb computes the natural logarithm of the value of the probability
 // density function at x.
 func (b Beta) LogProb(x float64) float64 {
@@ -65,6 +106,17 @@ func (b Beta) LogProb(x float64) float64 {
 	return lab - la - lb + (b.Alpha-1)*math.Log(x) + (b.Beta-1)*math.Log(1-x)
 }
 
+// MarshalParameters implements the ParameterMarshaler interface
+func (b Beta) MarshalParameters(p []Parameter) {
+	if len(p) != b.NumParameters() {
+		panic(badLength)
+	}
+	p[0].Name = "Alpha"
+	p[0].Value = b.Alpha
+	p[1].Name = "Beta"
+	p[1].Value = b.Beta
+}
+
 // Mean returns the mean of the probability distribution.
 func (b Beta) Mean() float64 {
 	return b.Alpha / (b.Alpha + b.Beta)
@@ -121,6 +173,21 @@ func (b Beta) Survival(x float64) float64 {
 	return mathext.RegIncBeta(b.Beta, b.Alpha, 1-x)
 }
 
+// UnmarshalParameters implements the ParameterMarshaler interface
+func (b *Beta) UnmarshalParameters(p []Parameter) {
+	if len(p) != b.NumParameters() {
+		panic(badLength)
+	}
+	if p[0].Name != "Alpha" {
+		panic("beta: " + panicNameMismatch)
+	}
+	if p[1].Name != "Beta" {
+		panic("beta: " + panicNameMismatch)
+	}
+	b.Alpha = p[0].Value
+	b.Beta = p[1].Value
+}
+
 // Variance returns the variance of the probability distribution.
 func (b Beta) Variance() float64 {
 	return b.Alpha * b.Beta / ((b.Alpha + b.Beta) * (b.Alpha + b.Beta) * (b.Alpha + b.Beta + 1))
@@ -85,6 +85,14 @@ func (e Exponential) LogProb(x float64) float64 {
 	return math.Log(e.Rate) - e.Rate*x
 }
 
+// MarshalParameters implements the ParameterMarshaler interface
+func (e Exponential) MarshalParameters(p []Parameter) {
+	if len(p) != e.NumParameters() {
+		panic(badLength)
+	}
+	e.parameters(p)
+}
+
 // Mean returns the mean of the probability distribution.
 func (e Exponential) Mean() float64 {
 	return 1 / e.Rate
@@ -241,6 +249,11 @@ func (e *Exponential) setParameters(p []Parameter) {
 	e.Rate = p[0].Value
 }
 
+// UnmarshalParameters implements the ParameterMarshaler interface
+func (e *Exponential) UnmarshalParameters(p []Parameter) {
+	e.setParameters(p)
+}
+
 // Variance returns the variance of the probability distribution.
 func (e Exponential) Variance() float64 {
 	return 1 / (e.Rate * e.Rate)
@@ -0,0 +1,149 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package distuv
+
+import (
+	"math"
+
+	"gonum.org/v1/gonum/diff/fd"
+	"gonum.org/v1/gonum/mat"
+)
+
+// ParameterizedLogProber is a distribution with a log probability density
+// and parameters that can be marshaled and unmarshaled.
+type ParameterizedLogProber interface {
+	LogProber
+	ParameterMarshaler
+}
+
+// FitStdErr returns the standard errors of the maximum likelihood estimates
+// of the parameters of d from the data samples x with relative weights w. The
+// standard errors are the square roots of the diagonal of the inverse of the
+// observed information matrix, the negative Hessian of the log-likelihood of
+// the samples with respect to the parameters at the parameters of d, which is
+// approximated by central finite differences. The returned parameters hold
+// the names of the parameters of d and their standard errors. If the observed
+// information matrix is not positive definite, the standard errors are NaN.
+//
+// The parameters of d are usually first set by its Fit method. The weights
+// are treated as frequencies, so they should be counts for the standard
+// errors to be meaningful. The parameters of d are modified during the call
+// and restored before FitStdErr returns. The standard errors are not
+// meaningful for parameters that determine the support of the distribution,
+// such as the bounds of a Uniform distribution.
+// If weights is nil, then all the weights are 1.
+// If weights is not nil, then the len(weights) must equal len(samples).
+func FitStdErr(d ParameterizedLogProber, samples, weights []float64) []Parameter {
+	if weights != nil && len(samples) != len(weights) {
+		panic(badLength)
+	}
+	n := d.NumParameters()
+	orig := make([]Parameter, n)
+	d.MarshalParameters(orig)
+	defer d.UnmarshalParameters(orig)
+
+	// The Hessian is found with respect to the parameters
+	// scaled by their magnitudes so that a single step
+	// size is suitable for all of them.
+	scale := make([]float64, n)
+	for i, p := range orig {
+		scale[i] = math.Abs(p.Value)
+		if scale[i] == 0 {
+			scale[i] = 1
+		}
+	}
+	p := make([]Parameter, n)
+	copy(p, orig)
+	logLike := func(u []float64) float64 {
+		for i := range p {
+			p[i].Value = orig[i].Value + scale[i]*u[i]
+		}
+		d.UnmarshalParameters(p)
+		var ll float64
+		for i, x := range samples {
+			w := 1.0
+			if weights != nil {
+				w = weights[i]
+			}
+			ll += w * d.LogProb(x)
+		}
+		return ll
+	}
+	hess := fd.Hessian(nil, logLike, make([]float64, n), &fd.Settings{Formula: fd.Central})
+	info := mat.NewSymDense(n, nil)
+	for i := 0; i < n; i++ {
+		for j := i; j < n; j++ {
+			info.SetSym(i, j, -hess.At(i, j)/(scale[i]*scale[j]))
+		}
+	}
+
+	se := make([]Parameter, n)
+	copy(se, orig)
+	var chol mat.Cholesky
+	cov := mat.NewSymDense(n, nil)
+	ok := chol.Factorize(info)
+	if ok {
+		err := chol.InverseTo(cov)
+		if err != nil {
+			_, ok = err.(mat.Condition)
+		}
+	}
+	for i := range se {
+		if ok {
+			se[i].Value = math.Sqrt(cov.At(i, i))
+		} else {
+			se[i].Value = math.NaN()
+		}
+	}
+	return se
+}
+
+// logSamples returns the logarithms of the samples, which must be positive.
+func logSamples(samples []float64) []float64 {
+	l := make([]float64, len(samples))
+	for i, x := range samples {
+		if !(x > 0) {
+			panic(badSupport)
+		}
+		l[i] = math.Log(x)
+	}
+	return l
+}
+
+// findRoot returns a root of the continuous monotonic function f found by
+// expanding an interval about x0 until f changes sign and then bisecting.
+func findRoot(f func(float64) float64, x0 float64) float64 {
+	lo, hi := x0-1, x0+1
+	flo, fhi := f(lo), f(hi)
+	for step := 2.0; (flo > 0) == (fhi > 0); step *= 2 {
+		if step > 1e10 {
+			panic("distuv: no root found")
+		}
+		// The root of a monotonic function is beyond
+		// the end of the interval closer to zero.
+		if math.Abs(flo) < math.Abs(fhi) {
+			hi, fhi = lo, flo
+			lo -= step
+			flo = f(lo)
+		} else {
+			lo, flo = hi, fhi
+			hi += step
+			fhi = f(hi)
+		}
+	}
+	for i := 0; i < 200; i++ {
+		mid := lo + (hi-lo)/2
+		if mid == lo || mid == hi {
+			break
+		}
+		fmid := f(mid)
+		if (fmid > 0) == (flo > 0) {
+			lo, flo = mid, fmid
+		} else {
+			hi = mid
+		}
+	}
+	return lo + (hi-lo)/2
+}
@@ -0,0 +1,234 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package distuv
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/stat"
+)
+
+type fitDist interface {
+	Rander
+	Fitter
+	ParameterizedLogProber
+}
+
+func TestFit(t *testing.T) {
+	src := rand.New(rand.NewSource(1))
+	for i, test := range []struct {
+		want, got fitDist
+		tol       float64
+	}{
+		{want: &Bernoulli{P: 0.3, Src: src}, got: &Bernoulli{}, tol: 0.05},
+		{want: &Beta{Alpha: 2, Beta: 5, Src: src}, got: &Beta{}, tol: 0.05},
+		{want: &Beta{Alpha: 0.5, Beta: 0.8, Src: src}, got: &Beta{}, tol: 0.05},
+		{want: &Exponential{Rate: 3, Src: src}, got: &Exponential{}, tol: 0.05},
+		{want: &Gamma{Alpha: 0.7, Beta: 2, Src: src}, got: &Gamma{}, tol: 0.05},
+		{want: &Gamma{Alpha: 20, Beta: 0.5, Src: src}, got: &Gamma{}, tol: 0.05},
+		{want: &LogNormal{Mu: 1, Sigma: 0.5, Src: src}, got: &LogNormal{}, tol: 0.05},
+		{want: &Normal{Mu: -1, Sigma: 2, Src: src}, got: &Normal{}, tol: 0.05},
+		{want: &Pareto{Xm: 2, Alpha: 3, Src: src}, got: &Pareto{}, tol: 0.05},
+		{want: &Poisson{Lambda: 4, Src: src}, got: &Poisson{}, tol: 0.05},
+		{want: &StudentsT{Mu: 1, Sigma: 2, Nu: 4, Src: src}, got: &StudentsT{}, tol: 0.1},
+		{want: &Weibull{K: 1.5, Lambda: 3, Src: src}, got: &Weibull{}, tol: 0.05},
+		{want: &Weibull{K: 0.5, Lambda: 1e-3, Src: src}, got: &Weibull{}, tol: 0.05},
+	} {
+		const n = 10000
+		x := make([]float64, n)
+		generateSamples(x, test.want)
+		test.got.Fit(x, nil)
+
+		want := make([]Parameter, test.want.NumParameters())
+		got := make([]Parameter, test.got.NumParameters())
+		test.want.MarshalParameters(want)
+		test.got.MarshalParameters(got)
+		for j := range want {
+			if !floats.EqualWithinRel(got[j].Value, want[j].Value, test.tol) {
+				t.Errorf("unexpected %s for case %d %T: got:%v want:%v", want[j].Name, i, test.got, got[j].Value, want[j].Value)
+			}
+		}
+
+		// The fitted parameters maximize the likelihood.
+		ll := func() float64 {
+			var ll float64
+			for _, v := range x {
+				ll += test.got.LogProb(v)
+			}
+			return ll
+		}
+		best := ll()
+		perturbed := make([]Parameter, len(got))
+		for j := range got {
+			if _, ok := test.got.(*Pareto); ok && j == 0 {
+				// Xm is on the boundary of the support.
+				continue
+			}
+			for _, f := range []float64{0.99, 1.01} {
+				copy(perturbed, got)
+				perturbed[j].Value *= f
+				test.got.UnmarshalParameters(perturbed)
+				if l := ll(); l > best {
+					t.Errorf("likelihood increased for case %d %T when scaling %s by %v: %v > %v", i, test.got, got[j].Name, f, l, best)
+				}
+			}
+		}
+		test.got.UnmarshalParameters(got)
+	}
+
+	// Weights are equivalent to repeated samples.
+	x := []float64{0.5, 1, 1.5, 2, 4}
+	w := []float64{1, 2, 1, 3, 1}
+	var rep []float64
+	for i, v := range x {
+		for j := 0; j < int(w[i]); j++ {
+			rep = append(rep, v)
+		}
+	}
+	for _, d := range []struct{ weighted, repeated fitDist }{
+		{&Gamma{}, &Gamma{}},
+		{&LogNormal{}, &LogNormal{}},
+		{&Weibull{}, &Weibull{}},
+		{&Pareto{}, &Pareto{}},
+	} {
+		d.weighted.Fit(x, w)
+		d.repeated.Fit(rep, nil)
+		a := make([]Parameter, d.weighted.NumParameters())
+		b := make([]Parameter, d.repeated.NumParameters())
+		d.weighted.MarshalParameters(a)
+		d.repeated.MarshalParameters(b)
+		for j := range a {
+			if !floats.EqualWithinAbsOrRel(a[j].Value, b[j].Value, 1e-10, 1e-10) {
+				t.Errorf("weighted fit mismatch for %T %s: got:%v want:%v", d.weighted, a[j].Name, a[j].Value, b[j].Value)
+			}
+		}
+	}
+
+	var u Uniform
+	u.Fit([]float64{3, -1, 2, 7, 10}, []float64{1, 1, 1, 1, 0})
+	if u.Min != -1 || u.Max != 7 {
+		t.Errorf("unexpected uniform fit: got:[%v, %v] want:[-1, 7]", u.Min, u.Max)
+	}
+
+	if !panics(func() { (&Gamma{}).Fit([]float64{1, -1}, nil) }) {
+		t.Error("expected panic for sample outside support")
+	}
+	if !panics(func() { (&Gamma{}).Fit([]float64{2, 2}, nil) }) {
+		t.Error("expected panic for equal samples")
+	}
+	if !panics(func() { (&Bernoulli{}).Fit([]float64{0, 0.5}, nil) }) {
+		t.Error("expected panic for non-binary sample")
+	}
+}
+
+func TestFitStdErr(t *testing.T) {
+	src := rand.New(rand.NewSource(1))
+	const n = 1000
+	x := make([]float64, n)
+
+	// The standard errors agree with the closed forms.
+	for i, test := range []struct {
+		d    fitDist
+		want func(d fitDist) []float64
+	}{
+		{
+			d: &Normal{Mu: 2, Sigma: 3, Src: src},
+			want: func(d fitDist) []float64 {
+				s := d.(*Normal).Sigma
+				return []float64{s / math.Sqrt(n), s / math.Sqrt(2*n)}
+			},
+		},
+		{
+			d: &Exponential{Rate: 5, Src: src},
+			want: func(d fitDist) []float64 {
+				return []float64{d.(*Exponential).Rate / math.Sqrt(n)}
+			},
+		},
+		{
+			d: &Poisson{Lambda: 7, Src: src},
+			want: func(d fitDist) []float64 {
+				return []float64{math.Sqrt(d.(*Poisson).Lambda / n)}
+			},
+		},
+		{
+			d: &Bernoulli{P: 0.2, Src: src},
+			want: func(d fitDist) []float64 {
+				p := d.(*Bernoulli).P
+				return []float64{math.Sqrt(p * (1 - p) / n)}
+			},
+		},
+		{
+			d: &LogNormal{Mu: 1, Sigma: 0.25, Src: src},
+			want: func(d fitDist) []float64 {
+				s := d.(*LogNormal).Sigma
+				return []float64{s / math.Sqrt(n), s / math.Sqrt(2*n)}
+			},
+		},
+	} {
+		generateSamples(x, test.d)
+		test.d.Fit(x, nil)
+		fitted := make([]Parameter, test.d.NumParameters())
+		test.d.MarshalParameters(fitted)
+
+		se := FitStdErr(test.d, x, nil)
+		want := test.want(test.d)
+		for j := range se {
+			if se[j].Name != fitted[j].Name {
+				t.Errorf("unexpected parameter name for case %d: got:%s want:%s", i, se[j].Name, fitted[j].Name)
+			}
+			if !floats.EqualWithinRel(se[j].Value, want[j], 1e-4) {
+				t.Errorf("unexpected standard error of %s for case %d: got:%v want:%v", se[j].Name, i, se[j].Value, want[j])
+			}
+		}
+		after := make([]Parameter, test.d.NumParameters())
+		test.d.MarshalParameters(after)
+		for j := range after {
+			if after[j] != fitted[j] {
+				t.Errorf("parameters not restored for case %d: got:%v want:%v", i, after, fitted)
+				break
+			}
+		}
+	}
+
+	// The standard errors agree with the spread of
+	// estimates from repeated samples.
+	for _, test := range []struct {
+		want fitDist
+		new  func() fitDist
+	}{
+		{want: &Gamma{Alpha: 2, Beta: 3, Src: src}, new: func() fitDist { return &Gamma{} }},
+		{want: &Weibull{K: 2, Lambda: 1, Src: src}, new: func() fitDist { return &Weibull{} }},
+		{want: &Beta{Alpha: 2, Beta: 3, Src: src}, new: func() fitDist { return &Beta{} }},
+	} {
+		const reps = 200
+		x := make([]float64, 200)
+		k := test.want.NumParameters()
+		est := make([][]float64, k)
+		var se []Parameter
+		for r := 0; r < reps; r++ {
+			generateSamples(x, test.want)
+			d := test.new()
+			d.Fit(x, nil)
+			p := make([]Parameter, k)
+			d.MarshalParameters(p)
+			for j := range p {
+				est[j] = append(est[j], p[j].Value)
+			}
+			if r == 0 {
+				se = FitStdErr(d, x, nil)
+			}
+		}
+		for j := range se {
+			sd := stat.StdDev(est[j], nil)
+			if !floats.EqualWithinRel(se[j].Value, sd, 0.25) {
+				t.Errorf("standard error of %T %s does not match spread of estimates: got:%v want:%v", test.want, se[j].Name, se[j].Value, sd)
+			}
+		}
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"golang.org/x/exp/rand"
 
 	"gonum.org/v1/gonum/mathext"
+	"gonum.org/v1/gonum/stat"
 )
 
 // Gamma implements the Gamma distribution, a two-parameter continuous distribution
@@ -43,6 +44,34 @@ func (g Gamma) ExKurtosis() float64 {
 	return 6 / g.Alpha
 }
 
+// Fit sets the parameters of the probability distribution from the
+// data samples x with relative weights w to their maximum likelihood
+// estimates. The shape Alpha is the solution of
+//  log(Alpha) - ψ(Alpha) = log(mean(x)) - mean(log(x)),
+// where ψ is the digamma function and the means are weighted, and the
+// rate is Beta = Alpha / mean(x). Fit will panic if a sample is not
+// positive or if all the samples are equal.
+// If weights is nil, then all the weights are 1.
+// If weights is not nil, then the len(weights) must equal len(samples).
+func (g *Gamma) Fit(samples, weights []float64) {
+	if weights != nil && len(samples) != len(weights) {
+		panic(badLength)
+	}
+	mean := stat.Mean(samples, weights)
+	s := math.Log(mean) - stat.Mean(logSamples(samples), weights)
+	if !(s > 0) {
+		panic("gamma: samples must not all be equal")
+	}
+	// Solve for log(Alpha) starting from the approximation
+	// of Minka, "Estimating a Gamma distribution" (2002).
+	a0 := (3 - s + math.Sqrt((s-3)*(s-3)+24*s)) / (12 * s)
+	t := findRoot(func(t float64) float64 {
+		return s - t + mathext.Digamma(math.Exp(t))
+	}, math.Log(a0))
+	g.Alpha = math.Exp(t)
+	g.Beta = g.Alpha / mean
+}
+
 // LogProb computes the natural logarithm of the value of the probability
 // density function at x.
 func (g Gamma) LogProb(x float64) float64 {
@@ -55,6 +84,17 @@ func (g Gamma) LogProb(x float64) float64 {
 	return a*math.Log(b) - lg + (a-1)*math.Log(x) - b*x
 }
 
+// MarshalParameters implements the ParameterMarshaler interface
+func (g Gamma) MarshalParameters(p []Parameter) {
+	if len(p) != g.NumParameters() {
+		panic(badLength)
+	}
+	p[0].Name = "Alpha"
+	p[0].Value = g.Alpha
+	p[1].Name = "Beta"
+	p[1].Value = g.Beta
+}
+
 // Mean returns the mean of the probability distribution.
 func (g Gamma) Mean() float64 {
 	return g.Alpha / g.Beta
@@ -239,6 +279,21 @@ func (g Gamma) StdDev() float64 {
 	return math.Sqrt(g.Variance())
 }
 
+// UnmarshalParameters implements the ParameterMarshaler interface
+func (g *Gamma) UnmarshalParameters(p []Parameter) {
+	if len(p) != g.NumParameters() {
+		panic(badLength)
+	}
+	if p[0].Name != "Alpha" {
+		panic("gamma: " + panicNameMismatch)
+	}
+	if p[1].Name != "Beta" {
+		panic("gamma: " + panicNameMismatch)
+	}
+	g.Alpha = p[0].Value
+	g.Beta = p[1].Value
+}
+
 // Variance returns the variance of the probability distribution.
 func (g Gamma) Variance() float64 {
 	return g.Alpha / g.Beta / g.Beta
@@ -17,6 +17,7 @@ var (
 	badLength     = "distuv: slice length mismatch"
 	badSuffStat   = "distuv: wrong suffStat length"
 	badNoSamples  = "distuv: must have at least one sample"
+	badSupport    = "distuv: sample outside support"
 )
 
 var (
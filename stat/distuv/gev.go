@@ -0,0 +1,338 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package distuv
+
+import (
+	"math"
+	"sort"
+
+	"golang.org/x/exp/rand"
+)
+
+// GeneralizedExtremeValue represents the generalized extreme value
+// distribution, the limiting distribution of normalized maxima of
+// independent samples. The cumulative distribution function is
+//  F(x) = exp(-(1 + Xi * (x-Mu)/Sigma)^(-1/Xi))
+// for Xi != 0 and
+//  F(x) = exp(-exp(-(x-Mu)/Sigma))
+// for Xi == 0. The distribution is the Gumbel distribution when Xi is zero,
+// the Fréchet distribution when Xi is positive and the reversed Weibull
+// distribution when Xi is negative.
+// More information at https://en.wikipedia.org/wiki/Generalized_extreme_value_distribution.
+type GeneralizedExtremeValue struct {
+	// Mu is the location of the distribution.
+	Mu float64
+	// Sigma is the scale of the distribution
+	// and must be positive.
+	Sigma float64
+	// Xi is the shape of the distribution.
+	Xi float64
+
+	Src *rand.Rand
+}
+
+// logT returns the logarithm of
+//  t(x) = (1 + Xi * (x-Mu)/Sigma)^(-1/Xi),
+// or -(x-Mu)/Sigma when Xi is zero, and whether x is in the support.
+func (g GeneralizedExtremeValue) logT(x float64) (float64, bool) {
+	z := (x - g.Mu) / g.Sigma
+	if g.Xi == 0 {
+		return -z, true
+	}
+	if 1+g.Xi*z <= 0 {
+		return 0, false
+	}
+	return -math.Log1p(g.Xi*z) / g.Xi, true
+}
+
+// CDF computes the value of the cumulative distribution function at x.
+func (g GeneralizedExtremeValue) CDF(x float64) float64 {
+	lt, ok := g.logT(x)
+	if !ok {
+		if g.Xi > 0 {
+			return 0
+		}
+		return 1
+	}
+	return math.Exp(-math.Exp(lt))
+}
+
+// Entropy returns the differential entropy of the distribution.
+func (g GeneralizedExtremeValue) Entropy() float64 {
+	return math.Log(g.Sigma) + eulerGamma*g.Xi + eulerGamma + 1
+}
+
+// ExKurtosis returns the excess kurtosis of the distribution.
+// The excess kurtosis is +Inf when Xi is at least 1/4.
+func (g GeneralizedExtremeValue) ExKurtosis() float64 {
+	switch {
+	case g.Xi == 0:
+		return 12.0 / 5
+	case g.Xi >= 0.25:
+		return math.Inf(1)
+	}
+	g1, g2, g3, g4 := g.gammas()
+	v := g2 - g1*g1
+	return (g4-4*g1*g3+6*g2*g1*g1-3*g1*g1*g1*g1)/(v*v) - 3
+}
+
+// gammas returns Γ(1 - k*Xi) for k = 1, ..., 4.
+func (g GeneralizedExtremeValue) gammas() (g1, g2, g3, g4 float64) {
+	return math.Gamma(1 - g.Xi), math.Gamma(1 - 2*g.Xi), math.Gamma(1 - 3*g.Xi), math.Gamma(1 - 4*g.Xi)
+}
+
+// Fit sets the parameters of the distribution to the maximum likelihood
+// estimates from the data samples x with relative weights w. The likelihood
+// is maximized using the Nelder–Mead method starting from the
+// probability-weighted moment estimates found by FitPWM.
+// If weights is nil, then all the weights are 1.
+// If weights is not nil, then the len(weights) must equal len(samples).
+// Fit will panic if there are fewer than three samples.
+func (g *GeneralizedExtremeValue) Fit(samples, weights []float64) {
+	if weights != nil && len(samples) != len(weights) {
+		panic(badLength)
+	}
+	g.FitPWM(samples)
+	if g.Sigma == 0 {
+		return
+	}
+	nll := func(p []float64) float64 {
+		d := GeneralizedExtremeValue{Mu: p[0], Sigma: math.Exp(p[1]), Xi: p[2]}
+		var ll float64
+		for i, x := range samples {
+			w := 1.0
+			if weights != nil {
+				w = weights[i]
+			}
+			ll += w * d.LogProb(x)
+		}
+		if math.IsNaN(ll) {
+			return math.Inf(1)
+		}
+		return -ll
+	}
+	p := nelderMead(nll, []float64{g.Mu, math.Log(g.Sigma), g.Xi}, []float64{0.1 * g.Sigma, 0.1, 0.1})
+	g.Mu, g.Sigma, g.Xi = p[0], math.Exp(p[1]), p[2]
+}
+
+// FitPWM sets the parameters of the distribution to the estimates from the
+// data samples found by the method of probability-weighted moments of
+// Hosking, Wallis and Wood. The estimates are robust for small samples and
+// are suitable starting points for maximum likelihood estimation. FitPWM will
+// panic if there are fewer than three samples.
+//
+// See Hosking, J. R. M., Wallis, J. R. and Wood, E. F. "Estimation of the
+// generalized extreme-value distribution by the method of probability-weighted
+// moments." Technometrics 27(3):251–261 (1985).
+func (g *GeneralizedExtremeValue) FitPWM(samples []float64) {
+	if len(samples) < 3 {
+		panic(badNoSamples)
+	}
+	b0, b1, b2 := pwm(samples)
+	l1 := b0
+	l2 := 2*b1 - b0
+	l3 := 6*b2 - 6*b1 + b0
+	if l2 <= 0 {
+		// All samples are equal.
+		g.Mu, g.Sigma, g.Xi = l1, 0, 0
+		return
+	}
+	t3 := l3 / l2
+	c := 2/(3+t3) - math.Ln2/math.Log(3)
+	k := 7.8590*c + 2.9554*c*c
+	if math.Abs(k) < 1e-8 {
+		g.Sigma = l2 / math.Ln2
+		g.Mu = l1 - eulerGamma*g.Sigma
+		g.Xi = 0
+		return
+	}
+	gk := math.Gamma(1 + k)
+	g.Sigma = l2 * k / (-math.Expm1(-k*math.Ln2) * gk)
+	g.Mu = l1 - g.Sigma*(1-gk)/k
+	g.Xi = -k
+}
+
+// LogProb computes the natural logarithm of the value of the probability
+// density function at x.
+func (g GeneralizedExtremeValue) LogProb(x float64) float64 {
+	lt, ok := g.logT(x)
+	if !ok {
+		return math.Inf(-1)
+	}
+	return -math.Log(g.Sigma) + (g.Xi+1)*lt - math.Exp(lt)
+}
+
+// MarshalParameters stores the named parameters of the distribution into p.
+// MarshalParameters panics if the length of p is not NumParameters.
+func (g GeneralizedExtremeValue) MarshalParameters(p []Parameter) {
+	if len(p) != g.NumParameters() {
+		panic(badLength)
+	}
+	p[0].Name = "Mu"
+	p[0].Value = g.Mu
+	p[1].Name = "Sigma"
+	p[1].Value = g.Sigma
+	p[2].Name = "Xi"
+	p[2].Value = g.Xi
+}
+
+// Mean returns the mean of the distribution.
+// The mean is +Inf when Xi is at least 1.
+func (g GeneralizedExtremeValue) Mean() float64 {
+	switch {
+	case g.Xi == 0:
+		return g.Mu + g.Sigma*eulerGamma
+	case g.Xi >= 1:
+		return math.Inf(1)
+	}
+	return g.Mu + g.Sigma*(math.Gamma(1-g.Xi)-1)/g.Xi
+}
+
+// Median returns the median of the distribution.
+func (g GeneralizedExtremeValue) Median() float64 {
+	return g.Quantile(0.5)
+}
+
+// Mode returns the mode of the distribution.
+func (g GeneralizedExtremeValue) Mode() float64 {
+	if g.Xi == 0 {
+		return g.Mu
+	}
+	return g.Mu + g.Sigma*math.Expm1(-g.Xi*math.Log1p(g.Xi))/g.Xi
+}
+
+// NumParameters returns the number of parameters in the distribution.
+func (GeneralizedExtremeValue) NumParameters() int {
+	return 3
+}
+
+// Prob computes the value of the probability density function at x.
+func (g GeneralizedExtremeValue) Prob(x float64) float64 {
+	return math.Exp(g.LogProb(x))
+}
+
+// Quantile returns the inverse of the cumulative distribution function.
+func (g GeneralizedExtremeValue) Quantile(p float64) float64 {
+	if p < 0 || 1 < p {
+		panic(badPercentile)
+	}
+	ly := math.Log(-math.Log(p))
+	if g.Xi == 0 {
+		return g.Mu - g.Sigma*ly
+	}
+	return g.Mu + g.Sigma*math.Expm1(-g.Xi*ly)/g.Xi
+}
+
+// Rand returns a random sample drawn from the distribution.
+func (g GeneralizedExtremeValue) Rand() float64 {
+	var u float64
+	if g.Src == nil {
+		u = rand.Float64()
+	} else {
+		u = g.Src.Float64()
+	}
+	return g.Quantile(u)
+}
+
+// ReturnLevel returns the level that is exceeded on average once every
+// period blocks when the distribution describes the maxima of blocks of
+// observations, such as annual maximum flows. The return level is the
+// 1 - 1/period quantile of the distribution. ReturnLevel will panic if
+// period is less than 1.
+func (g GeneralizedExtremeValue) ReturnLevel(period float64) float64 {
+	if period < 1 {
+		panic("distuv: return period less than one")
+	}
+	return g.Quantile(1 - 1/period)
+}
+
+// Skewness returns the skewness of the distribution.
+// The skewness is +Inf when Xi is at least 1/3.
+func (g GeneralizedExtremeValue) Skewness() float64 {
+	switch {
+	case g.Xi == 0:
+		// 12 * sqrt(6) * ζ(3) / π^3
+		return 1.1395470994046486
+	case g.Xi >= 1.0/3:
+		return math.Inf(1)
+	}
+	g1, g2, g3, _ := g.gammas()
+	s := (g3 - 3*g1*g2 + 2*g1*g1*g1) / math.Pow(g2-g1*g1, 1.5)
+	if g.Xi < 0 {
+		return -s
+	}
+	return s
+}
+
+// StdDev returns the standard deviation of the distribution.
+func (g GeneralizedExtremeValue) StdDev() float64 {
+	return math.Sqrt(g.Variance())
+}
+
+// Survival returns the survival function (complementary CDF) at x.
+func (g GeneralizedExtremeValue) Survival(x float64) float64 {
+	lt, ok := g.logT(x)
+	if !ok {
+		if g.Xi > 0 {
+			return 1
+		}
+		return 0
+	}
+	return -math.Expm1(-math.Exp(lt))
+}
+
+// UnmarshalParameters sets the parameters of the distribution from the named
+// parameters in p. UnmarshalParameters panics if the length of p is not
+// NumParameters or the names do not match those stored by MarshalParameters.
+func (g *GeneralizedExtremeValue) UnmarshalParameters(p []Parameter) {
+	if len(p) != g.NumParameters() {
+		panic(badLength)
+	}
+	if p[0].Name != "Mu" {
+		panic("gev: " + panicNameMismatch)
+	}
+	if p[1].Name != "Sigma" {
+		panic("gev: " + panicNameMismatch)
+	}
+	if p[2].Name != "Xi" {
+		panic("gev: " + panicNameMismatch)
+	}
+	g.Mu = p[0].Value
+	g.Sigma = p[1].Value
+	g.Xi = p[2].Value
+}
+
+// Variance returns the variance of the distribution.
+// The variance is +Inf when Xi is at least 1/2.
+func (g GeneralizedExtremeValue) Variance() float64 {
+	switch {
+	case g.Xi == 0:
+		return g.Sigma * g.Sigma * math.Pi * math.Pi / 6
+	case g.Xi >= 0.5:
+		return math.Inf(1)
+	}
+	g1, g2 := math.Gamma(1-g.Xi), math.Gamma(1-2*g.Xi)
+	return g.Sigma * g.Sigma * (g2 - g1*g1) / (g.Xi * g.Xi)
+}
+
+// pwm returns the unbiased estimates of the probability-weighted moments
+//  b_r = E[X * F(X)^r]
+// for r = 0, 1, 2 from the samples.
+func pwm(samples []float64) (b0, b1, b2 float64) {
+	x := samples
+	if !sort.Float64sAreSorted(x) {
+		x = make([]float64, len(samples))
+		copy(x, samples)
+		sort.Float64s(x)
+	}
+	n := float64(len(x))
+	for j, v := range x {
+		fj := float64(j)
+		b0 += v
+		b1 += fj / (n - 1) * v
+		b2 += fj * (fj - 1) / ((n - 1) * (n - 2)) * v
+	}
+	return b0 / n, b1 / n, b2 / n
+}
@@ -0,0 +1,143 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package distuv
+
+import (
+	"math"
+	"sort"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats"
+)
+
+func TestGeneralizedExtremeValue(t *testing.T) {
+	src := rand.New(rand.NewSource(1))
+	for i, test := range []struct {
+		mu, sigma, xi float64
+	}{
+		{mu: 0, sigma: 1, xi: 0},
+		{mu: 2, sigma: 0.5, xi: -0.3},
+		{mu: -1, sigma: 2, xi: 0.1},
+	} {
+		g := GeneralizedExtremeValue{Mu: test.mu, Sigma: test.sigma, Xi: test.xi, Src: src}
+		tol := 1e-2
+		const n = 1e6
+		x := make([]float64, n)
+		generateSamples(x, g)
+		sort.Float64s(x)
+
+		checkMean(t, i, x, g, tol)
+		checkVarAndStd(t, i, x, g, tol)
+		checkEntropy(t, i, x, g, tol)
+		checkExKurtosis(t, i, x, g, 0.2)
+		checkSkewness(t, i, x, g, 5e-2)
+		checkMedian(t, i, x, g, tol)
+		checkQuantileCDFSurvival(t, i, x, g, tol)
+		checkProbContinuous(t, i, x, g, 1e-10)
+		checkProbQuantContinuous(t, i, x, g, tol)
+
+		mode := g.Mode()
+		for _, d := range []float64{-1e-3, 1e-3} {
+			if g.Prob(mode+d) >= g.Prob(mode) {
+				t.Errorf("Mode is not a maximum of the density. Case %v: mode %v", i, mode)
+			}
+		}
+
+		if got, want := g.ReturnLevel(100), g.Quantile(0.99); got != want {
+			t.Errorf("Return level mismatch. Case %v: want %v, got %v", i, want, got)
+		}
+	}
+	if !panics(func() { GeneralizedExtremeValue{Sigma: 1}.ReturnLevel(0.5) }) {
+		t.Error("Expected panic for return period less than one")
+	}
+}
+
+func TestGeneralizedExtremeValueProb(t *testing.T) {
+	// The Gumbel distribution has CDF exp(-exp(-z)), and a GEV
+	// distribution with small shape is close to it.
+	gumbel := GeneralizedExtremeValue{Mu: 1, Sigma: 2}
+	near := GeneralizedExtremeValue{Mu: 1, Sigma: 2, Xi: 1e-10}
+	for _, x := range []float64{-3, 0, 1, 2.5, 10} {
+		z := (x - 1) / 2
+		want := math.Exp(-math.Exp(-z))
+		if got := gumbel.CDF(x); !floats.EqualWithinAbsOrRel(got, want, 1e-15, 1e-15) {
+			t.Errorf("Gumbel CDF mismatch at %v: want %v, got %v", x, want, got)
+		}
+		if got := near.CDF(x); !floats.EqualWithinAbsOrRel(got, want, 1e-8, 1e-8) {
+			t.Errorf("Near Gumbel CDF mismatch at %v: want %v, got %v", x, want, got)
+		}
+		if got, want := near.LogProb(x), gumbel.LogProb(x); !floats.EqualWithinAbsOrRel(got, want, 1e-8, 1e-8) {
+			t.Errorf("Near Gumbel LogProb mismatch at %v: want %v, got %v", x, want, got)
+		}
+	}
+
+	// Check the support bounds.
+	frechet := GeneralizedExtremeValue{Mu: 0, Sigma: 1, Xi: 0.5}
+	if frechet.CDF(-2.5) != 0 || frechet.Prob(-2.5) != 0 || frechet.Survival(-2.5) != 1 {
+		t.Error("Unexpected values below the lower bound of the support")
+	}
+	if q := frechet.Quantile(0); q != -2 {
+		t.Errorf("Unexpected lower bound: want -2, got %v", q)
+	}
+	weibull := GeneralizedExtremeValue{Mu: 0, Sigma: 1, Xi: -0.5}
+	if weibull.CDF(2.5) != 1 || weibull.Prob(2.5) != 0 || weibull.Survival(2.5) != 0 {
+		t.Error("Unexpected values above the upper bound of the support")
+	}
+	if q := weibull.Quantile(1); q != 2 {
+		t.Errorf("Unexpected upper bound: want 2, got %v", q)
+	}
+}
+
+func TestGeneralizedExtremeValueFit(t *testing.T) {
+	src := rand.New(rand.NewSource(1))
+	for i, test := range []struct {
+		mu, sigma, xi float64
+	}{
+		{mu: 0, sigma: 1, xi: 0},
+		{mu: 10, sigma: 3, xi: -0.2},
+		{mu: -5, sigma: 0.5, xi: 0.2},
+	} {
+		want := GeneralizedExtremeValue{Mu: test.mu, Sigma: test.sigma, Xi: test.xi, Src: src}
+		x := make([]float64, 10000)
+		generateSamples(x, want)
+
+		var pwm, ml GeneralizedExtremeValue
+		pwm.FitPWM(x)
+		ml.Fit(x, nil)
+		for _, fit := range []struct {
+			name string
+			got  GeneralizedExtremeValue
+		}{
+			{name: "PWM", got: pwm},
+			{name: "ML", got: ml},
+		} {
+			got := fit.got
+			if math.Abs(got.Mu-test.mu) > 0.05*test.sigma || math.Abs(got.Sigma-test.sigma) > 0.05*test.sigma || math.Abs(got.Xi-test.xi) > 0.05 {
+				t.Errorf("%s fit mismatch. Case %v: want %+v, got %+v", fit.name, i, want, got)
+			}
+		}
+
+		// The maximum likelihood estimate should have a
+		// likelihood at least as great as the PWM estimate.
+		var llPWM, llML float64
+		for _, v := range x {
+			llPWM += pwm.LogProb(v)
+			llML += ml.LogProb(v)
+		}
+		if llML < llPWM {
+			t.Errorf("ML likelihood less than PWM likelihood. Case %v: %v < %v", i, llML, llPWM)
+		}
+	}
+}
+
+func panics(fn func()) (panicked bool) {
+	defer func() {
+		panicked = recover() != nil
+	}()
+	fn()
+	return
+}
@@ -0,0 +1,280 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package distuv
+
+import (
+	"math"
+
+	"golang.org/x/exp/rand"
+)
+
+// GeneralizedPareto represents the generalized Pareto distribution, the
+// limiting distribution of exceedances of a high threshold. The cumulative
+// distribution function is
+//  F(x) = 1 - (1 + Xi * (x-Mu)/Sigma)^(-1/Xi)
+// for Xi != 0 and
+//  F(x) = 1 - exp(-(x-Mu)/Sigma)
+// for Xi == 0, for x >= Mu and, when Xi is negative, x <= Mu - Sigma/Xi.
+// More information at https://en.wikipedia.org/wiki/Generalized_Pareto_distribution.
+type GeneralizedPareto struct {
+	// Mu is the location of the distribution,
+	// the threshold in peaks-over-threshold
+	// analyses.
+	Mu float64
+	// Sigma is the scale of the distribution
+	// and must be positive.
+	Sigma float64
+	// Xi is the shape of the distribution.
+	Xi float64
+
+	Src *rand.Rand
+}
+
+// logSurvival returns the logarithm of the survival function at x for x
+// at or above Mu.
+func (g GeneralizedPareto) logSurvival(x float64) float64 {
+	z := (x - g.Mu) / g.Sigma
+	if g.Xi == 0 {
+		return -z
+	}
+	if 1+g.Xi*z <= 0 {
+		return math.Inf(-1)
+	}
+	return -math.Log1p(g.Xi*z) / g.Xi
+}
+
+// CDF computes the value of the cumulative distribution function at x.
+func (g GeneralizedPareto) CDF(x float64) float64 {
+	if x <= g.Mu {
+		return 0
+	}
+	return -math.Expm1(g.logSurvival(x))
+}
+
+// Entropy returns the differential entropy of the distribution.
+func (g GeneralizedPareto) Entropy() float64 {
+	return math.Log(g.Sigma) + g.Xi + 1
+}
+
+// ExKurtosis returns the excess kurtosis of the distribution.
+// The excess kurtosis is +Inf when Xi is at least 1/4.
+func (g GeneralizedPareto) ExKurtosis() float64 {
+	if g.Xi >= 0.25 {
+		return math.Inf(1)
+	}
+	xi := g.Xi
+	return 3*(1-2*xi)*(2*xi*xi+xi+3)/((1-3*xi)*(1-4*xi)) - 3
+}
+
+// Fit sets Sigma and Xi to the maximum likelihood estimates from the data
+// samples x with relative weights w, which must be exceedances of the
+// threshold Mu. Mu is not changed. The likelihood is maximized using the
+// Nelder–Mead method starting from the probability-weighted moment
+// estimates found by FitPWM.
+// If weights is nil, then all the weights are 1.
+// If weights is not nil, then the len(weights) must equal len(samples).
+// Fit will panic if there are fewer than two samples or if any sample is
+// less than Mu.
+func (g *GeneralizedPareto) Fit(samples, weights []float64) {
+	if weights != nil && len(samples) != len(weights) {
+		panic(badLength)
+	}
+	g.FitPWM(samples)
+	if g.Sigma == 0 {
+		return
+	}
+	nll := func(p []float64) float64 {
+		d := GeneralizedPareto{Mu: g.Mu, Sigma: math.Exp(p[0]), Xi: p[1]}
+		var ll float64
+		for i, x := range samples {
+			w := 1.0
+			if weights != nil {
+				w = weights[i]
+			}
+			ll += w * d.LogProb(x)
+		}
+		if math.IsNaN(ll) {
+			return math.Inf(1)
+		}
+		return -ll
+	}
+	p := nelderMead(nll, []float64{math.Log(g.Sigma), g.Xi}, []float64{0.1, 0.1})
+	g.Sigma, g.Xi = math.Exp(p[0]), p[1]
+}
+
+// FitPWM sets Sigma and Xi to the estimates from the data samples, which
+// must be exceedances of the threshold Mu, found by the method of
+// probability-weighted moments of Hosking and Wallis. Mu is not changed.
+// FitPWM will panic if there are fewer than two samples or if any sample is
+// less than Mu.
+//
+// See Hosking, J. R. M. and Wallis, J. R. "Parameter and quantile estimation
+// for the generalized Pareto distribution." Technometrics 29(3):339–349 (1987).
+func (g *GeneralizedPareto) FitPWM(samples []float64) {
+	if len(samples) < 2 {
+		panic(badNoSamples)
+	}
+	for _, x := range samples {
+		if x < g.Mu {
+			panic("distuv: sample below threshold")
+		}
+	}
+	// a_s = E[X (1-F(X))^s] = b_0 - s*b_1 for s = 0, 1.
+	b0, b1, _ := pwm(samples)
+	a0 := b0 - g.Mu
+	a1 := a0 - (b1 - g.Mu/2)
+	if a0 <= 0 {
+		// All samples are at the threshold.
+		g.Sigma, g.Xi = 0, 0
+		return
+	}
+	d := a0 - 2*a1
+	g.Sigma = 2 * a0 * a1 / d
+	g.Xi = 2 - a0/d
+}
+
+// LogProb computes the natural logarithm of the value of the probability
+// density function at x.
+func (g GeneralizedPareto) LogProb(x float64) float64 {
+	if x < g.Mu {
+		return math.Inf(-1)
+	}
+	ls := g.logSurvival(x)
+	if math.IsInf(ls, -1) {
+		return ls
+	}
+	// f(x) = S(x)^(1+Xi) / Sigma.
+	return -math.Log(g.Sigma) + (1+g.Xi)*ls
+}
+
+// MarshalParameters stores the named parameters of the distribution into p.
+// MarshalParameters panics if the length of p is not NumParameters.
+func (g GeneralizedPareto) MarshalParameters(p []Parameter) {
+	if len(p) != g.NumParameters() {
+		panic(badLength)
+	}
+	p[0].Name = "Mu"
+	p[0].Value = g.Mu
+	p[1].Name = "Sigma"
+	p[1].Value = g.Sigma
+	p[2].Name = "Xi"
+	p[2].Value = g.Xi
+}
+
+// Mean returns the mean of the distribution.
+// The mean is +Inf when Xi is at least 1.
+func (g GeneralizedPareto) Mean() float64 {
+	if g.Xi >= 1 {
+		return math.Inf(1)
+	}
+	return g.Mu + g.Sigma/(1-g.Xi)
+}
+
+// Median returns the median of the distribution.
+func (g GeneralizedPareto) Median() float64 {
+	return g.Quantile(0.5)
+}
+
+// Mode returns the mode of the distribution, which is Mu when Xi is at
+// least -1.
+func (g GeneralizedPareto) Mode() float64 {
+	return g.Mu
+}
+
+// NumParameters returns the number of parameters in the distribution.
+func (GeneralizedPareto) NumParameters() int {
+	return 3
+}
+
+// Prob computes the value of the probability density function at x.
+func (g GeneralizedPareto) Prob(x float64) float64 {
+	return math.Exp(g.LogProb(x))
+}
+
+// Quantile returns the inverse of the cumulative distribution function.
+func (g GeneralizedPareto) Quantile(p float64) float64 {
+	if p < 0 || 1 < p {
+		panic(badPercentile)
+	}
+	ls := math.Log1p(-p)
+	if g.Xi == 0 {
+		return g.Mu - g.Sigma*ls
+	}
+	return g.Mu + g.Sigma*math.Expm1(-g.Xi*ls)/g.Xi
+}
+
+// Rand returns a random sample drawn from the distribution.
+func (g GeneralizedPareto) Rand() float64 {
+	var u float64
+	if g.Src == nil {
+		u = rand.Float64()
+	} else {
+		u = g.Src.Float64()
+	}
+	return g.Quantile(u)
+}
+
+// ReturnLevel returns the level that is exceeded on average once every
+// period observations when the distribution describes the exceedances of
+// the threshold Mu and rate is the probability that an observation exceeds
+// the threshold. ReturnLevel will panic if period*rate is less than 1.
+func (g GeneralizedPareto) ReturnLevel(period, rate float64) float64 {
+	if period*rate < 1 {
+		panic("distuv: return period less than one")
+	}
+	return g.Quantile(1 - 1/(period*rate))
+}
+
+// Skewness returns the skewness of the distribution.
+// The skewness is +Inf when Xi is at least 1/3.
+func (g GeneralizedPareto) Skewness() float64 {
+	if g.Xi >= 1.0/3 {
+		return math.Inf(1)
+	}
+	return 2 * (1 + g.Xi) * math.Sqrt(1-2*g.Xi) / (1 - 3*g.Xi)
+}
+
+// StdDev returns the standard deviation of the distribution.
+func (g GeneralizedPareto) StdDev() float64 {
+	return math.Sqrt(g.Variance())
+}
+
+// Survival returns the survival function (complementary CDF) at x.
+func (g GeneralizedPareto) Survival(x float64) float64 {
+	if x <= g.Mu {
+		return 1
+	}
+	return math.Exp(g.logSurvival(x))
+}
+
+// UnmarshalParameters sets the parameters of the distribution from the named
+// parameters in p. UnmarshalParameters panics if the length of p is not
+// NumParameters or the names do not match those stored by MarshalParameters.
+func (g *GeneralizedPareto) UnmarshalParameters(p []Parameter) {
+	if len(p) != g.NumParameters() {
+		panic(badLength)
+	}
+	if p[0].Name != "Mu" {
+		panic("gpd: " + panicNameMismatch)
+	}
+	if p[1].Name != "Sigma" {
+		panic("gpd: " + panicNameMismatch)
+	}
+	if p[2].Name != "Xi" {
+		panic("gpd: " + panicNameMismatch)
+	}
+	g.Mu = p[0].Value
+	g.Sigma = p[1].Value
+	g.Xi = p[2].Value
+}
+
+// Variance returns the variance of the distribution.
+// The variance is +Inf when Xi is at least 1/2.
+func (g GeneralizedPareto) Variance() float64 {
+	if g.Xi >= 0.5 {
+		return math.Inf(1)
+	}
+	return g.Sigma * g.Sigma / ((1 - g.Xi) * (1 - g.Xi) * (1 - 2*g.Xi))
+}
@@ -0,0 +1,112 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package distuv
+
+import (
+	"math"
+	"sort"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/integrate/quad"
+)
+
+func TestGeneralizedPareto(t *testing.T) {
+	src := rand.New(rand.NewSource(1))
+	for i, test := range []struct {
+		mu, sigma, xi float64
+	}{
+		{mu: 0, sigma: 1, xi: 0},
+		{mu: 2, sigma: 0.5, xi: -0.3},
+		{mu: -1, sigma: 2, xi: 0.1},
+	} {
+		g := GeneralizedPareto{Mu: test.mu, Sigma: test.sigma, Xi: test.xi, Src: src}
+		tol := 1e-2
+		const n = 1e6
+		x := make([]float64, n)
+		generateSamples(x, g)
+		sort.Float64s(x)
+
+		checkMean(t, i, x, g, tol)
+		checkVarAndStd(t, i, x, g, tol)
+		checkEntropy(t, i, x, g, tol)
+		checkExKurtosis(t, i, x, g, 0.2)
+		checkSkewness(t, i, x, g, 5e-2)
+		checkMedian(t, i, x, g, tol)
+		checkQuantileCDFSurvival(t, i, x, g, tol)
+		// The density is discontinuous at Mu, so
+		// integrate over the support.
+		q := quad.Fixed(g.Prob, g.Mu, math.Inf(1), 1000000, nil, 0)
+		if math.Abs(q-1) > 1e-10 {
+			t.Errorf("Probability distribution doesn't integrate to 1. Case %v: Got %v", i, q)
+		}
+		checkProbQuantContinuous(t, i, x, g, tol)
+
+		if got, want := g.ReturnLevel(1000, 0.1), g.Quantile(0.99); !floats.EqualWithinAbsOrRel(got, want, 1e-14, 1e-14) {
+			t.Errorf("Return level mismatch. Case %v: want %v, got %v", i, want, got)
+		}
+	}
+
+	// The generalized Pareto distribution with zero
+	// shape is the exponential distribution.
+	g := GeneralizedPareto{Mu: 1, Sigma: 2}
+	e := Exponential{Rate: 0.5}
+	for _, x := range []float64{0.5, 1, 2, 5} {
+		if got, want := g.CDF(x), e.CDF(x-1); got != want && !floats.EqualWithinAbsOrRel(got, want, 1e-15, 1e-15) {
+			t.Errorf("Exponential CDF mismatch at %v: want %v, got %v", x, want, got)
+		}
+	}
+	if !panics(func() { g.ReturnLevel(5, 0.1) }) {
+		t.Error("Expected panic for return period less than one")
+	}
+}
+
+func TestGeneralizedParetoFit(t *testing.T) {
+	src := rand.New(rand.NewSource(1))
+	for i, test := range []struct {
+		mu, sigma, xi float64
+	}{
+		{mu: 0, sigma: 1, xi: 0},
+		{mu: 10, sigma: 3, xi: -0.2},
+		{mu: -5, sigma: 0.5, xi: 0.2},
+	} {
+		want := GeneralizedPareto{Mu: test.mu, Sigma: test.sigma, Xi: test.xi, Src: src}
+		x := make([]float64, 10000)
+		generateSamples(x, want)
+
+		pwm := GeneralizedPareto{Mu: test.mu}
+		pwm.FitPWM(x)
+		ml := GeneralizedPareto{Mu: test.mu}
+		ml.Fit(x, nil)
+		for _, fit := range []struct {
+			name string
+			got  GeneralizedPareto
+		}{
+			{name: "PWM", got: pwm},
+			{name: "ML", got: ml},
+		} {
+			got := fit.got
+			if got.Mu != test.mu || math.Abs(got.Sigma-test.sigma) > 0.05*test.sigma || math.Abs(got.Xi-test.xi) > 0.05 {
+				t.Errorf("%s fit mismatch. Case %v: want %+v, got %+v", fit.name, i, want, got)
+			}
+		}
+
+		var llPWM, llML float64
+		for _, v := range x {
+			llPWM += pwm.LogProb(v)
+			llML += ml.LogProb(v)
+		}
+		if llML < llPWM {
+			t.Errorf("ML likelihood less than PWM likelihood. Case %v: %v < %v", i, llML, llPWM)
+		}
+	}
+
+	g := GeneralizedPareto{Mu: 1}
+	if !panics(func() { g.FitPWM([]float64{2, 0.5, 3}) }) {
+		t.Error("Expected panic for sample below threshold")
+	}
+}
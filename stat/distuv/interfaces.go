@@ -20,3 +20,17 @@ type RandLogProber interface {
 type Quantiler interface {
 	Quantile(p float64) float64
 }
+
+type CDFer interface {
+	CDF(x float64) float64
+}
+
+type Fitter interface {
+	Fit(samples, weights []float64)
+}
+
+type ParameterMarshaler interface {
+	NumParameters() int
+	MarshalParameters([]Parameter)
+	UnmarshalParameters([]Parameter)
+}
@@ -8,6 +8,8 @@ import (
 	"math"
 
 	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/stat"
 )
 
 // LogNormal represents a random variable whose log is normally distributed.
@@ -35,6 +37,32 @@ func (l LogNormal) ExKurtosis() float64 {
 	return math.Exp(4*s2) + 2*math.Exp(3*s2) + 3*math.Exp(2*s2) - 6
 }
 
+// Fit sets the parameters of the probability distribution from the
+// data samples x with relative weights w. Mu and Sigma are set to the
+// maximum likelihood estimates, the weighted mean and the weighted
+// population standard deviation of the logarithms of the samples.
+// Fit will panic if a sample is not positive.
+// If weights is nil, then all the weights are 1.
+// If weights is not nil, then the len(weights) must equal len(samples).
+func (l *LogNormal) Fit(samples, weights []float64) {
+	if weights != nil && len(samples) != len(weights) {
+		panic(badLength)
+	}
+	logs := logSamples(samples)
+	mu := stat.Mean(logs, weights)
+	var ss, sum float64
+	for i, v := range logs {
+		w := 1.0
+		if weights != nil {
+			w = weights[i]
+		}
+		ss += w * (v - mu) * (v - mu)
+		sum += w
+	}
+	l.Mu = mu
+	l.Sigma = math.Sqrt(ss / sum)
+}
+
 // LogProb computes the natural logarithm of the value of the probability density function at x.
 func (l LogNormal) LogProb(x float64) float64 {
 	if x < 0 {
@@ -45,6 +73,17 @@ func (l LogNormal) LogProb(x float64) float64 {
 	return -0.5*normdiff*normdiff - logx - math.Log(l.Sigma) - logRoot2Pi
 }
 
+// MarshalParameters implements the ParameterMarshaler interface
+func (l LogNormal) MarshalParameters(p []Parameter) {
+	if len(p) != l.NumParameters() {
+		panic(badLength)
+	}
+	p[0].Name = "Mu"
+	p[0].Value = l.Mu
+	p[1].Name = "Sigma"
+	p[1].Value = l.Sigma
+}
+
 // Mean returns the mean of the probability distribution.
 func (l LogNormal) Mean() float64 {
 	return math.Exp(l.Mu + 0.5*l.Sigma*l.Sigma)
@@ -106,6 +145,21 @@ func (l LogNormal) Survival(x float64) float64 {
 	return 0.5 * (1 - math.Erf((math.Log(x)-l.Mu)/(math.Sqrt2*l.Sigma)))
 }
 
+// UnmarshalParameters implements the ParameterMarshaler interface
+func (l *LogNormal) UnmarshalParameters(p []Parameter) {
+	if len(p) != l.NumParameters() {
+		panic(badLength)
+	}
+	if p[0].Name != "Mu" {
+		panic("lognormal: " + panicNameMismatch)
+	}
+	if p[1].Name != "Sigma" {
+		panic("lognormal: " + panicNameMismatch)
+	}
+	l.Mu = p[0].Value
+	l.Sigma = p[1].Value
+}
+
 // Variance returns the variance of the probability distribution.
 func (l LogNormal) Variance() float64 {
 	s2 := l.Sigma * l.Sigma
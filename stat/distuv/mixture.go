@@ -0,0 +1,160 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package distuv
+
+import (
+	"math"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats"
+)
+
+// MixtureComponent is a continuous distribution that may be a component
+// of a Mixture.
+type MixtureComponent interface {
+	CDFer
+	LogProber
+	Quantiler
+	Rander
+}
+
+// Mixture represents a finite mixture of continuous distributions. The
+// density of the mixture is
+//  f(x) = \sum_i w_i f_i(x) / \sum_i w_i
+// where w_i is the weight and f_i is the density of the ith component.
+//
+// Weights must be non-negative with a positive sum and must have the same
+// length as Components. The weights need not sum to one.
+// More information at https://en.wikipedia.org/wiki/Mixture_distribution.
+type Mixture struct {
+	Weights    []float64
+	Components []MixtureComponent
+
+	// Src is the random source used by Rand
+	// to select a component. Samples from the
+	// selected component are drawn using the
+	// component's own Rand method.
+	Src *rand.Rand
+}
+
+// total returns the sum of the weights of m.
+func (m Mixture) total() float64 {
+	if len(m.Weights) != len(m.Components) {
+		panic(badLength)
+	}
+	var sum float64
+	for _, w := range m.Weights {
+		if w < 0 {
+			panic("distuv: negative mixture weight")
+		}
+		sum += w
+	}
+	if !(sum > 0) {
+		panic("distuv: mixture weights do not have a positive sum")
+	}
+	return sum
+}
+
+// CDF computes the value of the cumulative distribution function at x.
+func (m Mixture) CDF(x float64) float64 {
+	sum := m.total()
+	var cdf float64
+	for i, c := range m.Components {
+		if m.Weights[i] == 0 {
+			continue
+		}
+		cdf += m.Weights[i] * c.CDF(x)
+	}
+	return math.Min(1, cdf/sum)
+}
+
+// LogProb computes the natural logarithm of the value of the probability
+// density function at x.
+func (m Mixture) LogProb(x float64) float64 {
+	sum := m.total()
+	lp := make([]float64, 0, len(m.Components))
+	for i, c := range m.Components {
+		if m.Weights[i] == 0 {
+			continue
+		}
+		lp = append(lp, math.Log(m.Weights[i])+c.LogProb(x))
+	}
+	return floats.LogSumExp(lp) - math.Log(sum)
+}
+
+// Prob computes the value of the probability density function at x.
+func (m Mixture) Prob(x float64) float64 {
+	return math.Exp(m.LogProb(x))
+}
+
+// Quantile returns the inverse of the cumulative distribution function.
+// The quantile is found by bisection between the smallest and largest of
+// the corresponding quantiles of the components.
+func (m Mixture) Quantile(p float64) float64 {
+	if p < 0 || p > 1 {
+		panic(badPercentile)
+	}
+	m.total()
+	lo := math.Inf(1)
+	hi := math.Inf(-1)
+	for i, c := range m.Components {
+		if m.Weights[i] == 0 {
+			continue
+		}
+		q := c.Quantile(p)
+		lo = math.Min(lo, q)
+		hi = math.Max(hi, q)
+	}
+	if p == 0 {
+		return lo
+	}
+	if p == 1 || lo == hi {
+		return hi
+	}
+	// The CDF of the mixture is at most p at lo
+	// and at least p at hi.
+	for i := 0; i < 1100; i++ {
+		mid := lo + (hi-lo)/2
+		if mid == lo || mid == hi {
+			break
+		}
+		if m.CDF(mid) < p {
+			lo = mid
+		} else {
+			hi = mid
+		}
+	}
+	return hi
+}
+
+// Rand returns a random sample drawn from the distribution.
+func (m Mixture) Rand() float64 {
+	sum := m.total()
+	var rnd float64
+	if m.Src == nil {
+		rnd = rand.Float64()
+	} else {
+		rnd = m.Src.Float64()
+	}
+	rnd *= sum
+	last := -1
+	for i, w := range m.Weights {
+		if w == 0 {
+			continue
+		}
+		last = i
+		if rnd < w {
+			break
+		}
+		rnd -= w
+	}
+	return m.Components[last].Rand()
+}
+
+// Survival returns the survival function (complementary CDF) at x.
+func (m Mixture) Survival(x float64) float64 {
+	return 1 - m.CDF(x)
+}
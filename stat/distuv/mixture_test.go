@@ -0,0 +1,101 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package distuv
+
+import (
+	"math"
+	"sort"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats"
+)
+
+func TestMixture(t *testing.T) {
+	src := rand.New(rand.NewSource(1))
+	for i, m := range []Mixture{
+		{
+			Weights: []float64{1},
+			Components: []MixtureComponent{
+				Normal{Mu: 1, Sigma: 2, Src: src},
+			},
+		},
+		{
+			Weights: []float64{0.3, 0.7},
+			Components: []MixtureComponent{
+				Normal{Mu: -2, Sigma: 1, Src: src},
+				Normal{Mu: 3, Sigma: 0.5, Src: src},
+			},
+		},
+		{
+			Weights: []float64{2, 0, 1},
+			Components: []MixtureComponent{
+				Normal{Mu: -1, Sigma: 0.5, Src: src},
+				Normal{Mu: 100, Sigma: 1, Src: src},
+				Laplace{Mu: 2, Scale: 0.5, Src: src},
+			},
+		},
+	} {
+		m.Src = src
+		const tol = 1e-2
+		const n = 1e5
+		x := make([]float64, n)
+		generateSamples(x, m)
+		sort.Float64s(x)
+
+		checkQuantileCDFSurvival(t, i, x, m, tol)
+		checkProbContinuous(t, i, x, m, 1e-6)
+		checkProbQuantContinuous(t, i, x, m, tol)
+
+		// The distribution functions are weighted
+		// averages over the components.
+		sum := floats.Sum(m.Weights)
+		for _, v := range []float64{-3, -0.5, 0.5, 2.5, 3.5} {
+			var cdf, prob float64
+			for j, c := range m.Components {
+				cdf += m.Weights[j] / sum * c.CDF(v)
+				prob += m.Weights[j] / sum * math.Exp(c.LogProb(v))
+			}
+			if got := m.CDF(v); !floats.EqualWithinAbsOrRel(got, cdf, 1e-14, 1e-14) {
+				t.Errorf("CDF mismatch case %v at %v: want %v, got %v", i, v, cdf, got)
+			}
+			if got := m.Prob(v); !floats.EqualWithinAbsOrRel(got, prob, 1e-14, 1e-14) {
+				t.Errorf("Prob mismatch case %v at %v: want %v, got %v", i, v, prob, got)
+			}
+		}
+	}
+
+	// Mixtures compose with other combinators.
+	m := Mixture{
+		Weights: []float64{1, 3},
+		Components: []MixtureComponent{
+			Truncated{Dist: Normal{Mu: 0, Sigma: 1}, Lo: -1, Hi: 1},
+			Uniform{Min: 2, Max: 4},
+		},
+	}
+	for _, p := range []float64{0.1, 0.25, 0.5, 0.9} {
+		x := m.Quantile(p)
+		if got := m.CDF(x); !floats.EqualWithinAbsOrRel(got, p, 1e-12, 1e-12) {
+			t.Errorf("Quantile/CDF mismatch at %v: got %v", p, got)
+		}
+	}
+	if got, want := m.Quantile(0), -1.0; got != want {
+		t.Errorf("Unexpected Quantile(0): want %v, got %v", want, got)
+	}
+	if got, want := m.Quantile(1), 4.0; got != want {
+		t.Errorf("Unexpected Quantile(1): want %v, got %v", want, got)
+	}
+
+	for _, m := range []Mixture{
+		{Weights: []float64{1}, Components: []MixtureComponent{Normal{Mu: 0, Sigma: 1}, Normal{Mu: 1, Sigma: 1}}},
+		{Weights: []float64{1, -1}, Components: []MixtureComponent{Normal{Mu: 0, Sigma: 1}, Normal{Mu: 1, Sigma: 1}}},
+		{Weights: []float64{0, 0}, Components: []MixtureComponent{Normal{Mu: 0, Sigma: 1}, Normal{Mu: 1, Sigma: 1}}},
+	} {
+		if !panics(func() { m.CDF(0) }) {
+			t.Errorf("Expected panic for invalid weights %v", m.Weights)
+		}
+	}
+}
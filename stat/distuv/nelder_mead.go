@@ -0,0 +1,96 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package distuv
+
+import (
+	"math"
+	"sort"
+)
+
+// nelderMead returns an approximate minimizer of f found using the
+// Nelder–Mead simplex method starting from x0 with an initial simplex
+// displaced from x0 by step along each coordinate. It is used for maximum
+// likelihood fitting of distributions with few parameters, since the optimize
+// package depends on distuv.
+func nelderMead(f func([]float64) float64, x0, step []float64) []float64 {
+	const (
+		maxIter = 5000
+		tol     = 1e-12
+	)
+	n := len(x0)
+	type vertex struct {
+		x []float64
+		f float64
+	}
+	simplex := make([]vertex, n+1)
+	for i := range simplex {
+		x := make([]float64, n)
+		copy(x, x0)
+		if i > 0 {
+			x[i-1] += step[i-1]
+		}
+		simplex[i] = vertex{x: x, f: f(x)}
+	}
+	point := func(c []float64, a float64, x []float64) vertex {
+		// Return c + a*(x - c).
+		p := make([]float64, n)
+		for i := range p {
+			p[i] = c[i] + a*(x[i]-c[i])
+		}
+		return vertex{x: p, f: f(p)}
+	}
+
+	centroid := make([]float64, n)
+	for iter := 0; iter < maxIter; iter++ {
+		sort.SliceStable(simplex, func(i, j int) bool { return simplex[i].f < simplex[j].f })
+		best, worst := simplex[0], simplex[n]
+		if math.Abs(worst.f-best.f) <= tol*(math.Abs(best.f)+tol) {
+			var size float64
+			for _, v := range simplex[1:] {
+				for i := range v.x {
+					size = math.Max(size, math.Abs(v.x[i]-best.x[i]))
+				}
+			}
+			if size <= 1e-10 {
+				break
+			}
+		}
+
+		for i := range centroid {
+			centroid[i] = 0
+			for _, v := range simplex[:n] {
+				centroid[i] += v.x[i]
+			}
+			centroid[i] /= float64(n)
+		}
+
+		r := point(centroid, -1, worst.x)
+		switch {
+		case r.f < best.f:
+			if e := point(centroid, -2, worst.x); e.f < r.f {
+				simplex[n] = e
+			} else {
+				simplex[n] = r
+			}
+		case r.f < simplex[n-1].f:
+			simplex[n] = r
+		default:
+			c := point(centroid, 0.5, worst.x)
+			if r.f < worst.f {
+				c = point(centroid, -0.5, worst.x)
+			}
+			if c.f < math.Min(r.f, worst.f) {
+				simplex[n] = c
+				continue
+			}
+			// Shrink toward the best vertex.
+			for k := 1; k <= n; k++ {
+				simplex[k] = point(best.x, 0.5, simplex[k].x)
+			}
+		}
+	}
+	sort.SliceStable(simplex, func(i, j int) bool { return simplex[i].f < simplex[j].f })
+	return simplex[0].x
+}
@@ -90,6 +90,14 @@ func (n Normal) LogProb(x float64) float64 {
 	return negLogRoot2Pi - math.Log(n.Sigma) - (x-n.Mu)*(x-n.Mu)/(2*n.Sigma*n.Sigma)
 }
 
+// MarshalParameters implements the ParameterMarshaler interface
+func (n Normal) MarshalParameters(p []Parameter) {
+	if len(p) != n.NumParameters() {
+		panic(badLength)
+	}
+	n.parameters(p)
+}
+
 // Mean returns the mean of the probability distribution.
 func (n Normal) Mean() float64 {
 	return n.Mu
@@ -233,6 +241,11 @@ func (n *Normal) setParameters(p []Parameter) {
 	n.Sigma = p[1].Value
 }
 
+// UnmarshalParameters implements the ParameterMarshaler interface
+func (n *Normal) UnmarshalParameters(p []Parameter) {
+	n.setParameters(p)
+}
+
 // Variance returns the variance of the probability distribution.
 func (n Normal) Variance() float64 {
 	return n.Sigma * n.Sigma
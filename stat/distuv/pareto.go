@@ -8,6 +8,8 @@ import (
 	"math"
 
 	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats"
 )
 
 // Pareto implements the Pareto (Type I) distribution, a one parameter distribution
@@ -51,6 +53,36 @@ func (p Pareto) ExKurtosis() float64 {
 
 }
 
+// Fit sets the parameters of the probability distribution from the
+// data samples x with relative weights w to the maximum likelihood
+// estimates
+//  Xm = min(x),
+//  Alpha = \sum_i w_i / \sum_i w_i log(x_i/Xm).
+// Fit will panic if a sample is not positive.
+// If weights is nil, then all the weights are 1.
+// If weights is not nil, then the len(weights) must equal len(samples).
+func (p *Pareto) Fit(samples, weights []float64) {
+	if weights != nil && len(samples) != len(weights) {
+		panic(badLength)
+	}
+	if len(samples) == 0 {
+		panic(badNoSamples)
+	}
+	logs := logSamples(samples)
+	xm := floats.Min(logs)
+	var sum, sumLog float64
+	for i, v := range logs {
+		w := 1.0
+		if weights != nil {
+			w = weights[i]
+		}
+		sum += w
+		sumLog += w * (v - xm)
+	}
+	p.Xm = math.Exp(xm)
+	p.Alpha = sum / sumLog
+}
+
 // LogProb computes the natural logarithm of the value of the probability
 // density function at x.
 func (p Pareto) LogProb(x float64) float64 {
@@ -60,6 +92,17 @@ func (p Pareto) LogProb(x float64) float64 {
 	return math.Log(p.Alpha) + p.Alpha*math.Log(p.Xm) - (p.Alpha+1)*math.Log(x)
 }
 
+// MarshalParameters implements the ParameterMarshaler interface
+func (p Pareto) MarshalParameters(params []Parameter) {
+	if len(params) != p.NumParameters() {
+		panic(badLength)
+	}
+	params[0].Name = "Xm"
+	params[0].Value = p.Xm
+	params[1].Name = "Alpha"
+	params[1].Value = p.Alpha
+}
+
 // Mean returns the mean of the probability distribution.
 func (p Pareto) Mean() float64 {
 	if p.Alpha <= 1 {
@@ -112,6 +155,21 @@ func (p Pareto) Survival(x float64) float64 {
 	return math.Exp(p.Alpha * (math.Log(p.Xm) - math.Log(x)))
 }
 
+// UnmarshalParameters implements the ParameterMarshaler interface
+func (p *Pareto) UnmarshalParameters(params []Parameter) {
+	if len(params) != p.NumParameters() {
+		panic(badLength)
+	}
+	if params[0].Name != "Xm" {
+		panic("pareto: " + panicNameMismatch)
+	}
+	if params[1].Name != "Alpha" {
+		panic("pareto: " + panicNameMismatch)
+	}
+	p.Xm = params[0].Value
+	p.Alpha = params[1].Value
+}
+
 // Variance returns the variance of the probability distribution.
 func (p Pareto) Variance() float64 {
 	if p.Alpha <= 2 {
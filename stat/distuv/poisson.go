@@ -10,6 +10,7 @@ import (
 	"golang.org/x/exp/rand"
 
 	"gonum.org/v1/gonum/mathext"
+	"gonum.org/v1/gonum/stat"
 )
 
 // Poisson implements the Poisson distribution, a discrete probability distribution
@@ -39,6 +40,24 @@ func (p Poisson) ExKurtosis() float64 {
 	return 1 / p.Lambda
 }
 
+// Fit sets the parameters of the probability distribution from the
+// data samples x with relative weights w. Lambda is set to the maximum
+// likelihood estimate, the weighted mean of the samples. Fit will panic
+// if a sample is not a non-negative integer.
+// If weights is nil, then all the weights are 1.
+// If weights is not nil, then the len(weights) must equal len(samples).
+func (p *Poisson) Fit(samples, weights []float64) {
+	if weights != nil && len(samples) != len(weights) {
+		panic(badLength)
+	}
+	for _, x := range samples {
+		if x < 0 || math.Floor(x) != x {
+			panic(badSupport)
+		}
+	}
+	p.Lambda = stat.Mean(samples, weights)
+}
+
 // LogProb computes the natural logarithm of the value of the probability
 // density function at x.
 func (p Poisson) LogProb(x float64) float64 {
@@ -49,6 +68,15 @@ func (p Poisson) LogProb(x float64) float64 {
 	return x*math.Log(p.Lambda) - p.Lambda - lg
 }
 
+// MarshalParameters implements the ParameterMarshaler interface
+func (p Poisson) MarshalParameters(params []Parameter) {
+	if len(params) != p.NumParameters() {
+		panic(badLength)
+	}
+	params[0].Name = "Lambda"
+	params[0].Value = p.Lambda
+}
+
 // Mean returns the mean of the probability distribution.
 func (p Poisson) Mean() float64 {
 	return p.Lambda
@@ -130,6 +158,17 @@ func (p Poisson) Survival(x float64) float64 {
 	return 1 - p.CDF(x)
 }
 
+// UnmarshalParameters implements the ParameterMarshaler interface
+func (p *Poisson) UnmarshalParameters(params []Parameter) {
+	if len(params) != p.NumParameters() {
+		panic(badLength)
+	}
+	if params[0].Name != "Lambda" {
+		panic("poisson: " + panicNameMismatch)
+	}
+	p.Lambda = params[0].Value
+}
+
 // Variance returns the variance of the probability distribution.
 func (p Poisson) Variance() float64 {
 	return p.Lambda
@@ -10,6 +10,7 @@ import (
 	"golang.org/x/exp/rand"
 
 	"gonum.org/v1/gonum/mathext"
+	"gonum.org/v1/gonum/stat"
 )
 
 const logPi = 1.1447298858494001741 // http://oeis.org/A053510
@@ -61,6 +62,50 @@ func (s StudentsT) CDF(x float64) float64 {
 	return 0.5 * mathext.RegIncBeta(s.Nu/2, 0.5, t)
 }
 
+// Fit sets the parameters of the probability distribution from the
+// data samples x with relative weights w to their maximum likelihood
+// estimates. The likelihood is maximized using the Nelder–Mead method
+// starting from the weighted mean and standard deviation of the samples
+// with Nu = 5. The likelihood may be unbounded in Nu for samples with
+// light tails, in which case Nu will be large. Fit will panic if there
+// are fewer than two samples.
+// If weights is nil, then all the weights are 1.
+// If weights is not nil, then the len(weights) must equal len(samples).
+func (s *StudentsT) Fit(samples, weights []float64) {
+	if weights != nil && len(samples) != len(weights) {
+		panic(badLength)
+	}
+	if len(samples) < 2 {
+		panic(badNoSamples)
+	}
+	mean, std := stat.MeanStdDev(samples, weights)
+	if std == 0 {
+		panic("studentst: samples must not all be equal")
+	}
+	nll := func(p []float64) float64 {
+		d := StudentsT{Mu: p[0], Sigma: math.Exp(p[1]), Nu: math.Exp(p[2])}
+		var ll float64
+		for i, x := range samples {
+			w := 1.0
+			if weights != nil {
+				w = weights[i]
+			}
+			ll += w * d.LogProb(x)
+		}
+		if math.IsNaN(ll) {
+			return math.Inf(1)
+		}
+		return -ll
+	}
+	p := []float64{mean, math.Log(std), math.Log(5)}
+	// Restart once to avoid premature convergence
+	// of the simplex.
+	for i := 0; i < 2; i++ {
+		p = nelderMead(nll, p, []float64{0.1 * std, 0.1, 0.5})
+	}
+	s.Mu, s.Sigma, s.Nu = p[0], math.Exp(p[1]), math.Exp(p[2])
+}
+
 // LogProb computes the natural logarithm of the value of the probability
 // density function at x.
 func (s StudentsT) LogProb(x float64) float64 {
@@ -70,6 +115,19 @@ func (s StudentsT) LogProb(x float64) float64 {
 	return g1 - g2 - 0.5*math.Log(s.Nu) - 0.5*logPi - math.Log(s.Sigma) - ((s.Nu+1)/2)*math.Log(1+z*z/s.Nu)
 }
 
+// MarshalParameters implements the ParameterMarshaler interface
+func (s StudentsT) MarshalParameters(p []Parameter) {
+	if len(p) != s.NumParameters() {
+		panic(badLength)
+	}
+	p[0].Name = "Mu"
+	p[0].Value = s.Mu
+	p[1].Name = "Sigma"
+	p[1].Value = s.Sigma
+	p[2].Name = "Nu"
+	p[2].Value = s.Nu
+}
+
 // Mean returns the mean of the probability distribution.
 func (s StudentsT) Mean() float64 {
 	return s.Mu
@@ -147,6 +205,25 @@ func (s StudentsT) Survival(x float64) float64 {
 	return 1 - 0.5*mathext.RegIncBeta(s.Nu/2, 0.5, t)
 }
 
+// UnmarshalParameters implements the ParameterMarshaler interface
+func (s *StudentsT) UnmarshalParameters(p []Parameter) {
+	if len(p) != s.NumParameters() {
+		panic(badLength)
+	}
+	if p[0].Name != "Mu" {
+		panic("studentst: " + panicNameMismatch)
+	}
+	if p[1].Name != "Sigma" {
+		panic("studentst: " + panicNameMismatch)
+	}
+	if p[2].Name != "Nu" {
+		panic("studentst: " + panicNameMismatch)
+	}
+	s.Mu = p[0].Value
+	s.Sigma = p[1].Value
+	s.Nu = p[2].Value
+}
+
 // Variance returns the variance of the probability distribution.
 //
 // The variance is undefined for ν <= 1, and this returns math.NaN().
@@ -0,0 +1,102 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package distuv
+
+import (
+	"math"
+
+	"golang.org/x/exp/rand"
+)
+
+// Truncatable is a continuous distribution that may be truncated.
+type Truncatable interface {
+	CDFer
+	LogProber
+	Quantiler
+}
+
+// Truncated represents the distribution Dist restricted to the interval
+// [Lo, Hi]. The density of the truncated distribution is
+//  f(x) = f_Dist(x) / (F_Dist(Hi) - F_Dist(Lo))
+// for Lo <= x <= Hi and zero otherwise, where f_Dist and F_Dist are the
+// density and cumulative distribution function of Dist. Lo may be -Inf and
+// Hi may be +Inf.
+//
+// The probability of Dist in [Lo, Hi] must be positive. The CDF and Quantile
+// methods are derived from those of Dist, so precision is lost when the
+// interval is far in the upper tail of Dist.
+// More information at https://en.wikipedia.org/wiki/Truncated_distribution.
+type Truncated struct {
+	Dist   Truncatable
+	Lo, Hi float64
+
+	// Src is the random source used by Rand.
+	// Samples are generated by inversion
+	// using Dist.Quantile.
+	Src *rand.Rand
+}
+
+// mass returns the value of the CDF of Dist at Lo and the probability of
+// Dist in [Lo, Hi].
+func (t Truncated) mass() (lo, z float64) {
+	lo = t.Dist.CDF(t.Lo)
+	z = t.Dist.CDF(t.Hi) - lo
+	if !(z > 0) {
+		panic("distuv: truncation interval has zero probability")
+	}
+	return lo, z
+}
+
+// CDF computes the value of the cumulative distribution function at x.
+func (t Truncated) CDF(x float64) float64 {
+	if x < t.Lo {
+		return 0
+	}
+	if x >= t.Hi {
+		return 1
+	}
+	lo, z := t.mass()
+	return math.Min(1, math.Max(0, (t.Dist.CDF(x)-lo)/z))
+}
+
+// LogProb computes the natural logarithm of the value of the probability
+// density function at x.
+func (t Truncated) LogProb(x float64) float64 {
+	if x < t.Lo || x > t.Hi {
+		return math.Inf(-1)
+	}
+	_, z := t.mass()
+	return t.Dist.LogProb(x) - math.Log(z)
+}
+
+// Prob computes the value of the probability density function at x.
+func (t Truncated) Prob(x float64) float64 {
+	return math.Exp(t.LogProb(x))
+}
+
+// Quantile returns the inverse of the cumulative distribution function.
+func (t Truncated) Quantile(p float64) float64 {
+	if p < 0 || p > 1 {
+		panic(badPercentile)
+	}
+	lo, z := t.mass()
+	return math.Min(t.Hi, math.Max(t.Lo, t.Dist.Quantile(lo+p*z)))
+}
+
+// Rand returns a random sample drawn from the distribution.
+func (t Truncated) Rand() float64 {
+	var rnd float64
+	if t.Src == nil {
+		rnd = rand.Float64()
+	} else {
+		rnd = t.Src.Float64()
+	}
+	return t.Quantile(rnd)
+}
+
+// Survival returns the survival function (complementary CDF) at x.
+func (t Truncated) Survival(x float64) float64 {
+	return 1 - t.CDF(x)
+}
@@ -0,0 +1,88 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package distuv
+
+import (
+	"math"
+	"sort"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/integrate/quad"
+	"gonum.org/v1/gonum/stat"
+)
+
+func TestTruncated(t *testing.T) {
+	src := rand.New(rand.NewSource(1))
+	for i, test := range []struct {
+		d    Truncated
+		mean float64
+	}{
+		{
+			// The half-normal distribution.
+			d:    Truncated{Dist: Normal{Mu: 0, Sigma: 1}, Lo: math.Inf(-1), Hi: 0},
+			mean: -math.Sqrt(2 / math.Pi),
+		},
+		{
+			// The exponential distribution is memoryless.
+			d:    Truncated{Dist: Exponential{Rate: 1}, Lo: 0.5, Hi: math.Inf(1)},
+			mean: 1.5,
+		},
+		{
+			d:    Truncated{Dist: Normal{Mu: 1, Sigma: 2}, Lo: -1, Hi: 2},
+			mean: 1 + 2*(math.Exp(-0.5)-math.Exp(-0.125))/math.Sqrt(2*math.Pi)/(Normal{Mu: 0, Sigma: 1}.CDF(0.5)-Normal{Mu: 0, Sigma: 1}.CDF(-1)),
+		},
+		{
+			d: Truncated{Dist: Uniform{Min: 0, Max: 10}, Lo: 2, Hi: 4},
+			// The truncated uniform distribution is uniform.
+			mean: 3,
+		},
+	} {
+		d := test.d
+		d.Src = src
+		const tol = 1e-2
+		const n = 1e5
+		x := make([]float64, n)
+		generateSamples(x, d)
+		sort.Float64s(x)
+
+		if x[0] < d.Lo || x[len(x)-1] > d.Hi {
+			t.Errorf("Sample outside truncation interval. Case %v: [%v, %v]", i, x[0], x[len(x)-1])
+		}
+		if mean := stat.Mean(x, nil); !floats.EqualWithinAbsOrRel(mean, test.mean, tol, tol) {
+			t.Errorf("Mean mismatch case %v: want: %v, got: %v", i, test.mean, mean)
+		}
+		checkQuantileCDFSurvival(t, i, x, d, tol)
+		q := quad.Fixed(d.Prob, math.Max(d.Lo, d.Dist.Quantile(0)), math.Min(d.Hi, d.Dist.Quantile(1)), 100000, nil, 0)
+		if math.Abs(q-1) > 1e-6 {
+			t.Errorf("Probability distribution doesn't integrate to 1. Case %v: Got %v", i, q)
+		}
+		checkProbQuantContinuous(t, i, x, d, tol)
+
+		for _, v := range []float64{d.Lo - 1, d.Hi + 1} {
+			if p := d.Prob(v); p != 0 {
+				t.Errorf("Non-zero probability outside truncation interval. Case %v at %v: got %v", i, v, p)
+			}
+		}
+	}
+
+	// Truncation to the support does not change the distribution.
+	e := Exponential{Rate: 2}
+	d := Truncated{Dist: e, Lo: 0, Hi: math.Inf(1)}
+	for _, x := range []float64{0.1, 0.5, 1, 3} {
+		if got, want := d.CDF(x), e.CDF(x); !floats.EqualWithinAbsOrRel(got, want, 1e-15, 1e-15) {
+			t.Errorf("CDF mismatch at %v: want %v, got %v", x, want, got)
+		}
+		if got, want := d.LogProb(x), e.LogProb(x); !floats.EqualWithinAbsOrRel(got, want, 1e-15, 1e-15) {
+			t.Errorf("LogProb mismatch at %v: want %v, got %v", x, want, got)
+		}
+	}
+
+	if !panics(func() { Truncated{Dist: e, Lo: -2, Hi: -1}.CDF(-1.5) }) {
+		t.Error("Expected panic for truncation interval with zero probability")
+	}
+}
@@ -45,7 +45,31 @@ func (Uniform) ExKurtosis() float64 {
 	return -6.0 / 5.0
 }
 
-// Uniform doesn't have Fit because it's a bad idea to fit a uniform from data.
+// Fit sets the parameters of the probability distribution from the
+// data samples x with relative weights w. Min and Max are set to the
+// maximum likelihood estimates, the smallest and largest samples with
+// positive weight.
+// If weights is nil, then all the weights are 1.
+// If weights is not nil, then the len(weights) must equal len(samples).
+func (u *Uniform) Fit(samples, weights []float64) {
+	if weights != nil && len(samples) != len(weights) {
+		panic(badLength)
+	}
+	min := math.Inf(1)
+	max := math.Inf(-1)
+	for i, x := range samples {
+		if weights != nil && weights[i] <= 0 {
+			continue
+		}
+		min = math.Min(min, x)
+		max = math.Max(max, x)
+	}
+	if min > max {
+		panic(badNoSamples)
+	}
+	u.Min = min
+	u.Max = max
+}
 
 // LogProb computes the natural logarithm of the value of the probability density function at x.
 func (u Uniform) LogProb(x float64) float64 {
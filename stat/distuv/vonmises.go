@@ -0,0 +1,356 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package distuv
+
+import (
+	"math"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/stat"
+)
+
+// VonMises represents the von Mises distribution, a continuous distribution
+// of angles that is the circular analogue of the normal distribution. The
+// probability density function is
+//  f(x) = exp(K * cos(x - Mu)) / (2 * π * I_0(K)),
+// where I_0 is the modified Bessel function of the first kind of order zero.
+//
+// The support of the distribution is the interval [Mu-π, Mu+π], so angles
+// must be unwrapped into this interval before use with the density and
+// distribution functions. Moments other than the circular variance are of the
+// angle as a linear quantity on the support interval.
+// More information at https://en.wikipedia.org/wiki/Von_Mises_distribution.
+type VonMises struct {
+	// Mu is the mean direction of the distribution.
+	Mu float64
+	// K is the concentration of the distribution about Mu
+	// and must be non-negative. When K is zero the
+	// distribution is uniform on the support interval.
+	K float64
+
+	Src *rand.Rand
+}
+
+// CDF computes the value of the cumulative distribution function at x.
+func (v VonMises) CDF(x float64) float64 {
+	y := x - v.Mu
+	switch {
+	case y <= -math.Pi:
+		return 0
+	case y >= math.Pi:
+		return 1
+	}
+	a := besselRatios(v.K, vonMisesTerms(v.K))
+	sum := (y + math.Pi) / (2 * math.Pi)
+	for j := len(a) - 1; j >= 1; j-- {
+		fj := float64(j)
+		sum += a[j] * math.Sin(fj*y) / (fj * math.Pi)
+	}
+	return math.Max(0, math.Min(sum, 1))
+}
+
+// CircularVariance returns the circular variance of the distribution,
+//  1 - I_1(K) / I_0(K).
+func (v VonMises) CircularVariance() float64 {
+	return 1 - besselRatios(v.K, 1)[1]
+}
+
+// Entropy returns the differential entropy of the distribution.
+func (v VonMises) Entropy() float64 {
+	return log2Pi + logI0(v.K) - v.K*besselRatios(v.K, 1)[1]
+}
+
+// ExKurtosis returns the excess kurtosis of the distribution.
+func (v VonMises) ExKurtosis() float64 {
+	m2, m4 := v.moments()
+	return m4/(m2*m2) - 3
+}
+
+// Fit sets the parameters of the distribution to the maximum likelihood
+// estimates from the angles in samples with relative weights. Mu is set to
+// the circular mean of the samples and K is found by solving
+//  I_1(K) / I_0(K) = R
+// where R is the mean resultant length of the samples.
+// If weights is nil, then all the weights are 1. If weights is not nil, then
+// len(weights) must equal len(samples).
+func (v *VonMises) Fit(samples, weights []float64) {
+	if weights != nil && len(samples) != len(weights) {
+		panic(badLength)
+	}
+	if len(samples) == 0 {
+		panic(badNoSamples)
+	}
+	v.Mu = stat.CircularMean(samples, weights)
+	v.K = vonMisesConcentration(1 - stat.CircularVariance(samples, weights))
+}
+
+// LogProb computes the natural logarithm of the value of the probability
+// density function at x.
+func (v VonMises) LogProb(x float64) float64 {
+	y := x - v.Mu
+	if y < -math.Pi || math.Pi < y {
+		return math.Inf(-1)
+	}
+	return v.K*math.Cos(y) - log2Pi - logI0(v.K)
+}
+
+// MarshalParameters implements the ParameterMarshaler interface
+func (v VonMises) MarshalParameters(p []Parameter) {
+	if len(p) != v.NumParameters() {
+		panic(badLength)
+	}
+	p[0].Name = "Mu"
+	p[0].Value = v.Mu
+	p[1].Name = "K"
+	p[1].Value = v.K
+}
+
+// Mean returns the mean of the distribution on its support interval,
+// which is the mean direction Mu.
+func (v VonMises) Mean() float64 {
+	return v.Mu
+}
+
+// Median returns the median of the distribution.
+func (v VonMises) Median() float64 {
+	return v.Mu
+}
+
+// Mode returns the mode of the distribution.
+func (v VonMises) Mode() float64 {
+	return v.Mu
+}
+
+// NumParameters returns the number of parameters in the distribution.
+func (VonMises) NumParameters() int {
+	return 2
+}
+
+// Prob computes the value of the probability density function at x.
+func (v VonMises) Prob(x float64) float64 {
+	return math.Exp(v.LogProb(x))
+}
+
+// Quantile returns the inverse of the cumulative distribution function.
+func (v VonMises) Quantile(p float64) float64 {
+	if p < 0 || 1 < p {
+		panic(badPercentile)
+	}
+	// The CDF is strictly increasing on the
+	// support interval, so bisect for p.
+	lo, hi := v.Mu-math.Pi, v.Mu+math.Pi
+	for i := 0; i < 100; i++ {
+		mid := lo + (hi-lo)/2
+		if mid == lo || mid == hi {
+			break
+		}
+		if v.CDF(mid) < p {
+			lo = mid
+		} else {
+			hi = mid
+		}
+	}
+	return lo + (hi-lo)/2
+}
+
+// Rand returns a random sample drawn from the distribution in the
+// support interval [Mu-π, Mu+π].
+//
+// The sample is generated using the rejection method of Best and Fisher.
+// See Best, D. J. and Fisher, N. I. "Efficient simulation of the von Mises
+// distribution." Applied Statistics 28(2):152–157 (1979).
+func (v VonMises) Rand() float64 {
+	rnd := rand.Float64
+	norm := rand.NormFloat64
+	if v.Src != nil {
+		rnd = v.Src.Float64
+		norm = v.Src.NormFloat64
+	}
+	switch {
+	case v.K < 1e-8:
+		return v.Mu + math.Pi*(2*rnd()-1)
+	case v.K > 1e6:
+		// The distribution is closely approximated by
+		// a normal distribution with variance 1/K.
+		y := norm() / math.Sqrt(v.K)
+		return v.Mu + math.Max(-math.Pi, math.Min(y, math.Pi))
+	}
+
+	tau := 1 + math.Sqrt(1+4*v.K*v.K)
+	rho := (tau - math.Sqrt(2*tau)) / (2 * v.K)
+	r := (1 + rho*rho) / (2 * rho)
+	var f float64
+	for {
+		u1, u2 := rnd(), rnd()
+		z := math.Cos(math.Pi * u1)
+		f = (1 + r*z) / (r + z)
+		c := v.K * (r - f)
+		if c*(2-c) > u2 || math.Log(c/u2)+1 >= c {
+			break
+		}
+	}
+	theta := math.Acos(math.Max(-1, math.Min(f, 1)))
+	if rnd() < 0.5 {
+		theta = -theta
+	}
+	return v.Mu + theta
+}
+
+// Skewness returns the skewness of the distribution.
+func (VonMises) Skewness() float64 {
+	return 0
+}
+
+// StdDev returns the standard deviation of the distribution.
+func (v VonMises) StdDev() float64 {
+	return math.Sqrt(v.Variance())
+}
+
+// Survival returns the survival function (complementary CDF) at x.
+func (v VonMises) Survival(x float64) float64 {
+	return 1 - v.CDF(x)
+}
+
+// UnmarshalParameters implements the ParameterMarshaler interface
+func (v *VonMises) UnmarshalParameters(p []Parameter) {
+	if len(p) != v.NumParameters() {
+		panic(badLength)
+	}
+	if p[0].Name != "Mu" {
+		panic("vonmises: " + panicNameMismatch)
+	}
+	if p[1].Name != "K" {
+		panic("vonmises: " + panicNameMismatch)
+	}
+	v.Mu = p[0].Value
+	v.K = p[1].Value
+}
+
+// Variance returns the variance of the distribution.
+func (v VonMises) Variance() float64 {
+	m2, _ := v.moments()
+	return m2
+}
+
+// moments returns the second and fourth central moments of the distribution
+// on its support interval, computed from the Fourier series of the density
+//  f(y) = (1 + 2 * \sum_j A_j * cos(j*y)) / (2*π),
+// where A_j = I_j(K) / I_0(K) and y = x - Mu.
+func (v VonMises) moments() (m2, m4 float64) {
+	a := besselRatios(v.K, vonMisesTerms(v.K))
+	const pi2 = math.Pi * math.Pi
+	for j := len(a) - 1; j >= 1; j-- {
+		jj := float64(j * j)
+		t := a[j]
+		if j%2 != 0 {
+			t = -t
+		}
+		m2 += 4 * t / jj
+		m4 += t * (8*pi2/jj - 48/(jj*jj))
+	}
+	return m2 + pi2/3, m4 + pi2*pi2/5
+}
+
+// vonMisesTerms returns the number of terms of the Fourier series of a von
+// Mises density with concentration k needed for full precision.
+func vonMisesTerms(k float64) int {
+	// The coefficients decay as (k/2)^j/j! for small k
+	// and as exp(-j^2/(2k)) for large k.
+	return 30 + int(10*math.Sqrt(k))
+}
+
+// besselRatios returns the ratios I_j(k) / I_0(k) for j = 0, ..., n, where
+// I_j is the modified Bessel function of the first kind of order j. The
+// ratios are computed from the continued fraction for I_j(k) / I_{j-1}(k)
+// using backward recurrence.
+func besselRatios(k float64, n int) []float64 {
+	a := make([]float64, n+1)
+	a[0] = 1
+	if k == 0 {
+		return a
+	}
+	var r float64
+	for j := n + int(k) + 50; j >= 1; j-- {
+		r = 1 / (2*float64(j)/k + r)
+		if j <= n {
+			a[j] = r
+		}
+	}
+	for j := 1; j <= n; j++ {
+		a[j] *= a[j-1]
+	}
+	return a
+}
+
+// logI0 returns the logarithm of the modified Bessel function of the first
+// kind of order zero.
+func logI0(k float64) float64 {
+	k = math.Abs(k)
+	if k < 500 {
+		// Use the power series
+		//  I_0(k) = \sum_j ((k/2)^j / j!)^2.
+		sum, term := 1.0, 1.0
+		h := k / 2
+		for j := 1; ; j++ {
+			term *= h / float64(j)
+			t := term * term
+			sum += t
+			if t < sum*1e-17 {
+				break
+			}
+		}
+		return math.Log(sum)
+	}
+	// Use the asymptotic expansion
+	//  I_0(k) ~ exp(k) / sqrt(2πk) * \sum_j ((2j-1)!!)^2 / (j! * (8k)^j).
+	sum, term := 1.0, 1.0
+	for j := 1; j < 20; j++ {
+		f := float64(2*j - 1)
+		term *= f * f / (float64(j) * 8 * k)
+		sum += term
+		if term < sum*1e-17 {
+			break
+		}
+	}
+	return k - 0.5*math.Log(2*math.Pi*k) + math.Log(sum)
+}
+
+// vonMisesConcentration returns the concentration k of a von Mises
+// distribution with mean resultant length I_1(k) / I_0(k) equal to r.
+func vonMisesConcentration(r float64) float64 {
+	switch {
+	case r <= 0:
+		return 0
+	case r >= 1:
+		return math.Inf(1)
+	}
+
+	// Start from the approximation of Best and Fisher
+	// and refine using Newton's method with
+	//  d/dk A(k) = 1 - A(k)/k - A(k)^2.
+	var k float64
+	switch {
+	case r < 0.53:
+		k = 2*r + r*r*r + 5*math.Pow(r, 5)/6
+	case r < 0.85:
+		k = -0.4 + 1.39*r + 0.43/(1-r)
+	default:
+		k = 1 / (r*r*r - 4*r*r + 3*r)
+	}
+	for i := 0; i < 100; i++ {
+		a := besselRatios(k, 1)[1]
+		step := (a - r) / (1 - a/k - a*a)
+		next := k - step
+		if next <= 0 {
+			next = k / 2
+		}
+		if math.Abs(next-k) <= 1e-14*k {
+			return next
+		}
+		k = next
+	}
+	return k
+}
@@ -0,0 +1,114 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package distuv
+
+import (
+	"math"
+	"sort"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/integrate/quad"
+	"gonum.org/v1/gonum/stat"
+)
+
+func TestVonMises(t *testing.T) {
+	src := rand.New(rand.NewSource(1))
+	for i, test := range []struct {
+		mu, k float64
+	}{
+		{mu: 0, k: 0},
+		{mu: 1, k: 0.5},
+		{mu: -2, k: 2},
+		{mu: 3, k: 10},
+		{mu: 0.5, k: 100},
+	} {
+		v := VonMises{Mu: test.mu, K: test.k, Src: src}
+		tol := 1e-2
+		const n = 1e5
+		x := make([]float64, n)
+		generateSamples(x, v)
+		sort.Float64s(x)
+
+		checkMean(t, i, x, v, tol)
+		checkVarAndStd(t, i, x, v, tol)
+		checkEntropy(t, i, x, v, tol)
+		checkExKurtosis(t, i, x, v, 5e-2)
+		checkSkewness(t, i, x, v, 5e-2)
+		checkMedian(t, i, x, v, tol)
+		checkQuantileCDFSurvival(t, i, x, v, tol)
+		checkProbQuantContinuous(t, i, x, v, tol)
+
+		// The density integrates to one over the support.
+		q := quad.Fixed(v.Prob, v.Mu-math.Pi, v.Mu+math.Pi, 1000, nil, 0)
+		if math.Abs(q-1) > 1e-10 {
+			t.Errorf("Probability distribution doesn't integrate to 1. Case %v: Got %v", i, q)
+		}
+		if x[0] < v.Mu-math.Pi || v.Mu+math.Pi < x[len(x)-1] {
+			t.Errorf("Sample outside support. Case %v: [%v, %v]", i, x[0], x[len(x)-1])
+		}
+		if v.Prob(v.Mu+3.5) != 0 || v.CDF(v.Mu-3.5) != 0 || v.CDF(v.Mu+3.5) != 1 {
+			t.Errorf("Unexpected density or distribution outside support. Case %v", i)
+		}
+
+		var fit VonMises
+		fit.Fit(x, nil)
+		if math.Abs(fit.Mu-test.mu) > 0.05 && test.k > 0 {
+			t.Errorf("Fitted mean direction mismatch. Case %v: want %v, got %v", i, test.mu, fit.Mu)
+		}
+		if math.Abs(fit.K-test.k) > 0.05*test.k+0.01 {
+			t.Errorf("Fitted concentration mismatch. Case %v: want %v, got %v", i, test.k, fit.K)
+		}
+		if cv, want := v.CircularVariance(), stat.CircularVariance(x, nil); math.Abs(cv-want) > tol {
+			t.Errorf("Circular variance mismatch. Case %v: want %v, got %v", i, want, cv)
+		}
+	}
+}
+
+func TestVonMisesProb(t *testing.T) {
+	// Densities computed from the power series for I_0 and
+	// distributions by Simpson's rule integration of the density.
+	for _, test := range []struct {
+		mu, k, x float64
+		prob     float64
+		cdf      float64
+	}{
+		{mu: 0, k: 1, x: 0, prob: 0.3417104886234632, cdf: 0.5},
+		{mu: 0, k: 1, x: 1, prob: 0.21578146511029628, cdf: 0.7943553074346813},
+		{mu: 1, k: 4, x: 0, prob: 0.12225568624562154, cdf: 0.03322582090145145},
+		{mu: 0, k: 0, x: 1, prob: 1 / (2 * math.Pi), cdf: (1 + math.Pi) / (2 * math.Pi)},
+	} {
+		v := VonMises{Mu: test.mu, K: test.k}
+		if p := v.Prob(test.x); !floats.EqualWithinAbsOrRel(p, test.prob, 1e-12, 1e-12) {
+			t.Errorf("Prob mismatch for mu=%v k=%v x=%v: want %v, got %v", test.mu, test.k, test.x, test.prob, p)
+		}
+		if c := v.CDF(test.x); !floats.EqualWithinAbsOrRel(c, test.cdf, 1e-10, 1e-10) {
+			t.Errorf("CDF mismatch for mu=%v k=%v x=%v: want %v, got %v", test.mu, test.k, test.x, test.cdf, c)
+		}
+	}
+
+	// The density normalization is continuous where
+	// the evaluation of log(I_0) changes method.
+	lo := logI0(math.Nextafter(500, 0))
+	hi := logI0(500)
+	if math.Abs(lo-hi) > 1e-12 {
+		t.Errorf("Discontinuity in log(I_0) at 500: %v != %v", lo, hi)
+	}
+	if got, want := logI0(10), math.Log(2815.716628466254); math.Abs(got-want) > 1e-12 {
+		t.Errorf("log(I_0(10)) mismatch: want %v, got %v", want, got)
+	}
+}
+
+func TestVonMisesConcentration(t *testing.T) {
+	for _, k := range []float64{0.01, 0.3, 1, 2.5, 10, 100, 1000} {
+		r := besselRatios(k, 1)[1]
+		got := vonMisesConcentration(r)
+		if !floats.EqualWithinRel(got, k, 1e-8) {
+			t.Errorf("Concentration mismatch for k=%v: got %v", k, got)
+		}
+	}
+}
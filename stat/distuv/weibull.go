@@ -9,6 +9,9 @@ import (
 	"math/cmplx"
 
 	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/stat"
 )
 
 // Weibull distribution. Valid range for x is [0,+∞).
@@ -56,6 +59,52 @@ func (w Weibull) LogCDF(x float64) complex128 {
 	}
 }
 
+// Fit sets the parameters of the probability distribution from the
+// data samples x with relative weights w to their maximum likelihood
+// estimates. The shape K is the solution of
+//  \sum_i w_i x_i^K log(x_i) / \sum_i w_i x_i^K - 1/K = mean(log(x)),
+// where the mean is weighted, and the scale is
+//  λ = (\sum_i w_i x_i^K / \sum_i w_i)^(1/K).
+// Fit will panic if a sample is not positive or if all the samples are
+// equal.
+// If weights is nil, then all the weights are 1.
+// If weights is not nil, then the len(weights) must equal len(samples).
+func (w *Weibull) Fit(samples, weights []float64) {
+	if weights != nil && len(samples) != len(weights) {
+		panic(badLength)
+	}
+	logs := logSamples(samples)
+	meanLog := stat.Mean(logs, weights)
+	maxLog := floats.Max(logs)
+	if floats.Min(logs) == maxLog {
+		panic("weibull: samples must not all be equal")
+	}
+	// sums returns the weighted sums of (x_i/max(x))^k
+	// and (x_i/max(x))^k * log(x_i), scaled to avoid
+	// overflow.
+	sums := func(k float64) (sum, sumLog, total float64) {
+		for i, l := range logs {
+			v := 1.0
+			if weights != nil {
+				v = weights[i]
+			}
+			e := v * math.Exp(k*(l-maxLog))
+			sum += e
+			sumLog += e * l
+			total += v
+		}
+		return sum, sumLog, total
+	}
+	t := findRoot(func(t float64) float64 {
+		k := math.Exp(t)
+		sum, sumLog, _ := sums(k)
+		return sumLog/sum - 1/k - meanLog
+	}, 0)
+	w.K = math.Exp(t)
+	sum, _, total := sums(w.K)
+	w.Lambda = math.Exp(maxLog + math.Log(sum/total)/w.K)
+}
+
 // LogProb computes the natural logarithm of the value of the probability
 // density function at x. Zero is returned if x is less than zero.
 //
@@ -81,6 +130,14 @@ func (w Weibull) LogSurvival(x float64) float64 {
 	}
 }
 
+// MarshalParameters implements the ParameterMarshaler interface
+func (w Weibull) MarshalParameters(p []Parameter) {
+	if len(p) != w.NumParameters() {
+		panic(badLength)
+	}
+	w.parameters(p)
+}
+
 // Mean returns the mean of the probability distribution.
 func (w Weibull) Mean() float64 {
 	return w.Lambda * math.Gamma(1+1/w.K)
@@ -226,6 +283,11 @@ func (w *Weibull) setParameters(p []Parameter) {
 	w.Lambda = p[1].Value
 }
 
+// UnmarshalParameters implements the ParameterMarshaler interface
+func (w *Weibull) UnmarshalParameters(p []Parameter) {
+	w.setParameters(p)
+}
+
 // Variance returns the variance of the probability distribution.
 func (w Weibull) Variance() float64 {
 	return math.Pow(w.Lambda, 2) * (math.Gamma(1+2/w.K) - w.gammaIPow(1, 2))
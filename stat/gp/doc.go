@@ -0,0 +1,14 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package gp provides Gaussian process regression.
+//
+// A Gaussian process places a prior distribution over functions in which
+// the function values at any finite set of inputs are jointly normal with a
+// covariance given by a kernel function. Conditioning the prior on noisy
+// observations gives a posterior Gaussian process, whose mean and variance
+// predict the function at new inputs. The hyperparameters of the kernel and
+// the noise variance can be chosen by maximizing the marginal likelihood of
+// the observations.
+package gp // import "gonum.org/v1/gonum/stat/gp"
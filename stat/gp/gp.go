@@ -0,0 +1,263 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gp
+
+import (
+	"errors"
+	"math"
+
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/optimize"
+)
+
+// ErrNotPD is returned when the covariance matrix of the observations is not
+// positive definite.
+var ErrNotPD = errors.New("gp: covariance matrix not positive definite")
+
+// GP is a Gaussian process regression model with zero prior mean. The
+// observations are modeled as
+//  y_i = f(x_i) + ε_i,
+// where f is drawn from a Gaussian process with the covariance function
+// Kernel and ε_i are independent normal errors with variance Noise.
+type GP struct {
+	// Kernel is the covariance
+	// function of the process.
+	Kernel Kernel
+
+	// Noise is the variance of the
+	// observation errors.
+	Noise float64
+
+	x     *mat.Dense
+	y     *mat.VecDense
+	chol  mat.Cholesky
+	alpha *mat.VecDense
+}
+
+// Fit conditions the Gaussian process on the observations y at the inputs in
+// the rows of x by exact inference using the Cholesky factorization of the
+// covariance of the observations. If the covariance matrix is not positive
+// definite, for example when Noise is zero and x has repeated rows, Fit
+// returns ErrNotPD and the model is left unfitted. Fit will panic if the
+// number of rows of x is not equal to the length of y.
+func (g *GP) Fit(x mat.Matrix, y []float64) error {
+	r, _ := x.Dims()
+	if r != len(y) {
+		panic("gp: input and observation length mismatch")
+	}
+	g.x = mat.DenseCopyOf(x)
+	g.y = mat.NewVecDense(len(y), append([]float64(nil), y...))
+	g.alpha = nil
+	alpha, ok := g.factorize()
+	if !ok {
+		return ErrNotPD
+	}
+	g.alpha = alpha
+	return nil
+}
+
+// factorize computes the Cholesky factorization of the covariance of the
+// observations, returning K^-1 * y and whether the factorization succeeded.
+func (g *GP) factorize() (alpha *mat.VecDense, ok bool) {
+	n, _ := g.x.Dims()
+	k := mat.NewSymDense(n, nil)
+	for i := 0; i < n; i++ {
+		xi := g.x.RawRowView(i)
+		for j := i; j < n; j++ {
+			k.SetSym(i, j, g.Kernel.Cov(xi, g.x.RawRowView(j)))
+		}
+		k.SetSym(i, i, k.At(i, i)+g.Noise)
+	}
+	if !g.chol.Factorize(k) {
+		return nil, false
+	}
+	alpha = mat.NewVecDense(n, nil)
+	err := g.chol.SolveVec(alpha, g.y)
+	if err != nil {
+		if _, ok := err.(mat.Condition); !ok {
+			return nil, false
+		}
+	}
+	return alpha, true
+}
+
+// LogMarginalLikelihood returns the log of the marginal likelihood of the
+// observations used to fit g,
+//  log p(y | x) = -1/2 * y^T * K^-1 * y - 1/2 * log|K| - n/2 * log(2π),
+// where K is the covariance of the observations. LogMarginalLikelihood will
+// panic if g has not been successfully fitted.
+func (g *GP) LogMarginalLikelihood() float64 {
+	if g.alpha == nil {
+		panic("gp: model not fitted")
+	}
+	n := g.y.Len()
+	return -0.5*mat.Dot(g.y, g.alpha) - 0.5*g.chol.LogDet() - 0.5*float64(n)*math.Log(2*math.Pi)
+}
+
+// Predict returns the mean and variance of the posterior distribution of the
+// function values at the inputs in the rows of x. The variance of a new
+// observation at an input is the variance of the function value plus Noise.
+//
+// If mean or variance are nil, new slices are allocated, otherwise they must
+// have length equal to the number of rows of x. Predict will panic if g has
+// not been successfully fitted or the number of columns of x is not equal to
+// the number of columns of the fitted inputs.
+func (g *GP) Predict(mean, variance []float64, x mat.Matrix) (m, v []float64) {
+	if g.alpha == nil {
+		panic("gp: model not fitted")
+	}
+	r, c := x.Dims()
+	n, d := g.x.Dims()
+	if c != d {
+		panic("gp: input dimension mismatch")
+	}
+	if mean == nil {
+		mean = make([]float64, r)
+	}
+	if variance == nil {
+		variance = make([]float64, r)
+	}
+	if len(mean) != r || len(variance) != r {
+		panic("gp: slice length mismatch")
+	}
+
+	xs := make([]float64, c)
+	ks := mat.NewVecDense(n, nil)
+	var kinv mat.VecDense
+	for i := 0; i < r; i++ {
+		mat.Row(xs, i, x)
+		for j := 0; j < n; j++ {
+			ks.SetVec(j, g.Kernel.Cov(xs, g.x.RawRowView(j)))
+		}
+		mean[i] = mat.Dot(ks, g.alpha)
+		err := g.chol.SolveVec(&kinv, ks)
+		if err != nil {
+			if _, ok := err.(mat.Condition); !ok {
+				panic(err)
+			}
+		}
+		variance[i] = math.Max(0, g.Kernel.Cov(xs, xs)-mat.Dot(ks, &kinv))
+	}
+	return mean, variance
+}
+
+// Optimize chooses the hyperparameters of the kernel and the noise variance
+// of g by maximizing the log marginal likelihood of the observations y at
+// the inputs in the rows of x, starting from the current values, and then
+// fits g to the observations. The hyperparameters are optimized on a log
+// scale using the analytic gradient of the log marginal likelihood,
+//  ∂/∂θ_j log p(y | x) = 1/2 * tr((α*α^T - K^-1) * ∂K/∂θ_j),
+// where α = K^-1 * y. The initial Noise must be positive.
+//
+// The settings and method are passed to optimize.Local. If method is nil,
+// BFGS is used. If the optimization fails without finding a location, the
+// hyperparameters are restored and the error is returned. Otherwise g is
+// fitted with the best hyperparameters found, and any error from the
+// optimization or the fit is returned.
+//
+// Optimize will panic if the number of rows of x is not equal to the length
+// of y or if Noise is not positive.
+func (g *GP) Optimize(x mat.Matrix, y []float64, settings *optimize.Settings, method optimize.Method) error {
+	r, _ := x.Dims()
+	if r != len(y) {
+		panic("gp: input and observation length mismatch")
+	}
+	if !(g.Noise > 0) {
+		panic("gp: non-positive noise variance")
+	}
+	if method == nil {
+		method = &optimize.BFGS{}
+	}
+	g.x = mat.DenseCopyOf(x)
+	g.y = mat.NewVecDense(len(y), append([]float64(nil), y...))
+	g.alpha = nil
+
+	nh := g.Kernel.NumHyper()
+	init := make([]float64, nh+1)
+	g.Kernel.Hyper(init[:nh])
+	init[nh] = math.Log(g.Noise)
+	set := func(p []float64) {
+		g.Kernel.SetHyper(p[:nh])
+		g.Noise = math.Exp(p[nh])
+	}
+
+	problem := optimize.Problem{
+		Func: func(p []float64) float64 {
+			set(p)
+			alpha, ok := g.factorize()
+			if !ok {
+				g.alpha = nil
+				return math.Inf(1)
+			}
+			g.alpha = alpha
+			return -g.LogMarginalLikelihood()
+		},
+		Grad: func(grad, p []float64) {
+			set(p)
+			alpha, ok := g.factorize()
+			if !ok {
+				g.alpha = nil
+				for j := range grad {
+					grad[j] = math.NaN()
+				}
+				return
+			}
+			g.alpha = alpha
+			g.logMarginalGrad(grad)
+			for j, v := range grad {
+				grad[j] = -v
+			}
+		},
+	}
+
+	result, err := optimize.Local(problem, init, settings, method)
+	if result == nil {
+		set(init)
+		return err
+	}
+	set(result.X)
+	alpha, ok := g.factorize()
+	if !ok {
+		g.alpha = nil
+		return ErrNotPD
+	}
+	g.alpha = alpha
+	return err
+}
+
+// logMarginalGrad stores the gradient of the log marginal likelihood of the
+// fitted model g with respect to the logs of the kernel hyperparameters and
+// of the noise variance into grad.
+func (g *GP) logMarginalGrad(grad []float64) {
+	nh := g.Kernel.NumHyper()
+	if len(grad) != nh+1 {
+		panic("gp: hyperparameter length mismatch")
+	}
+	n := g.y.Len()
+	kinv := mat.NewSymDense(n, nil)
+	err := g.chol.InverseTo(kinv)
+	if err != nil {
+		if _, ok := err.(mat.Condition); !ok {
+			panic(err)
+		}
+	}
+	for j := range grad {
+		grad[j] = 0
+	}
+	dk := make([]float64, nh)
+	for a := 0; a < n; a++ {
+		xa := g.x.RawRowView(a)
+		for b := 0; b < n; b++ {
+			w := g.alpha.AtVec(a)*g.alpha.AtVec(b) - kinv.At(a, b)
+			g.Kernel.CovGrad(dk, xa, g.x.RawRowView(b))
+			for j, v := range dk {
+				grad[j] += 0.5 * w * v
+			}
+		}
+		// The derivative of K with respect to the
+		// log noise variance is Noise * I.
+		grad[nh] += 0.5 * (g.alpha.AtVec(a)*g.alpha.AtVec(a) - kinv.At(a, a)) * g.Noise
+	}
+}
@@ -0,0 +1,201 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gp
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/diff/fd"
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/stat/distmv"
+)
+
+// sinData returns n noisy observations of sin(2x) at
+// random inputs in [0, 5).
+func sinData(n int, noise float64, rnd *rand.Rand) (*mat.Dense, []float64) {
+	x := mat.NewDense(n, 1, nil)
+	y := make([]float64, n)
+	for i := range y {
+		x.Set(i, 0, 5*rnd.Float64())
+		y[i] = math.Sin(2*x.At(i, 0)) + noise*rnd.NormFloat64()
+	}
+	return x, y
+}
+
+// covariance returns the covariance of k between the rows of a and b.
+func covariance(k Kernel, a, b mat.Matrix) *mat.Dense {
+	ra, _ := a.Dims()
+	rb, _ := b.Dims()
+	c := mat.NewDense(ra, rb, nil)
+	for i := 0; i < ra; i++ {
+		for j := 0; j < rb; j++ {
+			c.Set(i, j, k.Cov(mat.Row(nil, i, a), mat.Row(nil, j, b)))
+		}
+	}
+	return c
+}
+
+func TestFitPredict(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for _, k := range kernels() {
+		x, y := sinData(20, 0.1, rnd)
+		g := GP{Kernel: k, Noise: 0.01}
+		err := g.Fit(x, y)
+		if err != nil {
+			t.Fatalf("unexpected error for %#v: %v", k, err)
+		}
+		xs := mat.NewDense(7, 1, []float64{-1, 0, 0.5, 1.7, 2.5, 4, 6})
+		mean, variance := g.Predict(nil, nil, xs)
+
+		// Compare with the posterior computed
+		// by direct matrix inversion.
+		kxx := covariance(k, x, x)
+		for i := 0; i < 20; i++ {
+			kxx.Set(i, i, kxx.At(i, i)+g.Noise)
+		}
+		var kinv mat.Dense
+		err = kinv.Inverse(kxx)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		ksx := covariance(k, xs, x)
+		var tmp, post mat.Dense
+		tmp.Mul(ksx, &kinv)
+		var wantMean mat.VecDense
+		wantMean.MulVec(&tmp, mat.NewVecDense(20, y))
+		post.Mul(&tmp, ksx.T())
+		kss := covariance(k, xs, xs)
+		for i := range mean {
+			if math.Abs(mean[i]-wantMean.AtVec(i)) > 1e-8 {
+				t.Errorf("unexpected mean for %#v at %v: got:%v want:%v", k, xs.At(i, 0), mean[i], wantMean.AtVec(i))
+			}
+			want := kss.At(i, i) - post.At(i, i)
+			if math.Abs(variance[i]-want) > 1e-8 {
+				t.Errorf("unexpected variance for %#v at %v: got:%v want:%v", k, xs.At(i, 0), variance[i], want)
+			}
+		}
+
+		// The log marginal likelihood is the log density
+		// of the observations under their prior.
+		sigma := mat.NewSymDense(20, nil)
+		for i := 0; i < 20; i++ {
+			for j := i; j < 20; j++ {
+				sigma.SetSym(i, j, kxx.At(i, j))
+			}
+		}
+		norm, ok := distmv.NewNormal(make([]float64, 20), sigma, nil)
+		if !ok {
+			t.Fatal("unexpected normal construction failure")
+		}
+		if got, want := g.LogMarginalLikelihood(), norm.LogProb(y); math.Abs(got-want) > 1e-8 {
+			t.Errorf("unexpected log marginal likelihood for %#v: got:%v want:%v", k, got, want)
+		}
+	}
+
+	// Without noise the posterior interpolates the observations.
+	x, y := sinData(10, 0, rnd)
+	g := GP{Kernel: &RBF{Variance: 1, LengthScale: 0.5}}
+	err := g.Fit(x, y)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	mean, variance := g.Predict(nil, nil, x)
+	if !floats.EqualApprox(mean, y, 1e-6) {
+		t.Errorf("noise-free posterior does not interpolate:\ngot: %v\nwant:%v", mean, y)
+	}
+	for i, v := range variance {
+		if v > 1e-6 {
+			t.Errorf("unexpected posterior variance at observation %d: %v", i, v)
+		}
+	}
+
+	// Repeated inputs without noise give a singular covariance.
+	dup := mat.NewDense(2, 1, []float64{1, 1})
+	if err := g.Fit(dup, []float64{0, 1}); err != ErrNotPD {
+		t.Errorf("unexpected error for repeated inputs: got:%v want:%v", err, ErrNotPD)
+	}
+	if !panics(func() { g.Predict(nil, nil, dup) }) {
+		t.Error("expected panic for prediction from unfitted model")
+	}
+}
+
+func TestLogMarginalGrad(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for _, k := range kernels() {
+		x, y := sinData(15, 0.2, rnd)
+		g := GP{Kernel: k, Noise: 0.05}
+		nh := k.NumHyper()
+		p := make([]float64, nh+1)
+		k.Hyper(p[:nh])
+		p[nh] = math.Log(g.Noise)
+		lml := func(q []float64) float64 {
+			h := GP{Kernel: g.Kernel, Noise: math.Exp(q[nh])}
+			orig := make([]float64, nh)
+			k.Hyper(orig)
+			k.SetHyper(q[:nh])
+			defer k.SetHyper(orig)
+			err := h.Fit(x, y)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			return h.LogMarginalLikelihood()
+		}
+		want := fd.Gradient(nil, lml, p, &fd.Settings{Formula: fd.Central})
+
+		err := g.Fit(x, y)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		got := make([]float64, nh+1)
+		g.logMarginalGrad(got)
+		if !floats.EqualApprox(got, want, 1e-5) {
+			t.Errorf("unexpected gradient for %#v:\ngot: %v\nwant:%v", k, got, want)
+		}
+	}
+}
+
+func TestOptimize(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	x, y := sinData(40, 0.1, rnd)
+	for _, k := range []Kernel{
+		&RBF{Variance: 1, LengthScale: 3},
+		&Matern{Nu: 2.5, Variance: 1, LengthScale: 3},
+	} {
+		g := GP{Kernel: k, Noise: 1}
+		err := g.Fit(x, y)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		before := g.LogMarginalLikelihood()
+		err = g.Optimize(x, y, nil, nil)
+		if err != nil {
+			t.Fatalf("unexpected error for %#v: %v", k, err)
+		}
+		after := g.LogMarginalLikelihood()
+		if after <= before {
+			t.Errorf("log marginal likelihood not increased for %#v: before:%v after:%v", k, before, after)
+		}
+		// The noise variance is recovered approximately.
+		if g.Noise < 0.1*0.1/4 || 4*0.1*0.1 < g.Noise {
+			t.Errorf("unexpected noise variance for %#v: got:%v want≈%v", k, g.Noise, 0.1*0.1)
+		}
+		xs := mat.NewDense(5, 1, []float64{0.5, 1.5, 2.5, 3.5, 4.5})
+		mean, _ := g.Predict(nil, nil, xs)
+		for i, m := range mean {
+			if want := math.Sin(2 * xs.At(i, 0)); math.Abs(m-want) > 0.15 {
+				t.Errorf("unexpected prediction for %#v at %v: got:%v want≈%v", k, xs.At(i, 0), m, want)
+			}
+		}
+	}
+
+	g := GP{Kernel: &RBF{Variance: 1, LengthScale: 1}}
+	if !panics(func() { g.Optimize(x, y, nil, nil) }) {
+		t.Error("expected panic for zero noise")
+	}
+}
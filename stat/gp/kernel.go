@@ -0,0 +1,217 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gp
+
+import "math"
+
+// Kernel is a covariance function of a Gaussian process with adjustable
+// hyperparameters. Hyperparameters are exposed on a log scale so that they
+// may be optimized without constraints.
+type Kernel interface {
+	// Cov returns the covariance between the
+	// function values at x and y.
+	Cov(x, y []float64) float64
+
+	// NumHyper returns the number of
+	// hyperparameters of the kernel.
+	NumHyper() int
+
+	// Hyper stores the logs of the hyperparameters
+	// into dst, which must have length NumHyper.
+	Hyper(dst []float64)
+
+	// SetHyper sets the hyperparameters from their
+	// logs in p, which must have length NumHyper.
+	SetHyper(p []float64)
+
+	// CovGrad stores the derivatives of Cov(x, y)
+	// with respect to the logs of the hyperparameters
+	// into dst, which must have length NumHyper.
+	CovGrad(dst, x, y []float64)
+}
+
+// RBF is the radial basis function, or squared exponential, kernel
+//  k(x, y) = σ² * exp(-|x - y|² / (2 * l²)),
+// where σ² is the variance and l is the length scale.
+type RBF struct {
+	Variance    float64
+	LengthScale float64
+}
+
+// Cov returns the covariance between the function values at x and y.
+func (k *RBF) Cov(x, y []float64) float64 {
+	return k.Variance * math.Exp(-sqDist(x, y)/(2*k.LengthScale*k.LengthScale))
+}
+
+// NumHyper returns 2.
+func (k *RBF) NumHyper() int { return 2 }
+
+// Hyper stores log(σ²) and log(l) into dst.
+func (k *RBF) Hyper(dst []float64) {
+	checkHyper(dst, 2)
+	dst[0] = math.Log(k.Variance)
+	dst[1] = math.Log(k.LengthScale)
+}
+
+// SetHyper sets σ² and l from their logs in p.
+func (k *RBF) SetHyper(p []float64) {
+	checkHyper(p, 2)
+	k.Variance = math.Exp(p[0])
+	k.LengthScale = math.Exp(p[1])
+}
+
+// CovGrad stores the derivatives of the covariance with respect
+// to log(σ²) and log(l) into dst.
+func (k *RBF) CovGrad(dst, x, y []float64) {
+	checkHyper(dst, 2)
+	c := k.Cov(x, y)
+	dst[0] = c
+	dst[1] = c * sqDist(x, y) / (k.LengthScale * k.LengthScale)
+}
+
+// Matern is the Matérn kernel with smoothness parameter ν of 1/2, 3/2 or
+// 5/2. With s = √(2ν) * |x - y| / l, the kernel is
+//  k(x, y) = σ² * exp(-s)                   for ν = 1/2,
+//  k(x, y) = σ² * (1 + s) * exp(-s)         for ν = 3/2,
+//  k(x, y) = σ² * (1 + s + s²/3) * exp(-s)  for ν = 5/2,
+// where σ² is the variance and l is the length scale. Functions drawn from
+// a Gaussian process with a Matérn kernel are ⌈ν⌉-1 times differentiable.
+// The smoothness is not a hyperparameter.
+type Matern struct {
+	// Nu is the smoothness parameter ν and
+	// must be 0.5, 1.5 or 2.5.
+	Nu float64
+
+	Variance    float64
+	LengthScale float64
+}
+
+// scaled returns s and the kernel value for the distance between x and y.
+func (k *Matern) scaled(x, y []float64) (s, c float64) {
+	r := math.Sqrt(sqDist(x, y)) / k.LengthScale
+	switch k.Nu {
+	case 0.5:
+		s = r
+		return s, k.Variance * math.Exp(-s)
+	case 1.5:
+		s = math.Sqrt(3) * r
+		return s, k.Variance * (1 + s) * math.Exp(-s)
+	case 2.5:
+		s = math.Sqrt(5) * r
+		return s, k.Variance * (1 + s + s*s/3) * math.Exp(-s)
+	default:
+		panic("gp: unsupported Matérn smoothness")
+	}
+}
+
+// Cov returns the covariance between the function values at x and y.
+// Cov will panic if k.Nu is not 0.5, 1.5 or 2.5.
+func (k *Matern) Cov(x, y []float64) float64 {
+	_, c := k.scaled(x, y)
+	return c
+}
+
+// NumHyper returns 2.
+func (k *Matern) NumHyper() int { return 2 }
+
+// Hyper stores log(σ²) and log(l) into dst.
+func (k *Matern) Hyper(dst []float64) {
+	checkHyper(dst, 2)
+	dst[0] = math.Log(k.Variance)
+	dst[1] = math.Log(k.LengthScale)
+}
+
+// SetHyper sets σ² and l from their logs in p.
+func (k *Matern) SetHyper(p []float64) {
+	checkHyper(p, 2)
+	k.Variance = math.Exp(p[0])
+	k.LengthScale = math.Exp(p[1])
+}
+
+// CovGrad stores the derivatives of the covariance with respect
+// to log(σ²) and log(l) into dst. CovGrad will panic if k.Nu is
+// not 0.5, 1.5 or 2.5.
+func (k *Matern) CovGrad(dst, x, y []float64) {
+	checkHyper(dst, 2)
+	s, c := k.scaled(x, y)
+	dst[0] = c
+	// The derivative with respect to log(l)
+	// is -s times the derivative with respect
+	// to s.
+	e := k.Variance * math.Exp(-s)
+	switch k.Nu {
+	case 0.5:
+		dst[1] = s * e
+	case 1.5:
+		dst[1] = s * s * e
+	case 2.5:
+		dst[1] = s * s * (1 + s) * e / 3
+	}
+}
+
+// Periodic is the periodic kernel
+//  k(x, y) = σ² * exp(-2 * sin²(π * |x - y| / p) / l²),
+// where σ² is the variance, l is the length scale and p is the period.
+type Periodic struct {
+	Variance    float64
+	LengthScale float64
+	Period      float64
+}
+
+// Cov returns the covariance between the function values at x and y.
+func (k *Periodic) Cov(x, y []float64) float64 {
+	sin := math.Sin(math.Pi * math.Sqrt(sqDist(x, y)) / k.Period)
+	return k.Variance * math.Exp(-2*sin*sin/(k.LengthScale*k.LengthScale))
+}
+
+// NumHyper returns 3.
+func (k *Periodic) NumHyper() int { return 3 }
+
+// Hyper stores log(σ²), log(l) and log(p) into dst.
+func (k *Periodic) Hyper(dst []float64) {
+	checkHyper(dst, 3)
+	dst[0] = math.Log(k.Variance)
+	dst[1] = math.Log(k.LengthScale)
+	dst[2] = math.Log(k.Period)
+}
+
+// SetHyper sets σ², l and p from their logs in p.
+func (k *Periodic) SetHyper(p []float64) {
+	checkHyper(p, 3)
+	k.Variance = math.Exp(p[0])
+	k.LengthScale = math.Exp(p[1])
+	k.Period = math.Exp(p[2])
+}
+
+// CovGrad stores the derivatives of the covariance with respect
+// to log(σ²), log(l) and log(p) into dst.
+func (k *Periodic) CovGrad(dst, x, y []float64) {
+	checkHyper(dst, 3)
+	u := math.Pi * math.Sqrt(sqDist(x, y)) / k.Period
+	sin := math.Sin(u)
+	l2 := k.LengthScale * k.LengthScale
+	c := k.Variance * math.Exp(-2*sin*sin/l2)
+	dst[0] = c
+	dst[1] = 4 * c * sin * sin / l2
+	dst[2] = 2 * c * u * math.Sin(2*u) / l2
+}
+
+func checkHyper(p []float64, n int) {
+	if len(p) != n {
+		panic("gp: hyperparameter length mismatch")
+	}
+}
+
+// sqDist returns the squared Euclidean distance between x and y.
+func sqDist(x, y []float64) float64 {
+	if len(x) != len(y) {
+		panic("gp: input dimension mismatch")
+	}
+	var d float64
+	for i, v := range x {
+		d += (v - y[i]) * (v - y[i])
+	}
+	return d
+}
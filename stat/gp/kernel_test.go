@@ -0,0 +1,109 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gp
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/diff/fd"
+	"gonum.org/v1/gonum/floats"
+)
+
+func kernels() []Kernel {
+	return []Kernel{
+		&RBF{Variance: 2, LengthScale: 0.7},
+		&Matern{Nu: 0.5, Variance: 1.5, LengthScale: 1.2},
+		&Matern{Nu: 1.5, Variance: 0.8, LengthScale: 0.5},
+		&Matern{Nu: 2.5, Variance: 3, LengthScale: 2},
+		&Periodic{Variance: 1.3, LengthScale: 0.9, Period: 1.7},
+	}
+}
+
+func TestKernelValues(t *testing.T) {
+	x := []float64{1, 2}
+	y := []float64{4, 6}
+	// |x - y| = 5.
+	for _, test := range []struct {
+		k    Kernel
+		want float64
+	}{
+		{k: &RBF{Variance: 2, LengthScale: 5}, want: 2 * math.Exp(-0.5)},
+		{k: &Matern{Nu: 0.5, Variance: 2, LengthScale: 5}, want: 2 * math.Exp(-1)},
+		{k: &Matern{Nu: 1.5, Variance: 2, LengthScale: 5}, want: 2 * (1 + math.Sqrt(3)) * math.Exp(-math.Sqrt(3))},
+		{k: &Matern{Nu: 2.5, Variance: 2, LengthScale: 5}, want: 2 * (1 + math.Sqrt(5) + 5.0/3) * math.Exp(-math.Sqrt(5))},
+		{k: &Periodic{Variance: 2, LengthScale: 0.5, Period: 20}, want: 2 * math.Exp(-2*0.5/0.25)},
+	} {
+		if got := test.k.Cov(x, y); math.Abs(got-test.want) > 1e-14 {
+			t.Errorf("unexpected covariance for %#v: got:%v want:%v", test.k, got, test.want)
+		}
+		if got := test.k.Cov(x, x); math.Abs(got-2) > 1e-14 {
+			t.Errorf("unexpected variance for %#v: got:%v want:2", test.k, got)
+		}
+		if got := test.k.Cov(y, x); got != test.k.Cov(x, y) {
+			t.Errorf("asymmetric covariance for %#v", test.k)
+		}
+	}
+
+	// The periodic kernel repeats with its period.
+	k := &Periodic{Variance: 1, LengthScale: 1, Period: 2}
+	if a, b := k.Cov([]float64{0}, []float64{0.3}), k.Cov([]float64{0}, []float64{4.3}); math.Abs(a-b) > 1e-14 {
+		t.Errorf("periodic kernel not periodic: %v != %v", a, b)
+	}
+
+	if !panics(func() { (&Matern{Nu: 1, Variance: 1, LengthScale: 1}).Cov(x, y) }) {
+		t.Error("expected panic for unsupported Matérn smoothness")
+	}
+	if !panics(func() { (&RBF{Variance: 1, LengthScale: 1}).Cov(x, []float64{1}) }) {
+		t.Error("expected panic for dimension mismatch")
+	}
+}
+
+func TestKernelHyper(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for _, k := range kernels() {
+		name := fmt.Sprintf("%#v", k)
+		p := make([]float64, k.NumHyper())
+		k.Hyper(p)
+		q := make([]float64, len(p))
+		for i := range q {
+			q[i] = rnd.NormFloat64()
+		}
+		k.SetHyper(q)
+		got := make([]float64, len(p))
+		k.Hyper(got)
+		if !floats.EqualApprox(got, q, 1e-14) {
+			t.Errorf("hyperparameters not round tripped for %s: got:%v want:%v", name, got, q)
+		}
+		k.SetHyper(p)
+
+		for trial := 0; trial < 10; trial++ {
+			x := []float64{rnd.NormFloat64(), rnd.NormFloat64()}
+			y := []float64{rnd.NormFloat64(), rnd.NormFloat64()}
+			grad := make([]float64, len(p))
+			k.CovGrad(grad, x, y)
+			want := fd.Gradient(nil, func(h []float64) float64 {
+				k.SetHyper(h)
+				c := k.Cov(x, y)
+				k.SetHyper(p)
+				return c
+			}, p, &fd.Settings{Formula: fd.Central})
+			if !floats.EqualApprox(grad, want, 1e-6) {
+				t.Errorf("unexpected gradient for %s: got:%v want:%v", name, grad, want)
+			}
+		}
+	}
+}
+
+func panics(fn func()) (ok bool) {
+	defer func() {
+		ok = recover() != nil
+	}()
+	fn()
+	return
+}
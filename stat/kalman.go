@@ -0,0 +1,368 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package stat
+
+import (
+	"errors"
+	"math"
+
+	"gonum.org/v1/gonum/diff/fd"
+	"gonum.org/v1/gonum/mat"
+)
+
+// ErrInnovationNotPD is returned by the Kalman filters when the covariance of
+// an observation prediction is not positive definite.
+var ErrInnovationNotPD = errors.New("stat: innovation covariance not positive definite")
+
+// StateSpace is a linear Gaussian state space model with possibly time-varying
+// system matrices. The state x_t and observation y_t at step t evolve as
+//  x_t = F_t * x_{t-1} + w_t,  w_t ~ N(0, Q_t),
+//  y_t = H_t * x_t + v_t,      v_t ~ N(0, R_t).
+type StateSpace interface {
+	// Transition returns the state transition
+	// matrix F_t for step t > 0.
+	Transition(t int) mat.Matrix
+
+	// ProcessNoise returns the covariance Q_t
+	// of the process noise for step t > 0.
+	ProcessNoise(t int) mat.Symmetric
+
+	// Observation returns the observation
+	// matrix H_t for step t.
+	Observation(t int) mat.Matrix
+
+	// ObservationNoise returns the covariance
+	// R_t of the observation noise for step t.
+	ObservationNoise(t int) mat.Symmetric
+}
+
+// TimeInvariant is a StateSpace with constant system matrices.
+type TimeInvariant struct {
+	F, H mat.Matrix
+	Q, R mat.Symmetric
+}
+
+// Transition returns s.F.
+func (s TimeInvariant) Transition(int) mat.Matrix { return s.F }
+
+// ProcessNoise returns s.Q.
+func (s TimeInvariant) ProcessNoise(int) mat.Symmetric { return s.Q }
+
+// Observation returns s.H.
+func (s TimeInvariant) Observation(int) mat.Matrix { return s.H }
+
+// ObservationNoise returns s.R.
+func (s TimeInvariant) ObservationNoise(int) mat.Symmetric { return s.R }
+
+// KalmanResult holds the results of Kalman filtering a sequence of
+// observations.
+type KalmanResult struct {
+	// PredMean and PredCov hold the mean and
+	// covariance of the state at each step
+	// given the observations before the step.
+	PredMean []*mat.VecDense
+	PredCov  []*mat.SymDense
+
+	// Mean and Cov hold the mean and covariance
+	// of the state at each step given the
+	// observations up to and including the step.
+	Mean []*mat.VecDense
+	Cov  []*mat.SymDense
+
+	// Transition holds the state transition
+	// matrix used to predict each step after
+	// the first. For the extended Kalman filter
+	// these are the Jacobians of the transition
+	// function. Transition[0] is nil.
+	Transition []*mat.Dense
+
+	// LogLikelihood is the log-likelihood of
+	// the observations.
+	LogLikelihood float64
+}
+
+// KalmanFilter performs Kalman filtering of the observations obs under the
+// linear Gaussian state space model sys, where the state at step 0 has the
+// prior mean mean0 and covariance cov0. The observation at step t is obs[t].
+// A nil observation is treated as missing and the state estimate is only
+// predicted at that step. Covariance updates use the Joseph form to preserve
+// symmetry and positive semi-definiteness.
+//
+// If the covariance of a predicted observation is not positive definite,
+// KalmanFilter returns ErrInnovationNotPD. KalmanFilter will panic if the
+// dimensions of the system matrices, prior and observations do not agree.
+//
+// See Kalman, R. E. "A new approach to linear filtering and prediction
+// problems." Journal of Basic Engineering 82(1):35–45 (1960).
+func KalmanFilter(sys StateSpace, mean0 mat.Vector, cov0 mat.Symmetric, obs [][]float64) (*KalmanResult, error) {
+	linear := func(dst *mat.VecDense, f *mat.Dense, m mat.Matrix, x *mat.VecDense) {
+		f.Copy(m)
+		dst.MulVec(m, x)
+	}
+	return kalmanFilter(mean0, cov0, obs,
+		func(t int, mean *mat.VecDense) (*mat.VecDense, *mat.Dense, mat.Symmetric) {
+			n := mean.Len()
+			x := mat.NewVecDense(n, nil)
+			f := mat.NewDense(n, n, nil)
+			linear(x, f, sys.Transition(t), mean)
+			return x, f, sys.ProcessNoise(t)
+		},
+		func(t int, mean *mat.VecDense, m int) (*mat.VecDense, *mat.Dense, mat.Symmetric) {
+			y := mat.NewVecDense(m, nil)
+			h := mat.NewDense(m, mean.Len(), nil)
+			linear(y, h, sys.Observation(t), mean)
+			return y, h, sys.ObservationNoise(t)
+		},
+	)
+}
+
+// NonlinearStateSpace is a nonlinear state space model with additive Gaussian
+// noise. The state x_t and observation y_t at step t evolve as
+//  x_t = f(x_{t-1}, t) + w_t,  w_t ~ N(0, Q),
+//  y_t = h(x_t, t) + v_t,      v_t ~ N(0, R).
+type NonlinearStateSpace struct {
+	// Transition stores f(x, t) into dst
+	// for steps t > 0.
+	Transition func(dst, x []float64, t int)
+
+	// Observation stores h(x, t) into dst.
+	Observation func(dst, x []float64, t int)
+
+	// Q and R are the covariances of the
+	// process and observation noise.
+	Q, R mat.Symmetric
+
+	// Jacobian holds the settings used to
+	// approximate the Jacobians of Transition
+	// and Observation. If Jacobian is nil the
+	// central difference formula is used.
+	Jacobian *fd.JacobianSettings
+}
+
+// ExtendedKalmanFilter performs extended Kalman filtering of the observations
+// obs under the nonlinear state space model sys, where the state at step 0
+// has the prior mean mean0 and covariance cov0. At each step the transition
+// and observation functions are linearized about the current state estimate
+// using finite difference approximations of their Jacobians computed by
+// fd.Jacobian. A nil observation is treated as missing and the state estimate
+// is only predicted at that step.
+//
+// If the covariance of a predicted observation is not positive definite,
+// ExtendedKalmanFilter returns ErrInnovationNotPD. ExtendedKalmanFilter will
+// panic if the dimensions of the noise covariances, prior and observations do
+// not agree.
+func ExtendedKalmanFilter(sys NonlinearStateSpace, mean0 mat.Vector, cov0 mat.Symmetric, obs [][]float64) (*KalmanResult, error) {
+	settings := sys.Jacobian
+	if settings == nil {
+		settings = &fd.JacobianSettings{Formula: fd.Central}
+	}
+	return kalmanFilter(mean0, cov0, obs,
+		func(t int, mean *mat.VecDense) (*mat.VecDense, *mat.Dense, mat.Symmetric) {
+			n := mean.Len()
+			x := mat.NewVecDense(n, nil)
+			at := mean.RawVector().Data
+			sys.Transition(x.RawVector().Data, at, t)
+			f := mat.NewDense(n, n, nil)
+			fd.Jacobian(f, func(dst, x []float64) { sys.Transition(dst, x, t) }, at, settings)
+			return x, f, sys.Q
+		},
+		func(t int, mean *mat.VecDense, m int) (*mat.VecDense, *mat.Dense, mat.Symmetric) {
+			y := mat.NewVecDense(m, nil)
+			at := mean.RawVector().Data
+			sys.Observation(y.RawVector().Data, at, t)
+			h := mat.NewDense(m, mean.Len(), nil)
+			fd.Jacobian(h, func(dst, x []float64) { sys.Observation(dst, x, t) }, at, settings)
+			return y, h, sys.R
+		},
+	)
+}
+
+// kalmanFilter implements Kalman filtering with the linearized transition
+// and observation models returned by predict and observe. predict returns
+// the predicted mean, the transition matrix and the process noise for step
+// t given the previous mean. observe returns the predicted observation of
+// length m, the observation matrix and the observation noise for step t
+// given the predicted mean.
+func kalmanFilter(mean0 mat.Vector, cov0 mat.Symmetric, obs [][]float64,
+	predict func(t int, mean *mat.VecDense) (*mat.VecDense, *mat.Dense, mat.Symmetric),
+	observe func(t int, mean *mat.VecDense, m int) (*mat.VecDense, *mat.Dense, mat.Symmetric),
+) (*KalmanResult, error) {
+	n := mean0.Len()
+	if cov0.Symmetric() != n {
+		panic(mat.ErrShape)
+	}
+	steps := len(obs)
+	r := &KalmanResult{
+		PredMean:   make([]*mat.VecDense, steps),
+		PredCov:    make([]*mat.SymDense, steps),
+		Mean:       make([]*mat.VecDense, steps),
+		Cov:        make([]*mat.SymDense, steps),
+		Transition: make([]*mat.Dense, steps),
+	}
+	for t, y := range obs {
+		// Predict.
+		var (
+			mean *mat.VecDense
+			cov  *mat.SymDense
+		)
+		if t == 0 {
+			mean = mat.NewVecDense(n, nil)
+			mean.CloneVec(mean0)
+			cov = mat.NewSymDense(n, nil)
+			cov.CopySym(cov0)
+		} else {
+			var f *mat.Dense
+			var q mat.Symmetric
+			mean, f, q = predict(t, r.Mean[t-1])
+			if q.Symmetric() != n {
+				panic(mat.ErrShape)
+			}
+			var fp mat.Dense
+			fp.Mul(f, r.Cov[t-1])
+			var fpf mat.Dense
+			fpf.Mul(&fp, f.T())
+			cov = mat.NewSymDense(n, nil)
+			for i := 0; i < n; i++ {
+				for j := i; j < n; j++ {
+					cov.SetSym(i, j, (fpf.At(i, j)+fpf.At(j, i))/2+q.At(i, j))
+				}
+			}
+			r.Transition[t] = f
+		}
+		r.PredMean[t] = mean
+		r.PredCov[t] = cov
+		if y == nil {
+			r.Mean[t] = mean
+			r.Cov[t] = cov
+			continue
+		}
+
+		// Update.
+		m := len(y)
+		yhat, h, rn := observe(t, mean, m)
+		if rn.Symmetric() != m {
+			panic(mat.ErrShape)
+		}
+		var ph mat.Dense
+		ph.Mul(cov, h.T())
+		s := mat.NewSymDense(m, nil)
+		var hph mat.Dense
+		hph.Mul(h, &ph)
+		for i := 0; i < m; i++ {
+			for j := i; j < m; j++ {
+				s.SetSym(i, j, (hph.At(i, j)+hph.At(j, i))/2+rn.At(i, j))
+			}
+		}
+		var chol mat.Cholesky
+		if !chol.Factorize(s) {
+			return nil, ErrInnovationNotPD
+		}
+
+		// The Kalman gain K = P * H^T * S^-1 is
+		// found by solving S * K^T = H * P.
+		var kt mat.Dense
+		err := chol.Solve(&kt, ph.T())
+		if err != nil {
+			if _, ok := err.(mat.Condition); !ok {
+				return nil, err
+			}
+		}
+		innov := mat.NewVecDense(m, nil)
+		innov.SubVec(mat.NewVecDense(m, y), yhat)
+		var upd mat.VecDense
+		upd.MulVec(kt.T(), innov)
+		post := mat.NewVecDense(n, nil)
+		post.AddVec(mean, &upd)
+
+		// Joseph form covariance update
+		//  (I - K*H) * P * (I - K*H)^T + K * R * K^T.
+		ikh := mat.NewDense(n, n, nil)
+		ikh.Mul(kt.T(), h)
+		ikh.Scale(-1, ikh)
+		for i := 0; i < n; i++ {
+			ikh.Set(i, i, ikh.At(i, i)+1)
+		}
+		var a, b, kr, krk mat.Dense
+		a.Mul(ikh, cov)
+		b.Mul(&a, ikh.T())
+		kr.Mul(kt.T(), rn)
+		krk.Mul(&kr, &kt)
+		pc := mat.NewSymDense(n, nil)
+		for i := 0; i < n; i++ {
+			for j := i; j < n; j++ {
+				pc.SetSym(i, j, (b.At(i, j)+b.At(j, i)+krk.At(i, j)+krk.At(j, i))/2)
+			}
+		}
+		r.Mean[t] = post
+		r.Cov[t] = pc
+
+		var sinv mat.VecDense
+		err = chol.SolveVec(&sinv, innov)
+		if err != nil {
+			if _, ok := err.(mat.Condition); !ok {
+				return nil, err
+			}
+		}
+		r.LogLikelihood -= 0.5 * (mat.Dot(innov, &sinv) + chol.LogDet() + float64(m)*math.Log(2*math.Pi))
+	}
+	return r, nil
+}
+
+// RTSSmoother performs Rauch–Tung–Striebel fixed-interval smoothing of the
+// state estimates in the filtering result r, returning the mean and
+// covariance of the state at each step given all of the observations. The
+// smoother uses the transition matrices stored in r, so it applies to the
+// results of both KalmanFilter and ExtendedKalmanFilter.
+//
+// If a predicted state covariance is singular, RTSSmoother returns an error.
+//
+// See Rauch, H. E., Tung, F. and Striebel, C. T. "Maximum likelihood estimates
+// of linear dynamic systems." AIAA Journal 3(8):1445–1450 (1965).
+func RTSSmoother(r *KalmanResult) (mean []*mat.VecDense, cov []*mat.SymDense, err error) {
+	steps := len(r.Mean)
+	mean = make([]*mat.VecDense, steps)
+	cov = make([]*mat.SymDense, steps)
+	if steps == 0 {
+		return mean, cov, nil
+	}
+	mean[steps-1] = mat.VecDenseCopyOf(r.Mean[steps-1])
+	cov[steps-1] = mat.NewSymDense(r.Cov[steps-1].Symmetric(), nil)
+	cov[steps-1].CopySym(r.Cov[steps-1])
+	for t := steps - 2; t >= 0; t-- {
+		n := r.Mean[t].Len()
+
+		// The smoother gain C = P_t * F^T * (P^-_{t+1})^-1
+		// is found by solving P^-_{t+1} * C^T = F * P_t.
+		var chol mat.Cholesky
+		if !chol.Factorize(r.PredCov[t+1]) {
+			return nil, nil, errors.New("stat: predicted covariance not positive definite")
+		}
+		var fp, ct mat.Dense
+		fp.Mul(r.Transition[t+1], r.Cov[t])
+		err = chol.Solve(&ct, &fp)
+		if err != nil {
+			if _, ok := err.(mat.Condition); !ok {
+				return nil, nil, err
+			}
+		}
+		var dm, step mat.VecDense
+		dm.SubVec(mean[t+1], r.PredMean[t+1])
+		step.MulVec(ct.T(), &dm)
+		mean[t] = mat.NewVecDense(n, nil)
+		mean[t].AddVec(r.Mean[t], &step)
+
+		var dp, cd, cdc mat.Dense
+		dp.Sub(cov[t+1], r.PredCov[t+1])
+		cd.Mul(ct.T(), &dp)
+		cdc.Mul(&cd, &ct)
+		cov[t] = mat.NewSymDense(n, nil)
+		for i := 0; i < n; i++ {
+			for j := i; j < n; j++ {
+				cov[t].SetSym(i, j, r.Cov[t].At(i, j)+(cdc.At(i, j)+cdc.At(j, i))/2)
+			}
+		}
+	}
+	return mean, cov, nil
+}
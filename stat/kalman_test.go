@@ -0,0 +1,416 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package stat
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// varyingStep is a constant velocity model in one dimension
+// observed in position with time-varying sampling intervals.
+type varyingStep struct {
+	dt []float64
+	q  float64
+	r  float64
+}
+
+func (s varyingStep) Transition(t int) mat.Matrix {
+	return mat.NewDense(2, 2, []float64{1, s.dt[t], 0, 1})
+}
+
+func (s varyingStep) ProcessNoise(t int) mat.Symmetric {
+	dt := s.dt[t]
+	return mat.NewSymDense(2, []float64{
+		s.q * dt * dt * dt / 3, s.q * dt * dt / 2,
+		s.q * dt * dt / 2, s.q * dt,
+	})
+}
+
+func (s varyingStep) Observation(int) mat.Matrix {
+	return mat.NewDense(1, 2, []float64{1, 0})
+}
+
+func (s varyingStep) ObservationNoise(t int) mat.Symmetric {
+	return mat.NewSymDense(1, []float64{s.r * (1 + float64(t%3))})
+}
+
+// kalmanBatch returns the mean and covariance of the states of sys given all
+// of the observations and the log-likelihood of the observations, computed by
+// conditioning the joint Gaussian distribution of the states and observations.
+func kalmanBatch(sys StateSpace, mean0 *mat.VecDense, cov0 *mat.SymDense, obs [][]float64) (mean []*mat.VecDense, cov []*mat.SymDense, ll float64) {
+	n := mean0.Len()
+	steps := len(obs)
+
+	// Marginal means and covariances of the states, and the
+	// cross covariance Cov(x_t, x_s) = F_t...F_{s+1} * P_s.
+	mu := mat.NewVecDense(n*steps, nil)
+	c := mat.NewDense(n*steps, n*steps, nil)
+	p := mat.DenseCopyOf(cov0)
+	m := mat.VecDenseCopyOf(mean0)
+	for s := 0; s < steps; s++ {
+		if s > 0 {
+			var fm mat.VecDense
+			fm.MulVec(sys.Transition(s), m)
+			m = &fm
+			var fp, fpf mat.Dense
+			fp.Mul(sys.Transition(s), p)
+			fpf.Mul(&fp, sys.Transition(s).T())
+			fpf.Add(&fpf, sys.ProcessNoise(s))
+			p = &fpf
+		}
+		for i := 0; i < n; i++ {
+			mu.SetVec(s*n+i, m.AtVec(i))
+		}
+		phi := mat.NewDense(n, n, nil)
+		for i := 0; i < n; i++ {
+			phi.Set(i, i, 1)
+		}
+		for t := s; t < steps; t++ {
+			if t > s {
+				var next mat.Dense
+				next.Mul(sys.Transition(t), phi)
+				phi = &next
+			}
+			var blk mat.Dense
+			blk.Mul(phi, p)
+			for i := 0; i < n; i++ {
+				for j := 0; j < n; j++ {
+					c.Set(t*n+i, s*n+j, blk.At(i, j))
+					c.Set(s*n+j, t*n+i, blk.At(i, j))
+				}
+			}
+		}
+	}
+
+	// Stack the observation model for the observed steps.
+	var rows int
+	for _, y := range obs {
+		rows += len(y)
+	}
+	h := mat.NewDense(rows, n*steps, nil)
+	rn := mat.NewDense(rows, rows, nil)
+	yv := mat.NewVecDense(rows, nil)
+	var off int
+	for t, y := range obs {
+		ht := sys.Observation(t)
+		rt := sys.ObservationNoise(t)
+		for i := range y {
+			yv.SetVec(off+i, y[i])
+			for j := 0; j < n; j++ {
+				h.Set(off+i, t*n+j, ht.At(i, j))
+			}
+			for j := range y {
+				rn.Set(off+i, off+j, rt.At(i, j))
+			}
+		}
+		off += len(y)
+	}
+
+	mean = make([]*mat.VecDense, steps)
+	cov = make([]*mat.SymDense, steps)
+	post := mu
+	pc := c
+	if rows != 0 {
+		var ch, syy mat.Dense
+		ch.Mul(c, h.T())
+		syy.Mul(h, &ch)
+		syy.Add(&syy, rn)
+		var chol mat.Cholesky
+		if !chol.Factorize(mat.NewSymDense(rows, syy.RawMatrix().Data)) {
+			panic("observation covariance not positive definite")
+		}
+		var hmu, resid, alpha mat.VecDense
+		hmu.MulVec(h, mu)
+		resid.SubVec(yv, &hmu)
+		chol.SolveVec(&alpha, &resid)
+		ll = -0.5 * (mat.Dot(&resid, &alpha) + chol.LogDet() + float64(rows)*math.Log(2*math.Pi))
+
+		post = mat.NewVecDense(n*steps, nil)
+		post.MulVec(&ch, &alpha)
+		post.AddVec(mu, post)
+		var gain mat.Dense
+		chol.Solve(&gain, ch.T())
+		pc = mat.NewDense(n*steps, n*steps, nil)
+		pc.Mul(&ch, &gain)
+		pc.Sub(c, pc)
+	}
+
+	for t := 0; t < steps; t++ {
+		mean[t] = mat.NewVecDense(n, nil)
+		cov[t] = mat.NewSymDense(n, nil)
+		for i := 0; i < n; i++ {
+			mean[t].SetVec(i, post.AtVec(t*n+i))
+			for j := i; j < n; j++ {
+				cov[t].SetSym(i, j, pc.At(t*n+i, t*n+j))
+			}
+		}
+	}
+	return mean, cov, ll
+}
+
+func TestKalmanFilterSmoother(t *testing.T) {
+	const tol = 1e-9
+	rnd := rand.New(rand.NewSource(1))
+	for _, test := range []struct {
+		name  string
+		sys   StateSpace
+		mean0 *mat.VecDense
+		cov0  *mat.SymDense
+		steps int
+		dim   int
+		drop  []int
+	}{
+		{
+			name: "random walk",
+			sys: TimeInvariant{
+				F: mat.NewDense(1, 1, []float64{1}),
+				H: mat.NewDense(1, 1, []float64{1}),
+				Q: mat.NewSymDense(1, []float64{0.5}),
+				R: mat.NewSymDense(1, []float64{2}),
+			},
+			mean0: mat.NewVecDense(1, []float64{1}),
+			cov0:  mat.NewSymDense(1, []float64{3}),
+			steps: 20,
+			dim:   1,
+		},
+		{
+			name: "two dimensional",
+			sys: TimeInvariant{
+				F: mat.NewDense(2, 2, []float64{0.9, 0.2, -0.1, 0.8}),
+				H: mat.NewDense(3, 2, []float64{1, 0, 0, 1, 1, 1}),
+				Q: mat.NewSymDense(2, []float64{0.3, 0.1, 0.1, 0.2}),
+				R: mat.NewSymDense(3, []float64{1, 0.2, 0, 0.2, 0.5, 0.1, 0, 0.1, 0.8}),
+			},
+			mean0: mat.NewVecDense(2, []float64{0, 1}),
+			cov0:  mat.NewSymDense(2, []float64{2, 0.5, 0.5, 1}),
+			steps: 15,
+			dim:   3,
+			drop:  []int{0, 4, 5},
+		},
+		{
+			name:  "time-varying",
+			sys:   varyingStep{dt: []float64{0, 1, 0.5, 2, 0.1, 1, 3, 0.7, 1, 1, 0.2}, q: 0.1, r: 0.5},
+			mean0: mat.NewVecDense(2, []float64{0, 1}),
+			cov0:  mat.NewSymDense(2, []float64{1, 0, 0, 1}),
+			steps: 11,
+			dim:   1,
+			drop:  []int{3},
+		},
+	} {
+		obs := make([][]float64, test.steps)
+		for i := range obs {
+			obs[i] = make([]float64, test.dim)
+			for j := range obs[i] {
+				obs[i][j] = rnd.NormFloat64() + float64(i)
+			}
+		}
+		for _, i := range test.drop {
+			obs[i] = nil
+		}
+
+		r, err := KalmanFilter(test.sys, test.mean0, test.cov0, obs)
+		if err != nil {
+			t.Fatalf("unexpected error for %s: %v", test.name, err)
+		}
+		mean, cov, err := RTSSmoother(r)
+		if err != nil {
+			t.Fatalf("unexpected smoother error for %s: %v", test.name, err)
+		}
+		wantMean, wantCov, wantLL := kalmanBatch(test.sys, test.mean0, test.cov0, obs)
+		if math.Abs(r.LogLikelihood-wantLL) > tol*math.Abs(wantLL) {
+			t.Errorf("unexpected log-likelihood for %s: got:%v want:%v", test.name, r.LogLikelihood, wantLL)
+		}
+		for i := range mean {
+			if !mat.EqualApprox(mean[i], wantMean[i], tol) {
+				t.Errorf("unexpected smoothed mean for %s at step %d: got:%v want:%v",
+					test.name, i, mat.Formatted(mean[i].T()), mat.Formatted(wantMean[i].T()))
+			}
+			if !mat.EqualApprox(cov[i], wantCov[i], tol) {
+				t.Errorf("unexpected smoothed covariance for %s at step %d:\ngot:\n%v\nwant:\n%v",
+					test.name, i, mat.Formatted(cov[i]), mat.Formatted(wantCov[i]))
+			}
+		}
+
+		// The filtered estimate at each step conditions
+		// only on the observations up to that step.
+		for _, i := range []int{0, test.steps / 2, test.steps - 1} {
+			part, partCov, _ := kalmanBatch(test.sys, test.mean0, test.cov0, obs[:i+1])
+			if !mat.EqualApprox(r.Mean[i], part[i], tol) || !mat.EqualApprox(r.Cov[i], partCov[i], tol) {
+				t.Errorf("unexpected filtered estimate for %s at step %d: got:%v want:%v",
+					test.name, i, mat.Formatted(r.Mean[i].T()), mat.Formatted(part[i].T()))
+			}
+		}
+	}
+}
+
+func TestKalmanFilterSteadyState(t *testing.T) {
+	// The covariance of the scalar random walk converges to
+	// the solution of the discrete algebraic Riccati equation.
+	const q, r = 0.5, 2.0
+	sys := TimeInvariant{
+		F: mat.NewDense(1, 1, []float64{1}),
+		H: mat.NewDense(1, 1, []float64{1}),
+		Q: mat.NewSymDense(1, []float64{q}),
+		R: mat.NewSymDense(1, []float64{r}),
+	}
+	obs := make([][]float64, 100)
+	for i := range obs {
+		obs[i] = []float64{float64(i)}
+	}
+	res, err := KalmanFilter(sys, mat.NewVecDense(1, nil), mat.NewSymDense(1, []float64{100}), obs)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	pred := (q + math.Sqrt(q*q+4*q*r)) / 2
+	want := pred * r / (pred + r)
+	if got := res.Cov[len(obs)-1].At(0, 0); math.Abs(got-want) > 1e-12 {
+		t.Errorf("unexpected steady state variance: got:%v want:%v", got, want)
+	}
+	if got := res.PredCov[len(obs)-1].At(0, 0); math.Abs(got-pred) > 1e-12 {
+		t.Errorf("unexpected steady state predicted variance: got:%v want:%v", got, pred)
+	}
+
+	// A singular innovation covariance is reported.
+	sys.H = mat.NewDense(1, 1, []float64{0})
+	sys.R = mat.NewSymDense(1, []float64{0})
+	_, err = KalmanFilter(sys, mat.NewVecDense(1, nil), mat.NewSymDense(1, []float64{1}), obs)
+	if err != ErrInnovationNotPD {
+		t.Errorf("unexpected error for singular innovation covariance: got:%v want:%v", err, ErrInnovationNotPD)
+	}
+}
+
+func TestExtendedKalmanFilter(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+
+	// For a linear model the extended Kalman filter
+	// agrees with the Kalman filter.
+	lin := TimeInvariant{
+		F: mat.NewDense(2, 2, []float64{0.9, 0.2, -0.1, 0.8}),
+		H: mat.NewDense(1, 2, []float64{1, 2}),
+		Q: mat.NewSymDense(2, []float64{0.3, 0.1, 0.1, 0.2}),
+		R: mat.NewSymDense(1, []float64{0.5}),
+	}
+	obs := make([][]float64, 20)
+	for i := range obs {
+		obs[i] = []float64{rnd.NormFloat64()}
+	}
+	obs[7] = nil
+	mean0 := mat.NewVecDense(2, []float64{1, -1})
+	cov0 := mat.NewSymDense(2, []float64{1, 0.2, 0.2, 2})
+	want, err := KalmanFilter(lin, mean0, cov0, obs)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	apply := func(m mat.Matrix) func(dst, x []float64, _ int) {
+		return func(dst, x []float64, _ int) {
+			r, _ := m.Dims()
+			mat.NewVecDense(r, dst).MulVec(m, mat.NewVecDense(len(x), x))
+		}
+	}
+	got, err := ExtendedKalmanFilter(NonlinearStateSpace{
+		Transition:  apply(lin.F),
+		Observation: apply(lin.H),
+		Q:           lin.Q,
+		R:           lin.R,
+	}, mean0, cov0, obs)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	const tol = 1e-6
+	if math.Abs(got.LogLikelihood-want.LogLikelihood) > tol {
+		t.Errorf("unexpected log-likelihood: got:%v want:%v", got.LogLikelihood, want.LogLikelihood)
+	}
+	for i := range obs {
+		if !mat.EqualApprox(got.Mean[i], want.Mean[i], tol) || !mat.EqualApprox(got.Cov[i], want.Cov[i], tol) {
+			t.Errorf("unexpected estimate at step %d: got:%v want:%v",
+				i, mat.Formatted(got.Mean[i].T()), mat.Formatted(want.Mean[i].T()))
+		}
+	}
+
+	// Track a target moving with constant velocity in
+	// the plane from range and bearing observations.
+	const (
+		steps = 50
+		dt    = 1.0
+		q     = 1e-3
+		sdR   = 0.5
+		sdB   = 0.01
+	)
+	sys := NonlinearStateSpace{
+		Transition: func(dst, x []float64, _ int) {
+			dst[0] = x[0] + dt*x[2]
+			dst[1] = x[1] + dt*x[3]
+			dst[2] = x[2]
+			dst[3] = x[3]
+		},
+		Observation: func(dst, x []float64, _ int) {
+			dst[0] = math.Hypot(x[0], x[1])
+			dst[1] = math.Atan2(x[1], x[0])
+		},
+		Q: mat.NewSymDense(4, []float64{
+			q, 0, 0, 0,
+			0, q, 0, 0,
+			0, 0, q, 0,
+			0, 0, 0, q,
+		}),
+		R: mat.NewSymDense(2, []float64{sdR * sdR, 0, 0, sdB * sdB}),
+	}
+	truth := make([][]float64, steps)
+	x := []float64{20, 10, 1, -0.5}
+	obs = make([][]float64, steps)
+	for i := range truth {
+		if i > 0 {
+			next := make([]float64, 4)
+			sys.Transition(next, x, i)
+			for j := range next {
+				next[j] += math.Sqrt(q) * rnd.NormFloat64()
+			}
+			x = next
+		}
+		truth[i] = x
+		obs[i] = make([]float64, 2)
+		sys.Observation(obs[i], x, i)
+		obs[i][0] += sdR * rnd.NormFloat64()
+		obs[i][1] += sdB * rnd.NormFloat64()
+	}
+	res, err := ExtendedKalmanFilter(sys, mat.NewVecDense(4, []float64{19, 11, 0, 0}), mat.NewSymDense(4, []float64{
+		4, 0, 0, 0,
+		0, 4, 0, 0,
+		0, 0, 1, 0,
+		0, 0, 0, 1,
+	}), obs)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	smoothed, _, err := RTSSmoother(res)
+	if err != nil {
+		t.Fatalf("unexpected smoother error: %v", err)
+	}
+	var filtErr, smoothErr float64
+	for i := steps / 2; i < steps; i++ {
+		filtErr += math.Hypot(res.Mean[i].AtVec(0)-truth[i][0], res.Mean[i].AtVec(1)-truth[i][1])
+		smoothErr += math.Hypot(smoothed[i].AtVec(0)-truth[i][0], smoothed[i].AtVec(1)-truth[i][1])
+	}
+	filtErr /= steps / 2
+	smoothErr /= steps / 2
+	if filtErr > sdR {
+		t.Errorf("unexpectedly large mean filtered position error: got:%v want:<%v", filtErr, sdR)
+	}
+	if smoothErr > filtErr {
+		t.Errorf("smoothing increased mean position error: filtered:%v smoothed:%v", filtErr, smoothErr)
+	}
+	for j := 2; j < 4; j++ {
+		got := res.Mean[steps-1].AtVec(j)
+		sd := math.Sqrt(res.Cov[steps-1].At(j, j))
+		if want := truth[steps-1][j]; math.Abs(got-want) > 3*sd {
+			t.Errorf("final velocity outside 3 standard deviations: got:%v±%v want:%v", got, sd, want)
+		}
+	}
+}
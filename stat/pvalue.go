@@ -0,0 +1,111 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package stat
+
+import (
+	"math"
+	"sort"
+)
+
+// Bonferroni returns the Bonferroni adjusted p-values for the family of
+// hypotheses with unadjusted p-values p,
+//  adj_i = min(1, m * p_i),
+// where m is the number of hypotheses. Rejecting the hypotheses with adjusted
+// p-values at most α controls the family-wise error rate at level α.
+//
+// If dst is nil, a new slice is allocated and returned, otherwise dst must
+// have the same length as p and is returned. dst may be p. Bonferroni will
+// panic if any p-value is outside [0, 1].
+func Bonferroni(dst, p []float64) []float64 {
+	dst = pvalueDst(dst, p)
+	m := float64(len(p))
+	for i, v := range p {
+		dst[i] = math.Min(1, m*v)
+	}
+	return dst
+}
+
+// Holm returns the Holm–Bonferroni adjusted p-values for the family of
+// hypotheses with unadjusted p-values p. With the p-values in increasing
+// order p_(1) <= ... <= p_(m), the adjusted p-values are
+//  adj_(i) = max_{j <= i} min(1, (m - j + 1) * p_(j)).
+// Rejecting the hypotheses with adjusted p-values at most α controls the
+// family-wise error rate at level α, and Holm's step-down procedure rejects
+// at least as many hypotheses as the Bonferroni correction.
+//
+// If dst is nil, a new slice is allocated and returned, otherwise dst must
+// have the same length as p and is returned. dst may be p. Holm will panic
+// if any p-value is outside [0, 1].
+//
+// See Holm, S. "A simple sequentially rejective multiple test procedure."
+// Scandinavian Journal of Statistics 6(2):65–70 (1979).
+func Holm(dst, p []float64) []float64 {
+	dst = pvalueDst(dst, p)
+	idx := argsort(p)
+	m := len(p)
+	var max float64
+	for i, k := range idx {
+		max = math.Max(max, math.Min(1, float64(m-i)*p[k]))
+		dst[k] = max
+	}
+	return dst
+}
+
+// BenjaminiHochberg returns the Benjamini–Hochberg adjusted p-values, or
+// q-values, for the family of hypotheses with unadjusted p-values p. With the
+// p-values in increasing order p_(1) <= ... <= p_(m), the adjusted p-values
+// are
+//  adj_(i) = min_{j >= i} min(1, m / j * p_(j)).
+// Rejecting the hypotheses with adjusted p-values at most α controls the
+// false discovery rate, the expected proportion of false rejections among
+// all rejections, at level α when the tests are independent or positively
+// dependent.
+//
+// If dst is nil, a new slice is allocated and returned, otherwise dst must
+// have the same length as p and is returned. dst may be p.
+// BenjaminiHochberg will panic if any p-value is outside [0, 1].
+//
+// See Benjamini, Y. and Hochberg, Y. "Controlling the false discovery rate:
+// a practical and powerful approach to multiple testing." Journal of the
+// Royal Statistical Society, Series B 57(1):289–300 (1995).
+func BenjaminiHochberg(dst, p []float64) []float64 {
+	dst = pvalueDst(dst, p)
+	idx := argsort(p)
+	m := len(p)
+	min := 1.0
+	for i := m - 1; i >= 0; i-- {
+		k := idx[i]
+		min = math.Min(min, float64(m)/float64(i+1)*p[k])
+		dst[k] = min
+	}
+	return dst
+}
+
+// pvalueDst checks the p-values in p and returns dst, allocating it if it
+// is nil.
+func pvalueDst(dst, p []float64) []float64 {
+	for _, v := range p {
+		if !(0 <= v && v <= 1) {
+			panic("stat: p-value out of range")
+		}
+	}
+	if dst == nil {
+		return make([]float64, len(p))
+	}
+	if len(dst) != len(p) {
+		panic("stat: slice length mismatch")
+	}
+	return dst
+}
+
+// argsort returns the indices of x in increasing order of value.
+func argsort(x []float64) []int {
+	idx := make([]int, len(x))
+	for i := range idx {
+		idx[i] = i
+	}
+	sort.SliceStable(idx, func(i, j int) bool { return x[idx[i]] < x[idx[j]] })
+	return idx
+}
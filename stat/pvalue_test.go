@@ -0,0 +1,83 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package stat
+
+import (
+	"testing"
+
+	"gonum.org/v1/gonum/floats"
+)
+
+func TestPValueAdjustment(t *testing.T) {
+	for _, test := range []struct {
+		name string
+		fn   func(dst, p []float64) []float64
+		p    []float64
+		want []float64
+	}{
+		{
+			name: "Bonferroni",
+			fn:   Bonferroni,
+			p:    []float64{0.01, 0.04, 0.03, 0.005, 0.2},
+			want: []float64{0.05, 0.2, 0.15, 0.025, 1},
+		},
+		{
+			name: "Holm",
+			fn:   Holm,
+			p:    []float64{0.01, 0.04, 0.03, 0.005, 0.2},
+			want: []float64{0.04, 0.09, 0.09, 0.025, 0.2},
+		},
+		{
+			name: "BenjaminiHochberg",
+			fn:   BenjaminiHochberg,
+			p:    []float64{0.01, 0.04, 0.03, 0.005, 0.2},
+			want: []float64{0.025, 0.05, 0.05, 0.025, 0.2},
+		},
+		{
+			name: "Holm ties",
+			fn:   Holm,
+			p:    []float64{0.3, 0.1, 0.3, 0.1},
+			want: []float64{0.6, 0.4, 0.6, 0.4},
+		},
+		{
+			name: "BenjaminiHochberg ties",
+			fn:   BenjaminiHochberg,
+			p:    []float64{0.3, 0.1, 0.3, 0.1},
+			want: []float64{0.3, 0.2, 0.3, 0.2},
+		},
+		{
+			name: "Holm saturated",
+			fn:   Holm,
+			p:    []float64{0.9, 0.5, 1},
+			want: []float64{1, 1, 1},
+		},
+		{
+			name: "empty",
+			fn:   BenjaminiHochberg,
+			p:    []float64{},
+			want: []float64{},
+		},
+	} {
+		got := test.fn(nil, test.p)
+		if !floats.EqualApprox(got, test.want, 1e-14) {
+			t.Errorf("unexpected adjusted p-values for %s: got:%v want:%v", test.name, got, test.want)
+		}
+
+		p := append([]float64(nil), test.p...)
+		got = test.fn(p, p)
+		if !floats.EqualApprox(got, test.want, 1e-14) {
+			t.Errorf("unexpected in place adjusted p-values for %s: got:%v want:%v", test.name, got, test.want)
+		}
+	}
+
+	for _, fn := range []func(dst, p []float64) []float64{Bonferroni, Holm, BenjaminiHochberg} {
+		if !panics(func() { fn(nil, []float64{0.5, 1.5}) }) {
+			t.Error("expected panic for p-value out of range")
+		}
+		if !panics(func() { fn(make([]float64, 1), []float64{0.5, 0.5}) }) {
+			t.Error("expected panic for length mismatch")
+		}
+	}
+}
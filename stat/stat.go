@@ -149,6 +149,76 @@ func CircularMean(x, weights []float64) float64 {
 	return math.Atan2(aY, aX)
 }
 
+// CircularStdDev returns the circular standard deviation of the dataset.
+//	sqrt(-2 * log(R))
+// where R is the mean resultant length of the angles,
+//	R = sqrt((\sum_i w_i * cos(alpha_i))^2 + (\sum_i w_i * sin(alpha_i))^2) / \sum_i w_i.
+// If weights is nil then all of the weights are 1. If weights is not nil, then
+// len(x) must equal len(weights).
+func CircularStdDev(x, weights []float64) float64 {
+	return math.Sqrt(-2 * math.Log(meanResultantLength(x, weights)))
+}
+
+// CircularVariance returns the circular variance of the dataset.
+//	1 - R
+// where R is the mean resultant length of the angles,
+//	R = sqrt((\sum_i w_i * cos(alpha_i))^2 + (\sum_i w_i * sin(alpha_i))^2) / \sum_i w_i.
+// The circular variance is in [0, 1], with 0 when all angles are equal.
+// If weights is nil then all of the weights are 1. If weights is not nil, then
+// len(x) must equal len(weights).
+func CircularVariance(x, weights []float64) float64 {
+	return 1 - meanResultantLength(x, weights)
+}
+
+// meanResultantLength returns the length of the weighted mean of the
+// unit vectors with angles x.
+func meanResultantLength(x, weights []float64) float64 {
+	if weights != nil && len(x) != len(weights) {
+		panic("stat: slice length mismatch")
+	}
+
+	var aX, aY, sumWeights float64
+	if weights != nil {
+		for i, v := range x {
+			aX += weights[i] * math.Cos(v)
+			aY += weights[i] * math.Sin(v)
+			sumWeights += weights[i]
+		}
+	} else {
+		for _, v := range x {
+			aX += math.Cos(v)
+			aY += math.Sin(v)
+		}
+		sumWeights = float64(len(x))
+	}
+
+	return math.Hypot(aX, aY) / sumWeights
+}
+
+// RayleighTest performs the Rayleigh test for uniformity of the angles x
+// against the alternative of a unimodal distribution. It returns the
+// Rayleigh statistic
+//	z = n * R^2,
+// where n is the number of angles and R is their mean resultant length, and
+// the approximate p-value of z under the null hypothesis that the angles are
+// uniformly distributed,
+//	p = exp(sqrt(1 + 4*n + 4*(n^2 - (n*R)^2)) - (1 + 2*n)).
+// Small p-values indicate that the angles are concentrated about a mean
+// direction. RayleighTest will panic if x is empty.
+//
+// See Zar, J. H. "Biostatistical Analysis", 5th ed., Section 27.1 (2010).
+func RayleighTest(x []float64) (z, p float64) {
+	if len(x) == 0 {
+		panic("stat: zero length slice")
+	}
+	n := float64(len(x))
+	r := meanResultantLength(x, nil)
+	nr := n * r
+	z = nr * r
+	p = math.Exp(math.Sqrt(1+4*n+4*(n*n-nr*nr)) - (1 + 2*n))
+	return z, math.Min(p, 1)
+}
+
 // Correlation returns the weighted correlation between the samples of x and y
 // with the given means.
 //  sum_i {w_i (x_i - meanX) * (y_i - meanY)} / (stdX * stdY)
@@ -10,6 +10,8 @@ import (
 	"reflect"
 	"testing"
 
+	"golang.org/x/exp/rand"
+
 	"gonum.org/v1/gonum/floats"
 )
 
@@ -59,6 +61,102 @@ func TestCircularMean(t *testing.T) {
 	}
 }
 
+func TestCircularVariance(t *testing.T) {
+	for i, test := range []struct {
+		x   []float64
+		wts []float64
+		v   float64
+		std float64
+	}{
+		{
+			x:   []float64{1, 1 + 2*math.Pi, 1 - 4*math.Pi},
+			v:   0,
+			std: 0,
+		},
+		{
+			x:   []float64{0, 0.5 * math.Pi},
+			v:   1 - math.Sqrt2/2,
+			std: math.Sqrt(math.Ln2),
+		},
+		{
+			x:   []float64{0, 0.5 * math.Pi},
+			wts: []float64{1, 2},
+			v:   1 - math.Sqrt(5)/3,
+			std: 0.7666724625954157,
+		},
+		{
+			x: []float64{0, 0.5 * math.Pi, math.Pi, 1.5 * math.Pi},
+			v: 1,
+		},
+	} {
+		v := CircularVariance(test.x, test.wts)
+		if math.Abs(v-test.v) > 1e-14 {
+			t.Errorf("Circular variance mismatch case %d: Expected %v, Found %v", i, test.v, v)
+		}
+		if test.v == 1 {
+			continue
+		}
+		std := CircularStdDev(test.x, test.wts)
+		if math.Abs(std-test.std) > 1e-7 {
+			t.Errorf("Circular standard deviation mismatch case %d: Expected %v, Found %v", i, test.std, std)
+		}
+	}
+	if !panics(func() { CircularVariance(make([]float64, 3), make([]float64, 2)) }) {
+		t.Errorf("CircularVariance did not panic with x, wts length mismatch")
+	}
+}
+
+func TestRayleighTest(t *testing.T) {
+	for i, test := range []struct {
+		x    []float64
+		z, p float64
+	}{
+		{
+			x: []float64{0, 0, 0, 0},
+			z: 4,
+			p: 0.0076206441831750665,
+		},
+		{
+			x: []float64{0, 0.5 * math.Pi, math.Pi, 1.5 * math.Pi},
+			z: 0,
+			p: 1,
+		},
+	} {
+		z, p := RayleighTest(test.x)
+		if math.Abs(z-test.z) > 1e-14 {
+			t.Errorf("Rayleigh statistic mismatch case %d: Expected %v, Found %v", i, test.z, z)
+		}
+		if math.Abs(p-test.p) > 1e-14 {
+			t.Errorf("Rayleigh p-value mismatch case %d: Expected %v, Found %v", i, test.p, p)
+		}
+	}
+
+	// The p-value of uniform angles should be small only rarely,
+	// and that of concentrated angles should be small.
+	rnd := rand.New(rand.NewSource(1))
+	uniform := make([]float64, 100)
+	concentrated := make([]float64, 100)
+	var rejected int
+	for trial := 0; trial < 200; trial++ {
+		for i := range uniform {
+			uniform[i] = 2 * math.Pi * rnd.Float64()
+			concentrated[i] = 1 + 0.5*rnd.NormFloat64()
+		}
+		if _, p := RayleighTest(uniform); p < 0.05 {
+			rejected++
+		}
+		if _, p := RayleighTest(concentrated); p > 1e-10 {
+			t.Errorf("unexpected large p-value for concentrated angles: %v", p)
+		}
+	}
+	if rejected > 20 {
+		t.Errorf("too many rejections of uniform angles at the 5%% level: %d of 200", rejected)
+	}
+	if !panics(func() { RayleighTest(nil) }) {
+		t.Errorf("RayleighTest did not panic with empty input")
+	}
+}
+
 func ExampleCorrelation() {
 	x := []float64{8, -3, 7, 8, -4}
 	y := []float64{10, 5, 6, 3, -1}
@@ -0,0 +1,484 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package stat
+
+import (
+	"errors"
+	"math"
+	"sort"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/mathext"
+)
+
+// SurvivalCurve is a step function estimate of a survival function.
+type SurvivalCurve struct {
+	// Time holds the distinct times at which
+	// events were observed in increasing order.
+	Time []float64
+
+	// Survival holds the estimated probability
+	// of survival beyond each time.
+	Survival []float64
+
+	// AtRisk holds the total weight of the
+	// observations at risk just before each
+	// time, and Events holds the total weight
+	// of the events at each time.
+	AtRisk, Events []float64
+
+	// StdErr holds Greenwood's estimate of
+	// the standard error of each survival
+	// probability.
+	StdErr []float64
+}
+
+// KaplanMeier returns the Kaplan–Meier product-limit estimate of the survival
+// function of the observations with the given times. If events[i] is true the
+// event of interest was observed at times[i], otherwise the observation was
+// right censored at times[i]. At each time t_j at which events are observed
+// the estimate is
+//  S(t_j) = \prod_{i <= j} (1 - d_i / n_i),
+// where d_i is the weight of events at t_i and n_i is the weight of
+// observations with times at least t_i, and the variance of the estimate is
+// given by Greenwood's formula
+//  Var(S(t_j)) = S(t_j)^2 * \sum_{i <= j} d_i / (n_i * (n_i - d_i)).
+// Observations censored at an event time are considered to be at risk at
+// that time.
+//
+// If weights is nil then all of the weights are 1. If weights is not nil,
+// then len(times) must equal len(weights). KaplanMeier will panic if the
+// lengths of times and events differ.
+func KaplanMeier(times []float64, events []bool, weights []float64) SurvivalCurve {
+	if len(times) != len(events) {
+		panic("stat: slice length mismatch")
+	}
+	if weights != nil && len(times) != len(weights) {
+		panic("stat: slice length mismatch")
+	}
+	idx := argsort(times)
+	var atRisk float64
+	if weights == nil {
+		atRisk = float64(len(times))
+	} else {
+		atRisk = floats.Sum(weights)
+	}
+
+	var c SurvivalCurve
+	s := 1.0
+	var greenwood float64
+	for i := 0; i < len(idx); {
+		t := times[idx[i]]
+		var d, removed float64
+		for ; i < len(idx) && times[idx[i]] == t; i++ {
+			w := 1.0
+			if weights != nil {
+				w = weights[idx[i]]
+			}
+			if events[idx[i]] {
+				d += w
+			}
+			removed += w
+		}
+		if d > 0 {
+			s *= 1 - d/atRisk
+			greenwood += d / (atRisk * (atRisk - d))
+			se := s * math.Sqrt(greenwood)
+			if s == 0 {
+				se = 0
+			}
+			c.Time = append(c.Time, t)
+			c.Survival = append(c.Survival, s)
+			c.AtRisk = append(c.AtRisk, atRisk)
+			c.Events = append(c.Events, d)
+			c.StdErr = append(c.StdErr, se)
+		}
+		atRisk -= removed
+	}
+	return c
+}
+
+// At returns the estimated probability of survival beyond time t.
+func (c SurvivalCurve) At(t float64) float64 {
+	i := sort.Search(len(c.Time), func(i int) bool { return c.Time[i] > t })
+	if i == 0 {
+		return 1
+	}
+	return c.Survival[i-1]
+}
+
+// ConfidenceBand returns the lower and upper bounds of pointwise confidence
+// intervals with the given confidence level for the survival probabilities
+// of c. The intervals are computed on the log(-log(S)) scale,
+//  S^{exp(±z * σ / (S * |log S|))},
+// where σ is the standard error of S and z is the standard normal quantile
+// for the confidence level, which keeps the bounds within [0, 1].
+//
+// If lower or upper are nil, new slices are allocated, otherwise they must
+// have the same length as c.Time. ConfidenceBand will panic if level is not
+// in (0, 1).
+func (c SurvivalCurve) ConfidenceBand(lower, upper []float64, level float64) (lo, hi []float64) {
+	if !(0 < level && level < 1) {
+		panic("stat: confidence level out of range")
+	}
+	if lower == nil {
+		lower = make([]float64, len(c.Time))
+	}
+	if upper == nil {
+		upper = make([]float64, len(c.Time))
+	}
+	if len(lower) != len(c.Time) || len(upper) != len(c.Time) {
+		panic("stat: slice length mismatch")
+	}
+	z := mathext.NormalQuantile(0.5 + level/2)
+	for i, s := range c.Survival {
+		if s == 0 || s == 1 {
+			lower[i], upper[i] = s, s
+			continue
+		}
+		e := z * c.StdErr[i] / (s * math.Abs(math.Log(s)))
+		lower[i] = math.Pow(s, math.Exp(e))
+		upper[i] = math.Pow(s, math.Exp(-e))
+	}
+	return lower, upper
+}
+
+// LogRank performs the log-rank test of the null hypothesis that the survival
+// functions of the groups of observations are equal. If events[i] is true the
+// event of interest was observed at times[i] for an observation in group
+// groups[i], otherwise the observation was right censored at times[i]. It
+// returns the test statistic
+//  χ² = (O - E)^T * V^-1 * (O - E),
+// where O and E are the observed and expected numbers of events in each
+// group but one and V is the covariance of O - E under the null hypothesis,
+// and its p-value from the χ² distribution with one fewer degrees of freedom
+// than the number of groups.
+//
+// LogRank will panic if the lengths of times, events and groups differ or if
+// there are fewer than two groups.
+//
+// See Mantel, N. "Evaluation of survival data and two new rank order
+// statistics arising in its consideration." Cancer Chemotherapy Reports
+// 50(3):163–170 (1966).
+func LogRank(times []float64, events []bool, groups []int) (chi2, p float64) {
+	if len(times) != len(events) || len(times) != len(groups) {
+		panic("stat: slice length mismatch")
+	}
+	index := make(map[int]int)
+	var labels []int
+	for _, g := range groups {
+		if _, ok := index[g]; !ok {
+			index[g] = 0
+			labels = append(labels, g)
+		}
+	}
+	if len(labels) < 2 {
+		panic("stat: fewer than two groups")
+	}
+	sort.Ints(labels)
+	for i, g := range labels {
+		index[g] = i
+	}
+	k := len(labels)
+
+	atRisk := make([]float64, k)
+	for _, g := range groups {
+		atRisk[index[g]]++
+	}
+	// Only the first k-1 groups are used since
+	// the differences sum to zero over groups.
+	diff := make([]float64, k-1)
+	v := mat.NewSymDense(k-1, nil)
+	d := make([]float64, k)
+	removed := make([]float64, k)
+	idx := argsort(times)
+	for i := 0; i < len(idx); {
+		t := times[idx[i]]
+		for j := range d {
+			d[j], removed[j] = 0, 0
+		}
+		for ; i < len(idx) && times[idx[i]] == t; i++ {
+			g := index[groups[idx[i]]]
+			if events[idx[i]] {
+				d[g]++
+			}
+			removed[g]++
+		}
+		n := floats.Sum(atRisk)
+		dt := floats.Sum(d)
+		if dt > 0 {
+			for a := 0; a < k-1; a++ {
+				diff[a] += d[a] - dt*atRisk[a]/n
+				if n == 1 {
+					continue
+				}
+				f := dt * (n - dt) / (n * n * (n - 1))
+				for b := a; b < k-1; b++ {
+					cov := -f * atRisk[a] * atRisk[b]
+					if a == b {
+						cov += f * atRisk[a] * n
+					}
+					v.SetSym(a, b, v.At(a, b)+cov)
+				}
+			}
+		}
+		floats.Sub(atRisk, removed)
+	}
+
+	var chol mat.Cholesky
+	if !chol.Factorize(v) {
+		return math.NaN(), math.NaN()
+	}
+	var x mat.VecDense
+	u := mat.NewVecDense(k-1, diff)
+	err := chol.SolveVec(&x, u)
+	if err != nil {
+		return math.NaN(), math.NaN()
+	}
+	chi2 = mat.Dot(u, &x)
+	return chi2, mathext.GammaIncComp(float64(k-1)/2, chi2/2)
+}
+
+// Ties specifies the approximation to the partial likelihood used for tied
+// event times when fitting a Cox proportional hazards model.
+type Ties int
+
+const (
+	// Breslow uses Breslow's approximation, treating tied
+	// events as if they occurred sequentially each with the
+	// full risk set.
+	Breslow Ties = iota
+	// Efron uses Efron's approximation, removing the average
+	// risk of the tied events from the risk set for each
+	// successive tied event.
+	Efron
+)
+
+// ErrCoxNoConvergence is returned by CoxPH.Fit when the Newton iteration does
+// not converge.
+var ErrCoxNoConvergence = errors.New("stat: Cox regression did not converge")
+
+// CoxPH is a Cox proportional hazards regression model, in which the hazard
+// of an observation with covariates x is
+//  h(t | x) = h_0(t) * exp(β^T * x)
+// for an unspecified baseline hazard h_0.
+type CoxPH struct {
+	// Coef holds the estimated coefficients β.
+	Coef []float64
+
+	// Cov holds the estimated covariance of the
+	// coefficients, the inverse of the observed
+	// information matrix.
+	Cov *mat.SymDense
+
+	// LogLikelihood is the maximized partial
+	// log-likelihood, and NullLogLikelihood is
+	// the partial log-likelihood at β = 0.
+	LogLikelihood, NullLogLikelihood float64
+}
+
+// Fit fits the Cox proportional hazards model to the observations with
+// covariates in the rows of x by maximizing the partial likelihood with
+// Newton's method, starting from β = 0. If events[i] is true the event of
+// interest was observed at times[i], otherwise the observation was right
+// censored at times[i]. Tied event times are handled with the given
+// approximation.
+//
+// If the Newton iteration does not converge within 50 iterations or the
+// information matrix is singular, Fit returns an error and c is left
+// unchanged. When the partial likelihood has no finite maximum, for example
+// when a covariate perfectly separates the events, the iteration may appear
+// to converge with very large coefficients and variances. Fit will panic if
+// the number of rows of x is not equal to the lengths of times and events,
+// or if ties is not a known approximation.
+//
+// See Cox, D. R. "Regression models and life-tables." Journal of the Royal
+// Statistical Society, Series B 34(2):187–220 (1972), and Efron, B. "The
+// efficiency of Cox's likelihood function for censored data." Journal of the
+// American Statistical Association 72(359):557–565 (1977).
+func (c *CoxPH) Fit(x mat.Matrix, times []float64, events []bool, ties Ties) error {
+	n, p := x.Dims()
+	if len(times) != n || len(events) != n {
+		panic("stat: slice length mismatch")
+	}
+	if ties != Breslow && ties != Efron {
+		panic("stat: unknown ties approximation")
+	}
+
+	// Center the covariates to avoid overflow in the
+	// relative risks; this does not change β.
+	xc := mat.NewDense(n, p, nil)
+	xc.Copy(x)
+	for j := 0; j < p; j++ {
+		col := mat.Col(nil, j, xc)
+		floats.AddConst(-floats.Sum(col)/float64(n), col)
+		xc.SetCol(j, col)
+	}
+	order := argsort(times)
+
+	beta := make([]float64, p)
+	grad := make([]float64, p)
+	info := mat.NewSymDense(p, nil)
+	ll := coxPartial(grad, info, xc, times, events, order, beta, ties)
+	null := ll
+
+	const (
+		maxIter = 50
+		tol     = 1e-10
+	)
+	var chol mat.Cholesky
+	step := mat.NewVecDense(p, nil)
+	next := make([]float64, p)
+	for iter := 0; ; iter++ {
+		if iter == maxIter {
+			return ErrCoxNoConvergence
+		}
+		if !chol.Factorize(info) {
+			return errors.New("stat: singular information matrix")
+		}
+		err := chol.SolveVec(step, mat.NewVecDense(p, grad))
+		if err != nil {
+			return err
+		}
+		// Halve the Newton step until the
+		// partial likelihood does not decrease.
+		var nextLL float64
+		for h := 1.0; ; h /= 2 {
+			if h < 1e-10 {
+				return ErrCoxNoConvergence
+			}
+			for j := range next {
+				next[j] = beta[j] + h*step.AtVec(j)
+			}
+			nextLL = coxPartial(nil, nil, xc, times, events, order, next, ties)
+			if nextLL >= ll {
+				break
+			}
+		}
+		copy(beta, next)
+		done := math.Abs(nextLL-ll) <= tol*(math.Abs(ll)+tol)
+		ll = coxPartial(grad, info, xc, times, events, order, beta, ties)
+		if done {
+			break
+		}
+	}
+	if !chol.Factorize(info) {
+		return errors.New("stat: singular information matrix")
+	}
+	cov := mat.NewSymDense(p, nil)
+	err := chol.InverseTo(cov)
+	if err != nil {
+		return err
+	}
+	for _, b := range beta {
+		if math.IsInf(b, 0) || math.IsNaN(b) {
+			return ErrCoxNoConvergence
+		}
+	}
+
+	c.Coef = beta
+	c.Cov = cov
+	c.LogLikelihood = ll
+	c.NullLogLikelihood = null
+	return nil
+}
+
+// coxPartial returns the partial log-likelihood of the Cox model with
+// coefficients beta for the covariates in the rows of x and observations
+// visited in increasing order of time by order. If grad and info are not nil,
+// the gradient and the observed information, the negative Hessian, of the
+// partial log-likelihood are stored into them.
+func coxPartial(grad []float64, info *mat.SymDense, x *mat.Dense, times []float64, events []bool, order []int, beta []float64, ties Ties) float64 {
+	_, p := x.Dims()
+	var (
+		// Risk set sums of r_i, r_i*x_i and r_i*x_i*x_i^T.
+		s0 float64
+		s1 = make([]float64, p)
+		s2 = make([]float64, p*p)
+
+		// Sums of the same over tied events.
+		d0 float64
+		d1 = make([]float64, p)
+		d2 = make([]float64, p*p)
+
+		// Risk set sums at each tied event.
+		e1 = make([]float64, p)
+		e2 = make([]float64, p*p)
+	)
+	if grad != nil {
+		for j := range grad {
+			grad[j] = 0
+		}
+		for i := 0; i < p; i++ {
+			for j := i; j < p; j++ {
+				info.SetSym(i, j, 0)
+			}
+		}
+	}
+	var ll float64
+	// Visit observations in decreasing order of
+	// time so the risk sets grow incrementally.
+	for k := len(order) - 1; k >= 0; {
+		t := times[order[k]]
+		d0 = 0
+		for j := range d1 {
+			d1[j] = 0
+		}
+		for j := range d2 {
+			d2[j] = 0
+		}
+		var nd int
+		for ; k >= 0 && times[order[k]] == t; k-- {
+			i := order[k]
+			row := x.RawRowView(i)
+			eta := floats.Dot(row, beta)
+			r := math.Exp(eta)
+			s0 += r
+			floats.AddScaled(s1, r, row)
+			for a, xa := range row {
+				floats.AddScaled(s2[a*p:(a+1)*p], r*xa, row)
+			}
+			if !events[i] {
+				continue
+			}
+			nd++
+			ll += eta
+			if grad != nil {
+				floats.Add(grad, row)
+			}
+			d0 += r
+			floats.AddScaled(d1, r, row)
+			for a, xa := range row {
+				floats.AddScaled(d2[a*p:(a+1)*p], r*xa, row)
+			}
+		}
+		for l := 0; l < nd; l++ {
+			var f float64
+			if ties == Efron {
+				f = float64(l) / float64(nd)
+			}
+			e0 := s0 - f*d0
+			ll -= math.Log(e0)
+			if grad == nil {
+				continue
+			}
+			for j := range e1 {
+				e1[j] = s1[j] - f*d1[j]
+				grad[j] -= e1[j] / e0
+			}
+			for j := range e2 {
+				e2[j] = s2[j] - f*d2[j]
+			}
+			for a := 0; a < p; a++ {
+				for b := a; b < p; b++ {
+					v := e2[a*p+b]/e0 - e1[a]*e1[b]/(e0*e0)
+					info.SetSym(a, b, info.At(a, b)+v)
+				}
+			}
+		}
+	}
+	return ll
+}
@@ -0,0 +1,261 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package stat
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+)
+
+// gehan holds the remission times in weeks of the leukemia patients in
+// the trial of 6-mercaptopurine (group 0) against placebo (group 1) from
+// Gehan, E. A. "A generalized Wilcoxon test for comparing arbitrarily
+// singly-censored samples." Biometrika 52(1/2):203–223 (1965).
+var gehan = struct {
+	times  []float64
+	events []bool
+	groups []int
+}{
+	times: []float64{
+		6, 6, 6, 6, 7, 9, 10, 10, 11, 13, 16, 17, 19, 20, 22, 23, 25, 32, 32, 34, 35,
+		1, 1, 2, 2, 3, 4, 4, 5, 5, 8, 8, 8, 8, 11, 11, 12, 12, 15, 17, 22, 23,
+	},
+	events: []bool{
+		true, true, true, false, true, false, true, false, false, true, true, false, false, false, true, true, false, false, false, false, false,
+		true, true, true, true, true, true, true, true, true, true, true, true, true, true, true, true, true, true, true, true, true,
+	},
+	groups: []int{
+		0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+		1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1,
+	},
+}
+
+func TestKaplanMeier(t *testing.T) {
+	// The values agree with those of survfit in the R survival package.
+	const n = 21
+	c := KaplanMeier(gehan.times[:n], gehan.events[:n], nil)
+	want := SurvivalCurve{
+		Time:     []float64{6, 7, 10, 13, 16, 22, 23},
+		Survival: []float64{0.8571428571428572, 0.8067226890756303, 0.7529411764705882, 0.6901960784313725, 0.6274509803921569, 0.5378151260504203, 0.44817927170868355},
+		AtRisk:   []float64{21, 17, 15, 12, 11, 7, 6},
+		Events:   []float64{3, 1, 1, 1, 1, 1, 1},
+		StdErr:   []float64{0.07636035483212125, 0.0869352851800572, 0.09634965299432051, 0.10681470777500983, 0.11405386525675254, 0.12823375169303403, 0.13459145675576048},
+	}
+	const tol = 1e-12
+	if !floats.Equal(c.Time, want.Time) || !floats.Equal(c.AtRisk, want.AtRisk) || !floats.Equal(c.Events, want.Events) {
+		t.Errorf("unexpected curve steps: got:%+v want:%+v", c, want)
+	}
+	if !floats.EqualApprox(c.Survival, want.Survival, tol) {
+		t.Errorf("unexpected survival: got:%v want:%v", c.Survival, want.Survival)
+	}
+	if !floats.EqualApprox(c.StdErr, want.StdErr, tol) {
+		t.Errorf("unexpected standard errors: got:%v want:%v", c.StdErr, want.StdErr)
+	}
+
+	for _, test := range []struct {
+		t, want float64
+	}{
+		{t: 0, want: 1},
+		{t: 5.9, want: 1},
+		{t: 6, want: want.Survival[0]},
+		{t: 9, want: want.Survival[1]},
+		{t: 100, want: want.Survival[6]},
+	} {
+		if got := c.At(test.t); got != test.want {
+			t.Errorf("unexpected survival at %v: got:%v want:%v", test.t, got, test.want)
+		}
+	}
+
+	lower, upper := c.ConfidenceBand(nil, nil, 0.95)
+	for _, test := range []struct {
+		i            int
+		lower, upper float64
+	}{
+		{i: 0, lower: 0.6197179552659839, upper: 0.951551747646997},
+		{i: 6, lower: 0.18805200595923247, upper: 0.6801426284952415},
+	} {
+		if !floats.EqualWithinAbsOrRel(lower[test.i], test.lower, 1e-8, 1e-8) || !floats.EqualWithinAbsOrRel(upper[test.i], test.upper, 1e-8, 1e-8) {
+			t.Errorf("unexpected confidence interval at %v: got:[%v, %v] want:[%v, %v]",
+				c.Time[test.i], lower[test.i], upper[test.i], test.lower, test.upper)
+		}
+	}
+	for i, s := range c.Survival {
+		if !(lower[i] < s && s < upper[i]) {
+			t.Errorf("confidence interval at %v does not contain estimate: [%v, %v] %v", c.Time[i], lower[i], upper[i], s)
+		}
+	}
+
+	// Integer weights are equivalent to repeated observations.
+	rnd := rand.New(rand.NewSource(1))
+	var times, wtimes, weights []float64
+	var events, wevents []bool
+	for i := 0; i < 50; i++ {
+		tm := float64(rnd.Intn(20))
+		ev := rnd.Float64() < 0.7
+		w := 1 + rnd.Intn(3)
+		wtimes = append(wtimes, tm)
+		wevents = append(wevents, ev)
+		weights = append(weights, float64(w))
+		for j := 0; j < w; j++ {
+			times = append(times, tm)
+			events = append(events, ev)
+		}
+	}
+	got := KaplanMeier(wtimes, wevents, weights)
+	rep := KaplanMeier(times, events, nil)
+	if !floats.Equal(got.Time, rep.Time) || !floats.EqualApprox(got.Survival, rep.Survival, tol) || !floats.EqualApprox(got.StdErr, rep.StdErr, tol) {
+		t.Errorf("weighted estimate does not match repeated observations:\ngot: %+v\nwant:%+v", got, rep)
+	}
+
+	// All events observed at the last time.
+	c = KaplanMeier([]float64{1, 2, 2}, []bool{true, true, true}, nil)
+	if c.Survival[1] != 0 || c.StdErr[1] != 0 {
+		t.Errorf("unexpected final step: survival:%v stderr:%v", c.Survival[1], c.StdErr[1])
+	}
+
+	if !panics(func() { KaplanMeier([]float64{1, 2}, []bool{true}, nil) }) {
+		t.Error("expected panic for length mismatch")
+	}
+	if !panics(func() { c.ConfidenceBand(nil, nil, 1) }) {
+		t.Error("expected panic for bad confidence level")
+	}
+}
+
+func TestLogRank(t *testing.T) {
+	// The value agrees with that of survdiff in the R survival package.
+	chi2, p := LogRank(gehan.times, gehan.events, gehan.groups)
+	if want := 16.792940989216532; math.Abs(chi2-want) > 1e-10 {
+		t.Errorf("unexpected statistic: got:%v want:%v", chi2, want)
+	}
+	if want := 4.16880910933455e-05; math.Abs(p-want) > 1e-12 {
+		t.Errorf("unexpected p-value: got:%v want:%v", p, want)
+	}
+
+	// The statistic does not depend on the group labels.
+	groups := make([]int, len(gehan.groups))
+	for i, g := range gehan.groups {
+		groups[i] = 5 - 3*g
+	}
+	if got, _ := LogRank(gehan.times, gehan.events, groups); math.Abs(got-chi2) > 1e-10 {
+		t.Errorf("unexpected statistic for relabeled groups: got:%v want:%v", got, chi2)
+	}
+
+	// Samples from identical distributions should
+	// not usually be found to differ.
+	rnd := rand.New(rand.NewSource(1))
+	var rejected int
+	const trials = 200
+	for trial := 0; trial < trials; trial++ {
+		var times []float64
+		var events []bool
+		var groups []int
+		for i := 0; i < 60; i++ {
+			times = append(times, rnd.ExpFloat64())
+			events = append(events, rnd.Float64() < 0.8)
+			groups = append(groups, i%3)
+		}
+		_, p := LogRank(times, events, groups)
+		if p < 0.05 {
+			rejected++
+		}
+	}
+	if rejected > trials/10 {
+		t.Errorf("unexpected number of rejections of true null hypothesis: got:%d of %d", rejected, trials)
+	}
+
+	if !panics(func() { LogRank([]float64{1, 2}, []bool{true, true}, []int{0, 0}) }) {
+		t.Error("expected panic for single group")
+	}
+}
+
+func TestCoxPH(t *testing.T) {
+	// The values agree with those of coxph in the R survival package.
+	x := mat.NewDense(len(gehan.groups), 1, nil)
+	for i, g := range gehan.groups {
+		x.Set(i, 0, float64(g))
+	}
+	for _, test := range []struct {
+		ties       Ties
+		coef, se   float64
+		ll, nullLL float64
+		name       string
+	}{
+		{ties: Breslow, coef: 1.509191412585879, se: 0.4095644063667414, ll: -86.37962207114558, nullLL: -93.98505047824516, name: "Breslow"},
+		{ties: Efron, coef: 1.5721251488290666, se: 0.4123967177094555, ll: -85.00842457737163, nullLL: -93.18426999684776, name: "Efron"},
+	} {
+		var c CoxPH
+		err := c.Fit(x, gehan.times, gehan.events, test.ties)
+		if err != nil {
+			t.Fatalf("unexpected error for %s: %v", test.name, err)
+		}
+		const tol = 1e-8
+		if math.Abs(c.Coef[0]-test.coef) > tol {
+			t.Errorf("unexpected coefficient for %s: got:%v want:%v", test.name, c.Coef[0], test.coef)
+		}
+		if se := math.Sqrt(c.Cov.At(0, 0)); math.Abs(se-test.se) > tol {
+			t.Errorf("unexpected standard error for %s: got:%v want:%v", test.name, se, test.se)
+		}
+		if math.Abs(c.LogLikelihood-test.ll) > tol {
+			t.Errorf("unexpected log-likelihood for %s: got:%v want:%v", test.name, c.LogLikelihood, test.ll)
+		}
+		if math.Abs(c.NullLogLikelihood-test.nullLL) > tol {
+			t.Errorf("unexpected null log-likelihood for %s: got:%v want:%v", test.name, c.NullLogLikelihood, test.nullLL)
+		}
+	}
+
+	// Without ties the approximations agree, and the fitted
+	// coefficients are a stationary point of the likelihood.
+	rnd := rand.New(rand.NewSource(1))
+	const n, p = 100, 3
+	beta := []float64{0.5, -1, 0}
+	xr := mat.NewDense(n, p, nil)
+	times := make([]float64, n)
+	events := make([]bool, n)
+	for i := 0; i < n; i++ {
+		for j := 0; j < p; j++ {
+			xr.Set(i, j, rnd.NormFloat64())
+		}
+		times[i] = rnd.ExpFloat64() / math.Exp(floats.Dot(xr.RawRowView(i), beta))
+		events[i] = rnd.Float64() < 0.8
+	}
+	var breslow, efron CoxPH
+	if err := breslow.Fit(xr, times, events, Breslow); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := efron.Fit(xr, times, events, Efron); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !floats.EqualApprox(breslow.Coef, efron.Coef, 1e-10) {
+		t.Errorf("approximations differ without ties: Breslow:%v Efron:%v", breslow.Coef, efron.Coef)
+	}
+	grad := make([]float64, p)
+	coxPartial(grad, mat.NewSymDense(p, nil), xr, times, events, argsort(times), efron.Coef, Efron)
+	if floats.Norm(grad, 2) > 1e-6 {
+		t.Errorf("unexpected gradient at fitted coefficients: %v", grad)
+	}
+
+	// Collinear covariates give a singular information matrix.
+	xc := mat.NewDense(n, 2, nil)
+	for i := 0; i < n; i++ {
+		xc.Set(i, 0, xr.At(i, 0))
+		xc.Set(i, 1, 2*xr.At(i, 0))
+	}
+	var c CoxPH
+	if err := c.Fit(xc, times, events, Efron); err == nil {
+		t.Error("expected error for collinear covariates")
+	}
+	if c.Coef != nil {
+		t.Error("unexpected modification of model after failed fit")
+	}
+
+	if !panics(func() { c.Fit(xr, times[:10], events, Efron) }) {
+		t.Error("expected panic for length mismatch")
+	}
+}
@@ -0,0 +1,385 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package tensor
+
+import "gonum.org/v1/gonum/mat"
+
+// Error represents tensor handling errors.
+type Error struct{ string }
+
+func (err Error) Error() string { return err.string }
+
+var (
+	ErrShape      = Error{"tensor: dimension mismatch"}
+	ErrIndex      = Error{"tensor: index out of range"}
+	ErrZeroLength = Error{"tensor: zero length in tensor definition"}
+	ErrPermute    = Error{"tensor: invalid permutation"}
+)
+
+// Dense is a dense N-dimensional tensor of float64 values.
+type Dense struct {
+	shape  []int
+	stride []int
+	offset int
+	data   []float64
+}
+
+// New creates a new Dense tensor with the given shape. If data == nil,
+// a new slice is allocated for the backing slice. If len(data) is equal
+// to the product of the elements of shape, data is used as the backing
+// slice, with elements stored in row-major order, and changes to the
+// elements of the returned Dense will be reflected in data. If neither
+// of these is true, New will panic. New will also panic if shape is
+// empty or any element of shape is not positive.
+func New(shape []int, data []float64) *Dense {
+	if len(shape) == 0 {
+		panic(ErrZeroLength)
+	}
+	n := 1
+	for _, d := range shape {
+		if d <= 0 {
+			panic(ErrZeroLength)
+		}
+		n *= d
+	}
+	if data == nil {
+		data = make([]float64, n)
+	}
+	if len(data) != n {
+		panic(ErrShape)
+	}
+	shape = append([]int(nil), shape...)
+	return &Dense{
+		shape:  shape,
+		stride: rowMajor(shape),
+		data:   data,
+	}
+}
+
+// rowMajor returns the row-major strides for the given shape.
+func rowMajor(shape []int) []int {
+	stride := make([]int, len(shape))
+	s := 1
+	for i := len(shape) - 1; i >= 0; i-- {
+		stride[i] = s
+		s *= shape[i]
+	}
+	return stride
+}
+
+// Rank returns the number of dimensions of the tensor.
+func (t *Dense) Rank() int { return len(t.shape) }
+
+// Shape returns the shape of the tensor.
+func (t *Dense) Shape() []int { return append([]int(nil), t.shape...) }
+
+// Len returns the number of elements in the tensor.
+func (t *Dense) Len() int {
+	n := 1
+	for _, d := range t.shape {
+		n *= d
+	}
+	return n
+}
+
+// index returns the offset into the backing data of the element at idx.
+func (t *Dense) index(idx []int) int {
+	if len(idx) != len(t.shape) {
+		panic(ErrShape)
+	}
+	off := t.offset
+	for i, v := range idx {
+		if v < 0 || v >= t.shape[i] {
+			panic(ErrIndex)
+		}
+		off += v * t.stride[i]
+	}
+	return off
+}
+
+// At returns the element at the given index.
+// At will panic if idx does not address an element of t.
+func (t *Dense) At(idx ...int) float64 {
+	return t.data[t.index(idx)]
+}
+
+// Set sets the element at the given index to v.
+// Set will panic if idx does not address an element of t.
+func (t *Dense) Set(v float64, idx ...int) {
+	t.data[t.index(idx)] = v
+}
+
+// Slice returns a view of t restricted to the elements with indices
+// in [i, j) along dimension dim. The returned tensor shares the backing
+// data of t. Slice will panic if dim is not a dimension of t or the
+// interval is empty or out of range.
+func (t *Dense) Slice(dim, i, j int) *Dense {
+	if dim < 0 || dim >= len(t.shape) {
+		panic(ErrIndex)
+	}
+	if i < 0 || j > t.shape[dim] || i >= j {
+		panic(ErrIndex)
+	}
+	v := t.view()
+	v.shape[dim] = j - i
+	v.offset += i * t.stride[dim]
+	return v
+}
+
+// Index returns a view of t with dimension dim fixed at index i, so the
+// returned tensor has rank one less than t. The returned tensor shares
+// the backing data of t. Index will panic if t has rank one, dim is not
+// a dimension of t or i is out of range.
+func (t *Dense) Index(dim, i int) *Dense {
+	if len(t.shape) == 1 {
+		panic(ErrShape)
+	}
+	if dim < 0 || dim >= len(t.shape) || i < 0 || i >= t.shape[dim] {
+		panic(ErrIndex)
+	}
+	v := t.view()
+	v.offset += i * t.stride[dim]
+	v.shape = append(v.shape[:dim], v.shape[dim+1:]...)
+	v.stride = append(v.stride[:dim], v.stride[dim+1:]...)
+	return v
+}
+
+// Permute returns a view of t with its dimensions reordered so that
+// dimension k of the returned tensor is dimension perm[k] of t. The
+// returned tensor shares the backing data of t. Permute will panic
+// if perm is not a permutation of the dimensions of t.
+func (t *Dense) Permute(perm ...int) *Dense {
+	if len(perm) != len(t.shape) {
+		panic(ErrPermute)
+	}
+	seen := make([]bool, len(perm))
+	v := t.view()
+	for k, p := range perm {
+		if p < 0 || p >= len(perm) || seen[p] {
+			panic(ErrPermute)
+		}
+		seen[p] = true
+		v.shape[k] = t.shape[p]
+		v.stride[k] = t.stride[p]
+	}
+	return v
+}
+
+// Reshape returns a tensor with the given shape holding the elements
+// of t in row-major order. If t is contiguous, the returned tensor
+// shares its backing data, otherwise the elements are copied. Reshape
+// will panic if the number of elements in shape differs from t.
+func (t *Dense) Reshape(shape ...int) *Dense {
+	if t.contiguous() {
+		return New(shape, t.data[t.offset:t.offset+t.Len()])
+	}
+	return New(shape, t.Clone().data)
+}
+
+// Clone returns a copy of t with contiguous row-major storage.
+func (t *Dense) Clone() *Dense {
+	c := New(t.shape, nil)
+	i := 0
+	t.each(func(off int) {
+		c.data[i] = t.data[off]
+		i++
+	})
+	return c
+}
+
+// Data returns the elements of t in row-major order. If t is
+// contiguous, the returned slice is the backing data of t,
+// otherwise it is a copy.
+func (t *Dense) Data() []float64 {
+	if t.contiguous() {
+		return t.data[t.offset : t.offset+t.Len()]
+	}
+	return t.Clone().data
+}
+
+// view returns a shallow copy of t with its own shape and stride.
+func (t *Dense) view() *Dense {
+	return &Dense{
+		shape:  append([]int(nil), t.shape...),
+		stride: append([]int(nil), t.stride...),
+		offset: t.offset,
+		data:   t.data,
+	}
+}
+
+// contiguous returns whether the elements of t are
+// stored contiguously in row-major order.
+func (t *Dense) contiguous() bool {
+	s := 1
+	for i := len(t.shape) - 1; i >= 0; i-- {
+		if t.shape[i] != 1 && t.stride[i] != s {
+			return false
+		}
+		s *= t.shape[i]
+	}
+	return true
+}
+
+// each calls fn with the offset of each element of t
+// in row-major order.
+func (t *Dense) each(fn func(off int)) {
+	idx := make([]int, len(t.shape))
+	off := t.offset
+	for {
+		fn(off)
+		d := len(idx) - 1
+		for ; d >= 0; d-- {
+			idx[d]++
+			off += t.stride[d]
+			if idx[d] < t.shape[d] {
+				break
+			}
+			off -= idx[d] * t.stride[d]
+			idx[d] = 0
+		}
+		if d < 0 {
+			return
+		}
+	}
+}
+
+// Add returns the element-wise sum of a and b.
+// Add will panic if the shapes of a and b differ.
+func Add(a, b *Dense) *Dense {
+	checkSameShape(a, b)
+	c := a.Clone()
+	i := 0
+	b.each(func(off int) {
+		c.data[i] += b.data[off]
+		i++
+	})
+	return c
+}
+
+// Scale returns the tensor t with each element multiplied by f.
+func Scale(f float64, t *Dense) *Dense {
+	c := t.Clone()
+	for i := range c.data {
+		c.data[i] *= f
+	}
+	return c
+}
+
+// Equal returns whether a and b have the same shape and elements.
+func Equal(a, b *Dense) bool {
+	if !sameShape(a, b) {
+		return false
+	}
+	ad := a.Data()
+	bd := b.Data()
+	for i, v := range ad {
+		if v != bd[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func sameShape(a, b *Dense) bool {
+	if len(a.shape) != len(b.shape) {
+		return false
+	}
+	for i, d := range a.shape {
+		if d != b.shape[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func checkSameShape(a, b *Dense) {
+	if !sameShape(a, b) {
+		panic(ErrShape)
+	}
+}
+
+// MulMat returns the product of t and the matrix m along dimension dim.
+// For an m with r rows and c columns, where r is the length of dimension
+// dim of t, the returned tensor has dimension dim of length c and
+//  result[..., j, ...] = Σ_k t[..., k, ...] * m[k, j]
+// with all other indices equal. MulMat will panic if dim is not a
+// dimension of t or the number of rows of m does not match.
+func MulMat(t *Dense, dim int, m mat.Matrix) *Dense {
+	if dim < 0 || dim >= len(t.shape) {
+		panic(ErrIndex)
+	}
+	r, c := m.Dims()
+	if r != t.shape[dim] {
+		panic(ErrShape)
+	}
+	// Move dim to the last position, multiply the
+	// unfolding by m and fold the result back.
+	perm := make([]int, 0, len(t.shape))
+	for i := range t.shape {
+		if i != dim {
+			perm = append(perm, i)
+		}
+	}
+	perm = append(perm, dim)
+	p := t.Permute(perm...).Clone()
+	rows := p.Len() / r
+	var prod mat.Dense
+	prod.Mul(mat.NewDense(rows, r, p.data), m)
+
+	shape := p.Shape()
+	shape[len(shape)-1] = c
+	res := New(shape, prod.RawMatrix().Data)
+	inv := make([]int, len(perm))
+	for i := range inv {
+		switch {
+		case i < dim:
+			inv[i] = i
+		case i == dim:
+			inv[i] = len(perm) - 1
+		default:
+			inv[i] = i - 1
+		}
+	}
+	return res.Permute(inv...).Clone()
+}
+
+// Unfold returns the mode-dim unfolding of t, the matrix whose rows
+// are indexed by dimension dim of t and whose columns are indexed by
+// the remaining dimensions in row-major order. Unfold will panic if
+// dim is not a dimension of t.
+func Unfold(t *Dense, dim int) *mat.Dense {
+	if dim < 0 || dim >= len(t.shape) {
+		panic(ErrIndex)
+	}
+	perm := []int{dim}
+	for i := range t.shape {
+		if i != dim {
+			perm = append(perm, i)
+		}
+	}
+	p := t.Permute(perm...).Clone()
+	return mat.NewDense(t.shape[dim], p.Len()/t.shape[dim], p.data)
+}
+
+// FromMatrix returns a rank 2 tensor holding a copy of the elements of m.
+func FromMatrix(m mat.Matrix) *Dense {
+	r, c := m.Dims()
+	t := New([]int{r, c}, nil)
+	for i := 0; i < r; i++ {
+		for j := 0; j < c; j++ {
+			t.data[i*c+j] = m.At(i, j)
+		}
+	}
+	return t
+}
+
+// Matrix returns a mat.Dense holding a copy of the elements of the
+// rank 2 tensor t. Matrix will panic if t does not have rank 2.
+func (t *Dense) Matrix() *mat.Dense {
+	if len(t.shape) != 2 {
+		panic(ErrShape)
+	}
+	return mat.NewDense(t.shape[0], t.shape[1], t.Clone().data)
+}
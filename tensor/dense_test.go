@@ -0,0 +1,172 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package tensor
+
+import (
+	"reflect"
+	"testing"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// seq returns a tensor with the given shape holding 0, 1, 2, ...
+// in row-major order.
+func seq(shape ...int) *Dense {
+	t := New(shape, nil)
+	for i := range t.data {
+		t.data[i] = float64(i)
+	}
+	return t
+}
+
+func TestDenseAtSet(t *testing.T) {
+	a := seq(2, 3, 4)
+	if got := a.At(1, 2, 3); got != 23 {
+		t.Errorf("unexpected element: got:%v want:23", got)
+	}
+	a.Set(-1, 0, 1, 2)
+	if got := a.data[6]; got != -1 {
+		t.Errorf("unexpected backing element after Set: got:%v want:-1", got)
+	}
+	if a.Rank() != 3 || a.Len() != 24 || !reflect.DeepEqual(a.Shape(), []int{2, 3, 4}) {
+		t.Errorf("unexpected dimensions: rank:%d len:%d shape:%v", a.Rank(), a.Len(), a.Shape())
+	}
+	for _, idx := range [][]int{{2, 0, 0}, {0, -1, 0}, {0, 0}, {0, 0, 0, 0}} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("expected panic for index %v", idx)
+				}
+			}()
+			a.At(idx...)
+		}()
+	}
+}
+
+func TestDenseViews(t *testing.T) {
+	a := seq(2, 3, 4)
+
+	s := a.Slice(2, 1, 3)
+	if !reflect.DeepEqual(s.Shape(), []int{2, 3, 2}) {
+		t.Errorf("unexpected slice shape: got:%v", s.Shape())
+	}
+	if got := s.At(1, 2, 0); got != a.At(1, 2, 1) {
+		t.Errorf("unexpected slice element: got:%v want:%v", got, a.At(1, 2, 1))
+	}
+	s.Set(100, 0, 0, 0)
+	if a.At(0, 0, 1) != 100 {
+		t.Error("slice does not share backing data")
+	}
+	want := []float64{100, 2, 5, 6, 9, 10, 13, 14, 17, 18, 21, 22}
+	if got := s.Data(); !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected slice data:\ngot: %v\nwant:%v", got, want)
+	}
+
+	x := a.Index(1, 2)
+	if !reflect.DeepEqual(x.Shape(), []int{2, 4}) {
+		t.Errorf("unexpected index shape: got:%v", x.Shape())
+	}
+	if got := x.At(1, 3); got != a.At(1, 2, 3) {
+		t.Errorf("unexpected index element: got:%v want:%v", got, a.At(1, 2, 3))
+	}
+
+	p := a.Permute(2, 0, 1)
+	if !reflect.DeepEqual(p.Shape(), []int{4, 2, 3}) {
+		t.Errorf("unexpected permuted shape: got:%v", p.Shape())
+	}
+	for i := 0; i < 2; i++ {
+		for j := 0; j < 3; j++ {
+			for k := 0; k < 4; k++ {
+				if p.At(k, i, j) != a.At(i, j, k) {
+					t.Fatalf("unexpected permuted element at %d,%d,%d", k, i, j)
+				}
+			}
+		}
+	}
+
+	r := a.Reshape(6, 4)
+	r.Set(-5, 5, 3)
+	if a.At(1, 2, 3) != -5 {
+		t.Error("reshape of contiguous tensor does not share backing data")
+	}
+	r = p.Reshape(8, 3)
+	if got := r.At(1, 0); got != a.At(1, 0, 0) {
+		t.Errorf("unexpected reshaped element: got:%v want:%v", got, a.At(1, 0, 0))
+	}
+}
+
+func TestAddScale(t *testing.T) {
+	a := seq(2, 2)
+	b := seq(2, 2).Permute(1, 0)
+	got := Add(a, b)
+	want := New([]int{2, 2}, []float64{0, 3, 3, 6})
+	if !Equal(got, want) {
+		t.Errorf("unexpected sum: got:%v want:%v", got.Data(), want.Data())
+	}
+	got = Scale(2, b)
+	want = New([]int{2, 2}, []float64{0, 4, 2, 6})
+	if !Equal(got, want) {
+		t.Errorf("unexpected scaled tensor: got:%v want:%v", got.Data(), want.Data())
+	}
+	if Equal(a, seq(4)) {
+		t.Error("tensors with different shapes reported equal")
+	}
+}
+
+func TestMulMat(t *testing.T) {
+	a := seq(2, 3, 4)
+	m := mat.NewDense(3, 2, []float64{
+		1, 0,
+		2, 1,
+		0, -1,
+	})
+	got := MulMat(a, 1, m)
+	if !reflect.DeepEqual(got.Shape(), []int{2, 2, 4}) {
+		t.Fatalf("unexpected product shape: got:%v", got.Shape())
+	}
+	for i := 0; i < 2; i++ {
+		for j := 0; j < 2; j++ {
+			for k := 0; k < 4; k++ {
+				var want float64
+				for l := 0; l < 3; l++ {
+					want += a.At(i, l, k) * m.At(l, j)
+				}
+				if got.At(i, j, k) != want {
+					t.Errorf("unexpected product element at %d,%d,%d: got:%v want:%v", i, j, k, got.At(i, j, k), want)
+				}
+			}
+		}
+	}
+
+	// For a matrix, multiplication along the last
+	// dimension is matrix multiplication.
+	b := mat.NewDense(2, 3, []float64{1, 2, 3, 4, 5, 6})
+	var want mat.Dense
+	want.Mul(b, m)
+	if !mat.Equal(MulMat(FromMatrix(b), 1, m).Matrix(), &want) {
+		t.Error("unexpected matrix product")
+	}
+}
+
+func TestUnfold(t *testing.T) {
+	a := seq(2, 3, 2)
+	got := Unfold(a, 1)
+	want := mat.NewDense(3, 4, []float64{
+		0, 1, 6, 7,
+		2, 3, 8, 9,
+		4, 5, 10, 11,
+	})
+	if !mat.Equal(got, want) {
+		t.Errorf("unexpected unfolding:\ngot: %v\nwant:%v", mat.Formatted(got), mat.Formatted(want))
+	}
+
+	m := mat.NewDense(2, 3, []float64{1, 2, 3, 4, 5, 6})
+	if !mat.Equal(FromMatrix(m).Matrix(), m) {
+		t.Error("round trip through tensor does not preserve matrix")
+	}
+	if !mat.Equal(FromMatrix(m).Permute(1, 0).Matrix(), m.T()) {
+		t.Error("permuted tensor does not give transpose")
+	}
+}
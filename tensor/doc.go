@@ -0,0 +1,11 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package tensor provides a dense N-dimensional array type.
+//
+// A Dense tensor holds its elements in a float64 slice addressed through
+// a shape and a stride for each dimension. Slicing, indexing and permuting
+// the dimensions of a tensor return views that share the backing data
+// with the receiver, in the same way as the views of the mat package.
+package tensor // import "gonum.org/v1/gonum/tensor"